@@ -56,10 +56,16 @@ func addDNS(record string, service *kapi.Service, etcdClient *etcd.Client) error
 		log.Printf("Skipping dns record for headless service: %s\n", service.Name)
 		return nil
 	}
+	if len(service.Spec.Ports) == 0 {
+		log.Printf("Skipping dns record for service without ports: %s\n", service.Name)
+		return nil
+	}
 
+	// SkyDNS records hold a single port, so only the first port of the service is published.
+	port := service.Spec.Ports[0].Port
 	svc := skymsg.Service{
 		Host:     service.Spec.PortalIP,
-		Port:     service.Spec.Port,
+		Port:     port,
 		Priority: 10,
 		Weight:   10,
 		Ttl:      30,
@@ -70,7 +76,7 @@ func addDNS(record string, service *kapi.Service, etcdClient *etcd.Client) error
 	}
 	// Set with no TTL, and hope that kubernetes events are accurate.
 
-	log.Printf("Setting dns record: %v -> %s:%d\n", record, service.Spec.PortalIP, service.Spec.Port)
+	log.Printf("Setting dns record: %v -> %s:%d\n", record, service.Spec.PortalIP, port)
 	_, err = etcdClient.Set(skymsg.Path(record), string(b), uint64(0))
 	return err
 }
@@ -40,6 +40,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/record"
 	nodeControllerPkg "github.com/GoogleCloudPlatform/kubernetes/pkg/cloudprovider/controller"
 	replicationControllerPkg "github.com/GoogleCloudPlatform/kubernetes/pkg/controller"
@@ -164,7 +165,7 @@ func startComponents(firstManifestURL, secondManifestURL, apiVersion string) (st
 
 	cl := client.NewOrDie(&client.Config{Host: apiServer.URL, Version: apiVersion})
 
-	helper, err := master.NewEtcdHelper(etcdClient, "")
+	helper, err := master.NewEtcdHelper(etcdClient, "", "")
 	if err != nil {
 		glog.Fatalf("Unable to get etcd helper: %v", err)
 	}
@@ -209,14 +210,15 @@ func startComponents(firstManifestURL, secondManifestURL, apiVersion string) (st
 	}
 	scheduler.New(schedulerConfig).Run()
 
-	endpoints := service.NewEndpointController(cl)
-	// ensure the service endpoints are sync'd several times within the window that the integration tests wait
-	go util.Forever(func() { endpoints.SyncServiceEndpoints() }, time.Second*4)
+	podInformer := cache.NewPodInformer(cl, 0)
 
-	controllerManager := replicationControllerPkg.NewReplicationManager(cl)
+	endpoints := service.NewEndpointController(cl, podInformer, 4*time.Second)
+	endpoints.Run(5, util.NeverStop)
+
+	controllerManager := replicationControllerPkg.NewReplicationManager(cl, podInformer, 1*time.Second)
 
 	// TODO: Write an integration test for the replication controllers watch.
-	controllerManager.Run(1 * time.Second)
+	controllerManager.Run(5, util.NeverStop)
 
 	nodeResources := &api.NodeResources{
 		Capacity: api.ResourceList{
@@ -225,7 +227,8 @@ func startComponents(firstManifestURL, secondManifestURL, apiVersion string) (st
 		}}
 
 	nodeController := nodeControllerPkg.NewNodeController(nil, "", machineList, nodeResources, cl, fakeKubeletClient{},
-		record.FromSource(api.EventSource{Component: "controllermanager"}), 10, 5*time.Minute)
+		record.FromSource(api.EventSource{Component: "controllermanager"}), 10, 5*time.Minute,
+		util.NewFakeRateLimiter(), 0.55)
 	nodeController.Run(5*time.Second, true, false)
 	cadvisorInterface := new(cadvisor.Fake)
 
@@ -286,11 +289,25 @@ func endpointsSet(c *client.Client, serviceNamespace, serviceID string, endpoint
 			glog.Infof("Error on creating endpoints: %v", err)
 			return false, nil
 		}
-		for _, e := range endpoints.Endpoints {
-			glog.Infof("%s/%s endpoint: %s:%d %#v", serviceNamespace, serviceID, e.IP, e.Port, e.TargetRef)
+		count := 0
+		for _, ss := range endpoints.Subsets {
+			for _, addr := range ss.Addresses {
+				for _, port := range ss.Ports {
+					count++
+					glog.Infof("%s/%s endpoint: %s:%d %#v", serviceNamespace, serviceID, addr.IP, port.Port, addr.TargetRef)
+				}
+			}
 		}
-		return len(endpoints.Endpoints) == endpointCount, nil
+		return count == endpointCount, nil
+	}
+}
+
+func countEndpoints(eps *api.Endpoints) int {
+	count := 0
+	for i := range eps.Subsets {
+		count += len(eps.Subsets[i].Addresses) * len(eps.Subsets[i].Ports)
 	}
+	return count
 }
 
 func podExists(c *client.Client, podNamespace string, podID string) wait.ConditionFunc {
@@ -376,7 +393,7 @@ containers:
 				glog.Fatalf("%s FAILED: mirror pod has not been created or is not running: %v", desc, err)
 			}
 			// Delete the mirror pod, and wait for it to be recreated.
-			c.Pods(namespace).Delete(podName)
+			c.Pods(namespace).Delete(podName, nil)
 			if err = wait.Poll(time.Second, time.Second*30,
 				podRunning(c, namespace, podName)); err != nil {
 				glog.Fatalf("%s FAILED: mirror pod has not been re-created or is not running: %v", desc, err)
@@ -452,12 +469,11 @@ func runSelfLinkTestOnNamespace(c *client.Client, namespace string) {
 			},
 		},
 		Spec: api.ServiceSpec{
-			Port: 12345,
+			Ports: []api.ServicePort{{Port: 12345, Protocol: "TCP"}},
 			// This is here because validation requires it.
 			Selector: map[string]string{
 				"foo": "bar",
 			},
-			Protocol:        "TCP",
 			SessionAffinity: "None",
 		},
 	}
@@ -514,12 +530,11 @@ func runAtomicPutTest(c *client.Client) {
 			},
 		},
 		Spec: api.ServiceSpec{
-			Port: 12345,
+			Ports: []api.ServicePort{{Port: 12345, Protocol: "TCP"}},
 			// This is here because validation requires it.
 			Selector: map[string]string{
 				"foo": "bar",
 			},
-			Protocol:        "TCP",
 			SessionAffinity: "None",
 		},
 	}
@@ -593,12 +608,11 @@ func runPatchTest(c *client.Client) {
 			},
 		},
 		Spec: api.ServiceSpec{
-			Port: 12345,
+			Ports: []api.ServicePort{{Port: 12345, Protocol: "TCP"}},
 			// This is here because validation requires it.
 			Selector: map[string]string{
 				"foo": "bar",
 			},
-			Protocol:        "TCP",
 			SessionAffinity: "None",
 		},
 	}
@@ -672,7 +686,7 @@ func runMasterServiceTest(client *client.Client) {
 		if err != nil {
 			glog.Fatalf("unexpected error listing endpoints for kubernetes service: %v", err)
 		}
-		if len(ep.Endpoints) == 0 {
+		if countEndpoints(ep) == 0 {
 			glog.Fatalf("no endpoints for kubernetes service: %v", ep)
 		}
 	} else {
@@ -683,7 +697,7 @@ func runMasterServiceTest(client *client.Client) {
 		if err != nil {
 			glog.Fatalf("unexpected error listing endpoints for kubernetes service: %v", err)
 		}
-		if len(ep.Endpoints) == 0 {
+		if countEndpoints(ep) == 0 {
 			glog.Fatalf("no endpoints for kubernetes service: %v", ep)
 		}
 	} else {
@@ -734,8 +748,7 @@ func runServiceTest(client *client.Client) {
 			Selector: map[string]string{
 				"name": "thisisalonglabel",
 			},
-			Port:            8080,
-			Protocol:        "TCP",
+			Ports:           []api.ServicePort{{Port: 8080, Protocol: "TCP"}},
 			SessionAffinity: "None",
 		},
 	}
@@ -751,8 +764,7 @@ func runServiceTest(client *client.Client) {
 			Selector: map[string]string{
 				"name": "thisisalonglabel",
 			},
-			Port:            8080,
-			Protocol:        "TCP",
+			Ports:           []api.ServicePort{{Port: 8080, Protocol: "TCP"}},
 			SessionAffinity: "None",
 		},
 	}
@@ -771,8 +783,7 @@ func runServiceTest(client *client.Client) {
 			Selector: map[string]string{
 				"name": "thisisalonglabel",
 			},
-			Port:            8080,
-			Protocol:        "TCP",
+			Ports:           []api.ServicePort{{Port: 8080, Protocol: "TCP"}},
 			SessionAffinity: "None",
 		},
 	}
@@ -21,8 +21,11 @@ package app
 
 import (
 	"crypto/tls"
+	"io"
 	"net"
 	"net/http"
+	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -30,10 +33,12 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/admission"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/auth/authorizer"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/capabilities"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/cloudprovider"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/master"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 
@@ -42,57 +47,80 @@ import (
 	"github.com/spf13/pflag"
 )
 
+// defaultLongRunningRequestRE matches the paths of watches, proxied requests
+// and the streams of logs, port forwarding and exec sessions.
+const defaultLongRunningRequestRE = "(/|^)((watch|proxy)(/|$)|(logs|portforward|exec)/?$)"
+
 // APIServer runs a kubernetes api server.
 type APIServer struct {
-	WideOpenPort               int
-	Address                    util.IP
-	PublicAddressOverride      util.IP
-	ReadOnlyPort               int
-	APIRate                    float32
-	APIBurst                   int
-	SecurePort                 int
-	TLSCertFile                string
-	TLSPrivateKeyFile          string
-	APIPrefix                  string
-	StorageVersion             string
-	CloudProvider              string
-	CloudConfigFile            string
-	EventTTL                   time.Duration
-	TokenAuthFile              string
-	AuthorizationMode          string
-	AuthorizationPolicyFile    string
-	AdmissionControl           string
-	AdmissionControlConfigFile string
-	EtcdServerList             util.StringList
-	EtcdConfigFile             string
-	CorsAllowedOriginList      util.StringList
-	AllowPrivileged            bool
-	PortalNet                  util.IPNet // TODO: make this a list
-	EnableLogsSupport          bool
-	MasterServiceNamespace     string
-	RuntimeConfig              util.ConfigurationMap
-	KubeletConfig              client.KubeletConfig
-	ClusterName                string
-	EnableProfiling            bool
+	WideOpenPort                int
+	Address                     util.IP
+	PublicAddressOverride       util.IP
+	ReadOnlyPort                int
+	APIRate                     float32
+	APIBurst                    int
+	SecurePort                  int
+	TLSCertFile                 string
+	TLSPrivateKeyFile           string
+	APIPrefix                   string
+	StorageVersion              string
+	StorageContentType          string
+	CloudProvider               string
+	CloudConfigFile             string
+	EventTTL                    time.Duration
+	TokenAuthFile               string
+	AuthorizationMode           string
+	AuthorizationPolicyFile     string
+	AuthorizationRBACSuperUser  string
+	AdmissionControl            string
+	AdmissionControlConfigFile  string
+	EtcdServerList              util.StringList
+	EtcdConfigFile              string
+	CorsAllowedOriginList       util.StringList
+	AllowPrivileged             bool
+	PortalNet                   util.IPNet // TODO: make this a list
+	EnableLogsSupport           bool
+	MasterServiceNamespace      string
+	RuntimeConfig               util.ConfigurationMap
+	KubeletConfig               client.KubeletConfig
+	ClusterName                 string
+	EnableProfiling             bool
+	AuditLogPath                string
+	AuditLogMaxSize             int
+	AuditLogMaxBackups          int
+	AuditLogBodies              bool
+	MaxRequestsInFlight         int
+	MaxMutatingRequestsInFlight int
+	RequestQueueTimeout         time.Duration
+	LongRunningRequestRE        string
+	WatchCacheSize              int
 }
 
 // NewAPIServer creates a new APIServer object with default parameters
 func NewAPIServer() *APIServer {
 	s := APIServer{
-		WideOpenPort:           8080,
-		Address:                util.IP(net.ParseIP("127.0.0.1")),
-		PublicAddressOverride:  util.IP(net.ParseIP("")),
-		ReadOnlyPort:           7080,
-		APIRate:                10.0,
-		APIBurst:               200,
-		SecurePort:             6443,
-		APIPrefix:              "/api",
-		EventTTL:               1 * time.Hour,
-		AuthorizationMode:      "AlwaysAllow",
-		AdmissionControl:       "AlwaysAdmit",
-		EnableLogsSupport:      true,
-		MasterServiceNamespace: api.NamespaceDefault,
-		ClusterName:            "kubernetes",
+		WideOpenPort:                8080,
+		Address:                     util.IP(net.ParseIP("127.0.0.1")),
+		PublicAddressOverride:       util.IP(net.ParseIP("")),
+		ReadOnlyPort:                7080,
+		APIRate:                     10.0,
+		APIBurst:                    200,
+		SecurePort:                  6443,
+		APIPrefix:                   "/api",
+		StorageContentType:          "application/json",
+		EventTTL:                    1 * time.Hour,
+		AuthorizationMode:           "AlwaysAllow",
+		AdmissionControl:            "AlwaysAdmit",
+		EnableLogsSupport:           true,
+		MasterServiceNamespace:      api.NamespaceDefault,
+		ClusterName:                 "kubernetes",
+		AuditLogMaxSize:             100,
+		AuditLogMaxBackups:          5,
+		MaxRequestsInFlight:         400,
+		MaxMutatingRequestsInFlight: 200,
+		RequestQueueTimeout:         time.Second,
+		LongRunningRequestRE:        defaultLongRunningRequestRE,
+		WatchCacheSize:              1000,
 
 		RuntimeConfig: make(util.ConfigurationMap),
 		KubeletConfig: client.KubeletConfig{
@@ -133,12 +161,14 @@ func (s *APIServer) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&s.TLSPrivateKeyFile, "tls_private_key_file", s.TLSPrivateKeyFile, "File containing x509 private key matching --tls_cert_file.")
 	fs.StringVar(&s.APIPrefix, "api_prefix", s.APIPrefix, "The prefix for API requests on the server. Default '/api'.")
 	fs.StringVar(&s.StorageVersion, "storage_version", s.StorageVersion, "The version to store resources with. Defaults to server preferred")
+	fs.StringVar(&s.StorageContentType, "storage_content_type", s.StorageContentType, "The encoding to store resources with in etcd, application/json or "+runtime.ProtobufContentType+". Protobuf requires --storage_version=v1beta3; stored JSON is still read.")
 	fs.StringVar(&s.CloudProvider, "cloud_provider", s.CloudProvider, "The provider for cloud services.  Empty string for no provider.")
 	fs.StringVar(&s.CloudConfigFile, "cloud_config", s.CloudConfigFile, "The path to the cloud provider configuration file.  Empty string for no configuration file.")
 	fs.DurationVar(&s.EventTTL, "event_ttl", s.EventTTL, "Amount of time to retain events. Default 1 hour.")
 	fs.StringVar(&s.TokenAuthFile, "token_auth_file", s.TokenAuthFile, "If set, the file that will be used to secure the secure port of the API server via token authentication.")
 	fs.StringVar(&s.AuthorizationMode, "authorization_mode", s.AuthorizationMode, "Selects how to do authorization on the secure port.  One of: "+strings.Join(apiserver.AuthorizationModeChoices, ","))
 	fs.StringVar(&s.AuthorizationPolicyFile, "authorization_policy_file", s.AuthorizationPolicyFile, "File with authorization policy in csv format, used with --authorization_mode=ABAC, on the secure port.")
+	fs.StringVar(&s.AuthorizationRBACSuperUser, "authorization_rbac_super_user", s.AuthorizationRBACSuperUser, "If set, this username is always authorized by --authorization_mode=RBAC, so that initial roles and bindings can be created.")
 	fs.StringVar(&s.AdmissionControl, "admission_control", s.AdmissionControl, "Ordered list of plug-ins to do admission control of resources into cluster. Comma-delimited list of: "+strings.Join(admission.GetPlugins(), ", "))
 	fs.StringVar(&s.AdmissionControlConfigFile, "admission_control_config_file", s.AdmissionControlConfigFile, "File with admission control configuration.")
 	fs.Var(&s.EtcdServerList, "etcd_servers", "List of etcd servers to watch (http://ip:port), comma separated. Mutually exclusive with -etcd_config")
@@ -151,6 +181,15 @@ func (s *APIServer) AddFlags(fs *pflag.FlagSet) {
 	client.BindKubeletClientConfigFlags(fs, &s.KubeletConfig)
 	fs.StringVar(&s.ClusterName, "cluster_name", s.ClusterName, "The instance prefix for the cluster")
 	fs.BoolVar(&s.EnableProfiling, "profiling", false, "Enable profiling via web interface host:port/debug/pprof/")
+	fs.StringVar(&s.AuditLogPath, "audit_log_path", s.AuditLogPath, "If set, a line is written to this file for every request to the API server. '-' means standard out.")
+	fs.IntVar(&s.AuditLogMaxSize, "audit_log_maxsize", s.AuditLogMaxSize, "Maximum size in megabytes of the audit log file before it gets rotated. 0 means the file is never rotated.")
+	fs.IntVar(&s.AuditLogMaxBackups, "audit_log_maxbackups", s.AuditLogMaxBackups, "Number of rotated audit log files to keep.")
+	fs.BoolVar(&s.AuditLogBodies, "audit_log_bodies", s.AuditLogBodies, "If true, the audit log includes the bodies of the requests that are not read-only, and of their responses.")
+	fs.IntVar(&s.MaxRequestsInFlight, "max_requests_inflight", s.MaxRequestsInFlight, "The maximum number of read-only requests served at the same time, beyond which requests are queued per user. 0 means no limit.")
+	fs.IntVar(&s.MaxMutatingRequestsInFlight, "max_mutating_requests_inflight", s.MaxMutatingRequestsInFlight, "The maximum number of mutating requests served at the same time, beyond which requests are queued per user. 0 means no limit.")
+	fs.DurationVar(&s.RequestQueueTimeout, "request_queue_timeout", s.RequestQueueTimeout, "How long a request waits in its user's queue when too many requests are in flight, before it is rejected with a 429.")
+	fs.StringVar(&s.LongRunningRequestRE, "long_running_request_regexp", s.LongRunningRequestRE, "A regular expression matching the paths of long running requests, like watches and proxied requests, which are not limited by --max_requests_inflight and --max_mutating_requests_inflight.")
+	fs.IntVar(&s.WatchCacheSize, "watch_cache_size", s.WatchCacheSize, "The number of recent changes to pods, nodes and endpoints kept in memory to serve watches and lists at a resource version without etcd. 0 disables the caches.")
 }
 
 // TODO: Longer term we should read this from some config store, rather than a flag.
@@ -160,7 +199,7 @@ func (s *APIServer) verifyPortalFlags() {
 	}
 }
 
-func newEtcd(etcdConfigFile string, etcdServerList util.StringList, storageVersion string) (helper tools.EtcdHelper, err error) {
+func newEtcd(etcdConfigFile string, etcdServerList util.StringList, storageVersion, storageContentType string) (helper tools.EtcdHelper, err error) {
 	var client tools.EtcdGetSet
 	if etcdConfigFile != "" {
 		client, err = etcd.NewClientFromFile(etcdConfigFile)
@@ -171,7 +210,7 @@ func newEtcd(etcdConfigFile string, etcdServerList util.StringList, storageVersi
 		client = etcd.NewClient(etcdServerList)
 	}
 
-	return master.NewEtcdHelper(client, storageVersion)
+	return master.NewEtcdHelper(client, storageVersion, storageContentType)
 }
 
 // Run runs the specified APIServer.  This should never exit.
@@ -207,7 +246,7 @@ func (s *APIServer) Run(_ []string) error {
 		glog.Fatalf("Invalid server address: %v", err)
 	}
 
-	helper, err := newEtcd(s.EtcdConfigFile, s.EtcdServerList, s.StorageVersion)
+	helper, err := newEtcd(s.EtcdConfigFile, s.EtcdServerList, s.StorageVersion, s.StorageContentType)
 	if err != nil {
 		glog.Fatalf("Invalid storage version or misconfigured etcd: %v", err)
 	}
@@ -219,10 +258,34 @@ func (s *APIServer) Run(_ []string) error {
 		glog.Fatalf("Invalid Authentication Config: %v", err)
 	}
 
-	authorizer, err := apiserver.NewAuthorizerFromAuthorizationConfig(s.AuthorizationMode, s.AuthorizationPolicyFile)
+	// The RBAC authorizer reads roles and bindings from the master's registries, so the master builds it.
+	enableRBAC := s.AuthorizationMode == apiserver.ModeRBAC
+	if !enableRBAC && s.AuthorizationRBACSuperUser != "" {
+		glog.Fatalf("Invalid Authorization Config: cannot specify --authorization_rbac_super_user without mode RBAC")
+	}
+	var authz authorizer.Authorizer
+	if !enableRBAC {
+		authz, err = apiserver.NewAuthorizerFromAuthorizationConfig(s.AuthorizationMode, s.AuthorizationPolicyFile)
+		if err != nil {
+			glog.Fatalf("Invalid Authorization Config: %v", err)
+		}
+	}
+
+	var auditWriter io.Writer
+	if s.AuditLogPath == "-" {
+		auditWriter = os.Stdout
+	} else if s.AuditLogPath != "" {
+		auditWriter, err = util.NewRotatingFile(s.AuditLogPath, int64(s.AuditLogMaxSize)*1024*1024, s.AuditLogMaxBackups)
+		if err != nil {
+			glog.Fatalf("Unable to open the audit log: %v", err)
+		}
+	}
+
+	longRunningRequestRE, err := regexp.Compile(s.LongRunningRequestRE)
 	if err != nil {
-		glog.Fatalf("Invalid Authorization Config: %v", err)
+		glog.Fatalf("Invalid --long_running_request_regexp: %v", err)
 	}
+	inFlightLimiter := apiserver.NewInFlightLimiter(s.MaxRequestsInFlight, s.MaxMutatingRequestsInFlight, s.RequestQueueTimeout, longRunningRequestRE)
 
 	admissionControlPluginNames := strings.Split(s.AdmissionControl, ",")
 	admissionController := admission.NewFromPlugins(client, admissionControlPluginNames, s.AdmissionControlConfigFile)
@@ -244,11 +307,17 @@ func (s *APIServer) Run(_ []string) error {
 		ReadWritePort:          s.SecurePort,
 		PublicAddress:          net.IP(s.PublicAddressOverride),
 		Authenticator:          authenticator,
-		Authorizer:             authorizer,
+		Authorizer:             authz,
 		AdmissionControl:       admissionController,
 		EnableV1Beta3:          v1beta3,
 		MasterServiceNamespace: s.MasterServiceNamespace,
 		ClusterName:            s.ClusterName,
+		EnableRBAC:             enableRBAC,
+		RBACSuperUser:          s.AuthorizationRBACSuperUser,
+		AuditWriter:            auditWriter,
+		AuditLogBodies:         s.AuditLogBodies,
+		InFlightLimiter:        inFlightLimiter,
+		WatchCacheCapacity:     s.WatchCacheSize,
 	}
 	m := master.New(config)
 
@@ -29,6 +29,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/record"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/cloudprovider"
 	nodeControllerPkg "github.com/GoogleCloudPlatform/kubernetes/pkg/cloudprovider/controller"
@@ -38,6 +39,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/resourcequota"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/service"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/volumeclaimbinder"
 
 	"github.com/golang/glog"
 	"github.com/spf13/pflag"
@@ -52,13 +54,19 @@ type CMServer struct {
 	CloudConfigFile         string
 	MinionRegexp            string
 	NodeSyncPeriod          time.Duration
+	ConcurrentEndpointSyncs int
+	ConcurrentRCSyncs       int
 	ResourceQuotaSyncPeriod time.Duration
 	NamespaceSyncPeriod     time.Duration
+	PVClaimBinderSyncPeriod time.Duration
 	RegisterRetryCount      int
 	MachineList             util.StringList
 	SyncNodeList            bool
 	SyncNodeStatus          bool
 	PodEvictionTimeout      time.Duration
+	DeletingPodsQps         float32
+	DeletingPodsBurst       int
+	UnhealthyZoneThreshold  float32
 
 	// TODO: Discover these by pinging the host machines, and rip out these params.
 	NodeMilliCPU int64
@@ -74,10 +82,16 @@ func NewCMServer() *CMServer {
 		Port:                    ports.ControllerManagerPort,
 		Address:                 util.IP(net.ParseIP("127.0.0.1")),
 		NodeSyncPeriod:          10 * time.Second,
+		ConcurrentEndpointSyncs: 5,
+		ConcurrentRCSyncs:       5,
 		ResourceQuotaSyncPeriod: 10 * time.Second,
 		NamespaceSyncPeriod:     1 * time.Minute,
+		PVClaimBinderSyncPeriod: 10 * time.Second,
 		RegisterRetryCount:      10,
 		PodEvictionTimeout:      5 * time.Minute,
+		DeletingPodsQps:         0.1,
+		DeletingPodsBurst:       10,
+		UnhealthyZoneThreshold:  0.55,
 		NodeMilliCPU:            1000,
 		NodeMemory:              resource.MustParse("3Gi"),
 		SyncNodeList:            true,
@@ -101,9 +115,17 @@ func (s *CMServer) AddFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&s.NodeSyncPeriod, "node_sync_period", s.NodeSyncPeriod, ""+
 		"The period for syncing nodes from cloudprovider. Longer periods will result in "+
 		"fewer calls to cloud provider, but may delay addition of new nodes to cluster.")
+	fs.IntVar(&s.ConcurrentEndpointSyncs, "concurrent_endpoint_syncs", s.ConcurrentEndpointSyncs, "The number of endpoint syncing operations that will be done concurrently. Larger number = faster endpoint updating, but more CPU (and network) load")
+	fs.IntVar(&s.ConcurrentRCSyncs, "concurrent_rc_syncs", s.ConcurrentRCSyncs, "The number of replication controllers that are allowed to sync concurrently. Larger number = more responsive replica management, but more CPU (and network) load")
 	fs.DurationVar(&s.ResourceQuotaSyncPeriod, "resource_quota_sync_period", s.ResourceQuotaSyncPeriod, "The period for syncing quota usage status in the system")
 	fs.DurationVar(&s.NamespaceSyncPeriod, "namespace_sync_period", s.NamespaceSyncPeriod, "The period for syncing namespace life-cycle updates")
+	fs.DurationVar(&s.PVClaimBinderSyncPeriod, "pvclaimbinder_sync_period", s.PVClaimBinderSyncPeriod, "The period for syncing persistent volumes and persistent volume claims")
 	fs.DurationVar(&s.PodEvictionTimeout, "pod_eviction_timeout", s.PodEvictionTimeout, "The grace peroid for deleting pods on failed nodes.")
+	fs.Float32Var(&s.DeletingPodsQps, "deleting_pods_qps", s.DeletingPodsQps, "Number of nodes per second on which pods are deleted in case of node failure.")
+	fs.IntVar(&s.DeletingPodsBurst, "deleting_pods_burst", s.DeletingPodsBurst, "Number of nodes on which pods are bursty deleted in case of node failure. For more details look into RateLimiter.")
+	fs.Float32Var(&s.UnhealthyZoneThreshold, "unhealthy_zone_threshold", s.UnhealthyZoneThreshold, ""+
+		"Fraction of not ready nodes in a zone at which the zone is treated as disrupted and pod eviction in it stops. "+
+		"Zero disables the check.")
 	fs.IntVar(&s.RegisterRetryCount, "register_retry_count", s.RegisterRetryCount, ""+
 		"The number of retries for initial node registration.  Retry interval equals node_sync_period.")
 	fs.Var(&s.MachineList, "machines", "List of machines to schedule onto, comma separated.")
@@ -157,11 +179,15 @@ func (s *CMServer) Run(_ []string) error {
 		http.ListenAndServe(net.JoinHostPort(s.Address.String(), strconv.Itoa(s.Port)), nil)
 	}()
 
-	endpoints := service.NewEndpointController(kubeClient)
-	go util.Forever(func() { endpoints.SyncServiceEndpoints() }, time.Second*10)
+	// The pod informer is shared by every controller that watches pods, so
+	// that pods are only listed and watched once.
+	podInformer := cache.NewPodInformer(kubeClient, 0)
 
-	controllerManager := replicationControllerPkg.NewReplicationManager(kubeClient)
-	controllerManager.Run(replicationControllerPkg.DefaultSyncPeriod)
+	endpoints := service.NewEndpointController(kubeClient, podInformer, service.DefaultSyncPeriod)
+	endpoints.Run(s.ConcurrentEndpointSyncs, util.NeverStop)
+
+	controllerManager := replicationControllerPkg.NewReplicationManager(kubeClient, podInformer, replicationControllerPkg.DefaultSyncPeriod)
+	controllerManager.Run(s.ConcurrentRCSyncs, util.NeverStop)
 
 	kubeletClient, err := client.NewKubeletClient(&s.KubeletConfig)
 	if err != nil {
@@ -178,14 +204,21 @@ func (s *CMServer) Run(_ []string) error {
 
 	nodeController := nodeControllerPkg.NewNodeController(cloud, s.MinionRegexp, s.MachineList, nodeResources,
 		kubeClient, kubeletClient, record.FromSource(api.EventSource{Component: "controllermanager"}),
-		s.RegisterRetryCount, s.PodEvictionTimeout)
+		s.RegisterRetryCount, s.PodEvictionTimeout, util.NewTokenBucketRateLimiter(s.DeletingPodsQps, s.DeletingPodsBurst),
+		s.UnhealthyZoneThreshold)
 	nodeController.Run(s.NodeSyncPeriod, s.SyncNodeList, s.SyncNodeStatus)
 
-	resourceQuotaManager := resourcequota.NewResourceQuotaManager(kubeClient)
-	resourceQuotaManager.Run(s.ResourceQuotaSyncPeriod)
+	resourceQuotaManager := resourcequota.NewResourceQuotaManager(kubeClient, podInformer, s.ResourceQuotaSyncPeriod)
+	resourceQuotaManager.Run(1, util.NeverStop)
+
+	namespaceManager := namespace.NewNamespaceManager(kubeClient, s.NamespaceSyncPeriod)
+	namespaceManager.Run(1, util.NeverStop)
 
-	namespaceManager := namespace.NewNamespaceManager(kubeClient)
-	namespaceManager.Run(s.NamespaceSyncPeriod)
+	pvclaimBinder, err := volumeclaimbinder.NewPersistentVolumeClaimBinder(kubeClient, ProbePersistentVolumePlugins())
+	if err != nil {
+		glog.Fatalf("Failure to start persistent volume claim binder: %v", err)
+	}
+	pvclaimBinder.Run(s.PVClaimBinderSyncPeriod)
 
 	select {}
 	return nil
@@ -42,14 +42,24 @@ type ProxyServer struct {
 	ClientConfig client.Config
 	HealthzPort  int
 	OOMScoreAdj  int
+	ProxyMode    string
 }
 
+// The modes in which kube-proxy can run.
+const (
+	// ProxyModeUserspace proxies the traffic through a userspace listener per service port.
+	ProxyModeUserspace = "userspace"
+	// ProxyModeIptables programs iptables to DNAT the traffic directly to the endpoints.
+	ProxyModeIptables = "iptables"
+)
+
 // NewProxyServer creates a new ProxyServer object with default parameters
 func NewProxyServer() *ProxyServer {
 	return &ProxyServer{
 		BindAddress: util.IP(net.ParseIP("0.0.0.0")),
 		HealthzPort: 10249,
 		OOMScoreAdj: -899,
+		ProxyMode:   ProxyModeUserspace,
 	}
 }
 
@@ -59,6 +69,7 @@ func (s *ProxyServer) AddFlags(fs *pflag.FlagSet) {
 	client.BindClientConfigFlags(fs, &s.ClientConfig)
 	fs.IntVar(&s.HealthzPort, "healthz_port", s.HealthzPort, "The port to bind the health check server. Use 0 to disable.")
 	fs.IntVar(&s.OOMScoreAdj, "oom_score_adj", s.OOMScoreAdj, "The oom_score_adj value for kube-proxy process. Values must be within the range [-1000, 1000]")
+	fs.StringVar(&s.ProxyMode, "proxy_mode", s.ProxyMode, "Which proxy mode to use: 'userspace' copies the traffic through the proxy, 'iptables' programs iptables to send it directly to the endpoints.")
 }
 
 // Run runs the specified ProxyServer.  This should never exit.
@@ -74,16 +85,39 @@ func (s *ProxyServer) Run(_ []string) error {
 	if net.IP(s.BindAddress).To4() == nil {
 		protocol = iptables.ProtocolIpv6
 	}
-	loadBalancer := proxy.NewLoadBalancerRR()
-	proxier := proxy.NewProxier(loadBalancer, net.IP(s.BindAddress), iptables.New(exec.New(), protocol))
-	if proxier == nil {
-		glog.Fatalf("failed to create proxier, aborting")
-	}
+	ipt := iptables.New(exec.New(), protocol)
+
+	var syncLoop func()
+	switch s.ProxyMode {
+	case ProxyModeUserspace:
+		// The iptables mode may have run before, and its portals would take precedence.
+		if err := proxy.CleanupIptablesProxyRules(ipt); err != nil {
+			glog.Errorf("Failed to remove the rules of the iptables proxy mode: %v", err)
+		}
+		loadBalancer := proxy.NewLoadBalancerRR()
+		proxier := proxy.NewProxier(loadBalancer, net.IP(s.BindAddress), ipt)
+		if proxier == nil {
+			glog.Fatalf("failed to create proxier, aborting")
+		}
+
+		// Wire proxier to handle changes to services
+		serviceConfig.RegisterHandler(proxier)
+		// And wire loadBalancer to handle changes to endpoints to services
+		endpointsConfig.RegisterHandler(loadBalancer)
+		syncLoop = proxier.SyncLoop
+	case ProxyModeIptables:
+		proxier := proxy.NewIptablesProxier(ipt)
+		if proxier == nil {
+			glog.Fatalf("failed to create iptables proxier, aborting")
+		}
 
-	// Wire proxier to handle changes to services
-	serviceConfig.RegisterHandler(proxier)
-	// And wire loadBalancer to handle changes to endpoints to services
-	endpointsConfig.RegisterHandler(loadBalancer)
+		// The iptables proxier handles changes to both services and endpoints
+		serviceConfig.RegisterHandler(config.ServiceConfigHandlerFunc(proxier.OnServiceUpdate))
+		endpointsConfig.RegisterHandler(config.EndpointsConfigHandlerFunc(proxier.OnEndpointsUpdate))
+		syncLoop = proxier.SyncLoop
+	default:
+		glog.Fatalf("unknown proxy mode %q, must be %q or %q", s.ProxyMode, ProxyModeUserspace, ProxyModeIptables)
+	}
 
 	// Note: RegisterHandler() calls need to happen before creation of Sources because sources
 	// only notify on changes, and the initial update (on process start) may be lost if no handlers
@@ -115,6 +149,6 @@ func (s *ProxyServer) Run(_ []string) error {
 	}
 
 	// Just loop forever for now...
-	proxier.SyncLoop()
+	syncLoop()
 	return nil
 }
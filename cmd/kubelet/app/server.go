@@ -70,6 +70,9 @@ type KubeletServer struct {
 	MinimumGCAge                   time.Duration
 	MaxPerPodContainerCount        int
 	MaxContainerCount              int
+	MaxContainerLogSizeMB          int
+	MaxRotatedContainerLogs        int
+	DockerRoot                     string
 	AuthPath                       string
 	CadvisorPort                   uint
 	OOMScoreAdj                    int
@@ -81,9 +84,13 @@ type KubeletServer struct {
 	StreamingConnectionIdleTimeout time.Duration
 	ImageGCHighThresholdPercent    int
 	ImageGCLowThresholdPercent     int
+	EvictionMemoryAvailableMB      int
+	EvictionDiskAvailablePercent   int
 	NetworkPluginName              string
 	CloudProvider                  string
 	CloudConfigFile                string
+	ContainerRuntime               string
+	RktInsecureSkipVerify          bool
 }
 
 // NewKubeletServer will create a new KubeletServer with default values.
@@ -95,20 +102,26 @@ func NewKubeletServer() *KubeletServer {
 		EnableServer:       true,
 		Address:            util.IP(net.ParseIP("127.0.0.1")),
 		Port:               ports.KubeletPort,
-		PodInfraContainerImage:      kubelet.PodInfraContainerImage,
-		RootDirectory:               defaultRootDir,
-		RegistryBurst:               10,
-		EnableDebuggingHandlers:     true,
-		MinimumGCAge:                1 * time.Minute,
-		MaxPerPodContainerCount:     5,
-		MaxContainerCount:           100,
-		CadvisorPort:                4194,
-		OOMScoreAdj:                 -900,
-		MasterServiceNamespace:      api.NamespaceDefault,
-		ImageGCHighThresholdPercent: 90,
-		ImageGCLowThresholdPercent:  80,
-		NetworkPluginName:           "",
-		HostNetworkSources:          kubelet.FileSource,
+		PodInfraContainerImage:       kubelet.PodInfraContainerImage,
+		RootDirectory:                defaultRootDir,
+		RegistryBurst:                10,
+		EnableDebuggingHandlers:      true,
+		MinimumGCAge:                 1 * time.Minute,
+		MaxPerPodContainerCount:      5,
+		MaxContainerCount:            100,
+		MaxContainerLogSizeMB:        100,
+		MaxRotatedContainerLogs:      2,
+		DockerRoot:                   "/var/lib/docker",
+		CadvisorPort:                 4194,
+		OOMScoreAdj:                  -900,
+		MasterServiceNamespace:       api.NamespaceDefault,
+		ImageGCHighThresholdPercent:  90,
+		ImageGCLowThresholdPercent:   80,
+		EvictionMemoryAvailableMB:    100,
+		EvictionDiskAvailablePercent: 5,
+		NetworkPluginName:            "",
+		HostNetworkSources:           kubelet.FileSource,
+		ContainerRuntime:             "docker",
 	}
 }
 
@@ -116,7 +129,7 @@ func NewKubeletServer() *KubeletServer {
 func (s *KubeletServer) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&s.Config, "config", s.Config, "Path to the config file or directory of files")
 	fs.DurationVar(&s.SyncFrequency, "sync_frequency", s.SyncFrequency, "Max period between synchronizing running containers and config")
-	fs.DurationVar(&s.FileCheckFrequency, "file_check_frequency", s.FileCheckFrequency, "Duration between checking config files for new data")
+	fs.DurationVar(&s.FileCheckFrequency, "file_check_frequency", s.FileCheckFrequency, "Duration between checking config files for new data. On Linux, changes are also picked up as soon as they happen")
 	fs.DurationVar(&s.HTTPCheckFrequency, "http_check_frequency", s.HTTPCheckFrequency, "Duration between checking http for new data")
 	fs.StringVar(&s.ManifestURL, "manifest_url", s.ManifestURL, "URL for accessing the container manifest")
 	fs.BoolVar(&s.EnableServer, "enable_server", s.EnableServer, "Enable the info server")
@@ -135,6 +148,9 @@ func (s *KubeletServer) AddFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&s.MinimumGCAge, "minimum_container_ttl_duration", s.MinimumGCAge, "Minimum age for a finished container before it is garbage collected.  Examples: '300ms', '10s' or '2h45m'")
 	fs.IntVar(&s.MaxPerPodContainerCount, "maximum_dead_containers_per_container", s.MaxPerPodContainerCount, "Maximum number of old instances of a container to retain per container.  Each container takes up some disk space.  Default: 5.")
 	fs.IntVar(&s.MaxContainerCount, "maximum_dead_containers", s.MaxContainerCount, "Maximum number of old instances of a containers to retain globally.  Each container takes up some disk space.  Default: 100.")
+	fs.IntVar(&s.MaxContainerLogSizeMB, "maximum_container_log_size_mb", s.MaxContainerLogSizeMB, "Size in MB at which the log of a running container is rotated. Zero disables log rotation.  Default: 100.")
+	fs.IntVar(&s.MaxRotatedContainerLogs, "maximum_rotated_container_logs", s.MaxRotatedContainerLogs, "Maximum number of rotated logs to retain per container.  Default: 2.")
+	fs.StringVar(&s.DockerRoot, "docker_root", s.DockerRoot, "Root directory of the Docker daemon, where it stores the logs of containers.  Default: /var/lib/docker.")
 	fs.StringVar(&s.AuthPath, "auth_path", s.AuthPath, "Path to .kubernetes_auth file, specifying how to authenticate to API server.")
 	fs.UintVar(&s.CadvisorPort, "cadvisor_port", s.CadvisorPort, "The port of the localhost cAdvisor endpoint")
 	fs.IntVar(&s.OOMScoreAdj, "oom_score_adj", s.OOMScoreAdj, "The oom_score_adj value for kubelet process. Values must be within the range [-1000, 1000]")
@@ -146,9 +162,13 @@ func (s *KubeletServer) AddFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&s.StreamingConnectionIdleTimeout, "streaming_connection_idle_timeout", 0, "Maximum time a streaming connection can be idle before the connection is automatically closed.  Example: '5m'")
 	fs.IntVar(&s.ImageGCHighThresholdPercent, "image_gc_high_threshold", s.ImageGCHighThresholdPercent, "The percent of disk usage after which image garbage collection is always run. Default: 90%%")
 	fs.IntVar(&s.ImageGCLowThresholdPercent, "image_gc_low_threshold", s.ImageGCLowThresholdPercent, "The percent of disk usage before which image garbage collection is never run. Lowest disk usage to garbage collect to. Default: 80%%")
+	fs.IntVar(&s.EvictionMemoryAvailableMB, "eviction_memory_available_mb", s.EvictionMemoryAvailableMB, "Memory in MB that must stay available on the node. Below it, pods are evicted and the node reports memory pressure. Zero disables memory eviction.  Default: 100.")
+	fs.IntVar(&s.EvictionDiskAvailablePercent, "eviction_disk_available_percent", s.EvictionDiskAvailablePercent, "The percent of the disk holding Docker images and containers that must stay available. Below it, pods are evicted and the node reports disk pressure. Zero disables disk eviction.  Default: 5%%")
 	fs.StringVar(&s.NetworkPluginName, "network_plugin", s.NetworkPluginName, "<Warning: Alpha feature> The name of the network plugin to be invoked for various events in kubelet/pod lifecycle")
 	fs.StringVar(&s.CloudProvider, "cloud_provider", s.CloudProvider, "The provider for cloud services.  Empty string for no provider.")
 	fs.StringVar(&s.CloudConfigFile, "cloud_config", s.CloudConfigFile, "The path to the cloud provider configuration file.  Empty string for no configuration file.")
+	fs.StringVar(&s.ContainerRuntime, "container_runtime", s.ContainerRuntime, "The container runtime to use. Possible values: 'docker', 'rkt'. Default: 'docker'.")
+	fs.BoolVar(&s.RktInsecureSkipVerify, "rkt_insecure_skip_verify", s.RktInsecureSkipVerify, "If true, the rkt runtime fetches images without verifying their signatures or keys. [default=false]")
 }
 
 // Run runs the specified KubeletServer.  This should never exit.
@@ -179,6 +199,17 @@ func (s *KubeletServer) Run(_ []string) error {
 		LowThresholdPercent:  s.ImageGCLowThresholdPercent,
 	}
 
+	evictionPolicy := kubelet.EvictionPolicy{
+		MemoryAvailableThreshold:      int64(s.EvictionMemoryAvailableMB) * 1024 * 1024,
+		DiskAvailableThresholdPercent: s.EvictionDiskAvailablePercent,
+	}
+
+	containerLogPolicy := kubelet.ContainerLogPolicy{
+		MaxSize:    int64(s.MaxContainerLogSizeMB) * 1024 * 1024,
+		MaxFiles:   s.MaxRotatedContainerLogs,
+		DockerRoot: s.DockerRoot,
+	}
+
 	cloud := cloudprovider.InitCloudProvider(s.CloudProvider, s.CloudConfigFile)
 	glog.Infof("Successfully initialized cloud provider: %q from the config file: %q\n", s.CloudProvider, s.CloudConfigFile)
 
@@ -218,7 +249,11 @@ func (s *KubeletServer) Run(_ []string) error {
 		NetworkPluginName:              s.NetworkPluginName,
 		StreamingConnectionIdleTimeout: s.StreamingConnectionIdleTimeout,
 		ImageGCPolicy:                  imageGCPolicy,
+		EvictionPolicy:                 evictionPolicy,
+		ContainerLogPolicy:             containerLogPolicy,
 		Cloud:                          cloud,
+		ContainerRuntime:               s.ContainerRuntime,
+		RktInsecureSkipVerify:          s.RktInsecureSkipVerify,
 	}
 
 	RunKubelet(&kcfg)
@@ -288,6 +323,15 @@ func SimpleKubelet(client *client.Client,
 		HighThresholdPercent: 90,
 		LowThresholdPercent:  80,
 	}
+	evictionPolicy := kubelet.EvictionPolicy{
+		MemoryAvailableThreshold:      100 * 1024 * 1024,
+		DiskAvailableThresholdPercent: 5,
+	}
+	containerLogPolicy := kubelet.ContainerLogPolicy{
+		MaxSize:    100 * 1024 * 1024,
+		MaxFiles:   2,
+		DockerRoot: "/var/lib/docker",
+	}
 	kcfg := KubeletConfig{
 		KubeClient:             client,
 		DockerClient:           dockerClient,
@@ -311,7 +355,10 @@ func SimpleKubelet(client *client.Client,
 		CadvisorInterface:       cadvisorInterface,
 		ConfigFile:              configFilePath,
 		ImageGCPolicy:           imageGCPolicy,
+		EvictionPolicy:          evictionPolicy,
+		ContainerLogPolicy:      containerLogPolicy,
 		Cloud:                   cloud,
+		ContainerRuntime:        "docker",
 	}
 	return &kcfg
 }
@@ -421,7 +468,11 @@ type KubeletConfig struct {
 	Recorder                       record.EventRecorder
 	TLSOptions                     *kubelet.TLSOptions
 	ImageGCPolicy                  kubelet.ImageGCPolicy
+	EvictionPolicy                 kubelet.EvictionPolicy
+	ContainerLogPolicy             kubelet.ContainerLogPolicy
 	Cloud                          cloudprovider.Interface
+	ContainerRuntime               string
+	RktInsecureSkipVerify          bool
 }
 
 func createAndInitKubelet(kc *KubeletConfig, pc *config.PodConfig) (*kubelet.Kubelet, error) {
@@ -453,6 +504,7 @@ func createAndInitKubelet(kc *KubeletConfig, pc *config.PodConfig) (*kubelet.Kub
 		float32(kc.RegistryPullQPS),
 		kc.RegistryBurst,
 		gcPolicy,
+		kc.ContainerLogPolicy,
 		pc.SeenAllSources,
 		kc.ClusterDomain,
 		net.IP(kc.ClusterDNS),
@@ -464,7 +516,10 @@ func createAndInitKubelet(kc *KubeletConfig, pc *config.PodConfig) (*kubelet.Kub
 		kc.Recorder,
 		kc.CadvisorInterface,
 		kc.ImageGCPolicy,
-		kc.Cloud)
+		kc.EvictionPolicy,
+		kc.Cloud,
+		kc.ContainerRuntime,
+		kc.RktInsecureSkipVerify)
 
 	if err != nil {
 		return nil, err
@@ -473,6 +528,9 @@ func createAndInitKubelet(kc *KubeletConfig, pc *config.PodConfig) (*kubelet.Kub
 	k.BirthCry()
 
 	k.StartGarbageCollection()
+	k.StartLogRotation()
+
+	k.StartEviction()
 
 	return k, nil
 }
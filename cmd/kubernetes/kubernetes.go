@@ -34,6 +34,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/testapi"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/record"
 	nodeControllerPkg "github.com/GoogleCloudPlatform/kubernetes/pkg/cloudprovider/controller"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/controller"
@@ -80,7 +81,7 @@ func (h *delegateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 func runApiServer(etcdClient tools.EtcdClient, addr net.IP, port int, masterServiceNamespace string) {
 	handler := delegateHandler{}
 
-	helper, err := master.NewEtcdHelper(etcdClient, "")
+	helper, err := master.NewEtcdHelper(etcdClient, "", "")
 	if err != nil {
 		glog.Fatalf("Unable to get etcd helper: %v", err)
 	}
@@ -130,14 +131,17 @@ func runControllerManager(machineList []string, cl *client.Client, nodeMilliCPU,
 	kubeClient := &client.HTTPKubeletClient{Client: http.DefaultClient, Port: ports.KubeletPort}
 
 	nodeController := nodeControllerPkg.NewNodeController(nil, "", machineList, nodeResources, cl, kubeClient,
-		record.FromSource(api.EventSource{Component: "controllermanager"}), 10, 5*time.Minute)
+		record.FromSource(api.EventSource{Component: "controllermanager"}), 10, 5*time.Minute,
+		util.NewTokenBucketRateLimiter(0.1, 10), 0.55)
 	nodeController.Run(10*time.Second, true, true)
 
-	endpoints := service.NewEndpointController(cl)
-	go util.Forever(func() { endpoints.SyncServiceEndpoints() }, time.Second*10)
+	podInformer := cache.NewPodInformer(cl, 0)
 
-	controllerManager := controller.NewReplicationManager(cl)
-	controllerManager.Run(controller.DefaultSyncPeriod)
+	endpoints := service.NewEndpointController(cl, podInformer, service.DefaultSyncPeriod)
+	endpoints.Run(5, util.NeverStop)
+
+	controllerManager := controller.NewReplicationManager(cl, podInformer, controller.DefaultSyncPeriod)
+	controllerManager.Run(5, util.NeverStop)
 }
 
 func startComponents(etcdClient tools.EtcdClient, cl *client.Client, addr net.IP, port int) {
@@ -37,6 +37,10 @@ const namespaceKey key = 0
 // userKey is the context key for the request user.
 const userKey key = 1
 
+// resourceVersionKey is the context key for the resource version a list was
+// requested at.
+const resourceVersionKey key = 2
+
 // NewContext instantiates a base context object for request flows.
 func NewContext() Context {
 	return context.TODO()
@@ -101,3 +105,14 @@ func UserFrom(ctx Context) (user.Info, bool) {
 	user, ok := ctx.Value(userKey).(user.Info)
 	return user, ok
 }
+
+// WithResourceVersion returns a copy of parent in which the resource version value is set
+func WithResourceVersion(parent Context, resourceVersion string) Context {
+	return WithValue(parent, resourceVersionKey, resourceVersion)
+}
+
+// ResourceVersionFrom returns the value of the resource version key on the ctx
+func ResourceVersionFrom(ctx Context) (string, bool) {
+	resourceVersion, ok := ctx.Value(resourceVersionKey).(string)
+	return resourceVersion, ok
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+
+package endpoints
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+)
+
+// RepackSubsets takes a slice of EndpointSubset objects, expands it to the full
+// representation, and then repacks that into the canonical layout: addresses
+// offering the same set of ports share a subset, port names are unique within
+// a subset, and subsets, addresses and ports are sorted.  This ensures that code
+// which operates on these objects can rely on the common form for things like
+// comparison.  The result is a newly allocated slice.
+func RepackSubsets(subsets []api.EndpointSubset) []api.EndpointSubset {
+	// First map each unique address to the set of ports it offers.  Addresses
+	// are de-duped by IP, keeping the first known target reference.
+	addrs := map[string]*api.EndpointAddress{}
+	addrPorts := map[string]map[api.EndpointPort]bool{}
+	for i := range subsets {
+		for j := range subsets[i].Addresses {
+			epa := &subsets[i].Addresses[j]
+			if addr, found := addrs[epa.IP]; !found {
+				copied := *epa
+				addrs[epa.IP] = &copied
+				addrPorts[epa.IP] = map[api.EndpointPort]bool{}
+			} else if addr.TargetRef == nil {
+				addr.TargetRef = epa.TargetRef
+			}
+			for _, epp := range subsets[i].Ports {
+				addrPorts[epa.IP][epp] = true
+			}
+		}
+	}
+
+	// Next, group the addresses by the set of ports they offer.  An address
+	// offering several ports with the same name is split across subsets.
+	bySet := map[string]*api.EndpointSubset{}
+	keys := []string{}
+	for ip, portSet := range addrPorts {
+		ports := make([]api.EndpointPort, 0, len(portSet))
+		for epp := range portSet {
+			ports = append(ports, epp)
+		}
+		sort.Sort(portsByName(ports))
+		for _, group := range splitByName(ports) {
+			key := portsKey(group)
+			subset, found := bySet[key]
+			if !found {
+				subset = &api.EndpointSubset{Ports: group}
+				bySet[key] = subset
+				keys = append(keys, key)
+			}
+			subset.Addresses = append(subset.Addresses, *addrs[ip])
+		}
+	}
+
+	// Finally, build the result in a deterministic order.
+	sort.Strings(keys)
+	result := make([]api.EndpointSubset, 0, len(keys))
+	for _, key := range keys {
+		subset := bySet[key]
+		sort.Sort(addressesByIP(subset.Addresses))
+		result = append(result, *subset)
+	}
+	return result
+}
+
+// splitByName partitions a sorted list of ports into groups in which every
+// port name appears at most once.
+func splitByName(ports []api.EndpointPort) [][]api.EndpointPort {
+	groups := [][]api.EndpointPort{}
+	names := []map[string]bool{}
+	for _, epp := range ports {
+		placed := false
+		for i := range groups {
+			if !names[i][epp.Name] {
+				groups[i] = append(groups[i], epp)
+				names[i][epp.Name] = true
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			groups = append(groups, []api.EndpointPort{epp})
+			names = append(names, map[string]bool{epp.Name: true})
+		}
+	}
+	return groups
+}
+
+func portsKey(ports []api.EndpointPort) string {
+	parts := make([]string, 0, len(ports))
+	for _, epp := range ports {
+		parts = append(parts, fmt.Sprintf("%s/%d/%s", epp.Name, epp.Port, epp.Protocol))
+	}
+	return strings.Join(parts, ",")
+}
+
+type addressesByIP []api.EndpointAddress
+
+func (sl addressesByIP) Len() int           { return len(sl) }
+func (sl addressesByIP) Swap(i, j int)      { sl[i], sl[j] = sl[j], sl[i] }
+func (sl addressesByIP) Less(i, j int) bool { return sl[i].IP < sl[j].IP }
+
+type portsByName []api.EndpointPort
+
+func (sl portsByName) Len() int      { return len(sl) }
+func (sl portsByName) Swap(i, j int) { sl[i], sl[j] = sl[j], sl[i] }
+func (sl portsByName) Less(i, j int) bool {
+	if sl[i].Name != sl[j].Name {
+		return sl[i].Name < sl[j].Name
+	}
+	if sl[i].Port != sl[j].Port {
+		return sl[i].Port < sl[j].Port
+	}
+	return sl[i].Protocol < sl[j].Protocol
+}
@@ -17,10 +17,13 @@ limitations under the License.
 package api
 
 import (
+	"fmt"
 	"reflect"
+	"sort"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/conversion"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 
 	"github.com/davecgh/go-spew/spew"
@@ -72,6 +75,8 @@ var standardResources = util.NewStringSet(
 	string(ResourceQuotas),
 	string(ResourceServices),
 	string(ResourceReplicationControllers),
+	string(ResourceSecrets),
+	string(ResourcePersistentVolumeClaims),
 	string(ResourceStorage))
 
 func IsStandardResourceName(str string) bool {
@@ -103,3 +108,37 @@ var standardFinalizers = util.NewStringSet(
 func IsStandardFinalizerName(str string) bool {
 	return standardFinalizers.Has(str)
 }
+
+// LabelSelectorAsSelector converts a set of exact match labels and a list of set-based
+// requirements into a single labels.Selector that matches only when all of them do.
+// An empty set and requirement list is equivalent to labels.Everything().
+func LabelSelectorAsSelector(set map[string]string, requirements []LabelSelectorRequirement) (labels.Selector, error) {
+	selector := labels.LabelSelector{}
+	for key, value := range set {
+		r, err := labels.NewRequirement(key, labels.EqualsOperator, util.NewStringSet(value))
+		if err != nil {
+			return nil, err
+		}
+		selector = append(selector, *r)
+	}
+	for _, req := range requirements {
+		var op labels.Operator
+		switch req.Operator {
+		case LabelSelectorOpIn:
+			op = labels.InOperator
+		case LabelSelectorOpNotIn:
+			op = labels.NotInOperator
+		case LabelSelectorOpExists:
+			op = labels.ExistsOperator
+		default:
+			return nil, fmt.Errorf("%q is not a valid label selector operator", req.Operator)
+		}
+		r, err := labels.NewRequirement(req.Key, op, util.NewStringSet(req.Values...))
+		if err != nil {
+			return nil, err
+		}
+		selector = append(selector, *r)
+	}
+	sort.Sort(labels.ByKey(selector))
+	return selector, nil
+}
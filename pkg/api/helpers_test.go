@@ -75,6 +75,8 @@ func TestIsStandardResource(t *testing.T) {
 	}{
 		{"cpu", true},
 		{"memory", true},
+		{"secrets", true},
+		{"persistentvolumeclaims", true},
 		{"disk", false},
 		{"blah", false},
 		{"x.y.z", false},
@@ -85,3 +87,49 @@ func TestIsStandardResource(t *testing.T) {
 		}
 	}
 }
+
+func TestLabelSelectorAsSelector(t *testing.T) {
+	testCases := []struct {
+		set          map[string]string
+		requirements []LabelSelectorRequirement
+		expected     string
+		expectErr    bool
+	}{
+		{expected: ""},
+		{set: map[string]string{"foo": "bar", "baz": "blah"}, expected: "baz=blah,foo=bar"},
+		{
+			set: map[string]string{"foo": "bar"},
+			requirements: []LabelSelectorRequirement{
+				{Key: "tier", Operator: LabelSelectorOpIn, Values: []string{"web", "api"}},
+				{Key: "env", Operator: LabelSelectorOpNotIn, Values: []string{"dev"}},
+				{Key: "canary", Operator: LabelSelectorOpExists},
+			},
+			expected: "canary,env notin (dev),foo=bar,tier in (api,web)",
+		},
+		{
+			requirements: []LabelSelectorRequirement{{Key: "tier", Operator: "Like", Values: []string{"web"}}},
+			expectErr:    true,
+		},
+		{
+			requirements: []LabelSelectorRequirement{{Key: "tier", Operator: LabelSelectorOpIn}},
+			expectErr:    true,
+		},
+		{set: map[string]string{"foo": "b@r"}, expectErr: true},
+	}
+	for i, tc := range testCases {
+		selector, err := LabelSelectorAsSelector(tc.set, tc.requirements)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("case[%d]: expected error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("case[%d]: unexpected error: %v", i, err)
+			continue
+		}
+		if e, a := tc.expected, selector.String(); e != a {
+			t.Errorf("case[%d]: expected %q, got %q", i, e, a)
+		}
+	}
+}
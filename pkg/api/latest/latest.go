@@ -88,6 +88,18 @@ func InterfacesFor(version string) (*meta.VersionInterfaces, error) {
 	}
 }
 
+// ProtobufCodecFor returns the Codec that encodes objects of the given version
+// in the protobuf wire format, or an error if the version has no protobuf
+// field numbers.
+func ProtobufCodecFor(version string) (runtime.Codec, error) {
+	switch version {
+	case "v1beta3":
+		return v1beta3.ProtobufCodec, nil
+	default:
+		return nil, fmt.Errorf("version %s can not be encoded as %s (valid: v1beta3)", version, runtime.ProtobufContentType)
+	}
+}
+
 func init() {
 	mapper := meta.NewDefaultRESTMapper(
 		Versions,
@@ -118,10 +130,12 @@ func init() {
 	// the list of kinds that are scoped at the root of the api hierarchy
 	// if a kind is not enumerated here, it is assumed to have a namespace scope
 	kindToRootScope := map[string]bool{
-		"Node":             true,
-		"Minion":           true,
-		"Namespace":        true,
-		"PersistentVolume": true,
+		"Node":               true,
+		"Minion":             true,
+		"Namespace":          true,
+		"PersistentVolume":   true,
+		"ClusterRole":        true,
+		"ClusterRoleBinding": true,
 	}
 
 	// these kinds should be excluded from the list of resources
@@ -57,6 +57,14 @@ func init() {
 		&PersistentVolumeList{},
 		&PersistentVolumeClaim{},
 		&PersistentVolumeClaimList{},
+		&Role{},
+		&RoleList{},
+		&RoleBinding{},
+		&RoleBindingList{},
+		&ClusterRole{},
+		&ClusterRoleList{},
+		&ClusterRoleBinding{},
+		&ClusterRoleBindingList{},
 	)
 	// Legacy names are supported
 	Scheme.AddKnownTypeWithName("", "Minion", &Node{})
@@ -95,3 +103,11 @@ func (*PersistentVolume) IsAnAPIObject()          {}
 func (*PersistentVolumeList) IsAnAPIObject()      {}
 func (*PersistentVolumeClaim) IsAnAPIObject()     {}
 func (*PersistentVolumeClaimList) IsAnAPIObject() {}
+func (*Role) IsAnAPIObject()                      {}
+func (*RoleList) IsAnAPIObject()                  {}
+func (*RoleBinding) IsAnAPIObject()               {}
+func (*RoleBindingList) IsAnAPIObject()           {}
+func (*ClusterRole) IsAnAPIObject()               {}
+func (*ClusterRoleList) IsAnAPIObject()           {}
+func (*ClusterRoleBinding) IsAnAPIObject()        {}
+func (*ClusterRoleBindingList) IsAnAPIObject()    {}
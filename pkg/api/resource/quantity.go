@@ -311,6 +311,23 @@ func (q *Quantity) UnmarshalJSON(value []byte) error {
 	return nil
 }
 
+// MarshalProtobuf implements the protobuf.Marshaler interface. The Quantity is
+// written as the string of its JSON.
+func (q Quantity) MarshalProtobuf() ([]byte, error) {
+	return []byte(q.String()), nil
+}
+
+// UnmarshalProtobuf implements the protobuf.Unmarshaler interface.
+func (q *Quantity) UnmarshalProtobuf(value []byte) error {
+	parsed, err := ParseQuantity(string(value))
+	if err != nil {
+		return err
+	}
+	// This copy is safe because parsed will not be referred to again.
+	*q = *parsed
+	return nil
+}
+
 // NewQuantity returns a new Quantity representing the given
 // value in the given format.
 func NewQuantity(value int64, format Format) *Quantity {
@@ -384,6 +384,25 @@ func TestJSON(t *testing.T) {
 	}
 }
 
+func TestProtobuf(t *testing.T) {
+	for i := 0; i < 500; i++ {
+		q := &Quantity{}
+		fuzzer.Fuzz(q)
+		b, err := q.MarshalProtobuf()
+		if err != nil {
+			t.Errorf("error encoding %v", q)
+		}
+		q2 := &Quantity{}
+		err = q2.UnmarshalProtobuf(b)
+		if err != nil {
+			t.Errorf("%v: error decoding %v", q, string(b))
+		}
+		if q2.Amount.Cmp(q.Amount) != 0 {
+			t.Errorf("Expected equal: %v, %v (protobuf was '%v')", q, q2, string(b))
+		}
+	}
+}
+
 func TestMilliNewSet(t *testing.T) {
 	table := []struct {
 		value  int64
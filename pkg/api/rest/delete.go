@@ -17,8 +17,12 @@ limitations under the License.
 package rest
 
 import (
+	"time"
+
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 )
 
 // RESTDeleteStrategy defines deletion behavior on an object that follows Kubernetes
@@ -35,17 +39,44 @@ type RESTDeleteStrategy interface {
 // should be gracefully deleted, if gracefulPending is set the object has already been gracefully deleted
 // (and the provided grace period is longer than the time to deletion), and an error is returned if the
 // condition cannot be checked or the gracePeriodSeconds is invalid. The options argument may be updated with
-// default values if graceful is true.
+// default values if graceful is true. When graceful is true the DeletionTimestamp and DeletionGracePeriodSeconds
+// of the object are set and the object should be persisted rather than removed.
 func BeforeDelete(strategy RESTDeleteStrategy, ctx api.Context, obj runtime.Object, options *api.DeleteOptions) (graceful, gracefulPending bool, err error) {
 	if strategy == nil {
 		return false, false, nil
 	}
-	_, _, kerr := objectMetaAndKind(strategy, obj)
+	objectMeta, _, kerr := objectMetaAndKind(strategy, obj)
 	if kerr != nil {
 		return false, false, kerr
 	}
-	if !strategy.CheckGracefulDelete(obj, options) {
+	if options.GracePeriodSeconds != nil && *options.GracePeriodSeconds < 0 {
+		return false, false, errors.NewBadRequest("gracePeriodSeconds must be a non-negative integer")
+	}
+
+	// if the object is already being deleted, the grace period may only be shortened
+	if objectMeta.DeletionTimestamp != nil {
+		// the object was marked for deletion without recording a grace period, delete it now
+		if objectMeta.DeletionGracePeriodSeconds == nil {
+			return false, false, nil
+		}
+		if options.GracePeriodSeconds != nil && *options.GracePeriodSeconds < *objectMeta.DeletionGracePeriodSeconds {
+			setDeletionTimestamp(objectMeta, *options.GracePeriodSeconds)
+			return true, false, nil
+		}
+		options.GracePeriodSeconds = objectMeta.DeletionGracePeriodSeconds
+		return false, true, nil
+	}
+
+	if !strategy.CheckGracefulDelete(obj, options) || options.GracePeriodSeconds == nil {
 		return false, false, nil
 	}
+	setDeletionTimestamp(objectMeta, *options.GracePeriodSeconds)
 	return true, false, nil
 }
+
+// setDeletionTimestamp records on objectMeta that the object will be deleted after gracePeriodSeconds.
+func setDeletionTimestamp(objectMeta *api.ObjectMeta, gracePeriodSeconds int64) {
+	now := util.NewTime(util.Now().Add(time.Duration(gracePeriodSeconds) * time.Second))
+	objectMeta.DeletionTimestamp = &now
+	objectMeta.DeletionGracePeriodSeconds = &gracePeriodSeconds
+}
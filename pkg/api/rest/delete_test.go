@@ -0,0 +1,119 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rest
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+)
+
+// testGracefulStrategy allows graceful deletion of every object with a default grace period.
+type testGracefulStrategy struct {
+	runtime.ObjectTyper
+	defaultGracePeriod int64
+}
+
+func (s testGracefulStrategy) CheckGracefulDelete(obj runtime.Object, options *api.DeleteOptions) bool {
+	if options.GracePeriodSeconds == nil {
+		period := s.defaultGracePeriod
+		options.GracePeriodSeconds = &period
+	}
+	return true
+}
+
+func TestBeforeDelete(t *testing.T) {
+	strategy := testGracefulStrategy{api.Scheme, 30}
+	deleting := util.Now()
+	tests := []struct {
+		meta            api.ObjectMeta
+		options         *api.DeleteOptions
+		expectGraceful  bool
+		expectPending   bool
+		expectErr       bool
+		expectRemaining int64
+	}{
+		{
+			meta:            api.ObjectMeta{Name: "foo"},
+			options:         &api.DeleteOptions{},
+			expectGraceful:  true,
+			expectRemaining: 30,
+		},
+		{
+			meta:            api.ObjectMeta{Name: "foo"},
+			options:         api.NewDeleteOptions(10),
+			expectGraceful:  true,
+			expectRemaining: 10,
+		},
+		{
+			meta:      api.ObjectMeta{Name: "foo"},
+			options:   api.NewDeleteOptions(-1),
+			expectErr: true,
+		},
+		{
+			// already being deleted, a longer grace period is ignored
+			meta:            api.ObjectMeta{Name: "foo", DeletionTimestamp: &deleting, DeletionGracePeriodSeconds: int64Ptr(10)},
+			options:         api.NewDeleteOptions(20),
+			expectPending:   true,
+			expectRemaining: 10,
+		},
+		{
+			// already being deleted, a shorter grace period is honored
+			meta:            api.ObjectMeta{Name: "foo", DeletionTimestamp: &deleting, DeletionGracePeriodSeconds: int64Ptr(10)},
+			options:         api.NewDeleteOptions(0),
+			expectGraceful:  true,
+			expectRemaining: 0,
+		},
+		{
+			// marked for deletion without a grace period, delete immediately
+			meta:    api.ObjectMeta{Name: "foo", DeletionTimestamp: &deleting},
+			options: &api.DeleteOptions{},
+		},
+	}
+	for i, test := range tests {
+		pod := &api.Pod{ObjectMeta: test.meta}
+		graceful, pending, err := BeforeDelete(strategy, api.NewDefaultContext(), pod, test.options)
+		if test.expectErr {
+			if err == nil {
+				t.Errorf("%d: expected error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%d: unexpected error: %v", i, err)
+			continue
+		}
+		if graceful != test.expectGraceful || pending != test.expectPending {
+			t.Errorf("%d: expected graceful=%t pending=%t, got graceful=%t pending=%t", i, test.expectGraceful, test.expectPending, graceful, pending)
+		}
+		if !graceful && !pending {
+			continue
+		}
+		if *test.options.GracePeriodSeconds != test.expectRemaining {
+			t.Errorf("%d: expected grace period %d, got %d", i, test.expectRemaining, *test.options.GracePeriodSeconds)
+		}
+		if pod.DeletionTimestamp == nil || pod.DeletionGracePeriodSeconds == nil || *pod.DeletionGracePeriodSeconds != test.expectRemaining {
+			t.Errorf("%d: unexpected deletion fields: %#v", i, pod.ObjectMeta)
+		}
+	}
+}
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
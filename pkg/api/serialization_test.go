@@ -32,6 +32,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/v1beta3"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/protobuf"
 	"github.com/davecgh/go-spew/spew"
 
 	flag "github.com/spf13/pflag"
@@ -92,6 +93,7 @@ func roundTripSame(t *testing.T, item runtime.Object) {
 	roundTrip(t, v1beta2.Codec, item)
 	fuzzInternalObject(t, "v1beta3", item, seed)
 	roundTrip(t, v1beta3.Codec, item)
+	roundTrip(t, v1beta3.ProtobufCodec, item)
 }
 
 func roundTripAll(t *testing.T, item runtime.Object) {
@@ -99,6 +101,7 @@ func roundTripAll(t *testing.T, item runtime.Object) {
 	roundTrip(t, v1beta1.Codec, fuzzInternalObject(t, "v1beta1", item, seed))
 	roundTrip(t, v1beta2.Codec, fuzzInternalObject(t, "v1beta2", item, seed))
 	roundTrip(t, v1beta3.Codec, fuzzInternalObject(t, "v1beta3", item, seed))
+	roundTrip(t, v1beta3.ProtobufCodec, fuzzInternalObject(t, "v1beta3", item, seed))
 }
 
 // For debugging problems
@@ -180,6 +183,31 @@ func TestEncode_Ptr(t *testing.T) {
 	}
 }
 
+func TestProtobufKeepsEmptyLists(t *testing.T) {
+	pod := &api.Pod{
+		Spec: api.PodSpec{
+			Volumes:       []api.Volume{},
+			RestartPolicy: api.RestartPolicyAlways,
+			DNSPolicy:     api.DNSClusterFirst,
+		},
+	}
+	data, err := v1beta3.ProtobufCodec.Encode(pod)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	obj, err := v1beta3.ProtobufCodec.Decode(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	spec := obj.(*api.Pod).Spec
+	if spec.Volumes == nil || len(spec.Volumes) != 0 {
+		t.Errorf("expected empty volumes, got %#v", spec.Volumes)
+	}
+	if spec.Containers != nil {
+		t.Errorf("expected nil containers, got %#v", spec.Containers)
+	}
+}
+
 func TestBadJSONRejection(t *testing.T) {
 	badJSONMissingKind := []byte(`{ }`)
 	if _, err := latest.Codec.Decode(badJSONMissingKind); err == nil {
@@ -216,6 +244,26 @@ func BenchmarkEncodeJSON(b *testing.B) {
 	}
 }
 
+// BenchmarkEncodeV1beta3 measures encoding to v1beta3 JSON, to compare with BenchmarkEncodeProtobuf
+func BenchmarkEncodeV1beta3(b *testing.B) {
+	pod := api.Pod{}
+	apiObjectFuzzer := apitesting.FuzzerFor(nil, "", rand.NewSource(benchmarkSeed))
+	apiObjectFuzzer.Fuzz(&pod)
+	for i := 0; i < b.N; i++ {
+		v1beta3.Codec.Encode(&pod)
+	}
+}
+
+// BenchmarkEncodeProtobuf measures encoding to v1beta3 protobuf, to compare with BenchmarkEncodeV1beta3
+func BenchmarkEncodeProtobuf(b *testing.B) {
+	pod := api.Pod{}
+	apiObjectFuzzer := apitesting.FuzzerFor(nil, "", rand.NewSource(benchmarkSeed))
+	apiObjectFuzzer.Fuzz(&pod)
+	for i := 0; i < b.N; i++ {
+		v1beta3.ProtobufCodec.Encode(&pod)
+	}
+}
+
 func BenchmarkDecode(b *testing.B) {
 	pod := api.Pod{}
 	apiObjectFuzzer := apitesting.FuzzerFor(nil, "", rand.NewSource(benchmarkSeed))
@@ -248,3 +296,74 @@ func BenchmarkDecodeJSON(b *testing.B) {
 		json.Unmarshal(data, &obj)
 	}
 }
+
+// BenchmarkDecodeV1beta3 measures decoding from v1beta3 JSON, to compare with BenchmarkDecodeProtobuf
+func BenchmarkDecodeV1beta3(b *testing.B) {
+	pod := api.Pod{}
+	apiObjectFuzzer := apitesting.FuzzerFor(nil, "", rand.NewSource(benchmarkSeed))
+	apiObjectFuzzer.Fuzz(&pod)
+	data, _ := v1beta3.Codec.Encode(&pod)
+	for i := 0; i < b.N; i++ {
+		v1beta3.Codec.Decode(data)
+	}
+}
+
+// BenchmarkDecodeProtobuf measures decoding from v1beta3 protobuf, to compare with BenchmarkDecodeV1beta3
+func BenchmarkDecodeProtobuf(b *testing.B) {
+	pod := api.Pod{}
+	apiObjectFuzzer := apitesting.FuzzerFor(nil, "", rand.NewSource(benchmarkSeed))
+	apiObjectFuzzer.Fuzz(&pod)
+	data, _ := v1beta3.ProtobufCodec.Encode(&pod)
+	for i := 0; i < b.N; i++ {
+		v1beta3.ProtobufCodec.Decode(data)
+	}
+}
+
+// fuzzV1beta3Pod returns the benchmark pod converted to v1beta3, to measure
+// serialization without conversion.
+func fuzzV1beta3Pod(b *testing.B) *v1beta3.Pod {
+	pod := api.Pod{}
+	apiObjectFuzzer := apitesting.FuzzerFor(nil, "", rand.NewSource(benchmarkSeed))
+	apiObjectFuzzer.Fuzz(&pod)
+	out := &v1beta3.Pod{}
+	if err := api.Scheme.Convert(&pod, out); err != nil {
+		b.Fatalf("unexpected error: %v", err)
+	}
+	return out
+}
+
+// BenchmarkMarshalV1beta3JSON provides a baseline for BenchmarkMarshalV1beta3Protobuf
+func BenchmarkMarshalV1beta3JSON(b *testing.B) {
+	pod := fuzzV1beta3Pod(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		json.Marshal(pod)
+	}
+}
+
+// BenchmarkMarshalV1beta3Protobuf measures the protobuf encoding of a v1beta3 pod, without conversion
+func BenchmarkMarshalV1beta3Protobuf(b *testing.B) {
+	pod := fuzzV1beta3Pod(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		protobuf.Marshal(pod)
+	}
+}
+
+// BenchmarkUnmarshalV1beta3JSON provides a baseline for BenchmarkUnmarshalV1beta3Protobuf
+func BenchmarkUnmarshalV1beta3JSON(b *testing.B) {
+	data, _ := json.Marshal(fuzzV1beta3Pod(b))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		json.Unmarshal(data, &v1beta3.Pod{})
+	}
+}
+
+// BenchmarkUnmarshalV1beta3Protobuf measures the protobuf decoding of a v1beta3 pod, without conversion
+func BenchmarkUnmarshalV1beta3Protobuf(b *testing.B) {
+	data, _ := protobuf.Marshal(fuzzV1beta3Pod(b))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		protobuf.Unmarshal(data, &v1beta3.Pod{})
+	}
+}
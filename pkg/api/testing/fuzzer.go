@@ -204,22 +204,26 @@ func FuzzerFor(t *testing.T, version string, src rand.Source) *fuzz.Fuzzer {
 		func(s *api.NamespaceStatus, c fuzz.Continue) {
 			s.Phase = api.NamespaceActive
 		},
-		func(ep *api.Endpoint, c fuzz.Continue) {
+		func(ep *api.EndpointAddress, c fuzz.Continue) {
+			c.FuzzNoCustom(ep) // fuzz self without calling this function again
 			// TODO: If our API used a particular type for IP fields we could just catch that here.
 			ep.IP = fmt.Sprintf("%d.%d.%d.%d", c.Rand.Intn(256), c.Rand.Intn(256), c.Rand.Intn(256), c.Rand.Intn(256))
+		},
+		func(ep *api.EndpointPort, c fuzz.Continue) {
+			c.FuzzNoCustom(ep) // fuzz self without calling this function again
 			ep.Port = c.Rand.Intn(65536)
 		},
 		func(http *api.HTTPGetAction, c fuzz.Continue) {
 			c.FuzzNoCustom(http)        // fuzz self without calling this function again
 			http.Path = "/" + http.Path // can't be blank
 		},
-		func(ss *api.ServiceSpec, c fuzz.Continue) {
-			c.FuzzNoCustom(ss) // fuzz self without calling this function again
-			switch ss.TargetPort.Kind {
+		func(sp *api.ServicePort, c fuzz.Continue) {
+			c.FuzzNoCustom(sp) // fuzz self without calling this function again
+			switch sp.TargetPort.Kind {
 			case util.IntstrInt:
-				ss.TargetPort.IntVal = 1 + ss.TargetPort.IntVal%65535 // non-zero
+				sp.TargetPort.IntVal = 1 + sp.TargetPort.IntVal%65535 // non-zero
 			case util.IntstrString:
-				ss.TargetPort.StrVal = "x" + ss.TargetPort.StrVal // non-empty
+				sp.TargetPort.StrVal = "x" + sp.TargetPort.StrVal // non-empty
 			}
 		},
 	)
@@ -131,6 +131,11 @@ type ObjectMeta struct {
 	// will send a hard termination signal to the container.
 	DeletionTimestamp *util.Time `json:"deletionTimestamp,omitempty"`
 
+	// DeletionGracePeriodSeconds is the number of seconds allowed for this object to
+	// terminate gracefully before it is removed from the system. It is only set when
+	// DeletionTimestamp is also set, and may only be shortened. Read-only.
+	DeletionGracePeriodSeconds *int64 `json:"deletionGracePeriodSeconds,omitempty"`
+
 	// Labels are key value pairs that may be used to scope and select individual resources.
 	// Label keys are of the form:
 	//     label-key ::= prefixed-name | name
@@ -193,6 +198,8 @@ type VolumeSource struct {
 	Secret *SecretVolumeSource `json:"secret"`
 	// NFS represents an NFS mount on the host that shares a pod's lifetime
 	NFS *NFSVolumeSource `json:"nfs"`
+	// DownwardAPI represents metadata about the pod that should populate this volume.
+	DownwardAPI *DownwardAPIVolumeSource `json:"downwardAPI"`
 }
 
 // Similar to VolumeSource but meant for the administrator who creates PVs.
@@ -220,7 +227,7 @@ type PersistentVolume struct {
 
 type PersistentVolumeSpec struct {
 	// Resources represents the actual resources of the volume
-	Capacity ResourceList `json:"capacity`
+	Capacity ResourceList `json:"capacity"`
 	// Source represents the location and type of a volume to mount.
 	// AccessModeTypes are inferred from the Source.
 	PersistentVolumeSource `json:",inline"`
@@ -270,7 +277,7 @@ type PersistentVolumeClaimStatus struct {
 	// Phase represents the current phase of PersistentVolumeClaim
 	Phase PersistentVolumeClaimPhase `json:"phase,omitempty"`
 	// AccessModes contains all ways the volume backing the PVC can be mounted
-	AccessModes []AccessModeType `json:"accessModes,omitempty`
+	AccessModes []AccessModeType `json:"accessModes,omitempty"`
 	// Represents the actual resources of the underlying volume
 	Capacity ResourceList `json:"capacity,omitempty"`
 	// VolumeRef is a reference to the PersistentVolume bound to the PersistentVolumeClaim
@@ -383,6 +390,23 @@ type SecretVolumeSource struct {
 	SecretName string `json:"secretName"`
 }
 
+// DownwardAPIVolumeSource represents a volume containing downward API info.
+type DownwardAPIVolumeSource struct {
+	// Items is a list of downward API volume files.
+	Items []DownwardAPIVolumeFile `json:"items,omitempty"`
+}
+
+// DownwardAPIVolumeFile represents a single file containing information from
+// the downward API.
+type DownwardAPIVolumeFile struct {
+	// Required: the relative path name of the file to be created. Must not be
+	// absolute or contain the '..' path element.
+	Path string `json:"path"`
+	// Required: selects a field of the pod. Only metadata.name,
+	// metadata.namespace, metadata.labels and metadata.annotations are supported.
+	FieldRef ObjectFieldSelector `json:"fieldRef"`
+}
+
 // NFSVolumeSource represents an NFS Mount that lasts the lifetime of a pod
 type NFSVolumeSource struct {
 	// Server is the hostname or IP address of the NFS server
@@ -428,6 +452,32 @@ type EnvVar struct {
 	Name string `json:"name"`
 	// Optional: defaults to "".
 	Value string `json:"value,omitempty"`
+	// Optional: specifies a source the value of this var should come from.
+	// Cannot be used if Value is not empty.
+	ValueFrom *EnvVarSource `json:"valueFrom,omitempty"`
+}
+
+// EnvVarSource represents a source for the value of an EnvVar.
+type EnvVarSource struct {
+	// Selects a key of a secret in the pod's namespace.
+	SecretKeyRef *SecretKeySelector `json:"secretKeyRef,omitempty"`
+	// Selects a field of the pod. Only metadata.name, metadata.namespace and
+	// status.podIP are supported.
+	FieldRef *ObjectFieldSelector `json:"fieldRef,omitempty"`
+}
+
+// SecretKeySelector selects a key of a Secret.
+type SecretKeySelector struct {
+	// Required: the name of the secret in the pod's namespace.
+	Name string `json:"name"`
+	// Required: the key of the secret to select from.
+	Key string `json:"key"`
+}
+
+// ObjectFieldSelector selects a field of an object.
+type ObjectFieldSelector struct {
+	// Required: path of the field to select, e.g. "metadata.name".
+	FieldPath string `json:"fieldPath"`
 }
 
 // HTTPGetAction describes an action based on HTTP Get requests.
@@ -463,6 +513,14 @@ type Probe struct {
 	InitialDelaySeconds int64 `json:"initialDelaySeconds,omitempty"`
 	// Length of time before health checking times out.  In seconds.
 	TimeoutSeconds int64 `json:"timeoutSeconds,omitempty"`
+	// How often to perform the probe.  In seconds.  Defaults to 10 seconds if unset.
+	PeriodSeconds int64 `json:"periodSeconds,omitempty"`
+	// Minimum consecutive successes for the probe to be considered successful after having failed.
+	// Defaults to 1 if unset.
+	SuccessThreshold int `json:"successThreshold,omitempty"`
+	// Minimum consecutive failures for the probe to be considered failed after having succeeded.
+	// Defaults to 3 if unset.
+	FailureThreshold int `json:"failureThreshold,omitempty"`
 }
 
 // PullPolicy describes a policy for if/when to pull a container image
@@ -507,11 +565,11 @@ type Container struct {
 	Command []string `json:"command,omitempty"`
 	// Optional: Defaults to Docker's default.
 	WorkingDir string          `json:"workingDir,omitempty"`
-	Ports      []ContainerPort `json:"ports,omitempty"`
-	Env        []EnvVar        `json:"env,omitempty"`
+	Ports      []ContainerPort `json:"ports,omitempty" patchStrategy:"merge" patchMergeKey:"containerPort"`
+	Env        []EnvVar        `json:"env,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
 	// Compute resource requirements.
 	Resources      ResourceRequirements `json:"resources,omitempty"`
-	VolumeMounts   []VolumeMount        `json:"volumeMounts,omitempty"`
+	VolumeMounts   []VolumeMount        `json:"volumeMounts,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
 	LivenessProbe  *Probe               `json:"livenessProbe,omitempty"`
 	ReadinessProbe *Probe               `json:"readinessProbe,omitempty"`
 	Lifecycle      *Lifecycle           `json:"lifecycle,omitempty"`
@@ -691,16 +749,51 @@ const (
 	DNSDefault DNSPolicy = "Default"
 )
 
+// LabelSelectorOperator is the relationship between a label key and the set of values
+// in a LabelSelectorRequirement.
+type LabelSelectorOperator string
+
+const (
+	LabelSelectorOpIn     LabelSelectorOperator = "In"
+	LabelSelectorOpNotIn  LabelSelectorOperator = "NotIn"
+	LabelSelectorOpExists LabelSelectorOperator = "Exists"
+)
+
+// LabelSelectorRequirement is a set-based label selector requirement. It is ANDed with
+// any other requirements and exact match labels of the selector it belongs to.
+type LabelSelectorRequirement struct {
+	// Key is the label key that the requirement applies to.
+	Key string `json:"key"`
+	// Operator represents the key's relationship to the set of values.
+	Operator LabelSelectorOperator `json:"operator"`
+	// Values is the set of values for the In and NotIn operators. It must be empty
+	// for the Exists operator.
+	Values []string `json:"values,omitempty"`
+}
+
+// DefaultTerminationGracePeriodSeconds is the grace period given to a pod to terminate
+// when none is specified in its spec.
+const DefaultTerminationGracePeriodSeconds = 30
+
 // PodSpec is a description of a pod
 type PodSpec struct {
-	Volumes []Volume `json:"volumes"`
+	Volumes []Volume `json:"volumes" patchStrategy:"merge" patchMergeKey:"name"`
 	// Required: there must be at least one container in a pod.
-	Containers    []Container   `json:"containers"`
+	Containers    []Container   `json:"containers" patchStrategy:"merge" patchMergeKey:"name"`
 	RestartPolicy RestartPolicy `json:"restartPolicy,omitempty"`
+	// Optional duration in seconds the pod needs to terminate gracefully. May be decreased in delete request.
+	// Value must be non-negative integer. The value zero indicates delete immediately.
+	// If this value is nil, the default grace period will be used instead.
+	// The grace period is the duration in seconds after the processes running in the pod are sent
+	// a termination signal and the time when the processes are forcibly halted with a kill signal.
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
 	// Required: Set DNS policy.
 	DNSPolicy DNSPolicy `json:"dnsPolicy,omitempty"`
 	// NodeSelector is a selector which must be true for the pod to fit on a node
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// NodeSelectorRequirements are set-based requirements which must also be true for the
+	// pod to fit on a node.
+	NodeSelectorRequirements []LabelSelectorRequirement `json:"nodeSelectorRequirements,omitempty"`
 
 	// Host is a request to schedule this pod onto a specific host.  If it is non-empty,
 	// the the scheduler simply schedules this pod onto that host, assuming that it fits
@@ -710,6 +803,10 @@ type PodSpec struct {
 	// used must be specified.
 	// Optional: Default to false.
 	HostNetwork bool `json:"hostNetwork,omitempty"`
+	// ImagePullSecrets is a list of references to secrets of type
+	// SecretTypeDockercfg in the pod's namespace, whose credentials are used
+	// to pull the images of the pod's containers.
+	ImagePullSecrets []LocalObjectReference `json:"imagePullSecrets,omitempty"`
 }
 
 // PodStatus represents information about the status of a pod. Status may trail the actual
@@ -793,6 +890,10 @@ type ReplicationControllerSpec struct {
 	// Selector is a label query over pods that should match the Replicas count.
 	Selector map[string]string `json:"selector"`
 
+	// SelectorRequirements are set-based requirements that pods must also satisfy
+	// to match the Replicas count.
+	SelectorRequirements []LabelSelectorRequirement `json:"selectorRequirements,omitempty"`
+
 	// TemplateRef is a reference to an object that describes the pod that will be created if
 	// insufficient replicas are detected. This reference is ignored if a Template is set.
 	// Must be set before converting to a v1beta3 API object
@@ -863,18 +964,18 @@ type ServiceStatus struct{}
 
 // ServiceSpec describes the attributes that a user creates on a service
 type ServiceSpec struct {
-	// Port is the TCP or UDP port that will be made available to each pod for connecting to the pods
-	// proxied by this service.
-	Port int `json:"port"`
-
-	// Required: Supports "TCP" and "UDP".
-	Protocol Protocol `json:"protocol,omitempty"`
+	// Required: The list of ports that are exposed by this service.
+	Ports []ServicePort `json:"ports"`
 
 	// This service will route traffic to pods having labels matching this selector. If empty or not present,
 	// the service is assumed to have endpoints set by an external process and Kubernetes will not modify
 	// those endpoints.
 	Selector map[string]string `json:"selector"`
 
+	// SelectorRequirements are set-based requirements that pods must also satisfy
+	// to receive traffic for this service.
+	SelectorRequirements []LabelSelectorRequirement `json:"selectorRequirements,omitempty"`
+
 	// PortalIP is usually assigned by the master.  If specified by the user
 	// we will try to respect it or else fail the request.  This field can
 	// not be changed by updates.
@@ -891,17 +992,29 @@ type ServiceSpec struct {
 	// For hostnames, the user will use a CNAME record (instead of using an A record with the IP)
 	PublicIPs []string `json:"publicIPs,omitempty"`
 
-	// TargetPort is the name or number of the port on the container to direct traffic to.
-	// This is useful if the containers the service points to have multiple open ports.
-	// Optional: If unspecified, the first port on the container will be used.
-	// As of v1beta3 this field will become required in the internal API,
-	// and the versioned APIs must provide a default value.
-	TargetPort util.IntOrString `json:"targetPort,omitempty"`
-
 	// Required: Supports "ClientIP" and "None".  Used to maintain session affinity.
 	SessionAffinity AffinityType `json:"sessionAffinity,omitempty"`
 }
 
+// ServicePort is a single port exposed by a service.
+type ServicePort struct {
+	// Optional if only one ServicePort is defined on this service: The
+	// name of this port within the service.  This must be a DNS_LABEL.
+	// All ports within a ServiceSpec must have unique names.  This maps to
+	// the 'Name' field in EndpointPort objects.
+	Name string `json:"name"`
+
+	// Required: Supports "TCP" and "UDP".
+	Protocol Protocol `json:"protocol"`
+
+	// Required: The port that will be exposed on the service.
+	Port int `json:"port"`
+
+	// Required: The name or number of the port on the container to direct
+	// traffic to.  The versioned APIs must provide a default value.
+	TargetPort util.IntOrString `json:"targetPort"`
+}
+
 // Service is a named abstraction of software service (for example, mysql) consisting of local port
 // (for example 3306) that the proxy listens on, and the selector that determines which pods
 // will answer requests sent through the proxy.
@@ -917,30 +1030,62 @@ type Service struct {
 }
 
 // Endpoints is a collection of endpoints that implement the actual service, for example:
-// Name: "mysql", Endpoints: [{"ip": "10.10.1.1", "port": 1909}, {"ip": "10.10.2.2", "port": 8834}]
+// Name: "mysql", Subsets: [
+//   {
+//     Addresses: [{"ip": "10.10.1.1"}, {"ip": "10.10.2.2"}],
+//     Ports: [{"name": "mysql", "port": 3306, "protocol": "TCP"}]
+//   },
+//   {
+//     Addresses: [{"ip": "10.10.3.3"}],
+//     Ports: [{"name": "mysql", "port": 3306, "protocol": "TCP"}, {"name": "metrics", "port": 9102, "protocol": "TCP"}]
+//   }
+// ]
 type Endpoints struct {
 	TypeMeta   `json:",inline"`
 	ObjectMeta `json:"metadata,omitempty"`
 
-	// Optional: The IP protocol for these endpoints. Supports "TCP" and
-	// "UDP".  Defaults to "TCP".
-	Protocol  Protocol   `json:"protocol,omitempty"`
-	Endpoints []Endpoint `json:"endpoints,omitempty"`
+	// The set of all endpoints is the union of all subsets.
+	Subsets []EndpointSubset `json:"subsets"`
+}
+
+// EndpointSubset is a group of addresses with a common set of ports.  The
+// expanded set of endpoints is the Cartesian product of Addresses x Ports.
+// For example, given:
+//   {
+//     Addresses: [{"ip": "10.10.1.1"}, {"ip": "10.10.2.2"}],
+//     Ports:     [{"name": "a", "port": 8675}, {"name": "b", "port": 309}]
+//   }
+// The resulting set of endpoints can be viewed as:
+//     a: [ 10.10.1.1:8675, 10.10.2.2:8675 ],
+//     b: [ 10.10.1.1:309, 10.10.2.2:309 ]
+type EndpointSubset struct {
+	Addresses []EndpointAddress `json:"addresses,omitempty"`
+	Ports     []EndpointPort    `json:"ports,omitempty"`
 }
 
-// Endpoint is a single IP endpoint of a service.
-type Endpoint struct {
-	// Required: The IP of this endpoint.
+// EndpointAddress is a tuple that describes single IP address.
+type EndpointAddress struct {
+	// The IP of this endpoint.
 	// TODO: This should allow hostname or IP, see #4447.
 	IP string `json:"ip"`
 
-	// Required: The destination port to access.
-	Port int `json:"port"`
-
 	// Optional: The kubernetes object related to the entry point.
 	TargetRef *ObjectReference `json:"targetRef,omitempty"`
 }
 
+// EndpointPort is a tuple that describes a single port.
+type EndpointPort struct {
+	// The name of this port (corresponds to ServicePort.Name).  Optional
+	// if only one port is defined.  Must be a DNS_LABEL.
+	Name string `json:"name,omitempty"`
+
+	// The port number.
+	Port int `json:"port"`
+
+	// The IP protocol for this port.
+	Protocol Protocol `json:"protocol"`
+}
+
 // EndpointsList is a list of endpoints.
 type EndpointsList struct {
 	TypeMeta `json:",inline"`
@@ -980,7 +1125,7 @@ type NodeStatus struct {
 	// NodePhase is the current lifecycle phase of the node.
 	Phase NodePhase `json:"phase,omitempty"`
 	// Conditions is an array of current node conditions.
-	Conditions []NodeCondition `json:"conditions,omitempty"`
+	Conditions []NodeCondition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
 	// Queried from cloud provider, if available.
 	Addresses []NodeAddress `json:"addresses,omitempty"`
 	// NodeSystemInfo is a set of ids/uuids to uniquely identify the node
@@ -1020,6 +1165,12 @@ const (
 	NodeReady NodeConditionType = "Ready"
 	// NodeSchedulable means the node is ready to accept new pods.
 	NodeSchedulable NodeConditionType = "Schedulable"
+	// NodeMemoryPressure means the memory available on the node is below the kubelet's
+	// eviction threshold, and pods are being evicted from it.
+	NodeMemoryPressure NodeConditionType = "MemoryPressure"
+	// NodeDiskPressure means the disk space available on the node is below the kubelet's
+	// eviction threshold, and pods are being evicted from it.
+	NodeDiskPressure NodeConditionType = "DiskPressure"
 )
 
 type NodeCondition struct {
@@ -1108,6 +1259,18 @@ const (
 type NamespaceStatus struct {
 	// Phase is the current lifecycle phase of the namespace.
 	Phase NamespacePhase `json:"phase,omitempty"`
+	// Remaining lists the resources that still have objects in a terminating namespace.
+	Remaining []NamespaceRemainingResource `json:"remaining,omitempty"`
+	// Message describes the progress of the deletion of a terminating namespace.
+	Message string `json:"message,omitempty"`
+}
+
+// NamespaceRemainingResource is the number of objects of a resource left in a namespace.
+type NamespaceRemainingResource struct {
+	// Resource is the name of the resource, e.g. pods.
+	Resource string `json:"resource"`
+	// Count is the number of objects of the resource found in the namespace.
+	Count int `json:"count"`
 }
 
 type NamespacePhase string
@@ -1371,6 +1534,12 @@ type ObjectReference struct {
 	FieldPath string `json:"fieldPath,omitempty"`
 }
 
+// LocalObjectReference contains enough information to locate the referenced
+// object inside the same namespace.
+type LocalObjectReference struct {
+	Name string `json:"name,omitempty"`
+}
+
 type EventSource struct {
 	// Component from which the event is generated.
 	Component string `json:"component,omitempty"`
@@ -1432,8 +1601,8 @@ type ContainerManifest struct {
 	// with the API refactoring. It is required for now to determine the instance
 	// of a Pod.
 	UUID          types.UID     `json:"uuid,omitempty"`
-	Volumes       []Volume      `json:"volumes"`
-	Containers    []Container   `json:"containers"`
+	Volumes       []Volume      `json:"volumes" patchStrategy:"merge" patchMergeKey:"name"`
+	Containers    []Container   `json:"containers" patchStrategy:"merge" patchMergeKey:"name"`
 	RestartPolicy RestartPolicy `json:"restartPolicy,omitempty"`
 	// Required: Set DNS policy.
 	DNSPolicy DNSPolicy `json:"dnsPolicy"`
@@ -1510,6 +1679,10 @@ const (
 	ResourceReplicationControllers ResourceName = "replicationcontrollers"
 	// ResourceQuotas, number
 	ResourceQuotas ResourceName = "resourcequotas"
+	// Secrets, number
+	ResourceSecrets ResourceName = "secrets"
+	// PersistentVolumeClaims, number
+	ResourcePersistentVolumeClaims ResourceName = "persistentvolumeclaims"
 )
 
 // ResourceQuotaSpec defines the desired hard limits to enforce for Quota
@@ -1568,6 +1741,30 @@ type SecretType string
 
 const (
 	SecretTypeOpaque SecretType = "Opaque" // Default; arbitrary user-defined data
+
+	// SecretTypeDockercfg contains a dockercfg file that follows the same
+	// format rules as ~/.dockercfg.
+	//
+	// Required fields:
+	// - Secret.Data[".dockercfg"] - a serialized ~/.dockercfg file
+	SecretTypeDockercfg SecretType = "kubernetes.io/dockercfg"
+
+	// SecretTypeTLS contains information about a TLS client or server secret.
+	//
+	// Required fields:
+	// - Secret.Data["tls.crt"] - PEM encoded TLS certificate
+	// - Secret.Data["tls.key"] - PEM encoded TLS private key
+	SecretTypeTLS SecretType = "kubernetes.io/tls"
+)
+
+const (
+	// DockerConfigKey is the key of the required data for SecretTypeDockercfg secrets.
+	DockerConfigKey = ".dockercfg"
+
+	// TLSCertKey is the key for the TLS certificate of SecretTypeTLS secrets.
+	TLSCertKey = "tls.crt"
+	// TLSPrivateKeyKey is the key for the TLS private key of SecretTypeTLS secrets.
+	TLSPrivateKeyKey = "tls.key"
 )
 
 type SecretList struct {
@@ -1577,6 +1774,115 @@ type SecretList struct {
 	Items []Secret `json:"items"`
 }
 
+// PolicyRule holds information that describes a policy rule of a role.
+type PolicyRule struct {
+	// Verbs is a list of verbs that apply to all of the resources, e.g. get, list, watch,
+	// create, update, delete or proxy.  "*" represents all verbs.
+	Verbs []string `json:"verbs"`
+	// Resources is a list of resources this rule applies to.  A subresource is written
+	// as "pods/status".  "*" represents all resources.
+	Resources []string `json:"resources"`
+	// ResourceNames is an optional list of the names the rule applies to.  An empty list
+	// means the rule applies to every name.
+	ResourceNames []string `json:"resourceNames,omitempty"`
+}
+
+// SubjectKind is the kind of a subject a role is granted to.
+type SubjectKind string
+
+const (
+	// UserKind subjects are matched against the name of the user.
+	UserKind SubjectKind = "User"
+	// GroupKind subjects are matched against the groups of the user.
+	GroupKind SubjectKind = "Group"
+)
+
+// Subject is a user or a group a role is granted to.
+type Subject struct {
+	Kind SubjectKind `json:"kind"`
+	Name string      `json:"name"`
+}
+
+// RoleRef references the role granted by a binding.  A RoleBinding may reference a Role
+// of its namespace or a ClusterRole, a ClusterRoleBinding may only reference a ClusterRole.
+type RoleRef struct {
+	// Kind is either "Role" or "ClusterRole".
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// Role is a namespaced set of policy rules.
+type Role struct {
+	TypeMeta   `json:",inline"`
+	ObjectMeta `json:"metadata,omitempty"`
+
+	// Rules holds all of the policy rules of this role.
+	Rules []PolicyRule `json:"rules"`
+}
+
+// RoleList is a list of Roles.
+type RoleList struct {
+	TypeMeta `json:",inline"`
+	ListMeta `json:"metadata,omitempty"`
+
+	Items []Role `json:"items"`
+}
+
+// RoleBinding grants the policy rules of a role to subjects, within the namespace of the binding.
+type RoleBinding struct {
+	TypeMeta   `json:",inline"`
+	ObjectMeta `json:"metadata,omitempty"`
+
+	// Subjects holds the users and groups the role is granted to.
+	Subjects []Subject `json:"subjects"`
+	// RoleRef references a Role of the namespace of the binding or a ClusterRole.
+	RoleRef RoleRef `json:"roleRef"`
+}
+
+// RoleBindingList is a list of RoleBindings.
+type RoleBindingList struct {
+	TypeMeta `json:",inline"`
+	ListMeta `json:"metadata,omitempty"`
+
+	Items []RoleBinding `json:"items"`
+}
+
+// ClusterRole is a cluster-wide set of policy rules.
+type ClusterRole struct {
+	TypeMeta   `json:",inline"`
+	ObjectMeta `json:"metadata,omitempty"`
+
+	// Rules holds all of the policy rules of this role.
+	Rules []PolicyRule `json:"rules"`
+}
+
+// ClusterRoleList is a list of ClusterRoles.
+type ClusterRoleList struct {
+	TypeMeta `json:",inline"`
+	ListMeta `json:"metadata,omitempty"`
+
+	Items []ClusterRole `json:"items"`
+}
+
+// ClusterRoleBinding grants the policy rules of a ClusterRole to subjects, in every namespace.
+type ClusterRoleBinding struct {
+	TypeMeta   `json:",inline"`
+	ObjectMeta `json:"metadata,omitempty"`
+
+	// Subjects holds the users and groups the role is granted to.
+	Subjects []Subject `json:"subjects"`
+	// RoleRef references a ClusterRole.
+	RoleRef RoleRef `json:"roleRef"`
+}
+
+// ClusterRoleBindingList is a list of ClusterRoleBindings.
+type ClusterRoleBindingList struct {
+	TypeMeta `json:",inline"`
+	ListMeta `json:"metadata,omitempty"`
+
+	Items []ClusterRoleBinding `json:"items"`
+}
+
 // These constants are for remote command execution and port forwarding and are
 // used by both the client side and server side components.
 //
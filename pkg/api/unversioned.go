@@ -17,7 +17,13 @@ limitations under the License.
 package api
 
 import (
+	"fmt"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 )
 
 // This file contains API types that are unversioned.
@@ -29,6 +35,145 @@ type APIVersions struct {
 	Versions []string `json:"versions"`
 }
 
+// APIResourceList lists the resources served by one version of the API, to
+// allow clients to discover them at runtime.
+type APIResourceList struct {
+	// APIVersion is the version of the API the resources belong to.
+	APIVersion string `json:"apiVersion"`
+	// Resources contains the name of each resource and what it supports.
+	Resources []APIResource `json:"resources"`
+}
+
+// APIResource describes a resource served by the API.
+type APIResource struct {
+	// Name is the name of the resource, as used in request paths.
+	Name string `json:"name"`
+	// Namespaced is true if the objects of the resource live in a namespace.
+	Namespaced bool `json:"namespaced"`
+	// Verbs lists the operations supported by the resource, among "create",
+	// "delete", "get", "list", "update" and "watch".
+	Verbs []string `json:"verbs"`
+}
+
+// PodLogOptions are the options for retrieving the log of a container. They
+// are passed as query parameters of the kubelet's /containerLogs endpoint.
+type PodLogOptions struct {
+	// Follow streams the log until the container stops.
+	Follow bool
+	// Previous returns the log of the previous terminated instance of the
+	// container rather than of the current one.
+	Previous bool
+	// SinceTime only returns the lines logged at or after this time, if set.
+	SinceTime *util.Time
+	// Timestamps prefixes every line with the RFC3339Nano time it was logged
+	// at, if true. Unset means true, as logs always had timestamps.
+	Timestamps *bool
+	// Tail only returns this many lines from the end of the log: a number, or
+	// "all". Empty means all.
+	Tail string
+	// LimitBytes stops the log after this many bytes, if positive. The last
+	// line may be cut.
+	LimitBytes int64
+}
+
+// Query parameters of PodLogOptions.
+const (
+	PodLogFollowParam     = "follow"
+	PodLogPreviousParam   = "previous"
+	PodLogSinceTimeParam  = "sinceTime"
+	PodLogTimestampsParam = "timestamps"
+	PodLogTailParam       = "tail"
+	PodLogLimitBytesParam = "limitBytes"
+)
+
+// Values returns the query parameters for the options that are set.
+func (o *PodLogOptions) Values() url.Values {
+	values := url.Values{}
+	if o.Follow {
+		values.Set(PodLogFollowParam, "true")
+	}
+	if o.Previous {
+		values.Set(PodLogPreviousParam, "true")
+	}
+	if o.SinceTime != nil {
+		values.Set(PodLogSinceTimeParam, o.SinceTime.UTC().Format(time.RFC3339Nano))
+	}
+	if o.Timestamps != nil {
+		values.Set(PodLogTimestampsParam, strconv.FormatBool(*o.Timestamps))
+	}
+	if len(o.Tail) > 0 {
+		values.Set(PodLogTailParam, o.Tail)
+	}
+	if o.LimitBytes > 0 {
+		values.Set(PodLogLimitBytesParam, strconv.FormatInt(o.LimitBytes, 10))
+	}
+	return values
+}
+
+// ShowTimestamps returns whether every line should be prefixed with the time it
+// was logged at.
+func (o *PodLogOptions) ShowTimestamps() bool {
+	return o.Timestamps == nil || *o.Timestamps
+}
+
+// ParsePodLogOptions reads PodLogOptions from query parameters.
+func ParsePodLogOptions(values url.Values) (*PodLogOptions, error) {
+	opts := &PodLogOptions{Tail: values.Get(PodLogTailParam)}
+	var err error
+	for param, value := range map[string]*bool{
+		PodLogFollowParam:   &opts.Follow,
+		PodLogPreviousParam: &opts.Previous,
+	} {
+		if s := values.Get(param); len(s) > 0 {
+			if *value, err = strconv.ParseBool(s); err != nil {
+				return nil, fmt.Errorf("invalid %s %q: %v", param, s, err)
+			}
+		}
+	}
+	if s := values.Get(PodLogTimestampsParam); len(s) > 0 {
+		timestamps, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %v", PodLogTimestampsParam, s, err)
+		}
+		opts.Timestamps = &timestamps
+	}
+	if s := values.Get(PodLogSinceTimeParam); len(s) > 0 {
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %v", PodLogSinceTimeParam, s, err)
+		}
+		sinceTime := util.NewTime(t)
+		opts.SinceTime = &sinceTime
+	}
+	if s := values.Get(PodLogLimitBytesParam); len(s) > 0 {
+		if opts.LimitBytes, err = strconv.ParseInt(s, 10, 64); err != nil || opts.LimitBytes < 0 {
+			return nil, fmt.Errorf("invalid %s %q: must be a non-negative number", PodLogLimitBytesParam, s)
+		}
+	}
+	if len(opts.Tail) > 0 && opts.Tail != "all" {
+		if n, err := strconv.Atoi(opts.Tail); err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid %s %q: must be a non-negative number or \"all\"", PodLogTailParam, opts.Tail)
+		}
+	}
+	return opts, nil
+}
+
+// PatchType is the content type of a PATCH request body, which selects how the
+// patch is applied to the stored object.
+type PatchType string
+
+const (
+	// JSONPatchType is a list of operations, as described in RFC 6902.
+	JSONPatchType PatchType = "application/json-patch+json"
+	// MergePatchType is a partial object merged into the original, as
+	// described in RFC 7386. Lists in the patch replace the original lists.
+	MergePatchType PatchType = "application/merge-patch+json"
+	// StrategicMergePatchType is a partial object merged into the original
+	// like MergePatchType, except that lists of the API types tagged with a
+	// patch merge key are merged item by item instead of being replaced.
+	StrategicMergePatchType PatchType = "application/strategic-merge-patch+json"
+)
+
 // RootPaths lists the paths available at root.
 // For example: "/healthz", "/api".
 type RootPaths struct {
@@ -408,8 +408,7 @@ func TestEndpointsConversion(t *testing.T) {
 				Endpoints: []string{},
 			},
 			expected: newer.Endpoints{
-				Protocol:  newer.ProtocolTCP,
-				Endpoints: []newer.Endpoint{},
+				Subsets: []newer.EndpointSubset{},
 			},
 		},
 		{
@@ -421,8 +420,10 @@ func TestEndpointsConversion(t *testing.T) {
 				Endpoints: []string{"1.2.3.4:88"},
 			},
 			expected: newer.Endpoints{
-				Protocol:  newer.ProtocolTCP,
-				Endpoints: []newer.Endpoint{{IP: "1.2.3.4", Port: 88}},
+				Subsets: []newer.EndpointSubset{{
+					Addresses: []newer.EndpointAddress{{IP: "1.2.3.4"}},
+					Ports:     []newer.EndpointPort{{Protocol: newer.ProtocolTCP, Port: 88}},
+				}},
 			},
 		},
 		{
@@ -434,8 +435,20 @@ func TestEndpointsConversion(t *testing.T) {
 				Endpoints: []string{"1.2.3.4:88", "1.2.3.4:89", "1.2.3.4:90"},
 			},
 			expected: newer.Endpoints{
-				Protocol:  newer.ProtocolUDP,
-				Endpoints: []newer.Endpoint{{IP: "1.2.3.4", Port: 88}, {IP: "1.2.3.4", Port: 89}, {IP: "1.2.3.4", Port: 90}},
+				Subsets: []newer.EndpointSubset{
+					{
+						Addresses: []newer.EndpointAddress{{IP: "1.2.3.4"}},
+						Ports:     []newer.EndpointPort{{Protocol: newer.ProtocolUDP, Port: 88}},
+					},
+					{
+						Addresses: []newer.EndpointAddress{{IP: "1.2.3.4"}},
+						Ports:     []newer.EndpointPort{{Protocol: newer.ProtocolUDP, Port: 89}},
+					},
+					{
+						Addresses: []newer.EndpointAddress{{IP: "1.2.3.4"}},
+						Ports:     []newer.EndpointPort{{Protocol: newer.ProtocolUDP, Port: 90}},
+					},
+				},
 			},
 		},
 	}
@@ -447,7 +460,7 @@ func TestEndpointsConversion(t *testing.T) {
 			t.Errorf("[Case: %d] Unexpected error: %v", i, err)
 			continue
 		}
-		if got.Protocol != tc.expected.Protocol || !newer.Semantic.DeepEqual(got.Endpoints, tc.expected.Endpoints) {
+		if !newer.Semantic.DeepEqual(got.Subsets, tc.expected.Subsets) {
 			t.Errorf("[Case: %d] Expected %v, got %v", i, tc.expected, got)
 		}
 
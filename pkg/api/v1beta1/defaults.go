@@ -17,6 +17,8 @@ limitations under the License.
 package v1beta1
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
@@ -51,6 +53,7 @@ func init() {
 			if obj.TerminationMessagePath == "" {
 				obj.TerminationMessagePath = TerminationMessagePathDefault
 			}
+			defaultResourceRequests(obj)
 		},
 		func(obj *RestartPolicy) {
 			if util.AllPtrFieldsNil(obj) {
@@ -96,6 +99,16 @@ func init() {
 				obj.Protocol = "TCP"
 			}
 		},
+		func(obj *ServicePort) {
+			if obj.Protocol == "" {
+				obj.Protocol = ProtocolTCP
+			}
+		},
+		func(obj *EndpointPort) {
+			if obj.Protocol == "" {
+				obj.Protocol = ProtocolTCP
+			}
+		},
 		func(obj *HTTPGetAction) {
 			if obj.Path == "" {
 				obj.Path = "/"
@@ -119,3 +132,32 @@ func defaultHostNetworkPorts(containers *[]Container) {
 		}
 	}
 }
+
+// defaultResourceRequests sets the request of a container for cpu and memory to
+// its limit, unless the request is set. The CPU and Memory fields of the container
+// are limits as well.
+func defaultResourceRequests(container *Container) {
+	limits := ResourceList{}
+	for name, limit := range container.Resources.Limits {
+		limits[name] = limit
+	}
+	if _, found := limits[ResourceCPU]; !found && container.CPU > 0 {
+		limits[ResourceCPU] = util.NewIntOrStringFromString(fmt.Sprintf("%v", float64(container.CPU)/1000))
+	}
+	if _, found := limits[ResourceMemory]; !found && container.Memory > 0 {
+		limits[ResourceMemory] = util.NewIntOrStringFromString(strconv.FormatInt(container.Memory, 10))
+	}
+	for _, name := range []ResourceName{ResourceCPU, ResourceMemory} {
+		limit, found := limits[name]
+		if !found {
+			continue
+		}
+		if _, found := container.Resources.Requests[name]; found {
+			continue
+		}
+		if container.Resources.Requests == nil {
+			container.Resources.Requests = ResourceList{}
+		}
+		container.Resources.Requests[name] = limit
+	}
+}
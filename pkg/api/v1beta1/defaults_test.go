@@ -22,6 +22,7 @@ import (
 
 	current "github.com/GoogleCloudPlatform/kubernetes/pkg/api/v1beta1"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 )
 
 func roundTrip(t *testing.T, obj runtime.Object) runtime.Object {
@@ -105,3 +106,32 @@ func TestSetDefaultContainerManifestHostNetwork(t *testing.T) {
 		t.Errorf("Expected container port to be defaulted, was made %d instead of %d", hostPortNum, portNum)
 	}
 }
+
+func TestSetDefaultContainerResourceRequests(t *testing.T) {
+	s := current.ContainerManifest{}
+	s.Containers = []current.Container{
+		{
+			CPU: 500,
+			Resources: current.ResourceRequirements{
+				Limits:   current.ResourceList{current.ResourceMemory: util.NewIntOrStringFromString("2000")},
+				Requests: current.ResourceList{current.ResourceMemory: util.NewIntOrStringFromString("1000")},
+			},
+		},
+		{},
+	}
+	obj2 := roundTrip(t, runtime.Object(&current.ContainerManifestList{
+		Items: []current.ContainerManifest{s},
+	}))
+	s2 := obj2.(*current.ContainerManifestList).Items[0]
+
+	requests := s2.Containers[0].Resources.Requests
+	if cpu := requests[current.ResourceCPU]; cpu.String() != "0.5" {
+		t.Errorf("Expected cpu request to default to the cpu limit, got %v", cpu)
+	}
+	if memory := requests[current.ResourceMemory]; memory.String() != "1000" {
+		t.Errorf("Expected memory request to be kept, got %v", memory)
+	}
+	if requests := s2.Containers[1].Resources.Requests; len(requests) != 0 {
+		t.Errorf("Expected no requests without limits, got %v", requests)
+	}
+}
@@ -64,6 +64,14 @@ func init() {
 		&PersistentVolumeList{},
 		&PersistentVolumeClaim{},
 		&PersistentVolumeClaimList{},
+		&Role{},
+		&RoleList{},
+		&RoleBinding{},
+		&RoleBindingList{},
+		&ClusterRole{},
+		&ClusterRoleList{},
+		&ClusterRoleBinding{},
+		&ClusterRoleBindingList{},
 	)
 	// Future names are supported
 	api.Scheme.AddKnownTypeWithName("v1beta1", "Node", &Minion{})
@@ -102,3 +110,11 @@ func (*PersistentVolume) IsAnAPIObject()          {}
 func (*PersistentVolumeList) IsAnAPIObject()      {}
 func (*PersistentVolumeClaim) IsAnAPIObject()     {}
 func (*PersistentVolumeClaimList) IsAnAPIObject() {}
+func (*Role) IsAnAPIObject()                      {}
+func (*RoleList) IsAnAPIObject()                  {}
+func (*RoleBinding) IsAnAPIObject()               {}
+func (*RoleBindingList) IsAnAPIObject()           {}
+func (*ClusterRole) IsAnAPIObject()               {}
+func (*ClusterRoleList) IsAnAPIObject()           {}
+func (*ClusterRoleBinding) IsAnAPIObject()        {}
+func (*ClusterRoleBindingList) IsAnAPIObject()    {}
@@ -22,6 +22,7 @@ import (
 	"strconv"
 
 	newer "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/endpoints"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/conversion"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
@@ -85,6 +86,7 @@ func init() {
 			out.UID = in.UID
 			out.CreationTimestamp = in.CreationTimestamp
 			out.DeletionTimestamp = in.DeletionTimestamp
+			out.DeletionGracePeriodSeconds = in.DeletionGracePeriodSeconds
 			out.SelfLink = in.SelfLink
 			if len(in.ResourceVersion) > 0 {
 				v, err := strconv.ParseUint(in.ResourceVersion, 10, 64)
@@ -102,6 +104,7 @@ func init() {
 			out.UID = in.UID
 			out.CreationTimestamp = in.CreationTimestamp
 			out.DeletionTimestamp = in.DeletionTimestamp
+			out.DeletionGracePeriodSeconds = in.DeletionGracePeriodSeconds
 			out.SelfLink = in.SelfLink
 			if in.ResourceVersion != 0 {
 				out.ResourceVersion = strconv.FormatUint(in.ResourceVersion, 10)
@@ -184,6 +187,9 @@ func init() {
 			if err := s.Convert(&in.Spec.NodeSelector, &out.NodeSelector, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.Spec.NodeSelectorRequirements, &out.NodeSelectorRequirements, 0); err != nil {
+				return err
+			}
 			return nil
 		},
 		func(in *Pod, out *newer.Pod, s conversion.Scope) error {
@@ -206,6 +212,9 @@ func init() {
 			if err := s.Convert(&in.NodeSelector, &out.Spec.NodeSelector, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.NodeSelectorRequirements, &out.Spec.NodeSelectorRequirements, 0); err != nil {
+				return err
+			}
 			return nil
 		},
 
@@ -249,6 +258,9 @@ func init() {
 			if err := s.Convert(&in.Selector, &out.ReplicaSelector, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.SelectorRequirements, &out.ReplicaSelectorRequirements, 0); err != nil {
+				return err
+			}
 			if in.TemplateRef != nil && in.Template == nil {
 				return &newer.ConversionError{
 					In:      in,
@@ -268,6 +280,9 @@ func init() {
 			if err := s.Convert(&in.ReplicaSelector, &out.Selector, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.ReplicaSelectorRequirements, &out.SelectorRequirements, 0); err != nil {
+				return err
+			}
 			out.Template = &newer.PodTemplateSpec{}
 			if err := s.Convert(&in.PodTemplate, out.Template, 0); err != nil {
 				return err
@@ -283,6 +298,9 @@ func init() {
 			if err := s.Convert(&in.Spec.NodeSelector, &out.NodeSelector, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.Spec.NodeSelectorRequirements, &out.NodeSelectorRequirements, 0); err != nil {
+				return err
+			}
 			if err := s.Convert(&in.ObjectMeta.Labels, &out.Labels, 0); err != nil {
 				return err
 			}
@@ -299,6 +317,9 @@ func init() {
 			if err := s.Convert(&in.NodeSelector, &out.Spec.NodeSelector, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.NodeSelectorRequirements, &out.Spec.NodeSelectorRequirements, 0); err != nil {
+				return err
+			}
 			if err := s.Convert(&in.Labels, &out.ObjectMeta.Labels, 0); err != nil {
 				return err
 			}
@@ -463,6 +484,10 @@ func init() {
 			out.DNSPolicy = DNSPolicy(in.DNSPolicy)
 			out.Version = "v1beta2"
 			out.HostNetwork = in.HostNetwork
+			out.TerminationGracePeriodSeconds = in.TerminationGracePeriodSeconds
+			if err := s.Convert(&in.ImagePullSecrets, &out.ImagePullSecrets, 0); err != nil {
+				return err
+			}
 			return nil
 		},
 		func(in *ContainerManifest, out *newer.PodSpec, s conversion.Scope) error {
@@ -477,6 +502,10 @@ func init() {
 			}
 			out.DNSPolicy = newer.DNSPolicy(in.DNSPolicy)
 			out.HostNetwork = in.HostNetwork
+			out.TerminationGracePeriodSeconds = in.TerminationGracePeriodSeconds
+			if err := s.Convert(&in.ImagePullSecrets, &out.ImagePullSecrets, 0); err != nil {
+				return err
+			}
 			return nil
 		},
 
@@ -563,14 +592,22 @@ func init() {
 				return err
 			}
 
-			out.Port = in.Spec.Port
-			out.Protocol = Protocol(in.Spec.Protocol)
+			if err := s.Convert(&in.Spec.Ports, &out.Ports, 0); err != nil {
+				return err
+			}
+			if len(out.Ports) > 0 {
+				out.Port = out.Ports[0].Port
+				out.Protocol = out.Ports[0].Protocol
+				out.ContainerPort = out.Ports[0].ContainerPort
+			}
 			if err := s.Convert(&in.Spec.Selector, &out.Selector, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.Spec.SelectorRequirements, &out.SelectorRequirements, 0); err != nil {
+				return err
+			}
 			out.CreateExternalLoadBalancer = in.Spec.CreateExternalLoadBalancer
 			out.PublicIPs = in.Spec.PublicIPs
-			out.ContainerPort = in.Spec.TargetPort
 			out.PortalIP = in.Spec.PortalIP
 			if err := s.Convert(&in.Spec.SessionAffinity, &out.SessionAffinity, 0); err != nil {
 				return err
@@ -589,14 +626,25 @@ func init() {
 				return err
 			}
 
-			out.Spec.Port = in.Port
-			out.Spec.Protocol = newer.Protocol(in.Protocol)
+			if len(in.Ports) > 0 {
+				if err := s.Convert(&in.Ports, &out.Spec.Ports, 0); err != nil {
+					return err
+				}
+			} else if in.Port != 0 {
+				out.Spec.Ports = []newer.ServicePort{{
+					Protocol:   newer.Protocol(in.Protocol),
+					Port:       in.Port,
+					TargetPort: in.ContainerPort,
+				}}
+			}
 			if err := s.Convert(&in.Selector, &out.Spec.Selector, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.SelectorRequirements, &out.Spec.SelectorRequirements, 0); err != nil {
+				return err
+			}
 			out.Spec.CreateExternalLoadBalancer = in.CreateExternalLoadBalancer
 			out.Spec.PublicIPs = in.PublicIPs
-			out.Spec.TargetPort = in.ContainerPort
 			out.Spec.PortalIP = in.PortalIP
 			if err := s.Convert(&in.SessionAffinity, &out.Spec.SessionAffinity, 0); err != nil {
 				return err
@@ -989,6 +1037,9 @@ func init() {
 			if err := s.Convert(&in.NFS, &out.NFS, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.DownwardAPI, &out.DownwardAPI, 0); err != nil {
+				return err
+			}
 			return nil
 		},
 		func(in *VolumeSource, out *newer.VolumeSource, s conversion.Scope) error {
@@ -1010,6 +1061,9 @@ func init() {
 			if err := s.Convert(&in.NFS, &out.NFS, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.DownwardAPI, &out.DownwardAPI, 0); err != nil {
+				return err
+			}
 			return nil
 		},
 
@@ -1085,6 +1139,9 @@ func init() {
 			}
 			out.InitialDelaySeconds = in.InitialDelaySeconds
 			out.TimeoutSeconds = in.TimeoutSeconds
+			out.PeriodSeconds = in.PeriodSeconds
+			out.SuccessThreshold = in.SuccessThreshold
+			out.FailureThreshold = in.FailureThreshold
 			return nil
 		},
 		func(in *LivenessProbe, out *newer.Probe, s conversion.Scope) error {
@@ -1099,6 +1156,24 @@ func init() {
 			}
 			out.InitialDelaySeconds = in.InitialDelaySeconds
 			out.TimeoutSeconds = in.TimeoutSeconds
+			out.PeriodSeconds = in.PeriodSeconds
+			out.SuccessThreshold = in.SuccessThreshold
+			out.FailureThreshold = in.FailureThreshold
+			return nil
+		},
+
+		func(in *newer.ServicePort, out *ServicePort, s conversion.Scope) error {
+			out.Name = in.Name
+			out.Protocol = Protocol(in.Protocol)
+			out.Port = in.Port
+			out.ContainerPort = in.TargetPort
+			return nil
+		},
+		func(in *ServicePort, out *newer.ServicePort, s conversion.Scope) error {
+			out.Name = in.Name
+			out.Protocol = newer.Protocol(in.Protocol)
+			out.Port = in.Port
+			out.TargetPort = in.ContainerPort
 			return nil
 		},
 
@@ -1109,21 +1184,37 @@ func init() {
 			if err := s.Convert(&in.ObjectMeta, &out.TypeMeta, 0); err != nil {
 				return err
 			}
-			if err := s.Convert(&in.Protocol, &out.Protocol, 0); err != nil {
+			if err := s.Convert(&in.Subsets, &out.Subsets, 0); err != nil {
 				return err
 			}
-			for i := range in.Endpoints {
-				ep := &in.Endpoints[i]
-				hostPort := net.JoinHostPort(ep.IP, strconv.Itoa(ep.Port))
-				out.Endpoints = append(out.Endpoints, hostPort)
-				if ep.TargetRef != nil {
-					target := EndpointObjectReference{
-						Endpoint: hostPort,
+			// The legacy fields can only describe a single port: use the
+			// first port of the first subset and every address offering it.
+			if len(in.Subsets) == 0 || len(in.Subsets[0].Ports) == 0 {
+				out.Protocol = ProtocolTCP
+				return nil
+			}
+			portName := in.Subsets[0].Ports[0].Name
+			out.Protocol = Protocol(in.Subsets[0].Ports[0].Protocol)
+			for i := range in.Subsets {
+				ss := &in.Subsets[i]
+				for j := range ss.Ports {
+					if ss.Ports[j].Name != portName {
+						continue
 					}
-					if err := s.Convert(ep.TargetRef, &target.ObjectReference, 0); err != nil {
-						return err
+					for k := range ss.Addresses {
+						addr := &ss.Addresses[k]
+						hostPort := net.JoinHostPort(addr.IP, strconv.Itoa(ss.Ports[j].Port))
+						out.Endpoints = append(out.Endpoints, hostPort)
+						if addr.TargetRef != nil {
+							target := EndpointObjectReference{
+								Endpoint: hostPort,
+							}
+							if err := s.Convert(addr.TargetRef, &target.ObjectReference, 0); err != nil {
+								return err
+							}
+							out.TargetRefs = append(out.TargetRefs, target)
+						}
 					}
-					out.TargetRefs = append(out.TargetRefs, target)
 				}
 			}
 			return nil
@@ -1135,31 +1226,36 @@ func init() {
 			if err := s.Convert(&in.TypeMeta, &out.ObjectMeta, 0); err != nil {
 				return err
 			}
-			if err := s.Convert(&in.Protocol, &out.Protocol, 0); err != nil {
-				return err
+			if len(in.Subsets) > 0 {
+				return s.Convert(&in.Subsets, &out.Subsets, 0)
 			}
+			// Build one subset per legacy endpoint, then collapse them.
 			for i := range in.Endpoints {
-				out.Endpoints = append(out.Endpoints, newer.Endpoint{})
-				ep := &out.Endpoints[i]
 				host, port, err := net.SplitHostPort(in.Endpoints[i])
 				if err != nil {
 					return err
 				}
-				ep.IP = host
 				pn, err := strconv.Atoi(port)
 				if err != nil {
 					return err
 				}
-				ep.Port = pn
+				addr := newer.EndpointAddress{IP: host}
 				for j := range in.TargetRefs {
 					if in.TargetRefs[j].Endpoint != in.Endpoints[i] {
 						continue
 					}
-					ep.TargetRef = &newer.ObjectReference{}
-					if err := s.Convert(&in.TargetRefs[j], ep.TargetRef, 0); err != nil {
+					addr.TargetRef = &newer.ObjectReference{}
+					if err := s.Convert(&in.TargetRefs[j], addr.TargetRef, 0); err != nil {
 						return err
 					}
 				}
+				out.Subsets = append(out.Subsets, newer.EndpointSubset{
+					Addresses: []newer.EndpointAddress{addr},
+					Ports:     []newer.EndpointPort{{Port: pn, Protocol: newer.Protocol(in.Protocol)}},
+				})
+			}
+			if len(out.Subsets) > 0 {
+				out.Subsets = endpoints.RepackSubsets(out.Subsets)
 			}
 			return nil
 		},
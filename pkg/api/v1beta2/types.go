@@ -80,6 +80,8 @@ type VolumeSource struct {
 	Secret *SecretVolumeSource `json:"secret" description:"secret to populate volume"`
 	// NFS represents an NFS mount on the host that shares a pod's lifetime
 	NFS *NFSVolumeSource `json:"nfs" description:"NFS volume that will be mounted in the host machine"`
+	// DownwardAPI represents metadata about the pod that should populate this volume
+	DownwardAPI *DownwardAPIVolumeSource `json:"downwardAPI,omitempty" description:"metadata about the pod that should populate this volume"`
 }
 
 // Similar to VolumeSource but meant for the administrator who creates PVs.
@@ -225,6 +227,21 @@ type SecretVolumeSource struct {
 	Target ObjectReference `json:"target" description:"target is a reference to a secret"`
 }
 
+// DownwardAPIVolumeSource represents a volume containing downward API info.
+type DownwardAPIVolumeSource struct {
+	// Items is a list of downward API volume files.
+	Items []DownwardAPIVolumeFile `json:"items,omitempty" description:"list of downward API volume files"`
+}
+
+// DownwardAPIVolumeFile represents a single file containing information from
+// the downward API.
+type DownwardAPIVolumeFile struct {
+	// The relative path name of the file to be created.
+	Path string `json:"path" description:"relative path name of the file to be created; must not be absolute or contain the '..' path element"`
+	// Selects a field of the pod.
+	FieldRef ObjectFieldSelector `json:"fieldRef" description:"selects a field of the pod; only name, namespace, labels and annotations are supported"`
+}
+
 // Protocol defines network protocols supported for things like conatiner ports.
 type Protocol string
 
@@ -302,6 +319,30 @@ type EnvVar struct {
 	Name string `json:"name" description:"name of the environment variable; must be a C_IDENTIFIER"`
 	// Optional: defaults to "".
 	Value string `json:"value,omitempty" description:"value of the environment variable; defaults to empty string"`
+	// Optional: specifies a source the value of this var should come from.
+	ValueFrom *EnvVarSource `json:"valueFrom,omitempty" description:"source for the environment variable's value; cannot be used if value is not empty"`
+}
+
+// EnvVarSource represents a source for the value of an EnvVar.
+type EnvVarSource struct {
+	// Selects a key of a secret in the pod's namespace.
+	SecretKeyRef *SecretKeySelector `json:"secretKeyRef,omitempty" description:"selects a key of a secret in the pod's namespace"`
+	// Selects a field of the pod.
+	FieldRef *ObjectFieldSelector `json:"fieldRef,omitempty" description:"selects a field of the pod; only name, namespace and podIP are supported"`
+}
+
+// SecretKeySelector selects a key of a Secret.
+type SecretKeySelector struct {
+	// The name of the secret in the pod's namespace to select from.
+	Name string `json:"name" description:"name of the secret in the pod's namespace"`
+	// The key of the secret to select from.  Must be a valid secret key.
+	Key string `json:"key" description:"key of the secret to select from"`
+}
+
+// ObjectFieldSelector selects a field of an object.
+type ObjectFieldSelector struct {
+	// Path of the field to select, e.g. "metadata.name".
+	FieldPath string `json:"fieldPath" description:"path of the field to select, e.g. metadata.name"`
 }
 
 // HTTPGetAction describes an action based on HTTP Get requests.
@@ -348,6 +389,12 @@ type LivenessProbe struct {
 	InitialDelaySeconds int64 `json:"initialDelaySeconds,omitempty" description:"number of seconds after the container has started before liveness probes are initiated"`
 	// Length of time before health checking times out.  In seconds.
 	TimeoutSeconds int64 `json:"timeoutSeconds,omitempty" description:"number of seconds after which liveness probes timeout; defaults to 1 second"`
+	// How often to perform the probe.  In seconds.
+	PeriodSeconds int64 `json:"periodSeconds,omitempty" description:"how often, in seconds, to perform the probe; defaults to 10 seconds"`
+	// Minimum consecutive successes for the probe to be considered successful after having failed.
+	SuccessThreshold int `json:"successThreshold,omitempty" description:"minimum consecutive successes for the probe to be considered successful after having failed; defaults to 1"`
+	// Minimum consecutive failures for the probe to be considered failed after having succeeded.
+	FailureThreshold int `json:"failureThreshold,omitempty" description:"minimum consecutive failures for the probe to be considered failed after having succeeded; defaults to 3"`
 }
 
 // PullPolicy describes a policy for if/when to pull a container image
@@ -397,14 +444,14 @@ type Container struct {
 	Command []string `json:"command,omitempty" description:"command argv array; not executed within a shell; defaults to entrypoint or command in the image; cannot be updated"`
 	// Optional: Defaults to Docker's default.
 	WorkingDir string               `json:"workingDir,omitempty" description:"container's working directory; defaults to image's default; cannot be updated"`
-	Ports      []ContainerPort      `json:"ports,omitempty" description:"list of ports to expose from the container; cannot be updated"`
-	Env        []EnvVar             `json:"env,omitempty" description:"list of environment variables to set in the container; cannot be updated"`
+	Ports      []ContainerPort      `json:"ports,omitempty" patchStrategy:"merge" patchMergeKey:"containerPort" description:"list of ports to expose from the container; cannot be updated"`
+	Env        []EnvVar             `json:"env,omitempty" patchStrategy:"merge" patchMergeKey:"name" description:"list of environment variables to set in the container; cannot be updated"`
 	Resources  ResourceRequirements `json:"resources,omitempty" description:"Compute Resources required by this container; cannot be updated"`
 	// Optional: Defaults to unlimited.
 	CPU int `json:"cpu,omitempty" description:"CPU share in thousandths of a core; cannot be updated"`
 	// Optional: Defaults to unlimited.
 	Memory         int64          `json:"memory,omitempty" description:"memory limit in bytes; defaults to unlimited; cannot be updated"`
-	VolumeMounts   []VolumeMount  `json:"volumeMounts,omitempty" description:"pod volumes to mount into the container's filesystem; cannot be updated"`
+	VolumeMounts   []VolumeMount  `json:"volumeMounts,omitempty" patchStrategy:"merge" patchMergeKey:"name" description:"pod volumes to mount into the container's filesystem; cannot be updated"`
 	LivenessProbe  *LivenessProbe `json:"livenessProbe,omitempty" description:"periodic probe of container liveness; container will be restarted if the probe fails; cannot be updated"`
 	ReadinessProbe *LivenessProbe `json:"readinessProbe,omitempty" description:"periodic probe of container service readiness; container will be removed from service endpoints if the probe fails; cannot be updated"`
 	Lifecycle      *Lifecycle     `json:"lifecycle,omitempty" description:"actions that the management system should take in response to container lifecycle events; cannot be updated"`
@@ -476,6 +523,11 @@ type TypeMeta struct {
 	// will send a hard termination signal to the container.
 	DeletionTimestamp *util.Time `json:"deletionTimestamp,omitempty" description:"RFC 3339 date and time at which the object will be deleted; populated by the system when a graceful deletion is requested, read-only; if not set, graceful deletion of the object has not been requested"`
 
+	// DeletionGracePeriodSeconds is the number of seconds allowed for this object to
+	// terminate gracefully before it is removed from the system. It is only set when
+	// DeletionTimestamp is also set, and may only be shortened. Read-only.
+	DeletionGracePeriodSeconds *int64 `json:"deletionGracePeriodSeconds,omitempty" description:"number of seconds allowed for this object to gracefully terminate before it will be removed from the system; only set when deletionTimestamp is also set, may only be shortened; read-only"`
+
 	// GenerateName indicates that the name should be made unique by the server prior to persisting
 	// it. A non-empty value for the field indicates the name will be made unique (and the name
 	// returned to the client will be different than the name passed). The value of this field will
@@ -651,13 +703,16 @@ type Pod struct {
 	CurrentState PodState          `json:"currentState,omitempty" description:"current state of the pod; populated by the system, read-only"`
 	// NodeSelector is a selector which must be true for the pod to fit on a node
 	NodeSelector map[string]string `json:"nodeSelector,omitempty" description:"selector which must match a node's labels for the pod to be scheduled on that node"`
+	// NodeSelectorRequirements are set-based requirements which must also be true for the pod to fit on a node
+	NodeSelectorRequirements []LabelSelectorRequirement `json:"nodeSelectorRequirements,omitempty" description:"set-based requirements which must also match a node's labels for the pod to be scheduled on that node"`
 }
 
 // ReplicationControllerState is the state of a replication controller, either input (create, update) or as output (list, get).
 type ReplicationControllerState struct {
-	Replicas        int               `json:"replicas" description:"number of replicas (desired or observed, as appropriate)"`
-	ReplicaSelector map[string]string `json:"replicaSelector,omitempty" description:"label keys and values that must match in order to be controlled by this replication controller"`
-	PodTemplate     PodTemplate       `json:"podTemplate,omitempty" description:"template for pods to be created by this replication controller when the observed number of replicas is less than the desired number of replicas"`
+	Replicas                    int                        `json:"replicas" description:"number of replicas (desired or observed, as appropriate)"`
+	ReplicaSelector             map[string]string          `json:"replicaSelector,omitempty" description:"label keys and values that must match in order to be controlled by this replication controller"`
+	ReplicaSelectorRequirements []LabelSelectorRequirement `json:"replicaSelectorRequirements,omitempty" description:"set-based requirements that pods must also satisfy in order to be controlled by this replication controller"`
+	PodTemplate                 PodTemplate                `json:"podTemplate,omitempty" description:"template for pods to be created by this replication controller when the observed number of replicas is less than the desired number of replicas"`
 }
 
 // ReplicationControllerList is a collection of replication controllers.
@@ -680,10 +735,11 @@ type ReplicationController struct {
 //
 // https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/replication-controller.md#pod-template
 type PodTemplate struct {
-	DesiredState PodState          `json:"desiredState,omitempty" description:"specification of the desired state of pods created from this template"`
-	NodeSelector map[string]string `json:"nodeSelector,omitempty" description:"a selector which must be true for the pod to fit on a node"`
-	Labels       map[string]string `json:"labels,omitempty" description:"map of string keys and values that can be used to organize and categorize the pods created from the template; must match the selector of the replication controller to which the template belongs; may match selectors of services"`
-	Annotations  map[string]string `json:"annotations,omitempty" description:"map of string keys and values that can be used by external tooling to store and retrieve arbitrary metadata about pods created from the template"`
+	DesiredState             PodState                   `json:"desiredState,omitempty" description:"specification of the desired state of pods created from this template"`
+	NodeSelector             map[string]string          `json:"nodeSelector,omitempty" description:"a selector which must be true for the pod to fit on a node"`
+	NodeSelectorRequirements []LabelSelectorRequirement `json:"nodeSelectorRequirements,omitempty" description:"set-based requirements which must also be true for the pod to fit on a node"`
+	Labels                   map[string]string          `json:"labels,omitempty" description:"map of string keys and values that can be used to organize and categorize the pods created from the template; must match the selector of the replication controller to which the template belongs; may match selectors of services"`
+	Annotations              map[string]string          `json:"annotations,omitempty" description:"map of string keys and values that can be used by external tooling to store and retrieve arbitrary metadata about pods created from the template"`
 }
 
 // Session Affinity Type string
@@ -727,6 +783,8 @@ type Service struct {
 
 	// This service will route traffic to pods having labels matching this selector. If null, no endpoints will be automatically created. If empty, all pods will be selected.
 	Selector map[string]string `json:"selector" description:"label keys and values that must match in order to receive traffic for this service; if empty, all pods are selected, if not specified, endpoints must be manually specified"`
+	// SelectorRequirements are set-based requirements that pods must also satisfy to receive traffic for this service.
+	SelectorRequirements []LabelSelectorRequirement `json:"selectorRequirements,omitempty" description:"set-based requirements that pods must also satisfy in order to receive traffic for this service"`
 	// An external load balancer should be set up via the cloud-provider
 	CreateExternalLoadBalancer bool `json:"createExternalLoadBalancer,omitempty" description:"set up a cloud-provider-specific load balancer on an external IP"`
 
@@ -751,6 +809,28 @@ type Service struct {
 
 	// Optional: Supports "ClientIP" and "None".  Used to maintain session affinity.
 	SessionAffinity AffinityType `json:"sessionAffinity,omitempty" description:"enable client IP based session affinity; must be ClientIP or None; defaults to None"`
+
+	// Optional: Ports is the list of ports exposed by this service.  If it is set,
+	// Port, Protocol and ContainerPort are ignored on input and describe the first
+	// port on output.
+	Ports []ServicePort `json:"ports,omitempty" description:"ports exposed by the service; if specified, port, protocol and containerPort are ignored on input and reflect the first port on output"`
+}
+
+// ServicePort is a single port exposed by a service.
+type ServicePort struct {
+	// Optional if only one ServicePort is defined on this service: The
+	// name of this port within the service.  This must be a DNS_LABEL.
+	// All ports within a service must have unique names.
+	Name string `json:"name,omitempty" description:"the name of this port; optional if only one port is defined"`
+
+	// Optional: Supports "TCP" and "UDP".  Defaults to "TCP".
+	Protocol Protocol `json:"protocol,omitempty" description:"the protocol used by this port; must be UDP or TCP; TCP if unspecified"`
+
+	// Required: The port that will be exposed on the service.
+	Port int `json:"port" description:"the port number that is exposed"`
+
+	// Optional: The name or number of the port on the container to direct traffic to.
+	ContainerPort util.IntOrString `json:"containerPort,omitempty" description:"the port to access on the containers belonging to pods targeted by the service; defaults to the container's first open port"`
 }
 
 // EndpointObjectReference is a reference to an object exposing the endpoint
@@ -769,6 +849,39 @@ type Endpoints struct {
 	Endpoints []string `json:"endpoints" description:"list of endpoints corresponding to a service, of the form address:port, such as 10.10.1.1:1909"`
 	// Optional: The kubernetes object related to the entry point.
 	TargetRefs []EndpointObjectReference `json:"targetRefs,omitempty" description:"list of references to objects providing the endpoints"`
+	// Optional: Subsets is the set of all endpoints, grouped by common ports.  If it is set,
+	// Protocol, Endpoints and TargetRefs are ignored on input and describe the first port
+	// of each subset on output.
+	Subsets []EndpointSubset `json:"subsets,omitempty" description:"sets of addresses and ports that comprise a service; if specified, protocol, endpoints and targetRefs are ignored on input"`
+}
+
+// EndpointSubset is a group of addresses with a common set of ports.  The
+// expanded set of endpoints is the Cartesian product of Addresses x Ports.
+type EndpointSubset struct {
+	Addresses []EndpointAddress `json:"addresses,omitempty" description:"IP addresses which offer the related ports"`
+	Ports     []EndpointPort    `json:"ports,omitempty" description:"port numbers available on the related IP addresses"`
+}
+
+// EndpointAddress is a tuple that describes single IP address.
+type EndpointAddress struct {
+	// The IP of this endpoint.
+	IP string `json:"ip" description:"IP address of the endpoint"`
+
+	// Optional: The kubernetes object related to the entry point.
+	TargetRef *ObjectReference `json:"targetRef,omitempty" description:"reference to object providing the endpoint"`
+}
+
+// EndpointPort is a tuple that describes a single port.
+type EndpointPort struct {
+	// The name of this port (corresponds to ServicePort.Name).  Optional
+	// if only one port is defined.  Must be a DNS_LABEL.
+	Name string `json:"name,omitempty" description:"name of this port"`
+
+	// The port number.
+	Port int `json:"port" description:"port number of the endpoint"`
+
+	// The IP protocol for this port.
+	Protocol Protocol `json:"protocol,omitempty" description:"protocol for this port; must be UDP or TCP; TCP if unspecified"`
 }
 
 // EndpointsList is a list of endpoints.
@@ -794,7 +907,7 @@ type NodeStatus struct {
 	// NodePhase is the current lifecycle phase of the node.
 	Phase NodePhase `json:"phase,omitempty" description:"node phase is the current lifecycle phase of the node"`
 	// Conditions is an array of current node conditions.
-	Conditions []NodeCondition `json:"conditions,omitempty" description:"conditions is an array of current node conditions"`
+	Conditions []NodeCondition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"kind" description:"conditions is an array of current node conditions"`
 	// Queried from cloud provider, if available.
 	Addresses []NodeAddress `json:"addresses,omitempty" description:"list of addresses reachable to the node"`
 	// NodeSystemInfo is a set of ids/uuids to uniquely identify the node
@@ -840,6 +953,12 @@ const (
 	NodeReady NodeConditionKind = "Ready"
 	// NodeSchedulable means the node is ready to accept new pods.
 	NodeSchedulable NodeConditionKind = "Schedulable"
+	// NodeMemoryPressure means the memory available on the node is below the kubelet's
+	// eviction threshold, and pods are being evicted from it.
+	NodeMemoryPressure NodeConditionKind = "MemoryPressure"
+	// NodeDiskPressure means the disk space available on the node is below the kubelet's
+	// eviction threshold, and pods are being evicted from it.
+	NodeDiskPressure NodeConditionKind = "DiskPressure"
 )
 
 // Described the conditions of a running node.
@@ -935,6 +1054,18 @@ type NamespaceSpec struct {
 type NamespaceStatus struct {
 	// Phase is the current lifecycle phase of the namespace.
 	Phase NamespacePhase `json:"phase,omitempty" description:"phase is the current lifecycle phase of the namespace"`
+	// Remaining lists the resources that still have objects in a terminating namespace.
+	Remaining []NamespaceRemainingResource `json:"remaining,omitempty" description:"resources that still have objects in a terminating namespace"`
+	// Message describes the progress of the deletion of a terminating namespace.
+	Message string `json:"message,omitempty" description:"human readable progress of the deletion of a terminating namespace"`
+}
+
+// NamespaceRemainingResource is the number of objects of a resource left in a namespace.
+type NamespaceRemainingResource struct {
+	// Resource is the name of the resource, e.g. pods.
+	Resource string `json:"resource" description:"name of the resource"`
+	// Count is the number of objects of the resource found in the namespace.
+	Count int `json:"count" description:"number of objects of the resource found in the namespace"`
 }
 
 type NamespacePhase string
@@ -1161,6 +1292,11 @@ type ObjectReference struct {
 	FieldPath string `json:"fieldPath,omitempty" description:"if referring to a piece of an object instead of an entire object, this string should contain a valid JSON/Go field access statement, such as desiredState.manifest.containers[2]"`
 }
 
+// LocalObjectReference contains enough information to locate the referenced object inside the same namespace.
+type LocalObjectReference struct {
+	Name string `json:"name,omitempty" description:"name of the referent"`
+}
+
 // Event is a report of an event somewhere in the cluster.
 // TODO: Decide whether to store these separately or with the object they apply to.
 //
@@ -1233,15 +1369,24 @@ type ContainerManifest struct {
 	// with the API refactory. It is required for now to determine the instance
 	// of a Pod.
 	UUID          types.UID     `json:"uuid,omitempty" description:"manifest UUID; cannot be updated"`
-	Volumes       []Volume      `json:"volumes" description:"list of volumes that can be mounted by containers belonging to the pod"`
-	Containers    []Container   `json:"containers" description:"list of containers belonging to the pod; cannot be updated; containers cannot currently be added or removed"`
+	Volumes       []Volume      `json:"volumes" patchStrategy:"merge" patchMergeKey:"name" description:"list of volumes that can be mounted by containers belonging to the pod"`
+	Containers    []Container   `json:"containers" patchStrategy:"merge" patchMergeKey:"name" description:"list of containers belonging to the pod; cannot be updated; containers cannot currently be added or removed"`
 	RestartPolicy RestartPolicy `json:"restartPolicy,omitempty" description:"restart policy for all containers within the pod; one of RestartPolicyAlways, RestartPolicyOnFailure, RestartPolicyNever"`
+	// Optional duration in seconds the pod needs to terminate gracefully. May be decreased in delete request.
+	// Value must be non-negative integer. The value zero indicates delete immediately.
+	// If this value is nil, the default grace period will be used instead.
+	// The grace period is the duration in seconds after the processes running in the pod are sent
+	// a termination signal and the time when the processes are forcibly halted with a kill signal.
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty" description:"optional duration in seconds the pod needs to terminate gracefully; may be decreased in delete request; value must be non-negative integer; the value zero indicates delete immediately; if this value is not set, the default grace period will be used instead"`
 	// Optional: Set DNS policy.  Defaults to "ClusterFirst"
 	DNSPolicy DNSPolicy `json:"dnsPolicy,omitempty" description:"DNS policy for containers within the pod; one of 'ClusterFirst' or 'Default'"`
 	// Uses the host's network namespace. If this option is set, the ports that will be
 	// used must be specified.
 	// Optional: Default to false.
 	HostNetwork bool `json:"hostNetwork,omitempty" description:"host networking requested for this pod"`
+	// ImagePullSecrets is a list of references to secrets in the same namespace to use for pulling
+	// the images of the pod's containers.
+	ImagePullSecrets []LocalObjectReference `json:"imagePullSecrets,omitempty" description:"list of references to secrets of type kubernetes.io/dockercfg in the same namespace to use for pulling any of the images used by this pod"`
 }
 
 // ContainerManifestList is used to communicate container manifests to kubelet.
@@ -1267,16 +1412,47 @@ const (
 	DNSDefault DNSPolicy = "Default"
 )
 
+// LabelSelectorOperator is the relationship between a label key and the set of values
+// in a LabelSelectorRequirement.
+type LabelSelectorOperator string
+
+const (
+	LabelSelectorOpIn     LabelSelectorOperator = "In"
+	LabelSelectorOpNotIn  LabelSelectorOperator = "NotIn"
+	LabelSelectorOpExists LabelSelectorOperator = "Exists"
+)
+
+// LabelSelectorRequirement is a set-based label selector requirement. It is ANDed with
+// any other requirements and exact match labels of the selector it belongs to.
+type LabelSelectorRequirement struct {
+	// Key is the label key that the requirement applies to.
+	Key string `json:"key" description:"label key that the requirement applies to"`
+	// Operator represents the key's relationship to the set of values.
+	Operator LabelSelectorOperator `json:"operator" description:"relationship of the key to the set of values; one of In, NotIn or Exists"`
+	// Values is the set of values for the In and NotIn operators. It must be empty
+	// for the Exists operator.
+	Values []string `json:"values,omitempty" description:"set of values for the In and NotIn operators; must be empty for Exists"`
+}
+
 // PodSpec is a description of a pod
 type PodSpec struct {
-	Volumes []Volume `json:"volumes" description:"list of volumes that can be mounted by containers belonging to the pod"`
+	Volumes []Volume `json:"volumes" patchStrategy:"merge" patchMergeKey:"name" description:"list of volumes that can be mounted by containers belonging to the pod"`
 	// Required: there must be at least one container in a pod.
-	Containers    []Container   `json:"containers" description:"list of containers belonging to the pod; containers cannot currently be added or removed; there must be at least one container in a Pod"`
+	Containers    []Container   `json:"containers" patchStrategy:"merge" patchMergeKey:"name" description:"list of containers belonging to the pod; containers cannot currently be added or removed; there must be at least one container in a Pod"`
 	RestartPolicy RestartPolicy `json:"restartPolicy,omitempty" description:"restart policy for all containers within the pod; one of RestartPolicyAlways, RestartPolicyOnFailure, RestartPolicyNever"`
+	// Optional duration in seconds the pod needs to terminate gracefully. May be decreased in delete request.
+	// Value must be non-negative integer. The value zero indicates delete immediately.
+	// If this value is nil, the default grace period will be used instead.
+	// The grace period is the duration in seconds after the processes running in the pod are sent
+	// a termination signal and the time when the processes are forcibly halted with a kill signal.
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty" description:"optional duration in seconds the pod needs to terminate gracefully; may be decreased in delete request; value must be non-negative integer; the value zero indicates delete immediately; if this value is not set, the default grace period will be used instead"`
 	// Optional: Set DNS policy.  Defaults to "ClusterFirst"
 	DNSPolicy DNSPolicy `json:"dnsPolicy,omitempty" description:"DNS policy for containers within the pod; one of 'ClusterFirst' or 'Default'"`
 	// NodeSelector is a selector which must be true for the pod to fit on a node
 	NodeSelector map[string]string `json:"nodeSelector,omitempty" description:"selector which must match a node's labels for the pod to be scheduled on that node"`
+	// NodeSelectorRequirements are set-based requirements which must also be true for the
+	// pod to fit on a node.
+	NodeSelectorRequirements []LabelSelectorRequirement `json:"nodeSelectorRequirements,omitempty" description:"set-based requirements which must also match a node's labels for the pod to be scheduled on that node"`
 
 	// Host is a request to schedule this pod onto a specific host.  If it is non-empty,
 	// the the scheduler simply schedules this pod onto that host, assuming that it fits
@@ -1286,6 +1462,9 @@ type PodSpec struct {
 	// used must be specified.
 	// Optional: Default to false.
 	HostNetwork bool `json:"hostNetwork,omitempty" description:"host networking requested for this pod"`
+	// ImagePullSecrets is a list of references to secrets in the same namespace to use for pulling
+	// the images of the pod's containers.
+	ImagePullSecrets []LocalObjectReference `json:"imagePullSecrets,omitempty" description:"list of references to secrets of type kubernetes.io/dockercfg in the same namespace to use for pulling any of the images used by this pod"`
 }
 
 // List holds a list of objects, which may not be known by the server.
@@ -1346,6 +1525,10 @@ const (
 	ResourceReplicationControllers ResourceName = "replicationcontrollers"
 	// ResourceQuotas, number
 	ResourceQuotas ResourceName = "resourcequotas"
+	// Secrets, number
+	ResourceSecrets ResourceName = "secrets"
+	// PersistentVolumeClaims, number
+	ResourcePersistentVolumeClaims ResourceName = "persistentvolumeclaims"
 )
 
 // ResourceQuotaSpec defines the desired hard limits to enforce for Quota
@@ -1416,6 +1599,29 @@ type SecretType string
 
 const (
 	SecretTypeOpaque SecretType = "Opaque" // Default; arbitrary user-defined data
+
+	// SecretTypeDockercfg contains a dockercfg file that follows the same format rules as ~/.dockercfg
+	//
+	// Required fields:
+	// - Secret.Data[".dockercfg"] - a serialized ~/.dockercfg file
+	SecretTypeDockercfg SecretType = "kubernetes.io/dockercfg"
+
+	// SecretTypeTLS contains information about a TLS client or server secret.
+	//
+	// Required fields:
+	// - Secret.Data["tls.crt"] - PEM encoded TLS certificate
+	// - Secret.Data["tls.key"] - PEM encoded TLS private key
+	SecretTypeTLS SecretType = "kubernetes.io/tls"
+)
+
+const (
+	// DockerConfigKey is the key of the required data for SecretTypeDockercfg secrets
+	DockerConfigKey = ".dockercfg"
+
+	// TLSCertKey is the key for the TLS certificate of SecretTypeTLS secrets
+	TLSCertKey = "tls.crt"
+	// TLSPrivateKeyKey is the key for the TLS private key of SecretTypeTLS secrets
+	TLSPrivateKeyKey = "tls.key"
 )
 
 type SecretList struct {
@@ -1423,3 +1629,104 @@ type SecretList struct {
 
 	Items []Secret `json:"items" description:"items is a list of secret objects"`
 }
+
+// PolicyRule holds information that describes a policy rule of a role.
+type PolicyRule struct {
+	// Verbs is a list of verbs that apply to all of the resources, e.g. get, list, watch,
+	// create, update, delete or proxy.  "*" represents all verbs.
+	Verbs []string `json:"verbs" description:"list of verbs that apply to all of the resources; * represents all verbs"`
+	// Resources is a list of resources this rule applies to.  A subresource is written
+	// as "pods/status".  "*" represents all resources.
+	Resources []string `json:"resources" description:"list of resources the rule applies to; a subresource is written as pods/status; * represents all resources"`
+	// ResourceNames is an optional list of the names the rule applies to.  An empty list
+	// means the rule applies to every name.
+	ResourceNames []string `json:"resourceNames,omitempty" description:"optional list of the names the rule applies to; empty means every name"`
+}
+
+// SubjectKind is the kind of a subject a role is granted to.
+type SubjectKind string
+
+const (
+	// UserKind subjects are matched against the name of the user.
+	UserKind SubjectKind = "User"
+	// GroupKind subjects are matched against the groups of the user.
+	GroupKind SubjectKind = "Group"
+)
+
+// Subject is a user or a group a role is granted to.
+type Subject struct {
+	Kind SubjectKind `json:"kind" description:"kind of the subject or role"`
+	Name string      `json:"name" description:"name of the subject or role"`
+}
+
+// RoleRef references the role granted by a binding.  A RoleBinding may reference a Role
+// of its namespace or a ClusterRole, a ClusterRoleBinding may only reference a ClusterRole.
+type RoleRef struct {
+	// Kind is either "Role" or "ClusterRole".
+	Kind string `json:"kind" description:"kind of the subject or role"`
+	Name string `json:"name" description:"name of the subject or role"`
+}
+
+// Role is a namespaced set of policy rules.
+type Role struct {
+	TypeMeta `json:",inline"`
+
+	// Rules holds all of the policy rules of this role.
+	Rules []PolicyRule `json:"rules" description:"policy rules of the role"`
+}
+
+// RoleList is a list of Roles.
+type RoleList struct {
+	TypeMeta `json:",inline"`
+
+	Items []Role `json:"items" description:"items is a list of Role objects"`
+}
+
+// RoleBinding grants the policy rules of a role to subjects, within the namespace of the binding.
+type RoleBinding struct {
+	TypeMeta `json:",inline"`
+
+	// Subjects holds the users and groups the role is granted to.
+	Subjects []Subject `json:"subjects" description:"users and groups the role is granted to"`
+	// RoleRef references a Role of the namespace of the binding or a ClusterRole.
+	RoleRef RoleRef `json:"roleRef" description:"reference to the granted role"`
+}
+
+// RoleBindingList is a list of RoleBindings.
+type RoleBindingList struct {
+	TypeMeta `json:",inline"`
+
+	Items []RoleBinding `json:"items" description:"items is a list of RoleBinding objects"`
+}
+
+// ClusterRole is a cluster-wide set of policy rules.
+type ClusterRole struct {
+	TypeMeta `json:",inline"`
+
+	// Rules holds all of the policy rules of this role.
+	Rules []PolicyRule `json:"rules" description:"policy rules of the role"`
+}
+
+// ClusterRoleList is a list of ClusterRoles.
+type ClusterRoleList struct {
+	TypeMeta `json:",inline"`
+
+	Items []ClusterRole `json:"items" description:"items is a list of ClusterRole objects"`
+}
+
+// ClusterRoleBinding grants the policy rules of a ClusterRole to subjects, in every namespace.
+type ClusterRoleBinding struct {
+	TypeMeta `json:",inline"`
+
+	// Subjects holds the users and groups the role is granted to.
+	Subjects []Subject `json:"subjects" description:"users and groups the role is granted to"`
+	// RoleRef references a ClusterRole.
+	RoleRef RoleRef `json:"roleRef" description:"reference to the granted role"`
+}
+
+// ClusterRoleBindingList is a list of ClusterRoleBindings.
+type ClusterRoleBindingList struct {
+	TypeMeta `json:",inline"`
+
+	Items []ClusterRoleBinding `json:"items" description:"items is a list of ClusterRoleBinding objects"`
+}
@@ -20,6 +20,8 @@ import (
 	"fmt"
 
 	newer "github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/endpoints"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/conversion"
 )
 
 func init() {
@@ -73,4 +75,127 @@ func init() {
 		// If one of the conversion functions is malformed, detect it immediately.
 		panic(err)
 	}
+	err = newer.Scheme.AddConversionFuncs(
+		// The single port fields of a ServiceSpec describe its first port.
+		func(in *newer.ServiceSpec, out *ServiceSpec, s conversion.Scope) error {
+			if err := s.Convert(&in.Ports, &out.Ports, 0); err != nil {
+				return err
+			}
+			if len(out.Ports) > 0 {
+				out.Port = out.Ports[0].Port
+				out.Protocol = out.Ports[0].Protocol
+				out.TargetPort = out.Ports[0].TargetPort
+			}
+			if err := s.Convert(&in.Selector, &out.Selector, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.SelectorRequirements, &out.SelectorRequirements, 0); err != nil {
+				return err
+			}
+			out.PortalIP = in.PortalIP
+			out.CreateExternalLoadBalancer = in.CreateExternalLoadBalancer
+			if err := s.Convert(&in.PublicIPs, &out.PublicIPs, 0); err != nil {
+				return err
+			}
+			out.SessionAffinity = AffinityType(in.SessionAffinity)
+			return nil
+		},
+		func(in *ServiceSpec, out *newer.ServiceSpec, s conversion.Scope) error {
+			if len(in.Ports) > 0 {
+				if err := s.Convert(&in.Ports, &out.Ports, 0); err != nil {
+					return err
+				}
+			} else if in.Port != 0 {
+				out.Ports = []newer.ServicePort{{
+					Protocol:   newer.Protocol(in.Protocol),
+					Port:       in.Port,
+					TargetPort: in.TargetPort,
+				}}
+			}
+			if err := s.Convert(&in.Selector, &out.Selector, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.SelectorRequirements, &out.SelectorRequirements, 0); err != nil {
+				return err
+			}
+			out.PortalIP = in.PortalIP
+			out.CreateExternalLoadBalancer = in.CreateExternalLoadBalancer
+			if err := s.Convert(&in.PublicIPs, &out.PublicIPs, 0); err != nil {
+				return err
+			}
+			out.SessionAffinity = newer.AffinityType(in.SessionAffinity)
+			return nil
+		},
+
+		// The legacy fields of Endpoints describe the first port of the first subset.
+		func(in *newer.Endpoints, out *Endpoints, s conversion.Scope) error {
+			if err := s.Convert(&in.TypeMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.ObjectMeta, &out.ObjectMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Subsets, &out.Subsets, 0); err != nil {
+				return err
+			}
+			if len(in.Subsets) == 0 || len(in.Subsets[0].Ports) == 0 {
+				out.Protocol = ProtocolTCP
+				return nil
+			}
+			portName := in.Subsets[0].Ports[0].Name
+			out.Protocol = Protocol(in.Subsets[0].Ports[0].Protocol)
+			for i := range in.Subsets {
+				ss := &in.Subsets[i]
+				for j := range ss.Ports {
+					if ss.Ports[j].Name != portName {
+						continue
+					}
+					for k := range ss.Addresses {
+						ep := Endpoint{IP: ss.Addresses[k].IP, Port: ss.Ports[j].Port}
+						if ss.Addresses[k].TargetRef != nil {
+							ep.TargetRef = &ObjectReference{}
+							if err := s.Convert(ss.Addresses[k].TargetRef, ep.TargetRef, 0); err != nil {
+								return err
+							}
+						}
+						out.Endpoints = append(out.Endpoints, ep)
+					}
+				}
+			}
+			return nil
+		},
+		func(in *Endpoints, out *newer.Endpoints, s conversion.Scope) error {
+			if err := s.Convert(&in.TypeMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.ObjectMeta, &out.ObjectMeta, 0); err != nil {
+				return err
+			}
+			if len(in.Subsets) > 0 {
+				return s.Convert(&in.Subsets, &out.Subsets, 0)
+			}
+			// Build one subset per legacy endpoint, then collapse them.
+			for i := range in.Endpoints {
+				addr := newer.EndpointAddress{IP: in.Endpoints[i].IP}
+				if in.Endpoints[i].TargetRef != nil {
+					addr.TargetRef = &newer.ObjectReference{}
+					if err := s.Convert(in.Endpoints[i].TargetRef, addr.TargetRef, 0); err != nil {
+						return err
+					}
+				}
+				out.Subsets = append(out.Subsets, newer.EndpointSubset{
+					Addresses: []newer.EndpointAddress{addr},
+					Ports:     []newer.EndpointPort{{Port: in.Endpoints[i].Port, Protocol: newer.Protocol(in.Protocol)}},
+				})
+			}
+			if len(out.Subsets) > 0 {
+				out.Subsets = endpoints.RepackSubsets(out.Subsets)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		// If one of the conversion functions is malformed, detect it immediately.
+		panic(err)
+	}
 }
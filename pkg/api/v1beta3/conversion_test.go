@@ -45,3 +45,36 @@ func TestNodeConversion(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestServicePortsConversion(t *testing.T) {
+	// A service which only sets the legacy fields gets a single port.
+	obj, err := current.Codec.Decode([]byte(`{"kind":"Service","apiVersion":"v1beta3","metadata":{"name":"foo"},"spec":{"port":80,"protocol":"UDP","targetPort":8080}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	svc, ok := obj.(*newer.Service)
+	if !ok {
+		t.Fatalf("unexpected type: %#v", obj)
+	}
+	if len(svc.Spec.Ports) != 1 {
+		t.Fatalf("expected one port, got %#v", svc.Spec.Ports)
+	}
+	port := svc.Spec.Ports[0]
+	if port.Port != 80 || port.Protocol != newer.ProtocolUDP || port.TargetPort.IntVal != 8080 {
+		t.Errorf("unexpected port: %#v", port)
+	}
+
+	// The legacy fields mirror the first port.
+	svc.Spec.Ports = append(svc.Spec.Ports, newer.ServicePort{Name: "other", Port: 81, Protocol: newer.ProtocolTCP})
+	data, err := current.Codec.Encode(svc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := &current.Service{}
+	if err := current.Codec.DecodeInto(data, out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Spec.Port != 80 || out.Spec.Protocol != current.ProtocolUDP || len(out.Spec.Ports) != 2 {
+		t.Errorf("unexpected service spec: %#v", out.Spec)
+	}
+}
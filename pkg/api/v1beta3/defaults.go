@@ -51,6 +51,7 @@ func init() {
 			if obj.TerminationMessagePath == "" {
 				obj.TerminationMessagePath = TerminationMessagePathDefault
 			}
+			defaultResourceRequests(obj)
 		},
 		func(obj *Service) {
 			if obj.Spec.Protocol == "" {
@@ -86,6 +87,20 @@ func init() {
 				obj.Protocol = "TCP"
 			}
 		},
+		func(obj *ServicePort) {
+			if obj.Protocol == "" {
+				obj.Protocol = ProtocolTCP
+			}
+			if obj.TargetPort.Kind == util.IntstrInt && obj.TargetPort.IntVal == 0 ||
+				obj.TargetPort.Kind == util.IntstrString && obj.TargetPort.StrVal == "" {
+				obj.TargetPort = util.NewIntOrStringFromInt(obj.Port)
+			}
+		},
+		func(obj *EndpointPort) {
+			if obj.Protocol == "" {
+				obj.Protocol = ProtocolTCP
+			}
+		},
 		func(obj *HTTPGetAction) {
 			if obj.Path == "" {
 				obj.Path = "/"
@@ -115,3 +130,21 @@ func defaultHostNetworkPorts(containers *[]Container) {
 		}
 	}
 }
+
+// defaultResourceRequests sets the request of a container for cpu and memory to
+// its limit, unless the request is set.
+func defaultResourceRequests(container *Container) {
+	for _, name := range []ResourceName{ResourceCPU, ResourceMemory} {
+		limit, found := container.Resources.Limits[name]
+		if !found {
+			continue
+		}
+		if _, found := container.Resources.Requests[name]; found {
+			continue
+		}
+		if container.Resources.Requests == nil {
+			container.Resources.Requests = ResourceList{}
+		}
+		container.Resources.Requests[name] = limit
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
 	current "github.com/GoogleCloudPlatform/kubernetes/pkg/api/v1beta3"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
@@ -88,6 +89,30 @@ func TestSetDefaulServiceTargetPort(t *testing.T) {
 	}
 }
 
+func TestSetDefaultServicePort(t *testing.T) {
+	in := &current.Service{Spec: current.ServiceSpec{
+		Ports: []current.ServicePort{
+			{Protocol: "UDP", Port: 9376, TargetPort: util.NewIntOrStringFromString("p")},
+			{Protocol: "UDP", Port: 8675},
+			{Port: 309},
+		},
+	}}
+	obj := roundTrip(t, runtime.Object(in))
+	out := obj.(*current.Service)
+	if out.Spec.Ports[0].Protocol != current.ProtocolUDP {
+		t.Errorf("Expected protocol %s, got %s", current.ProtocolUDP, out.Spec.Ports[0].Protocol)
+	}
+	if out.Spec.Ports[0].TargetPort != in.Spec.Ports[0].TargetPort {
+		t.Errorf("Expected TargetPort to be unchanged, got %v", out.Spec.Ports[0].TargetPort)
+	}
+	if out.Spec.Ports[1].TargetPort != util.NewIntOrStringFromInt(8675) {
+		t.Errorf("Expected TargetPort to be defaulted, got %v", out.Spec.Ports[1].TargetPort)
+	}
+	if out.Spec.Ports[2].Protocol != current.ProtocolTCP {
+		t.Errorf("Expected protocol %s, got %s", current.ProtocolTCP, out.Spec.Ports[2].Protocol)
+	}
+}
+
 func TestSetDefaultNamespace(t *testing.T) {
 	s := &current.Namespace{}
 	obj2 := roundTrip(t, runtime.Object(s))
@@ -123,3 +148,37 @@ func TestSetDefaultPodSpecHostNetwork(t *testing.T) {
 		t.Errorf("Expected container port to be defaulted, was made %d instead of %d", hostPortNum, portNum)
 	}
 }
+
+func TestSetDefaultContainerResourceRequests(t *testing.T) {
+	pod := &current.Pod{
+		Spec: current.PodSpec{
+			Containers: []current.Container{
+				{
+					Resources: current.ResourceRequirements{
+						Limits: current.ResourceList{
+							current.ResourceCPU:    resource.MustParse("500m"),
+							current.ResourceMemory: resource.MustParse("2Gi"),
+						},
+						Requests: current.ResourceList{
+							current.ResourceMemory: resource.MustParse("1Gi"),
+						},
+					},
+				},
+				{},
+			},
+		},
+	}
+	obj2 := roundTrip(t, runtime.Object(pod))
+	pod2 := obj2.(*current.Pod)
+
+	requests := pod2.Spec.Containers[0].Resources.Requests
+	if cpu := requests[current.ResourceCPU]; cpu.String() != "500m" {
+		t.Errorf("Expected cpu request to default to the cpu limit, got %v", cpu.String())
+	}
+	if memory := requests[current.ResourceMemory]; memory.String() != "1Gi" {
+		t.Errorf("Expected memory request to be kept, got %v", memory.String())
+	}
+	if requests := pod2.Spec.Containers[1].Resources.Requests; len(requests) != 0 {
+		t.Errorf("Expected no requests without limits, got %v", requests)
+	}
+}
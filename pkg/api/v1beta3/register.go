@@ -24,6 +24,10 @@ import (
 // Codec encodes internal objects to the v1beta3 scheme
 var Codec = runtime.CodecFor(api.Scheme, "v1beta3")
 
+// ProtobufCodec encodes internal objects to the v1beta3 scheme in the protobuf
+// wire format, using the field numbers in the protobuf tags of the v1beta3 types.
+var ProtobufCodec = runtime.ProtobufCodecFor(api.Scheme, "v1beta3")
+
 func init() {
 	api.Scheme.AddKnownTypes("v1beta3",
 		&Pod{},
@@ -58,6 +62,14 @@ func init() {
 		&PersistentVolumeList{},
 		&PersistentVolumeClaim{},
 		&PersistentVolumeClaimList{},
+		&Role{},
+		&RoleList{},
+		&RoleBinding{},
+		&RoleBindingList{},
+		&ClusterRole{},
+		&ClusterRoleList{},
+		&ClusterRoleBinding{},
+		&ClusterRoleBindingList{},
 	)
 	// Legacy names are supported
 	api.Scheme.AddKnownTypeWithName("v1beta3", "Minion", &Node{})
@@ -95,4 +107,12 @@ func (*PersistentVolume) IsAnAPIObject()          {}
 func (*PersistentVolumeList) IsAnAPIObject()      {}
 func (*PersistentVolumeClaim) IsAnAPIObject()     {}
 func (*PersistentVolumeClaimList) IsAnAPIObject() {}
+func (*Role) IsAnAPIObject()                      {}
+func (*RoleList) IsAnAPIObject()                  {}
+func (*RoleBinding) IsAnAPIObject()               {}
+func (*RoleBindingList) IsAnAPIObject()           {}
+func (*ClusterRole) IsAnAPIObject()               {}
+func (*ClusterRoleList) IsAnAPIObject()           {}
+func (*ClusterRoleBinding) IsAnAPIObject()        {}
+func (*ClusterRoleBindingList) IsAnAPIObject()    {}
 func (*DeleteOptions) IsAnAPIObject()             {}
@@ -46,31 +46,37 @@ import (
 //     or more simply:
 //         DNS_LABEL(\.DNS_LABEL)*
 
+// Protobuf field numbers
+// ----------------------
+// Every field carries its field number for the protobuf encoding in a protobuf
+// tag.  The numbers are part of the wire format: never reuse or renumber them,
+// and give a new field the next unused number of its struct.
+
 // TypeMeta describes an individual object in an API response or request
 // with strings representing the type of the object and its API schema version.
 // Structures that are versioned or persisted should inline TypeMeta.
 type TypeMeta struct {
 	// Kind is a string value representing the REST resource this object represents.
 	// Servers may infer this from the endpoint the client submits requests to.
-	Kind string `json:"kind,omitempty" description:"kind of object, in CamelCase; cannot be updated"`
+	Kind string `json:"kind,omitempty" description:"kind of object, in CamelCase; cannot be updated" protobuf:"1"`
 
 	// APIVersion defines the versioned schema of this representation of an object.
 	// Servers should convert recognized schemas to the latest internal value, and
 	// may reject unrecognized values.
-	APIVersion string `json:"apiVersion,omitempty" description:"version of the schema the object should have"`
+	APIVersion string `json:"apiVersion,omitempty" description:"version of the schema the object should have" protobuf:"2"`
 }
 
 // ListMeta describes metadata that synthetic resources must have, including lists and
 // various status objects.
 type ListMeta struct {
 	// SelfLink is a URL representing this object.
-	SelfLink string `json:"selfLink,omitempty" description:"URL for the object; populated by the system, read-only"`
+	SelfLink string `json:"selfLink,omitempty" description:"URL for the object; populated by the system, read-only" protobuf:"1"`
 
 	// An opaque value that represents the version of this response for use with optimistic
 	// concurrency and change monitoring endpoints.  Clients must treat these values as opaque
 	// and values may only be valid for a particular resource or set of resources. Only servers
 	// will generate resource versions.
-	ResourceVersion string `json:"resourceVersion,omitempty" description:"string that identifies the internal version of this object that can be used by clients to determine when objects have changed; populated by the system, read-only; value must be treated as opaque by clients and passed unmodified back to the server: https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#concurrency-control-and-consistency"`
+	ResourceVersion string `json:"resourceVersion,omitempty" description:"string that identifies the internal version of this object that can be used by clients to determine when objects have changed; populated by the system, read-only; value must be treated as opaque by clients and passed unmodified back to the server: https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#concurrency-control-and-consistency" protobuf:"2"`
 }
 
 // ObjectMeta is metadata that all persisted resources must have, which includes all objects
@@ -80,7 +86,7 @@ type ObjectMeta struct {
 	// some resources may allow a client to request the generation of an appropriate name
 	// automatically. Name is primarily intended for creation idempotence and configuration
 	// definition.
-	Name string `json:"name,omitempty" description:"string that identifies an object. Must be unique within a namespace; cannot be updated"`
+	Name string `json:"name,omitempty" description:"string that identifies an object. Must be unique within a namespace; cannot be updated" protobuf:"1"`
 
 	// GenerateName indicates that the name should be made unique by the server prior to persisting
 	// it. A non-empty value for the field indicates the name will be made unique (and the name
@@ -93,32 +99,32 @@ type ObjectMeta struct {
 	// generated name exists - instead, it will either return 201 Created or 500 with Reason
 	// ServerTimeout indicating a unique name could not be found in the time allotted, and the client
 	// should retry (optionally after the time indicated in the Retry-After header).
-	GenerateName string `json:"generateName,omitempty" description:"an optional prefix to use to generate a unique name; has the same validation rules as name; optional, and is applied only name if is not specified"`
+	GenerateName string `json:"generateName,omitempty" description:"an optional prefix to use to generate a unique name; has the same validation rules as name; optional, and is applied only name if is not specified" protobuf:"2"`
 
 	// Namespace defines the space within which name must be unique. An empty namespace is
 	// equivalent to the "default" namespace, but "default" is the canonical representation.
 	// Not all objects are required to be scoped to a namespace - the value of this field for
 	// those objects will be empty.
-	Namespace string `json:"namespace,omitempty" description:"namespace of the object; cannot be updated"`
+	Namespace string `json:"namespace,omitempty" description:"namespace of the object; cannot be updated" protobuf:"3"`
 
 	// SelfLink is a URL representing this object.
-	SelfLink string `json:"selfLink,omitempty" description:"URL for the object; populated by the system, read-only"`
+	SelfLink string `json:"selfLink,omitempty" description:"URL for the object; populated by the system, read-only" protobuf:"4"`
 
 	// UID is the unique in time and space value for this object. It is typically generated by
 	// the server on successful creation of a resource and is not allowed to change on PUT
 	// operations.
-	UID types.UID `json:"uid,omitempty" description:"unique UUID across space and time; populated by the system; read-only"`
+	UID types.UID `json:"uid,omitempty" description:"unique UUID across space and time; populated by the system; read-only" protobuf:"5"`
 
 	// An opaque value that represents the version of this resource. May be used for optimistic
 	// concurrency, change detection, and the watch operation on a resource or set of resources.
 	// Clients must treat these values as opaque and values may only be valid for a particular
 	// resource or set of resources. Only servers will generate resource versions.
-	ResourceVersion string `json:"resourceVersion,omitempty" description:"string that identifies the internal version of this object that can be used by clients to determine when objects have changed; populated by the system, read-only; value must be treated as opaque by clients and passed unmodified back to the server: https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#concurrency-control-and-consistency"`
+	ResourceVersion string `json:"resourceVersion,omitempty" description:"string that identifies the internal version of this object that can be used by clients to determine when objects have changed; populated by the system, read-only; value must be treated as opaque by clients and passed unmodified back to the server: https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#concurrency-control-and-consistency" protobuf:"6"`
 
 	// CreationTimestamp is a timestamp representing the server time when this object was
 	// created. It is not guaranteed to be set in happens-before order across separate operations.
 	// Clients may not set this value. It is represented in RFC3339 form and is in UTC.
-	CreationTimestamp util.Time `json:"creationTimestamp,omitempty" description:"RFC 3339 date and time at which the object was created; populated by the system, read-only; null for lists"`
+	CreationTimestamp util.Time `json:"creationTimestamp,omitempty" description:"RFC 3339 date and time at which the object was created; populated by the system, read-only; null for lists" protobuf:"7"`
 
 	// DeletionTimestamp is the time after which this resource will be deleted. This
 	// field is set by the server when a graceful deletion is requested by the user, and is not
@@ -129,16 +135,21 @@ type ObjectMeta struct {
 	// a pod is deleted in 30 seconds. The Kubelet will react by sending a graceful termination
 	// signal to the containers in the pod. Once the resource is deleted in the API, the Kubelet
 	// will send a hard termination signal to the container.
-	DeletionTimestamp *util.Time `json:"deletionTimestamp,omitempty" description:"RFC 3339 date and time at which the object will be deleted; populated by the system when a graceful deletion is requested, read-only; if not set, graceful deletion of the object has not been requested"`
+	DeletionTimestamp *util.Time `json:"deletionTimestamp,omitempty" description:"RFC 3339 date and time at which the object will be deleted; populated by the system when a graceful deletion is requested, read-only; if not set, graceful deletion of the object has not been requested" protobuf:"8"`
+
+	// DeletionGracePeriodSeconds is the number of seconds allowed for this object to
+	// terminate gracefully before it is removed from the system. It is only set when
+	// DeletionTimestamp is also set, and may only be shortened. Read-only.
+	DeletionGracePeriodSeconds *int64 `json:"deletionGracePeriodSeconds,omitempty" description:"number of seconds allowed for this object to gracefully terminate before it will be removed from the system; only set when deletionTimestamp is also set, may only be shortened; read-only" protobuf:"9"`
 
 	// Labels are key value pairs that may be used to scope and select individual resources.
 	// TODO: replace map[string]string with labels.LabelSet type
-	Labels map[string]string `json:"labels,omitempty" description:"map of string keys and values that can be used to organize and categorize objects; may match selectors of replication controllers and services"`
+	Labels map[string]string `json:"labels,omitempty" description:"map of string keys and values that can be used to organize and categorize objects; may match selectors of replication controllers and services" protobuf:"10"`
 
 	// Annotations are unstructured key value data stored with a resource that may be set by
 	// external tooling. They are not queryable and should be preserved when modifying
 	// objects.
-	Annotations map[string]string `json:"annotations,omitempty" description:"map of string keys and values that can be used by external tooling to store and retrieve arbitrary metadata about objects"`
+	Annotations map[string]string `json:"annotations,omitempty" description:"map of string keys and values that can be used by external tooling to store and retrieve arbitrary metadata about objects" protobuf:"11"`
 }
 
 const (
@@ -184,11 +195,11 @@ const (
 type Volume struct {
 	// Required: This must be a DNS_LABEL.  Each volume in a pod must have
 	// a unique name.
-	Name string `json:"name" description:"volume name; must be a DNS_LABEL and unique within the pod"`
+	Name string `json:"name" description:"volume name; must be a DNS_LABEL and unique within the pod" protobuf:"1"`
 	// Source represents the location and type of a volume to mount.
 	// This is optional for now. If not specified, the Volume is implied to be an EmptyDir.
 	// This implied behavior is deprecated and will be removed in a future version.
-	VolumeSource `json:",inline,omitempty"`
+	VolumeSource `json:",inline,omitempty" protobuf:"2"`
 }
 
 // VolumeSource represents the source location of a volume to mount.
@@ -200,18 +211,20 @@ type VolumeSource struct {
 	// to see the host machine. Most containers will NOT need this.
 	// TODO(jonesdl) We need to restrict who can use host directory mounts and who can/can not
 	// mount host directories as read/write.
-	HostPath *HostPathVolumeSource `json:"hostPath" description:"pre-existing host file or directory; generally for privileged system daemons or other agents tied to the host"`
+	HostPath *HostPathVolumeSource `json:"hostPath" description:"pre-existing host file or directory; generally for privileged system daemons or other agents tied to the host" protobuf:"1"`
 	// EmptyDir represents a temporary directory that shares a pod's lifetime.
-	EmptyDir *EmptyDirVolumeSource `json:"emptyDir" description:"temporary directory that shares a pod's lifetime"`
+	EmptyDir *EmptyDirVolumeSource `json:"emptyDir" description:"temporary directory that shares a pod's lifetime" protobuf:"2"`
 	// GCEPersistentDisk represents a GCE Disk resource that is attached to a
 	// kubelet's host machine and then exposed to the pod.
-	GCEPersistentDisk *GCEPersistentDiskVolumeSource `json:"gcePersistentDisk" description:"GCE disk resource attached to the host machine on demand"`
+	GCEPersistentDisk *GCEPersistentDiskVolumeSource `json:"gcePersistentDisk" description:"GCE disk resource attached to the host machine on demand" protobuf:"3"`
 	// GitRepo represents a git repository at a particular revision.
-	GitRepo *GitRepoVolumeSource `json:"gitRepo" description:"git repository at a particular revision"`
+	GitRepo *GitRepoVolumeSource `json:"gitRepo" description:"git repository at a particular revision" protobuf:"4"`
 	// Secret represents a secret that should populate this volume.
-	Secret *SecretVolumeSource `json:"secret" description:"secret to populate volume"`
+	Secret *SecretVolumeSource `json:"secret" description:"secret to populate volume" protobuf:"5"`
 	// NFS represents an NFS mount on the host that shares a pod's lifetime
-	NFS *NFSVolumeSource `json:"nfs" description:"NFS volume that will be mounted in the host machine"`
+	NFS *NFSVolumeSource `json:"nfs" description:"NFS volume that will be mounted in the host machine" protobuf:"6"`
+	// DownwardAPI represents metadata about the pod that should populate this volume
+	DownwardAPI *DownwardAPIVolumeSource `json:"downwardAPI,omitempty" description:"metadata about the pod that should populate this volume" protobuf:"7"`
 }
 
 // Similar to VolumeSource but meant for the administrator who creates PVs.
@@ -219,81 +232,81 @@ type VolumeSource struct {
 type PersistentVolumeSource struct {
 	// GCEPersistentDisk represents a GCE Disk resource that is attached to a
 	// kubelet's host machine and then exposed to the pod.
-	GCEPersistentDisk *GCEPersistentDiskVolumeSource `json:"gcePersistentDisk" description:"GCE disk resource provisioned by an admin"`
+	GCEPersistentDisk *GCEPersistentDiskVolumeSource `json:"gcePersistentDisk" description:"GCE disk resource provisioned by an admin" protobuf:"1"`
 	// HostPath represents a directory on the host.
 	// This is useful for development and testing only.
 	// on-host storage is not supported in any way.
-	HostPath *HostPathVolumeSource `json:"hostPath" description:"a HostPath provisioned by a developer or tester; for develment use only"`
+	HostPath *HostPathVolumeSource `json:"hostPath" description:"a HostPath provisioned by a developer or tester; for develment use only" protobuf:"2"`
 }
 
 type PersistentVolume struct {
-	TypeMeta   `json:",inline"`
-	ObjectMeta `json:"metadata,omitempty"`
+	TypeMeta   `json:",inline" protobuf:"1"`
+	ObjectMeta `json:"metadata,omitempty" protobuf:"2"`
 
 	//Spec defines a persistent volume owned by the cluster
-	Spec PersistentVolumeSpec `json:"spec,omitempty" description:"specification of a persistent volume as provisioned by an administrator"`
+	Spec PersistentVolumeSpec `json:"spec,omitempty" description:"specification of a persistent volume as provisioned by an administrator" protobuf:"3"`
 
 	// Status represents the current information about persistent volume.
-	Status PersistentVolumeStatus `json:"status,omitempty" description:"current status of a persistent volume; populated by the system, read-only"`
+	Status PersistentVolumeStatus `json:"status,omitempty" description:"current status of a persistent volume; populated by the system, read-only" protobuf:"4"`
 }
 
 type PersistentVolumeSpec struct {
 	// Resources represents the actual resources of the volume
-	Capacity ResourceList `json:"capacity,omitempty" description:"a description of the persistent volume's resources and capacity"`
+	Capacity ResourceList `json:"capacity,omitempty" description:"a description of the persistent volume's resources and capacity" protobuf:"1"`
 	// Source represents the location and type of a volume to mount.
 	// AccessModeTypes are inferred from the Source.
-	PersistentVolumeSource `json:",inline" description:"the actual volume backing the persistent volume"`
+	PersistentVolumeSource `json:",inline" description:"the actual volume backing the persistent volume" protobuf:"2"`
 	// holds the binding reference to a PersistentVolumeClaim
-	ClaimRef *ObjectReference `json:"claimRef,omitempty" description:"the binding reference to a persistent volume claim"`
+	ClaimRef *ObjectReference `json:"claimRef,omitempty" description:"the binding reference to a persistent volume claim" protobuf:"3"`
 }
 
 type PersistentVolumeStatus struct {
 	// Phase indicates if a volume is available, bound to a claim, or released by a claim
-	Phase PersistentVolumePhase `json:"phase,omitempty" description:"the current phase of a persistent volume"`
+	Phase PersistentVolumePhase `json:"phase,omitempty" description:"the current phase of a persistent volume" protobuf:"1"`
 }
 
 type PersistentVolumeList struct {
-	TypeMeta `json:",inline"`
-	ListMeta `json:"metadata,omitempty"`
-	Items    []PersistentVolume `json:"items,omitempty" description:"list of persistent volumes"`
+	TypeMeta `json:",inline" protobuf:"1"`
+	ListMeta `json:"metadata,omitempty" protobuf:"2"`
+	Items    []PersistentVolume `json:"items,omitempty" description:"list of persistent volumes" protobuf:"3"`
 }
 
 // PersistentVolumeClaim is a user's request for and claim to a persistent volume
 type PersistentVolumeClaim struct {
-	TypeMeta   `json:",inline"`
-	ObjectMeta `json:"metadata,omitempty"`
+	TypeMeta   `json:",inline" protobuf:"1"`
+	ObjectMeta `json:"metadata,omitempty" protobuf:"2"`
 
 	// Spec defines the volume requested by a pod author
-	Spec PersistentVolumeClaimSpec `json:"spec,omitempty" description: "the desired characteristics of a volume"`
+	Spec PersistentVolumeClaimSpec `json:"spec,omitempty" description:"the desired characteristics of a volume" protobuf:"3"`
 
 	// Status represents the current information about a claim
-	Status PersistentVolumeClaimStatus `json:"status,omitempty" description:"the current status of a persistent volume claim; read-only"`
+	Status PersistentVolumeClaimStatus `json:"status,omitempty" description:"the current status of a persistent volume claim; read-only" protobuf:"4"`
 }
 
 type PersistentVolumeClaimList struct {
-	TypeMeta `json:",inline"`
-	ListMeta `json:"metadata,omitempty"`
-	Items    []PersistentVolumeClaim `json:"items,omitempty" description: "a list of persistent volume claims"`
+	TypeMeta `json:",inline" protobuf:"1"`
+	ListMeta `json:"metadata,omitempty" protobuf:"2"`
+	Items    []PersistentVolumeClaim `json:"items,omitempty" description:"a list of persistent volume claims" protobuf:"3"`
 }
 
 // PersistentVolumeClaimSpec describes the common attributes of storage devices
 // and allows a Source for provider-specific attributes
 type PersistentVolumeClaimSpec struct {
 	// Contains the types of access modes required
-	AccessModes []AccessModeType `json:"accessModes,omitempty" description:"the desired access modes the volume should have"`
+	AccessModes []AccessModeType `json:"accessModes,omitempty" description:"the desired access modes the volume should have" protobuf:"1"`
 	// Resources represents the minimum resources required
-	Resources ResourceRequirements `json:"resources,omitempty" description:"the desired resources the volume should have"`
+	Resources ResourceRequirements `json:"resources,omitempty" description:"the desired resources the volume should have" protobuf:"2"`
 }
 
 type PersistentVolumeClaimStatus struct {
 	// Phase represents the current phase of PersistentVolumeClaim
-	Phase PersistentVolumeClaimPhase `json:"phase,omitempty" description:"the current phase of the claim"`
+	Phase PersistentVolumeClaimPhase `json:"phase,omitempty" description:"the current phase of the claim" protobuf:"1"`
 	// AccessModes contains all ways the volume backing the PVC can be mounted
-	AccessModes []AccessModeType `json:"accessModes,omitempty" description:"the actual access modes the volume has"`
+	AccessModes []AccessModeType `json:"accessModes,omitempty" description:"the actual access modes the volume has" protobuf:"2"`
 	// Represents the actual resources of the underlying volume
-	Capacity ResourceList `json:"capacity,omitempty" description:"the actual resources the volume has"`
+	Capacity ResourceList `json:"capacity,omitempty" description:"the actual resources the volume has" protobuf:"3"`
 	// VolumeRef is a reference to the PersistentVolume bound to the PersistentVolumeClaim
-	VolumeRef *ObjectReference `json:"volumeRef,omitempty" description:"a reference to the backing persistent volume, when bound"`
+	VolumeRef *ObjectReference `json:"volumeRef,omitempty" description:"a reference to the backing persistent volume, when bound" protobuf:"4"`
 }
 
 type AccessModeType string
@@ -330,13 +343,13 @@ const (
 
 // HostPathVolumeSource represents bare host directory volume.
 type HostPathVolumeSource struct {
-	Path string `json:"path" description:"path of the directory on the host"`
+	Path string `json:"path" description:"path of the directory on the host" protobuf:"1"`
 }
 
 type EmptyDirVolumeSource struct {
 	// Optional: what type of storage medium should back this directory.
 	// The default is "" which means to use the node's default medium.
-	Medium StorageType `json:"medium" description:"type of storage used to back the volume; must be an empty string (default) or Memory"`
+	Medium StorageType `json:"medium" description:"type of storage used to back the volume; must be an empty string (default) or Memory" protobuf:"1"`
 }
 
 // StorageType defines ways that storage can be allocated to a volume.
@@ -364,27 +377,27 @@ const (
 // A GCE PD can only be mounted as read/write once.
 type GCEPersistentDiskVolumeSource struct {
 	// Unique name of the PD resource. Used to identify the disk in GCE
-	PDName string `json:"pdName" description:"unique name of the PD resource in GCE"`
+	PDName string `json:"pdName" description:"unique name of the PD resource in GCE" protobuf:"1"`
 	// Required: Filesystem type to mount.
 	// Must be a filesystem type supported by the host operating system.
 	// Ex. "ext4", "xfs", "ntfs"
 	// TODO: how do we prevent errors in the filesystem from compromising the machine
-	FSType string `json:"fsType,omitempty" description:"file system type to mount, such as ext4, xfs, ntfs"`
+	FSType string `json:"fsType,omitempty" description:"file system type to mount, such as ext4, xfs, ntfs" protobuf:"2"`
 	// Optional: Partition on the disk to mount.
 	// If omitted, kubelet will attempt to mount the device name.
 	// Ex. For /dev/sda1, this field is "1", for /dev/sda, this field is 0 or empty.
-	Partition int `json:"partition,omitempty" description:"partition on the disk to mount (e.g., '1' for /dev/sda1); if omitted the plain device name (e.g., /dev/sda) will be mounted"`
+	Partition int `json:"partition,omitempty" description:"partition on the disk to mount (e.g., '1' for /dev/sda1); if omitted the plain device name (e.g., /dev/sda) will be mounted" protobuf:"3"`
 	// Optional: Defaults to false (read/write). ReadOnly here will force
 	// the ReadOnly setting in VolumeMounts.
-	ReadOnly bool `json:"readOnly,omitempty" description:"read-only if true, read-write otherwise (false or unspecified)"`
+	ReadOnly bool `json:"readOnly,omitempty" description:"read-only if true, read-write otherwise (false or unspecified)" protobuf:"4"`
 }
 
 // GitRepoVolumeSource represents a volume that is pulled from git when the pod is created.
 type GitRepoVolumeSource struct {
 	// Repository URL
-	Repository string `json:"repository" description:"repository URL"`
+	Repository string `json:"repository" description:"repository URL" protobuf:"1"`
 	// Commit hash, this is optional
-	Revision string `json:"revision" description:"commit hash for the specified revision"`
+	Revision string `json:"revision" description:"commit hash for the specified revision" protobuf:"2"`
 }
 
 // SecretVolumeSource adapts a Secret into a VolumeSource
@@ -392,70 +405,109 @@ type GitRepoVolumeSource struct {
 // https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/design/secrets.md
 type SecretVolumeSource struct {
 	// Name of the secret in the pod's namespace to use
-	SecretName string `json:"secretName" description:"secretName is the name of a secret in the pod's namespace"`
+	SecretName string `json:"secretName" description:"secretName is the name of a secret in the pod's namespace" protobuf:"1"`
+}
+
+// DownwardAPIVolumeSource represents a volume containing downward API info.
+type DownwardAPIVolumeSource struct {
+	// Items is a list of downward API volume files.
+	Items []DownwardAPIVolumeFile `json:"items,omitempty" description:"list of downward API volume files" protobuf:"1"`
+}
+
+// DownwardAPIVolumeFile represents a single file containing information from
+// the downward API.
+type DownwardAPIVolumeFile struct {
+	// The relative path name of the file to be created.
+	Path string `json:"path" description:"relative path name of the file to be created; must not be absolute or contain the '..' path element" protobuf:"1"`
+	// Selects a field of the pod.
+	FieldRef ObjectFieldSelector `json:"fieldRef" description:"selects a field of the pod; only name, namespace, labels and annotations are supported" protobuf:"2"`
 }
 
 // NFSVolumeSource represents an NFS mount that lasts the lifetime of a pod
 type NFSVolumeSource struct {
 	// Server is the hostname or IP address of the NFS server
-	Server string `json:"server" description:"the hostname or IP address of the NFS server"`
+	Server string `json:"server" description:"the hostname or IP address of the NFS server" protobuf:"1"`
 
 	// Path is the exported NFS share
-	Path string `json:"path" description:"the path that is exported by the NFS server"`
+	Path string `json:"path" description:"the path that is exported by the NFS server" protobuf:"2"`
 
 	// Optional: Defaults to false (read/write). ReadOnly here will force
 	// the NFS export to be mounted with read-only permissions
-	ReadOnly bool `json:"readOnly,omitempty" description:"forces the NFS export to be mounted with read-only permissions"`
+	ReadOnly bool `json:"readOnly,omitempty" description:"forces the NFS export to be mounted with read-only permissions" protobuf:"3"`
 }
 
 // ContainerPort represents a network port in a single container.
 type ContainerPort struct {
 	// Optional: If specified, this must be a DNS_LABEL.  Each named port
 	// in a pod must have a unique name.
-	Name string `json:"name,omitempty" description:"name for the port that can be referred to by services; must be a DNS_LABEL and unique without the pod"`
+	Name string `json:"name,omitempty" description:"name for the port that can be referred to by services; must be a DNS_LABEL and unique without the pod" protobuf:"1"`
 	// Optional: If specified, this must be a valid port number, 0 < x < 65536.
 	// If HostNetwork is specified, this must match ContainerPort.
-	HostPort int `json:"hostPort,omitempty" description:"number of port to expose on the host; most containers do not need this"`
+	HostPort int `json:"hostPort,omitempty" description:"number of port to expose on the host; most containers do not need this" protobuf:"2"`
 	// Required: This must be a valid port number, 0 < x < 65536.
-	ContainerPort int `json:"containerPort" description:"number of port to expose on the pod's IP address"`
+	ContainerPort int `json:"containerPort" description:"number of port to expose on the pod's IP address" protobuf:"3"`
 	// Optional: Defaults to "TCP".
-	Protocol Protocol `json:"protocol,omitempty" description:"protocol for port; must be UDP or TCP; TCP if unspecified"`
+	Protocol Protocol `json:"protocol,omitempty" description:"protocol for port; must be UDP or TCP; TCP if unspecified" protobuf:"4"`
 	// Optional: What host IP to bind the external port to.
-	HostIP string `json:"hostIP,omitempty" description:"host IP to bind the port to"`
+	HostIP string `json:"hostIP,omitempty" description:"host IP to bind the port to" protobuf:"5"`
 }
 
 // VolumeMount describes a mounting of a Volume within a container.
 type VolumeMount struct {
 	// Required: This must match the Name of a Volume [above].
-	Name string `json:"name" description:"name of the volume to mount"`
+	Name string `json:"name" description:"name of the volume to mount" protobuf:"1"`
 	// Optional: Defaults to false (read-write).
-	ReadOnly bool `json:"readOnly,omitempty" description:"mounted read-only if true, read-write otherwise (false or unspecified)"`
+	ReadOnly bool `json:"readOnly,omitempty" description:"mounted read-only if true, read-write otherwise (false or unspecified)" protobuf:"2"`
 	// Required.
-	MountPath string `json:"mountPath" description:"path within the container at which the volume should be mounted"`
+	MountPath string `json:"mountPath" description:"path within the container at which the volume should be mounted" protobuf:"3"`
 }
 
 // EnvVar represents an environment variable present in a Container.
 type EnvVar struct {
 	// Required: This must be a C_IDENTIFIER.
-	Name string `json:"name" description:"name of the environment variable; must be a C_IDENTIFIER"`
+	Name string `json:"name" description:"name of the environment variable; must be a C_IDENTIFIER" protobuf:"1"`
 	// Optional: defaults to "".
-	Value string `json:"value,omitempty" description:"value of the environment variable; defaults to empty string"`
+	Value string `json:"value,omitempty" description:"value of the environment variable; defaults to empty string" protobuf:"2"`
+	// Optional: specifies a source the value of this var should come from.
+	ValueFrom *EnvVarSource `json:"valueFrom,omitempty" description:"source for the environment variable's value; cannot be used if value is not empty" protobuf:"3"`
+}
+
+// EnvVarSource represents a source for the value of an EnvVar.
+type EnvVarSource struct {
+	// Selects a key of a secret in the pod's namespace.
+	SecretKeyRef *SecretKeySelector `json:"secretKeyRef,omitempty" description:"selects a key of a secret in the pod's namespace" protobuf:"1"`
+	// Selects a field of the pod.
+	FieldRef *ObjectFieldSelector `json:"fieldRef,omitempty" description:"selects a field of the pod; only name, namespace and podIP are supported" protobuf:"2"`
+}
+
+// SecretKeySelector selects a key of a Secret.
+type SecretKeySelector struct {
+	// The name of the secret in the pod's namespace to select from.
+	Name string `json:"name" description:"name of the secret in the pod's namespace" protobuf:"1"`
+	// The key of the secret to select from.  Must be a valid secret key.
+	Key string `json:"key" description:"key of the secret to select from" protobuf:"2"`
+}
+
+// ObjectFieldSelector selects a field of an object.
+type ObjectFieldSelector struct {
+	// Path of the field to select, e.g. "metadata.name".
+	FieldPath string `json:"fieldPath" description:"path of the field to select, e.g. metadata.name" protobuf:"1"`
 }
 
 // HTTPGetAction describes an action based on HTTP Get requests.
 type HTTPGetAction struct {
 	// Optional: Path to access on the HTTP server.
-	Path string `json:"path,omitempty" description:"path to access on the HTTP server"`
+	Path string `json:"path,omitempty" description:"path to access on the HTTP server" protobuf:"1"`
 	// Required: Name or number of the port to access on the container.
-	Port util.IntOrString `json:"port,omitempty" description:"number or name of the port to access on the container"`
+	Port util.IntOrString `json:"port,omitempty" description:"number or name of the port to access on the container" protobuf:"2"`
 	// Optional: Host name to connect to, defaults to the pod IP.
-	Host string `json:"host,omitempty" description:"hostname to connect to; defaults to pod IP"`
+	Host string `json:"host,omitempty" description:"hostname to connect to; defaults to pod IP" protobuf:"3"`
 }
 
 // TCPSocketAction describes an action based on opening a socket
 type TCPSocketAction struct {
 	// Required: Port to connect to.
-	Port util.IntOrString `json:"port,omitempty" description:"number of name of the port to access on the container"`
+	Port util.IntOrString `json:"port,omitempty" description:"number of name of the port to access on the container" protobuf:"1"`
 }
 
 // ExecAction describes a "run in container" action.
@@ -464,17 +516,23 @@ type ExecAction struct {
 	// command  is root ('/') in the container's filesystem.  The command is simply exec'd, it is
 	// not run inside a shell, so traditional shell instructions ('|', etc) won't work.  To use
 	// a shell, you need to explicitly call out to that shell.
-	Command []string `json:"command,omitempty" description:"command line to execute inside the container; working directory for the command is root ('/') in the container's file system; the command is exec'd, not run inside a shell; exit status of 0 is treated as live/healthy and non-zero is unhealthy"`
+	Command []string `json:"command,omitempty" description:"command line to execute inside the container; working directory for the command is root ('/') in the container's file system; the command is exec'd, not run inside a shell; exit status of 0 is treated as live/healthy and non-zero is unhealthy" protobuf:"1"`
 }
 
 // Probe describes a liveness probe to be examined to the container.
 type Probe struct {
 	// The action taken to determine the health of a container
-	Handler `json:",inline"`
+	Handler `json:",inline" protobuf:"1"`
 	// Length of time before health checking is activated.  In seconds.
-	InitialDelaySeconds int64 `json:"initialDelaySeconds,omitempty" description:"number of seconds after the container has started before liveness probes are initiated"`
+	InitialDelaySeconds int64 `json:"initialDelaySeconds,omitempty" description:"number of seconds after the container has started before liveness probes are initiated" protobuf:"2"`
 	// Length of time before health checking times out.  In seconds.
-	TimeoutSeconds int64 `json:"timeoutSeconds,omitempty" description:"number of seconds after which liveness probes timeout; defaults to 1 second"`
+	TimeoutSeconds int64 `json:"timeoutSeconds,omitempty" description:"number of seconds after which liveness probes timeout; defaults to 1 second" protobuf:"3"`
+	// How often to perform the probe.  In seconds.
+	PeriodSeconds int64 `json:"periodSeconds,omitempty" description:"how often, in seconds, to perform the probe; defaults to 10 seconds" protobuf:"4"`
+	// Minimum consecutive successes for the probe to be considered successful after having failed.
+	SuccessThreshold int `json:"successThreshold,omitempty" description:"minimum consecutive successes for the probe to be considered successful after having failed; defaults to 1" protobuf:"5"`
+	// Minimum consecutive failures for the probe to be considered failed after having succeeded.
+	FailureThreshold int `json:"failureThreshold,omitempty" description:"minimum consecutive failures for the probe to be considered failed after having succeeded; defaults to 3" protobuf:"6"`
 }
 
 // PullPolicy describes a policy for if/when to pull a container image
@@ -495,17 +553,17 @@ type CapabilityType string
 // Capabilities represent POSIX capabilities that can be added or removed to a running container.
 type Capabilities struct {
 	// Added capabilities
-	Add []CapabilityType `json:"add,omitempty" description:"added capabilities"`
+	Add []CapabilityType `json:"add,omitempty" description:"added capabilities" protobuf:"1"`
 	// Removed capabilities
-	Drop []CapabilityType `json:"drop,omitempty" description:"droped capabilities"`
+	Drop []CapabilityType `json:"drop,omitempty" description:"droped capabilities" protobuf:"2"`
 }
 
 // ResourceRequirements describes the compute resource requirements.
 type ResourceRequirements struct {
 	// Limits describes the maximum amount of compute resources required.
-	Limits ResourceList `json:"limits,omitempty" description:"Maximum amount of compute resources allowed"`
+	Limits ResourceList `json:"limits,omitempty" description:"Maximum amount of compute resources allowed" protobuf:"1"`
 	// Requests describes the minimum amount of compute resources required.
-	Requests ResourceList `json:"requests,omitempty" description:"Minimum amount of resources requested"`
+	Requests ResourceList `json:"requests,omitempty" description:"Minimum amount of resources requested" protobuf:"2"`
 }
 
 const (
@@ -517,28 +575,28 @@ const (
 type Container struct {
 	// Required: This must be a DNS_LABEL.  Each container in a pod must
 	// have a unique name.
-	Name string `json:"name" description:"name of the container; must be a DNS_LABEL and unique within the pod; cannot be updated"`
+	Name string `json:"name" description:"name of the container; must be a DNS_LABEL and unique within the pod; cannot be updated" protobuf:"1"`
 	// Required.
-	Image string `json:"image" description:"Docker image name"`
+	Image string `json:"image" description:"Docker image name" protobuf:"2"`
 	// Optional: Defaults to whatever is defined in the image.
-	Command []string `json:"command,omitempty" description:"command argv array; not executed within a shell; defaults to entrypoint or command in the image; cannot be updated"`
+	Command []string `json:"command,omitempty" description:"command argv array; not executed within a shell; defaults to entrypoint or command in the image; cannot be updated" protobuf:"3"`
 	// Optional: Defaults to Docker's default.
-	WorkingDir     string               `json:"workingDir,omitempty" description:"container's working directory; defaults to image's default; cannot be updated"`
-	Ports          []ContainerPort      `json:"ports,omitempty" description:"list of ports to expose from the container; cannot be updated"`
-	Env            []EnvVar             `json:"env,omitempty" description:"list of environment variables to set in the container; cannot be updated"`
-	Resources      ResourceRequirements `json:"resources,omitempty" description:"Compute Resources required by this container; cannot be updated"`
-	VolumeMounts   []VolumeMount        `json:"volumeMounts,omitempty" description:"pod volumes to mount into the container's filesyste; cannot be updated"`
-	LivenessProbe  *Probe               `json:"livenessProbe,omitempty" description:"periodic probe of container liveness; container will be restarted if the probe fails; cannot be updated"`
-	ReadinessProbe *Probe               `json:"readinessProbe,omitempty" description:"periodic probe of container service readiness; container will be removed from service endpoints if the probe fails; cannot be updated"`
-	Lifecycle      *Lifecycle           `json:"lifecycle,omitempty" description:"actions that the management system should take in response to container lifecycle events; cannot be updated"`
+	WorkingDir     string               `json:"workingDir,omitempty" description:"container's working directory; defaults to image's default; cannot be updated" protobuf:"4"`
+	Ports          []ContainerPort      `json:"ports,omitempty" patchStrategy:"merge" patchMergeKey:"containerPort" description:"list of ports to expose from the container; cannot be updated" protobuf:"5"`
+	Env            []EnvVar             `json:"env,omitempty" patchStrategy:"merge" patchMergeKey:"name" description:"list of environment variables to set in the container; cannot be updated" protobuf:"6"`
+	Resources      ResourceRequirements `json:"resources,omitempty" description:"Compute Resources required by this container; cannot be updated" protobuf:"7"`
+	VolumeMounts   []VolumeMount        `json:"volumeMounts,omitempty" patchStrategy:"merge" patchMergeKey:"name" description:"pod volumes to mount into the container's filesyste; cannot be updated" protobuf:"8"`
+	LivenessProbe  *Probe               `json:"livenessProbe,omitempty" description:"periodic probe of container liveness; container will be restarted if the probe fails; cannot be updated" protobuf:"9"`
+	ReadinessProbe *Probe               `json:"readinessProbe,omitempty" description:"periodic probe of container service readiness; container will be removed from service endpoints if the probe fails; cannot be updated" protobuf:"10"`
+	Lifecycle      *Lifecycle           `json:"lifecycle,omitempty" description:"actions that the management system should take in response to container lifecycle events; cannot be updated" protobuf:"11"`
 	// Optional: Defaults to /dev/termination-log
-	TerminationMessagePath string `json:"terminationMessagePath,omitempty" description:"path at which the file to which the container's termination message will be written is mounted into the container's filesystem; message written is intended to be brief final status, such as an assertion failure message; defaults to /dev/termination-log; cannot be updated"`
+	TerminationMessagePath string `json:"terminationMessagePath,omitempty" description:"path at which the file to which the container's termination message will be written is mounted into the container's filesystem; message written is intended to be brief final status, such as an assertion failure message; defaults to /dev/termination-log; cannot be updated" protobuf:"12"`
 	// Optional: Default to false.
-	Privileged bool `json:"privileged,omitempty" description:"whether or not the container is granted privileged status; defaults to false; cannot be updated"`
+	Privileged bool `json:"privileged,omitempty" description:"whether or not the container is granted privileged status; defaults to false; cannot be updated" protobuf:"13"`
 	// Optional: Policy for pulling images for this container
-	ImagePullPolicy PullPolicy `json:"imagePullPolicy" description:"image pull policy; one of PullAlways, PullNever, PullIfNotPresent; defaults to PullAlways if :latest tag is specified, or PullIfNotPresent otherwise; cannot be updated"`
+	ImagePullPolicy PullPolicy `json:"imagePullPolicy" description:"image pull policy; one of PullAlways, PullNever, PullIfNotPresent; defaults to PullAlways if :latest tag is specified, or PullIfNotPresent otherwise; cannot be updated" protobuf:"14"`
 	// Optional: Capabilities for container.
-	Capabilities Capabilities `json:"capabilities,omitempty" description:"capabilities for container; cannot be updated"`
+	Capabilities Capabilities `json:"capabilities,omitempty" description:"capabilities for container; cannot be updated" protobuf:"15"`
 }
 
 // Handler defines a specific action that should be taken
@@ -546,12 +604,12 @@ type Container struct {
 type Handler struct {
 	// One and only one of the following should be specified.
 	// Exec specifies the action to take.
-	Exec *ExecAction `json:"exec,omitempty" description:"exec-based handler"`
+	Exec *ExecAction `json:"exec,omitempty" description:"exec-based handler" protobuf:"1"`
 	// HTTPGet specifies the http request to perform.
-	HTTPGet *HTTPGetAction `json:"httpGet,omitempty" description:"HTTP-based handler"`
+	HTTPGet *HTTPGetAction `json:"httpGet,omitempty" description:"HTTP-based handler" protobuf:"2"`
 	// TCPSocket specifies an action involving a TCP port.
 	// TODO: implement a realistic TCP lifecycle hook
-	TCPSocket *TCPSocketAction `json:"tcpSocket,omitempty"  description:"TCP-based handler; TCP hooks not yet supported"`
+	TCPSocket *TCPSocketAction `json:"tcpSocket,omitempty"  description:"TCP-based handler; TCP hooks not yet supported" protobuf:"3"`
 }
 
 // Lifecycle describes actions that the management system should take in response to container lifecycle
@@ -560,10 +618,10 @@ type Handler struct {
 type Lifecycle struct {
 	// PostStart is called immediately after a container is created.  If the handler fails, the container
 	// is terminated and restarted.
-	PostStart *Handler `json:"postStart,omitempty" description:"called immediately after a container is started; if the handler fails, the container is terminated and restarted according to its restart policy; other management of the container blocks until the hook completes"`
+	PostStart *Handler `json:"postStart,omitempty" description:"called immediately after a container is started; if the handler fails, the container is terminated and restarted according to its restart policy; other management of the container blocks until the hook completes" protobuf:"1"`
 	// PreStop is called immediately before a container is terminated.  The reason for termination is
 	// passed to the handler.  Regardless of the outcome of the handler, the container is eventually terminated.
-	PreStop *Handler `json:"preStop,omitempty" description:"called before a container is terminated; the container is terminated after the handler completes; other management of the container blocks until the hook completes"`
+	PreStop *Handler `json:"preStop,omitempty" description:"called before a container is terminated; the container is terminated after the handler completes; other management of the container blocks until the hook completes" protobuf:"2"`
 }
 
 type ConditionStatus string
@@ -580,46 +638,46 @@ const (
 
 type ContainerStateWaiting struct {
 	// Reason could be pulling image,
-	Reason string `json:"reason,omitempty" description:"(brief) reason the container is not yet running, such as pulling its image"`
+	Reason string `json:"reason,omitempty" description:"(brief) reason the container is not yet running, such as pulling its image" protobuf:"1"`
 }
 
 type ContainerStateRunning struct {
-	StartedAt util.Time `json:"startedAt,omitempty" description:"time at which the container was last (re-)started"`
+	StartedAt util.Time `json:"startedAt,omitempty" description:"time at which the container was last (re-)started" protobuf:"1"`
 }
 
 type ContainerStateTerminated struct {
-	ExitCode    int       `json:"exitCode" description:"exit status from the last termination of the container"`
-	Signal      int       `json:"signal,omitempty" description:"signal from the last termination of the container"`
-	Reason      string    `json:"reason,omitempty" description:"(brief) reason from the last termination of the container"`
-	Message     string    `json:"message,omitempty" description:"message regarding the last termination of the container"`
-	StartedAt   util.Time `json:"startedAt,omitempty" description:"time at which previous execution of the container started"`
-	FinishedAt  util.Time `json:"finishedAt,omitempty" description:"time at which the container last terminated"`
-	ContainerID string    `json:"containerID,omitempty" description:"container's ID in the format 'docker://<container_id>'"`
+	ExitCode    int       `json:"exitCode" description:"exit status from the last termination of the container" protobuf:"1"`
+	Signal      int       `json:"signal,omitempty" description:"signal from the last termination of the container" protobuf:"2"`
+	Reason      string    `json:"reason,omitempty" description:"(brief) reason from the last termination of the container" protobuf:"3"`
+	Message     string    `json:"message,omitempty" description:"message regarding the last termination of the container" protobuf:"4"`
+	StartedAt   util.Time `json:"startedAt,omitempty" description:"time at which previous execution of the container started" protobuf:"5"`
+	FinishedAt  util.Time `json:"finishedAt,omitempty" description:"time at which the container last terminated" protobuf:"6"`
+	ContainerID string    `json:"containerID,omitempty" description:"container's ID in the format 'docker://<container_id>'" protobuf:"7"`
 }
 
 // ContainerState holds a possible state of container.
 // Only one of its members may be specified.
 // If none of them is specified, the default one is ContainerStateWaiting.
 type ContainerState struct {
-	Waiting     *ContainerStateWaiting    `json:"waiting,omitempty" description:"details about a waiting container"`
-	Running     *ContainerStateRunning    `json:"running,omitempty" description:"details about a running container"`
-	Termination *ContainerStateTerminated `json:"termination,omitempty" description:"details about a terminated container"`
+	Waiting     *ContainerStateWaiting    `json:"waiting,omitempty" description:"details about a waiting container" protobuf:"1"`
+	Running     *ContainerStateRunning    `json:"running,omitempty" description:"details about a running container" protobuf:"2"`
+	Termination *ContainerStateTerminated `json:"termination,omitempty" description:"details about a terminated container" protobuf:"3"`
 }
 
 type ContainerStatus struct {
 	// TODO(dchen1107): Should we rename PodStatus to a more generic name or have a separate states
 	// defined for container?
-	State                ContainerState `json:"state,omitempty" description:"details about the container's current condition"`
-	LastTerminationState ContainerState `json:"lastState,omitempty" description:"details about the container's last termination condition"`
-	Ready                bool           `json:"ready" description:"specifies whether the container has passed its readiness probe"`
+	State                ContainerState `json:"state,omitempty" description:"details about the container's current condition" protobuf:"1"`
+	LastTerminationState ContainerState `json:"lastState,omitempty" description:"details about the container's last termination condition" protobuf:"2"`
+	Ready                bool           `json:"ready" description:"specifies whether the container has passed its readiness probe" protobuf:"3"`
 	// Note that this is calculated from dead containers.  But those containers are subject to
 	// garbage collection.  This value will get capped at 5 by GC.
-	RestartCount int `json:"restartCount" description:"the number of times the container has been restarted, currently based on the number of dead containers that have not yet been removed"`
+	RestartCount int `json:"restartCount" description:"the number of times the container has been restarted, currently based on the number of dead containers that have not yet been removed" protobuf:"4"`
 	// TODO(dchen1107): Which image the container is running with?
 	// The image the container is running
-	Image       string `json:"image" description:"image of the container"`
-	ImageID     string `json:"imageID" description:"ID of the container's image"`
-	ContainerID string `json:"containerID,omitempty" description:"container's ID in the format 'docker://<container_id>'"`
+	Image       string `json:"image" description:"image of the container" protobuf:"5"`
+	ImageID     string `json:"imageID" description:"ID of the container's image" protobuf:"6"`
+	ContainerID string `json:"containerID,omitempty" description:"container's ID in the format 'docker://<container_id>'" protobuf:"7"`
 }
 
 // PodPhase is a label for the condition of a pod at the current time.
@@ -658,9 +716,9 @@ const (
 // TODO: add LastTransitionTime, Reason, Message to match NodeCondition api.
 type PodCondition struct {
 	// Type is the type of the condition
-	Type PodConditionType `json:"type" description:"kind of the condition"`
+	Type PodConditionType `json:"type" description:"kind of the condition" protobuf:"1"`
 	// Status is the status of the condition
-	Status ConditionStatus `json:"status" description:"status of the condition, one of Full, None, Unknown"`
+	Status ConditionStatus `json:"status" description:"status of the condition, one of Full, None, Unknown" protobuf:"2"`
 }
 
 // PodInfo contains one entry for every container with available info.
@@ -692,40 +750,74 @@ const (
 	DNSDefault DNSPolicy = "Default"
 )
 
+// LabelSelectorOperator is the relationship between a label key and the set of values
+// in a LabelSelectorRequirement.
+type LabelSelectorOperator string
+
+const (
+	LabelSelectorOpIn     LabelSelectorOperator = "In"
+	LabelSelectorOpNotIn  LabelSelectorOperator = "NotIn"
+	LabelSelectorOpExists LabelSelectorOperator = "Exists"
+)
+
+// LabelSelectorRequirement is a set-based label selector requirement. It is ANDed with
+// any other requirements and exact match labels of the selector it belongs to.
+type LabelSelectorRequirement struct {
+	// Key is the label key that the requirement applies to.
+	Key string `json:"key" description:"label key that the requirement applies to" protobuf:"1"`
+	// Operator represents the key's relationship to the set of values.
+	Operator LabelSelectorOperator `json:"operator" description:"relationship of the key to the set of values; one of In, NotIn or Exists" protobuf:"2"`
+	// Values is the set of values for the In and NotIn operators. It must be empty
+	// for the Exists operator.
+	Values []string `json:"values,omitempty" description:"set of values for the In and NotIn operators; must be empty for Exists" protobuf:"3"`
+}
+
 // PodSpec is a description of a pod
 type PodSpec struct {
-	Volumes []Volume `json:"volumes" description:"list of volumes that can be mounted by containers belonging to the pod"`
+	Volumes []Volume `json:"volumes" patchStrategy:"merge" patchMergeKey:"name" description:"list of volumes that can be mounted by containers belonging to the pod" protobuf:"1"`
 	// Required: there must be at least one container in a pod.
-	Containers    []Container   `json:"containers" description:"list of containers belonging to the pod; cannot be updated; containers cannot currently be added or removed; there must be at least one container in a Pod"`
-	RestartPolicy RestartPolicy `json:"restartPolicy,omitempty" description:"restart policy for all containers within the pod; one of RestartPolicyAlways, RestartPolicyOnFailure, RestartPolicyNever"`
+	Containers    []Container   `json:"containers" patchStrategy:"merge" patchMergeKey:"name" description:"list of containers belonging to the pod; cannot be updated; containers cannot currently be added or removed; there must be at least one container in a Pod" protobuf:"2"`
+	RestartPolicy RestartPolicy `json:"restartPolicy,omitempty" description:"restart policy for all containers within the pod; one of RestartPolicyAlways, RestartPolicyOnFailure, RestartPolicyNever" protobuf:"3"`
+	// Optional duration in seconds the pod needs to terminate gracefully. May be decreased in delete request.
+	// Value must be non-negative integer. The value zero indicates delete immediately.
+	// If this value is nil, the default grace period will be used instead.
+	// The grace period is the duration in seconds after the processes running in the pod are sent
+	// a termination signal and the time when the processes are forcibly halted with a kill signal.
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty" description:"optional duration in seconds the pod needs to terminate gracefully; may be decreased in delete request; value must be non-negative integer; the value zero indicates delete immediately; if this value is not set, the default grace period will be used instead" protobuf:"4"`
 	// Optional: Set DNS policy.  Defaults to "ClusterFirst"
-	DNSPolicy DNSPolicy `json:"dnsPolicy,omitempty" description:"DNS policy for containers within the pod; one of 'ClusterFirst' or 'Default'"`
+	DNSPolicy DNSPolicy `json:"dnsPolicy,omitempty" description:"DNS policy for containers within the pod; one of 'ClusterFirst' or 'Default'" protobuf:"5"`
 	// NodeSelector is a selector which must be true for the pod to fit on a node
-	NodeSelector map[string]string `json:"nodeSelector,omitempty" description:"selector which must match a node's labels for the pod to be scheduled on that node"`
+	NodeSelector map[string]string `json:"nodeSelector,omitempty" description:"selector which must match a node's labels for the pod to be scheduled on that node" protobuf:"6"`
+	// NodeSelectorRequirements are set-based requirements which must also be true for the
+	// pod to fit on a node.
+	NodeSelectorRequirements []LabelSelectorRequirement `json:"nodeSelectorRequirements,omitempty" description:"set-based requirements which must also match a node's labels for the pod to be scheduled on that node" protobuf:"7"`
 
 	// Host is a request to schedule this pod onto a specific host.  If it is non-empty,
 	// the the scheduler simply schedules this pod onto that host, assuming that it fits
 	// resource requirements.
-	Host string `json:"host,omitempty" description:"host requested for this pod"`
+	Host string `json:"host,omitempty" description:"host requested for this pod" protobuf:"8"`
 	// Uses the host's network namespace. If this option is set, the ports that will be
 	// used must be specified.
 	// Optional: Default to false.
-	HostNetwork bool `json:"hostNetwork,omitempty" description:"host networking requested for this pod"`
+	HostNetwork bool `json:"hostNetwork,omitempty" description:"host networking requested for this pod" protobuf:"9"`
+	// ImagePullSecrets is a list of references to secrets in the same namespace to use for pulling
+	// the images of the pod's containers.
+	ImagePullSecrets []LocalObjectReference `json:"imagePullSecrets,omitempty" description:"list of references to secrets of type kubernetes.io/dockercfg in the same namespace to use for pulling any of the images used by this pod" protobuf:"10"`
 }
 
 // PodStatus represents information about the status of a pod. Status may trail the actual
 // state of a system.
 type PodStatus struct {
-	Phase      PodPhase       `json:"phase,omitempty" description:"current condition of the pod."`
-	Conditions []PodCondition `json:"Condition,omitempty" description:"current service state of pod"`
+	Phase      PodPhase       `json:"phase,omitempty" description:"current condition of the pod." protobuf:"1"`
+	Conditions []PodCondition `json:"Condition,omitempty" description:"current service state of pod" protobuf:"2"`
 	// A human readable message indicating details about why the pod is in this state.
-	Message string `json:"message,omitempty" description:"human readable message indicating details about why the pod is in this condition"`
+	Message string `json:"message,omitempty" description:"human readable message indicating details about why the pod is in this condition" protobuf:"3"`
 
 	// Host is the name of the node that this Pod is currently bound to, or empty if no
 	// assignment has been done.
-	Host   string `json:"host,omitempty" description:"host to which the pod is assigned; empty if not yet scheduled; cannot be updated"`
-	HostIP string `json:"hostIP,omitempty" description:"IP address of the host to which the pod is assigned; empty if not yet scheduled"`
-	PodIP  string `json:"podIP,omitempty" description:"IP address allocated to the pod; routable at least within the cluster; empty if not yet allocated"`
+	Host   string `json:"host,omitempty" description:"host to which the pod is assigned; empty if not yet scheduled; cannot be updated" protobuf:"4"`
+	HostIP string `json:"hostIP,omitempty" description:"IP address of the host to which the pod is assigned; empty if not yet scheduled" protobuf:"5"`
+	PodIP  string `json:"podIP,omitempty" description:"IP address allocated to the pod; routable at least within the cluster; empty if not yet allocated" protobuf:"6"`
 
 	// The key of this map is the *name* of the container within the manifest; it has one
 	// entry per container in the manifest. The value of this map is currently the output
@@ -733,110 +825,114 @@ type PodStatus struct {
 	// upon.
 	// TODO: Make real decisions about what our info should look like. Re-enable fuzz test
 	// when we have done this.
-	Info PodInfo `json:"info,omitempty" description:"map of container name to container status"`
+	Info PodInfo `json:"info,omitempty" description:"map of container name to container status" protobuf:"7"`
 }
 
 // PodStatusResult is a wrapper for PodStatus returned by kubelet that can be encode/decoded
 type PodStatusResult struct {
-	TypeMeta   `json:",inline"`
-	ObjectMeta `json:"metadata,omitempty" description:"standard object metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata"`
+	TypeMeta   `json:",inline" protobuf:"1"`
+	ObjectMeta `json:"metadata,omitempty" description:"standard object metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
 	// Status represents the current information about a pod. This data may not be up
 	// to date.
-	Status PodStatus `json:"status,omitempty" description:"most recently observed status of the pod; populated by the system, read-only; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#spec-and-status"`
+	Status PodStatus `json:"status,omitempty" description:"most recently observed status of the pod; populated by the system, read-only; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#spec-and-status" protobuf:"3"`
 }
 
 // Pod is a collection of containers that can run on a host. This resource is created
 // by clients and scheduled onto hosts.
 type Pod struct {
-	TypeMeta   `json:",inline"`
-	ObjectMeta `json:"metadata,omitempty" description:"standard object metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata"`
+	TypeMeta   `json:",inline" protobuf:"1"`
+	ObjectMeta `json:"metadata,omitempty" description:"standard object metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
 
 	// Spec defines the behavior of a pod.
-	Spec PodSpec `json:"spec,omitempty" description:"specification of the desired behavior of the pod; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#spec-and-status"`
+	Spec PodSpec `json:"spec,omitempty" description:"specification of the desired behavior of the pod; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#spec-and-status" protobuf:"3"`
 
 	// Status represents the current information about a pod. This data may not be up
 	// to date.
-	Status PodStatus `json:"status,omitempty" description:"most recently observed status of the pod; populated by the system, read-only; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#spec-and-status"`
+	Status PodStatus `json:"status,omitempty" description:"most recently observed status of the pod; populated by the system, read-only; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#spec-and-status" protobuf:"4"`
 }
 
 // PodList is a list of Pods.
 type PodList struct {
-	TypeMeta `json:",inline"`
-	ListMeta `json:"metadata,omitempty" description:"standard list metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#types-kinds`
+	TypeMeta `json:",inline" protobuf:"1"`
+	ListMeta `json:"metadata,omitempty" description:"standard list metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#types-kinds" protobuf:"2"`
 
-	Items []Pod `json:"items" description:"list of pods"`
+	Items []Pod `json:"items" description:"list of pods" protobuf:"3"`
 }
 
 // PodTemplateSpec describes the data a pod should have when created from a template
 type PodTemplateSpec struct {
 	// Metadata of the pods created from this template.
-	ObjectMeta `json:"metadata,omitempty" description:"standard object metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata"`
+	ObjectMeta `json:"metadata,omitempty" description:"standard object metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"1"`
 
 	// Spec defines the behavior of a pod.
-	Spec PodSpec `json:"spec,omitempty" description:"specification of the desired behavior of the pod; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#spec-and-status"`
+	Spec PodSpec `json:"spec,omitempty" description:"specification of the desired behavior of the pod; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#spec-and-status" protobuf:"2"`
 }
 
 // PodTemplate describes a template for creating copies of a predefined pod.
 type PodTemplate struct {
-	TypeMeta   `json:",inline"`
-	ObjectMeta `json:"metadata,omitempty" description:"standard object metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata"`
+	TypeMeta   `json:",inline" protobuf:"1"`
+	ObjectMeta `json:"metadata,omitempty" description:"standard object metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
 
 	// Spec defines the behavior of a pod.
-	Spec PodTemplateSpec `json:"spec,omitempty" description:"specification of the desired behavior of the pod; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#spec-and-status"`
+	Spec PodTemplateSpec `json:"spec,omitempty" description:"specification of the desired behavior of the pod; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#spec-and-status" protobuf:"3"`
 }
 
 // PodTemplateList is a list of PodTemplates.
 type PodTemplateList struct {
-	TypeMeta `json:",inline"`
-	ListMeta `json:"metadata,omitempty" description:"standard list metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata"`
+	TypeMeta `json:",inline" protobuf:"1"`
+	ListMeta `json:"metadata,omitempty" description:"standard list metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
 
-	Items []PodTemplate `json:"items" description:"list of pod templates"`
+	Items []PodTemplate `json:"items" description:"list of pod templates" protobuf:"3"`
 }
 
 // ReplicationControllerSpec is the specification of a replication controller.
 type ReplicationControllerSpec struct {
 	// Replicas is the number of desired replicas.
-	Replicas int `json:"replicas" description:"number of replicas desired"`
+	Replicas int `json:"replicas" description:"number of replicas desired" protobuf:"1"`
 
 	// Selector is a label query over pods that should match the Replicas count.
-	Selector map[string]string `json:"selector,omitempty" description:"label keys and values that must match in order to be controlled by this replication controller"`
+	Selector map[string]string `json:"selector,omitempty" description:"label keys and values that must match in order to be controlled by this replication controller" protobuf:"2"`
+
+	// SelectorRequirements are set-based requirements that pods must also satisfy
+	// to match the Replicas count.
+	SelectorRequirements []LabelSelectorRequirement `json:"selectorRequirements,omitempty" description:"set-based requirements that pods must also satisfy in order to be controlled by this replication controller" protobuf:"3"`
 
 	// TemplateRef is a reference to an object that describes the pod that will be created if
 	// insufficient replicas are detected.
-	TemplateRef *ObjectReference `json:"templateRef,omitempty" description:"reference to an object that describes the pod that will be created if insufficient replicas are detected"`
+	TemplateRef *ObjectReference `json:"templateRef,omitempty" description:"reference to an object that describes the pod that will be created if insufficient replicas are detected" protobuf:"4"`
 
 	// Template is the object that describes the pod that will be created if
 	// insufficient replicas are detected. This takes precedence over a
 	// TemplateRef.
-	Template *PodTemplateSpec `json:"template,omitempty" description:"object that describes the pod that will be created if insufficient replicas are detected; takes precendence over templateRef"`
+	Template *PodTemplateSpec `json:"template,omitempty" description:"object that describes the pod that will be created if insufficient replicas are detected; takes precendence over templateRef" protobuf:"5"`
 }
 
 // ReplicationControllerStatus represents the current status of a replication
 // controller.
 type ReplicationControllerStatus struct {
 	// Replicas is the number of actual replicas.
-	Replicas int `json:"replicas" description:"most recently oberved number of replicas"`
+	Replicas int `json:"replicas" description:"most recently oberved number of replicas" protobuf:"1"`
 }
 
 // ReplicationController represents the configuration of a replication controller.
 type ReplicationController struct {
-	TypeMeta   `json:",inline"`
-	ObjectMeta `json:"metadata,omitempty" description:"standard object metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata"`
+	TypeMeta   `json:",inline" protobuf:"1"`
+	ObjectMeta `json:"metadata,omitempty" description:"standard object metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
 
 	// Spec defines the desired behavior of this replication controller.
-	Spec ReplicationControllerSpec `json:"spec,omitempty" description:"specification of the desired behavior of the replication controller; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#spec-and-status"`
+	Spec ReplicationControllerSpec `json:"spec,omitempty" description:"specification of the desired behavior of the replication controller; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#spec-and-status" protobuf:"3"`
 
 	// Status is the current status of this replication controller. This data may be
 	// out of date by some window of time.
-	Status ReplicationControllerStatus `json:"status,omitempty" description:"most recently observed status of the replication controller; populated by the system, read-only; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#spec-and-status"`
+	Status ReplicationControllerStatus `json:"status,omitempty" description:"most recently observed status of the replication controller; populated by the system, read-only; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#spec-and-status" protobuf:"4"`
 }
 
 // ReplicationControllerList is a collection of replication controllers.
 type ReplicationControllerList struct {
-	TypeMeta `json:",inline"`
-	ListMeta `json:"metadata,omitempty" description:"standard list metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata"`
+	TypeMeta `json:",inline" protobuf:"1"`
+	ListMeta `json:"metadata,omitempty" description:"standard list metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
 
-	Items []ReplicationController `json:"items" description:"list of replication controllers"`
+	Items []ReplicationController `json:"items" description:"list of replication controllers" protobuf:"3"`
 }
 
 // Session Affinity Type string
@@ -857,49 +953,77 @@ type ServiceStatus struct{}
 type ServiceSpec struct {
 	// Port is the TCP or UDP port that will be made available to each pod for connecting to the pods
 	// proxied by this service.
-	Port int `json:"port" description:"port exposed by the service"`
+	Port int `json:"port" description:"port exposed by the service" protobuf:"1"`
 
 	// Optional: Supports "TCP" and "UDP".  Defaults to "TCP".
-	Protocol Protocol `json:"protocol,omitempty" description:"protocol for port; must be UDP or TCP; TCP if unspecified"`
+	Protocol Protocol `json:"protocol,omitempty" description:"protocol for port; must be UDP or TCP; TCP if unspecified" protobuf:"2"`
+
+	// Ports is the list of ports exposed by this service.  If it is set, Port,
+	// Protocol and TargetPort are ignored on input and describe the first port
+	// on output.
+	Ports []ServicePort `json:"ports,omitempty" description:"ports exposed by the service; if specified, port, protocol and targetPort are ignored on input and reflect the first port on output" protobuf:"3"`
 
 	// This service will route traffic to pods having labels matching this selector. If null, no endpoints will be automatically created. If empty, all pods will be selected.
-	Selector map[string]string `json:"selector" description:"label keys and values that must match in order to receive traffic for this service; if empty, all pods are selected, if not specified, endpoints must be manually specified"`
+	Selector map[string]string `json:"selector" description:"label keys and values that must match in order to receive traffic for this service; if empty, all pods are selected, if not specified, endpoints must be manually specified" protobuf:"4"`
+
+	// SelectorRequirements are set-based requirements that pods must also satisfy
+	// to receive traffic for this service.
+	SelectorRequirements []LabelSelectorRequirement `json:"selectorRequirements,omitempty" description:"set-based requirements that pods must also satisfy in order to receive traffic for this service" protobuf:"5"`
 
 	// PortalIP is usually assigned by the master.  If specified by the user
 	// we will try to respect it or else fail the request.  This field can
 	// not be changed by updates.
 	// Valid values are None, empty string (""), or a valid IP address
 	// None can be specified for headless services when proxying is not required
-	PortalIP string `json:"portalIP,omitempty description: IP address of the service; usually assigned by the system; if specified, it will be allocated to the service if unused, and creation of the service will fail otherwise; cannot be updated; 'None' can be specified for a headless service when proxying is not required"`
+	PortalIP string `json:"portalIP,omitempty description: IP address of the service; usually assigned by the system; if specified, it will be allocated to the service if unused, and creation of the service will fail otherwise; cannot be updated; 'None' can be specified for a headless service when proxying is not required" protobuf:"6"`
 
 	// CreateExternalLoadBalancer indicates whether a load balancer should be created for this service.
-	CreateExternalLoadBalancer bool `json:"createExternalLoadBalancer,omitempty" description:"set up a cloud-provider-specific load balancer on an external IP"`
+	CreateExternalLoadBalancer bool `json:"createExternalLoadBalancer,omitempty" description:"set up a cloud-provider-specific load balancer on an external IP" protobuf:"7"`
 
 	// PublicIPs are used by external load balancers, or can be set by
 	// users to handle external traffic that arrives at a node.
-	PublicIPs []string `json:"publicIPs,omitempty" description:"externally visible IPs (e.g. load balancers) that should be proxied to this service"`
+	PublicIPs []string `json:"publicIPs,omitempty" description:"externally visible IPs (e.g. load balancers) that should be proxied to this service" protobuf:"8"`
 
 	// TargetPort is the name or number of the port on the container to direct traffic to.
 	// This is useful if the containers the service points to have multiple open ports.
 	// Optional: If unspecified, the service port is used (an identity map).
-	TargetPort util.IntOrString `json:"targetPort,omitempty" description:"number or name of the port to access on the containers belonging to pods targeted by the service; defaults to the container's first open port"`
+	TargetPort util.IntOrString `json:"targetPort,omitempty" description:"number or name of the port to access on the containers belonging to pods targeted by the service; defaults to the container's first open port" protobuf:"9"`
 
 	// Optional: Supports "ClientIP" and "None".  Used to maintain session affinity.
-	SessionAffinity AffinityType `json:"sessionAffinity,omitempty" description:"enable client IP based session affinity; must be ClientIP or None; defaults to None"`
+	SessionAffinity AffinityType `json:"sessionAffinity,omitempty" description:"enable client IP based session affinity; must be ClientIP or None; defaults to None" protobuf:"10"`
+}
+
+// ServicePort is a single port exposed by a service.
+type ServicePort struct {
+	// Optional if only one ServicePort is defined on this service: The
+	// name of this port within the service.  This must be a DNS_LABEL.
+	// All ports within a ServiceSpec must have unique names.  This maps to
+	// the 'Name' field in EndpointPort objects.
+	Name string `json:"name,omitempty" description:"the name of this port; optional if only one port is defined" protobuf:"1"`
+
+	// Optional: Supports "TCP" and "UDP".  Defaults to "TCP".
+	Protocol Protocol `json:"protocol,omitempty" description:"the protocol used by this port; must be UDP or TCP; TCP if unspecified" protobuf:"2"`
+
+	// Required: The port that will be exposed on the service.
+	Port int `json:"port" description:"the port number that is exposed" protobuf:"3"`
+
+	// Optional: The name or number of the port on the container to direct
+	// traffic to.  If unspecified, the service port is used (an identity map).
+	TargetPort util.IntOrString `json:"targetPort,omitempty" description:"the port to access on the containers belonging to pods targeted by the service; defaults to the service port" protobuf:"4"`
 }
 
 // Service is a named abstraction of software service (for example, mysql) consisting of local port
 // (for example 3306) that the proxy listens on, and the selector that determines which pods
 // will answer requests sent through the proxy.
 type Service struct {
-	TypeMeta   `json:",inline"`
-	ObjectMeta `json:"metadata,omitempty" description:"standard object metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata"`
+	TypeMeta   `json:",inline" protobuf:"1"`
+	ObjectMeta `json:"metadata,omitempty" description:"standard object metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
 
 	// Spec defines the behavior of a service.
-	Spec ServiceSpec `json:"spec,omitempty" description:"specification of the desired behavior of the service; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#spec-and-status"`
+	Spec ServiceSpec `json:"spec,omitempty" description:"specification of the desired behavior of the service; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#spec-and-status" protobuf:"3"`
 
 	// Status represents the current status of a service.
-	Status ServiceStatus `json:"status,omitempty" description:"most recently observed status of the service; populated by the system, read-only; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#spec-and-status"`
+	Status ServiceStatus `json:"status,omitempty" description:"most recently observed status of the service; populated by the system, read-only; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#spec-and-status" protobuf:"4"`
 }
 
 const (
@@ -910,88 +1034,123 @@ const (
 
 // ServiceList holds a list of services.
 type ServiceList struct {
-	TypeMeta `json:",inline"`
-	ListMeta `json:"metadata,omitempty" description:"standard list metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata"`
+	TypeMeta `json:",inline" protobuf:"1"`
+	ListMeta `json:"metadata,omitempty" description:"standard list metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
 
-	Items []Service `json:"items" description:"list of services"`
+	Items []Service `json:"items" description:"list of services" protobuf:"3"`
 }
 
 // Endpoints is a collection of endpoints that implement the actual service, for example:
 // Name: "mysql", Endpoints: [{"ip": "10.10.1.1", "port": 1909}, {"ip": "10.10.2.2", "port": 8834}]
 type Endpoints struct {
-	TypeMeta   `json:",inline"`
-	ObjectMeta `json:"metadata,omitempty" description:"standard object metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata"`
+	TypeMeta   `json:",inline" protobuf:"1"`
+	ObjectMeta `json:"metadata,omitempty" description:"standard object metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
 
 	// Optional: The IP protocol for these endpoints. Supports "TCP" and
 	// "UDP".  Defaults to "TCP".
-	Protocol Protocol `json:"protocol,omitempty" description:"IP protocol for endpoint ports; must be UDP or TCP; TCP if unspecified"`
+	Protocol Protocol `json:"protocol,omitempty" description:"IP protocol for endpoint ports; must be UDP or TCP; TCP if unspecified" protobuf:"3"`
+
+	Endpoints []Endpoint `json:"endpoints,omitempty" description:"list of endpoints corresponding to a service" protobuf:"4"`
 
-	Endpoints []Endpoint `json:"endpoints,omitempty" description:"list of endpoints corresponding to a service"`
+	// Subsets is the set of all endpoints, grouped by common ports.  If it is
+	// set, Protocol and Endpoints are ignored on input and describe the first
+	// port of each subset on output.
+	Subsets []EndpointSubset `json:"subsets,omitempty" description:"sets of addresses and ports that comprise a service; if specified, protocol and endpoints are ignored on input" protobuf:"5"`
+}
+
+// EndpointSubset is a group of addresses with a common set of ports.  The
+// expanded set of endpoints is the Cartesian product of Addresses x Ports.
+type EndpointSubset struct {
+	Addresses []EndpointAddress `json:"addresses,omitempty" description:"IP addresses which offer the related ports" protobuf:"1"`
+	Ports     []EndpointPort    `json:"ports,omitempty" description:"port numbers available on the related IP addresses" protobuf:"2"`
+}
+
+// EndpointAddress is a tuple that describes single IP address.
+type EndpointAddress struct {
+	// The IP of this endpoint.
+	// TODO: This should allow hostname or IP, see #4447.
+	IP string `json:"ip" description:"IP address of the endpoint" protobuf:"1"`
+
+	// Optional: The kubernetes object related to the entry point.
+	TargetRef *ObjectReference `json:"targetRef,omitempty" description:"reference to object providing the endpoint" protobuf:"2"`
+}
+
+// EndpointPort is a tuple that describes a single port.
+type EndpointPort struct {
+	// The name of this port (corresponds to ServicePort.Name).  Optional
+	// if only one port is defined.  Must be a DNS_LABEL.
+	Name string `json:"name,omitempty" description:"name of this port" protobuf:"1"`
+
+	// The port number.
+	Port int `json:"port" description:"port number of the endpoint" protobuf:"2"`
+
+	// The IP protocol for this port.
+	Protocol Protocol `json:"protocol,omitempty" description:"protocol for this port; must be UDP or TCP; TCP if unspecified" protobuf:"3"`
 }
 
 // Endpoint is a single IP endpoint of a service.
 type Endpoint struct {
 	// Required: The IP of this endpoint.
 	// TODO: This should allow hostname or IP, see #4447.
-	IP string `json:"ip" description:"IP of this endpoint"`
+	IP string `json:"ip" description:"IP of this endpoint" protobuf:"1"`
 
 	// Required: The destination port to access.
-	Port int `json:"port" description:"destination port of this endpoint"`
+	Port int `json:"port" description:"destination port of this endpoint" protobuf:"2"`
 
 	// Optional: The kubernetes object related to the entry point.
-	TargetRef *ObjectReference `json:"targetRef,omitempty" description:"reference to object providing the endpoint"`
+	TargetRef *ObjectReference `json:"targetRef,omitempty" description:"reference to object providing the endpoint" protobuf:"3"`
 }
 
 // EndpointsList is a list of endpoints.
 type EndpointsList struct {
-	TypeMeta `json:",inline"`
-	ListMeta `json:"metadata,omitempty" description:"standard list metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata"`
+	TypeMeta `json:",inline" protobuf:"1"`
+	ListMeta `json:"metadata,omitempty" description:"standard list metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
 
-	Items []Endpoints `json:"items" description:"list of endpoints"`
+	Items []Endpoints `json:"items" description:"list of endpoints" protobuf:"3"`
 }
 
 // NodeSpec describes the attributes that a node is created with.
 type NodeSpec struct {
 	// Capacity represents the available resources of a node.
 	// see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/resources.md for more details.
-	Capacity ResourceList `json:"capacity,omitempty" description:"compute resource capacity of the node; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/resources.md"`
+	Capacity ResourceList `json:"capacity,omitempty" description:"compute resource capacity of the node; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/resources.md" protobuf:"1"`
 	// PodCIDR represents the pod IP range assigned to the node
-	PodCIDR string `json:"podCIDR,omitempty" description:"pod IP range assigned to the node"`
+	PodCIDR string `json:"podCIDR,omitempty" description:"pod IP range assigned to the node" protobuf:"2"`
 	// External ID of the node assigned by some machine database (e.g. a cloud provider)
-	ExternalID string `json:"externalID,omitempty" description:"external ID assigned to the node by some machine database (e.g. a cloud provider)"`
+	ExternalID string `json:"externalID,omitempty" description:"external ID assigned to the node by some machine database (e.g. a cloud provider)" protobuf:"3"`
 	// Unschedulable controls node schedulability of new pods. By default node is schedulable.
-	Unschedulable bool `json:"unschedulable,omitempty" description:"disable pod scheduling on the node"`
+	Unschedulable bool `json:"unschedulable,omitempty" description:"disable pod scheduling on the node" protobuf:"4"`
 }
 
 // NodeSystemInfo is a set of ids/uuids to uniquely identify the node.
 type NodeSystemInfo struct {
 	// MachineID is the machine-id reported by the node
-	MachineID string `json:"machineID"`
+	MachineID string `json:"machineID" protobuf:"1"`
 	// SystemUUID is the system-uuid reported by the node
-	SystemUUID string `json:"systemUUID"`
+	SystemUUID string `json:"systemUUID" protobuf:"2"`
 	// BootID is the boot-id reported by the node
-	BootID string `json:"bootID" description:"boot id is the boot-id reported by the node"`
+	BootID string `json:"bootID" description:"boot id is the boot-id reported by the node" protobuf:"3"`
 }
 
 // NodeStatus is information about the current status of a node.
 type NodeStatus struct {
 	// NodePhase is the current lifecycle phase of the node.
-	Phase NodePhase `json:"phase,omitempty" description:"most recently observed lifecycle phase of the node"`
+	Phase NodePhase `json:"phase,omitempty" description:"most recently observed lifecycle phase of the node" protobuf:"1"`
 	// Conditions is an array of current node conditions.
-	Conditions []NodeCondition `json:"conditions,omitempty" description:"list of node conditions observed"`
+	Conditions []NodeCondition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" description:"list of node conditions observed" protobuf:"2"`
 	// Queried from cloud provider, if available.
-	Addresses []NodeAddress `json:"addresses,omitempty" description:"list of addresses reachable to the node"`
+	Addresses []NodeAddress `json:"addresses,omitempty" description:"list of addresses reachable to the node" protobuf:"3"`
 	// NodeSystemInfo is a set of ids/uuids to uniquely identify the node
-	NodeInfo NodeSystemInfo `json:"nodeInfo,omitempty"`
+	NodeInfo NodeSystemInfo `json:"nodeInfo,omitempty" protobuf:"4"`
 }
 
 // NodeInfo is the information collected on the node.
 type NodeInfo struct {
-	TypeMeta `json:",inline"`
+	TypeMeta `json:",inline" protobuf:"1"`
 	// Capacity represents the available resources of a node
-	Capacity ResourceList `json:"capacity,omitempty"`
+	Capacity ResourceList `json:"capacity,omitempty" protobuf:"2"`
 	// NodeSystemInfo is a set of ids/uuids to uniquely identify the node
-	NodeSystemInfo `json:",inline,omitempty"`
+	NodeSystemInfo `json:",inline,omitempty" protobuf:"3"`
 }
 
 type NodePhase string
@@ -1018,15 +1177,21 @@ const (
 	NodeReady NodeConditionType = "Ready"
 	// NodeSchedulable means the node is ready to accept new pods.
 	NodeSchedulable NodeConditionType = "Schedulable"
+	// NodeMemoryPressure means the memory available on the node is below the kubelet's
+	// eviction threshold, and pods are being evicted from it.
+	NodeMemoryPressure NodeConditionType = "MemoryPressure"
+	// NodeDiskPressure means the disk space available on the node is below the kubelet's
+	// eviction threshold, and pods are being evicted from it.
+	NodeDiskPressure NodeConditionType = "DiskPressure"
 )
 
 type NodeCondition struct {
-	Type               NodeConditionType `json:"type" description:"type of node condition, one of Reachable, Ready"`
-	Status             ConditionStatus   `json:"status" description:"status of the condition, one of Full, None, Unknown"`
-	LastProbeTime      util.Time         `json:"lastProbeTime,omitempty" description:"last time the condition was probed"`
-	LastTransitionTime util.Time         `json:"lastTransitionTime,omitempty" description:"last time the condition transit from one status to another"`
-	Reason             string            `json:"reason,omitempty" description:"(brief) reason for the condition's last transition"`
-	Message            string            `json:"message,omitempty" description:"human readable message indicating details about last transition"`
+	Type               NodeConditionType `json:"type" description:"type of node condition, one of Reachable, Ready" protobuf:"1"`
+	Status             ConditionStatus   `json:"status" description:"status of the condition, one of Full, None, Unknown" protobuf:"2"`
+	LastProbeTime      util.Time         `json:"lastProbeTime,omitempty" description:"last time the condition was probed" protobuf:"3"`
+	LastTransitionTime util.Time         `json:"lastTransitionTime,omitempty" description:"last time the condition transit from one status to another" protobuf:"4"`
+	Reason             string            `json:"reason,omitempty" description:"(brief) reason for the condition's last transition" protobuf:"5"`
+	Message            string            `json:"message,omitempty" description:"human readable message indicating details about last transition" protobuf:"6"`
 }
 
 type NodeAddressType string
@@ -1039,8 +1204,8 @@ const (
 )
 
 type NodeAddress struct {
-	Type    NodeAddressType `json:"type"`
-	Address string          `json:"address"`
+	Type    NodeAddressType `json:"type" protobuf:"1"`
+	Address string          `json:"address" protobuf:"2"`
 }
 
 // ResourceName is the name identifying various resources in a ResourceList.
@@ -1061,22 +1226,22 @@ type ResourceList map[ResourceName]resource.Quantity
 // Node is a worker node in Kubernetes.
 // The name of the node according to etcd is in ID.
 type Node struct {
-	TypeMeta   `json:",inline"`
-	ObjectMeta `json:"metadata,omitempty" description:"standard object metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata"`
+	TypeMeta   `json:",inline" protobuf:"1"`
+	ObjectMeta `json:"metadata,omitempty" description:"standard object metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
 
 	// Spec defines the behavior of a node.
-	Spec NodeSpec `json:"spec,omitempty" description:"specification of a node; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#spec-and-status"`
+	Spec NodeSpec `json:"spec,omitempty" description:"specification of a node; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#spec-and-status" protobuf:"3"`
 
 	// Status describes the current status of a Node
-	Status NodeStatus `json:"status,omitempty" description:"most recently observed status of the node; populated by the system, read-only; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#spec-and-status"`
+	Status NodeStatus `json:"status,omitempty" description:"most recently observed status of the node; populated by the system, read-only; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#spec-and-status" protobuf:"4"`
 }
 
 // NodeList is a list of minions.
 type NodeList struct {
-	TypeMeta `json:",inline"`
-	ListMeta `json:"metadata,omitempty" description:"standard list metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata"`
+	TypeMeta `json:",inline" protobuf:"1"`
+	ListMeta `json:"metadata,omitempty" description:"standard list metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
 
-	Items []Node `json:"items" description:"list of nodes"`
+	Items []Node `json:"items" description:"list of nodes" protobuf:"3"`
 }
 
 type FinalizerName string
@@ -1089,13 +1254,25 @@ const (
 // NamespaceSpec describes the attributes on a Namespace
 type NamespaceSpec struct {
 	// Finalizers is an opaque list of values that must be empty to permanently remove object from storage
-	Finalizers []FinalizerName `json:"finalizers,omitempty" description:"an opaque list of values that must be empty to permanently remove object from storage"`
+	Finalizers []FinalizerName `json:"finalizers,omitempty" description:"an opaque list of values that must be empty to permanently remove object from storage" protobuf:"1"`
 }
 
 // NamespaceStatus is information about the current status of a Namespace.
 type NamespaceStatus struct {
 	// Phase is the current lifecycle phase of the namespace.
-	Phase NamespacePhase `json:"phase,omitempty" description:"phase is the current lifecycle phase of the namespace"`
+	Phase NamespacePhase `json:"phase,omitempty" description:"phase is the current lifecycle phase of the namespace" protobuf:"1"`
+	// Remaining lists the resources that still have objects in a terminating namespace.
+	Remaining []NamespaceRemainingResource `json:"remaining,omitempty" description:"resources that still have objects in a terminating namespace" protobuf:"2"`
+	// Message describes the progress of the deletion of a terminating namespace.
+	Message string `json:"message,omitempty" description:"human readable progress of the deletion of a terminating namespace" protobuf:"3"`
+}
+
+// NamespaceRemainingResource is the number of objects of a resource left in a namespace.
+type NamespaceRemainingResource struct {
+	// Resource is the name of the resource, e.g. pods.
+	Resource string `json:"resource" description:"name of the resource" protobuf:"1"`
+	// Count is the number of objects of the resource found in the namespace.
+	Count int `json:"count" description:"number of objects of the resource found in the namespace" protobuf:"2"`
 }
 
 type NamespacePhase string
@@ -1111,66 +1288,66 @@ const (
 // A namespace provides a scope for Names.
 // Use of multiple namespaces is optional
 type Namespace struct {
-	TypeMeta   `json:",inline"`
-	ObjectMeta `json:"metadata,omitempty" description:"standard object metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata"`
+	TypeMeta   `json:",inline" protobuf:"1"`
+	ObjectMeta `json:"metadata,omitempty" description:"standard object metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
 
 	// Spec defines the behavior of the Namespace.
-	Spec NamespaceSpec `json:"spec,omitempty" description:"spec defines the behavior of the Namespace; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#spec-and-status"`
+	Spec NamespaceSpec `json:"spec,omitempty" description:"spec defines the behavior of the Namespace; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#spec-and-status" protobuf:"3"`
 
 	// Status describes the current status of a Namespace
-	Status NamespaceStatus `json:"status,omitempty" description:"status describes the current status of a Namespace; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#spec-and-status"`
+	Status NamespaceStatus `json:"status,omitempty" description:"status describes the current status of a Namespace; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#spec-and-status" protobuf:"4"`
 }
 
 // NamespaceList is a list of Namespaces.
 type NamespaceList struct {
-	TypeMeta `json:",inline"`
-	ListMeta `json:"metadata,omitempty" description:"standard list metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata"`
+	TypeMeta `json:",inline" protobuf:"1"`
+	ListMeta `json:"metadata,omitempty" description:"standard list metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
 
 	// Items is the list of Namespace objects in the list
-	Items []Namespace `json:"items"  description:"items is the list of Namespace objects in the list"`
+	Items []Namespace `json:"items"  description:"items is the list of Namespace objects in the list" protobuf:"3"`
 }
 
 // Binding ties one object to another - for example, a pod is bound to a node by a scheduler.
 type Binding struct {
-	TypeMeta `json:",inline"`
+	TypeMeta `json:",inline" protobuf:"1"`
 	// ObjectMeta describes the object that is being bound.
-	ObjectMeta `json:"metadata,omitempty" description:"standard object metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata"`
+	ObjectMeta `json:"metadata,omitempty" description:"standard object metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
 
 	// Target is the object to bind to.
-	Target ObjectReference `json:"target" description:"an object to bind to"`
+	Target ObjectReference `json:"target" description:"an object to bind to" protobuf:"3"`
 }
 
 // DeleteOptions may be provided when deleting an API object
 type DeleteOptions struct {
-	TypeMeta `json:",inline"`
+	TypeMeta `json:",inline" protobuf:"1"`
 
 	// Optional duration in seconds before the object should be deleted. Value must be non-negative integer.
 	// The value zero indicates delete immediately. If this value is nil, the default grace period for the
 	// specified type will be used.
-	GracePeriodSeconds *int64 `json:"gracePeriodSeconds" description:"the duration in seconds to wait before deleting this object; defaults to a per object value if not specified; zero means delete immediately"`
+	GracePeriodSeconds *int64 `json:"gracePeriodSeconds" description:"the duration in seconds to wait before deleting this object; defaults to a per object value if not specified; zero means delete immediately" protobuf:"2"`
 }
 
 // Status is a return value for calls that don't return other objects.
 type Status struct {
-	TypeMeta `json:",inline"`
-	ListMeta `json:"metadata,omitempty" description:"standard list metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata"`
+	TypeMeta `json:",inline" protobuf:"1"`
+	ListMeta `json:"metadata,omitempty" description:"standard list metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
 
 	// One of: "Success" or "Failure"
-	Status string `json:"status,omitempty" description:"status of the operation; either Success, or Failure"`
+	Status string `json:"status,omitempty" description:"status of the operation; either Success, or Failure" protobuf:"3"`
 	// A human-readable description of the status of this operation.
-	Message string `json:"message,omitempty" description:"human-readable description of the status of this operation"`
+	Message string `json:"message,omitempty" description:"human-readable description of the status of this operation" protobuf:"4"`
 	// A machine-readable description of why this operation is in the
 	// "Failure" status. If this value is empty there
 	// is no information available. A Reason clarifies an HTTP status
 	// code but does not override it.
-	Reason StatusReason `json:"reason,omitempty" description:"machine-readable description of why this operation is in the 'Failure' status; if this value is empty there is no information available; a reason clarifies an HTTP status code but does not override it"`
+	Reason StatusReason `json:"reason,omitempty" description:"machine-readable description of why this operation is in the 'Failure' status; if this value is empty there is no information available; a reason clarifies an HTTP status code but does not override it" protobuf:"5"`
 	// Extended data associated with the reason.  Each reason may define its
 	// own extended details. This field is optional and the data returned
 	// is not guaranteed to conform to any schema except that defined by
 	// the reason type.
-	Details *StatusDetails `json:"details,omitempty" description:"extended data associated with the reason; each reason may define its own extended details; this field is optional and the data returned is not guaranteed to conform to any schema except that defined by the reason type"`
+	Details *StatusDetails `json:"details,omitempty" description:"extended data associated with the reason; each reason may define its own extended details; this field is optional and the data returned is not guaranteed to conform to any schema except that defined by the reason type" protobuf:"6"`
 	// Suggested HTTP return code for this status, 0 if not set.
-	Code int `json:"code,omitempty" description:"suggested HTTP return code for this status; 0 if not set"`
+	Code int `json:"code,omitempty" description:"suggested HTTP return code for this status; 0 if not set" protobuf:"7"`
 }
 
 // StatusDetails is a set of additional properties that MAY be set by the
@@ -1182,13 +1359,13 @@ type Status struct {
 type StatusDetails struct {
 	// The ID attribute of the resource associated with the status StatusReason
 	// (when there is a single ID which can be described).
-	ID string `json:"id,omitempty" description:"the ID attribute of the resource associated with the status StatusReason (when there is a single ID which can be described)"`
+	ID string `json:"id,omitempty" description:"the ID attribute of the resource associated with the status StatusReason (when there is a single ID which can be described)" protobuf:"1"`
 	// The kind attribute of the resource associated with the status StatusReason.
 	// On some operations may differ from the requested resource Kind.
-	Kind string `json:"kind,omitempty" description:"the kind attribute of the resource associated with the status StatusReason; on some operations may differ from the requested resource Kind"`
+	Kind string `json:"kind,omitempty" description:"the kind attribute of the resource associated with the status StatusReason; on some operations may differ from the requested resource Kind" protobuf:"2"`
 	// The Causes array includes more details associated with the StatusReason
 	// failure. Not all StatusReasons may provide detailed causes.
-	Causes []StatusCause `json:"causes,omitempty" description:"the Causes array includes more details associated with the StatusReason failure; not all StatusReasons may provide detailed causes"`
+	Causes []StatusCause `json:"causes,omitempty" description:"the Causes array includes more details associated with the StatusReason failure; not all StatusReasons may provide detailed causes" protobuf:"3"`
 }
 
 // Values of Status.Status
@@ -1263,10 +1440,10 @@ const (
 type StatusCause struct {
 	// A machine-readable description of the cause of the error. If this value is
 	// empty there is no information available.
-	Type CauseType `json:"reason,omitempty" description:"machine-readable description of the cause of the error; if this value is empty there is no information available"`
+	Type CauseType `json:"reason,omitempty" description:"machine-readable description of the cause of the error; if this value is empty there is no information available" protobuf:"1"`
 	// A human-readable description of the cause of the error.  This field may be
 	// presented as-is to a reader.
-	Message string `json:"message,omitempty" description:"human-readable description of the cause of the error; this field may be presented as-is to a reader"`
+	Message string `json:"message,omitempty" description:"human-readable description of the cause of the error; this field may be presented as-is to a reader" protobuf:"2"`
 	// The field of the resource that has caused this error, as named by its JSON
 	// serialization. May include dot and postfix notation for nested attributes.
 	// Arrays are zero-indexed.  Fields may appear more than once in an array of
@@ -1276,7 +1453,7 @@ type StatusCause struct {
 	// Examples:
 	//   "name" - the field "name" on the current resource
 	//   "items[0].name" - the field "name" on the first array entry in "items"
-	Field string `json:"field,omitempty" description:"field of the resource that has caused this error, as named by its JSON serialization; may include dot and postfix notation for nested attributes; arrays are zero-indexed; fields may appear more than once in an array of causes due to fields having multiple errors"`
+	Field string `json:"field,omitempty" description:"field of the resource that has caused this error, as named by its JSON serialization; may include dot and postfix notation for nested attributes; arrays are zero-indexed; fields may appear more than once in an array of causes due to fields having multiple errors" protobuf:"3"`
 }
 
 // CauseType is a machine readable value providing more detail about what
@@ -1304,12 +1481,12 @@ const (
 
 // ObjectReference contains enough information to let you inspect or modify the referred object.
 type ObjectReference struct {
-	Kind            string    `json:"kind,omitempty" description:"kind of the referent"`
-	Namespace       string    `json:"namespace,omitempty" description:"namespace of the referent"`
-	Name            string    `json:"name,omitempty" description:"name of the referent"`
-	UID             types.UID `json:"uid,omitempty" description:"uid of the referent"`
-	APIVersion      string    `json:"apiVersion,omitempty" description:"API version of the referent"`
-	ResourceVersion string    `json:"resourceVersion,omitempty" description:"specific resourceVersion to which this reference is made, if any: https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#concurrency-control-and-consistency"`
+	Kind            string    `json:"kind,omitempty" description:"kind of the referent" protobuf:"1"`
+	Namespace       string    `json:"namespace,omitempty" description:"namespace of the referent" protobuf:"2"`
+	Name            string    `json:"name,omitempty" description:"name of the referent" protobuf:"3"`
+	UID             types.UID `json:"uid,omitempty" description:"uid of the referent" protobuf:"4"`
+	APIVersion      string    `json:"apiVersion,omitempty" description:"API version of the referent" protobuf:"5"`
+	ResourceVersion string    `json:"resourceVersion,omitempty" description:"specific resourceVersion to which this reference is made, if any: https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#concurrency-control-and-consistency" protobuf:"6"`
 
 	// Optional. If referring to a piece of an object instead of an entire object, this string
 	// should contain information to identify the sub-object. For example, if the object
@@ -1319,61 +1496,66 @@ type ObjectReference struct {
 	// index 2 in this pod). This syntax is chosen only to have some well-defined way of
 	// referencing a part of an object.
 	// TODO: this design is not final and this field is subject to change in the future.
-	FieldPath string `json:"fieldPath,omitempty" description:"if referring to a piece of an object instead of an entire object, this string should contain a valid JSON/Go field access statement, such as desiredState.manifest.containers[2]"`
+	FieldPath string `json:"fieldPath,omitempty" description:"if referring to a piece of an object instead of an entire object, this string should contain a valid JSON/Go field access statement, such as desiredState.manifest.containers[2]" protobuf:"7"`
+}
+
+// LocalObjectReference contains enough information to locate the referenced object inside the same namespace.
+type LocalObjectReference struct {
+	Name string `json:"name,omitempty" description:"name of the referent" protobuf:"1"`
 }
 
 type EventSource struct {
 	// Component from which the event is generated.
-	Component string `json:"component,omitempty" description:"component that generated the event"`
+	Component string `json:"component,omitempty" description:"component that generated the event" protobuf:"1"`
 	// Host name on which the event is generated.
-	Host string `json:"host,omitempty" description:"name of the host where the event is generated"`
+	Host string `json:"host,omitempty" description:"name of the host where the event is generated" protobuf:"2"`
 }
 
 // Event is a report of an event somewhere in the cluster.
 // TODO: Decide whether to store these separately or with the object they apply to.
 type Event struct {
-	TypeMeta   `json:",inline"`
-	ObjectMeta `json:"metadata" description:"standard object metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata"`
+	TypeMeta   `json:",inline" protobuf:"1"`
+	ObjectMeta `json:"metadata" description:"standard object metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
 
 	// Required. The object that this event is about.
-	InvolvedObject ObjectReference `json:"involvedObject,omitempty" description:"object this event is about"`
+	InvolvedObject ObjectReference `json:"involvedObject,omitempty" description:"object this event is about" protobuf:"3"`
 
 	// Optional; this should be a short, machine understandable string that gives the reason
 	// for this event being generated.
 	// TODO: provide exact specification for format.
-	Reason string `json:"reason,omitempty" description:"short, machine understandable string that gives the reason for the transition into the object's current status"`
+	Reason string `json:"reason,omitempty" description:"short, machine understandable string that gives the reason for the transition into the object's current status" protobuf:"4"`
 
 	// Optional. A human-readable description of the status of this operation.
 	// TODO: decide on maximum length.
-	Message string `json:"message,omitempty" description:"human-readable description of the status of this operation"`
+	Message string `json:"message,omitempty" description:"human-readable description of the status of this operation" protobuf:"5"`
 
 	// Optional. The component reporting this event. Should be a short machine understandable string.
-	Source EventSource `json:"source,omitempty" description:"component reporting this event"`
+	Source EventSource `json:"source,omitempty" description:"component reporting this event" protobuf:"6"`
 
 	// The time at which the event was first recorded. (Time of server receipt is in TypeMeta.)
-	FirstTimestamp util.Time `json:"firstTimestamp,omitempty" description:"the time at which the event was first recorded"`
+	FirstTimestamp util.Time `json:"firstTimestamp,omitempty" description:"the time at which the event was first recorded" protobuf:"7"`
 
 	// The time at which the most recent occurance of this event was recorded.
-	LastTimestamp util.Time `json:"lastTimestamp,omitempty" description:"the time at which the most recent occurance of this event was recorded"`
+	LastTimestamp util.Time `json:"lastTimestamp,omitempty" description:"the time at which the most recent occurance of this event was recorded" protobuf:"8"`
 
 	// The number of times this event has occurred.
-	Count int `json:"count,omitempty" description:"the number of times this event has occurred"`
+	Count int `json:"count,omitempty" description:"the number of times this event has occurred" protobuf:"9"`
 }
 
 // EventList is a list of events.
 type EventList struct {
-	TypeMeta `json:",inline"`
-	ListMeta `json:"metadata,omitempty" description:"standard list metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata"`
+	TypeMeta `json:",inline" protobuf:"1"`
+	ListMeta `json:"metadata,omitempty" description:"standard list metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
 
-	Items []Event `json:"items" description:"list of events"`
+	Items []Event `json:"items" description:"list of events" protobuf:"3"`
 }
 
 // List holds a list of objects, which may not be known by the server.
 type List struct {
-	TypeMeta `json:",inline"`
-	ListMeta `json:"metadata,omitempty" description:"standard list metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata"`
+	TypeMeta `json:",inline" protobuf:"1"`
+	ListMeta `json:"metadata,omitempty" description:"standard list metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
 
-	Items []runtime.RawExtension `json:"items" description:"list of objects"`
+	Items []runtime.RawExtension `json:"items" description:"list of objects" protobuf:"3"`
 }
 
 // A type of object that is limited
@@ -1389,35 +1571,35 @@ const (
 // LimitRangeItem defines a min/max usage limit for any resource that matches on kind
 type LimitRangeItem struct {
 	// Type of resource that this limit applies to
-	Type LimitType `json:"type,omitempty" description:"type of resource that this limit applies to"`
+	Type LimitType `json:"type,omitempty" description:"type of resource that this limit applies to" protobuf:"1"`
 	// Max usage constraints on this kind by resource name
-	Max ResourceList `json:"max,omitempty" description:"max usage constraints on this kind by resource name"`
+	Max ResourceList `json:"max,omitempty" description:"max usage constraints on this kind by resource name" protobuf:"2"`
 	// Min usage constraints on this kind by resource name
-	Min ResourceList `json:"min,omitempty" description:"min usage constraints on this kind by resource name"`
+	Min ResourceList `json:"min,omitempty" description:"min usage constraints on this kind by resource name" protobuf:"3"`
 }
 
 // LimitRangeSpec defines a min/max usage limit for resources that match on kind
 type LimitRangeSpec struct {
 	// Limits is the list of LimitRangeItem objects that are enforced
-	Limits []LimitRangeItem `json:"limits" description:"limits is the list of LimitRangeItem objects that are enforced"`
+	Limits []LimitRangeItem `json:"limits" description:"limits is the list of LimitRangeItem objects that are enforced" protobuf:"1"`
 }
 
 // LimitRange sets resource usage limits for each kind of resource in a Namespace
 type LimitRange struct {
-	TypeMeta   `json:",inline"`
-	ObjectMeta `json:"metadata,omitempty" description:"standard object metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata"`
+	TypeMeta   `json:",inline" protobuf:"1"`
+	ObjectMeta `json:"metadata,omitempty" description:"standard object metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
 
 	// Spec defines the limits enforced
-	Spec LimitRangeSpec `json:"spec,omitempty" description:"spec defines the limits enforced; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#spec-and-status"`
+	Spec LimitRangeSpec `json:"spec,omitempty" description:"spec defines the limits enforced; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#spec-and-status" protobuf:"3"`
 }
 
 // LimitRangeList is a list of LimitRange items.
 type LimitRangeList struct {
-	TypeMeta `json:",inline"`
-	ListMeta `json:"metadata,omitempty" description:"standard list metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata"`
+	TypeMeta `json:",inline" protobuf:"1"`
+	ListMeta `json:"metadata,omitempty" description:"standard list metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
 
 	// Items is a list of LimitRange objects
-	Items []LimitRange `json:"items" description:"items is a list of LimitRange objects"`
+	Items []LimitRange `json:"items" description:"items is a list of LimitRange objects" protobuf:"3"`
 }
 
 // The following identify resource constants for Kubernetes object types
@@ -1430,56 +1612,60 @@ const (
 	ResourceReplicationControllers ResourceName = "replicationcontrollers"
 	// ResourceQuotas, number
 	ResourceQuotas ResourceName = "resourcequotas"
+	// Secrets, number
+	ResourceSecrets ResourceName = "secrets"
+	// PersistentVolumeClaims, number
+	ResourcePersistentVolumeClaims ResourceName = "persistentvolumeclaims"
 )
 
 // ResourceQuotaSpec defines the desired hard limits to enforce for Quota
 type ResourceQuotaSpec struct {
 	// Hard is the set of desired hard limits for each named resource
-	Hard ResourceList `json:"hard,omitempty" description:"hard is the set of desired hard limits for each named resource"`
+	Hard ResourceList `json:"hard,omitempty" description:"hard is the set of desired hard limits for each named resource" protobuf:"1"`
 }
 
 // ResourceQuotaStatus defines the enforced hard limits and observed use
 type ResourceQuotaStatus struct {
 	// Hard is the set of enforced hard limits for each named resource
-	Hard ResourceList `json:"hard,omitempty" description:"hard is the set of enforced hard limits for each named resource"`
+	Hard ResourceList `json:"hard,omitempty" description:"hard is the set of enforced hard limits for each named resource" protobuf:"1"`
 	// Used is the current observed total usage of the resource in the namespace
-	Used ResourceList `json:"used,omitempty" description:"used is the current observed total usage of the resource in the namespace"`
+	Used ResourceList `json:"used,omitempty" description:"used is the current observed total usage of the resource in the namespace" protobuf:"2"`
 }
 
 // ResourceQuota sets aggregate quota restrictions enforced per namespace
 type ResourceQuota struct {
-	TypeMeta   `json:",inline"`
-	ObjectMeta `json:"metadata,omitempty" description:"standard object metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata"`
+	TypeMeta   `json:",inline" protobuf:"1"`
+	ObjectMeta `json:"metadata,omitempty" description:"standard object metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
 
 	// Spec defines the desired quota
-	Spec ResourceQuotaSpec `json:"spec,omitempty" description:"spec defines the desired quota; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#spec-and-status"`
+	Spec ResourceQuotaSpec `json:"spec,omitempty" description:"spec defines the desired quota; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#spec-and-status" protobuf:"3"`
 
 	// Status defines the actual enforced quota and its current usage
-	Status ResourceQuotaStatus `json:"status,omitempty" description:"status defines the actual enforced quota and current usage; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#spec-and-status"`
+	Status ResourceQuotaStatus `json:"status,omitempty" description:"status defines the actual enforced quota and current usage; https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#spec-and-status" protobuf:"4"`
 }
 
 // ResourceQuotaList is a list of ResourceQuota items
 type ResourceQuotaList struct {
-	TypeMeta `json:",inline"`
-	ListMeta `json:"metadata,omitempty" description:"standard list metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata"`
+	TypeMeta `json:",inline" protobuf:"1"`
+	ListMeta `json:"metadata,omitempty" description:"standard list metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
 
 	// Items is a list of ResourceQuota objects
-	Items []ResourceQuota `json:"items" description:"items is a list of ResourceQuota objects"`
+	Items []ResourceQuota `json:"items" description:"items is a list of ResourceQuota objects" protobuf:"3"`
 }
 
 // Secret holds secret data of a certain type.  The total bytes of the values in
 // the Data field must be less than MaxSecretSize bytes.
 type Secret struct {
-	TypeMeta   `json:",inline"`
-	ObjectMeta `json:"metadata,omitempty" description:"standard object metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata"`
+	TypeMeta   `json:",inline" protobuf:"1"`
+	ObjectMeta `json:"metadata,omitempty" description:"standard object metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
 
 	// Data contains the secret data.  Each key must be a valid DNS_SUBDOMAIN.
 	// The serialized form of the secret data is a base64 encoded string,
 	// representing the arbitrary (possibly non-string) data value here.
-	Data map[string][]byte `json:"data,omitempty" description:"data contains the secret data.  Each key must be a valid DNS_SUBDOMAIN.  Each value must be a base64 encoded string"`
+	Data map[string][]byte `json:"data,omitempty" description:"data contains the secret data.  Each key must be a valid DNS_SUBDOMAIN.  Each value must be a base64 encoded string" protobuf:"3"`
 
 	// Used to facilitate programatic handling of secret data.
-	Type SecretType `json:"type,omitempty" description:"type facilitates programmatic handling of secret data"`
+	Type SecretType `json:"type,omitempty" description:"type facilitates programmatic handling of secret data" protobuf:"4"`
 }
 
 const MaxSecretSize = 1 * 1024 * 1024
@@ -1488,11 +1674,143 @@ type SecretType string
 
 const (
 	SecretTypeOpaque SecretType = "Opaque" // Default; arbitrary user-defined data
+
+	// SecretTypeDockercfg contains a dockercfg file that follows the same format rules as ~/.dockercfg
+	//
+	// Required fields:
+	// - Secret.Data[".dockercfg"] - a serialized ~/.dockercfg file
+	SecretTypeDockercfg SecretType = "kubernetes.io/dockercfg"
+
+	// SecretTypeTLS contains information about a TLS client or server secret.
+	//
+	// Required fields:
+	// - Secret.Data["tls.crt"] - PEM encoded TLS certificate
+	// - Secret.Data["tls.key"] - PEM encoded TLS private key
+	SecretTypeTLS SecretType = "kubernetes.io/tls"
+)
+
+const (
+	// DockerConfigKey is the key of the required data for SecretTypeDockercfg secrets
+	DockerConfigKey = ".dockercfg"
+
+	// TLSCertKey is the key for the TLS certificate of SecretTypeTLS secrets
+	TLSCertKey = "tls.crt"
+	// TLSPrivateKeyKey is the key for the TLS private key of SecretTypeTLS secrets
+	TLSPrivateKeyKey = "tls.key"
 )
 
 type SecretList struct {
-	TypeMeta `json:",inline"`
-	ListMeta `json:"metadata,omitempty" description:"standard list metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata"`
+	TypeMeta `json:",inline" protobuf:"1"`
+	ListMeta `json:"metadata,omitempty" description:"standard list metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
+
+	Items []Secret `json:"items" description:"items is a list of secret objects" protobuf:"3"`
+}
+
+// PolicyRule holds information that describes a policy rule of a role.
+type PolicyRule struct {
+	// Verbs is a list of verbs that apply to all of the resources, e.g. get, list, watch,
+	// create, update, delete or proxy.  "*" represents all verbs.
+	Verbs []string `json:"verbs" description:"list of verbs that apply to all of the resources; * represents all verbs" protobuf:"1"`
+	// Resources is a list of resources this rule applies to.  A subresource is written
+	// as "pods/status".  "*" represents all resources.
+	Resources []string `json:"resources" description:"list of resources the rule applies to; a subresource is written as pods/status; * represents all resources" protobuf:"2"`
+	// ResourceNames is an optional list of the names the rule applies to.  An empty list
+	// means the rule applies to every name.
+	ResourceNames []string `json:"resourceNames,omitempty" description:"optional list of the names the rule applies to; empty means every name" protobuf:"3"`
+}
+
+// SubjectKind is the kind of a subject a role is granted to.
+type SubjectKind string
+
+const (
+	// UserKind subjects are matched against the name of the user.
+	UserKind SubjectKind = "User"
+	// GroupKind subjects are matched against the groups of the user.
+	GroupKind SubjectKind = "Group"
+)
+
+// Subject is a user or a group a role is granted to.
+type Subject struct {
+	Kind SubjectKind `json:"kind" description:"kind of the subject or role" protobuf:"1"`
+	Name string      `json:"name" description:"name of the subject or role" protobuf:"2"`
+}
+
+// RoleRef references the role granted by a binding.  A RoleBinding may reference a Role
+// of its namespace or a ClusterRole, a ClusterRoleBinding may only reference a ClusterRole.
+type RoleRef struct {
+	// Kind is either "Role" or "ClusterRole".
+	Kind string `json:"kind" description:"kind of the subject or role" protobuf:"1"`
+	Name string `json:"name" description:"name of the subject or role" protobuf:"2"`
+}
+
+// Role is a namespaced set of policy rules.
+type Role struct {
+	TypeMeta   `json:",inline" protobuf:"1"`
+	ObjectMeta `json:"metadata,omitempty" description:"standard object metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
+
+	// Rules holds all of the policy rules of this role.
+	Rules []PolicyRule `json:"rules" description:"policy rules of the role" protobuf:"3"`
+}
+
+// RoleList is a list of Roles.
+type RoleList struct {
+	TypeMeta `json:",inline" protobuf:"1"`
+	ListMeta `json:"metadata,omitempty" description:"standard list metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
+
+	Items []Role `json:"items" description:"items is a list of Role objects" protobuf:"3"`
+}
+
+// RoleBinding grants the policy rules of a role to subjects, within the namespace of the binding.
+type RoleBinding struct {
+	TypeMeta   `json:",inline" protobuf:"1"`
+	ObjectMeta `json:"metadata,omitempty" description:"standard object metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
+
+	// Subjects holds the users and groups the role is granted to.
+	Subjects []Subject `json:"subjects" description:"users and groups the role is granted to" protobuf:"3"`
+	// RoleRef references a Role of the namespace of the binding or a ClusterRole.
+	RoleRef RoleRef `json:"roleRef" description:"reference to the granted role" protobuf:"4"`
+}
+
+// RoleBindingList is a list of RoleBindings.
+type RoleBindingList struct {
+	TypeMeta `json:",inline" protobuf:"1"`
+	ListMeta `json:"metadata,omitempty" description:"standard list metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
+
+	Items []RoleBinding `json:"items" description:"items is a list of RoleBinding objects" protobuf:"3"`
+}
+
+// ClusterRole is a cluster-wide set of policy rules.
+type ClusterRole struct {
+	TypeMeta   `json:",inline" protobuf:"1"`
+	ObjectMeta `json:"metadata,omitempty" description:"standard object metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
+
+	// Rules holds all of the policy rules of this role.
+	Rules []PolicyRule `json:"rules" description:"policy rules of the role" protobuf:"3"`
+}
+
+// ClusterRoleList is a list of ClusterRoles.
+type ClusterRoleList struct {
+	TypeMeta `json:",inline" protobuf:"1"`
+	ListMeta `json:"metadata,omitempty" description:"standard list metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
+
+	Items []ClusterRole `json:"items" description:"items is a list of ClusterRole objects" protobuf:"3"`
+}
+
+// ClusterRoleBinding grants the policy rules of a ClusterRole to subjects, in every namespace.
+type ClusterRoleBinding struct {
+	TypeMeta   `json:",inline" protobuf:"1"`
+	ObjectMeta `json:"metadata,omitempty" description:"standard object metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
+
+	// Subjects holds the users and groups the role is granted to.
+	Subjects []Subject `json:"subjects" description:"users and groups the role is granted to" protobuf:"3"`
+	// RoleRef references a ClusterRole.
+	RoleRef RoleRef `json:"roleRef" description:"reference to the granted role" protobuf:"4"`
+}
+
+// ClusterRoleBindingList is a list of ClusterRoleBindings.
+type ClusterRoleBindingList struct {
+	TypeMeta `json:",inline" protobuf:"1"`
+	ListMeta `json:"metadata,omitempty" description:"standard list metadata; see https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/api-conventions.md#metadata" protobuf:"2"`
 
-	Items []Secret `json:"items" description:"items is a list of secret objects"`
+	Items []ClusterRoleBinding `json:"items" description:"items is a list of ClusterRoleBinding objects" protobuf:"3"`
 }
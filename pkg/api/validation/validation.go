@@ -17,6 +17,7 @@ limitations under the License.
 package validation
 
 import (
+	"encoding/json"
 	"fmt"
 	"net"
 	"path"
@@ -44,6 +45,7 @@ var qualifiedNameErrorMsg string = fmt.Sprintf("must have at most %d characters
 var dnsSubdomainErrorMsg string = fmt.Sprintf("must have at most %d characters and match regex %s", util.DNS1123SubdomainMaxLength, util.DNS1123SubdomainFmt)
 var dns1123LabelErrorMsg string = fmt.Sprintf("must have at most %d characters and match regex %s", util.DNS1123LabelMaxLength, util.DNS1123LabelFmt)
 var dns952LabelErrorMsg string = fmt.Sprintf("must have at most %d characters and match regex %s", util.DNS952LabelMaxLength, util.DNS952LabelFmt)
+var secretKeyErrorMsg string = "must be a DNS_SUBDOMAIN, optionally prefixed with a single '.'"
 var pdPartitionErrorMsg string = intervalErrorMsg(0, 255)
 var portRangeErrorMsg string = intervalErrorMsg(0, 65536)
 
@@ -63,6 +65,41 @@ func ValidateLabels(labels map[string]string, field string) errs.ValidationError
 	return allErrs
 }
 
+var supportedLabelSelectorOperators = util.NewStringSet(string(api.LabelSelectorOpIn), string(api.LabelSelectorOpNotIn), string(api.LabelSelectorOpExists))
+
+// ValidateLabelSelectorRequirements validates that a list of set-based label selector
+// requirements is correctly defined.
+func ValidateLabelSelectorRequirements(requirements []api.LabelSelectorRequirement, field string) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	for i, req := range requirements {
+		rErrs := errs.ValidationErrorList{}
+		if !util.IsQualifiedName(req.Key) {
+			rErrs = append(rErrs, errs.NewFieldInvalid("key", req.Key, qualifiedNameErrorMsg))
+		}
+		switch req.Operator {
+		case "":
+			rErrs = append(rErrs, errs.NewFieldRequired("operator"))
+		case api.LabelSelectorOpIn, api.LabelSelectorOpNotIn:
+			if len(req.Values) == 0 {
+				rErrs = append(rErrs, errs.NewFieldRequired("values"))
+			}
+		case api.LabelSelectorOpExists:
+			if len(req.Values) != 0 {
+				rErrs = append(rErrs, errs.NewFieldInvalid("values", req.Values, "must be empty for the Exists operator"))
+			}
+		default:
+			rErrs = append(rErrs, errs.NewFieldNotSupported("operator", req.Operator))
+		}
+		for _, v := range req.Values {
+			if !util.IsValidLabelValue(v) {
+				rErrs = append(rErrs, errs.NewFieldInvalid("values", v, labelValueErrorMsg))
+			}
+		}
+		allErrs = append(allErrs, rErrs.PrefixIndex(i).Prefix(field)...)
+	}
+	return allErrs
+}
+
 // ValidateAnnotations validates that a set of annotations are correctly defined.
 func ValidateAnnotations(annotations map[string]string, field string) errs.ValidationErrorList {
 	allErrs := errs.ValidationErrorList{}
@@ -169,6 +206,17 @@ func nameIsDNSSubdomain(name string, prefix bool) (bool, string) {
 	return false, dnsSubdomainErrorMsg
 }
 
+// nameIsDNSLabel is a ValidateNameFunc for names that must be a DNS 1123 label.
+func nameIsDNSLabel(name string, prefix bool) (bool, string) {
+	if prefix {
+		name = maskTrailingDash(name)
+	}
+	if util.IsDNS1123Label(name) {
+		return true, ""
+	}
+	return false, dns1123LabelErrorMsg
+}
+
 // nameIsDNS952Label is a ValidateNameFunc for names that must be a DNS 952 label.
 func nameIsDNS952Label(name string, prefix bool) (bool, string) {
 	if prefix {
@@ -231,6 +279,9 @@ func ValidateObjectMetaUpdate(old, meta *api.ObjectMeta) errs.ValidationErrorLis
 	} else {
 		meta.CreationTimestamp = old.CreationTimestamp
 	}
+	// deletion fields are owned by the server and are only changed through a delete
+	meta.DeletionTimestamp = old.DeletionTimestamp
+	meta.DeletionGracePeriodSeconds = old.DeletionGracePeriodSeconds
 
 	if old.Name != meta.Name {
 		allErrs = append(allErrs, errs.NewFieldInvalid("name", meta.Name, "field is immutable"))
@@ -300,6 +351,10 @@ func validateSource(source *api.VolumeSource) errs.ValidationErrorList {
 		numVolumes++
 		allErrs = append(allErrs, validateNFS(source.NFS).Prefix("nfs")...)
 	}
+	if source.DownwardAPI != nil {
+		numVolumes++
+		allErrs = append(allErrs, validateDownwardAPIVolumeSource(source.DownwardAPI).Prefix("downwardAPI")...)
+	}
 	if numVolumes != 1 {
 		allErrs = append(allErrs, errs.NewFieldInvalid("", source, "exactly 1 volume type is required"))
 	}
@@ -344,6 +399,32 @@ func validateSecretVolumeSource(secretSource *api.SecretVolumeSource) errs.Valid
 	return allErrs
 }
 
+// supportedDownwardAPIVolumeFields are the pod fields that can be projected
+// into a downward API volume.
+var supportedDownwardAPIVolumeFields = util.NewStringSet("metadata.name", "metadata.namespace", "metadata.labels", "metadata.annotations")
+
+func validateDownwardAPIVolumeSource(downwardAPI *api.DownwardAPIVolumeSource) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	paths := util.StringSet{}
+	for i, item := range downwardAPI.Items {
+		iErrs := errs.ValidationErrorList{}
+		if len(item.Path) == 0 {
+			iErrs = append(iErrs, errs.NewFieldRequired("path"))
+		} else if path.IsAbs(item.Path) {
+			iErrs = append(iErrs, errs.NewFieldInvalid("path", item.Path, "must be a relative path"))
+		} else if util.NewStringSet(strings.Split(item.Path, "/")...).Has("..") {
+			iErrs = append(iErrs, errs.NewFieldInvalid("path", item.Path, "must not contain '..'"))
+		} else if paths.Has(path.Clean(item.Path)) {
+			iErrs = append(iErrs, errs.NewFieldDuplicate("path", item.Path))
+		} else {
+			paths.Insert(path.Clean(item.Path))
+		}
+		iErrs = append(iErrs, validateObjectFieldSelector(&item.FieldRef, supportedDownwardAPIVolumeFields).Prefix("fieldRef")...)
+		allErrs = append(allErrs, iErrs.PrefixIndex(i).Prefix("items")...)
+	}
+	return allErrs
+}
+
 func validateNFS(nfs *api.NFSVolumeSource) errs.ValidationErrorList {
 	allErrs := errs.ValidationErrorList{}
 	if nfs.Server == "" {
@@ -358,8 +439,12 @@ func validateNFS(nfs *api.NFSVolumeSource) errs.ValidationErrorList {
 	return allErrs
 }
 
+// ValidatePersistentVolumeName can be used to check whether the given persistent volume
+// or persistent volume claim name is valid.
+// Prefix indicates this name will be used as part of generation, in which case
+// trailing dashes are allowed.
 func ValidatePersistentVolumeName(name string, prefix bool) (bool, string) {
-	return util.IsDNS1123Label(name), name
+	return nameIsDNSLabel(name, prefix)
 }
 
 func ValidatePersistentVolume(pv *api.PersistentVolume) errs.ValidationErrorList {
@@ -399,6 +484,48 @@ func ValidatePersistentVolumeClaim(pvc *api.PersistentVolumeClaim) errs.Validati
 	return allErrs
 }
 
+// ValidatePersistentVolumeUpdate tests to see if the update is legal for an end user to make.
+// newPv is updated with fields that cannot be changed.
+func ValidatePersistentVolumeUpdate(newPv, oldPv *api.PersistentVolume) errs.ValidationErrorList {
+	allErrs := ValidatePersistentVolume(newPv)
+	allErrs = append(allErrs, ValidateObjectMetaUpdate(&oldPv.ObjectMeta, &newPv.ObjectMeta).Prefix("metadata")...)
+	newPv.Status = oldPv.Status
+	return allErrs
+}
+
+// ValidatePersistentVolumeStatusUpdate tests to see if the status update is legal for an end user to make.
+// newPv is updated with fields that cannot be changed.
+func ValidatePersistentVolumeStatusUpdate(newPv, oldPv *api.PersistentVolume) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	allErrs = append(allErrs, ValidateObjectMetaUpdate(&oldPv.ObjectMeta, &newPv.ObjectMeta).Prefix("metadata")...)
+	if newPv.ResourceVersion == "" {
+		allErrs = append(allErrs, fmt.Errorf("ResourceVersion must be specified"))
+	}
+	newPv.Spec = oldPv.Spec
+	return allErrs
+}
+
+// ValidatePersistentVolumeClaimUpdate tests to see if the update is legal for an end user to make.
+// newPvc is updated with fields that cannot be changed.
+func ValidatePersistentVolumeClaimUpdate(newPvc, oldPvc *api.PersistentVolumeClaim) errs.ValidationErrorList {
+	allErrs := ValidatePersistentVolumeClaim(newPvc)
+	allErrs = append(allErrs, ValidateObjectMetaUpdate(&oldPvc.ObjectMeta, &newPvc.ObjectMeta).Prefix("metadata")...)
+	newPvc.Status = oldPvc.Status
+	return allErrs
+}
+
+// ValidatePersistentVolumeClaimStatusUpdate tests to see if the status update is legal for an end user to make.
+// newPvc is updated with fields that cannot be changed.
+func ValidatePersistentVolumeClaimStatusUpdate(newPvc, oldPvc *api.PersistentVolumeClaim) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	allErrs = append(allErrs, ValidateObjectMetaUpdate(&oldPvc.ObjectMeta, &newPvc.ObjectMeta).Prefix("metadata")...)
+	if newPvc.ResourceVersion == "" {
+		allErrs = append(allErrs, fmt.Errorf("ResourceVersion must be specified"))
+	}
+	newPvc.Spec = oldPvc.Spec
+	return allErrs
+}
+
 var supportedPortProtocols = util.NewStringSet(string(api.ProtocolTCP), string(api.ProtocolUDP))
 
 func validatePorts(ports []api.ContainerPort) errs.ValidationErrorList {
@@ -445,11 +572,63 @@ func validateEnv(vars []api.EnvVar) errs.ValidationErrorList {
 		if !util.IsCIdentifier(ev.Name) {
 			vErrs = append(vErrs, errs.NewFieldInvalid("name", ev.Name, cIdentifierErrorMsg))
 		}
+		if ev.ValueFrom != nil {
+			if len(ev.Value) != 0 {
+				vErrs = append(vErrs, errs.NewFieldInvalid("valueFrom", "", "may not be specified when value is not empty"))
+			}
+			vErrs = append(vErrs, validateEnvVarSource(ev.ValueFrom).Prefix("valueFrom")...)
+		}
 		allErrs = append(allErrs, vErrs.PrefixIndex(i)...)
 	}
 	return allErrs
 }
 
+// supportedEnvVarFields are the pod fields that can be referenced by an
+// environment variable.
+var supportedEnvVarFields = util.NewStringSet("metadata.name", "metadata.namespace", "status.podIP")
+
+func validateEnvVarSource(source *api.EnvVarSource) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	numSources := 0
+	if source.SecretKeyRef != nil {
+		numSources++
+		allErrs = append(allErrs, validateSecretKeySelector(source.SecretKeyRef).Prefix("secretKeyRef")...)
+	}
+	if source.FieldRef != nil {
+		numSources++
+		allErrs = append(allErrs, validateObjectFieldSelector(source.FieldRef, supportedEnvVarFields).Prefix("fieldRef")...)
+	}
+	if numSources != 1 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("", source, "exactly one of secretKeyRef or fieldRef is required"))
+	}
+	return allErrs
+}
+
+func validateObjectFieldSelector(selector *api.ObjectFieldSelector, supported util.StringSet) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	if len(selector.FieldPath) == 0 {
+		allErrs = append(allErrs, errs.NewFieldRequired("fieldPath"))
+	} else if !supported.Has(selector.FieldPath) {
+		allErrs = append(allErrs, errs.NewFieldNotSupported("fieldPath", selector.FieldPath))
+	}
+	return allErrs
+}
+
+func validateSecretKeySelector(selector *api.SecretKeySelector) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	if len(selector.Name) == 0 {
+		allErrs = append(allErrs, errs.NewFieldRequired("name"))
+	} else if ok, qualifier := ValidateSecretName(selector.Name, false); !ok {
+		allErrs = append(allErrs, errs.NewFieldInvalid("name", selector.Name, qualifier))
+	}
+	if len(selector.Key) == 0 {
+		allErrs = append(allErrs, errs.NewFieldRequired("key"))
+	} else if !isSecretKey(selector.Key) {
+		allErrs = append(allErrs, errs.NewFieldInvalid("key", selector.Key, secretKeyErrorMsg))
+	}
+	return allErrs
+}
+
 func validateVolumeMounts(mounts []api.VolumeMount, volumes util.StringSet) errs.ValidationErrorList {
 	allErrs := errs.ValidationErrorList{}
 
@@ -481,6 +660,15 @@ func validateProbe(probe *api.Probe) errs.ValidationErrorList {
 	if probe.TimeoutSeconds < 0 {
 		allErrs = append(allErrs, errs.NewFieldInvalid("timeout", probe.TimeoutSeconds, "may not be less than zero"))
 	}
+	if probe.PeriodSeconds < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("periodSeconds", probe.PeriodSeconds, "may not be less than zero"))
+	}
+	if probe.SuccessThreshold < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("successThreshold", probe.SuccessThreshold, "may not be less than zero"))
+	}
+	if probe.FailureThreshold < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("failureThreshold", probe.FailureThreshold, "may not be less than zero"))
+	}
 	return allErrs
 }
 
@@ -725,7 +913,26 @@ func ValidatePodSpec(spec *api.PodSpec) errs.ValidationErrorList {
 	allErrs = append(allErrs, validateRestartPolicy(&spec.RestartPolicy).Prefix("restartPolicy")...)
 	allErrs = append(allErrs, validateDNSPolicy(&spec.DNSPolicy).Prefix("dnsPolicy")...)
 	allErrs = append(allErrs, ValidateLabels(spec.NodeSelector, "nodeSelector")...)
+	allErrs = append(allErrs, ValidateLabelSelectorRequirements(spec.NodeSelectorRequirements, "nodeSelectorRequirements")...)
 	allErrs = append(allErrs, validateHostNetwork(spec.HostNetwork, spec.Containers).Prefix("hostNetwork")...)
+	if spec.TerminationGracePeriodSeconds != nil && *spec.TerminationGracePeriodSeconds < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("terminationGracePeriodSeconds", *spec.TerminationGracePeriodSeconds, "must be non-negative"))
+	}
+	allErrs = append(allErrs, validateImagePullSecrets(spec.ImagePullSecrets).Prefix("imagePullSecrets")...)
+	return allErrs
+}
+
+func validateImagePullSecrets(refs []api.LocalObjectReference) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	for i, ref := range refs {
+		rErrs := errs.ValidationErrorList{}
+		if len(ref.Name) == 0 {
+			rErrs = append(rErrs, errs.NewFieldRequired("name"))
+		} else if ok, qualifier := ValidateSecretName(ref.Name, false); !ok {
+			rErrs = append(rErrs, errs.NewFieldInvalid("name", ref.Name, qualifier))
+		}
+		allErrs = append(allErrs, rErrs.PrefixIndex(i)...)
+	}
 	return allErrs
 }
 
@@ -781,23 +988,18 @@ func ValidateService(service *api.Service) errs.ValidationErrorList {
 	allErrs := errs.ValidationErrorList{}
 	allErrs = append(allErrs, ValidateObjectMeta(&service.ObjectMeta, true, ValidateServiceName).Prefix("metadata")...)
 
-	if !util.IsValidPortNum(service.Spec.Port) {
-		allErrs = append(allErrs, errs.NewFieldInvalid("spec.port", service.Spec.Port, portRangeErrorMsg))
-	}
-	if len(service.Spec.Protocol) == 0 {
-		allErrs = append(allErrs, errs.NewFieldRequired("spec.protocol"))
-	} else if !supportedPortProtocols.Has(strings.ToUpper(string(service.Spec.Protocol))) {
-		allErrs = append(allErrs, errs.NewFieldNotSupported("spec.protocol", service.Spec.Protocol))
+	if len(service.Spec.Ports) == 0 {
+		allErrs = append(allErrs, errs.NewFieldRequired("spec.ports"))
 	}
-	if service.Spec.TargetPort.Kind == util.IntstrInt && service.Spec.TargetPort.IntVal != 0 && !util.IsValidPortNum(service.Spec.TargetPort.IntVal) {
-		allErrs = append(allErrs, errs.NewFieldInvalid("spec.containerPort", service.Spec.Port, portRangeErrorMsg))
-	} else if service.Spec.TargetPort.Kind == util.IntstrString && len(service.Spec.TargetPort.StrVal) == 0 {
-		allErrs = append(allErrs, errs.NewFieldRequired("spec.containerPort"))
+	allPortNames := util.StringSet{}
+	for i := range service.Spec.Ports {
+		allErrs = append(allErrs, validateServicePort(&service.Spec.Ports[i], len(service.Spec.Ports) > 1, &allPortNames).PrefixIndex(i).Prefix("spec.ports")...)
 	}
 
 	if service.Spec.Selector != nil {
 		allErrs = append(allErrs, ValidateLabels(service.Spec.Selector, "spec.selector")...)
 	}
+	allErrs = append(allErrs, ValidateLabelSelectorRequirements(service.Spec.SelectorRequirements, "spec.selectorRequirements")...)
 
 	if service.Spec.SessionAffinity == "" {
 		allErrs = append(allErrs, errs.NewFieldRequired("spec.sessionAffinity"))
@@ -822,6 +1024,40 @@ func ValidateService(service *api.Service) errs.ValidationErrorList {
 	return allErrs
 }
 
+func validateServicePort(sp *api.ServicePort, requireName bool, allNames *util.StringSet) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+
+	if requireName && sp.Name == "" {
+		allErrs = append(allErrs, errs.NewFieldRequired("name"))
+	} else if sp.Name != "" {
+		if !util.IsDNS1123Label(sp.Name) {
+			allErrs = append(allErrs, errs.NewFieldInvalid("name", sp.Name, dns1123LabelErrorMsg))
+		} else if allNames.Has(sp.Name) {
+			allErrs = append(allErrs, errs.NewFieldDuplicate("name", sp.Name))
+		} else {
+			allNames.Insert(sp.Name)
+		}
+	}
+
+	if !util.IsValidPortNum(sp.Port) {
+		allErrs = append(allErrs, errs.NewFieldInvalid("port", sp.Port, portRangeErrorMsg))
+	}
+
+	if len(sp.Protocol) == 0 {
+		allErrs = append(allErrs, errs.NewFieldRequired("protocol"))
+	} else if !supportedPortProtocols.Has(strings.ToUpper(string(sp.Protocol))) {
+		allErrs = append(allErrs, errs.NewFieldNotSupported("protocol", sp.Protocol))
+	}
+
+	if sp.TargetPort.Kind == util.IntstrInt && sp.TargetPort.IntVal != 0 && !util.IsValidPortNum(sp.TargetPort.IntVal) {
+		allErrs = append(allErrs, errs.NewFieldInvalid("targetPort", sp.TargetPort, portRangeErrorMsg))
+	} else if sp.TargetPort.Kind == util.IntstrString && len(sp.TargetPort.StrVal) == 0 {
+		allErrs = append(allErrs, errs.NewFieldRequired("targetPort"))
+	}
+
+	return allErrs
+}
+
 // ValidateServiceUpdate tests if required fields in the service are set during an update
 func ValidateServiceUpdate(oldService, service *api.Service) errs.ValidationErrorList {
 	allErrs := errs.ValidationErrorList{}
@@ -857,7 +1093,12 @@ func ValidateReplicationControllerUpdate(oldController, controller *api.Replicat
 func ValidateReplicationControllerSpec(spec *api.ReplicationControllerSpec) errs.ValidationErrorList {
 	allErrs := errs.ValidationErrorList{}
 
-	selector := labels.Set(spec.Selector).AsSelector()
+	allErrs = append(allErrs, ValidateLabelSelectorRequirements(spec.SelectorRequirements, "selectorRequirements")...)
+	selector, err := api.LabelSelectorAsSelector(spec.Selector, spec.SelectorRequirements)
+	if err != nil {
+		// Invalid requirements have already been reported above.
+		selector = labels.Set(spec.Selector).AsSelector()
+	}
 	if selector.Empty() {
 		allErrs = append(allErrs, errs.NewFieldRequired("selector"))
 	}
@@ -1007,8 +1248,8 @@ func ValidateSecret(secret *api.Secret) errs.ValidationErrorList {
 
 	totalSize := 0
 	for key, value := range secret.Data {
-		if !util.IsDNS1123Subdomain(key) {
-			allErrs = append(allErrs, errs.NewFieldInvalid(fmt.Sprintf("data[%s]", key), key, cIdentifierErrorMsg))
+		if !isSecretKey(key) {
+			allErrs = append(allErrs, errs.NewFieldInvalid(fmt.Sprintf("data[%s]", key), key, secretKeyErrorMsg))
 		}
 
 		totalSize += len(value)
@@ -1018,6 +1259,170 @@ func ValidateSecret(secret *api.Secret) errs.ValidationErrorList {
 		allErrs = append(allErrs, errs.NewFieldForbidden("data", "Maximum secret size exceeded"))
 	}
 
+	switch secret.Type {
+	case api.SecretTypeDockercfg:
+		dockercfg, exists := secret.Data[api.DockerConfigKey]
+		if !exists {
+			allErrs = append(allErrs, errs.NewFieldRequired(fmt.Sprintf("data[%s]", api.DockerConfigKey)))
+			break
+		}
+		// Make sure that the content is well-formed json.
+		if err := json.Unmarshal(dockercfg, &map[string]interface{}{}); err != nil {
+			allErrs = append(allErrs, errs.NewFieldInvalid(fmt.Sprintf("data[%s]", api.DockerConfigKey), "<secret contents redacted>", err.Error()))
+		}
+	case api.SecretTypeTLS:
+		if _, exists := secret.Data[api.TLSCertKey]; !exists {
+			allErrs = append(allErrs, errs.NewFieldRequired(fmt.Sprintf("data[%s]", api.TLSCertKey)))
+		}
+		if _, exists := secret.Data[api.TLSPrivateKeyKey]; !exists {
+			allErrs = append(allErrs, errs.NewFieldRequired(fmt.Sprintf("data[%s]", api.TLSPrivateKeyKey)))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateSecretUpdate tests if the changes to a secret are legal.
+func ValidateSecretUpdate(newSecret, oldSecret *api.Secret) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	allErrs = append(allErrs, ValidateObjectMetaUpdate(&oldSecret.ObjectMeta, &newSecret.ObjectMeta).Prefix("metadata")...)
+	if newSecret.Type != oldSecret.Type {
+		allErrs = append(allErrs, errs.NewFieldInvalid("type", newSecret.Type, "field is immutable"))
+	}
+	allErrs = append(allErrs, ValidateSecret(newSecret)...)
+	return allErrs
+}
+
+// isSecretKey returns true if key may be used as a key in a secret's data:
+// a DNS subdomain, optionally prefixed with a single dot so that dotfiles
+// such as .dockercfg can be stored.
+func isSecretKey(key string) bool {
+	return util.IsDNS1123Subdomain(strings.TrimPrefix(key, "."))
+}
+
+// ValidateRoleName can be used to check whether the given role or role binding name is valid.
+// Prefix indicates this name will be used as part of generation, in which case
+// trailing dashes are allowed.
+func ValidateRoleName(name string, prefix bool) (bool, string) {
+	return nameIsDNSSubdomain(name, prefix)
+}
+
+func validatePolicyRules(rules []api.PolicyRule) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	for i, rule := range rules {
+		ruleErrs := errs.ValidationErrorList{}
+		if len(rule.Verbs) == 0 {
+			ruleErrs = append(ruleErrs, errs.NewFieldRequired("verbs"))
+		}
+		if len(rule.Resources) == 0 {
+			ruleErrs = append(ruleErrs, errs.NewFieldRequired("resources"))
+		}
+		allErrs = append(allErrs, ruleErrs.PrefixIndex(i)...)
+	}
+	return allErrs
+}
+
+var supportedSubjectKinds = util.NewStringSet(string(api.UserKind), string(api.GroupKind))
+
+func validateRoleBindingSubjects(subjects []api.Subject) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	for i, subject := range subjects {
+		subjectErrs := errs.ValidationErrorList{}
+		if !supportedSubjectKinds.Has(string(subject.Kind)) {
+			subjectErrs = append(subjectErrs, errs.NewFieldNotSupported("kind", subject.Kind))
+		}
+		if len(subject.Name) == 0 {
+			subjectErrs = append(subjectErrs, errs.NewFieldRequired("name"))
+		}
+		allErrs = append(allErrs, subjectErrs.PrefixIndex(i)...)
+	}
+	return allErrs
+}
+
+// validateRoleRef checks the role referenced by a binding.  Only RoleBindings may reference a Role.
+func validateRoleRef(roleRef *api.RoleRef, allowRole bool) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	switch {
+	case roleRef.Kind == "ClusterRole":
+	case roleRef.Kind == "Role" && allowRole:
+	default:
+		allErrs = append(allErrs, errs.NewFieldNotSupported("kind", roleRef.Kind))
+	}
+	if len(roleRef.Name) == 0 {
+		allErrs = append(allErrs, errs.NewFieldRequired("name"))
+	} else if ok, qualifier := ValidateRoleName(roleRef.Name, false); !ok {
+		allErrs = append(allErrs, errs.NewFieldInvalid("name", roleRef.Name, qualifier))
+	}
+	return allErrs
+}
+
+// ValidateRole tests if required fields in the role are set.
+func ValidateRole(role *api.Role) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	allErrs = append(allErrs, ValidateObjectMeta(&role.ObjectMeta, true, ValidateRoleName).Prefix("metadata")...)
+	allErrs = append(allErrs, validatePolicyRules(role.Rules).Prefix("rules")...)
+	return allErrs
+}
+
+// ValidateRoleUpdate tests if required fields in the role are set during an update.
+func ValidateRoleUpdate(oldRole, role *api.Role) errs.ValidationErrorList {
+	allErrs := ValidateObjectMetaUpdate(&oldRole.ObjectMeta, &role.ObjectMeta).Prefix("metadata")
+	allErrs = append(allErrs, validatePolicyRules(role.Rules).Prefix("rules")...)
+	return allErrs
+}
+
+// ValidateRoleBinding tests if required fields in the role binding are set.
+func ValidateRoleBinding(binding *api.RoleBinding) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	allErrs = append(allErrs, ValidateObjectMeta(&binding.ObjectMeta, true, ValidateRoleName).Prefix("metadata")...)
+	allErrs = append(allErrs, validateRoleBindingSubjects(binding.Subjects).Prefix("subjects")...)
+	allErrs = append(allErrs, validateRoleRef(&binding.RoleRef, true).Prefix("roleRef")...)
+	return allErrs
+}
+
+// ValidateRoleBindingUpdate tests if required fields in the role binding are set during an update.
+// The referenced role cannot be changed, a new binding must be created instead.
+func ValidateRoleBindingUpdate(oldBinding, binding *api.RoleBinding) errs.ValidationErrorList {
+	allErrs := ValidateObjectMetaUpdate(&oldBinding.ObjectMeta, &binding.ObjectMeta).Prefix("metadata")
+	allErrs = append(allErrs, validateRoleBindingSubjects(binding.Subjects).Prefix("subjects")...)
+	if oldBinding.RoleRef != binding.RoleRef {
+		allErrs = append(allErrs, errs.NewFieldInvalid("roleRef", binding.RoleRef, "field is immutable"))
+	}
+	return allErrs
+}
+
+// ValidateClusterRole tests if required fields in the cluster role are set.
+func ValidateClusterRole(role *api.ClusterRole) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	allErrs = append(allErrs, ValidateObjectMeta(&role.ObjectMeta, false, ValidateRoleName).Prefix("metadata")...)
+	allErrs = append(allErrs, validatePolicyRules(role.Rules).Prefix("rules")...)
+	return allErrs
+}
+
+// ValidateClusterRoleUpdate tests if required fields in the cluster role are set during an update.
+func ValidateClusterRoleUpdate(oldRole, role *api.ClusterRole) errs.ValidationErrorList {
+	allErrs := ValidateObjectMetaUpdate(&oldRole.ObjectMeta, &role.ObjectMeta).Prefix("metadata")
+	allErrs = append(allErrs, validatePolicyRules(role.Rules).Prefix("rules")...)
+	return allErrs
+}
+
+// ValidateClusterRoleBinding tests if required fields in the cluster role binding are set.
+func ValidateClusterRoleBinding(binding *api.ClusterRoleBinding) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	allErrs = append(allErrs, ValidateObjectMeta(&binding.ObjectMeta, false, ValidateRoleName).Prefix("metadata")...)
+	allErrs = append(allErrs, validateRoleBindingSubjects(binding.Subjects).Prefix("subjects")...)
+	allErrs = append(allErrs, validateRoleRef(&binding.RoleRef, false).Prefix("roleRef")...)
+	return allErrs
+}
+
+// ValidateClusterRoleBindingUpdate tests if required fields in the cluster role binding are set during an update.
+// The referenced role cannot be changed, a new binding must be created instead.
+func ValidateClusterRoleBindingUpdate(oldBinding, binding *api.ClusterRoleBinding) errs.ValidationErrorList {
+	allErrs := ValidateObjectMetaUpdate(&oldBinding.ObjectMeta, &binding.ObjectMeta).Prefix("metadata")
+	allErrs = append(allErrs, validateRoleBindingSubjects(binding.Subjects).Prefix("subjects")...)
+	if oldBinding.RoleRef != binding.RoleRef {
+		allErrs = append(allErrs, errs.NewFieldInvalid("roleRef", binding.RoleRef, "field is immutable"))
+	}
 	return allErrs
 }
 
@@ -1039,6 +1444,18 @@ func validateResourceRequirements(container *api.Container) errs.ValidationError
 		}
 		allErrs = append(allErrs, errs...)
 	}
+	for resourceName, quantity := range container.Resources.Requests {
+		// A container may not request more than its limit.
+		if limit, found := container.Resources.Limits[resourceName]; found && quantity.MilliValue() > limit.MilliValue() {
+			allErrs = append(allErrs, errs.NewFieldInvalid(fmt.Sprintf("resources.requests[%s]", resourceName), quantity.String(), "must be less than or equal to the limit"))
+		}
+		// Validate resource name.
+		errs := validateResourceName(resourceName.String(), fmt.Sprintf("resources.requests[%s]", resourceName))
+		if api.IsStandardResourceName(resourceName.String()) {
+			errs = append(errs, validateBasicResource(quantity).Prefix(fmt.Sprintf("Resource %s: ", resourceName))...)
+		}
+		allErrs = append(allErrs, errs...)
+	}
 
 	return allErrs
 }
@@ -1161,6 +1578,62 @@ func ValidateNamespaceFinalizeUpdate(newNamespace, oldNamespace *api.Namespace)
 func ValidateEndpoints(endpoints *api.Endpoints) errs.ValidationErrorList {
 	allErrs := errs.ValidationErrorList{}
 	allErrs = append(allErrs, ValidateObjectMeta(&endpoints.ObjectMeta, true, ValidateEndpointsName).Prefix("metadata")...)
+	allErrs = append(allErrs, validateEndpointSubsets(endpoints.Subsets).Prefix("subsets")...)
+	return allErrs
+}
+
+func validateEndpointSubsets(subsets []api.EndpointSubset) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+
+	for i := range subsets {
+		ss := &subsets[i]
+
+		ssErrs := errs.ValidationErrorList{}
+
+		if len(ss.Addresses) == 0 {
+			ssErrs = append(ssErrs, errs.NewFieldRequired("addresses"))
+		}
+		if len(ss.Ports) == 0 {
+			ssErrs = append(ssErrs, errs.NewFieldRequired("ports"))
+		}
+		for addr := range ss.Addresses {
+			ssErrs = append(ssErrs, validateEndpointAddress(&ss.Addresses[addr]).PrefixIndex(addr).Prefix("addresses")...)
+		}
+		for port := range ss.Ports {
+			ssErrs = append(ssErrs, validateEndpointPort(&ss.Ports[port], len(ss.Ports) > 1).PrefixIndex(port).Prefix("ports")...)
+		}
+
+		allErrs = append(allErrs, ssErrs.PrefixIndex(i)...)
+	}
+
+	return allErrs
+}
+
+func validateEndpointAddress(address *api.EndpointAddress) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	if !util.IsValidIP(address.IP) {
+		allErrs = append(allErrs, errs.NewFieldInvalid("ip", address.IP, "invalid IPv4 address"))
+	}
+	return allErrs
+}
+
+func validateEndpointPort(port *api.EndpointPort, requireName bool) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	if requireName && port.Name == "" {
+		allErrs = append(allErrs, errs.NewFieldRequired("name"))
+	} else if port.Name != "" {
+		if !util.IsDNS1123Label(port.Name) {
+			allErrs = append(allErrs, errs.NewFieldInvalid("name", port.Name, dns1123LabelErrorMsg))
+		}
+	}
+	if !util.IsValidPortNum(port.Port) {
+		allErrs = append(allErrs, errs.NewFieldInvalid("port", port.Port, portRangeErrorMsg))
+	}
+	if len(port.Protocol) == 0 {
+		allErrs = append(allErrs, errs.NewFieldRequired("protocol"))
+	} else if !supportedPortProtocols.Has(strings.ToUpper(string(port.Protocol))) {
+		allErrs = append(allErrs, errs.NewFieldNotSupported("protocol", port.Protocol))
+	}
 	return allErrs
 }
 
@@ -1168,5 +1641,6 @@ func ValidateEndpoints(endpoints *api.Endpoints) errs.ValidationErrorList {
 func ValidateEndpointsUpdate(oldEndpoints *api.Endpoints, endpoints *api.Endpoints) errs.ValidationErrorList {
 	allErrs := errs.ValidationErrorList{}
 	allErrs = append(allErrs, ValidateObjectMetaUpdate(&oldEndpoints.ObjectMeta, &endpoints.ObjectMeta).Prefix("metadata")...)
+	allErrs = append(allErrs, validateEndpointSubsets(endpoints.Subsets).Prefix("subsets")...)
 	return allErrs
 }
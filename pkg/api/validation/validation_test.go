@@ -256,6 +256,17 @@ func TestValidatePersistentVolumes(t *testing.T) {
 			},
 			),
 		},
+		"subdomain-name": {
+			isExpectedFailure: true,
+			volume: testVolume("foo.bar", "", api.PersistentVolumeSpec{
+				Capacity: api.ResourceList{
+					api.ResourceName(api.ResourceStorage): resource.MustParse("10G"),
+				},
+				PersistentVolumeSource: api.PersistentVolumeSource{
+					HostPath: &api.HostPathVolumeSource{Path: "/foo"},
+				},
+			}),
+		},
 		"missing-name": {
 			isExpectedFailure: true,
 			volume: testVolume("", "", api.PersistentVolumeSpec{
@@ -375,12 +386,17 @@ func TestValidateVolumes(t *testing.T) {
 		{Name: "gcepd", VolumeSource: api.VolumeSource{GCEPersistentDisk: &api.GCEPersistentDiskVolumeSource{"my-PD", "ext4", 1, false}}},
 		{Name: "gitrepo", VolumeSource: api.VolumeSource{GitRepo: &api.GitRepoVolumeSource{"my-repo", "hashstring"}}},
 		{Name: "secret", VolumeSource: api.VolumeSource{Secret: &api.SecretVolumeSource{"my-secret"}}},
+		{Name: "downwardapi", VolumeSource: api.VolumeSource{DownwardAPI: &api.DownwardAPIVolumeSource{Items: []api.DownwardAPIVolumeFile{
+			{Path: "labels", FieldRef: api.ObjectFieldSelector{FieldPath: "metadata.labels"}},
+			{Path: "meta/annotations", FieldRef: api.ObjectFieldSelector{FieldPath: "metadata.annotations"}},
+			{Path: "name", FieldRef: api.ObjectFieldSelector{FieldPath: "metadata.name"}},
+		}}}},
 	}
 	names, errs := validateVolumes(successCase)
 	if len(errs) != 0 {
 		t.Errorf("expected success: %v", errs)
 	}
-	if len(names) != len(successCase) || !names.HasAll("abc", "123", "abc-123", "empty", "gcepd", "gitrepo", "secret") {
+	if len(names) != len(successCase) || !names.HasAll("abc", "123", "abc-123", "empty", "gcepd", "gitrepo", "secret", "downwardapi") {
 		t.Errorf("wrong names result: %v", names)
 	}
 	emptyVS := api.VolumeSource{EmptyDir: &api.EmptyDirVolumeSource{}}
@@ -415,6 +431,25 @@ func TestValidateVolumes(t *testing.T) {
 	}
 }
 
+func TestValidateDownwardAPIVolumeSource(t *testing.T) {
+	fieldRef := func(path string) api.ObjectFieldSelector {
+		return api.ObjectFieldSelector{FieldPath: path}
+	}
+	errorCases := map[string][]api.DownwardAPIVolumeFile{
+		"missing path":      {{FieldRef: fieldRef("metadata.labels")}},
+		"absolute path":     {{Path: "/labels", FieldRef: fieldRef("metadata.labels")}},
+		"dot-dot path":      {{Path: "../labels", FieldRef: fieldRef("metadata.labels")}},
+		"duplicate path":    {{Path: "labels", FieldRef: fieldRef("metadata.labels")}, {Path: "./labels", FieldRef: fieldRef("metadata.annotations")}},
+		"missing fieldPath": {{Path: "labels"}},
+		"unsupported field": {{Path: "ip", FieldRef: fieldRef("status.podIP")}},
+	}
+	for k, v := range errorCases {
+		if errs := validateDownwardAPIVolumeSource(&api.DownwardAPIVolumeSource{Items: v}); len(errs) == 0 {
+			t.Errorf("expected failure for %s", k)
+		}
+	}
+}
+
 func TestValidatePorts(t *testing.T) {
 	successCase := []api.ContainerPort{
 		{Name: "abc", ContainerPort: 80, HostPort: 80, Protocol: "TCP"},
@@ -479,6 +514,11 @@ func TestValidateEnv(t *testing.T) {
 		{Name: "ABC", Value: "value"},
 		{Name: "AbC_123", Value: "value"},
 		{Name: "abc", Value: ""},
+		{Name: "abc", ValueFrom: &api.EnvVarSource{SecretKeyRef: &api.SecretKeySelector{Name: "secret", Key: "key"}}},
+		{Name: "abc", ValueFrom: &api.EnvVarSource{SecretKeyRef: &api.SecretKeySelector{Name: "secret", Key: ".dockercfg"}}},
+		{Name: "abc", ValueFrom: &api.EnvVarSource{FieldRef: &api.ObjectFieldSelector{FieldPath: "metadata.name"}}},
+		{Name: "abc", ValueFrom: &api.EnvVarSource{FieldRef: &api.ObjectFieldSelector{FieldPath: "metadata.namespace"}}},
+		{Name: "abc", ValueFrom: &api.EnvVarSource{FieldRef: &api.ObjectFieldSelector{FieldPath: "status.podIP"}}},
 	}
 	if errs := validateEnv(successCase); len(errs) != 0 {
 		t.Errorf("expected success: %v", errs)
@@ -500,6 +540,25 @@ func TestValidateEnv(t *testing.T) {
 			}
 		}
 	}
+
+	valueFromErrorCases := map[string][]api.EnvVar{
+		"value and valueFrom": {{Name: "abc", Value: "foo", ValueFrom: &api.EnvVarSource{SecretKeyRef: &api.SecretKeySelector{Name: "secret", Key: "key"}}}},
+		"empty source":        {{Name: "abc", ValueFrom: &api.EnvVarSource{}}},
+		"missing secret name": {{Name: "abc", ValueFrom: &api.EnvVarSource{SecretKeyRef: &api.SecretKeySelector{Key: "key"}}}},
+		"missing secret key":  {{Name: "abc", ValueFrom: &api.EnvVarSource{SecretKeyRef: &api.SecretKeySelector{Name: "secret"}}}},
+		"invalid secret key":  {{Name: "abc", ValueFrom: &api.EnvVarSource{SecretKeyRef: &api.SecretKeySelector{Name: "secret", Key: "a..b"}}}},
+		"missing fieldPath":   {{Name: "abc", ValueFrom: &api.EnvVarSource{FieldRef: &api.ObjectFieldSelector{}}}},
+		"unsupported field":   {{Name: "abc", ValueFrom: &api.EnvVarSource{FieldRef: &api.ObjectFieldSelector{FieldPath: "metadata.labels"}}}},
+		"two sources": {{Name: "abc", ValueFrom: &api.EnvVarSource{
+			SecretKeyRef: &api.SecretKeySelector{Name: "secret", Key: "key"},
+			FieldRef:     &api.ObjectFieldSelector{FieldPath: "metadata.name"},
+		}}},
+	}
+	for k, v := range valueFromErrorCases {
+		if errs := validateEnv(v); len(errs) == 0 {
+			t.Errorf("expected failure for %s", k)
+		}
+	}
 }
 
 func TestValidateVolumeMounts(t *testing.T) {
@@ -532,6 +591,7 @@ func TestValidateProbe(t *testing.T) {
 		nil,
 		{TimeoutSeconds: 10, InitialDelaySeconds: 0, Handler: handler},
 		{TimeoutSeconds: 0, InitialDelaySeconds: 10, Handler: handler},
+		{PeriodSeconds: 5, SuccessThreshold: 2, FailureThreshold: 5, Handler: handler},
 	}
 	for _, p := range successCases {
 		if errs := validateProbe(p); len(errs) != 0 {
@@ -544,6 +604,9 @@ func TestValidateProbe(t *testing.T) {
 		{TimeoutSeconds: 10, InitialDelaySeconds: -10, Handler: handler},
 		{TimeoutSeconds: -10, InitialDelaySeconds: 10, Handler: handler},
 		{TimeoutSeconds: -10, InitialDelaySeconds: -10, Handler: handler},
+		{PeriodSeconds: -1, Handler: handler},
+		{SuccessThreshold: -1, Handler: handler},
+		{FailureThreshold: -1, Handler: handler},
 	}
 	for _, p := range errorCases {
 		if errs := validateProbe(p); len(errs) == 0 {
@@ -662,6 +725,15 @@ func TestValidateContainers(t *testing.T) {
 			},
 			ImagePullPolicy: "IfNotPresent",
 		},
+		{
+			Name:  "requests-test",
+			Image: "image",
+			Resources: api.ResourceRequirements{
+				Limits:   getResourceLimits("10", "10G"),
+				Requests: getResourceLimits("500m", "10G"),
+			},
+			ImagePullPolicy: "IfNotPresent",
+		},
 		{Name: "abc-1234", Image: "image", Privileged: true, ImagePullPolicy: "IfNotPresent"},
 	}
 	if errs := validateContainers(successCase, volumes); len(errs) != 0 {
@@ -810,6 +882,27 @@ func TestValidateContainers(t *testing.T) {
 				ImagePullPolicy: "IfNotPresent",
 			},
 		},
+		"Request CPU invalid": {
+			{
+				Name:  "abc-123",
+				Image: "image",
+				Resources: api.ResourceRequirements{
+					Requests: getResourceLimits("-10", "0"),
+				},
+				ImagePullPolicy: "IfNotPresent",
+			},
+		},
+		"Request over limit": {
+			{
+				Name:  "abc-123",
+				Image: "image",
+				Resources: api.ResourceRequirements{
+					Limits:   getResourceLimits("1", "1G"),
+					Requests: getResourceLimits("2", "1G"),
+				},
+				ImagePullPolicy: "IfNotPresent",
+			},
+		},
 	}
 	for k, v := range errorCases {
 		if errs := validateContainers(v, volumes); len(errs) == 0 {
@@ -856,6 +949,8 @@ func TestValidateDNSPolicy(t *testing.T) {
 }
 
 func TestValidatePodSpec(t *testing.T) {
+	gracePeriod := int64(30)
+	negativeGracePeriod := int64(-1)
 	successCases := []api.PodSpec{
 		{ // Populate basic fields, leave defaults for most.
 			Volumes:       []api.Volume{{Name: "vol", VolumeSource: api.VolumeSource{EmptyDir: &api.EmptyDirVolumeSource{}}}},
@@ -872,8 +967,9 @@ func TestValidatePodSpec(t *testing.T) {
 			NodeSelector: map[string]string{
 				"key": "value",
 			},
-			Host:      "foobar",
-			DNSPolicy: api.DNSClusterFirst,
+			Host:                          "foobar",
+			DNSPolicy:                     api.DNSClusterFirst,
+			TerminationGracePeriodSeconds: &gracePeriod,
 		},
 		{ // Populate HostNetwork.
 			Containers: []api.Container{
@@ -928,6 +1024,12 @@ func TestValidatePodSpec(t *testing.T) {
 			RestartPolicy: api.RestartPolicyAlways,
 			DNSPolicy:     api.DNSClusterFirst,
 		},
+		"negative termination grace period": {
+			Containers:                    []api.Container{{Name: "ctr", Image: "image", ImagePullPolicy: "IfNotPresent"}},
+			RestartPolicy:                 api.RestartPolicyAlways,
+			DNSPolicy:                     api.DNSClusterFirst,
+			TerminationGracePeriodSeconds: &negativeGracePeriod,
+		},
 	}
 	for k, v := range failureCases {
 		if errs := ValidatePodSpec(&v); len(errs) == 0 {
@@ -1307,14 +1409,43 @@ func TestValidateService(t *testing.T) {
 		{
 			name: "missing protocol",
 			makeSvc: func(s *api.Service) {
-				s.Spec.Protocol = ""
+				s.Spec.Ports[0].Protocol = ""
 			},
 			numErrs: 1,
 		},
 		{
 			name: "invalid protocol",
 			makeSvc: func(s *api.Service) {
-				s.Spec.Protocol = "INVALID"
+				s.Spec.Ports[0].Protocol = "INVALID"
+			},
+			numErrs: 1,
+		},
+		{
+			name: "missing ports",
+			makeSvc: func(s *api.Service) {
+				s.Spec.Ports = nil
+			},
+			numErrs: 1,
+		},
+		{
+			name: "missing port name with multiple ports",
+			makeSvc: func(s *api.Service) {
+				s.Spec.Ports = append(s.Spec.Ports, api.ServicePort{Name: "q", Port: 12345, Protocol: "TCP"})
+			},
+			numErrs: 1,
+		},
+		{
+			name: "invalid port name",
+			makeSvc: func(s *api.Service) {
+				s.Spec.Ports[0].Name = "INVALID"
+			},
+			numErrs: 1,
+		},
+		{
+			name: "dup port name",
+			makeSvc: func(s *api.Service) {
+				s.Spec.Ports[0].Name = "p"
+				s.Spec.Ports = append(s.Spec.Ports, api.ServicePort{Name: "p", Port: 12345, Protocol: "TCP"})
 			},
 			numErrs: 1,
 		},
@@ -1328,28 +1459,28 @@ func TestValidateService(t *testing.T) {
 		{
 			name: "missing port",
 			makeSvc: func(s *api.Service) {
-				s.Spec.Port = 0
+				s.Spec.Ports[0].Port = 0
 			},
 			numErrs: 1,
 		},
 		{
 			name: "invalid port",
 			makeSvc: func(s *api.Service) {
-				s.Spec.Port = 65536
+				s.Spec.Ports[0].Port = 65536
 			},
 			numErrs: 1,
 		},
 		{
 			name: "missing targetPort string",
 			makeSvc: func(s *api.Service) {
-				s.Spec.TargetPort = util.NewIntOrStringFromString("")
+				s.Spec.Ports[0].TargetPort = util.NewIntOrStringFromString("")
 			},
 			numErrs: 1,
 		},
 		{
 			name: "invalid targetPort int",
 			makeSvc: func(s *api.Service) {
-				s.Spec.TargetPort = util.NewIntOrStringFromInt(65536)
+				s.Spec.Ports[0].TargetPort = util.NewIntOrStringFromInt(65536)
 			},
 			numErrs: 1,
 		},
@@ -1391,15 +1522,23 @@ func TestValidateService(t *testing.T) {
 		{
 			name: "valid 2",
 			makeSvc: func(s *api.Service) {
-				s.Spec.Protocol = "UDP"
-				s.Spec.TargetPort = util.NewIntOrStringFromInt(12345)
+				s.Spec.Ports[0].Protocol = "UDP"
+				s.Spec.Ports[0].TargetPort = util.NewIntOrStringFromInt(12345)
 			},
 			numErrs: 0,
 		},
 		{
 			name: "valid 3",
 			makeSvc: func(s *api.Service) {
-				s.Spec.TargetPort = util.NewIntOrStringFromString("http")
+				s.Spec.Ports[0].TargetPort = util.NewIntOrStringFromString("http")
+			},
+			numErrs: 0,
+		},
+		{
+			name: "valid 4",
+			makeSvc: func(s *api.Service) {
+				s.Spec.Ports[0].Name = "p"
+				s.Spec.Ports = append(s.Spec.Ports, api.ServicePort{Name: "q", Port: 12345, Protocol: "TCP", TargetPort: util.NewIntOrStringFromString("http")})
 			},
 			numErrs: 0,
 		},
@@ -1430,8 +1569,7 @@ func TestValidateService(t *testing.T) {
 			Spec: api.ServiceSpec{
 				Selector:        map[string]string{"key": "val"},
 				SessionAffinity: "None",
-				Port:            8675,
-				Protocol:        "TCP",
+				Ports:           []api.ServicePort{{Port: 8675, Protocol: "TCP"}},
 			},
 		}
 		tc.makeSvc(&svc)
@@ -1666,6 +1804,26 @@ func TestValidateReplicationController(t *testing.T) {
 				Template: &readWriteVolumePodTemplate.Spec,
 			},
 		},
+		{
+			ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault},
+			Spec: api.ReplicationControllerSpec{
+				Selector: validSelector,
+				SelectorRequirements: []api.LabelSelectorRequirement{
+					{Key: "a", Operator: api.LabelSelectorOpIn, Values: []string{"b", "c"}},
+					{Key: "d", Operator: api.LabelSelectorOpNotIn, Values: []string{"e"}},
+				},
+				Template: &validPodTemplate.Spec,
+			},
+		},
+		{
+			ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault},
+			Spec: api.ReplicationControllerSpec{
+				SelectorRequirements: []api.LabelSelectorRequirement{
+					{Key: "a", Operator: api.LabelSelectorOpExists},
+				},
+				Template: &validPodTemplate.Spec,
+			},
+		},
 	}
 	for _, successCase := range successCases {
 		if errs := ValidateReplicationController(&successCase); len(errs) != 0 {
@@ -1701,6 +1859,36 @@ func TestValidateReplicationController(t *testing.T) {
 				Template: &validPodTemplate.Spec,
 			},
 		},
+		"selector requirement doesnt match": {
+			ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault},
+			Spec: api.ReplicationControllerSpec{
+				Selector: validSelector,
+				SelectorRequirements: []api.LabelSelectorRequirement{
+					{Key: "a", Operator: api.LabelSelectorOpNotIn, Values: []string{"b"}},
+				},
+				Template: &validPodTemplate.Spec,
+			},
+		},
+		"exists selector requirement with values": {
+			ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault},
+			Spec: api.ReplicationControllerSpec{
+				Selector: validSelector,
+				SelectorRequirements: []api.LabelSelectorRequirement{
+					{Key: "a", Operator: api.LabelSelectorOpExists, Values: []string{"b"}},
+				},
+				Template: &validPodTemplate.Spec,
+			},
+		},
+		"unsupported selector requirement operator": {
+			ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault},
+			Spec: api.ReplicationControllerSpec{
+				Selector: validSelector,
+				SelectorRequirements: []api.LabelSelectorRequirement{
+					{Key: "a", Operator: "Like", Values: []string{"b"}},
+				},
+				Template: &validPodTemplate.Spec,
+			},
+		},
 		"invalid manifest": {
 			ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault},
 			Spec: api.ReplicationControllerSpec{
@@ -1794,6 +1982,7 @@ func TestValidateReplicationController(t *testing.T) {
 		for i := range errs {
 			field := errs[i].(*errors.ValidationError).Field
 			if !strings.HasPrefix(field, "spec.template.") &&
+				!strings.HasPrefix(field, "spec.selectorRequirements[") &&
 				field != "metadata.name" &&
 				field != "metadata.namespace" &&
 				field != "spec.selector" &&
@@ -2418,6 +2607,8 @@ func TestValidateResourceQuota(t *testing.T) {
 			api.ResourceServices:               resource.MustParse("10"),
 			api.ResourceReplicationControllers: resource.MustParse("10"),
 			api.ResourceQuotas:                 resource.MustParse("10"),
+			api.ResourceSecrets:                resource.MustParse("10"),
+			api.ResourcePersistentVolumeClaims: resource.MustParse("10"),
 		},
 	}
 
@@ -2688,6 +2879,14 @@ func TestValidateSecret(t *testing.T) {
 		invalidNs   = validSecret()
 		overMaxSize = validSecret()
 		invalidKey  = validSecret()
+		dotKey      = validSecret()
+		doubleDot   = validSecret()
+
+		validDockercfg = validSecret()
+		noDockercfg    = validSecret()
+		badDockercfg   = validSecret()
+		validTLS       = validSecret()
+		noTLSKey       = validSecret()
 	)
 
 	emptyName.Name = ""
@@ -2698,6 +2897,19 @@ func TestValidateSecret(t *testing.T) {
 		"over": make([]byte, api.MaxSecretSize+1),
 	}
 	invalidKey.Data["a..b"] = []byte("whoops")
+	dotKey.Data[".dotfile"] = []byte("bar")
+	doubleDot.Data["..dotfile"] = []byte("bar")
+
+	validDockercfg.Type = api.SecretTypeDockercfg
+	validDockercfg.Data[api.DockerConfigKey] = []byte(`{"registry.example.com": {"auth": "Zm9vOmJhcg==", "email": "foo@example.com"}}`)
+	noDockercfg.Type = api.SecretTypeDockercfg
+	badDockercfg.Type = api.SecretTypeDockercfg
+	badDockercfg.Data[api.DockerConfigKey] = []byte("not json")
+	validTLS.Type = api.SecretTypeTLS
+	validTLS.Data[api.TLSCertKey] = []byte("cert")
+	validTLS.Data[api.TLSPrivateKeyKey] = []byte("key")
+	noTLSKey.Type = api.SecretTypeTLS
+	noTLSKey.Data[api.TLSCertKey] = []byte("cert")
 
 	tests := map[string]struct {
 		secret api.Secret
@@ -2710,6 +2922,13 @@ func TestValidateSecret(t *testing.T) {
 		"invalid namespace": {invalidNs, false},
 		"over max size":     {overMaxSize, false},
 		"invalid key":       {invalidKey, false},
+		"dot key":           {dotKey, true},
+		"double dot key":    {doubleDot, false},
+		"valid dockercfg":   {validDockercfg, true},
+		"missing dockercfg": {noDockercfg, false},
+		"invalid dockercfg": {badDockercfg, false},
+		"valid tls":         {validTLS, true},
+		"missing tls key":   {noTLSKey, false},
 	}
 
 	for name, tc := range tests {
@@ -2722,3 +2941,197 @@ func TestValidateSecret(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateSecretUpdate(t *testing.T) {
+	oldSecret := api.Secret{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "bar", ResourceVersion: "1"},
+		Data:       map[string][]byte{"data-1": []byte("bar")},
+		Type:       api.SecretTypeOpaque,
+	}
+
+	newData := oldSecret
+	newData.Data = map[string][]byte{"data-1": []byte("baz")}
+	if errs := ValidateSecretUpdate(&newData, &oldSecret); len(errs) != 0 {
+		t.Errorf("expected success: %v", errs)
+	}
+
+	newType := oldSecret
+	newType.Type = api.SecretTypeTLS
+	newType.Data = map[string][]byte{api.TLSCertKey: []byte("cert"), api.TLSPrivateKeyKey: []byte("key")}
+	if errs := ValidateSecretUpdate(&newType, &oldSecret); len(errs) == 0 {
+		t.Errorf("expected failure when changing the secret type")
+	}
+}
+
+func TestValidateEndpoints(t *testing.T) {
+	validEndpoints := func() api.Endpoints {
+		return api.Endpoints{
+			ObjectMeta: api.ObjectMeta{Name: "mysvc", Namespace: "namespace"},
+			Subsets: []api.EndpointSubset{
+				{
+					Addresses: []api.EndpointAddress{{IP: "10.10.1.1"}, {IP: "10.10.2.2"}},
+					Ports:     []api.EndpointPort{{Name: "a", Port: 8675, Protocol: "TCP"}, {Name: "b", Port: 309, Protocol: "TCP"}},
+				},
+				{
+					Addresses: []api.EndpointAddress{{IP: "10.10.3.3"}},
+					Ports:     []api.EndpointPort{{Port: 93, Protocol: "UDP"}},
+				},
+			},
+		}
+	}
+
+	var (
+		noAddresses    = validEndpoints()
+		noPorts        = validEndpoints()
+		invalidIP      = validEndpoints()
+		missingName    = validEndpoints()
+		invalidPort    = validEndpoints()
+		invalidProto   = validEndpoints()
+		noSubsets      = validEndpoints()
+		invalidObjName = validEndpoints()
+	)
+
+	noAddresses.Subsets[0].Addresses = nil
+	noPorts.Subsets[1].Ports = nil
+	invalidIP.Subsets[0].Addresses[1].IP = "foo.bar"
+	missingName.Subsets[0].Ports[1].Name = ""
+	invalidPort.Subsets[1].Ports[0].Port = 65536
+	invalidProto.Subsets[1].Ports[0].Protocol = "ICMP"
+	noSubsets.Subsets = nil
+	invalidObjName.Name = "NoUppercaseOrSpecialCharsLike=Equals"
+
+	tests := map[string]struct {
+		endpoints api.Endpoints
+		valid     bool
+	}{
+		"valid":                   {validEndpoints(), true},
+		"no subsets":              {noSubsets, true},
+		"no addresses":            {noAddresses, false},
+		"no ports":                {noPorts, false},
+		"invalid ip":              {invalidIP, false},
+		"missing name with ports": {missingName, false},
+		"invalid port":            {invalidPort, false},
+		"invalid protocol":        {invalidProto, false},
+		"invalid name":            {invalidObjName, false},
+	}
+
+	for name, tc := range tests {
+		errs := ValidateEndpoints(&tc.endpoints)
+		if tc.valid && len(errs) > 0 {
+			t.Errorf("%v: Unexpected error: %v", name, errs)
+		}
+		if !tc.valid && len(errs) == 0 {
+			t.Errorf("%v: Unexpected non-error", name)
+		}
+	}
+}
+
+func TestValidateRole(t *testing.T) {
+	validRole := func() api.Role {
+		return api.Role{
+			ObjectMeta: api.ObjectMeta{Name: "pod-status-updater", Namespace: "bar"},
+			Rules:      []api.PolicyRule{{Verbs: []string{"update"}, Resources: []string{"pods/status"}}},
+		}
+	}
+
+	var (
+		emptyNs        = validRole()
+		noVerbs        = validRole()
+		noResources    = validRole()
+		clusterNoVerbs = api.ClusterRole{ObjectMeta: api.ObjectMeta{Name: "admin"}, Rules: []api.PolicyRule{{Resources: []string{"*"}}}}
+	)
+	emptyNs.Namespace = ""
+	noVerbs.Rules[0].Verbs = nil
+	noResources.Rules[0].Resources = nil
+
+	tests := map[string]struct {
+		role  api.Role
+		valid bool
+	}{
+		"valid":           {validRole(), true},
+		"empty namespace": {emptyNs, false},
+		"no verbs":        {noVerbs, false},
+		"no resources":    {noResources, false},
+	}
+	for name, tc := range tests {
+		errs := ValidateRole(&tc.role)
+		if tc.valid && len(errs) > 0 {
+			t.Errorf("%v: Unexpected error: %v", name, errs)
+		}
+		if !tc.valid && len(errs) == 0 {
+			t.Errorf("%v: Unexpected non-error", name)
+		}
+	}
+
+	if errs := ValidateClusterRole(&api.ClusterRole{ObjectMeta: api.ObjectMeta{Name: "admin"}, Rules: []api.PolicyRule{{Verbs: []string{"*"}, Resources: []string{"*"}}}}); len(errs) != 0 {
+		t.Errorf("Unexpected error: %v", errs)
+	}
+	if errs := ValidateClusterRole(&clusterNoVerbs); len(errs) == 0 {
+		t.Errorf("Unexpected non-error")
+	}
+}
+
+func TestValidateRoleBinding(t *testing.T) {
+	validBinding := func() api.RoleBinding {
+		return api.RoleBinding{
+			ObjectMeta: api.ObjectMeta{Name: "tenant-status", Namespace: "bar"},
+			Subjects:   []api.Subject{{Kind: api.UserKind, Name: "alice"}, {Kind: api.GroupKind, Name: "tenants"}},
+			RoleRef:    api.RoleRef{Kind: "Role", Name: "pod-status-updater"},
+		}
+	}
+
+	var (
+		clusterRoleRef = validBinding()
+		badRoleKind    = validBinding()
+		noRoleName     = validBinding()
+		badSubjectKind = validBinding()
+		noSubjectName  = validBinding()
+	)
+	clusterRoleRef.RoleRef.Kind = "ClusterRole"
+	badRoleKind.RoleRef.Kind = "Pod"
+	noRoleName.RoleRef.Name = ""
+	badSubjectKind.Subjects[0].Kind = "Robot"
+	noSubjectName.Subjects[1].Name = ""
+
+	tests := map[string]struct {
+		binding api.RoleBinding
+		valid   bool
+	}{
+		"valid":              {validBinding(), true},
+		"cluster role":       {clusterRoleRef, true},
+		"invalid role kind":  {badRoleKind, false},
+		"empty role name":    {noRoleName, false},
+		"invalid subject":    {badSubjectKind, false},
+		"empty subject name": {noSubjectName, false},
+	}
+	for name, tc := range tests {
+		errs := ValidateRoleBinding(&tc.binding)
+		if tc.valid && len(errs) > 0 {
+			t.Errorf("%v: Unexpected error: %v", name, errs)
+		}
+		if !tc.valid && len(errs) == 0 {
+			t.Errorf("%v: Unexpected non-error", name)
+		}
+	}
+
+	updated := validBinding()
+	updated.ResourceVersion = "1"
+	old := updated
+	updated.RoleRef.Name = "other"
+	if errs := ValidateRoleBindingUpdate(&old, &updated); len(errs) == 0 {
+		t.Errorf("Expected the role reference to be immutable")
+	}
+
+	clusterBinding := api.ClusterRoleBinding{
+		ObjectMeta: api.ObjectMeta{Name: "admins"},
+		Subjects:   []api.Subject{{Kind: api.GroupKind, Name: "admins"}},
+		RoleRef:    api.RoleRef{Kind: "Role", Name: "admin"},
+	}
+	if errs := ValidateClusterRoleBinding(&clusterBinding); len(errs) == 0 {
+		t.Errorf("Expected a cluster role binding to reject a Role")
+	}
+	clusterBinding.RoleRef.Kind = "ClusterRole"
+	if errs := ValidateClusterRoleBinding(&clusterBinding); len(errs) != 0 {
+		t.Errorf("Unexpected error: %v", errs)
+	}
+}
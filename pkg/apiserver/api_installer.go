@@ -38,6 +38,9 @@ type APIInstaller struct {
 	group  *APIGroupVersion
 	info   *APIRequestInfoResolver
 	prefix string // Path prefix where API resources are to be registered.
+
+	// resources describes the top level resources registered so far, for discovery.
+	resources []api.APIResource
 }
 
 // Struct capturing information about an action ("GET", "POST", "WATCH", PROXY", etc).
@@ -152,6 +155,21 @@ func (a *APIInstaller) registerResourceHandlers(path string, storage rest.Storag
 		storageMeta = defaultStorageMetadata{}
 	}
 
+	if len(subresource) == 0 {
+		a.resources = append(a.resources, api.APIResource{
+			Name:       resource,
+			Namespaced: mapping.Scope.Name() == meta.RESTScopeNameNamespace,
+			Verbs: appendVerbs(nil,
+				verb{"create", isCreater},
+				verb{"delete", isDeleter || isGracefulDeleter},
+				verb{"get", isGetter},
+				verb{"list", isLister},
+				verb{"update", isUpdater},
+				verb{"watch", isWatcher},
+			),
+		})
+	}
+
 	var versionedDeleterObject runtime.Object
 	switch {
 	case isGracefulDeleter:
@@ -288,11 +306,20 @@ func (a *APIInstaller) registerResourceHandlers(path string, storage rest.Storag
 	// test/integration/auth_test.go is currently the most comprehensive status code test
 
 	reqScope := RequestScope{
-		ContextFunc: ctxFn,
-		Codec:       mapping.Codec,
-		APIVersion:  a.group.Version,
-		Resource:    resource,
-		Kind:        kind,
+		ContextFunc:   ctxFn,
+		Codec:         mapping.Codec,
+		ProtobufCodec: a.group.ProtobufCodec,
+		Creater:       a.group.Creater,
+		APIVersion:    a.group.Version,
+		Resource:      resource,
+		Kind:          kind,
+	}
+
+	// Objects are written as JSON, or as protobuf to clients that accept it if
+	// the group has a protobuf codec. Watch events are always JSON.
+	encodings := []string{"application/json"}
+	if a.group.ProtobufCodec != nil {
+		encodings = append(encodings, runtime.ProtobufContentType)
 	}
 	for _, action := range actions {
 		reqScope.Namer = action.Namer
@@ -303,7 +330,7 @@ func (a *APIInstaller) registerResourceHandlers(path string, storage rest.Storag
 				Filter(m).
 				Doc("read the specified " + kind).
 				Operation("read" + kind).
-				Produces(append(storageMeta.ProducesMIMETypes(action.Verb), "application/json")...).
+				Produces(append(storageMeta.ProducesMIMETypes(action.Verb), encodings...)...).
 				Writes(versionedObject)
 			addParams(route, action.Params)
 			ws.Route(route)
@@ -312,7 +339,7 @@ func (a *APIInstaller) registerResourceHandlers(path string, storage rest.Storag
 				Filter(m).
 				Doc("list objects of kind " + kind).
 				Operation("list" + kind).
-				Produces("application/json").
+				Produces(encodings...).
 				Writes(versionedList)
 			addParams(route, action.Params)
 			ws.Route(route)
@@ -321,7 +348,7 @@ func (a *APIInstaller) registerResourceHandlers(path string, storage rest.Storag
 				Filter(m).
 				Doc("replace the specified " + kind).
 				Operation("replace" + kind).
-				Produces(append(storageMeta.ProducesMIMETypes(action.Verb), "application/json")...).
+				Produces(append(storageMeta.ProducesMIMETypes(action.Verb), encodings...)...).
 				Reads(versionedObject)
 			addParams(route, action.Params)
 			ws.Route(route)
@@ -329,10 +356,11 @@ func (a *APIInstaller) registerResourceHandlers(path string, storage rest.Storag
 			route := ws.PATCH(action.Path).To(PatchResource(patcher, reqScope, a.group.Typer, admit)).
 				Filter(m).
 				Doc("partially update the specified " + kind).
-				// TODO: toggle patch strategy by content type
-				// Consumes("application/merge-patch+json", "application/json-patch+json").
+				// The patch strategy is chosen by PatchResource from the content type;
+				// the route still consumes any type so that clients that do not set
+				// one keep getting a merge patch.
 				Operation("patch" + kind).
-				Produces(append(storageMeta.ProducesMIMETypes(action.Verb), "application/json")...).
+				Produces(append(storageMeta.ProducesMIMETypes(action.Verb), encodings...)...).
 				Reads(versionedObject)
 			addParams(route, action.Params)
 			ws.Route(route)
@@ -341,7 +369,7 @@ func (a *APIInstaller) registerResourceHandlers(path string, storage rest.Storag
 				Filter(m).
 				Doc("create a " + kind).
 				Operation("create" + kind).
-				Produces(append(storageMeta.ProducesMIMETypes(action.Verb), "application/json")...).
+				Produces(append(storageMeta.ProducesMIMETypes(action.Verb), encodings...)...).
 				Reads(versionedObject)
 			addParams(route, action.Params)
 			ws.Route(route)
@@ -350,7 +378,7 @@ func (a *APIInstaller) registerResourceHandlers(path string, storage rest.Storag
 				Filter(m).
 				Doc("delete a " + kind).
 				Operation("delete" + kind).
-				Produces(append(storageMeta.ProducesMIMETypes(action.Verb), "application/json")...)
+				Produces(append(storageMeta.ProducesMIMETypes(action.Verb), encodings...)...)
 			if isGracefulDeleter {
 				route.Reads(versionedDeleterObject)
 			}
@@ -620,6 +648,22 @@ func indirectArbitraryPointer(ptrToObject interface{}) interface{} {
 	return reflect.Indirect(reflect.ValueOf(ptrToObject)).Interface()
 }
 
+// verb is a verb reported by discovery and whether a resource supports it.
+type verb struct {
+	name      string
+	supported bool
+}
+
+// appendVerbs appends the names of the supported verbs to verbs.
+func appendVerbs(verbs []string, candidates ...verb) []string {
+	for _, v := range candidates {
+		if v.supported {
+			verbs = append(verbs, v.name)
+		}
+	}
+	return verbs
+}
+
 func appendIf(actions []action, a action, shouldAppend bool) []action {
 	if shouldAppend {
 		actions = append(actions, a)
@@ -104,7 +104,11 @@ type APIGroupVersion struct {
 
 	Mapper meta.RESTMapper
 
-	Codec   runtime.Codec
+	Codec runtime.Codec
+	// ProtobufCodec, if set, encodes objects to clients that accept
+	// runtime.ProtobufContentType. Codec must decode what it encodes.
+	ProtobufCodec runtime.Codec
+
 	Typer   runtime.ObjectTyper
 	Creater runtime.ObjectCreater
 	Linker  runtime.SelfLinker
@@ -126,6 +130,11 @@ func (g *APIGroupVersion) InstallREST(container *restful.Container) error {
 		prefix: prefix,
 	}
 	ws, registrationErrors := installer.Install()
+	ws.Route(ws.GET("/").To(APIResourceHandler(g.Version, installer.resources)).
+		Doc("get available resources").
+		Operation("getAPIResources").
+		Produces(restful.MIME_JSON).
+		Consumes(restful.MIME_JSON))
 	container.Add(ws)
 	return errors.NewAggregate(registrationErrors)
 }
@@ -197,8 +206,17 @@ func APIVersionHandler(versions ...string) restful.RouteFunction {
 	}
 }
 
+// APIResourceHandler returns a handler which will list the provided resources as available in version.
+func APIResourceHandler(version string, resources []api.APIResource) restful.RouteFunction {
+	return func(req *restful.Request, resp *restful.Response) {
+		// TODO: use restful's Response methods
+		writeRawJSON(http.StatusOK, api.APIResourceList{APIVersion: version, Resources: resources}, resp.ResponseWriter)
+	}
+}
+
 // write renders a returned runtime.Object to the response as a stream or an encoded object.
-func write(statusCode int, apiVersion string, codec runtime.Codec, object runtime.Object, w http.ResponseWriter, req *http.Request) {
+// The object is encoded with protobufCodec if it is set and the request accepts protobuf.
+func write(statusCode int, apiVersion string, codec, protobufCodec runtime.Codec, object runtime.Object, w http.ResponseWriter, req *http.Request) {
 	if stream, ok := object.(rest.ResourceStreamer); ok {
 		out, contentType, err := stream.InputStream(apiVersion, req.Header.Get("Accept"))
 		if err != nil {
@@ -214,9 +232,40 @@ func write(statusCode int, apiVersion string, codec runtime.Codec, object runtim
 		io.Copy(w, out)
 		return
 	}
+	if protobufCodec != nil && acceptsProtobuf(req) {
+		writeProtobuf(statusCode, codec, protobufCodec, object, w)
+		return
+	}
 	writeJSON(statusCode, codec, object, w)
 }
 
+// acceptsProtobuf returns true if the Accept header of the request lists
+// runtime.ProtobufContentType.
+func acceptsProtobuf(req *http.Request) bool {
+	for _, accept := range strings.Split(req.Header.Get("Accept"), ",") {
+		if i := strings.Index(accept, ";"); i >= 0 {
+			accept = accept[:i]
+		}
+		if strings.TrimSpace(accept) == runtime.ProtobufContentType {
+			return true
+		}
+	}
+	return false
+}
+
+// writeProtobuf renders an object in the protobuf wire format to the response.
+// Errors are rendered as JSON with codec, which clients of either format decode.
+func writeProtobuf(statusCode int, codec, protobufCodec runtime.Codec, object runtime.Object, w http.ResponseWriter) {
+	output, err := protobufCodec.Encode(object)
+	if err != nil {
+		errorJSONFatal(err, codec, w)
+		return
+	}
+	w.Header().Set("Content-Type", runtime.ProtobufContentType)
+	w.WriteHeader(statusCode)
+	w.Write(output)
+}
+
 // writeJSON renders an object as JSON to the response.
 func writeJSON(statusCode int, codec runtime.Codec, object runtime.Object, w http.ResponseWriter) {
 	output, err := codec.Encode(object)
@@ -164,6 +164,33 @@ func handleInternal(storage map[string]rest.Storage, admissionControl admission.
 		Admit:   admissionControl,
 		Context: requestContextMapper,
 	}
+	return install(group)
+}
+
+// handleProtobuf uses the default settings and encodes objects with
+// protobufCodec to clients that accept protobuf.
+func handleProtobuf(storage map[string]rest.Storage, protobufCodec runtime.Codec) http.Handler {
+	group := &APIGroupVersion{
+		Storage: storage,
+
+		Mapper: mapper,
+
+		Root:    "/api",
+		Version: testVersion,
+
+		Creater:       api.Scheme,
+		Typer:         api.Scheme,
+		Codec:         codec,
+		ProtobufCodec: protobufCodec,
+		Linker:        selfLinker,
+
+		Admit:   admissionControl,
+		Context: requestContextMapper,
+	}
+	return install(group)
+}
+
+func install(group *APIGroupVersion) http.Handler {
 	container := restful.NewContainer()
 	container.Router(restful.CurlyRouter{})
 	mux := container.ServeMux
@@ -489,6 +516,35 @@ func TestUnimplementedRESTStorage(t *testing.T) {
 	}
 }
 
+func TestAPIResources(t *testing.T) {
+	handler := handle(map[string]rest.Storage{
+		"simple":        &SimpleRESTStorage{},
+		"simple/status": &SimpleRESTStorage{},
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	response, err := http.Get(server.URL + "/api/" + testVersion)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer response.Body.Close()
+
+	var list api.APIResourceList
+	if err := json.NewDecoder(response.Body).Decode(&list); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := api.APIResourceList{
+		APIVersion: testVersion,
+		Resources: []api.APIResource{
+			{Name: "simple", Namespaced: true, Verbs: []string{"create", "delete", "get", "list", "update", "watch"}},
+		},
+	}
+	if !reflect.DeepEqual(expected, list) {
+		t.Errorf("Expected %#v, Got %#v", expected, list)
+	}
+}
+
 func TestVersion(t *testing.T) {
 	handler := handle(map[string]rest.Storage{})
 	server := httptest.NewServer(handler)
@@ -774,6 +830,81 @@ func TestGetBinary(t *testing.T) {
 	}
 }
 
+// prefixCodec marks the objects it encodes, so that tests can tell which
+// codec wrote a response.
+type prefixCodec struct {
+	runtime.Codec
+	prefix string
+}
+
+func (c prefixCodec) Encode(obj runtime.Object) ([]byte, error) {
+	data, err := c.Codec.Encode(obj)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(c.prefix), data...), nil
+}
+
+func TestGetProtobuf(t *testing.T) {
+	storage := map[string]rest.Storage{
+		"simple": &SimpleRESTStorage{item: Simple{Other: "foo"}},
+	}
+	server := httptest.NewServer(handleProtobuf(storage, prefixCodec{codec, "protobuf:"}))
+	defer server.Close()
+
+	table := []struct {
+		accept      string
+		contentType string
+		protobuf    bool
+	}{
+		{"", "application/json", false},
+		{"application/json", "application/json", false},
+		{"application/x-protobuf", "application/x-protobuf", true},
+		{"application/json;q=0.9, application/x-protobuf", "application/x-protobuf", true},
+	}
+	for _, path := range []string{"/api/version/simple/id", "/api/version/simple"} {
+		for _, item := range table {
+			req, _ := http.NewRequest("GET", server.URL+path, nil)
+			if item.accept != "" {
+				req.Header.Set("Accept", item.accept)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			body, err := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("%s %q: unexpected response: %d %s", path, item.accept, resp.StatusCode, string(body))
+				continue
+			}
+			if contentType := resp.Header.Get("Content-Type"); contentType != item.contentType {
+				t.Errorf("%s %q: expected content type %s, got %s", path, item.accept, item.contentType, contentType)
+			}
+			if isProtobuf := strings.HasPrefix(string(body), "protobuf:"); isProtobuf != item.protobuf {
+				t.Errorf("%s %q: expected protobuf %t, got %s", path, item.accept, item.protobuf, string(body))
+			}
+		}
+	}
+
+	// Without a protobuf codec, clients that only accept protobuf are refused.
+	server = httptest.NewServer(handle(storage))
+	defer server.Close()
+	req, _ := http.NewRequest("GET", server.URL+"/api/version/simple/id", nil)
+	req.Header.Set("Accept", "application/x-protobuf")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotAcceptable {
+		t.Errorf("expected %d, got %d", http.StatusNotAcceptable, resp.StatusCode)
+	}
+}
+
 func TestGetAlternateSelfLink(t *testing.T) {
 	storage := map[string]rest.Storage{}
 	simpleStorage := SimpleRESTStorage{
@@ -1068,6 +1199,65 @@ func TestPatch(t *testing.T) {
 	}
 }
 
+func TestPatchContentTypes(t *testing.T) {
+	testCases := []struct {
+		contentType string
+		patch       string
+		expected    Simple
+	}{
+		{
+			contentType: "",
+			patch:       `{"labels":{"foo":"bar"}}`,
+			expected:    Simple{Other: "bar", Labels: map[string]string{"foo": "bar"}},
+		},
+		{
+			contentType: string(api.MergePatchType),
+			patch:       `{"other":null,"labels":{"foo":"bar"}}`,
+			expected:    Simple{Labels: map[string]string{"foo": "bar"}},
+		},
+		{
+			contentType: string(api.StrategicMergePatchType) + "; charset=utf-8",
+			patch:       `{"labels":{"foo":"bar"}}`,
+			expected:    Simple{Other: "bar", Labels: map[string]string{"foo": "bar"}},
+		},
+		{
+			contentType: string(api.JSONPatchType),
+			patch:       `[{"op":"replace","path":"/other","value":"baz"}]`,
+			expected:    Simple{Other: "baz"},
+		},
+	}
+	for _, test := range testCases {
+		storage := map[string]rest.Storage{}
+		ID := "id"
+		simpleStorage := SimpleRESTStorage{item: Simple{ObjectMeta: api.ObjectMeta{Name: ID}, Other: "bar"}}
+		storage["simple"] = &simpleStorage
+		handler := handle(storage)
+		server := httptest.NewServer(handler)
+
+		request, err := http.NewRequest("PATCH", server.URL+"/api/version/simple/"+ID, bytes.NewReader([]byte(test.patch)))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(test.contentType) != 0 {
+			request.Header.Set("Content-Type", test.contentType)
+		}
+		response, err := http.DefaultClient.Do(request)
+		server.Close()
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.contentType, err)
+			continue
+		}
+		if response.StatusCode != http.StatusOK {
+			t.Errorf("%q: unexpected response %#v", test.contentType, response)
+			continue
+		}
+		updated := simpleStorage.updated
+		if updated == nil || updated.Other != test.expected.Other || !reflect.DeepEqual(updated.Labels, test.expected.Labels) {
+			t.Errorf("%q: expected %#v, got %#v", test.contentType, test.expected, updated)
+		}
+	}
+}
+
 func TestPatchRequiresMatchingName(t *testing.T) {
 	storage := map[string]rest.Storage{}
 	ID := "id"
@@ -0,0 +1,188 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/httplog"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/golang/glog"
+)
+
+// maxAuditBodyBytes is the most bytes of a request or response body that is
+// written to the audit log.
+const maxAuditBodyBytes = 64 * 1024
+
+// WithAudit logs two lines to out for every request handled by handler, sharing
+// a unique ID for the request. The "accepted" line is written before handler is
+// called, so that long running requests like watches and exec sessions are
+// logged as they start, and holds the request attributes resolved by resolver.
+// The "completed" line is written once handler returns, and holds the same
+// attributes, the user and groups read from the request context, the response
+// code and the latency. The user is read once handler returns, so handler may
+// be the authenticator, and requests it rejects are logged without a user. The
+// request context must outlive handler for that. If logBodies is true, the
+// request and response bodies of requests that are not read-only are added to
+// the completed line. Every line is written with a single call to out, which
+// must be safe for concurrent use.
+func WithAudit(handler http.Handler, requestContextMapper api.RequestContextMapper, resolver *APIRequestInfoResolver, out io.Writer, logBodies bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		startTime := time.Now()
+		id := string(util.NewUUID())
+		info, _ := resolver.GetAPIRequestInfo(req)
+
+		respWriter := &auditResponseWriter{ResponseWriter: w}
+		var reqBody *auditBuffer
+		if logBodies && !IsReadOnlyReq(*req) {
+			reqBody = &auditBuffer{}
+			respWriter.body = &auditBuffer{}
+			if req.Body != nil {
+				req.Body = &auditReadCloser{req.Body, reqBody}
+			}
+		}
+
+		line := &bytes.Buffer{}
+		fmt.Fprintf(line, "%s AUDIT: id=%q stage=\"accepted\" ip=%q method=%q verb=%q namespace=%q resource=%q name=%q uri=%q\n",
+			startTime.UTC().Format(time.RFC3339Nano), id, remoteIP(req), req.Method, info.Verb, info.Namespace, info.Resource, info.Name, req.RequestURI)
+		writeAuditLine(out, id, line)
+
+		handler.ServeHTTP(respWriter, req)
+
+		userName, groups := "<none>", ""
+		if ctx, ok := requestContextMapper.Get(req); ok {
+			if user, ok := api.UserFrom(ctx); ok {
+				userName = user.GetName()
+				groups = strings.Join(user.GetGroups(), ",")
+			}
+		}
+		// Go replies with 200 when the handler writes nothing.
+		if respWriter.code == 0 {
+			respWriter.code = http.StatusOK
+		}
+
+		line = &bytes.Buffer{}
+		fmt.Fprintf(line, "%s AUDIT: id=%q stage=\"completed\" ip=%q method=%q user=%q groups=%q verb=%q namespace=%q resource=%q name=%q uri=%q code=%d latency=%v",
+			time.Now().UTC().Format(time.RFC3339Nano), id, remoteIP(req), req.Method, userName, groups, info.Verb, info.Namespace, info.Resource, info.Name, req.RequestURI, respWriter.code, time.Since(startTime))
+		if reqBody != nil {
+			fmt.Fprintf(line, " requestBody=%q responseBody=%q", reqBody, respWriter.body)
+		}
+		line.WriteString("\n")
+		writeAuditLine(out, id, line)
+	})
+}
+
+// writeAuditLine writes line to out with a single call.
+func writeAuditLine(out io.Writer, id string, line *bytes.Buffer) {
+	if _, err := out.Write(line.Bytes()); err != nil {
+		glog.Errorf("Unable to write audit log for request %s: %v", id, err)
+	}
+}
+
+// auditBuffer keeps the first maxAuditBodyBytes bytes written to it.
+type auditBuffer struct {
+	bytes.Buffer
+	truncated bool
+}
+
+func (b *auditBuffer) Write(p []byte) (int, error) {
+	if room := maxAuditBodyBytes - b.Len(); len(p) > room {
+		b.truncated = true
+		p = p[:room]
+	}
+	b.Buffer.Write(p)
+	return len(p), nil
+}
+
+func (b *auditBuffer) String() string {
+	if b.truncated {
+		return b.Buffer.String() + "...(truncated)"
+	}
+	return b.Buffer.String()
+}
+
+// auditReadCloser records the request body as the handler reads it.
+type auditReadCloser struct {
+	io.ReadCloser
+	body *auditBuffer
+}
+
+func (r *auditReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.body.Write(p[:n])
+	return n, err
+}
+
+// auditResponseWriter records the response code, and the response body if body
+// is set.
+type auditResponseWriter struct {
+	http.ResponseWriter
+	code int
+	body *auditBuffer
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (w *auditResponseWriter) WriteHeader(code int) {
+	if w.code == 0 {
+		w.code = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write implements http.ResponseWriter.
+func (w *auditResponseWriter) Write(p []byte) (int, error) {
+	if w.code == 0 {
+		w.code = http.StatusOK
+	}
+	if w.body != nil {
+		w.body.Write(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush implements http.Flusher.
+func (w *auditResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// CloseNotify implements http.CloseNotifier. The httplog logger that may wrap
+// the underlying writer does not, so it is skipped. If the underlying writer
+// can't notify either, the returned channel never fires.
+func (w *auditResponseWriter) CloseNotify() <-chan bool {
+	if notifier, ok := httplog.Unlogged(w.ResponseWriter).(http.CloseNotifier); ok {
+		return notifier.CloseNotify()
+	}
+	return make(chan bool)
+}
+
+// Hijack implements http.Hijacker.
+func (w *auditResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return hijacker.Hijack()
+	}
+	return nil, nil, fmt.Errorf("the response writer %T does not support hijacking", w.ResponseWriter)
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/latest"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/auth/authenticator"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/auth/handlers"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/auth/user"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+)
+
+// auditedHandler audits an authenticator which authenticates every request as
+// alice, like the master does.
+func auditedHandler(t *testing.T, out *bytes.Buffer, logBodies bool, code int) http.Handler {
+	mapper := api.NewRequestContextMapper()
+	resolver := &APIRequestInfoResolver{APIPrefixes: util.NewStringSet("api"), RestMapper: latest.RESTMapper}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Body != nil {
+			ioutil.ReadAll(req.Body)
+		}
+		w.WriteHeader(code)
+		w.Write([]byte(`{"kind":"Pod"}`))
+	})
+	withUser := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx, _ := mapper.Get(req)
+		mapper.Update(req, api.WithUser(ctx, &user.DefaultInfo{Name: "alice", Groups: []string{"admins", "devs"}}))
+		handler.ServeHTTP(w, req)
+	})
+	filter, err := api.NewRequestContextFilter(mapper, WithAudit(withUser, mapper, resolver, out, logBodies))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return filter
+}
+
+func TestWithAudit(t *testing.T) {
+	out := &bytes.Buffer{}
+	handler := auditedHandler(t, out, false, http.StatusCreated)
+
+	req, _ := http.NewRequest("POST", "/api/v1beta3/namespaces/other/pods", strings.NewReader(`{"kind":"Pod"}`))
+	req.RequestURI = "/api/v1beta3/namespaces/other/pods"
+	req.RemoteAddr = "10.0.0.1:1234"
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	req, _ = http.NewRequest("GET", "/api/v1beta3/namespaces/other/pods/foo", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines, got %q", out.String())
+	}
+	for _, expected := range []string{`AUDIT: id="`, `stage="accepted"`, `ip="10.0.0.1"`, `method="POST"`, `verb="create"`, `namespace="other"`, `resource="pods"`, `name=""`, `uri="/api/v1beta3/namespaces/other/pods"`} {
+		if !strings.Contains(lines[0], expected) {
+			t.Errorf("expected %q in %q", expected, lines[0])
+		}
+	}
+	for _, expected := range []string{`AUDIT: id="`, `stage="completed"`, `ip="10.0.0.1"`, `method="POST"`, `user="alice"`, `groups="admins,devs"`, `verb="create"`, `namespace="other"`, `resource="pods"`, `name=""`, `uri="/api/v1beta3/namespaces/other/pods"`, `code=201`, `latency=`} {
+		if !strings.Contains(lines[1], expected) {
+			t.Errorf("expected %q in %q", expected, lines[1])
+		}
+	}
+	if strings.Contains(lines[1], "requestBody") {
+		t.Errorf("unexpected bodies in %q", lines[1])
+	}
+	for _, expected := range []string{`stage="completed"`, `verb="get"`, `name="foo"`} {
+		if !strings.Contains(lines[3], expected) {
+			t.Errorf("expected %q in %q", expected, lines[3])
+		}
+	}
+	id := strings.Split(lines[0], " ")[2]
+	if !strings.Contains(lines[1], id) {
+		t.Errorf("expected the lines of a request to share ID %q, got %q", id, lines[1])
+	}
+	if strings.Contains(lines[3], id) {
+		t.Errorf("expected a different ID for each request, got %q twice", id)
+	}
+}
+
+func TestWithAuditAcceptedBeforeHandler(t *testing.T) {
+	out := &bytes.Buffer{}
+	mapper := api.NewRequestContextMapper()
+	resolver := &APIRequestInfoResolver{APIPrefixes: util.NewStringSet("api"), RestMapper: latest.RESTMapper}
+	handler := WithAudit(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// A watch is only logged as completed once it ends.
+		if !strings.Contains(out.String(), `stage="accepted"`) || !strings.Contains(out.String(), `verb="watch"`) {
+			t.Errorf("expected the request to be logged as accepted before it is served, got %q", out.String())
+		}
+		if strings.Contains(out.String(), `stage="completed"`) {
+			t.Errorf("unexpected completed line before the request is served: %q", out.String())
+		}
+	}), mapper, resolver, out, false)
+
+	req, _ := http.NewRequest("GET", "/api/v1beta3/watch/namespaces/other/pods", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !strings.Contains(out.String(), `stage="completed"`) {
+		t.Errorf("expected the request to be logged as completed, got %q", out.String())
+	}
+}
+
+func TestWithAuditBodies(t *testing.T) {
+	out := &bytes.Buffer{}
+	handler := auditedHandler(t, out, true, http.StatusOK)
+
+	req, _ := http.NewRequest("PUT", "/api/v1beta3/namespaces/other/pods/foo", strings.NewReader(`{"kind":"Pod","metadata":{"name":"foo"}}`))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	req, _ = http.NewRequest("GET", "/api/v1beta3/namespaces/other/pods/foo", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines, got %q", out.String())
+	}
+	if strings.Contains(lines[0], "requestBody") {
+		t.Errorf("unexpected bodies in the accepted line %q", lines[0])
+	}
+	expected := `requestBody="{\"kind\":\"Pod\",\"metadata\":{\"name\":\"foo\"}}" responseBody="{\"kind\":\"Pod\"}"`
+	if !strings.HasSuffix(lines[1], expected) {
+		t.Errorf("expected %q in %q", expected, lines[1])
+	}
+	if strings.Contains(lines[3], "requestBody") {
+		t.Errorf("unexpected bodies for a read-only request in %q", lines[3])
+	}
+}
+
+func TestWithAuditUnauthenticated(t *testing.T) {
+	out := &bytes.Buffer{}
+	mapper := api.NewRequestContextMapper()
+	resolver := &APIRequestInfoResolver{APIPrefixes: util.NewStringSet("api"), RestMapper: latest.RESTMapper}
+	rejectAll := authenticator.RequestFunc(func(req *http.Request) (user.Info, bool, error) {
+		return nil, false, nil
+	})
+	authenticated, err := handlers.NewRequestAuthenticator(mapper, rejectAll, handlers.Unauthorized, http.NotFoundHandler())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	handler, err := api.NewRequestContextFilter(mapper, WithAudit(authenticated, mapper, resolver, out, false))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	req, _ := http.NewRequest("GET", "/api/v1beta3/namespaces/other/pods/foo", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	for _, expected := range []string{`user="<none>"`, `verb="get"`, `code=401`} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected %q in %q", expected, out.String())
+		}
+	}
+}
+
+func TestWithAuditDefaultCode(t *testing.T) {
+	out := &bytes.Buffer{}
+	mapper := api.NewRequestContextMapper()
+	resolver := &APIRequestInfoResolver{APIPrefixes: util.NewStringSet("api"), RestMapper: latest.RESTMapper}
+	handler := WithAudit(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// The recorder supports neither, which must not panic.
+		if _, _, err := w.(http.Hijacker).Hijack(); err == nil {
+			t.Errorf("expected an error hijacking the connection")
+		}
+		w.(http.CloseNotifier).CloseNotify()
+	}), mapper, resolver, out, false)
+
+	req, _ := http.NewRequest("DELETE", "/api/v1beta3/namespaces/other/pods/foo", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !strings.Contains(out.String(), "code=200") {
+		t.Errorf("expected code 200 when nothing is written, got %q", out.String())
+	}
+}
@@ -58,10 +58,13 @@ const (
 	ModeAlwaysAllow string = "AlwaysAllow"
 	ModeAlwaysDeny  string = "AlwaysDeny"
 	ModeABAC        string = "ABAC"
+	// ModeRBAC authorizes requests using the roles and bindings stored in the API.
+	// The authorizer is built by the master, which owns the registries it reads from.
+	ModeRBAC string = "RBAC"
 )
 
 // Keep this list in sync with constant list above.
-var AuthorizationModeChoices = []string{ModeAlwaysAllow, ModeAlwaysDeny, ModeABAC}
+var AuthorizationModeChoices = []string{ModeAlwaysAllow, ModeAlwaysDeny, ModeABAC, ModeRBAC}
 
 // NewAuthorizerFromAuthorizationConfig returns the right sort of authorizer.Authorizer
 // based on the authorizationMode xor an error.  authorizationMode should be one of AuthorizationModeChoices.
@@ -77,6 +80,8 @@ func NewAuthorizerFromAuthorizationConfig(authorizationMode string, authorizatio
 		return NewAlwaysDenyAuthorizer(), nil
 	case ModeABAC:
 		return abac.NewFromFile(authorizationPolicyFile)
+	case ModeRBAC:
+		return nil, errors.New("RBAC authorizer must be created by the master")
 	default:
 		return nil, errors.New("Unknown authorization mode")
 	}
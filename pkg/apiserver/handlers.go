@@ -18,6 +18,7 @@ package apiserver
 
 import (
 	"fmt"
+	"net"
 	"net/http"
 	"path"
 	"regexp"
@@ -80,6 +81,36 @@ func RateLimit(rl util.RateLimiter, handler http.Handler) http.Handler {
 	})
 }
 
+// MaxInFlightLimit limits the requests served at the same time by handler with
+// limiter. Requests are queued per user, read from the request context, or per
+// client IP for requests without one. Requests that can not be served in time
+// are rejected with a 429 asking the client to retry later.
+func MaxInFlightLimit(limiter *InFlightLimiter, requestContextMapper api.RequestContextMapper, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		userName := remoteIP(req)
+		if ctx, ok := requestContextMapper.Get(req); ok {
+			if user, ok := api.UserFrom(ctx); ok {
+				userName = user.GetName()
+			}
+		}
+		release, ok := limiter.acquire(req, userName)
+		if !ok {
+			tooManyRequests(w, req)
+			return
+		}
+		defer release()
+		handler.ServeHTTP(w, req)
+	})
+}
+
+// remoteIP returns the IP address of the client that sent req.
+func remoteIP(req *http.Request) string {
+	if host, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+		return host
+	}
+	return req.RemoteAddr
+}
+
 // RecoverPanics wraps an http Handler to recover and log panics.
 func RecoverPanics(handler http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
@@ -188,6 +219,12 @@ func (r *requestAttributeGetter) GetAttribs(req *http.Request) authorizer.Attrib
 	// in empty (does not understand defaulting rules.)
 	attribs.Namespace = apiRequestInfo.Namespace
 
+	// The remaining fields are empty for requests that do not address the REST object store.
+	attribs.Verb = apiRequestInfo.Verb
+	attribs.APIVersion = apiRequestInfo.APIVersion
+	attribs.Name = apiRequestInfo.Name
+	attribs.Subresource = apiRequestInfo.Subresource
+
 	return &attribs
 }
 
@@ -215,6 +252,8 @@ type APIRequestInfo struct {
 	Kind string
 	// Name is empty for some verbs, but if the request directly indicates a name (not in body content) then this field is filled in.
 	Name string
+	// Subresource is the part of the resource being requested following the name, for example: status
+	Subresource string
 	// Parts are the path parts for the request, always starting with /{resource}/{name}
 	Parts []string
 	// Raw is the unparsed form of everything other than parts.
@@ -347,6 +386,11 @@ func (r *APIRequestInfoResolver) GetAPIRequestInfo(req *http.Request) (APIReques
 		requestInfo.Name = requestInfo.Parts[1]
 	}
 
+	// a third part names a subresource, except for proxy and redirect where it is part of the target path
+	if len(requestInfo.Parts) >= 3 && requestInfo.Verb != "proxy" && requestInfo.Verb != "redirect" {
+		requestInfo.Subresource = requestInfo.Parts[2]
+	}
+
 	// if there's no name on the request and we thought it was a get before, then the actual verb is a list
 	if len(requestInfo.Name) == 0 && requestInfo.Verb == "get" {
 		requestInfo.Verb = "list"
@@ -164,3 +164,35 @@ func TestGetAPIRequestInfo(t *testing.T) {
 		}
 	}
 }
+
+func TestGetAPIRequestInfoSubresource(t *testing.T) {
+	testCases := []struct {
+		method              string
+		url                 string
+		expectedName        string
+		expectedSubresource string
+	}{
+		{"GET", "/api/v1beta3/namespaces/other/pods/foo", "foo", ""},
+		{"PUT", "/api/v1beta3/namespaces/other/pods/foo/status", "foo", "status"},
+		{"POST", "/api/v1beta3/namespaces/other/pods/foo/binding", "foo", "binding"},
+		{"GET", "/api/v1beta3/proxy/namespaces/other/pods/foo/some/path", "foo", ""},
+		{"GET", "/api/v1beta3/redirect/namespaces/other/pods/foo/bar", "foo", ""},
+	}
+
+	apiRequestInfoResolver := &APIRequestInfoResolver{util.NewStringSet("api"), latest.RESTMapper}
+
+	for _, testCase := range testCases {
+		req, _ := http.NewRequest(testCase.method, testCase.url, nil)
+		apiRequestInfo, err := apiRequestInfoResolver.GetAPIRequestInfo(req)
+		if err != nil {
+			t.Errorf("Unexpected error for url: %s %v", testCase.url, err)
+			continue
+		}
+		if testCase.expectedName != apiRequestInfo.Name {
+			t.Errorf("Unexpected name for url: %s, expected: %s, actual: %s", testCase.url, testCase.expectedName, apiRequestInfo.Name)
+		}
+		if testCase.expectedSubresource != apiRequestInfo.Subresource {
+			t.Errorf("Unexpected subresource for url: %s, expected: %s, actual: %s", testCase.url, testCase.expectedSubresource, apiRequestInfo.Subresource)
+		}
+	}
+}
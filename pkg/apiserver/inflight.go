@@ -0,0 +1,179 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// maxQueuedRequestsPerUser is the most requests of one user that wait for a
+// slot of an InFlightLimiter at the same time. Requests beyond it are rejected
+// right away.
+const maxQueuedRequestsPerUser = 50
+
+// InFlightLimiter limits the number of requests served at the same time,
+// separately for read-only and mutating requests. See MaxInFlightLimit.
+type InFlightLimiter struct {
+	readOnly             *fairQueue
+	mutating             *fairQueue
+	queueTimeout         time.Duration
+	longRunningRequestRE *regexp.Regexp
+}
+
+// NewInFlightLimiter creates an InFlightLimiter that serves at most maxReadOnly
+// read-only and maxMutating mutating requests at the same time; 0 means no
+// limit. Requests wait for a slot up to queueTimeout. Requests whose path
+// matches longRunningRequestRE, if set, are never limited.
+func NewInFlightLimiter(maxReadOnly, maxMutating int, queueTimeout time.Duration, longRunningRequestRE *regexp.Regexp) *InFlightLimiter {
+	return &InFlightLimiter{
+		readOnly:             newFairQueue(maxReadOnly),
+		mutating:             newFairQueue(maxMutating),
+		queueTimeout:         queueTimeout,
+		longRunningRequestRE: longRunningRequestRE,
+	}
+}
+
+// acquire waits for a slot for req on behalf of user, and returns the function
+// that releases it, or false if no slot was given in time.
+func (l *InFlightLimiter) acquire(req *http.Request, user string) (func(), bool) {
+	if l.longRunningRequestRE != nil && l.longRunningRequestRE.MatchString(req.URL.Path) {
+		return func() {}, true
+	}
+	queue := l.mutating
+	if IsReadOnlyReq(*req) {
+		queue = l.readOnly
+	}
+	if !queue.acquire(user, l.queueTimeout) {
+		return nil, false
+	}
+	return queue.release, true
+}
+
+// fairQueue hands out a limited number of slots. When none is free, requests
+// wait in one queue per user, and the slots that free up are given to the
+// users with waiting requests in turn, so that a user sending many requests
+// does not delay the requests of the others.
+type fairQueue struct {
+	limit int
+
+	lock     sync.Mutex
+	inFlight int
+	// waiting holds the waiting requests of each user, in arrival order.
+	waiting map[string][]chan struct{}
+	// users lists the users with waiting requests, the next one to be given a
+	// slot first.
+	users []string
+}
+
+func newFairQueue(limit int) *fairQueue {
+	return &fairQueue{
+		limit:   limit,
+		waiting: map[string][]chan struct{}{},
+	}
+}
+
+// acquire waits up to timeout for a slot for user, and returns true if one was
+// given. A slot must be released once it is no longer used.
+func (q *fairQueue) acquire(user string, timeout time.Duration) bool {
+	if q.limit <= 0 {
+		return true
+	}
+	q.lock.Lock()
+	if q.inFlight < q.limit && len(q.users) == 0 {
+		q.inFlight++
+		q.lock.Unlock()
+		return true
+	}
+	if timeout <= 0 || len(q.waiting[user]) >= maxQueuedRequestsPerUser {
+		q.lock.Unlock()
+		return false
+	}
+	ready := make(chan struct{})
+	if len(q.waiting[user]) == 0 {
+		q.users = append(q.users, user)
+	}
+	q.waiting[user] = append(q.waiting[user], ready)
+	q.lock.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-ready:
+		return true
+	case <-timer.C:
+	}
+
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	select {
+	case <-ready:
+		// The slot was given while the timer fired.
+		return true
+	default:
+	}
+	q.remove(user, ready)
+	return false
+}
+
+// remove drops a waiting request of user that timed out.
+func (q *fairQueue) remove(user string, ready chan struct{}) {
+	waiting := q.waiting[user]
+	for i := range waiting {
+		if waiting[i] == ready {
+			waiting = append(waiting[:i], waiting[i+1:]...)
+			break
+		}
+	}
+	if len(waiting) > 0 {
+		q.waiting[user] = waiting
+		return
+	}
+	delete(q.waiting, user)
+	for i := range q.users {
+		if q.users[i] == user {
+			q.users = append(q.users[:i], q.users[i+1:]...)
+			break
+		}
+	}
+}
+
+// release frees a slot, handing it to the oldest waiting request of the next
+// user in turn if there is one.
+func (q *fairQueue) release() {
+	if q.limit <= 0 {
+		return
+	}
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if len(q.users) == 0 {
+		q.inFlight--
+		return
+	}
+	user := q.users[0]
+	q.users = q.users[1:]
+	waiting := q.waiting[user]
+	close(waiting[0])
+	if len(waiting) > 1 {
+		q.waiting[user] = waiting[1:]
+		q.users = append(q.users, user)
+	} else {
+		delete(q.waiting, user)
+	}
+}
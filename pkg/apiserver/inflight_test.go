@@ -0,0 +1,141 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+)
+
+// waitForQueued waits until user has n requests waiting in q.
+func waitForQueued(t *testing.T, q *fairQueue, user string, n int) {
+	for i := 0; i < 1000; i++ {
+		q.lock.Lock()
+		queued := len(q.waiting[user])
+		q.lock.Unlock()
+		if queued == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("expected %d requests of %s to be queued", n, user)
+}
+
+func TestFairQueueTakesTurns(t *testing.T) {
+	q := newFairQueue(1)
+	if !q.acquire("a", 0) {
+		t.Fatalf("expected a free slot")
+	}
+	if q.acquire("b", 0) {
+		t.Fatalf("unexpected slot over the limit")
+	}
+
+	granted := make(chan string, 3)
+	enqueue := func(name, user string) {
+		go func() {
+			if q.acquire(user, time.Minute) {
+				granted <- name
+			}
+		}()
+	}
+	enqueue("a1", "a")
+	waitForQueued(t, q, "a", 1)
+	enqueue("a2", "a")
+	waitForQueued(t, q, "a", 2)
+	enqueue("b1", "b")
+	waitForQueued(t, q, "b", 1)
+
+	for _, expected := range []string{"a1", "b1", "a2"} {
+		q.release()
+		if actual := <-granted; actual != expected {
+			t.Errorf("expected %s to get the slot, got %s", expected, actual)
+		}
+	}
+	q.release()
+	if q.inFlight != 0 || len(q.users) != 0 || len(q.waiting) != 0 {
+		t.Errorf("expected an empty queue, got %#v", q)
+	}
+}
+
+func TestFairQueueTimeout(t *testing.T) {
+	q := newFairQueue(1)
+	q.acquire("a", 0)
+	if q.acquire("b", 10*time.Millisecond) {
+		t.Errorf("unexpected slot over the limit")
+	}
+	if len(q.users) != 0 || len(q.waiting) != 0 {
+		t.Errorf("expected the request that timed out to leave the queue, got %#v", q)
+	}
+	q.release()
+	if !q.acquire("b", 0) {
+		t.Errorf("expected a free slot")
+	}
+}
+
+func TestFairQueueUnlimited(t *testing.T) {
+	q := newFairQueue(0)
+	for i := 0; i < 10; i++ {
+		if !q.acquire("a", 0) {
+			t.Fatalf("unexpected limit")
+		}
+	}
+}
+
+func TestMaxInFlightLimit(t *testing.T) {
+	block := make(chan struct{})
+	var served sync.WaitGroup
+	limiter := NewInFlightLimiter(1, 1, 0, regexp.MustCompile("/watch/"))
+	handler := MaxInFlightLimit(limiter, api.NewRequestContextMapper(), http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		served.Done()
+		<-block
+	}))
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Fill the read-only and the mutating slots.
+	served.Add(2)
+	go http.Get(server.URL + "/api/v1beta3/pods")
+	go http.Post(server.URL+"/api/v1beta3/namespaces/default/pods", "application/json", nil)
+	served.Wait()
+
+	for _, method := range []string{"GET", "POST"} {
+		req, _ := http.NewRequest(method, server.URL+"/api/v1beta3/namespaces/default/pods", nil)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.StatusCode != errors.StatusTooManyRequests {
+			t.Errorf("%s: expected status %d, got %d", method, errors.StatusTooManyRequests, resp.StatusCode)
+		}
+		if resp.Header.Get("Retry-After") != "1" {
+			t.Errorf("%s: expected a Retry-After header, got %#v", method, resp.Header)
+		}
+	}
+
+	// Long running requests are not limited.
+	served.Add(1)
+	go http.Get(server.URL + "/api/v1beta3/watch/pods")
+	served.Wait()
+	close(block)
+}
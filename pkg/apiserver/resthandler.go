@@ -21,6 +21,7 @@ import (
 	"net/http"
 	"net/url"
 	gpath "path"
+	"strings"
 	"time"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/admission"
@@ -30,6 +31,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/strategicpatch"
 
 	"github.com/emicklei/go-restful"
 	"github.com/evanphx/json-patch"
@@ -64,9 +66,12 @@ type RequestScope struct {
 	Namer ScopeNamer
 	ContextFunc
 	runtime.Codec
-	Resource   string
-	Kind       string
-	APIVersion string
+	// ProtobufCodec, if set, encodes results for clients that accept protobuf.
+	ProtobufCodec runtime.Codec
+	Creater       runtime.ObjectCreater
+	Resource      string
+	Kind          string
+	APIVersion    string
 }
 
 // GetResource returns a function that handles retrieving a single resource from a rest.Storage object.
@@ -90,7 +95,7 @@ func GetResource(r rest.Getter, scope RequestScope) restful.RouteFunction {
 			errorJSON(err, scope.Codec, w)
 			return
 		}
-		write(http.StatusOK, scope.APIVersion, scope.Codec, result, w, req.Request)
+		write(http.StatusOK, scope.APIVersion, scope.Codec, scope.ProtobufCodec, result, w, req.Request)
 	}
 }
 
@@ -130,6 +135,11 @@ func ListResource(r rest.Lister, scope RequestScope) restful.RouteFunction {
 			errorJSON(err, scope.Codec, w)
 			return
 		}
+		// a list at a resource version may be served from a cache that is at
+		// least that recent
+		if resourceVersion := req.Request.URL.Query().Get("resourceVersion"); len(resourceVersion) > 0 {
+			ctx = api.WithResourceVersion(ctx, resourceVersion)
+		}
 
 		result, err := r.List(ctx, label, field)
 		if err != nil {
@@ -140,7 +150,7 @@ func ListResource(r rest.Lister, scope RequestScope) restful.RouteFunction {
 			errorJSON(err, scope.Codec, w)
 			return
 		}
-		write(http.StatusOK, scope.APIVersion, scope.Codec, result, w, req.Request)
+		write(http.StatusOK, scope.APIVersion, scope.Codec, scope.ProtobufCodec, result, w, req.Request)
 	}
 }
 
@@ -196,11 +206,13 @@ func CreateResource(r rest.Creater, scope RequestScope, typer runtime.ObjectType
 			return
 		}
 
-		write(http.StatusCreated, scope.APIVersion, scope.Codec, result, w, req.Request)
+		write(http.StatusCreated, scope.APIVersion, scope.Codec, scope.ProtobufCodec, result, w, req.Request)
 	}
 }
 
-// PatchResource returns a function that will handle a resource patch
+// PatchResource returns a function that will handle a resource patch. The
+// Content-Type of the request selects how the patch is applied, see
+// api.PatchType; any other content type is applied as a merge patch.
 // TODO: Eventually PatchResource should just use AtomicUpdate and this routine should be a bit cleaner
 func PatchResource(r rest.Patcher, scope RequestScope, typer runtime.ObjectTyper, admit admission.Interface) restful.RouteFunction {
 	return func(req *restful.Request, res *restful.Response) {
@@ -242,7 +254,7 @@ func PatchResource(r rest.Patcher, scope RequestScope, typer runtime.ObjectTyper
 			errorJSON(err, scope.Codec, w)
 			return
 		}
-		patchedObjJs, err := jsonpatch.MergePatch(originalObjJs, patchJs)
+		patchedObjJs, err := getPatchedJS(patchType(req), originalObjJs, patchJs, scope)
 		if err != nil {
 			errorJSON(err, scope.Codec, w)
 			return
@@ -272,7 +284,40 @@ func PatchResource(r rest.Patcher, scope RequestScope, typer runtime.ObjectTyper
 			return
 		}
 
-		write(http.StatusOK, scope.APIVersion, scope.Codec, result, w, req.Request)
+		write(http.StatusOK, scope.APIVersion, scope.Codec, scope.ProtobufCodec, result, w, req.Request)
+	}
+}
+
+// patchType returns the patch type named by the Content-Type of the request,
+// without its parameters.
+func patchType(req *restful.Request) api.PatchType {
+	contentType := req.HeaderParameter("Content-Type")
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+	return api.PatchType(strings.TrimSpace(contentType))
+}
+
+// getPatchedJS applies the patch to the original object, both encoded with
+// scope.Codec.
+func getPatchedJS(patchType api.PatchType, originalJS, patchJS []byte, scope RequestScope) ([]byte, error) {
+	switch patchType {
+	case api.JSONPatchType:
+		patch, err := jsonpatch.DecodePatch(patchJS)
+		if err != nil {
+			return nil, err
+		}
+		return patch.Apply(originalJS)
+	case api.StrategicMergePatchType:
+		// The patch strategies are read from the tags of the versioned type
+		// the object is encoded as.
+		versionedObj, err := scope.Creater.New(scope.APIVersion, scope.Kind)
+		if err != nil {
+			return nil, err
+		}
+		return strategicpatch.StrategicMergePatchData(originalJS, patchJS, versionedObj)
+	default:
+		return jsonpatch.MergePatch(originalJS, patchJS)
 	}
 }
 
@@ -336,7 +381,7 @@ func UpdateResource(r rest.Updater, scope RequestScope, typer runtime.ObjectType
 		if wasCreated {
 			status = http.StatusCreated
 		}
-		writeJSON(status, scope.Codec, result, w)
+		write(status, scope.APIVersion, scope.Codec, scope.ProtobufCodec, result, w, req.Request)
 	}
 }
 
@@ -405,7 +450,7 @@ func DeleteResource(r rest.GracefulDeleter, checkBody bool, scope RequestScope,
 				}
 			}
 		}
-		write(http.StatusOK, scope.APIVersion, scope.Codec, result, w, req.Request)
+		write(http.StatusOK, scope.APIVersion, scope.Codec, scope.ProtobufCodec, result, w, req.Request)
 	}
 }
 
@@ -41,6 +41,19 @@ type Attributes interface {
 
 	// The kind of object, if a request is for a REST object.
 	GetResource() string
+
+	// The verb of the request, e.g. "get", "list", "create", if a request is
+	// for a REST object.
+	GetVerb() string
+
+	// The API version of the request, if a request is for a REST object.
+	GetAPIVersion() string
+
+	// The name of the object, if a request is for a single REST object.
+	GetName() string
+
+	// The subresource being requested, e.g. "status" or "binding", if any.
+	GetSubresource() string
 }
 
 // Authorizer makes an authorization decision based on information gained by making
@@ -52,10 +65,14 @@ type Authorizer interface {
 
 // AttributesRecord implements Attributes interface.
 type AttributesRecord struct {
-	User      user.Info
-	ReadOnly  bool
-	Namespace string
-	Resource  string
+	User        user.Info
+	ReadOnly    bool
+	Namespace   string
+	Resource    string
+	Verb        string
+	APIVersion  string
+	Name        string
+	Subresource string
 }
 
 func (a AttributesRecord) GetUserName() string {
@@ -77,3 +94,19 @@ func (a AttributesRecord) GetNamespace() string {
 func (a AttributesRecord) GetResource() string {
 	return a.Resource
 }
+
+func (a AttributesRecord) GetVerb() string {
+	return a.Verb
+}
+
+func (a AttributesRecord) GetAPIVersion() string {
+	return a.APIVersion
+}
+
+func (a AttributesRecord) GetName() string {
+	return a.Name
+}
+
+func (a AttributesRecord) GetSubresource() string {
+	return a.Subresource
+}
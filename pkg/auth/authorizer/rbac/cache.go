@@ -0,0 +1,112 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kubeerr "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+)
+
+// Cache serves the roles and bindings read by an RBACAuthorizer from memory, so that
+// authorizing a request does not read from storage. It is kept up to date by
+// reflectors, and implements RoleGetter, RoleBindingLister, ClusterRoleGetter and
+// ClusterRoleBindingLister.
+type Cache struct {
+	roles               cache.Indexer
+	roleBindings        cache.Indexer
+	clusterRoles        cache.Indexer
+	clusterRoleBindings cache.Indexer
+
+	reflectors []*cache.Reflector
+}
+
+// NewCache returns a Cache filled from the given ListerWatchers, which must list and
+// watch the objects of every namespace. The cache is empty until Run is called.
+func NewCache(roles, roleBindings, clusterRoles, clusterRoleBindings cache.ListerWatcher) *Cache {
+	c := &Cache{}
+	var r *cache.Reflector
+	c.roles, r = cache.NewNamespaceKeyedIndexerAndReflector(roles, &api.Role{}, 0)
+	c.reflectors = append(c.reflectors, r)
+	c.roleBindings, r = cache.NewNamespaceKeyedIndexerAndReflector(roleBindings, &api.RoleBinding{}, 0)
+	c.reflectors = append(c.reflectors, r)
+	c.clusterRoles, r = cache.NewNamespaceKeyedIndexerAndReflector(clusterRoles, &api.ClusterRole{}, 0)
+	c.reflectors = append(c.reflectors, r)
+	c.clusterRoleBindings, r = cache.NewNamespaceKeyedIndexerAndReflector(clusterRoleBindings, &api.ClusterRoleBinding{}, 0)
+	c.reflectors = append(c.reflectors, r)
+	return c
+}
+
+// Run starts the reflectors filling the cache, and returns immediately.
+func (c *Cache) Run() {
+	for _, r := range c.reflectors {
+		r.Run()
+	}
+}
+
+// GetRole implements RoleGetter.
+func (c *Cache) GetRole(ctx api.Context, name string) (*api.Role, error) {
+	obj, exists, err := c.roles.Get(&api.Role{ObjectMeta: api.ObjectMeta{Namespace: api.NamespaceValue(ctx), Name: name}})
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, kubeerr.NewNotFound("roles", name)
+	}
+	return obj.(*api.Role), nil
+}
+
+// ListRoleBindings implements RoleBindingLister.
+func (c *Cache) ListRoleBindings(ctx api.Context, selector labels.Selector) (*api.RoleBindingList, error) {
+	items, err := c.roleBindings.Index("namespace", &api.RoleBinding{ObjectMeta: api.ObjectMeta{Namespace: api.NamespaceValue(ctx)}})
+	if err != nil {
+		return nil, err
+	}
+	list := &api.RoleBindingList{}
+	for _, item := range items {
+		binding := item.(*api.RoleBinding)
+		if selector.Matches(labels.Set(binding.Labels)) {
+			list.Items = append(list.Items, *binding)
+		}
+	}
+	return list, nil
+}
+
+// GetClusterRole implements ClusterRoleGetter.
+func (c *Cache) GetClusterRole(ctx api.Context, name string) (*api.ClusterRole, error) {
+	obj, exists, err := c.clusterRoles.Get(&api.ClusterRole{ObjectMeta: api.ObjectMeta{Name: name}})
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, kubeerr.NewNotFound("clusterroles", name)
+	}
+	return obj.(*api.ClusterRole), nil
+}
+
+// ListClusterRoleBindings implements ClusterRoleBindingLister.
+func (c *Cache) ListClusterRoleBindings(ctx api.Context, selector labels.Selector) (*api.ClusterRoleBindingList, error) {
+	list := &api.ClusterRoleBindingList{}
+	for _, item := range c.clusterRoleBindings.List() {
+		binding := item.(*api.ClusterRoleBinding)
+		if selector.Matches(labels.Set(binding.Labels)) {
+			list.Items = append(list.Items, *binding)
+		}
+	}
+	return list, nil
+}
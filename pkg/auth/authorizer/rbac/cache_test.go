@@ -0,0 +1,87 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kubeerr "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/wait"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+)
+
+func fakeListWatch(list runtime.Object, w watch.Interface) *cache.ListWatch {
+	return &cache.ListWatch{
+		ListFunc:  func() (runtime.Object, error) { return list, nil },
+		WatchFunc: func(resourceVersion string) (watch.Interface, error) { return w, nil },
+	}
+}
+
+func TestCache(t *testing.T) {
+	registry := newTestRegistry()
+	roleBindingWatch := watch.NewFake()
+	c := NewCache(
+		fakeListWatch(&api.RoleList{Items: registry.roles}, watch.NewFake()),
+		fakeListWatch(&api.RoleBindingList{Items: registry.roleBindings}, roleBindingWatch),
+		fakeListWatch(&api.ClusterRoleList{Items: registry.clusterRoles}, watch.NewFake()),
+		fakeListWatch(&api.ClusterRoleBindingList{Items: registry.clusterRoleBindings}, watch.NewFake()),
+	)
+	c.Run()
+	err := wait.Poll(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		return len(c.roles.List()) == len(registry.roles) &&
+			len(c.roleBindings.List()) == len(registry.roleBindings) &&
+			len(c.clusterRoles.List()) == len(registry.clusterRoles) &&
+			len(c.clusterRoleBindings.List()) == len(registry.clusterRoleBindings), nil
+	})
+	if err != nil {
+		t.Fatalf("the cache was not filled: %v", err)
+	}
+
+	role, err := c.GetRole(api.WithNamespace(api.NewContext(), "ns1"), "pod-reader")
+	if err != nil || role.Name != "pod-reader" {
+		t.Errorf("unexpected role %v: %v", role, err)
+	}
+	if _, err := c.GetRole(api.WithNamespace(api.NewContext(), "ns2"), "pod-reader"); !kubeerr.IsNotFound(err) {
+		t.Errorf("expected a not found error for a role of another namespace, got %v", err)
+	}
+	if _, err := c.GetClusterRole(api.NewContext(), "missing"); !kubeerr.IsNotFound(err) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+	clusterBindings, err := c.ListClusterRoleBindings(api.NewContext(), labels.Everything())
+	if err != nil || len(clusterBindings.Items) != 1 {
+		t.Errorf("unexpected cluster role bindings %v: %v", clusterBindings, err)
+	}
+
+	roleBindingWatch.Add(&api.RoleBinding{
+		ObjectMeta: api.ObjectMeta{Name: "new", Namespace: "ns2"},
+		Subjects:   []api.Subject{{Kind: api.UserKind, Name: "alice"}},
+		RoleRef:    api.RoleRef{Kind: "ClusterRole", Name: "editor"},
+	})
+	ctx := api.WithNamespace(api.NewContext(), "ns2")
+	err = wait.Poll(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		bindings, err := c.ListRoleBindings(ctx, labels.Everything())
+		return err == nil && len(bindings.Items) == 3, err
+	})
+	if err != nil {
+		t.Errorf("the watched role binding was not added to the namespace: %v", err)
+	}
+}
@@ -0,0 +1,305 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rbac implements an authorizer.Authorizer that grants access to the
+// API according to the Roles, ClusterRoles and their bindings stored in the API.
+package rbac
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kubeerr "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/auth/authorizer"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/auth/user"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+
+	"github.com/golang/glog"
+)
+
+// RoleGetter gets the Roles of a namespace.
+type RoleGetter interface {
+	GetRole(ctx api.Context, name string) (*api.Role, error)
+}
+
+// RoleBindingLister lists the RoleBindings of a namespace.
+type RoleBindingLister interface {
+	ListRoleBindings(ctx api.Context, selector labels.Selector) (*api.RoleBindingList, error)
+}
+
+// ClusterRoleGetter gets ClusterRoles.
+type ClusterRoleGetter interface {
+	GetClusterRole(ctx api.Context, name string) (*api.ClusterRole, error)
+}
+
+// ClusterRoleBindingLister lists ClusterRoleBindings.
+type ClusterRoleBindingLister interface {
+	ListClusterRoleBindings(ctx api.Context, selector labels.Selector) (*api.ClusterRoleBindingList, error)
+}
+
+// EscalationChecker confirms that the user of a request may bind a role, or write
+// the rules of a role.
+type EscalationChecker interface {
+	ConfirmNoEscalation(ctx api.Context, namespace string, ref api.RoleRef) error
+	ConfirmNoRuleEscalation(ctx api.Context, namespace, kind, name string, rules []api.PolicyRule) error
+}
+
+// RBACAuthorizer authorizes a request when one of the roles bound to the user,
+// or to one of its groups, has a rule matching the request.
+type RBACAuthorizer struct {
+	superUser string
+
+	roles               RoleGetter
+	roleBindings        RoleBindingLister
+	clusterRoles        ClusterRoleGetter
+	clusterRoleBindings ClusterRoleBindingLister
+}
+
+// New returns an RBACAuthorizer reading roles and bindings from the given sources.
+// Requests made by superUser, if not empty, are always authorized.
+func New(roles RoleGetter, roleBindings RoleBindingLister, clusterRoles ClusterRoleGetter, clusterRoleBindings ClusterRoleBindingLister, superUser string) *RBACAuthorizer {
+	return &RBACAuthorizer{
+		superUser:           superUser,
+		roles:               roles,
+		roleBindings:        roleBindings,
+		clusterRoles:        clusterRoles,
+		clusterRoleBindings: clusterRoleBindings,
+	}
+}
+
+// Authorize implements authorizer.Authorizer.
+func (r *RBACAuthorizer) Authorize(a authorizer.Attributes) error {
+	if len(r.superUser) > 0 && a.GetUserName() == r.superUser {
+		return nil
+	}
+
+	clusterBindings, err := r.clusterRoleBindings.ListClusterRoleBindings(api.NewContext(), labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, binding := range clusterBindings.Items {
+		if !appliesTo(binding.Subjects, a.GetUserName(), a.GetGroups()) {
+			continue
+		}
+		if anyRuleMatches(r.rulesForRef(api.NewContext(), binding.RoleRef), a) {
+			return nil
+		}
+	}
+
+	// Cluster-scoped requests are only granted by cluster role bindings.
+	if len(a.GetNamespace()) == 0 {
+		return errors.New("No role binding matched.")
+	}
+
+	ctx := api.WithNamespace(api.NewContext(), a.GetNamespace())
+	bindings, err := r.roleBindings.ListRoleBindings(ctx, labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, binding := range bindings.Items {
+		if !appliesTo(binding.Subjects, a.GetUserName(), a.GetGroups()) {
+			continue
+		}
+		if anyRuleMatches(r.rulesForRef(ctx, binding.RoleRef), a) {
+			return nil
+		}
+	}
+	return errors.New("No role binding matched.")
+}
+
+// ConfirmNoEscalation returns a Forbidden error unless the user of the request already
+// holds every right granted by the referenced role, so that users cannot grant more
+// rights than they have. Rights are resolved in the given namespace, or cluster-wide
+// if it is empty. Requests without a user, which did not go through authorization,
+// are not checked.
+func (r *RBACAuthorizer) ConfirmNoEscalation(ctx api.Context, namespace string, ref api.RoleRef) error {
+	refCtx := api.WithNamespace(api.NewContext(), namespace)
+	return r.ConfirmNoRuleEscalation(ctx, namespace, ref.Kind, ref.Name, r.rulesForRef(refCtx, ref))
+}
+
+// ConfirmNoRuleEscalation returns a Forbidden error for the named object of the given
+// kind unless the user of the request already holds every right granted by rules, so
+// that users who may edit a role bound to them cannot add rights to it. Rights are
+// resolved as in ConfirmNoEscalation.
+func (r *RBACAuthorizer) ConfirmNoRuleEscalation(ctx api.Context, namespace, kind, name string, rules []api.PolicyRule) error {
+	u, ok := api.UserFrom(ctx)
+	if !ok || (len(r.superUser) > 0 && u.GetName() == r.superUser) {
+		return nil
+	}
+	held, err := r.RulesFor(u, namespace)
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		if !covers(held, rule) {
+			return kubeerr.NewForbidden(kind, name, fmt.Errorf("user %q cannot grant rights it does not hold: %v", u.GetName(), rule))
+		}
+	}
+	return nil
+}
+
+// RulesFor returns the rules granted to the user by the cluster role bindings and, if
+// namespace is not empty, by the role bindings of the namespace.
+func (r *RBACAuthorizer) RulesFor(u user.Info, namespace string) ([]api.PolicyRule, error) {
+	rules := []api.PolicyRule{}
+	clusterBindings, err := r.clusterRoleBindings.ListClusterRoleBindings(api.NewContext(), labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, binding := range clusterBindings.Items {
+		if appliesTo(binding.Subjects, u.GetName(), u.GetGroups()) {
+			rules = append(rules, r.rulesForRef(api.NewContext(), binding.RoleRef)...)
+		}
+	}
+	if len(namespace) == 0 {
+		return rules, nil
+	}
+
+	ctx := api.WithNamespace(api.NewContext(), namespace)
+	bindings, err := r.roleBindings.ListRoleBindings(ctx, labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, binding := range bindings.Items {
+		if appliesTo(binding.Subjects, u.GetName(), u.GetGroups()) {
+			rules = append(rules, r.rulesForRef(ctx, binding.RoleRef)...)
+		}
+	}
+	return rules, nil
+}
+
+// rulesForRef resolves the role referenced by a binding and returns its rules. A role
+// that cannot be found grants nothing.
+func (r *RBACAuthorizer) rulesForRef(ctx api.Context, ref api.RoleRef) []api.PolicyRule {
+	switch ref.Kind {
+	case "Role":
+		role, err := r.roles.GetRole(ctx, ref.Name)
+		if err != nil {
+			glog.V(4).Infof("Unable to get role %s/%s: %v", api.NamespaceValue(ctx), ref.Name, err)
+			return nil
+		}
+		return role.Rules
+	case "ClusterRole":
+		role, err := r.clusterRoles.GetClusterRole(api.NewContext(), ref.Name)
+		if err != nil {
+			glog.V(4).Infof("Unable to get cluster role %s: %v", ref.Name, err)
+			return nil
+		}
+		return role.Rules
+	}
+	return nil
+}
+
+// appliesTo returns true if the user, or one of its groups, is among the subjects.
+func appliesTo(subjects []api.Subject, userName string, groups []string) bool {
+	for _, subject := range subjects {
+		switch subject.Kind {
+		case api.UserKind:
+			if len(userName) > 0 && subject.Name == userName {
+				return true
+			}
+		case api.GroupKind:
+			for _, group := range groups {
+				if subject.Name == group {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func anyRuleMatches(rules []api.PolicyRule, a authorizer.Attributes) bool {
+	for _, rule := range rules {
+		if ruleMatches(rule, a) {
+			return true
+		}
+	}
+	return false
+}
+
+// covers returns true if the rules grant every verb on every resource, and resource
+// name, that the given rule grants.
+func covers(rules []api.PolicyRule, rule api.PolicyRule) bool {
+	names := rule.ResourceNames
+	if len(names) == 0 {
+		// The rule grants access to every name, which only a rule without names covers.
+		names = []string{""}
+	}
+	for _, verb := range rule.Verbs {
+		for _, resource := range rule.Resources {
+			for _, name := range names {
+				if !anyRuleGrants(rules, verb, resource, name) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// anyRuleGrants returns true if one of the rules grants the verb on the resource and
+// name. An empty name stands for every name.
+func anyRuleGrants(rules []api.PolicyRule, verb, resource, name string) bool {
+	for _, rule := range rules {
+		if !contains(rule.Verbs, verb) || !contains(lower(rule.Resources), strings.ToLower(resource)) {
+			continue
+		}
+		if len(rule.ResourceNames) == 0 || (len(name) > 0 && contains(rule.ResourceNames, name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleMatches returns true if the rule covers the verb, the resource (or subresource)
+// and the name of the request. Requests which do not address a REST object only
+// match rules granting every verb on every resource.
+func ruleMatches(rule api.PolicyRule, a authorizer.Attributes) bool {
+	if !contains(rule.Verbs, a.GetVerb()) {
+		return false
+	}
+	resource := a.GetResource()
+	if len(a.GetSubresource()) > 0 {
+		resource = resource + "/" + a.GetSubresource()
+	}
+	// Resource names are camel-cased in some API versions and lower-cased in others.
+	if !contains(lower(rule.Resources), strings.ToLower(resource)) {
+		return false
+	}
+	return len(rule.ResourceNames) == 0 || contains(rule.ResourceNames, a.GetName())
+}
+
+// contains returns true if value is not empty and is listed in values, or if values
+// holds the "*" wildcard.
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == "*" || (len(value) > 0 && v == value) {
+			return true
+		}
+	}
+	return false
+}
+
+func lower(values []string) []string {
+	result := make([]string, len(values))
+	for i, v := range values {
+		result[i] = strings.ToLower(v)
+	}
+	return result
+}
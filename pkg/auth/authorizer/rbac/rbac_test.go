@@ -0,0 +1,261 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kubeerr "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/auth/authorizer"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/auth/user"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+)
+
+type fakeRegistry struct {
+	roles               []api.Role
+	roleBindings        []api.RoleBinding
+	clusterRoles        []api.ClusterRole
+	clusterRoleBindings []api.ClusterRoleBinding
+}
+
+func (f *fakeRegistry) GetRole(ctx api.Context, name string) (*api.Role, error) {
+	for i := range f.roles {
+		if f.roles[i].Namespace == api.NamespaceValue(ctx) && f.roles[i].Name == name {
+			return &f.roles[i], nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (f *fakeRegistry) ListRoleBindings(ctx api.Context, selector labels.Selector) (*api.RoleBindingList, error) {
+	list := &api.RoleBindingList{}
+	for _, binding := range f.roleBindings {
+		if binding.Namespace == api.NamespaceValue(ctx) {
+			list.Items = append(list.Items, binding)
+		}
+	}
+	return list, nil
+}
+
+func (f *fakeRegistry) GetClusterRole(ctx api.Context, name string) (*api.ClusterRole, error) {
+	for i := range f.clusterRoles {
+		if f.clusterRoles[i].Name == name {
+			return &f.clusterRoles[i], nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (f *fakeRegistry) ListClusterRoleBindings(ctx api.Context, selector labels.Selector) (*api.ClusterRoleBindingList, error) {
+	return &api.ClusterRoleBindingList{Items: f.clusterRoleBindings}, nil
+}
+
+func newTestRegistry() *fakeRegistry {
+	return &fakeRegistry{
+		roles: []api.Role{
+			{
+				ObjectMeta: api.ObjectMeta{Name: "pod-reader", Namespace: "ns1"},
+				Rules:      []api.PolicyRule{{Verbs: []string{"get", "list", "watch"}, Resources: []string{"pods"}}},
+			},
+			{
+				ObjectMeta: api.ObjectMeta{Name: "status-updater", Namespace: "ns2"},
+				Rules:      []api.PolicyRule{{Verbs: []string{"update"}, Resources: []string{"pods/status"}, ResourceNames: []string{"foo"}}},
+			},
+		},
+		roleBindings: []api.RoleBinding{
+			{
+				ObjectMeta: api.ObjectMeta{Name: "alice-reads-pods", Namespace: "ns1"},
+				Subjects:   []api.Subject{{Kind: api.UserKind, Name: "alice"}},
+				RoleRef:    api.RoleRef{Kind: "Role", Name: "pod-reader"},
+			},
+			{
+				ObjectMeta: api.ObjectMeta{Name: "bob-updates-status", Namespace: "ns2"},
+				Subjects:   []api.Subject{{Kind: api.UserKind, Name: "bob"}},
+				RoleRef:    api.RoleRef{Kind: "Role", Name: "status-updater"},
+			},
+			{
+				ObjectMeta: api.ObjectMeta{Name: "devs-edit", Namespace: "ns2"},
+				Subjects:   []api.Subject{{Kind: api.GroupKind, Name: "devs"}},
+				RoleRef:    api.RoleRef{Kind: "ClusterRole", Name: "editor"},
+			},
+			{
+				ObjectMeta: api.ObjectMeta{Name: "dangling", Namespace: "ns1"},
+				Subjects:   []api.Subject{{Kind: api.UserKind, Name: "carol"}},
+				RoleRef:    api.RoleRef{Kind: "Role", Name: "missing"},
+			},
+		},
+		clusterRoles: []api.ClusterRole{
+			{
+				ObjectMeta: api.ObjectMeta{Name: "admin"},
+				Rules:      []api.PolicyRule{{Verbs: []string{"*"}, Resources: []string{"*"}}},
+			},
+			{
+				ObjectMeta: api.ObjectMeta{Name: "editor"},
+				Rules:      []api.PolicyRule{{Verbs: []string{"create", "update", "delete"}, Resources: []string{"pods", "services"}}},
+			},
+		},
+		clusterRoleBindings: []api.ClusterRoleBinding{
+			{
+				ObjectMeta: api.ObjectMeta{Name: "admins"},
+				Subjects:   []api.Subject{{Kind: api.GroupKind, Name: "admins"}},
+				RoleRef:    api.RoleRef{Kind: "ClusterRole", Name: "admin"},
+			},
+		},
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	registry := newTestRegistry()
+	a := New(registry, registry, registry, registry, "root")
+
+	alice := &user.DefaultInfo{Name: "alice"}
+	bob := &user.DefaultInfo{Name: "bob"}
+	carol := &user.DefaultInfo{Name: "carol"}
+	dave := &user.DefaultInfo{Name: "dave", Groups: []string{"devs"}}
+	eve := &user.DefaultInfo{Name: "eve", Groups: []string{"admins"}}
+	root := &user.DefaultInfo{Name: "root"}
+
+	testCases := []struct {
+		name      string
+		attr      authorizer.AttributesRecord
+		expectErr bool
+	}{
+		{"super user", authorizer.AttributesRecord{User: root, Verb: "delete", Resource: "nodes", Name: "n1"}, false},
+		{"super user non-resource", authorizer.AttributesRecord{User: root}, false},
+		{"cluster admin by group", authorizer.AttributesRecord{User: eve, Verb: "delete", Namespace: "ns1", Resource: "pods", Name: "foo"}, false},
+		{"cluster admin cluster-scoped", authorizer.AttributesRecord{User: eve, Verb: "create", Resource: "nodes"}, false},
+		{"cluster admin non-resource", authorizer.AttributesRecord{User: eve, ReadOnly: true}, false},
+		{"role allows verb", authorizer.AttributesRecord{User: alice, Verb: "list", Namespace: "ns1", Resource: "pods"}, false},
+		{"role denies verb", authorizer.AttributesRecord{User: alice, Verb: "delete", Namespace: "ns1", Resource: "pods", Name: "foo"}, true},
+		{"role denies resource", authorizer.AttributesRecord{User: alice, Verb: "get", Namespace: "ns1", Resource: "services", Name: "foo"}, true},
+		{"role denies subresource", authorizer.AttributesRecord{User: alice, Verb: "get", Namespace: "ns1", Resource: "pods", Name: "foo", Subresource: "log"}, true},
+		{"role scoped to namespace", authorizer.AttributesRecord{User: alice, Verb: "get", Namespace: "ns2", Resource: "pods", Name: "foo"}, true},
+		{"role not cluster-wide", authorizer.AttributesRecord{User: alice, Verb: "list", Resource: "pods"}, true},
+		{"role non-resource", authorizer.AttributesRecord{User: alice, ReadOnly: true}, true},
+		{"subresource and name allowed", authorizer.AttributesRecord{User: bob, Verb: "update", Namespace: "ns2", Resource: "pods", Name: "foo", Subresource: "status"}, false},
+		{"subresource other name", authorizer.AttributesRecord{User: bob, Verb: "update", Namespace: "ns2", Resource: "pods", Name: "bar", Subresource: "status"}, true},
+		{"subresource not resource", authorizer.AttributesRecord{User: bob, Verb: "update", Namespace: "ns2", Resource: "pods", Name: "foo"}, true},
+		{"cluster role bound in namespace", authorizer.AttributesRecord{User: dave, Verb: "create", Namespace: "ns2", Resource: "services"}, false},
+		{"camel-cased resource", authorizer.AttributesRecord{User: dave, Verb: "create", Namespace: "ns2", Resource: "Services"}, false},
+		{"cluster role bound in other namespace", authorizer.AttributesRecord{User: dave, Verb: "create", Namespace: "ns1", Resource: "services"}, true},
+		{"missing role", authorizer.AttributesRecord{User: carol, Verb: "get", Namespace: "ns1", Resource: "pods", Name: "foo"}, true},
+	}
+	for _, tc := range testCases {
+		err := a.Authorize(tc.attr)
+		if tc.expectErr && err == nil {
+			t.Errorf("%s: expected request to be denied", tc.name)
+		}
+		if !tc.expectErr && err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+		}
+	}
+}
+
+func TestConfirmNoEscalation(t *testing.T) {
+	registry := newTestRegistry()
+	registry.roles = append(registry.roles, api.Role{
+		ObjectMeta: api.ObjectMeta{Name: "pod-status-reader", Namespace: "ns1"},
+		Rules:      []api.PolicyRule{{Verbs: []string{"get"}, Resources: []string{"pods/status"}}},
+	}, api.Role{
+		ObjectMeta: api.ObjectMeta{Name: "foo-reader", Namespace: "ns1"},
+		Rules:      []api.PolicyRule{{Verbs: []string{"get"}, Resources: []string{"pods"}, ResourceNames: []string{"foo"}}},
+	})
+	a := New(registry, registry, registry, registry, "root")
+
+	alice := &user.DefaultInfo{Name: "alice"}
+	dave := &user.DefaultInfo{Name: "dave", Groups: []string{"devs"}}
+	eve := &user.DefaultInfo{Name: "eve", Groups: []string{"admins"}}
+	root := &user.DefaultInfo{Name: "root"}
+
+	testCases := []struct {
+		name      string
+		user      user.Info
+		namespace string
+		ref       api.RoleRef
+		expectErr bool
+	}{
+		{"no user", nil, "ns1", api.RoleRef{Kind: "ClusterRole", Name: "admin"}, false},
+		{"super user", root, "", api.RoleRef{Kind: "ClusterRole", Name: "admin"}, false},
+		{"cluster admin", eve, "", api.RoleRef{Kind: "ClusterRole", Name: "editor"}, false},
+		{"own role", alice, "ns1", api.RoleRef{Kind: "Role", Name: "pod-reader"}, false},
+		{"role with a resource name", alice, "ns1", api.RoleRef{Kind: "Role", Name: "foo-reader"}, false},
+		{"more verbs", alice, "ns1", api.RoleRef{Kind: "ClusterRole", Name: "editor"}, true},
+		{"other resource", alice, "ns1", api.RoleRef{Kind: "Role", Name: "pod-status-reader"}, true},
+		{"other namespace", alice, "ns2", api.RoleRef{Kind: "ClusterRole", Name: "editor"}, true},
+		{"cluster role bound in namespace", dave, "ns2", api.RoleRef{Kind: "ClusterRole", Name: "editor"}, false},
+		{"cluster role bound cluster-wide", dave, "", api.RoleRef{Kind: "ClusterRole", Name: "editor"}, true},
+		{"wildcards", dave, "ns2", api.RoleRef{Kind: "ClusterRole", Name: "admin"}, true},
+		{"missing role", alice, "ns1", api.RoleRef{Kind: "Role", Name: "missing"}, false},
+	}
+	for _, tc := range testCases {
+		ctx := api.NewContext()
+		if tc.user != nil {
+			ctx = api.WithUser(ctx, tc.user)
+		}
+		err := a.ConfirmNoEscalation(ctx, tc.namespace, tc.ref)
+		if tc.expectErr && !kubeerr.IsForbidden(err) {
+			t.Errorf("%s: expected a forbidden error, got %v", tc.name, err)
+		}
+		if !tc.expectErr && err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+		}
+	}
+}
+
+func TestConfirmNoRuleEscalation(t *testing.T) {
+	a := New(newTestRegistry(), newTestRegistry(), newTestRegistry(), newTestRegistry(), "root")
+
+	alice := &user.DefaultInfo{Name: "alice"}
+	eve := &user.DefaultInfo{Name: "eve", Groups: []string{"admins"}}
+	root := &user.DefaultInfo{Name: "root"}
+
+	podReader := []api.PolicyRule{{Verbs: []string{"get", "list", "watch"}, Resources: []string{"pods"}}}
+	// alice may edit pod-reader, which is bound to her, but not add rights to it.
+	podWriter := append(podReader, api.PolicyRule{Verbs: []string{"create", "delete"}, Resources: []string{"pods"}})
+
+	testCases := []struct {
+		name      string
+		user      user.Info
+		namespace string
+		rules     []api.PolicyRule
+		expectErr bool
+	}{
+		{"no user", nil, "ns1", podWriter, false},
+		{"super user", root, "", podWriter, false},
+		{"cluster admin", eve, "", podWriter, false},
+		{"held rules", alice, "ns1", podReader, false},
+		{"fewer rules", alice, "ns1", podReader[:0], false},
+		{"added rule", alice, "ns1", podWriter, true},
+		{"other namespace", alice, "ns2", podReader, true},
+		{"cluster-wide", alice, "", podReader, true},
+	}
+	for _, tc := range testCases {
+		ctx := api.NewContext()
+		if tc.user != nil {
+			ctx = api.WithUser(ctx, tc.user)
+		}
+		err := a.ConfirmNoRuleEscalation(ctx, tc.namespace, "Role", "pod-reader", tc.rules)
+		if tc.expectErr && !kubeerr.IsForbidden(err) {
+			t.Errorf("%s: expected a forbidden error, got %v", tc.name, err)
+		}
+		if !tc.expectErr && err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+		}
+	}
+}
@@ -21,4 +21,9 @@ limitations under the License.
 // list currently available minions), and one that additionally acts as
 // a FIFO queue (for example, to allow a scheduler to process incoming
 // pods).
+//
+// Controllers are built from an Informer, which keeps an indexed Store in
+// sync with the server and notifies ResourceEventHandlers of every change,
+// and a RateLimitingQueue of object keys that workers process and retry
+// with backoff.
 package cache
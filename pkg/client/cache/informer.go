@@ -0,0 +1,288 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+)
+
+// ResourceEventHandler can handle notifications for events that happen to a
+// resource held in an Informer's store. Handlers are called synchronously from
+// the informer's watch goroutine, so they should not block; the usual pattern
+// is to compute a key and add it to a RateLimitingQueue.
+//   - OnAdd is called when an object is added.
+//   - OnUpdate is called when an object is modified, and on every resync. Note
+//     that oldObj is the last known state of the object; it is possible that
+//     several changes were combined together, so you can't use this to see
+//     every single change.
+//   - OnDelete gets the final state of the item if it is known.
+type ResourceEventHandler interface {
+	OnAdd(obj interface{})
+	OnUpdate(oldObj, newObj interface{})
+	OnDelete(obj interface{})
+}
+
+// ResourceEventHandlerFuncs is an adaptor to let you easily specify as many or
+// as few of the notification functions as you want while still implementing
+// ResourceEventHandler.
+type ResourceEventHandlerFuncs struct {
+	AddFunc    func(obj interface{})
+	UpdateFunc func(oldObj, newObj interface{})
+	DeleteFunc func(obj interface{})
+}
+
+// OnAdd calls AddFunc if it's not nil.
+func (r ResourceEventHandlerFuncs) OnAdd(obj interface{}) {
+	if r.AddFunc != nil {
+		r.AddFunc(obj)
+	}
+}
+
+// OnUpdate calls UpdateFunc if it's not nil.
+func (r ResourceEventHandlerFuncs) OnUpdate(oldObj, newObj interface{}) {
+	if r.UpdateFunc != nil {
+		r.UpdateFunc(oldObj, newObj)
+	}
+}
+
+// OnDelete calls DeleteFunc if it's not nil.
+func (r ResourceEventHandlerFuncs) OnDelete(obj interface{}) {
+	if r.DeleteFunc != nil {
+		r.DeleteFunc(obj)
+	}
+}
+
+// Informer keeps an indexed Store up to date with the contents of the server
+// using a single Reflector, and notifies any number of registered handlers of
+// the changes it observes. One Informer can be shared by several controllers
+// that are interested in the same resource, so that the resource is only
+// listed and watched once.
+type Informer struct {
+	indexer   Indexer
+	reflector *Reflector
+	// resyncPeriod is how often every object in the store is redelivered to
+	// the handlers as an update. Resyncs are served from the local store and
+	// never hit the server.
+	resyncPeriod time.Duration
+
+	lock     sync.RWMutex
+	handlers []ResourceEventHandler
+	synced   bool
+	started  bool
+}
+
+// NewInformer returns an Informer for the resources listed and watched by lw,
+// which must be of objType. If resyncPeriod is non-zero, every object in the
+// store is periodically passed to OnUpdate. The store is keyed with
+// MetaNamespaceKeyFunc and always has a "namespace" index in addition to the
+// given indexers.
+func NewInformer(lw ListerWatcher, objType runtime.Object, resyncPeriod time.Duration, indexers Indexers) *Informer {
+	allIndexers := Indexers{"namespace": MetaNamespaceIndexFunc}
+	for name, f := range indexers {
+		allIndexers[name] = f
+	}
+	i := &Informer{
+		indexer:      NewIndexer(MetaNamespaceKeyFunc, allIndexers),
+		resyncPeriod: resyncPeriod,
+	}
+	i.reflector = NewReflector(lw, objType, &informerStore{i}, 0)
+	return i
+}
+
+// AddEventHandler registers a handler for the events of this informer. Handlers
+// added after the informer has synced are only notified of later changes.
+func (i *Informer) AddEventHandler(handler ResourceEventHandler) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.handlers = append(i.handlers, handler)
+}
+
+// GetStore returns the informer's local cache. Callers must treat the
+// objects in it as read-only.
+func (i *Informer) GetStore() Indexer {
+	return i.indexer
+}
+
+// HasSynced returns true once the informer has received the initial list of
+// objects from the server.
+func (i *Informer) HasSynced() bool {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	return i.synced
+}
+
+// Run starts the informer and returns immediately. It stops when stopCh is
+// closed. Every controller sharing an informer may call Run; only the first
+// call has an effect.
+func (i *Informer) Run(stopCh <-chan struct{}) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	if i.started {
+		return
+	}
+	i.started = true
+	i.reflector.RunUntil(stopCh)
+	if i.resyncPeriod > 0 {
+		go util.Until(i.resync, i.resyncPeriod, stopCh)
+	}
+}
+
+// WaitForSync blocks until every informer has synced. It returns false if
+// stopCh is closed first.
+func WaitForSync(stopCh <-chan struct{}, informers ...*Informer) bool {
+	for {
+		synced := true
+		for _, i := range informers {
+			synced = synced && i.HasSynced()
+		}
+		if synced {
+			return true
+		}
+		select {
+		case <-stopCh:
+			return false
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// resync redelivers every object in the store to the handlers as an update.
+func (i *Informer) resync() {
+	if !i.HasSynced() {
+		return
+	}
+	for _, obj := range i.indexer.List() {
+		i.notifyUpdate(obj, obj)
+	}
+}
+
+func (i *Informer) currentHandlers() []ResourceEventHandler {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	return i.handlers
+}
+
+func (i *Informer) notifyAdd(obj interface{}) {
+	for _, h := range i.currentHandlers() {
+		h.OnAdd(obj)
+	}
+}
+
+func (i *Informer) notifyUpdate(oldObj, newObj interface{}) {
+	for _, h := range i.currentHandlers() {
+		h.OnUpdate(oldObj, newObj)
+	}
+}
+
+func (i *Informer) notifyDelete(obj interface{}) {
+	for _, h := range i.currentHandlers() {
+		h.OnDelete(obj)
+	}
+}
+
+// informerStore is the Store handed to the informer's Reflector. It applies
+// each change to the informer's indexer and then notifies the handlers.
+type informerStore struct {
+	informer *Informer
+}
+
+func (s *informerStore) Add(obj interface{}) error {
+	old, exists, err := s.informer.indexer.Get(obj)
+	if err != nil {
+		return err
+	}
+	if err := s.informer.indexer.Add(obj); err != nil {
+		return err
+	}
+	if exists {
+		s.informer.notifyUpdate(old, obj)
+	} else {
+		s.informer.notifyAdd(obj)
+	}
+	return nil
+}
+
+func (s *informerStore) Update(obj interface{}) error {
+	return s.Add(obj)
+}
+
+func (s *informerStore) Delete(obj interface{}) error {
+	if err := s.informer.indexer.Delete(obj); err != nil {
+		return err
+	}
+	s.informer.notifyDelete(obj)
+	return nil
+}
+
+func (s *informerStore) List() []interface{} {
+	return s.informer.indexer.List()
+}
+
+func (s *informerStore) Get(obj interface{}) (interface{}, bool, error) {
+	return s.informer.indexer.Get(obj)
+}
+
+func (s *informerStore) GetByKey(key string) (interface{}, bool, error) {
+	return s.informer.indexer.GetByKey(key)
+}
+
+// Replace swaps the contents of the indexer for list and notifies the
+// handlers of every object that was added, updated or removed as a result.
+func (s *informerStore) Replace(list []interface{}) error {
+	i := s.informer
+	old := map[string]interface{}{}
+	for _, obj := range i.indexer.List() {
+		key, err := MetaNamespaceKeyFunc(obj)
+		if err != nil {
+			return fmt.Errorf("couldn't create key for object: %v", err)
+		}
+		old[key] = obj
+	}
+	keys := make([]string, 0, len(list))
+	for _, obj := range list {
+		key, err := MetaNamespaceKeyFunc(obj)
+		if err != nil {
+			return fmt.Errorf("couldn't create key for object: %v", err)
+		}
+		keys = append(keys, key)
+	}
+	if err := i.indexer.Replace(list); err != nil {
+		return err
+	}
+
+	for ix, obj := range list {
+		if oldObj, exists := old[keys[ix]]; exists {
+			delete(old, keys[ix])
+			i.notifyUpdate(oldObj, obj)
+		} else {
+			i.notifyAdd(obj)
+		}
+	}
+	for _, obj := range old {
+		i.notifyDelete(obj)
+	}
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.synced = true
+	return nil
+}
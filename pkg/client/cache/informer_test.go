@@ -0,0 +1,145 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+)
+
+type recordingHandler struct {
+	lock    sync.Mutex
+	added   []string
+	updated []string
+	deleted []string
+}
+
+func (r *recordingHandler) OnAdd(obj interface{}) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.added = append(r.added, obj.(*api.Pod).Name)
+}
+
+func (r *recordingHandler) OnUpdate(oldObj, newObj interface{}) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.updated = append(r.updated, oldObj.(*api.Pod).ResourceVersion+"->"+newObj.(*api.Pod).ResourceVersion)
+}
+
+func (r *recordingHandler) OnDelete(obj interface{}) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.deleted = append(r.deleted, obj.(*api.Pod).Name)
+}
+
+func TestInformerReplace(t *testing.T) {
+	i := NewInformer(&testLW{}, &api.Pod{}, 0, nil)
+	h := &recordingHandler{}
+	i.AddEventHandler(h)
+	s := &informerStore{i}
+
+	if i.HasSynced() {
+		t.Errorf("expected informer not to be synced before the first list")
+	}
+	s.Add(&api.Pod{ObjectMeta: api.ObjectMeta{Name: "foo", ResourceVersion: "1"}})
+	s.Add(&api.Pod{ObjectMeta: api.ObjectMeta{Name: "bar", ResourceVersion: "1"}})
+	s.Replace([]interface{}{
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "foo", ResourceVersion: "2"}},
+		&api.Pod{ObjectMeta: api.ObjectMeta{Name: "baz", ResourceVersion: "1"}},
+	})
+
+	if !i.HasSynced() {
+		t.Errorf("expected informer to be synced after a list")
+	}
+	if e, a := []string{"foo", "bar", "baz"}, h.added; !api.Semantic.DeepEqual(e, a) {
+		t.Errorf("expected adds %v, got %v", e, a)
+	}
+	if e, a := []string{"1->2"}, h.updated; !api.Semantic.DeepEqual(e, a) {
+		t.Errorf("expected updates %v, got %v", e, a)
+	}
+	if e, a := []string{"bar"}, h.deleted; !api.Semantic.DeepEqual(e, a) {
+		t.Errorf("expected deletes %v, got %v", e, a)
+	}
+	if _, exists, _ := i.GetStore().GetByKey("bar"); exists {
+		t.Errorf("expected bar to be removed from the store")
+	}
+}
+
+func TestInformerWatch(t *testing.T) {
+	fw := watch.NewFake()
+	lw := &testLW{
+		ListFunc: func() (runtime.Object, error) {
+			return &api.PodList{
+				ListMeta: api.ListMeta{ResourceVersion: "1"},
+				Items:    []api.Pod{{ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "ns", ResourceVersion: "1"}}},
+			}, nil
+		},
+		WatchFunc: func(resourceVersion string) (watch.Interface, error) {
+			return fw, nil
+		},
+	}
+	i := NewInformer(lw, &api.Pod{}, 0, nil)
+	deleted := make(chan string, 1)
+	i.AddEventHandler(ResourceEventHandlerFuncs{
+		DeleteFunc: func(obj interface{}) { deleted <- obj.(*api.Pod).Name },
+	})
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	i.Run(stopCh)
+
+	fw.Add(&api.Pod{ObjectMeta: api.ObjectMeta{Name: "bar", Namespace: "ns", ResourceVersion: "2"}})
+	fw.Delete(&api.Pod{ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "ns", ResourceVersion: "3"}})
+	select {
+	case name := <-deleted:
+		if name != "foo" {
+			t.Errorf("expected foo to be deleted, got %s", name)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for delete")
+	}
+
+	items, err := i.GetStore().Index("namespace", &api.Pod{ObjectMeta: api.ObjectMeta{Namespace: "ns"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 1 || items[0].(*api.Pod).Name != "bar" {
+		t.Errorf("expected only bar in namespace ns, got %#v", items)
+	}
+}
+
+func TestInformerResync(t *testing.T) {
+	i := NewInformer(&testLW{}, &api.Pod{}, time.Hour, nil)
+	h := &recordingHandler{}
+	i.AddEventHandler(h)
+	s := &informerStore{i}
+
+	s.Add(&api.Pod{ObjectMeta: api.ObjectMeta{Name: "foo", ResourceVersion: "1"}})
+	i.resync()
+	if len(h.updated) != 0 {
+		t.Errorf("expected no resync before the informer has synced, got %v", h.updated)
+	}
+	s.Replace([]interface{}{&api.Pod{ObjectMeta: api.ObjectMeta{Name: "foo", ResourceVersion: "1"}}})
+	i.resync()
+	if e, a := []string{"1->1", "1->1"}, h.updated; !api.Semantic.DeepEqual(e, a) {
+		t.Errorf("expected updates %v, got %v", e, a)
+	}
+}
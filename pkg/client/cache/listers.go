@@ -116,7 +116,10 @@ func (s *StoreToServiceLister) GetPodServices(pod api.Pod) (services []api.Servi
 		if service.Namespace != pod.Namespace {
 			continue
 		}
-		selector = labels.Set(service.Spec.Selector).AsSelector()
+		selector, err = api.LabelSelectorAsSelector(service.Spec.Selector, service.Spec.SelectorRequirements)
+		if err != nil {
+			return nil, err
+		}
 		if selector.Matches(labels.Set(pod.Labels)) {
 			services = append(services, service)
 		}
@@ -0,0 +1,167 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+)
+
+// RateLimitingQueue is a queue of keys for controllers to process. A key that
+// is added several times before it is processed is only processed once, and a
+// key is never handed to two workers at the same time: if it is added while
+// being processed, it is queued again once the worker calls Done. Keys whose
+// processing failed can be requeued with a per-key exponential backoff.
+type RateLimitingQueue struct {
+	lock sync.Mutex
+	cond sync.Cond
+
+	// queue holds the keys waiting to be processed, in order. Every key in
+	// queue is also in dirty.
+	queue []string
+	// dirty holds every key that needs processing.
+	dirty util.StringSet
+	// processing holds the keys currently handed out to workers. A key may be
+	// both dirty and processing, in which case it is queued again on Done.
+	processing util.StringSet
+	// failures counts the consecutive AddRateLimited calls for each key.
+	failures map[string]int
+
+	baseDelay    time.Duration
+	maxDelay     time.Duration
+	shuttingDown bool
+}
+
+// NewRateLimitingQueue returns a queue whose rate limited keys are delayed by
+// baseDelay*2^n, capped at maxDelay, where n is the number of times the key
+// has been requeued since it was last forgotten.
+func NewRateLimitingQueue(baseDelay, maxDelay time.Duration) *RateLimitingQueue {
+	q := &RateLimitingQueue{
+		dirty:      util.StringSet{},
+		processing: util.StringSet{},
+		failures:   map[string]int{},
+		baseDelay:  baseDelay,
+		maxDelay:   maxDelay,
+	}
+	q.cond.L = &q.lock
+	return q
+}
+
+// Add marks key as needing processing.
+func (q *RateLimitingQueue) Add(key string) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if q.shuttingDown || q.dirty.Has(key) {
+		return
+	}
+	q.dirty.Insert(key)
+	if q.processing.Has(key) {
+		return
+	}
+	q.queue = append(q.queue, key)
+	q.cond.Signal()
+}
+
+// AddAfter adds key to the queue once duration has passed.
+func (q *RateLimitingQueue) AddAfter(key string, duration time.Duration) {
+	if duration <= 0 {
+		q.Add(key)
+		return
+	}
+	time.AfterFunc(duration, func() { q.Add(key) })
+}
+
+// AddRateLimited adds key to the queue after its backoff delay, and doubles the
+// delay for the next time.
+func (q *RateLimitingQueue) AddRateLimited(key string) {
+	q.lock.Lock()
+	n := q.failures[key]
+	q.failures[key]++
+	delay := q.maxDelay
+	// Stop shifting well before baseDelay could overflow.
+	if n < 32 {
+		if d := q.baseDelay << uint(n); d > 0 && d < q.maxDelay {
+			delay = d
+		}
+	}
+	q.lock.Unlock()
+	q.AddAfter(key, delay)
+}
+
+// Forget resets the backoff of key. Callers should call it once a key has
+// been processed successfully.
+func (q *RateLimitingQueue) Forget(key string) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	delete(q.failures, key)
+}
+
+// NumRequeues returns how many times key has been rate limited since it was
+// last forgotten.
+func (q *RateLimitingQueue) NumRequeues(key string) int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return q.failures[key]
+}
+
+// Get blocks until a key is available and returns it. The caller must call
+// Done with the key once it has been processed. If shutdown is true, the queue
+// has been shut down and the caller should stop.
+func (q *RateLimitingQueue) Get() (key string, shutdown bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	for len(q.queue) == 0 && !q.shuttingDown {
+		q.cond.Wait()
+	}
+	if len(q.queue) == 0 {
+		return "", true
+	}
+	key, q.queue = q.queue[0], q.queue[1:]
+	q.processing.Insert(key)
+	q.dirty.Delete(key)
+	return key, false
+}
+
+// Done marks key as processed. If it was added again while it was being
+// processed, it is put back in the queue.
+func (q *RateLimitingQueue) Done(key string) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.processing.Delete(key)
+	if q.dirty.Has(key) {
+		q.queue = append(q.queue, key)
+		q.cond.Signal()
+	}
+}
+
+// Len returns the number of keys waiting to be processed.
+func (q *RateLimitingQueue) Len() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return len(q.queue)
+}
+
+// ShutDown makes Get return to every waiting worker once the queue is
+// drained, and causes further additions to be ignored.
+func (q *RateLimitingQueue) ShutDown() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.shuttingDown = true
+	q.cond.Broadcast()
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimitingQueueDeduplicates(t *testing.T) {
+	q := NewRateLimitingQueue(time.Millisecond, time.Second)
+	q.Add("a")
+	q.Add("b")
+	q.Add("a")
+	if e, a := 2, q.Len(); e != a {
+		t.Fatalf("expected %d queued keys, got %d", e, a)
+	}
+
+	key, _ := q.Get()
+	if key != "a" {
+		t.Fatalf("expected a, got %s", key)
+	}
+	// Adding a key that is being processed must not hand it to another worker.
+	q.Add("a")
+	if e, a := 1, q.Len(); e != a {
+		t.Errorf("expected %d queued keys, got %d", e, a)
+	}
+	q.Done("a")
+	if e, a := 2, q.Len(); e != a {
+		t.Errorf("expected a to be requeued on Done, got %d queued keys", a)
+	}
+
+	key, _ = q.Get()
+	q.Done(key)
+	key, _ = q.Get()
+	q.Done(key)
+	if e, a := 0, q.Len(); e != a {
+		t.Errorf("expected %d queued keys, got %d", e, a)
+	}
+}
+
+func TestRateLimitingQueueBackoff(t *testing.T) {
+	q := NewRateLimitingQueue(time.Millisecond, 4*time.Millisecond)
+	for i := 0; i < 5; i++ {
+		q.AddRateLimited("a")
+		key, _ := q.Get()
+		if key != "a" {
+			t.Fatalf("expected a, got %s", key)
+		}
+		q.Done(key)
+	}
+	if e, a := 5, q.NumRequeues("a"); e != a {
+		t.Errorf("expected %d requeues, got %d", e, a)
+	}
+	q.Forget("a")
+	if e, a := 0, q.NumRequeues("a"); e != a {
+		t.Errorf("expected %d requeues after Forget, got %d", e, a)
+	}
+}
+
+func TestRateLimitingQueueShutDown(t *testing.T) {
+	q := NewRateLimitingQueue(time.Millisecond, time.Second)
+	done := make(chan bool)
+	go func() {
+		_, shutdown := q.Get()
+		done <- shutdown
+	}()
+	q.ShutDown()
+	select {
+	case shutdown := <-done:
+		if !shutdown {
+			t.Errorf("expected Get to report shutdown")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for Get to return")
+	}
+	q.Add("a")
+	if e, a := 0, q.Len(); e != a {
+		t.Errorf("expected adds after shutdown to be ignored, got %d queued keys", a)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"net"
 	"net/url"
+	"path"
 	"strings"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
@@ -41,6 +42,9 @@ type Interface interface {
 	ResourceQuotasNamespacer
 	SecretsNamespacer
 	NamespacesInterface
+	PersistentVolumesInterface
+	PersistentVolumeClaimsNamespacer
+	ResourcesNamespacer
 }
 
 func (c *Client) ReplicationControllers(namespace string) ReplicationControllerInterface {
@@ -83,10 +87,23 @@ func (c *Client) Namespaces() NamespaceInterface {
 	return newNamespaces(c)
 }
 
+func (c *Client) PersistentVolumes() PersistentVolumeInterface {
+	return newPersistentVolumes(c)
+}
+
+func (c *Client) PersistentVolumeClaims(namespace string) PersistentVolumeClaimInterface {
+	return newPersistentVolumeClaims(c, namespace)
+}
+
+func (c *Client) Resources(resource, namespace string) ResourceInterface {
+	return newResources(c, resource, namespace)
+}
+
 // VersionInterface has a method to retrieve the server version.
 type VersionInterface interface {
 	ServerVersion() (*version.Info, error)
 	ServerAPIVersions() (*api.APIVersions, error)
+	ServerResources() (*api.APIResourceList, error)
 }
 
 // APIStatus is exposed by errors that can be converted to an api.Status object
@@ -128,6 +145,21 @@ func (c *Client) ServerAPIVersions() (*api.APIVersions, error) {
 	return &v, nil
 }
 
+// ServerResources retrieves and parses the list of resources the server supports
+// in the API version of the client.
+func (c *Client) ServerResources() (*api.APIResourceList, error) {
+	body, err := c.Get().AbsPath(path.Clean(c.baseURL.Path)).Do().Raw()
+	if err != nil {
+		return nil, err
+	}
+	var list api.APIResourceList
+	err = json.Unmarshal(body, &list)
+	if err != nil {
+		return nil, fmt.Errorf("got '%s': %v", string(body), err)
+	}
+	return &list, nil
+}
+
 // IsTimeout tests if this is a timeout error in the underlying transport.
 // This is unbelievably ugly.
 // See: http://stackoverflow.com/questions/23494950/specifically-check-for-timeout-error for details
@@ -243,6 +243,36 @@ func TestGetServerVersion(t *testing.T) {
 	}
 }
 
+func TestGetServerResources(t *testing.T) {
+	expect := api.APIResourceList{
+		APIVersion: testapi.Version(),
+		Resources: []api.APIResource{
+			{Name: "pods", Namespaced: true, Verbs: []string{"get", "list"}},
+		},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if e, a := "/api/"+testapi.Version(), req.URL.Path; e != a {
+			t.Errorf("expected path %q, got %q", e, a)
+		}
+		output, err := json.Marshal(expect)
+		if err != nil {
+			t.Errorf("unexpected encoding error: %v", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(output)
+	}))
+	client := NewOrDie(&Config{Host: server.URL, Version: testapi.Version()})
+	got, err := client.ServerResources()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e, a := expect, *got; !reflect.DeepEqual(e, a) {
+		t.Errorf("expected %v, got %v", e, a)
+	}
+}
+
 func TestGetServerAPIVersions(t *testing.T) {
 	versions := []string{"v1", "v2", "v3"}
 	expect := api.APIVersions{Versions: versions}
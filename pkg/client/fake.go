@@ -35,22 +35,26 @@ type FakeAction struct {
 // Fake implements Interface. Meant to be embedded into a struct to get a default
 // implementation. This makes faking out just the method you want to test easier.
 type Fake struct {
-	Actions             []FakeAction
-	PodsList            api.PodList
-	CtrlList            api.ReplicationControllerList
-	Ctrl                api.ReplicationController
-	ServiceList         api.ServiceList
-	EndpointsList       api.EndpointsList
-	MinionsList         api.NodeList
-	EventsList          api.EventList
-	LimitRangesList     api.LimitRangeList
-	ResourceQuotaStatus api.ResourceQuota
-	ResourceQuotasList  api.ResourceQuotaList
-	NamespacesList      api.NamespaceList
-	SecretList          api.SecretList
-	Secret              api.Secret
-	Err                 error
-	Watch               watch.Interface
+	Actions                   []FakeAction
+	PodsList                  api.PodList
+	CtrlList                  api.ReplicationControllerList
+	Ctrl                      api.ReplicationController
+	ServiceList               api.ServiceList
+	EndpointsList             api.EndpointsList
+	MinionsList               api.NodeList
+	EventsList                api.EventList
+	LimitRangesList           api.LimitRangeList
+	ResourceQuotaStatus       api.ResourceQuota
+	ResourceQuotasList        api.ResourceQuotaList
+	NamespacesList            api.NamespaceList
+	SecretList                api.SecretList
+	Secret                    api.Secret
+	PersistentVolumesList     api.PersistentVolumeList
+	PersistentVolumeClaimList api.PersistentVolumeClaimList
+	APIResources              []api.APIResource
+	ResourceLists             map[string]runtime.Object
+	Err                       error
+	Watch                     watch.Interface
 }
 
 func (c *Fake) LimitRanges(namespace string) LimitRangeInterface {
@@ -93,6 +97,18 @@ func (c *Fake) Namespaces() NamespaceInterface {
 	return &FakeNamespaces{Fake: c}
 }
 
+func (c *Fake) PersistentVolumes() PersistentVolumeInterface {
+	return &FakePersistentVolumes{Fake: c}
+}
+
+func (c *Fake) PersistentVolumeClaims(namespace string) PersistentVolumeClaimInterface {
+	return &FakePersistentVolumeClaims{Fake: c, Namespace: namespace}
+}
+
+func (c *Fake) Resources(resource, namespace string) ResourceInterface {
+	return &FakeResources{Fake: c, Resource: resource, Namespace: namespace}
+}
+
 func (c *Fake) ServerVersion() (*version.Info, error) {
 	c.Actions = append(c.Actions, FakeAction{Action: "get-version", Value: nil})
 	versionInfo := version.Get()
@@ -104,6 +120,11 @@ func (c *Fake) ServerAPIVersions() (*api.APIVersions, error) {
 	return &api.APIVersions{Versions: []string{"v1beta1", "v1beta2"}}, nil
 }
 
+func (c *Fake) ServerResources() (*api.APIResourceList, error) {
+	c.Actions = append(c.Actions, FakeAction{Action: "get-apiresources", Value: nil})
+	return &api.APIResourceList{APIVersion: testapi.Version(), Resources: c.APIResources}, c.Err
+}
+
 type HTTPClientFunc func(*http.Request) (*http.Response, error)
 
 func (f HTTPClientFunc) Do(req *http.Request) (*http.Response, error) {
@@ -128,6 +149,10 @@ func (c *FakeRESTClient) Put() *Request {
 	return NewRequest(c, "PUT", &url.URL{Host: "localhost"}, testapi.Version(), c.Codec, c.Legacy, c.Legacy)
 }
 
+func (c *FakeRESTClient) Patch(pt api.PatchType) *Request {
+	return NewRequest(c, "PATCH", &url.URL{Host: "localhost"}, testapi.Version(), c.Codec, c.Legacy, c.Legacy).SetHeader("Content-Type", string(pt))
+}
+
 func (c *FakeRESTClient) Post() *Request {
 	return NewRequest(c, "POST", &url.URL{Host: "localhost"}, testapi.Version(), c.Codec, c.Legacy, c.Legacy)
 }
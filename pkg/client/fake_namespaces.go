@@ -65,6 +65,6 @@ func (c *FakeNamespaces) Finalize(namespace *api.Namespace) (*api.Namespace, err
 }
 
 func (c *FakeNamespaces) Status(namespace *api.Namespace) (*api.Namespace, error) {
-	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "status-namespace", Value: namespace.Name})
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "status-namespace", Value: namespace})
 	return &api.Namespace{}, nil
 }
@@ -0,0 +1,45 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+)
+
+// FakeResources implements ResourceInterface. Meant to be embedded into a struct to get a default
+// implementation. This makes faking out just the methods you want to test easier.
+type FakeResources struct {
+	Fake      *Fake
+	Resource  string
+	Namespace string
+}
+
+func (c *FakeResources) List(label labels.Selector, field fields.Selector) (runtime.Object, error) {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "list-" + c.Resource})
+	if list, found := c.Fake.ResourceLists[c.Resource]; found {
+		return api.Scheme.CopyOrDie(list), c.Fake.Err
+	}
+	return &api.List{}, c.Fake.Err
+}
+
+func (c *FakeResources) Delete(name string) error {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "delete-" + c.Resource, Value: name})
+	return c.Fake.Err
+}
@@ -54,6 +54,10 @@ type Config struct {
 	// to a RESTClient or Client. Required when initializing a RESTClient, optional
 	// when initializing a Client.
 	Codec runtime.Codec
+	// ContentType is the media type that objects are sent and preferably received in.
+	// JSON is used if it is empty. If it is runtime.ProtobufContentType and Codec is not
+	// set, SetKubernetesDefaults chooses the protobuf codec of Version.
+	ContentType string
 
 	// Server requires Basic authentication
 	Username string
@@ -77,6 +81,11 @@ type Config struct {
 	// Transport may be used for custom HTTP behavior. This attribute may not
 	// be specified with the TLS client certificate options.
 	Transport http.RoundTripper
+
+	// MaxRetries is the number of times a request is sent again after a 429 Too
+	// Many Requests response. Zero means DefaultMaxRetries, and a negative
+	// number disables retries.
+	MaxRetries int
 }
 
 type KubeletConfig struct {
@@ -170,6 +179,13 @@ func SetKubernetesDefaults(config *Config) error {
 	}
 	if config.Codec == nil {
 		config.Codec = versionInterfaces.Codec
+		if config.ContentType == runtime.ProtobufContentType {
+			codec, err := latest.ProtobufCodecFor(version)
+			if err != nil {
+				return err
+			}
+			config.Codec = codec
+		}
 	}
 	config.LegacyBehavior = (version == "v1beta1" || version == "v1beta2")
 	return nil
@@ -193,6 +209,12 @@ func RESTClientFor(config *Config) (*RESTClient, error) {
 	}
 
 	client := NewRESTClient(baseURL, config.Version, config.Codec, config.LegacyBehavior)
+	client.ContentType = config.ContentType
+	if config.MaxRetries < 0 {
+		client.MaxRetries = 0
+	} else if config.MaxRetries > 0 {
+		client.MaxRetries = config.MaxRetries
+	}
 
 	transport, err := TransportFor(config)
 	if err != nil {
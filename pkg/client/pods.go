@@ -32,7 +32,7 @@ type PodsNamespacer interface {
 type PodInterface interface {
 	List(selector labels.Selector) (*api.PodList, error)
 	Get(name string) (*api.Pod, error)
-	Delete(name string) error
+	Delete(name string, options *api.DeleteOptions) error
 	Create(pod *api.Pod) (*api.Pod, error)
 	Update(pod *api.Pod) (*api.Pod, error)
 	Watch(label labels.Selector, field fields.Selector, resourceVersion string) (watch.Interface, error)
@@ -68,9 +68,14 @@ func (c *pods) Get(name string) (result *api.Pod, err error) {
 	return
 }
 
-// Delete takes the name of the pod, and returns an error if one occurs
-func (c *pods) Delete(name string) error {
-	return c.r.Delete().Namespace(c.ns).Resource("pods").Name(name).Do().Error()
+// Delete takes the name of the pod and optional delete options, and returns an error if one occurs.
+// If options is nil the server applies the pod's default grace period.
+func (c *pods) Delete(name string, options *api.DeleteOptions) error {
+	request := c.r.Delete().Namespace(c.ns).Resource("pods").Name(name)
+	if options != nil {
+		request = request.Body(options)
+	}
+	return request.Do().Error()
 }
 
 // Create takes the representation of a pod.  Returns the server's representation of the pod, and an error, if it occurs.
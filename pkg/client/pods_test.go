@@ -135,7 +135,18 @@ func TestDeletePod(t *testing.T) {
 		Request:  testRequest{Method: "DELETE", Path: testapi.ResourcePath("pods", ns, "foo"), Query: buildQueryValues(ns, nil)},
 		Response: Response{StatusCode: 200},
 	}
-	err := c.Setup().Pods(ns).Delete("foo")
+	err := c.Setup().Pods(ns).Delete("foo", nil)
+	c.Validate(t, nil, err)
+}
+
+func TestDeletePodWithOptions(t *testing.T) {
+	ns := api.NamespaceDefault
+	options := api.NewDeleteOptions(10)
+	c := &testClient{
+		Request:  testRequest{Method: "DELETE", Path: testapi.ResourcePath("pods", ns, "foo"), Query: buildQueryValues(ns, nil), Body: options},
+		Response: Response{StatusCode: 200},
+	}
+	err := c.Setup().Pods(ns).Delete("foo", options)
 	c.Validate(t, nil, err)
 }
 
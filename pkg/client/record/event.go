@@ -60,7 +60,8 @@ func StartRecording(sink EventSink) watch.Interface {
 		eventCopy := *event
 		event = &eventCopy
 
-		previousEvent := getEvent(event)
+		key := aggregateEvent(event)
+		previousEvent := getEvent(key)
 		updateExistingEvent := previousEvent.Count > 0
 		if updateExistingEvent {
 			event.Count = previousEvent.Count + 1
@@ -71,7 +72,7 @@ func StartRecording(sink EventSink) watch.Interface {
 
 		tries := 0
 		for {
-			if recordEvent(sink, event, updateExistingEvent) {
+			if recordEvent(sink, event, key, updateExistingEvent) {
 				break
 			}
 			tries++
@@ -93,8 +94,8 @@ func StartRecording(sink EventSink) watch.Interface {
 // recordEvent attempts to write event to a sink. It returns true if the event
 // was successfully recorded or discarded, false if it should be retried.
 // If updateExistingEvent is false, it creates a new event, otherwise it updates
-// existing event.
-func recordEvent(sink EventSink, event *api.Event, updateExistingEvent bool) bool {
+// existing event. The result is remembered under key.
+func recordEvent(sink EventSink, event *api.Event, key string, updateExistingEvent bool) bool {
 	var newEvent *api.Event
 	var err error
 	if updateExistingEvent {
@@ -103,7 +104,7 @@ func recordEvent(sink EventSink, event *api.Event, updateExistingEvent bool) boo
 		newEvent, err = sink.Create(event)
 	}
 	if err == nil {
-		addOrUpdateEvent(newEvent)
+		addOrUpdateEvent(key, newEvent)
 		return true
 	}
 
@@ -457,7 +457,8 @@ func TestLotsOfEvents(t *testing.T) {
 		APIVersion: "v1beta1",
 	}
 	for i := 0; i < maxQueuedEvents; i++ {
-		go FromSource(testSource).Event(ref, "Reason", strconv.Itoa(i))
+		// Use a different reason for each event so they are not combined.
+		go FromSource(testSource).Event(ref, "Reason"+strconv.Itoa(i), strconv.Itoa(i))
 	}
 	// Make sure no events were dropped by either of the listeners.
 	for i := 0; i < maxQueuedEvents; i++ {
@@ -17,7 +17,9 @@ limitations under the License.
 package record
 
 import (
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
@@ -40,6 +42,14 @@ type history struct {
 
 const (
 	maxLruCacheEntries = 4096
+
+	// Once this many events with the same source, involved object and reason
+	// but different messages have been seen within similarEventsInterval,
+	// further ones are combined into a single event.
+	maxSimilarEvents      = 10
+	similarEventsInterval = 10 * time.Minute
+	// The number of most recent messages kept in a combined event.
+	maxSampleMessages = 3
 )
 
 type historyCache struct {
@@ -49,10 +59,9 @@ type historyCache struct {
 
 var previousEvents = historyCache{cache: lru.New(maxLruCacheEntries)}
 
-// addOrUpdateEvent creates a new entry for the given event in the previous events hash table if the event
+// addOrUpdateEvent creates a new entry for the given key in the previous events hash table if the event
 // doesn't already exist, otherwise it updates the existing entry.
-func addOrUpdateEvent(newEvent *api.Event) history {
-	key := getEventKey(newEvent)
+func addOrUpdateEvent(key string, newEvent *api.Event) history {
 	previousEvents.Lock()
 	defer previousEvents.Unlock()
 	previousEvents.cache.Add(
@@ -66,10 +75,9 @@ func addOrUpdateEvent(newEvent *api.Event) history {
 	return getEventFromCache(key)
 }
 
-// getEvent returns the entry corresponding to the given event, if one exists, otherwise a history object
+// getEvent returns the entry corresponding to the given key, if one exists, otherwise a history object
 // with a count of 0 is returned.
-func getEvent(event *api.Event) history {
-	key := getEventKey(event)
+func getEvent(key string) history {
 	previousEvents.RLock()
 	defer previousEvents.RUnlock()
 	return getEventFromCache(key)
@@ -87,6 +95,11 @@ func getEventFromCache(key string) history {
 }
 
 func getEventKey(event *api.Event) string {
+	return getAggregateKey(event) + event.Message
+}
+
+// getAggregateKey returns the key shared by events that only differ by their message.
+func getAggregateKey(event *api.Event) string {
 	return event.Source.Component +
 		event.Source.Host +
 		event.InvolvedObject.Kind +
@@ -94,6 +107,50 @@ func getEventKey(event *api.Event) string {
 		event.InvolvedObject.Name +
 		string(event.InvolvedObject.UID) +
 		event.InvolvedObject.APIVersion +
-		event.Reason +
-		event.Message
+		event.Reason
+}
+
+type similarEvents struct {
+	// The distinct messages seen since the first of these events.
+	messages util.StringSet
+	// The most recent distinct messages, oldest first.
+	samples []string
+	// The time of the first of these events.
+	firstTimestamp util.Time
+}
+
+var similarEventsCache = historyCache{cache: lru.New(maxLruCacheEntries)}
+
+// aggregateEvent keeps track of the events that only differ by their message,
+// and returns the key under which the given event should be recorded. Once
+// there have been maxSimilarEvents different messages within
+// similarEventsInterval, the message of the event is replaced with a sample
+// of the recent messages, and all of them are recorded under the same key, so
+// they end up as a single event with a count.
+func aggregateEvent(event *api.Event) string {
+	key := getAggregateKey(event)
+	similarEventsCache.Lock()
+	defer similarEventsCache.Unlock()
+
+	record := similarEvents{messages: util.NewStringSet(), firstTimestamp: event.LastTimestamp}
+	if value, ok := similarEventsCache.cache.Get(key); ok {
+		previous := value.(similarEvents)
+		if event.LastTimestamp.Sub(previous.firstTimestamp.Time) <= similarEventsInterval {
+			record = previous
+		}
+	}
+	if !record.messages.Has(event.Message) {
+		record.messages.Insert(event.Message)
+		record.samples = append(record.samples, event.Message)
+		if len(record.samples) > maxSampleMessages {
+			record.samples = record.samples[len(record.samples)-maxSampleMessages:]
+		}
+	}
+	similarEventsCache.cache.Add(key, record)
+
+	if len(record.messages) < maxSimilarEvents {
+		return getEventKey(event)
+	}
+	event.Message = "(combined from similar events): " + strings.Join(record.samples, "; ")
+	return key
 }
@@ -17,7 +17,9 @@ limitations under the License.
 package record
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
@@ -46,7 +48,7 @@ func TestAddOrUpdateEventNoExisting(t *testing.T) {
 	}
 
 	// Act
-	result := addOrUpdateEvent(&event)
+	result := addOrUpdateEvent(getEventKey(&event), &event)
 
 	// Assert
 	compareEventWithHistoryEntry(&event, &result, t)
@@ -100,9 +102,9 @@ func TestAddOrUpdateEventExisting(t *testing.T) {
 	}
 
 	// Act
-	addOrUpdateEvent(&event1)
-	result1 := addOrUpdateEvent(&event2)
-	result2 := getEvent(&event1)
+	addOrUpdateEvent(getEventKey(&event1), &event1)
+	result1 := addOrUpdateEvent(getEventKey(&event2), &event2)
+	result2 := getEvent(getEventKey(&event1))
 
 	// Assert
 	compareEventWithHistoryEntry(&event2, &result1, t)
@@ -129,7 +131,7 @@ func TestGetEventNoExisting(t *testing.T) {
 	}
 
 	// Act
-	existingEvent := getEvent(&event)
+	existingEvent := getEvent(getEventKey(&event))
 
 	// Assert
 	if existingEvent.Count != 0 {
@@ -158,15 +160,75 @@ func TestGetEventExisting(t *testing.T) {
 		FirstTimestamp: eventTime,
 		LastTimestamp:  eventTime,
 	}
-	addOrUpdateEvent(&event)
+	addOrUpdateEvent(getEventKey(&event), &event)
 
 	// Act
-	existingEvent := getEvent(&event)
+	existingEvent := getEvent(getEventKey(&event))
 
 	// Assert
 	compareEventWithHistoryEntry(&event, &existingEvent, t)
 }
 
+func TestAggregateEvent(t *testing.T) {
+	eventTime := util.Now()
+	newEvent := func(message string, timestamp util.Time) *api.Event {
+		return &api.Event{
+			Reason:  "BackOff",
+			Message: message,
+			InvolvedObject: api.ObjectReference{
+				Kind:       "Pod",
+				Name:       "crash.looping",
+				Namespace:  "aggregated",
+				UID:        "A77D34AFB20242",
+				APIVersion: "v1beta3",
+			},
+			Source: api.EventSource{
+				Component: "kubelet",
+				Host:      "kublet.node5",
+			},
+			Count:          1,
+			FirstTimestamp: timestamp,
+			LastTimestamp:  timestamp,
+		}
+	}
+
+	keys := util.NewStringSet()
+	for i := 0; i < maxSimilarEvents-1; i++ {
+		event := newEvent(fmt.Sprintf("message %d", i), eventTime)
+		key := aggregateEvent(event)
+		if key != getEventKey(event) || event.Message != fmt.Sprintf("message %d", i) {
+			t.Errorf("Expected event %d to be left alone, got key %q and message %q", i, key, event.Message)
+		}
+		keys.Insert(key)
+	}
+	if len(keys) != maxSimilarEvents-1 {
+		t.Errorf("Expected %d different keys, got %d", maxSimilarEvents-1, len(keys))
+	}
+
+	var aggregateKey string
+	for i := maxSimilarEvents - 1; i < maxSimilarEvents+2; i++ {
+		event := newEvent(fmt.Sprintf("message %d", i), eventTime)
+		key := aggregateEvent(event)
+		if i == maxSimilarEvents-1 {
+			aggregateKey = key
+		}
+		if key != aggregateKey || key != getAggregateKey(event) {
+			t.Errorf("Expected event %d to be combined under %q, got %q", i, aggregateKey, key)
+		}
+		expected := fmt.Sprintf("(combined from similar events): message %d; message %d; message %d", i-2, i-1, i)
+		if event.Message != expected {
+			t.Errorf("Expected message %q, got %q", expected, event.Message)
+		}
+	}
+
+	// Once the interval is over, events are recorded individually again.
+	later := util.NewTime(eventTime.Add(similarEventsInterval + time.Second))
+	event := newEvent("message 0", later)
+	if key := aggregateEvent(event); key != getEventKey(event) || event.Message != "message 0" {
+		t.Errorf("Expected event to be left alone after the interval, got key %q and message %q", key, event.Message)
+	}
+}
+
 func compareEventWithHistoryEntry(expected *api.Event, actual *history, t *testing.T) {
 
 	if actual.Count != expected.Count {
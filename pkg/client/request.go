@@ -100,6 +100,7 @@ type Request struct {
 	path    string
 	subpath string
 	params  url.Values
+	headers http.Header
 
 	// structural elements of the request that are part of the Kubernetes API conventions
 	namespace    string
@@ -109,6 +110,7 @@ type Request struct {
 	subresource  string
 	selector     labels.Selector
 	timeout      time.Duration
+	maxRetries   int
 
 	apiVersion string
 
@@ -136,6 +138,16 @@ func NewRequest(client HTTPClient, verb string, baseURL *url.URL, apiVersion str
 	}
 }
 
+// MaxRetries sets the number of times the request is sent again after a 429
+// Too Many Requests response. Zero, the default, disables retries.
+func (r *Request) MaxRetries(maxRetries int) *Request {
+	if r.err != nil {
+		return r
+	}
+	r.maxRetries = maxRetries
+	return r
+}
+
 // Prefix adds segments to the relative beginning to the request path. These
 // items will be placed before the optional Namespace, Resource, or Name sections.
 // Setting AbsPath will clear any previously set Prefix segments
@@ -330,6 +342,20 @@ func (r *Request) Param(paramName, s string) *Request {
 	return r.setParam(paramName, s)
 }
 
+// PodLogOptions sets the query parameters of a request for the log of a
+// container from the given options.
+func (r *Request) PodLogOptions(opts *api.PodLogOptions) *Request {
+	if r.err != nil {
+		return r
+	}
+	for paramName, values := range opts.Values() {
+		for _, value := range values {
+			r.setParam(paramName, value)
+		}
+	}
+	return r
+}
+
 func (r *Request) setParam(paramName, value string) *Request {
 	if specialParams.Has(paramName) {
 		r.err = fmt.Errorf("must set %v through the corresponding function, not directly.", paramName)
@@ -342,6 +368,19 @@ func (r *Request) setParam(paramName, value string) *Request {
 	return r
 }
 
+// SetHeader sets the value of the given HTTP header on the request, replacing
+// any existing values.
+func (r *Request) SetHeader(key, value string) *Request {
+	if r.err != nil {
+		return r
+	}
+	if r.headers == nil {
+		r.headers = http.Header{}
+	}
+	r.headers.Set(key, value)
+	return r
+}
+
 // Timeout makes the request use the given duration as a timeout. Sets the "timeout"
 // parameter.
 func (r *Request) Timeout(d time.Duration) *Request {
@@ -369,9 +408,9 @@ func (r *Request) Body(obj interface{}) *Request {
 			r.err = err
 			return r
 		}
-		r.body = bytes.NewBuffer(data)
+		r.body = bytes.NewReader(data)
 	case []byte:
-		r.body = bytes.NewBuffer(t)
+		r.body = bytes.NewReader(t)
 	case io.Reader:
 		r.body = t
 	case runtime.Object:
@@ -380,7 +419,7 @@ func (r *Request) Body(obj interface{}) *Request {
 			r.err = err
 			return r
 		}
-		r.body = bytes.NewBuffer(data)
+		r.body = bytes.NewReader(data)
 	default:
 		r.err = fmt.Errorf("unknown type used for body: %+v", obj)
 	}
@@ -436,6 +475,9 @@ func (r *Request) Watch() (watch.Interface, error) {
 	if err != nil {
 		return nil, err
 	}
+	for key, values := range r.headers {
+		req.Header[key] = values
+	}
 	client := r.client
 	if client == nil {
 		client = http.DefaultClient
@@ -486,6 +528,9 @@ func (r *Request) Stream() (io.ReadCloser, error) {
 	if err != nil {
 		return nil, err
 	}
+	for key, values := range r.headers {
+		req.Header[key] = values
+	}
 	client := r.client
 	if client == nil {
 		client = http.DefaultClient
@@ -532,6 +577,47 @@ func (r *Request) Upgrade(config *Config, newRoundTripperFunc func(*tls.Config)
 	return upgradeRoundTripper.NewConnection(resp)
 }
 
+// DefaultMaxRetries is the number of times a request is sent again after a 429
+// Too Many Requests response before the response is returned to the caller,
+// unless the client is configured otherwise.
+const DefaultMaxRetries = 10
+
+// maxRetryDelay caps the time to wait before sending a request again.
+const maxRetryDelay = 32 * time.Second
+
+// retrySleep is overridden in tests.
+var retrySleep = time.Sleep
+
+// retryDelay returns how long to wait before sending the request again after a
+// 429 response, given the number of retries already made. The wait is the
+// Retry-After of the response, since the server knows best when to come back.
+// Without one, it starts at one second and doubles with each retry, so that
+// clients back off from a busy server. Returns false if the request body can
+// not be sent again.
+func (r *Request) retryDelay(retries int) (time.Duration, bool) {
+	if r.body != nil {
+		seeker, ok := r.body.(io.Seeker)
+		if !ok {
+			return 0, false
+		}
+		if _, err := seeker.Seek(0, 0); err != nil {
+			return 0, false
+		}
+	}
+	delay := time.Second
+	if seconds, err := strconv.Atoi(r.resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		delay = time.Duration(seconds) * time.Second
+	} else {
+		for i := 0; i < retries && delay < maxRetryDelay; i++ {
+			delay *= 2
+		}
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay, true
+}
+
 // DoRaw executes a raw request which is not subject to interpretation as an API response.
 func (r *Request) DoRaw() ([]byte, error) {
 	client := r.client
@@ -539,8 +625,6 @@ func (r *Request) DoRaw() ([]byte, error) {
 		client = http.DefaultClient
 	}
 
-	// Right now we make about ten retry attempts if we get a Retry-After response.
-	// TODO: Change to a timeout based approach.
 	retries := 0
 
 	for {
@@ -561,26 +645,25 @@ func (r *Request) DoRaw() ([]byte, error) {
 		if err != nil {
 			return nil, err
 		}
+		for key, values := range r.headers {
+			r.req.Header[key] = values
+		}
 		r.resp, err = client.Do(r.req)
 		if err != nil {
 			return nil, err
 		}
-		defer r.resp.Body.Close()
 
 		// Check to see if we got a 429 Too Many Requests response code.
-		if r.resp.StatusCode == errors.StatusTooManyRequests {
-			if retries < 10 {
+		if r.resp.StatusCode == errors.StatusTooManyRequests && retries < r.maxRetries {
+			if delay, ok := r.retryDelay(retries); ok {
 				retries++
-				if waitFor := r.resp.Header.Get("Retry-After"); waitFor != "" {
-					delay, err := strconv.Atoi(waitFor)
-					if err == nil {
-						glog.V(4).Infof("Got a Retry-After %s response for attempt %d to %v", waitFor, retries, r.finalURL())
-						time.Sleep(time.Duration(delay) * time.Second)
-						continue
-					}
-				}
+				r.resp.Body.Close()
+				glog.V(4).Infof("Got a 429 response for attempt %d to %v, retrying in %v", retries, r.finalURL(), delay)
+				retrySleep(delay)
+				continue
 			}
 		}
+		defer r.resp.Body.Close()
 		body, err := ioutil.ReadAll(r.resp.Body)
 		if err != nil {
 			return nil, err
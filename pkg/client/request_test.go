@@ -166,6 +166,37 @@ func TestRequestParam(t *testing.T) {
 	}
 }
 
+func TestRequestPodLogOptions(t *testing.T) {
+	sinceTime := util.Date(2015, 5, 1, 10, 0, 0, 0, time.UTC)
+	timestamps := false
+	opts := &api.PodLogOptions{Previous: true, SinceTime: &sinceTime, Timestamps: &timestamps, LimitBytes: 1024}
+	r := (&Request{}).PodLogOptions(opts)
+	expected := url.Values{
+		"previous":   []string{"true"},
+		"sinceTime":  []string{"2015-05-01T10:00:00Z"},
+		"timestamps": []string{"false"},
+		"limitBytes": []string{"1024"},
+	}
+	if !api.Semantic.DeepEqual(r.params, expected) {
+		t.Errorf("expected %#v, got %#v", expected, r.params)
+	}
+	parsed, err := api.ParsePodLogOptions(r.params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !api.Semantic.DeepEqual(opts, parsed) {
+		t.Errorf("expected %#v, got %#v", opts, parsed)
+	}
+	if parsed.ShowTimestamps() {
+		t.Errorf("expected timestamps to be turned off: %#v", parsed)
+	}
+	// Logs have timestamps unless asked otherwise.
+	defaults, err := api.ParsePodLogOptions(url.Values{})
+	if err != nil || !defaults.ShowTimestamps() {
+		t.Errorf("expected timestamps by default, got %#v: %v", defaults, err)
+	}
+}
+
 func TestRequestURI(t *testing.T) {
 	r := (&Request{}).Param("foo", "a")
 	r.Prefix("other")
@@ -617,7 +648,7 @@ func TestRequestDo(t *testing.T) {
 
 func TestDoRequestNewWay(t *testing.T) {
 	reqBody := "request body"
-	expectedObj := &api.Service{Spec: api.ServiceSpec{Port: 12345}}
+	expectedObj := &api.Service{Spec: api.ServiceSpec{Ports: []api.ServicePort{{Port: 12345}}}}
 	expectedBody, _ := v1beta2.Codec.Encode(expectedObj)
 	fakeHandler := util.FakeHandler{
 		StatusCode:   200,
@@ -651,7 +682,7 @@ func TestDoRequestNewWay(t *testing.T) {
 func TestDoRequestNewWayReader(t *testing.T) {
 	reqObj := &api.Pod{ObjectMeta: api.ObjectMeta{Name: "foo"}}
 	reqBodyExpected, _ := v1beta1.Codec.Encode(reqObj)
-	expectedObj := &api.Service{Spec: api.ServiceSpec{Port: 12345}}
+	expectedObj := &api.Service{Spec: api.ServiceSpec{Ports: []api.ServicePort{{Port: 12345}}}}
 	expectedBody, _ := v1beta1.Codec.Encode(expectedObj)
 	fakeHandler := util.FakeHandler{
 		StatusCode:   200,
@@ -687,7 +718,7 @@ func TestDoRequestNewWayReader(t *testing.T) {
 func TestDoRequestNewWayObj(t *testing.T) {
 	reqObj := &api.Pod{ObjectMeta: api.ObjectMeta{Name: "foo"}}
 	reqBodyExpected, _ := v1beta2.Codec.Encode(reqObj)
-	expectedObj := &api.Service{Spec: api.ServiceSpec{Port: 12345}}
+	expectedObj := &api.Service{Spec: api.ServiceSpec{Ports: []api.ServicePort{{Port: 12345}}}}
 	expectedBody, _ := v1beta2.Codec.Encode(expectedObj)
 	fakeHandler := util.FakeHandler{
 		StatusCode:   200,
@@ -737,7 +768,7 @@ func TestDoRequestNewWayFile(t *testing.T) {
 		t.Errorf("unexpected error: %v", err)
 	}
 
-	expectedObj := &api.Service{Spec: api.ServiceSpec{Port: 12345}}
+	expectedObj := &api.Service{Spec: api.ServiceSpec{Ports: []api.ServicePort{{Port: 12345}}}}
 	expectedBody, _ := v1beta1.Codec.Encode(expectedObj)
 	fakeHandler := util.FakeHandler{
 		StatusCode:   200,
@@ -778,7 +809,7 @@ func TestWasCreated(t *testing.T) {
 		t.Errorf("unexpected error: %v", err)
 	}
 
-	expectedObj := &api.Service{Spec: api.ServiceSpec{Port: 12345}}
+	expectedObj := &api.Service{Spec: api.ServiceSpec{Ports: []api.ServicePort{{Port: 12345}}}}
 	expectedBody, _ := v1beta1.Codec.Encode(expectedObj)
 	fakeHandler := util.FakeHandler{
 		StatusCode:   201,
@@ -908,6 +939,118 @@ func TestBody(t *testing.T) {
 	}
 }
 
+func TestDoRawRetriesTooManyRequests(t *testing.T) {
+	const data = "test payload"
+	attempts := 0
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		if string(body) != data {
+			t.Errorf("attempt %d: unexpected body %q", attempts, string(body))
+		}
+		attempts++
+		switch attempts {
+		case 1:
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(apierrors.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(apierrors.StatusTooManyRequests)
+		default:
+			w.Write([]byte("ok"))
+		}
+	}))
+	defer testServer.Close()
+
+	var delays []time.Duration
+	retrySleep = func(d time.Duration) { delays = append(delays, d) }
+	defer func() { retrySleep = time.Sleep }()
+
+	c := NewOrDie(&Config{Host: testServer.URL, Version: testapi.Version()})
+	body, err := c.Post().AbsPath("/").Body([]byte(data)).DoRaw()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(body) != "ok" {
+		t.Errorf("unexpected body %q", string(body))
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+	if e, a := []time.Duration{2 * time.Second, 2 * time.Second}, delays; !reflect.DeepEqual(e, a) {
+		t.Errorf("expected delays %v, got %v", e, a)
+	}
+}
+
+func TestDoRawGivesUpOnTooManyRequests(t *testing.T) {
+	attempts := 0
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		attempts++
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(apierrors.StatusTooManyRequests)
+	}))
+	defer testServer.Close()
+
+	var total time.Duration
+	retrySleep = func(d time.Duration) { total += d }
+	defer func() { retrySleep = time.Sleep }()
+
+	c := NewOrDie(&Config{Host: testServer.URL, Version: testapi.Version()})
+	if _, err := c.Get().AbsPath("/").DoRaw(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attempts != DefaultMaxRetries+1 {
+		t.Errorf("expected %d attempts, got %d", DefaultMaxRetries+1, attempts)
+	}
+	// The Retry-After is honored as is.
+	if e, a := DefaultMaxRetries*time.Second, total; e != a {
+		t.Errorf("expected to wait %v, waited %v", e, a)
+	}
+}
+
+func TestDoRawBacksOffWithoutRetryAfter(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(apierrors.StatusTooManyRequests)
+	}))
+	defer testServer.Close()
+
+	var delays []time.Duration
+	retrySleep = func(d time.Duration) { delays = append(delays, d) }
+	defer func() { retrySleep = time.Sleep }()
+
+	c := NewOrDie(&Config{Host: testServer.URL, Version: testapi.Version(), MaxRetries: 7})
+	if _, err := c.Get().AbsPath("/").DoRaw(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 1, 2, 4, 8 and 16 seconds, then capped at maxRetryDelay.
+	expected := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, maxRetryDelay, maxRetryDelay}
+	if !reflect.DeepEqual(expected, delays) {
+		t.Errorf("expected delays %v, got %v", expected, delays)
+	}
+}
+
+func TestDoRawMaxRetriesFromConfig(t *testing.T) {
+	attempts := 0
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		attempts++
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(apierrors.StatusTooManyRequests)
+	}))
+	defer testServer.Close()
+
+	retrySleep = func(time.Duration) {}
+	defer func() { retrySleep = time.Sleep }()
+
+	for maxRetries, expected := range map[int]int{2: 3, -1: 1} {
+		attempts = 0
+		c := NewOrDie(&Config{Host: testServer.URL, Version: testapi.Version(), MaxRetries: maxRetries})
+		if _, err := c.Get().AbsPath("/").DoRaw(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if attempts != expected {
+			t.Errorf("MaxRetries %d: expected %d attempts, got %d", maxRetries, expected, attempts)
+		}
+	}
+}
+
 func authFromReq(r *http.Request) (*Config, bool) {
 	auth, ok := r.Header["Authorization"]
 	if !ok {
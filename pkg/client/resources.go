@@ -0,0 +1,74 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+)
+
+// ResourcesNamespacer has methods to work with the objects of any resource,
+// for callers that learn about resources at runtime through ServerResources.
+type ResourcesNamespacer interface {
+	Resources(resource, namespace string) ResourceInterface
+}
+
+// ResourceInterface has methods to work with the objects of a resource whose
+// type is not known at compile time.
+type ResourceInterface interface {
+	List(label labels.Selector, field fields.Selector) (runtime.Object, error)
+	Delete(name string) error
+}
+
+// resources implements ResourceInterface
+type resources struct {
+	client    *Client
+	resource  string
+	namespace string
+}
+
+// newResources returns a new resources object.
+func newResources(c *Client, resource, namespace string) *resources {
+	return &resources{
+		client:    c,
+		resource:  resource,
+		namespace: namespace,
+	}
+}
+
+// List returns the list of objects of the resource matching the selectors.
+func (r *resources) List(label labels.Selector, field fields.Selector) (runtime.Object, error) {
+	return r.client.Get().
+		Namespace(r.namespace).
+		Resource(r.resource).
+		LabelsSelectorParam(api.LabelSelectorQueryParam(r.client.APIVersion()), label).
+		FieldsSelectorParam(api.FieldSelectorQueryParam(r.client.APIVersion()), field).
+		Do().
+		Get()
+}
+
+// Delete deletes the object of the resource with the given name.
+func (r *resources) Delete(name string) error {
+	return r.client.Delete().
+		Namespace(r.namespace).
+		Resource(r.resource).
+		Name(name).
+		Do().
+		Error()
+}
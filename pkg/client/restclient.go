@@ -21,6 +21,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 )
 
@@ -45,11 +46,19 @@ type RESTClient struct {
 	// REST resources.
 	Codec runtime.Codec
 
+	// ContentType, if set, is sent as the Content-Type of requests and preferred in
+	// their Accept header. Codec must encode objects in it.
+	ContentType string
+
 	// Set specific behavior of the client.  If not set http.DefaultClient will be
 	// used.
 	Client HTTPClient
 
 	Timeout time.Duration
+
+	// MaxRetries is the number of times requests are sent again after a 429 Too
+	// Many Requests response. NewRESTClient sets it to DefaultMaxRetries.
+	MaxRetries int
 }
 
 // NewRESTClient creates a new RESTClient. This client performs generic REST functions
@@ -71,6 +80,8 @@ func NewRESTClient(baseURL *url.URL, apiVersion string, c runtime.Codec, legacyB
 		Codec: c,
 
 		LegacyBehavior: legacyBehavior,
+
+		MaxRetries: DefaultMaxRetries,
 	}
 }
 
@@ -92,7 +103,12 @@ func (c *RESTClient) Verb(verb string) *Request {
 	// if c.Client != nil {
 	// 	timeout = c.Client.Timeout
 	// }
-	return NewRequest(c.Client, verb, c.baseURL, c.apiVersion, c.Codec, c.LegacyBehavior, c.LegacyBehavior).Timeout(c.Timeout)
+	request := NewRequest(c.Client, verb, c.baseURL, c.apiVersion, c.Codec, c.LegacyBehavior, c.LegacyBehavior).Timeout(c.Timeout).MaxRetries(c.MaxRetries)
+	if len(c.ContentType) != 0 {
+		// Responses the server only writes as JSON, like watch events, are still accepted.
+		request.SetHeader("Accept", c.ContentType+", */*").SetHeader("Content-Type", c.ContentType)
+	}
+	return request
 }
 
 // Post begins a POST request. Short for c.Verb("POST").
@@ -105,9 +121,10 @@ func (c *RESTClient) Put() *Request {
 	return c.Verb("PUT")
 }
 
-// Patch begins a PATCH request. Short for c.Verb("Patch").
-func (c *RESTClient) Patch() *Request {
-	return c.Verb("PATCH")
+// Patch begins a PATCH request whose body is a patch of the given type. Short for
+// c.Verb("PATCH").SetHeader("Content-Type", string(pt)).
+func (c *RESTClient) Patch(pt api.PatchType) *Request {
+	return c.Verb("PATCH").SetHeader("Content-Type", string(pt))
 }
 
 // Get begins a GET request. Short for c.Verb("GET").
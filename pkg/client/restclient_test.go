@@ -144,6 +144,38 @@ func TestDoRequestBearer(t *testing.T) {
 	}
 }
 
+func TestDoRequestPatch(t *testing.T) {
+	expectedBody, _ := latest.Codec.Encode(&api.Pod{})
+	fakeHandler := util.FakeHandler{
+		StatusCode:   200,
+		ResponseBody: string(expectedBody),
+		T:            t,
+	}
+	testServer := httptest.NewServer(&fakeHandler)
+	defer testServer.Close()
+	c, err := RESTClientFor(&Config{
+		Host:    testServer.URL,
+		Version: testapi.Version(),
+		Codec:   testapi.Codec(),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	patch := `{"metadata":{"labels":{"foo":"bar"}}}`
+	if err := c.Patch(api.StrategicMergePatchType).Resource("pods").Name("foo").Body([]byte(patch)).Do().Error(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fakeHandler.RequestReceived.Method != "PATCH" {
+		t.Errorf("unexpected method: %s", fakeHandler.RequestReceived.Method)
+	}
+	if contentType := fakeHandler.RequestReceived.Header.Get("Content-Type"); contentType != string(api.StrategicMergePatchType) {
+		t.Errorf("unexpected content type: %s", contentType)
+	}
+	if fakeHandler.RequestBody != patch {
+		t.Errorf("unexpected body: %s", fakeHandler.RequestBody)
+	}
+}
+
 func TestDoRequestWithoutPassword(t *testing.T) {
 	status := &api.Status{Status: api.StatusFailure}
 	expectedBody, _ := latest.Codec.Encode(status)
@@ -296,3 +328,52 @@ func TestDoRequestCreated(t *testing.T) {
 	}
 	fakeHandler.ValidateRequest(t, "/"+testapi.Version()+"/test", "GET", nil)
 }
+
+func TestDoRequestProtobuf(t *testing.T) {
+	pod := &api.Pod{ObjectMeta: api.ObjectMeta{Name: "foo"}}
+	expectedBody, _ := v1beta3.ProtobufCodec.Encode(pod)
+	fakeHandler := util.FakeHandler{
+		StatusCode:   201,
+		ResponseBody: string(expectedBody),
+		T:            t,
+	}
+	testServer := httptest.NewServer(&fakeHandler)
+	defer testServer.Close()
+	config := &Config{
+		Host:        testServer.URL,
+		Version:     "v1beta3",
+		ContentType: runtime.ProtobufContentType,
+	}
+	if err := SetKubernetesDefaults(config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Codec != v1beta3.ProtobufCodec {
+		t.Errorf("expected the v1beta3 protobuf codec, got %#v", config.Codec)
+	}
+	c, err := RESTClientFor(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	obj, err := c.Post().Resource("pods").Body(pod).Do().Get()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out, ok := obj.(*api.Pod); !ok || out.Name != "foo" {
+		t.Errorf("unexpected object: %#v", obj)
+	}
+	req := fakeHandler.RequestReceived
+	if e, a := "application/x-protobuf, */*", req.Header.Get("Accept"); e != a {
+		t.Errorf("expected Accept %q, got %q", e, a)
+	}
+	if e, a := runtime.ProtobufContentType, req.Header.Get("Content-Type"); e != a {
+		t.Errorf("expected Content-Type %q, got %q", e, a)
+	}
+	if !runtime.IsProtobuf([]byte(fakeHandler.RequestBody)) {
+		t.Errorf("expected a protobuf body, got %q", fakeHandler.RequestBody)
+	}
+
+	config = &Config{Host: testServer.URL, Version: "v1beta1", ContentType: runtime.ProtobufContentType}
+	if err := SetKubernetesDefaults(config); err == nil {
+		t.Errorf("expected an error for a version without protobuf")
+	}
+}
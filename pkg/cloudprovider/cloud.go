@@ -73,6 +73,22 @@ type Zone struct {
 	Region        string
 }
 
+// Labels recording the Zone of a node. They are set by the kubelet when its
+// cloud provider supports Zones.
+const (
+	LabelZoneFailureDomain = "failure-domain.kubernetes.io/zone"
+	LabelZoneRegion        = "failure-domain.kubernetes.io/region"
+)
+
+// GetNodeZone returns the Zone recorded in the labels of node. Nodes without
+// zone labels all belong to the empty Zone.
+func GetNodeZone(node *api.Node) Zone {
+	return Zone{
+		FailureDomain: node.Labels[LabelZoneFailureDomain],
+		Region:        node.Labels[LabelZoneRegion],
+	}
+}
+
 // Zones is an abstract, pluggable interface for zone enumeration.
 type Zones interface {
 	// GetZone returns the Zone containing the current failure zone and locality region that the program is running in
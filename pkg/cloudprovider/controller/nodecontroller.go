@@ -77,6 +77,12 @@ type NodeController struct {
 	recorder           record.EventRecorder
 	registerRetryCount int
 	podEvictionTimeout time.Duration
+	// podEvictor rate limits the eviction of pods from unready nodes.
+	podEvictor *podEvictor
+	// unhealthyZoneThreshold is the fraction of unready nodes above which a
+	// zone is assumed to be partitioned from the master, rather than to have
+	// failing nodes, and pod eviction in it stops.
+	unhealthyZoneThreshold float32
 	// Method for easy mocking in unittest.
 	lookupIP func(host string) ([]net.IP, error)
 	now      func() util.Time
@@ -92,19 +98,23 @@ func NewNodeController(
 	kubeletClient client.KubeletClient,
 	recorder record.EventRecorder,
 	registerRetryCount int,
-	podEvictionTimeout time.Duration) *NodeController {
+	podEvictionTimeout time.Duration,
+	deletingPodsRateLimiter util.RateLimiter,
+	unhealthyZoneThreshold float32) *NodeController {
 	return &NodeController{
-		cloud:              cloud,
-		matchRE:            matchRE,
-		nodes:              nodes,
-		staticResources:    staticResources,
-		kubeClient:         kubeClient,
-		kubeletClient:      kubeletClient,
-		recorder:           recorder,
-		registerRetryCount: registerRetryCount,
-		podEvictionTimeout: podEvictionTimeout,
-		lookupIP:           net.LookupIP,
-		now:                util.Now,
+		cloud:                  cloud,
+		matchRE:                matchRE,
+		nodes:                  nodes,
+		staticResources:        staticResources,
+		kubeClient:             kubeClient,
+		kubeletClient:          kubeletClient,
+		recorder:               recorder,
+		registerRetryCount:     registerRetryCount,
+		podEvictionTimeout:     podEvictionTimeout,
+		podEvictor:             newPodEvictor(deletingPodsRateLimiter),
+		unhealthyZoneThreshold: unhealthyZoneThreshold,
+		lookupIP:               net.LookupIP,
+		now:                    util.Now,
 	}
 }
 
@@ -333,6 +343,13 @@ func (nc *NodeController) DoCheck(node *api.Node) []api.NodeCondition {
 	nc.updateLastTransitionTime(oldSchedulableCondition, newSchedulableCondition)
 	conditions = append(conditions, *newSchedulableCondition)
 
+	// Keep the resource pressure conditions, which are reported by Kubelet itself.
+	for _, conditionType := range []api.NodeConditionType{api.NodeMemoryPressure, api.NodeDiskPressure} {
+		if condition := nc.getCondition(node, conditionType); condition != nil {
+			conditions = append(conditions, *condition)
+		}
+	}
+
 	return conditions
 }
 
@@ -436,16 +453,22 @@ func (nc *NodeController) PopulateAddresses(nodes *api.NodeList) (*api.NodeList,
 
 // MonitorNodeStatus verifies node status are constantly updated by kubelet, and if not,
 // post "NodeReady==ConditionUnknown". It also evicts all pods if node is not ready or
-// not reachable for a long period of time.
+// not reachable for a long period of time, at a limited rate and only while most nodes
+// in the node's zone are still ready.
 func (nc *NodeController) MonitorNodeStatus() error {
 	nodes, err := nc.kubeClient.Nodes().List()
 	if err != nil {
 		return err
 	}
+	zoneNodes := map[cloudprovider.Zone]int{}
+	zoneUnready := map[cloudprovider.Zone]int{}
+	toEvict := []*api.Node{}
+	nodeNames := util.StringSet{}
 	for i := range nodes.Items {
 		var gracePeriod time.Duration
 		var lastReadyCondition api.NodeCondition
 		node := &nodes.Items[i]
+		nodeNames.Insert(node.Name)
 		readyCondition := nc.getCondition(node, api.NodeReady)
 		if readyCondition == nil {
 			// If ready condition is nil, then kubelet (or nodecontroller) never posted node status.
@@ -494,24 +517,89 @@ func (nc *NodeController) MonitorNodeStatus() error {
 			}
 		}
 
+		zone := cloudprovider.GetNodeZone(node)
+		zoneNodes[zone]++
+		if currentReadyCondition := nc.getCondition(node, api.NodeReady); currentReadyCondition != nil {
+			if currentReadyCondition.Status == api.ConditionTrue {
+				// The node is back, stop any pending eviction.
+				if nc.podEvictor.Remove(node.Name) {
+					glog.V(2).Infof("node %v is ready again, cancelling pod eviction", node.Name)
+				}
+			} else {
+				zoneUnready[zone]++
+			}
+		}
+
 		if readyCondition != nil {
 			// Check eviction timeout.
 			if lastReadyCondition.Status == api.ConditionFalse &&
 				nc.now().After(lastReadyCondition.LastTransitionTime.Add(nc.podEvictionTimeout)) {
 				// Node stays in not ready for at least 'podEvictionTimeout' - evict all pods on the unhealthy node.
-				nc.deletePods(node.Name)
+				toEvict = append(toEvict, node)
 			}
 			if lastReadyCondition.Status == api.ConditionUnknown &&
 				nc.now().After(lastReadyCondition.LastProbeTime.Add(nc.podEvictionTimeout-gracePeriod)) {
 				// Same as above. Note however, since condition unknown is posted by node controller, which means we
 				// need to substract monitoring grace period in order to get the real 'podEvictionTimeout'.
-				nc.deletePods(node.Name)
+				toEvict = append(toEvict, node)
+			}
+		}
+	}
+
+	// Nodes deleted from the cluster have nothing left to evict.
+	nc.podEvictor.Retain(nodeNames)
+
+	evicted := []string{}
+	for _, node := range toEvict {
+		zone := cloudprovider.GetNodeZone(node)
+		if nc.zoneDisrupted(zoneNodes[zone], zoneUnready[zone]) {
+			// Too many nodes in the zone went away at once: this looks like a network
+			// partition between them and the master, so leave their pods alone.
+			glog.V(2).Infof("not evicting pods from node %v: %d of %d nodes in zone %+v are not ready",
+				node.Name, zoneUnready[zone], zoneNodes[zone], zone)
+			nc.podEvictor.Remove(node.Name)
+			continue
+		}
+		if nc.podEvictor.Add(node.Name) {
+			glog.V(2).Infof("node %v queued for pod eviction", node.Name)
+		} else if nc.podEvictor.Evicted(node.Name) {
+			evicted = append(evicted, node.Name)
+		}
+	}
+	if len(evicted) > 0 {
+		// Evict again the pods that are still on nodes whose pods were already
+		// evicted, such as pods bound to them since.
+		hosts, err := nc.nodesWithPods()
+		if err != nil {
+			glog.Errorf("Error listing pods of evicted nodes: %v", err)
+		} else {
+			for _, nodeName := range evicted {
+				if hosts.Has(nodeName) && nc.podEvictor.Requeue(nodeName) {
+					glog.V(2).Infof("node %v still has pods, queued for pod eviction again", nodeName)
+				}
 			}
 		}
 	}
+	nc.podEvictor.Try(func(nodeName string) bool {
+		if err := nc.deletePods(nodeName); err != nil {
+			glog.Errorf("Error evicting pods from node %v: %v", nodeName, err)
+			return false
+		}
+		return true
+	})
 	return nil
 }
 
+// zoneDisrupted returns true if so many of a zone's nodes are unready that the
+// zone is probably partitioned from the master. A single unready node is never
+// considered a disruption.
+func (nc *NodeController) zoneDisrupted(nodes, unready int) bool {
+	if nc.unhealthyZoneThreshold <= 0 || unready <= 1 {
+		return false
+	}
+	return float32(unready) >= nc.unhealthyZoneThreshold*float32(nodes)
+}
+
 // GetStaticNodesWithSpec constructs and returns api.NodeList for static nodes. If error
 // occurs, an empty NodeList will be returned with a non-nil error info. The method only
 // constructs spec fields for nodes.
@@ -566,7 +654,23 @@ func (nc *NodeController) GetCloudNodesWithSpec() (*api.NodeList, error) {
 	return result, nil
 }
 
-// deletePods will delete all pods from master running on given node.
+// nodesWithPods returns the names of the nodes that pods are bound to.
+func (nc *NodeController) nodesWithPods() (util.StringSet, error) {
+	pods, err := nc.kubeClient.Pods(api.NamespaceAll).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	hosts := util.StringSet{}
+	for i := range pods.Items {
+		if host := pods.Items[i].Status.Host; host != "" {
+			hosts.Insert(host)
+		}
+	}
+	return hosts, nil
+}
+
+// deletePods will delete all pods from master running on given node, and
+// record an event for each of them.
 func (nc *NodeController) deletePods(nodeID string) error {
 	glog.V(2).Infof("Delete all pods from %v", nodeID)
 	// TODO: We don't yet have field selectors from client, see issue #1362.
@@ -574,17 +678,21 @@ func (nc *NodeController) deletePods(nodeID string) error {
 	if err != nil {
 		return err
 	}
-	for _, pod := range pods.Items {
+	var lastErr error
+	for i := range pods.Items {
+		pod := &pods.Items[i]
 		if pod.Status.Host != nodeID {
 			continue
 		}
 		glog.V(2).Infof("Delete pod %v", pod.Name)
-		if err := nc.kubeClient.Pods(pod.Namespace).Delete(pod.Name); err != nil {
+		nc.recorder.Eventf(pod, "nodeControllerEviction", "Deleting pod %s from node %s, which is not ready", pod.Name, nodeID)
+		if err := nc.kubeClient.Pods(pod.Namespace).Delete(pod.Name, api.NewDeleteOptions(0)); err != nil {
 			glog.Errorf("Error deleting pod %v: %v", pod.Name, err)
+			lastErr = err
 		}
 	}
 
-	return nil
+	return lastErr
 }
 
 // isRunningCloudProvider checks if cluster is running with cloud provider.
@@ -23,6 +23,7 @@ import (
 	"net/http"
 	"reflect"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 
@@ -30,6 +31,8 @@ import (
 	apierrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/record"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/cloudprovider"
 	fake_cloud "github.com/GoogleCloudPlatform/kubernetes/pkg/cloudprovider/fake"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
@@ -247,7 +250,7 @@ func TestRegisterNodes(t *testing.T) {
 		for _, machine := range item.machines {
 			nodes.Items = append(nodes.Items, *newNode(machine))
 		}
-		nodeController := NewNodeController(nil, "", item.machines, &api.NodeResources{}, item.fakeNodeHandler, nil, nil, 10, time.Minute)
+		nodeController := NewNodeController(nil, "", item.machines, &api.NodeResources{}, item.fakeNodeHandler, nil, &record.FakeRecorder{}, 10, time.Minute, util.NewFakeRateLimiter(), 0.55)
 		err := nodeController.RegisterNodes(&nodes, item.retryCount, time.Millisecond)
 		if !item.expectedFail && err != nil {
 			t.Errorf("unexpected error: %v", err)
@@ -329,7 +332,7 @@ func TestCreateGetStaticNodesWithSpec(t *testing.T) {
 		},
 	}
 	for _, item := range table {
-		nodeController := NewNodeController(nil, "", item.machines, &resources, nil, nil, nil, 10, time.Minute)
+		nodeController := NewNodeController(nil, "", item.machines, &resources, nil, nil, &record.FakeRecorder{}, 10, time.Minute, util.NewFakeRateLimiter(), 0.55)
 		nodes, err := nodeController.GetStaticNodesWithSpec()
 		if err != nil {
 			t.Errorf("unexpected error: %v", err)
@@ -390,7 +393,7 @@ func TestCreateGetCloudNodesWithSpec(t *testing.T) {
 	}
 
 	for _, item := range table {
-		nodeController := NewNodeController(item.fakeCloud, ".*", nil, &api.NodeResources{}, nil, nil, nil, 10, time.Minute)
+		nodeController := NewNodeController(item.fakeCloud, ".*", nil, &api.NodeResources{}, nil, nil, &record.FakeRecorder{}, 10, time.Minute, util.NewFakeRateLimiter(), 0.55)
 		nodes, err := nodeController.GetCloudNodesWithSpec()
 		if err != nil {
 			t.Errorf("unexpected error: %v", err)
@@ -487,7 +490,7 @@ func TestSyncCloudNodes(t *testing.T) {
 	}
 
 	for _, item := range table {
-		nodeController := NewNodeController(item.fakeCloud, item.matchRE, nil, &api.NodeResources{}, item.fakeNodeHandler, nil, nil, 10, time.Minute)
+		nodeController := NewNodeController(item.fakeCloud, item.matchRE, nil, &api.NodeResources{}, item.fakeNodeHandler, nil, &record.FakeRecorder{}, 10, time.Minute, util.NewFakeRateLimiter(), 0.55)
 		if err := nodeController.SyncCloudNodes(); err != nil {
 			t.Errorf("unexpected error: %v", err)
 		}
@@ -569,7 +572,7 @@ func TestSyncCloudNodesEvictPods(t *testing.T) {
 	}
 
 	for _, item := range table {
-		nodeController := NewNodeController(item.fakeCloud, item.matchRE, nil, &api.NodeResources{}, item.fakeNodeHandler, nil, nil, 10, time.Minute)
+		nodeController := NewNodeController(item.fakeCloud, item.matchRE, nil, &api.NodeResources{}, item.fakeNodeHandler, nil, &record.FakeRecorder{}, 10, time.Minute, util.NewFakeRateLimiter(), 0.55)
 		if err := nodeController.SyncCloudNodes(); err != nil {
 			t.Errorf("unexpected error: %v", err)
 		}
@@ -668,10 +671,55 @@ func TestNodeConditionsCheck(t *testing.T) {
 				},
 			},
 		},
+		{
+			// Kubelet reported memory pressure and kubelet /healthz probe returns success.
+			// Expected memory pressure condition to be kept as is.
+			node: &api.Node{
+				ObjectMeta: api.ObjectMeta{Name: "node0"},
+				Status: api.NodeStatus{
+					Conditions: []api.NodeCondition{
+						{
+							Type:               api.NodeMemoryPressure,
+							Status:             api.ConditionTrue,
+							Reason:             "kubelet is evicting pods",
+							LastProbeTime:      fakeNow,
+							LastTransitionTime: util.Date(2015, 1, 1, 11, 0, 0, 0, time.UTC),
+						},
+					},
+				},
+			},
+			fakeKubeletClient: &FakeKubeletClient{
+				Status: probe.Success,
+				Err:    nil,
+			},
+			expectedConditions: []api.NodeCondition{
+				{
+					Type:               api.NodeReady,
+					Status:             api.ConditionTrue,
+					Reason:             "Node health check succeeded: kubelet /healthz endpoint returns ok",
+					LastProbeTime:      fakeNow,
+					LastTransitionTime: fakeNow,
+				},
+				{
+					Type:               api.NodeSchedulable,
+					Status:             api.ConditionTrue,
+					Reason:             "Node is schedulable by default",
+					LastProbeTime:      fakeNow,
+					LastTransitionTime: fakeNow,
+				},
+				{
+					Type:               api.NodeMemoryPressure,
+					Status:             api.ConditionTrue,
+					Reason:             "kubelet is evicting pods",
+					LastProbeTime:      fakeNow,
+					LastTransitionTime: util.Date(2015, 1, 1, 11, 0, 0, 0, time.UTC),
+				},
+			},
+		},
 	}
 
 	for _, item := range table {
-		nodeController := NewNodeController(nil, "", nil, nil, nil, item.fakeKubeletClient, nil, 10, time.Minute)
+		nodeController := NewNodeController(nil, "", nil, nil, nil, item.fakeKubeletClient, &record.FakeRecorder{}, 10, time.Minute, util.NewFakeRateLimiter(), 0.55)
 		nodeController.now = func() util.Time { return fakeNow }
 		conditions := nodeController.DoCheck(item.node)
 		if !reflect.DeepEqual(item.expectedConditions, conditions) {
@@ -702,7 +750,7 @@ func TestPopulateNodeAddresses(t *testing.T) {
 	}
 
 	for _, item := range table {
-		nodeController := NewNodeController(item.fakeCloud, ".*", nil, nil, nil, nil, nil, 10, time.Minute)
+		nodeController := NewNodeController(item.fakeCloud, ".*", nil, nil, nil, nil, &record.FakeRecorder{}, 10, time.Minute, util.NewFakeRateLimiter(), 0.55)
 		result, err := nodeController.PopulateAddresses(item.nodes)
 		// In case of IP querying error, we should continue.
 		if err != nil {
@@ -805,7 +853,7 @@ func TestSyncProbedNodeStatus(t *testing.T) {
 	}
 
 	for _, item := range table {
-		nodeController := NewNodeController(item.fakeCloud, ".*", nil, nil, item.fakeNodeHandler, item.fakeKubeletClient, nil, 10, time.Minute)
+		nodeController := NewNodeController(item.fakeCloud, ".*", nil, nil, item.fakeNodeHandler, item.fakeKubeletClient, &record.FakeRecorder{}, 10, time.Minute, util.NewFakeRateLimiter(), 0.55)
 		nodeController.now = func() util.Time { return fakeNow }
 		if err := nodeController.SyncProbedNodeStatus(); err != nil {
 			t.Errorf("unexpected error: %v", err)
@@ -908,7 +956,7 @@ func TestSyncProbedNodeStatusTransitionTime(t *testing.T) {
 	}
 
 	for _, item := range table {
-		nodeController := NewNodeController(nil, "", []string{"node0"}, nil, item.fakeNodeHandler, item.fakeKubeletClient, nil, 10, time.Minute)
+		nodeController := NewNodeController(nil, "", []string{"node0"}, nil, item.fakeNodeHandler, item.fakeKubeletClient, &record.FakeRecorder{}, 10, time.Minute, util.NewFakeRateLimiter(), 0.55)
 		nodeController.lookupIP = func(host string) ([]net.IP, error) { return nil, fmt.Errorf("lookup %v: no such host", host) }
 		nodeController.now = func() util.Time { return fakeNow }
 		if err := nodeController.SyncProbedNodeStatus(); err != nil {
@@ -1061,7 +1109,7 @@ func TestSyncProbedNodeStatusEvictPods(t *testing.T) {
 	}
 
 	for _, item := range table {
-		nodeController := NewNodeController(nil, "", []string{"node0"}, nil, item.fakeNodeHandler, item.fakeKubeletClient, nil, 10, 5*time.Minute)
+		nodeController := NewNodeController(nil, "", []string{"node0"}, nil, item.fakeNodeHandler, item.fakeKubeletClient, &record.FakeRecorder{}, 10, 5*time.Minute, util.NewFakeRateLimiter(), 0.55)
 		nodeController.lookupIP = func(host string) ([]net.IP, error) { return nil, fmt.Errorf("lookup %v: no such host", host) }
 		if err := nodeController.SyncProbedNodeStatus(); err != nil {
 			t.Errorf("unexpected error: %v", err)
@@ -1219,7 +1267,7 @@ func TestMonitorNodeStatusEvictPods(t *testing.T) {
 	}
 
 	for _, item := range table {
-		nodeController := NewNodeController(nil, "", []string{"node0"}, nil, item.fakeNodeHandler, nil, nil, 10, item.evictionTimeout)
+		nodeController := NewNodeController(nil, "", []string{"node0"}, nil, item.fakeNodeHandler, nil, &record.FakeRecorder{}, 10, item.evictionTimeout, util.NewFakeRateLimiter(), 0.55)
 		nodeController.now = func() util.Time { return fakeNow }
 		if err := nodeController.MonitorNodeStatus(); err != nil {
 			t.Errorf("unexpected error: %v", err)
@@ -1236,6 +1284,149 @@ func TestMonitorNodeStatusEvictPods(t *testing.T) {
 	}
 }
 
+func TestMonitorNodeStatusEvictPodsZoneDisruption(t *testing.T) {
+	fakeNow := util.Date(2015, 1, 1, 12, 0, 0, 0, time.UTC)
+	zoneNode := func(name, zone string, status api.ConditionStatus) *api.Node {
+		return &api.Node{
+			ObjectMeta: api.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: util.Date(2012, 1, 1, 0, 0, 0, 0, time.UTC),
+				Labels: map[string]string{
+					cloudprovider.LabelZoneRegion:        "region1",
+					cloudprovider.LabelZoneFailureDomain: zone,
+				},
+			},
+			Status: api.NodeStatus{
+				Conditions: []api.NodeCondition{
+					{
+						Type:   api.NodeReady,
+						Status: status,
+						// Node status has just been updated, and transited to its status 1hr ago.
+						LastProbeTime:      fakeNow,
+						LastTransitionTime: util.Date(2015, 1, 1, 11, 0, 0, 0, time.UTC),
+					},
+				},
+			},
+		}
+	}
+	table := []struct {
+		nodes           []*api.Node
+		expectedEvicted util.StringSet
+	}{
+		// One of three nodes in the zone is not ready: evict its pods.
+		{
+			nodes: []*api.Node{
+				zoneNode("node0", "zone1", api.ConditionFalse),
+				zoneNode("node1", "zone1", api.ConditionTrue),
+				zoneNode("node2", "zone1", api.ConditionTrue),
+			},
+			expectedEvicted: util.NewStringSet("node0"),
+		},
+		// Most nodes in zone1 are not ready: the zone is disrupted and nothing is
+		// evicted there, while the failed node in the healthy zone2 is evicted.
+		{
+			nodes: []*api.Node{
+				zoneNode("node0", "zone1", api.ConditionFalse),
+				zoneNode("node1", "zone1", api.ConditionFalse),
+				zoneNode("node2", "zone1", api.ConditionTrue),
+				zoneNode("node3", "zone2", api.ConditionFalse),
+				zoneNode("node4", "zone2", api.ConditionTrue),
+				zoneNode("node5", "zone2", api.ConditionTrue),
+			},
+			expectedEvicted: util.NewStringSet("node3"),
+		},
+	}
+
+	for i, item := range table {
+		pods := []api.Pod{}
+		for _, node := range item.nodes {
+			pods = append(pods, *newPod("pod-"+node.Name, node.Name))
+		}
+		fakeNodeHandler := &FakeNodeHandler{
+			Existing: item.nodes,
+			Fake:     client.Fake{PodsList: api.PodList{Items: pods}},
+		}
+		nodeController := NewNodeController(nil, "", nil, nil, fakeNodeHandler, nil, &record.FakeRecorder{}, 10, 30*time.Minute, util.NewFakeRateLimiter(), 0.55)
+		nodeController.now = func() util.Time { return fakeNow }
+		if err := nodeController.MonitorNodeStatus(); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		evicted := util.StringSet{}
+		for _, action := range fakeNodeHandler.Actions {
+			if action.Action == "delete-pod" {
+				evicted.Insert(strings.TrimPrefix(action.Value.(string), "pod-"))
+			}
+		}
+		if !reflect.DeepEqual(item.expectedEvicted, evicted) {
+			t.Errorf("%d: expected pods evicted from %v, got %v", i, item.expectedEvicted.List(), evicted.List())
+		}
+	}
+}
+
+func TestMonitorNodeStatusEvictsPodsBoundAfterEviction(t *testing.T) {
+	fakeNow := util.Date(2015, 1, 1, 12, 0, 0, 0, time.UTC)
+	fakeNodeHandler := &FakeNodeHandler{
+		Existing: []*api.Node{
+			{
+				ObjectMeta: api.ObjectMeta{
+					Name:              "node0",
+					CreationTimestamp: util.Date(2012, 1, 1, 0, 0, 0, 0, time.UTC),
+				},
+				Status: api.NodeStatus{
+					Conditions: []api.NodeCondition{
+						{
+							Type:               api.NodeReady,
+							Status:             api.ConditionFalse,
+							LastProbeTime:      fakeNow,
+							LastTransitionTime: util.Date(2015, 1, 1, 11, 0, 0, 0, time.UTC),
+						},
+					},
+				},
+			},
+		},
+		Fake: client.Fake{
+			PodsList: api.PodList{Items: []api.Pod{*newPod("pod0", "node0")}},
+		},
+	}
+	nodeController := NewNodeController(nil, "", nil, nil, fakeNodeHandler, nil, &record.FakeRecorder{}, 10, 30*time.Minute, util.NewFakeRateLimiter(), 0.55)
+	nodeController.now = func() util.Time { return fakeNow }
+	deleted := func() []string {
+		names := []string{}
+		for _, action := range fakeNodeHandler.Actions {
+			if action.Action == "delete-pod" {
+				names = append(names, action.Value.(string))
+			}
+		}
+		fakeNodeHandler.Actions = nil
+		return names
+	}
+
+	if err := nodeController.MonitorNodeStatus(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if names := deleted(); !reflect.DeepEqual(names, []string{"pod0"}) {
+		t.Errorf("expected pod0 to be evicted, got %v", names)
+	}
+
+	// Nothing is left on the node.
+	fakeNodeHandler.PodsList = api.PodList{}
+	if err := nodeController.MonitorNodeStatus(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if names := deleted(); len(names) != 0 {
+		t.Errorf("expected no eviction, got %v", names)
+	}
+
+	// A pod is bound to the node while it is still not ready.
+	fakeNodeHandler.PodsList = api.PodList{Items: []api.Pod{*newPod("pod1", "node0")}}
+	if err := nodeController.MonitorNodeStatus(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if names := deleted(); !reflect.DeepEqual(names, []string{"pod1"}) {
+		t.Errorf("expected pod1 to be evicted, got %v", names)
+	}
+}
+
 func TestMonitorNodeStatusUpdateStatus(t *testing.T) {
 	fakeNow := util.Date(2015, 1, 1, 12, 0, 0, 0, time.UTC)
 	table := []struct {
@@ -1401,7 +1592,7 @@ func TestMonitorNodeStatusUpdateStatus(t *testing.T) {
 	}
 
 	for _, item := range table {
-		nodeController := NewNodeController(nil, "", []string{"node0"}, nil, item.fakeNodeHandler, nil, nil, 10, 5*time.Minute)
+		nodeController := NewNodeController(nil, "", []string{"node0"}, nil, item.fakeNodeHandler, nil, &record.FakeRecorder{}, 10, 5*time.Minute, util.NewFakeRateLimiter(), 0.55)
 		nodeController.now = func() util.Time { return fakeNow }
 		if err := nodeController.MonitorNodeStatus(); err != nil {
 			t.Errorf("unexpected error: %v", err)
@@ -0,0 +1,165 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+)
+
+// podEvictor queues the nodes whose pods should be evicted, in the order in
+// which they were found unready, and lets them through at the rate allowed by
+// its rate limiter. A node is queued once until it is removed from the evictor,
+// which happens when it becomes ready again, unless it is requeued because pods
+// are still found on it.
+type podEvictor struct {
+	lock    sync.Mutex
+	queue   []string
+	queued  util.StringSet
+	evicted util.StringSet
+	limiter util.RateLimiter
+}
+
+func newPodEvictor(limiter util.RateLimiter) *podEvictor {
+	return &podEvictor{
+		queued:  util.StringSet{},
+		evicted: util.StringSet{},
+		limiter: limiter,
+	}
+}
+
+// Add queues node for eviction, unless it is already queued or its pods have
+// already been evicted. It returns true if the node was queued.
+func (e *podEvictor) Add(node string) bool {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if e.queued.Has(node) || e.evicted.Has(node) {
+		return false
+	}
+	e.queued.Insert(node)
+	e.queue = append(e.queue, node)
+	return true
+}
+
+// Requeue queues node for eviction again if its pods were already evicted,
+// for pods that were left on it or bound to it since. It returns true if the
+// node was queued.
+func (e *podEvictor) Requeue(node string) bool {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if !e.evicted.Has(node) {
+		return false
+	}
+	e.evicted.Delete(node)
+	e.queued.Insert(node)
+	e.queue = append(e.queue, node)
+	return true
+}
+
+// Evicted returns true if the pods of node were evicted and the node has not
+// been removed or requeued since.
+func (e *podEvictor) Evicted(node string) bool {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return e.evicted.Has(node)
+}
+
+// Retain forgets every node that is not in nodes, such as nodes that were
+// deleted from the cluster.
+func (e *podEvictor) Retain(nodes util.StringSet) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	for _, node := range e.evicted.List() {
+		if !nodes.Has(node) {
+			e.evicted.Delete(node)
+		}
+	}
+	queue := []string{}
+	for _, node := range e.queue {
+		if nodes.Has(node) {
+			queue = append(queue, node)
+		} else {
+			e.queued.Delete(node)
+		}
+	}
+	e.queue = queue
+}
+
+// Remove cancels any pending eviction of node and forgets that its pods were
+// evicted. It returns true if an eviction was pending.
+func (e *podEvictor) Remove(node string) bool {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.evicted.Delete(node)
+	if !e.queued.Has(node) {
+		return false
+	}
+	e.queued.Delete(node)
+	for i := range e.queue {
+		if e.queue[i] == node {
+			e.queue = append(e.queue[:i], e.queue[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// Try calls evict for queued nodes, oldest first, for as long as the rate
+// limiter allows. If evict returns false the node stays queued and is retried
+// by a later call.
+func (e *podEvictor) Try(evict func(node string) bool) {
+	failed := []string{}
+	for {
+		node, ok := e.next()
+		if !ok {
+			break
+		}
+		if !evict(node) {
+			failed = append(failed, node)
+			continue
+		}
+		e.lock.Lock()
+		if e.queued.Has(node) {
+			e.queued.Delete(node)
+			e.evicted.Insert(node)
+		}
+		e.lock.Unlock()
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	retry := []string{}
+	for _, node := range failed {
+		if e.queued.Has(node) {
+			retry = append(retry, node)
+		}
+	}
+	e.queue = append(retry, e.queue...)
+}
+
+// next pops the oldest queued node if the rate limiter accepts it.
+func (e *podEvictor) next() (string, bool) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if len(e.queue) == 0 || !e.limiter.CanAccept() {
+		return "", false
+	}
+	node := e.queue[0]
+	e.queue = e.queue[1:]
+	return node, true
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+)
+
+// countingRateLimiter accepts the first 'allowed' calls to CanAccept.
+type countingRateLimiter struct {
+	allowed int
+}
+
+func (c *countingRateLimiter) CanAccept() bool {
+	if c.allowed <= 0 {
+		return false
+	}
+	c.allowed--
+	return true
+}
+
+func (c *countingRateLimiter) Stop() {}
+
+func evictAll(evicted *[]string) func(string) bool {
+	return func(node string) bool {
+		*evicted = append(*evicted, node)
+		return true
+	}
+}
+
+func TestPodEvictorRateLimits(t *testing.T) {
+	limiter := &countingRateLimiter{allowed: 2}
+	evictor := newPodEvictor(limiter)
+	for _, node := range []string{"node0", "node1", "node2"} {
+		if !evictor.Add(node) {
+			t.Errorf("expected %v to be queued", node)
+		}
+	}
+
+	evicted := []string{}
+	evictor.Try(evictAll(&evicted))
+	if !reflect.DeepEqual(evicted, []string{"node0", "node1"}) {
+		t.Errorf("unexpected evictions: %v", evicted)
+	}
+
+	limiter.allowed = 1
+	evicted = []string{}
+	evictor.Try(evictAll(&evicted))
+	if !reflect.DeepEqual(evicted, []string{"node2"}) {
+		t.Errorf("unexpected evictions: %v", evicted)
+	}
+}
+
+func TestPodEvictorEvictsOnce(t *testing.T) {
+	evictor := newPodEvictor(util.NewFakeRateLimiter())
+	evictor.Add("node0")
+	evicted := []string{}
+	evictor.Try(evictAll(&evicted))
+	if evictor.Add("node0") {
+		t.Errorf("expected evicted node not to be queued again")
+	}
+	evictor.Try(evictAll(&evicted))
+	if !reflect.DeepEqual(evicted, []string{"node0"}) {
+		t.Errorf("unexpected evictions: %v", evicted)
+	}
+
+	// Once the node comes back, a later failure evicts its pods again.
+	evictor.Remove("node0")
+	if !evictor.Add("node0") {
+		t.Errorf("expected node to be queued after it was removed")
+	}
+}
+
+func TestPodEvictorRetriesFailures(t *testing.T) {
+	evictor := newPodEvictor(util.NewFakeRateLimiter())
+	evictor.Add("node0")
+	evictor.Add("node1")
+	evictor.Try(func(node string) bool { return node != "node0" })
+
+	evicted := []string{}
+	evictor.Try(evictAll(&evicted))
+	if !reflect.DeepEqual(evicted, []string{"node0"}) {
+		t.Errorf("unexpected evictions: %v", evicted)
+	}
+}
+
+func TestPodEvictorRemove(t *testing.T) {
+	evictor := newPodEvictor(util.NewFakeRateLimiter())
+	evictor.Add("node0")
+	evictor.Add("node1")
+	if !evictor.Remove("node0") {
+		t.Errorf("expected pending eviction to be cancelled")
+	}
+	if evictor.Remove("node2") {
+		t.Errorf("unexpected pending eviction for unknown node")
+	}
+
+	evicted := []string{}
+	evictor.Try(evictAll(&evicted))
+	if !reflect.DeepEqual(evicted, []string{"node1"}) {
+		t.Errorf("unexpected evictions: %v", evicted)
+	}
+}
+
+func TestPodEvictorRequeue(t *testing.T) {
+	evictor := newPodEvictor(util.NewFakeRateLimiter())
+	if evictor.Requeue("node0") {
+		t.Errorf("expected a node that was never evicted not to be requeued")
+	}
+	evictor.Add("node0")
+	evicted := []string{}
+	evictor.Try(evictAll(&evicted))
+	if !evictor.Evicted("node0") {
+		t.Errorf("expected node0 to be evicted")
+	}
+	if !evictor.Requeue("node0") {
+		t.Errorf("expected evicted node to be requeued")
+	}
+	if evictor.Requeue("node0") {
+		t.Errorf("expected queued node not to be requeued twice")
+	}
+	evictor.Try(evictAll(&evicted))
+	if !reflect.DeepEqual(evicted, []string{"node0", "node0"}) {
+		t.Errorf("unexpected evictions: %v", evicted)
+	}
+}
+
+func TestPodEvictorRetain(t *testing.T) {
+	evictor := newPodEvictor(util.NewFakeRateLimiter())
+	evictor.Add("node0")
+	evictor.Add("node1")
+	evicted := []string{}
+	evictor.Try(evictAll(&evicted))
+	evictor.Add("node2")
+	evictor.Add("node3")
+
+	evictor.Retain(util.NewStringSet("node1", "node3"))
+	if evictor.Evicted("node0") || !evictor.Evicted("node1") {
+		t.Errorf("expected only node1 to stay evicted")
+	}
+	if !evictor.Add("node0") {
+		t.Errorf("expected a forgotten node to be queued again")
+	}
+	evicted = []string{}
+	evictor.Try(evictAll(&evicted))
+	if !reflect.DeepEqual(evicted, []string{"node3", "node0"}) {
+		t.Errorf("unexpected evictions: %v", evicted)
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+	"time"
+)
+
+// expectationsTimeout is how long a controller waits to observe the pods it
+// created or deleted before it assumes the events were lost and syncs anyway.
+const expectationsTimeout = 5 * time.Minute
+
+// expectation counts the pod additions and deletions a controller still
+// expects to observe.
+type expectation struct {
+	add       int
+	del       int
+	timestamp time.Time
+}
+
+// expectations tracks, per controller key, the pods a controller has asked the
+// apiserver to create or delete but not yet seen in its pod cache. Acting
+// before they are observed would create or delete the same pods twice.
+type expectations struct {
+	lock  sync.Mutex
+	items map[string]*expectation
+}
+
+func newExpectations() *expectations {
+	return &expectations{items: map[string]*expectation{}}
+}
+
+// expect records that the controller is about to create add pods and delete
+// del pods.
+func (e *expectations) expect(key string, add, del int) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.items[key] = &expectation{add: add, del: del, timestamp: time.Now()}
+}
+
+// observedAdd records that one of the expected pod creations has been seen.
+func (e *expectations) observedAdd(key string) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if exp, ok := e.items[key]; ok {
+		exp.add--
+	}
+}
+
+// observedDelete records that one of the expected pod deletions has been seen.
+func (e *expectations) observedDelete(key string) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if exp, ok := e.items[key]; ok {
+		exp.del--
+	}
+}
+
+// satisfied returns true if the controller has observed everything it
+// expected, or has waited longer than expectationsTimeout for it.
+func (e *expectations) satisfied(key string) bool {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	exp, ok := e.items[key]
+	if !ok {
+		return true
+	}
+	return (exp.add <= 0 && exp.del <= 0) || time.Since(exp.timestamp) > expectationsTimeout
+}
+
+// delete forgets the expectations of a controller that no longer exists.
+func (e *expectations) delete(key string) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	delete(e.items, key)
+}
@@ -22,11 +22,12 @@ import (
 	"time"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
-	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/validation"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 	"github.com/golang/glog"
@@ -37,17 +38,29 @@ import (
 type ReplicationManager struct {
 	kubeClient client.Interface
 	podControl PodControlInterface
-	syncTime   <-chan time.Time
 
 	// To allow injection of syncReplicationController for testing.
 	syncHandler func(controller api.ReplicationController) error
+
+	// rcInformer and podInformer keep rcStore and podStore up to date with
+	// the apiserver. The pod informer may be shared with other controllers.
+	rcInformer  *cache.Informer
+	rcStore     cache.Indexer
+	podInformer *cache.Informer
+	podStore    cache.Indexer
+
+	// expectations tracks the pod creations and deletions each controller
+	// is waiting to observe, so that it doesn't act twice on a stale podStore.
+	expectations *expectations
+	// queue holds the keys of the controllers that need to be synced.
+	queue *cache.RateLimitingQueue
 }
 
 // PodControlInterface is an interface that knows how to add or delete pods
 // created as an interface to allow testing.
 type PodControlInterface interface {
 	// createReplica creates new replicated pods according to the spec.
-	createReplica(namespace string, controller api.ReplicationController)
+	createReplica(namespace string, controller api.ReplicationController) error
 	// deletePod deletes the pod identified by podID.
 	deletePod(namespace string, podID string) error
 }
@@ -57,10 +70,16 @@ type RealPodControl struct {
 	kubeClient client.Interface
 }
 
-// Time period of main replication controller sync loop
-const DefaultSyncPeriod = 5 * time.Second
+// Time period between full resyncs of every replication controller from the local cache.
+const DefaultSyncPeriod = 30 * time.Second
 
-func (r RealPodControl) createReplica(namespace string, controller api.ReplicationController) {
+// Bounds of the backoff applied to controllers whose sync failed.
+const (
+	minRetryDelay = 5 * time.Millisecond
+	maxRetryDelay = 5 * time.Minute
+)
+
+func (r RealPodControl) createReplica(namespace string, controller api.ReplicationController) error {
 	desiredLabels := make(labels.Set)
 	for k, v := range controller.Spec.Template.Labels {
 		desiredLabels[k] = v
@@ -84,106 +103,181 @@ func (r RealPodControl) createReplica(namespace string, controller api.Replicati
 		},
 	}
 	if err := api.Scheme.Convert(&controller.Spec.Template.Spec, &pod.Spec); err != nil {
-		util.HandleError(fmt.Errorf("unable to convert pod template: %v", err))
-		return
+		return fmt.Errorf("unable to convert pod template: %v", err)
 	}
 	if labels.Set(pod.Labels).AsSelector().Empty() {
-		util.HandleError(fmt.Errorf("unable to create pod replica, no labels"))
-		return
+		return fmt.Errorf("unable to create pod replica, no labels")
 	}
 	if _, err := r.kubeClient.Pods(namespace).Create(pod); err != nil {
-		util.HandleError(fmt.Errorf("unable to create pod replica: %v", err))
+		return fmt.Errorf("unable to create pod replica: %v", err)
 	}
+	return nil
 }
 
 func (r RealPodControl) deletePod(namespace, podID string) error {
-	return r.kubeClient.Pods(namespace).Delete(podID)
+	return r.kubeClient.Pods(namespace).Delete(podID, nil)
 }
 
-// NewReplicationManager creates a new ReplicationManager.
-func NewReplicationManager(kubeClient client.Interface) *ReplicationManager {
+// NewReplicationManager creates a new ReplicationManager. podInformer may be
+// shared with other controllers; every controller is resynced from the local
+// cache every resyncPeriod.
+func NewReplicationManager(kubeClient client.Interface, podInformer *cache.Informer, resyncPeriod time.Duration) *ReplicationManager {
 	rm := &ReplicationManager{
 		kubeClient: kubeClient,
 		podControl: RealPodControl{
 			kubeClient: kubeClient,
 		},
+		rcInformer: cache.NewInformer(
+			&cache.ListWatch{
+				ListFunc: func() (runtime.Object, error) {
+					return kubeClient.ReplicationControllers(api.NamespaceAll).List(labels.Everything())
+				},
+				WatchFunc: func(resourceVersion string) (watch.Interface, error) {
+					return kubeClient.ReplicationControllers(api.NamespaceAll).Watch(labels.Everything(), fields.Everything(), resourceVersion)
+				},
+			},
+			&api.ReplicationController{},
+			resyncPeriod,
+			nil,
+		),
+		podInformer:  podInformer,
+		expectations: newExpectations(),
+		queue:        cache.NewRateLimitingQueue(minRetryDelay, maxRetryDelay),
 	}
+	rm.rcStore = rm.rcInformer.GetStore()
+	rm.podStore = podInformer.GetStore()
+	rm.rcInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    rm.enqueueController,
+		UpdateFunc: func(old, cur interface{}) { rm.enqueueController(cur) },
+		DeleteFunc: rm.enqueueController,
+	})
+	podInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    rm.addPod,
+		UpdateFunc: rm.updatePod,
+		DeleteFunc: rm.deletePod,
+	})
 	rm.syncHandler = rm.syncReplicationController
 	return rm
 }
 
-// Run begins watching and syncing.
-func (rm *ReplicationManager) Run(period time.Duration) {
-	rm.syncTime = time.Tick(period)
-	resourceVersion := ""
-	go util.Forever(func() { rm.watchControllers(&resourceVersion) }, period)
+// Run starts the informers and, once they have synced, the given number of
+// workers. It returns immediately; everything stops when stopCh is closed.
+func (rm *ReplicationManager) Run(workers int, stopCh <-chan struct{}) {
+	rm.rcInformer.Run(stopCh)
+	rm.podInformer.Run(stopCh)
+	go func() {
+		defer util.HandleCrash()
+		if !cache.WaitForSync(stopCh, rm.rcInformer, rm.podInformer) {
+			return
+		}
+		for i := 0; i < workers; i++ {
+			go util.Until(rm.worker, time.Second, stopCh)
+		}
+		<-stopCh
+		rm.queue.ShutDown()
+	}()
 }
 
-// resourceVersion is a pointer to the resource version to use/update.
-func (rm *ReplicationManager) watchControllers(resourceVersion *string) {
-	watching, err := rm.kubeClient.ReplicationControllers(api.NamespaceAll).Watch(
-		labels.Everything(),
-		fields.Everything(),
-		*resourceVersion,
-	)
+// worker processes controllers from the queue until it is shut down.
+func (rm *ReplicationManager) worker() {
+	for rm.processNextWorkItem() {
+	}
+}
+
+func (rm *ReplicationManager) processNextWorkItem() bool {
+	key, quit := rm.queue.Get()
+	if quit {
+		return false
+	}
+	defer rm.queue.Done(key)
+	if err := rm.syncController(key); err != nil {
+		util.HandleError(fmt.Errorf("error syncing replication controller %q: %v", key, err))
+		rm.queue.AddRateLimited(key)
+		return true
+	}
+	rm.queue.Forget(key)
+	return true
+}
+
+// syncController syncs the controller with the given key from the local cache.
+func (rm *ReplicationManager) syncController(key string) error {
+	obj, exists, err := rm.rcStore.GetByKey(key)
 	if err != nil {
-		util.HandleError(fmt.Errorf("unable to watch: %v", err))
-		time.Sleep(5 * time.Second)
+		return err
+	}
+	if !exists {
+		glog.V(4).Infof("Replication controller %v has been deleted", key)
+		rm.expectations.delete(key)
+		return nil
+	}
+	return rm.syncHandler(*obj.(*api.ReplicationController))
+}
+
+func (rm *ReplicationManager) enqueueController(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		util.HandleError(fmt.Errorf("couldn't get key for object %+v: %v", obj, err))
 		return
 	}
+	rm.queue.Add(key)
+}
 
-	for {
-		select {
-		case <-rm.syncTime:
-			rm.synchronize()
-		case event, open := <-watching.ResultChan():
-			if !open {
-				// watchChannel has been closed, or something else went
-				// wrong with our watch call. Let the util.Forever()
-				// that called us call us again.
-				return
-			}
-			if event.Type == watch.Error {
-				util.HandleError(fmt.Errorf("error from watch during sync: %v", errors.FromObject(event.Object)))
-				// Clear the resource version, this may cause us to skip some elements on the watch,
-				// but we'll catch them on the synchronize() call, so it works out.
-				*resourceVersion = ""
-				continue
-			}
-			glog.V(4).Infof("Got watch: %#v", event)
-			rc, ok := event.Object.(*api.ReplicationController)
-			if !ok {
-				if status, ok := event.Object.(*api.Status); ok {
-					if status.Status == api.StatusFailure {
-						glog.Errorf("failed to watch: %v", status)
-						// Clear resource version here, as above, this won't hurt consistency, but we
-						// should consider introspecting more carefully here. (or make the apiserver smarter)
-						// "why not both?"
-						*resourceVersion = ""
-						continue
-					}
-				}
-				util.HandleError(fmt.Errorf("unexpected object: %#v", event.Object))
-				continue
-			}
-			// If we get disconnected, start where we left off.
-			*resourceVersion = rc.ResourceVersion
-			// Sync even if this is a deletion event, to ensure that we leave
-			// it in the desired state.
-			glog.V(4).Infof("About to sync from watch: %v", rc.Name)
-			if err := rm.syncHandler(*rc); err != nil {
-				util.HandleError(fmt.Errorf("unexpected sync error: %v", err))
-			}
+// getPodControllers returns the keys of the controllers in the pod's namespace
+// whose selector matches the pod.
+func (rm *ReplicationManager) getPodControllers(pod *api.Pod) []string {
+	items, err := rm.rcStore.Index("namespace", pod)
+	if err != nil {
+		util.HandleError(fmt.Errorf("unable to list controllers for pod %s/%s: %v", pod.Namespace, pod.Name, err))
+		return nil
+	}
+	keys := []string{}
+	for _, item := range items {
+		rc := item.(*api.ReplicationController)
+		s, err := api.LabelSelectorAsSelector(rc.Spec.Selector, rc.Spec.SelectorRequirements)
+		if err != nil || s.Empty() || !s.Matches(labels.Set(pod.Labels)) {
+			continue
 		}
+		if key, err := cache.MetaNamespaceKeyFunc(rc); err == nil {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func (rm *ReplicationManager) addPod(obj interface{}) {
+	for _, key := range rm.getPodControllers(obj.(*api.Pod)) {
+		rm.expectations.observedAdd(key)
+		rm.queue.Add(key)
+	}
+}
+
+func (rm *ReplicationManager) updatePod(old, cur interface{}) {
+	oldPod, curPod := old.(*api.Pod), cur.(*api.Pod)
+	keys := util.NewStringSet(rm.getPodControllers(curPod)...)
+	if !api.Semantic.DeepEqual(oldPod.Labels, curPod.Labels) {
+		keys.Insert(rm.getPodControllers(oldPod)...)
+	}
+	for _, key := range keys.List() {
+		rm.queue.Add(key)
+	}
+}
+
+func (rm *ReplicationManager) deletePod(obj interface{}) {
+	for _, key := range rm.getPodControllers(obj.(*api.Pod)) {
+		rm.expectations.observedDelete(key)
+		rm.queue.Add(key)
 	}
 }
 
 // Helper function. Also used in pkg/registry/controller, for now.
+// Pods that are being gracefully deleted are not considered active, so that
+// their replacements are started while they shut down.
 func FilterActivePods(pods []api.Pod) []api.Pod {
 	var result []api.Pod
 	for _, value := range pods {
 		if api.PodSucceeded != value.Status.Phase &&
-			api.PodFailed != value.Status.Phase {
+			api.PodFailed != value.Status.Phase &&
+			value.DeletionTimestamp == nil {
 			result = append(result, value)
 		}
 	}
@@ -191,69 +285,81 @@ func FilterActivePods(pods []api.Pod) []api.Pod {
 }
 
 func (rm *ReplicationManager) syncReplicationController(controller api.ReplicationController) error {
-	s := labels.Set(controller.Spec.Selector).AsSelector()
-	podList, err := rm.kubeClient.Pods(controller.Namespace).List(s)
+	key, err := cache.MetaNamespaceKeyFunc(&controller)
 	if err != nil {
 		return err
 	}
-	filteredList := FilterActivePods(podList.Items)
+	s, err := api.LabelSelectorAsSelector(controller.Spec.Selector, controller.Spec.SelectorRequirements)
+	if err != nil {
+		return err
+	}
+	items, err := rm.podStore.Index("namespace", &controller)
+	if err != nil {
+		return err
+	}
+	pods := []api.Pod{}
+	for _, item := range items {
+		pod := item.(*api.Pod)
+		if s.Matches(labels.Set(pod.Labels)) {
+			pods = append(pods, *pod)
+		}
+	}
+	filteredList := FilterActivePods(pods)
 	activePods := len(filteredList)
-	diff := activePods - controller.Spec.Replicas
+
+	// Until the pods created or deleted by the last sync show up in the
+	// cache, the active pod count can't be trusted to act on.
+	if rm.expectations.satisfied(key) {
+		rm.manageReplicas(key, filteredList, controller)
+	} else {
+		glog.V(4).Infof("Waiting for pod creations and deletions of %q to be observed", key)
+	}
+
+	if controller.Status.Replicas != activePods {
+		controller.Status.Replicas = activePods
+		_, err = rm.kubeClient.ReplicationControllers(controller.Namespace).Update(&controller)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// manageReplicas creates or deletes pods so that the controller has as many
+// active pods as it wants, and records what it expects to observe as a result.
+func (rm *ReplicationManager) manageReplicas(key string, filteredList []api.Pod, controller api.ReplicationController) {
+	diff := len(filteredList) - controller.Spec.Replicas
 	if diff < 0 {
 		diff *= -1
+		rm.expectations.expect(key, diff, 0)
 		wait := sync.WaitGroup{}
 		wait.Add(diff)
 		glog.V(2).Infof("Too few \"%s\" replicas, creating %d\n", controller.Name, diff)
 		for i := 0; i < diff; i++ {
 			go func() {
 				defer wait.Done()
-				rm.podControl.createReplica(controller.Namespace, controller)
+				if err := rm.podControl.createReplica(controller.Namespace, controller); err != nil {
+					util.HandleError(err)
+					// The pod will never be observed, so don't wait for it.
+					rm.expectations.observedAdd(key)
+				}
 			}()
 		}
 		wait.Wait()
 	} else if diff > 0 {
 		glog.V(2).Infof("Too many \"%s\" replicas, deleting %d\n", controller.Name, diff)
+		rm.expectations.expect(key, 0, diff)
 		wait := sync.WaitGroup{}
 		wait.Add(diff)
 		for i := 0; i < diff; i++ {
 			go func(ix int) {
 				defer wait.Done()
-				rm.podControl.deletePod(controller.Namespace, filteredList[ix].Name)
+				if err := rm.podControl.deletePod(controller.Namespace, filteredList[ix].Name); err != nil {
+					util.HandleError(fmt.Errorf("unable to delete pod %s/%s: %v", controller.Namespace, filteredList[ix].Name, err))
+					rm.expectations.observedDelete(key)
+				}
 			}(i)
 		}
 		wait.Wait()
 	}
-	if controller.Status.Replicas != activePods {
-		controller.Status.Replicas = activePods
-		_, err = rm.kubeClient.ReplicationControllers(controller.Namespace).Update(&controller)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-func (rm *ReplicationManager) synchronize() {
-	// TODO: remove this method completely and rely on the watch.
-	// Add resource version tracking to watch to make this work.
-	var controllers []api.ReplicationController
-	list, err := rm.kubeClient.ReplicationControllers(api.NamespaceAll).List(labels.Everything())
-	if err != nil {
-		util.HandleError(fmt.Errorf("synchronization error: %v", err))
-		return
-	}
-	controllers = list.Items
-	wg := sync.WaitGroup{}
-	wg.Add(len(controllers))
-	for ix := range controllers {
-		go func(ix int) {
-			defer wg.Done()
-			glog.V(4).Infof("periodic sync of %v", controllers[ix].Name)
-			err := rm.syncHandler(controllers[ix])
-			if err != nil {
-				util.HandleError(fmt.Errorf("error synchronizing: %v", err))
-			}
-		}(ix)
-	}
-	wg.Wait()
 }
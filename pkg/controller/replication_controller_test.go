@@ -28,6 +28,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/latest"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/testapi"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
@@ -39,10 +40,11 @@ type FakePodControl struct {
 	lock           sync.Mutex
 }
 
-func (f *FakePodControl) createReplica(namespace string, spec api.ReplicationController) {
+func (f *FakePodControl) createReplica(namespace string, spec api.ReplicationController) error {
 	f.lock.Lock()
 	defer f.lock.Unlock()
 	f.controllerSpec = append(f.controllerSpec, spec)
+	return nil
 }
 
 func (f *FakePodControl) deletePod(namespace string, podName string) error {
@@ -58,6 +60,7 @@ func newReplicationController(replicas int) api.ReplicationController {
 		ObjectMeta: api.ObjectMeta{Name: "foobar", Namespace: api.NamespaceDefault, ResourceVersion: "18"},
 		Spec: api.ReplicationControllerSpec{
 			Replicas: replicas,
+			Selector: map[string]string{"name": "foo"},
 			Template: &api.PodTemplateSpec{
 				ObjectMeta: api.ObjectMeta{
 					Labels: map[string]string{
@@ -89,7 +92,9 @@ func newPodList(count int) *api.PodList {
 	for i := 0; i < count; i++ {
 		pods = append(pods, api.Pod{
 			ObjectMeta: api.ObjectMeta{
-				Name: fmt.Sprintf("pod%d", i),
+				Name:      fmt.Sprintf("pod%d", i),
+				Namespace: api.NamespaceDefault,
+				Labels:    map[string]string{"name": "foo"},
 			},
 		})
 	}
@@ -98,6 +103,22 @@ func newPodList(count int) *api.PodList {
 	}
 }
 
+// newTestManager returns a ReplicationManager whose informers are never run;
+// tests fill its stores directly.
+func newTestManager(kubeClient client.Interface) (*ReplicationManager, *FakePodControl) {
+	manager := NewReplicationManager(kubeClient, cache.NewPodInformer(kubeClient, 0), 0)
+	fakePodControl := &FakePodControl{}
+	manager.podControl = fakePodControl
+	return manager, fakePodControl
+}
+
+func addPods(store cache.Store, count int) {
+	for _, pod := range newPodList(count).Items {
+		p := pod
+		store.Add(&p)
+	}
+}
+
 func validateSyncReplication(t *testing.T, fakePodControl *FakePodControl, expectedCreates, expectedDeletes int) {
 	if len(fakePodControl.controllerSpec) != expectedCreates {
 		t.Errorf("Unexpected number of creates.  Expected %d, saw %d\n", expectedCreates, len(fakePodControl.controllerSpec))
@@ -158,15 +179,13 @@ func TestSyncReplicationControllerDoesNothing(t *testing.T) {
 	defer testServer.Close()
 	client := client.NewOrDie(&client.Config{Host: testServer.URL, Version: testapi.Version()})
 
-	fakePodControl := FakePodControl{}
-
-	manager := NewReplicationManager(client)
-	manager.podControl = &fakePodControl
+	manager, fakePodControl := newTestManager(client)
+	addPods(manager.podStore, 2)
 
 	controllerSpec := newReplicationController(2)
 
 	manager.syncReplicationController(controllerSpec)
-	validateSyncReplication(t, &fakePodControl, 0, 0)
+	validateSyncReplication(t, fakePodControl, 0, 0)
 }
 
 func TestSyncReplicationControllerDeletes(t *testing.T) {
@@ -179,15 +198,37 @@ func TestSyncReplicationControllerDeletes(t *testing.T) {
 	defer testServer.Close()
 	client := client.NewOrDie(&client.Config{Host: testServer.URL, Version: testapi.Version()})
 
-	fakePodControl := FakePodControl{}
-
-	manager := NewReplicationManager(client)
-	manager.podControl = &fakePodControl
+	manager, fakePodControl := newTestManager(client)
+	addPods(manager.podStore, 2)
 
 	controllerSpec := newReplicationController(1)
 
 	manager.syncReplicationController(controllerSpec)
-	validateSyncReplication(t, &fakePodControl, 0, 1)
+	validateSyncReplication(t, fakePodControl, 0, 1)
+}
+
+func TestSyncReplicationControllerReplacesTerminatingPods(t *testing.T) {
+	body, _ := latest.Codec.Encode(newPodList(2))
+	fakeHandler := util.FakeHandler{
+		StatusCode:   200,
+		ResponseBody: string(body),
+	}
+	testServer := httptest.NewServer(&fakeHandler)
+	defer testServer.Close()
+	client := client.NewOrDie(&client.Config{Host: testServer.URL, Version: testapi.Version()})
+
+	manager, fakePodControl := newTestManager(client)
+	pods := newPodList(2)
+	now := util.Now()
+	pods.Items[1].DeletionTimestamp = &now
+	for i := range pods.Items {
+		manager.podStore.Add(&pods.Items[i])
+	}
+
+	controllerSpec := newReplicationController(2)
+
+	manager.syncReplicationController(controllerSpec)
+	validateSyncReplication(t, fakePodControl, 1, 0)
 }
 
 func TestSyncReplicationControllerCreates(t *testing.T) {
@@ -199,11 +240,9 @@ func TestSyncReplicationControllerCreates(t *testing.T) {
 	defer testServer.Close()
 	client := client.NewOrDie(&client.Config{Host: testServer.URL, Version: testapi.Version()})
 
-	manager := NewReplicationManager(client)
-	fakePodControl := FakePodControl{}
-	manager.podControl = &fakePodControl
+	manager, fakePodControl := newTestManager(client)
 	manager.syncReplicationController(controller)
-	validateSyncReplication(t, &fakePodControl, 2, 0)
+	validateSyncReplication(t, fakePodControl, 2, 0)
 
 	// No Status.Replicas update expected even though 2 pods were just created,
 	// because the controller manager can't observe the pods till the next sync cycle.
@@ -213,6 +252,41 @@ func TestSyncReplicationControllerCreates(t *testing.T) {
 	}
 }
 
+func TestSyncReplicationControllerWaitsForExpectations(t *testing.T) {
+	controller := newReplicationController(2)
+	testServer, _ := makeTestServer(t, api.NamespaceDefault, controller.Name,
+		serverResponse{http.StatusOK, newPodList(0)},
+		serverResponse{http.StatusInternalServerError, &api.ReplicationControllerList{}},
+		serverResponse{http.StatusOK, &controller})
+	defer testServer.Close()
+	client := client.NewOrDie(&client.Config{Host: testServer.URL, Version: testapi.Version()})
+
+	manager, fakePodControl := newTestManager(client)
+	manager.rcStore.Add(&controller)
+	manager.syncReplicationController(controller)
+	validateSyncReplication(t, fakePodControl, 2, 0)
+
+	// The new pods haven't reached the cache yet, so another sync must not
+	// create them again.
+	manager.syncReplicationController(controller)
+	validateSyncReplication(t, fakePodControl, 2, 0)
+
+	// Once only one of the pods has been observed, the controller still waits.
+	pods := newPodList(2)
+	manager.podStore.Add(&pods.Items[0])
+	manager.addPod(&pods.Items[0])
+	manager.syncReplicationController(controller)
+	validateSyncReplication(t, fakePodControl, 2, 0)
+
+	// Losing that pod after both creations were observed triggers a new one.
+	manager.podStore.Add(&pods.Items[1])
+	manager.addPod(&pods.Items[1])
+	manager.podStore.Delete(&pods.Items[0])
+	manager.deletePod(&pods.Items[0])
+	manager.syncReplicationController(controller)
+	validateSyncReplication(t, fakePodControl, 3, 0)
+}
+
 func TestCreateReplica(t *testing.T) {
 	ns := api.NamespaceDefault
 	body := runtime.EncodeOrDie(testapi.Codec(), &api.Pod{})
@@ -229,7 +303,9 @@ func TestCreateReplica(t *testing.T) {
 	}
 
 	controllerSpec := newReplicationController(1)
-	podControl.createReplica(ns, controllerSpec)
+	if err := podControl.createReplica(ns, controllerSpec); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
 	manifest := api.ContainerManifest{}
 	if err := api.Scheme.Convert(&controllerSpec.Spec.Template.Spec, &manifest); err != nil {
@@ -254,10 +330,11 @@ func TestCreateReplica(t *testing.T) {
 	}
 }
 
-func TestSynchronize(t *testing.T) {
+func TestSyncControllersFromQueue(t *testing.T) {
 	controllerSpec1 := newReplicationController(4)
 	controllerSpec2 := newReplicationController(3)
 	controllerSpec2.Name = "bar"
+	controllerSpec2.Spec.Selector = map[string]string{"name": "bar"}
 	controllerSpec2.Spec.Template.ObjectMeta.Labels = map[string]string{
 		"name": "bar",
 		"type": "production",
@@ -265,21 +342,26 @@ func TestSynchronize(t *testing.T) {
 
 	testServer, _ := makeTestServer(t, api.NamespaceDefault, "",
 		serverResponse{http.StatusOK, newPodList(0)},
-		serverResponse{http.StatusOK, &api.ReplicationControllerList{
-			Items: []api.ReplicationController{
-				controllerSpec1,
-				controllerSpec2,
-			}}},
+		serverResponse{http.StatusOK, &api.ReplicationControllerList{}},
 		serverResponse{http.StatusInternalServerError, &api.ReplicationController{}})
 	defer testServer.Close()
 	client := client.NewOrDie(&client.Config{Host: testServer.URL, Version: testapi.Version()})
-	manager := NewReplicationManager(client)
-	fakePodControl := FakePodControl{}
-	manager.podControl = &fakePodControl
-
-	manager.synchronize()
+	manager, fakePodControl := newTestManager(client)
+	manager.rcStore.Add(&controllerSpec1)
+	manager.rcStore.Add(&controllerSpec2)
+	manager.enqueueController(&controllerSpec1)
+	manager.enqueueController(&controllerSpec2)
+	// A controller that was deleted before it was synced is ignored.
+	manager.queue.Add("default/baz")
+
+	for i := 0; i < 3; i++ {
+		manager.processNextWorkItem()
+	}
 
-	validateSyncReplication(t, &fakePodControl, 7, 0)
+	validateSyncReplication(t, fakePodControl, 7, 0)
+	if manager.queue.Len() != 0 {
+		t.Errorf("Expected an empty queue, got %d items", manager.queue.Len())
+	}
 }
 
 func TestControllerNoReplicaUpdate(t *testing.T) {
@@ -296,13 +378,14 @@ func TestControllerNoReplicaUpdate(t *testing.T) {
 		serverResponse{http.StatusOK, &rc})
 	defer testServer.Close()
 	client := client.NewOrDie(&client.Config{Host: testServer.URL, Version: testapi.Version()})
-	manager := NewReplicationManager(client)
-	fakePodControl := FakePodControl{}
-	manager.podControl = &fakePodControl
+	manager, fakePodControl := newTestManager(client)
+	manager.rcStore.Add(&rc)
+	addPods(manager.podStore, activePods)
+	manager.enqueueController(&rc)
 
-	manager.synchronize()
+	manager.processNextWorkItem()
 
-	validateSyncReplication(t, &fakePodControl, 0, 0)
+	validateSyncReplication(t, fakePodControl, 0, 0)
 	if fakeUpdateHandler.RequestReceived != nil {
 		t.Errorf("Unexpected updates for controller via %v",
 			fakeUpdateHandler.RequestReceived.URL)
@@ -324,28 +407,24 @@ func TestControllerUpdateReplicas(t *testing.T) {
 		serverResponse{http.StatusOK, &rc})
 	defer testServer.Close()
 	client := client.NewOrDie(&client.Config{Host: testServer.URL, Version: testapi.Version()})
-	manager := NewReplicationManager(client)
-	fakePodControl := FakePodControl{}
-	manager.podControl = &fakePodControl
+	manager, fakePodControl := newTestManager(client)
+	manager.rcStore.Add(&rc)
+	addPods(manager.podStore, activePods)
+	manager.enqueueController(&rc)
 
-	manager.synchronize()
+	manager.processNextWorkItem()
 
 	// Status.Replicas should go up from 2->4 even though we created 5-4=1 pod
 	rc.Status = api.ReplicationControllerStatus{Replicas: 4}
 	decRc := runtime.EncodeOrDie(testapi.Codec(), &rc)
 	fakeUpdateHandler.ValidateRequest(t, testapi.ResourcePathWithQueryParams(replicationControllerResourceName(), rc.Namespace, rc.Name), "PUT", &decRc)
-	validateSyncReplication(t, &fakePodControl, 1, 0)
-}
-
-type FakeWatcher struct {
-	w *watch.FakeWatcher
-	*client.Fake
+	validateSyncReplication(t, fakePodControl, 1, 0)
 }
 
 func TestWatchControllers(t *testing.T) {
 	fakeWatch := watch.NewFake()
 	client := &client.Fake{Watch: fakeWatch}
-	manager := NewReplicationManager(client)
+	manager, _ := newTestManager(client)
 	var testControllerSpec api.ReplicationController
 	received := make(chan struct{})
 	manager.syncHandler = func(controllerSpec api.ReplicationController) error {
@@ -356,8 +435,11 @@ func TestWatchControllers(t *testing.T) {
 		return nil
 	}
 
-	resourceVersion := ""
-	go manager.watchControllers(&resourceVersion)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	defer manager.queue.ShutDown()
+	manager.rcInformer.Run(stopCh)
+	go util.Until(manager.worker, 10*time.Millisecond, stopCh)
 
 	// Test normal case
 	testControllerSpec.Name = "foo"
@@ -366,7 +448,37 @@ func TestWatchControllers(t *testing.T) {
 
 	select {
 	case <-received:
-	case <-time.After(100 * time.Millisecond):
+	case <-time.After(5 * time.Second):
 		t.Errorf("Expected 1 call but got 0")
 	}
 }
+
+func TestWatchPods(t *testing.T) {
+	client := &client.Fake{}
+	manager, _ := newTestManager(client)
+	rc := newReplicationController(1)
+	other := newReplicationController(1)
+	other.Name = "other"
+	other.Spec.Selector = map[string]string{"name": "bar"}
+	manager.rcStore.Add(&rc)
+	manager.rcStore.Add(&other)
+
+	pod := newPodList(1).Items[0]
+	manager.addPod(&pod)
+	if manager.queue.Len() != 1 {
+		t.Fatalf("Expected only the matching controller to be queued, got %d items", manager.queue.Len())
+	}
+	key, _ := manager.queue.Get()
+	if e, a := "default/foobar", key; e != a {
+		t.Errorf("Expected %s, got %s", e, a)
+	}
+	manager.queue.Done(key)
+
+	// Relabeling a pod syncs both the old and the new controller.
+	relabeled := pod
+	relabeled.Labels = map[string]string{"name": "bar"}
+	manager.updatePod(&pod, &relabeled)
+	if manager.queue.Len() != 2 {
+		t.Errorf("Expected 2 queued controllers, got %d", manager.queue.Len())
+	}
+}
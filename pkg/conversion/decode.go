@@ -17,12 +17,12 @@ limitations under the License.
 package conversion
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 )
 
-// Decode converts a JSON string back into a pointer to an api object.
+// Decode converts a JSON string, or protobuf written by
+// EncodeToVersionProtobuf, back into a pointer to an api object.
 // Deduces the type based upon the fields added by the MetaInsertionFactory
 // technique. The object will be converted, if necessary, into the
 // s.InternalVersion type before being returned. Decode will not decode
@@ -43,7 +43,7 @@ func (s *Scheme) Decode(data []byte) (interface{}, error) {
 		return nil, err
 	}
 
-	if err := json.Unmarshal(data, obj); err != nil {
+	if err := unmarshal(data, obj); err != nil {
 		return nil, err
 	}
 
@@ -67,7 +67,8 @@ func (s *Scheme) Decode(data []byte) (interface{}, error) {
 	return obj, nil
 }
 
-// DecodeInto parses a JSON string and stores it in obj. Returns an error
+// DecodeInto parses a JSON string, or protobuf written by
+// EncodeToVersionProtobuf, and stores it in obj. Returns an error
 // if data.Kind is set and doesn't match the type of obj. Obj should be a
 // pointer to an api type.
 // If obj's version doesn't match that in data, an attempt will be made to convert
@@ -99,7 +100,7 @@ func (s *Scheme) DecodeInto(data []byte, obj interface{}) error {
 	if err != nil {
 		return err
 	}
-	if err := json.Unmarshal(data, external); err != nil {
+	if err := unmarshal(data, external); err != nil {
 		return err
 	}
 	flags, meta := s.generateConvertMeta(dataVersion, objVersion, external)
@@ -50,6 +50,14 @@ import (
 // config files.
 //
 func (s *Scheme) EncodeToVersion(obj interface{}, destVersion string) (data []byte, err error) {
+	return s.encodeToVersion(obj, destVersion, func(version, kind string, obj interface{}) ([]byte, error) {
+		return json.Marshal(obj)
+	})
+}
+
+// encodeToVersion converts obj to destVersion and calls marshal with the
+// converted object, whose version and kind are set while marshal runs.
+func (s *Scheme) encodeToVersion(obj interface{}, destVersion string, marshal func(version, kind string, obj interface{}) ([]byte, error)) (data []byte, err error) {
 	obj = maybeCopy(obj)
 	v, _ := EnforcePtr(obj) // maybeCopy guarantees a pointer
 	if _, registered := s.typeToVersion[v.Type()]; !registered {
@@ -87,8 +95,7 @@ func (s *Scheme) EncodeToVersion(obj interface{}, destVersion string) (data []by
 		return nil, err
 	}
 
-	// To add metadata, do some simple surgery on the JSON.
-	data, err = json.Marshal(obj)
+	data, err = marshal(destVersion, objKind, obj)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,83 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/protobuf"
+)
+
+// protobufPrefix starts every object written by EncodeToVersionProtobuf. No
+// JSON or YAML document starts with it, so decoding tells the formats apart
+// without being told which one it reads.
+var protobufPrefix = []byte("k8s\x00")
+
+// protobufEnvelope follows protobufPrefix and wraps the encoded object with
+// its version and kind, so they can be read without knowing its type.
+type protobufEnvelope struct {
+	APIVersion string `protobuf:"1"`
+	Kind       string `protobuf:"2"`
+	Raw        []byte `protobuf:"3"`
+}
+
+// IsProtobuf returns true if data holds an object written by
+// EncodeToVersionProtobuf.
+func IsProtobuf(data []byte) bool {
+	return bytes.HasPrefix(data, protobufPrefix)
+}
+
+// EncodeToVersionProtobuf is like EncodeToVersion, but writes the object in
+// the protobuf wire format instead of JSON. Every field of the versioned type
+// must carry its field number in a protobuf tag; see pkg/util/protobuf.
+func (s *Scheme) EncodeToVersionProtobuf(obj interface{}, destVersion string) (data []byte, err error) {
+	return s.encodeToVersion(obj, destVersion, func(version, kind string, obj interface{}) ([]byte, error) {
+		raw, err := protobuf.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		envelope, err := protobuf.Marshal(&protobufEnvelope{APIVersion: version, Kind: kind, Raw: raw})
+		if err != nil {
+			return nil, err
+		}
+		return append(append([]byte{}, protobufPrefix...), envelope...), nil
+	})
+}
+
+// readProtobufEnvelope returns the envelope of data, which must hold an
+// object written by EncodeToVersionProtobuf.
+func readProtobufEnvelope(data []byte) (*protobufEnvelope, error) {
+	envelope := &protobufEnvelope{}
+	if err := protobuf.Unmarshal(data[len(protobufPrefix):], envelope); err != nil {
+		return nil, err
+	}
+	return envelope, nil
+}
+
+// unmarshal decodes data, which holds JSON or an object written by
+// EncodeToVersionProtobuf, into obj.
+func unmarshal(data []byte, obj interface{}) error {
+	if !IsProtobuf(data) {
+		return json.Unmarshal(data, obj)
+	}
+	envelope, err := readProtobufEnvelope(data)
+	if err != nil {
+		return err
+	}
+	return protobuf.Unmarshal(envelope.Raw, obj)
+}
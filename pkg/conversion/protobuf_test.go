@@ -0,0 +1,81 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProtobufTypes(t *testing.T) {
+	table := []interface{}{
+		&TestType1{},
+		&ExternalInternalSame{},
+	}
+	for _, item := range table {
+		// Because the fuzzer is random, do a few times.
+		for i := 0; i < *fuzzIters; i++ {
+			name := reflect.TypeOf(item).Elem().Name()
+			source := reflect.New(reflect.TypeOf(item).Elem()).Interface()
+			TestObjectFuzzer.Fuzz(source)
+
+			s := GetTestScheme()
+			data, err := s.EncodeToVersionProtobuf(source, "v1")
+			if err != nil {
+				t.Fatalf("%v: %v (%#v)", name, err, source)
+			}
+			if !IsProtobuf(data) {
+				t.Fatalf("%v: expected protobuf, got %q", name, string(data))
+			}
+			version, kind, err := s.DataVersionAndKind(data)
+			if err != nil || version != "v1" || kind != name {
+				t.Errorf("%v: unexpected version and kind %q %q: %v", name, version, kind, err)
+			}
+
+			obj2, err := s.Decode(data)
+			if err != nil {
+				t.Fatalf("%v: %v", name, err)
+			}
+			if !reflect.DeepEqual(source, obj2) {
+				t.Errorf("1: %v: diff: %v", name, objDiff(source, obj2))
+			}
+			obj3 := reflect.New(reflect.TypeOf(source).Elem()).Interface()
+			if err := s.DecodeInto(data, obj3); err != nil {
+				t.Fatalf("2: %v: %v", name, err)
+			}
+			if !reflect.DeepEqual(source, obj3) {
+				t.Errorf("3: %v: diff: %v", name, objDiff(source, obj3))
+			}
+		}
+	}
+}
+
+func TestProtobufIsSmallerThanJSON(t *testing.T) {
+	s := GetTestScheme()
+	obj := &TestType1{A: "name", B: 100, L: true, M: map[string]int{"a": 1, "b": 2}, P: []TestType2{{A: "x", B: 1}, {A: "y", B: 2}}}
+	data, err := s.EncodeToVersion(obj, "v1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pb, err := s.EncodeToVersionProtobuf(obj, "v1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pb) >= len(data) {
+		t.Errorf("expected protobuf to be smaller than %d bytes of JSON, got %d bytes", len(data), len(pb))
+	}
+}
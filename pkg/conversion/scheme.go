@@ -321,6 +321,13 @@ func (s *Scheme) generateConvertMeta(srcVersion, destVersion string, in interfac
 // DataVersionAndKind will return the APIVersion and Kind of the given wire-format
 // encoding of an API Object, or an error.
 func (s *Scheme) DataVersionAndKind(data []byte) (version, kind string, err error) {
+	if IsProtobuf(data) {
+		envelope, err := readProtobufEnvelope(data)
+		if err != nil {
+			return "", "", err
+		}
+		return envelope.APIVersion, envelope.Kind, nil
+	}
 	return s.MetaFactory.Interpret(data)
 }
 
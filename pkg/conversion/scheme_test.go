@@ -34,11 +34,11 @@ var fuzzIters = flag.Int("fuzz_iters", 50, "How many fuzzing iterations to do.")
 
 // Test a weird version/kind embedding format.
 type MyWeirdCustomEmbeddedVersionKindField struct {
-	ID         string `json:"ID,omitempty"`
-	APIVersion string `json:"myVersionKey,omitempty"`
-	ObjectKind string `json:"myKindKey,omitempty"`
-	Z          string `json:"Z,omitempty"`
-	Y          uint64 `json:"Y,omitempty"`
+	ID         string `json:"ID,omitempty" protobuf:"1"`
+	APIVersion string `json:"myVersionKey,omitempty" protobuf:"2"`
+	ObjectKind string `json:"myKindKey,omitempty" protobuf:"3"`
+	Z          string `json:"Z,omitempty" protobuf:"4"`
+	Y          uint64 `json:"Y,omitempty" protobuf:"5"`
 }
 
 type TestType1 struct {
@@ -62,37 +62,37 @@ type TestType1 struct {
 }
 
 type TestType2 struct {
-	A string `json:"A,omitempty"`
-	B int    `json:"B,omitempty"`
+	A string `json:"A,omitempty" protobuf:"1"`
+	B int    `json:"B,omitempty" protobuf:"2"`
 }
 
 type ExternalTestType2 struct {
-	A string `json:"A,omitempty"`
-	B int    `json:"B,omitempty"`
+	A string `json:"A,omitempty" protobuf:"1"`
+	B int    `json:"B,omitempty" protobuf:"2"`
 }
 type ExternalTestType1 struct {
-	MyWeirdCustomEmbeddedVersionKindField `json:",inline"`
-	A                                     string                       `json:"A,omitempty"`
-	B                                     int                          `json:"B,omitempty"`
-	C                                     int8                         `json:"C,omitempty"`
-	D                                     int16                        `json:"D,omitempty"`
-	E                                     int32                        `json:"E,omitempty"`
-	F                                     int64                        `json:"F,omitempty"`
-	G                                     uint                         `json:"G,omitempty"`
-	H                                     uint8                        `json:"H,omitempty"`
-	I                                     uint16                       `json:"I,omitempty"`
-	J                                     uint32                       `json:"J,omitempty"`
-	K                                     uint64                       `json:"K,omitempty"`
-	L                                     bool                         `json:"L,omitempty"`
-	M                                     map[string]int               `json:"M,omitempty"`
-	N                                     map[string]ExternalTestType2 `json:"N,omitempty"`
-	O                                     *ExternalTestType2           `json:"O,omitempty"`
-	P                                     []ExternalTestType2          `json:"Q,omitempty"`
+	MyWeirdCustomEmbeddedVersionKindField `json:",inline" protobuf:"1"`
+	A                                     string                       `json:"A,omitempty" protobuf:"2"`
+	B                                     int                          `json:"B,omitempty" protobuf:"3"`
+	C                                     int8                         `json:"C,omitempty" protobuf:"4"`
+	D                                     int16                        `json:"D,omitempty" protobuf:"5"`
+	E                                     int32                        `json:"E,omitempty" protobuf:"6"`
+	F                                     int64                        `json:"F,omitempty" protobuf:"7"`
+	G                                     uint                         `json:"G,omitempty" protobuf:"8"`
+	H                                     uint8                        `json:"H,omitempty" protobuf:"9"`
+	I                                     uint16                       `json:"I,omitempty" protobuf:"10"`
+	J                                     uint32                       `json:"J,omitempty" protobuf:"11"`
+	K                                     uint64                       `json:"K,omitempty" protobuf:"12"`
+	L                                     bool                         `json:"L,omitempty" protobuf:"13"`
+	M                                     map[string]int               `json:"M,omitempty" protobuf:"14"`
+	N                                     map[string]ExternalTestType2 `json:"N,omitempty" protobuf:"15"`
+	O                                     *ExternalTestType2           `json:"O,omitempty" protobuf:"16"`
+	P                                     []ExternalTestType2          `json:"Q,omitempty" protobuf:"17"`
 }
 
 type ExternalInternalSame struct {
-	MyWeirdCustomEmbeddedVersionKindField `json:",inline"`
-	A                                     TestType2 `json:"A,omitempty"`
+	MyWeirdCustomEmbeddedVersionKindField `json:",inline" protobuf:"1"`
+	A                                     TestType2 `json:"A,omitempty" protobuf:"2"`
 }
 
 // TestObjectFuzzer can randomly populate all the above objects.
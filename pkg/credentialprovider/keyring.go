@@ -17,10 +17,12 @@ limitations under the License.
 package credentialprovider
 
 import (
+	"fmt"
 	"net/url"
 	"sort"
 	"strings"
 
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	docker "github.com/fsouza/go-dockerclient"
 	"github.com/golang/glog"
 )
@@ -144,6 +146,56 @@ func (dk *lazyDockerKeyring) Lookup(image string) (docker.AuthConfiguration, boo
 	return keyring.Lookup(image)
 }
 
+// unionDockerKeyring delegates to a set of keyrings, in order, and returns
+// the credentials of the first one that has any for the image.
+type unionDockerKeyring struct {
+	keyrings []DockerKeyring
+}
+
+// Lookup implements the DockerKeyring method for fetching credentials
+// based on image name.
+func (k *unionDockerKeyring) Lookup(image string) (docker.AuthConfiguration, bool) {
+	for _, subKeyring := range k.keyrings {
+		if subKeyring == nil {
+			continue
+		}
+		if auth, ok := subKeyring.Lookup(image); ok {
+			return auth, true
+		}
+	}
+	return docker.AuthConfiguration{}, false
+}
+
+// MakeDockerKeyring returns a keyring that looks up credentials in the
+// dockercfg secrets passed in first, and then in defaultKeyring. Secrets
+// of other types are ignored.
+func MakeDockerKeyring(passedSecrets []api.Secret, defaultKeyring DockerKeyring) (DockerKeyring, error) {
+	passedCredentials := []DockerConfig{}
+	for _, passedSecret := range passedSecrets {
+		if passedSecret.Type != api.SecretTypeDockercfg {
+			continue
+		}
+		dockercfgBytes, exists := passedSecret.Data[api.DockerConfigKey]
+		if !exists || len(dockercfgBytes) == 0 {
+			continue
+		}
+		dockercfg, err := readDockerConfigFileFromBytes(dockercfgBytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s in secret %s/%s: %v", api.DockerConfigKey, passedSecret.Namespace, passedSecret.Name, err)
+		}
+		passedCredentials = append(passedCredentials, dockercfg)
+	}
+
+	if len(passedCredentials) == 0 {
+		return defaultKeyring, nil
+	}
+	basicKeyring := &BasicDockerKeyring{}
+	for _, currCredentials := range passedCredentials {
+		basicKeyring.Add(currCredentials)
+	}
+	return &unionDockerKeyring{[]DockerKeyring{basicKeyring, defaultKeyring}}, nil
+}
+
 type FakeKeyring struct {
 	auth docker.AuthConfiguration
 	ok   bool
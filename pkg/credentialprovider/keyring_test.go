@@ -20,6 +20,9 @@ import (
 	"encoding/base64"
 	"fmt"
 	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	docker "github.com/fsouza/go-dockerclient"
 )
 
 func TestDockerKeyringFromBytes(t *testing.T) {
@@ -259,3 +262,46 @@ func TestLazyKeyring(t *testing.T) {
 		t.Errorf("Unexpected number of Provide calls: %v", provider.Count)
 	}
 }
+
+func TestMakeDockerKeyringFromSecrets(t *testing.T) {
+	auth := base64.StdEncoding.EncodeToString([]byte("foo:bar"))
+	dockercfg := fmt.Sprintf(`{"registry.example.com": {"auth": %q, "email": "foo@example.com"}}`, auth)
+	secrets := []api.Secret{
+		{
+			ObjectMeta: api.ObjectMeta{Name: "opaque", Namespace: "ns"},
+			Type:       api.SecretTypeOpaque,
+			Data:       map[string][]byte{api.DockerConfigKey: []byte("ignored")},
+		},
+		{
+			ObjectMeta: api.ObjectMeta{Name: "pull", Namespace: "ns"},
+			Type:       api.SecretTypeDockercfg,
+			Data:       map[string][]byte{api.DockerConfigKey: []byte(dockercfg)},
+		},
+	}
+	defaultKeyring := &FakeKeyring{auth: docker.AuthConfiguration{Username: "default"}, ok: true}
+
+	keyring, err := MakeDockerKeyring(secrets, defaultKeyring)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	creds, ok := keyring.Lookup("registry.example.com/team/image")
+	if !ok {
+		t.Fatalf("expected credentials from the pull secret")
+	}
+	if creds.Username != "foo" || creds.Password != "bar" || creds.Email != "foo@example.com" {
+		t.Errorf("unexpected credentials: %+v", creds)
+	}
+	creds, ok = keyring.Lookup("other.example.com/image")
+	if !ok || creds.Username != "default" {
+		t.Errorf("expected to fall back to the default keyring, got %+v, %v", creds, ok)
+	}
+
+	if keyring, _ := MakeDockerKeyring(secrets[:1], defaultKeyring); keyring != DockerKeyring(defaultKeyring) {
+		t.Errorf("expected the default keyring without dockercfg secrets")
+	}
+
+	secrets[1].Data[api.DockerConfigKey] = []byte("not json")
+	if _, err := MakeDockerKeyring(secrets, defaultKeyring); err == nil {
+		t.Errorf("expected an error for an invalid dockercfg")
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"time"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubectl/cmd/util"
@@ -32,7 +33,13 @@ const (
 $ kubectl log 123456-7890 ruby-container
 
 // Starts streaming of ruby-container logs from pod 123456-7890.
-$ kubectl log -f 123456-7890 ruby-container`
+$ kubectl log -f 123456-7890 ruby-container
+
+// Returns the logs of the previous, terminated instance of ruby-container, without timestamps.
+$ kubectl log -p --timestamps=false 123456-7890 ruby-container
+
+// Returns at most 1MB of the ruby-container logs written since 10:00 UTC.
+$ kubectl log --since-time=2015-05-01T10:00:00Z --limit-bytes=1048576 123456-7890 ruby-container`
 )
 
 func selectContainer(pod *api.Pod, in io.Reader, out io.Writer) string {
@@ -59,7 +66,7 @@ func selectContainer(pod *api.Pod, in io.Reader, out io.Writer) string {
 
 func (f *Factory) NewCmdLog(out io.Writer) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "log [-f] POD [CONTAINER]",
+		Use:     "log [-f] [-p] POD [CONTAINER]",
 		Short:   "Print the logs for a container in a pod.",
 		Long:    "Print the logs for a container in a pod. If the pod has only one container, the container name is optional.",
 		Example: log_example,
@@ -69,6 +76,10 @@ func (f *Factory) NewCmdLog(out io.Writer) *cobra.Command {
 		},
 	}
 	cmd.Flags().BoolP("follow", "f", false, "Specify if the logs should be streamed.")
+	cmd.Flags().BoolP("previous", "p", false, "If true, print the logs of the previous terminated instance of the container.")
+	cmd.Flags().String("since-time", "", "Only print the logs written at or after this RFC3339 time, e.g. 2015-05-01T10:00:00Z.")
+	cmd.Flags().Bool("timestamps", true, "If true, prefix each line of the logs with the time it was written at.")
+	cmd.Flags().Int("limit-bytes", 0, "Maximum number of bytes of logs to print. Zero means no limit.")
 	cmd.Flags().Bool("interactive", true, "If true, prompt the user for input when required. Default true.")
 	return cmd
 }
@@ -104,17 +115,32 @@ func RunLog(f *Factory, out io.Writer, cmd *cobra.Command, args []string) error
 		container = args[1]
 	}
 
-	follow := false
-	if util.GetFlagBool(cmd, "follow") {
-		follow = true
+	timestamps := util.GetFlagBool(cmd, "timestamps")
+	opts := &api.PodLogOptions{
+		Follow:     util.GetFlagBool(cmd, "follow"),
+		Previous:   util.GetFlagBool(cmd, "previous"),
+		Timestamps: &timestamps,
+	}
+	if sinceTime := util.GetFlagString(cmd, "since-time"); len(sinceTime) > 0 {
+		t, err := time.Parse(time.RFC3339, sinceTime)
+		if err != nil {
+			return util.UsageError(cmd, "invalid --since-time %q: %v", sinceTime, err)
+		}
+		since := libutil.NewTime(t)
+		opts.SinceTime = &since
+	}
+	limitBytes := util.GetFlagInt(cmd, "limit-bytes")
+	if limitBytes < 0 {
+		return util.UsageError(cmd, "--limit-bytes must be a non-negative number")
 	}
+	opts.LimitBytes = int64(limitBytes)
 
 	readCloser, err := client.RESTClient.Get().
 		Prefix("proxy").
 		Resource("minions").
 		Name(pod.Status.Host).
 		Suffix("containerLogs", namespace, podID, container).
-		Param("follow", strconv.FormatBool(follow)).
+		PodLogOptions(opts).
 		Stream()
 	if err != nil {
 		return err
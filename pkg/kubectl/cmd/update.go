@@ -17,9 +17,11 @@ limitations under the License.
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	cmdutil "github.com/GoogleCloudPlatform/kubernetes/pkg/kubectl/cmd/util"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubectl/resource"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
@@ -36,8 +38,8 @@ $ kubectl update -f pod.json
 // Update a pod based on the JSON passed into stdin.
 $ cat pod.json | kubectl update -f -
 
-// Update a pod by downloading it, applying the patch, then updating. Requires apiVersion be specified.
-$ kubectl update pods my-pod --patch='{ "apiVersion": "v1beta1", "desiredState": { "manifest": [{ "cpu": 100 }]}}'`
+// Update the image of one container of a pod on the server, leaving the other containers as they are. Requires apiVersion be specified.
+$ kubectl update pods my-pod --patch='{ "apiVersion": "v1beta3", "spec": { "containers": [{ "name": "nginx", "image": "nginx:1.7.9" }]}}'`
 )
 
 func (f *Factory) NewCmdUpdate(out io.Writer) *cobra.Command {
@@ -53,7 +55,7 @@ func (f *Factory) NewCmdUpdate(out io.Writer) *cobra.Command {
 		},
 	}
 	cmd.Flags().VarP(&filenames, "filename", "f", "Filename, directory, or URL to file to use to update the resource.")
-	cmd.Flags().String("patch", "", "A JSON document to override the existing resource. The resource is downloaded, patched with the JSON, then updated.")
+	cmd.Flags().String("patch", "", "A JSON document to merge into the existing resource on the server. Lists of named items, like containers and ports, are merged item by item.")
 	return cmd
 }
 
@@ -129,27 +131,36 @@ func updateWithPatch(cmd *cobra.Command, args []string, f *Factory, patch string
 	if err != nil {
 		return "", err
 	}
-	client, err := f.RESTClient(mapping)
+	// The server applies the patch to the resource encoded in the API version
+	// the patch is written for.
+	version, err := patchAPIVersion(patch)
 	if err != nil {
 		return "", err
 	}
-
-	helper := resource.NewHelper(client, mapping)
-	obj, err := helper.Get(namespace, name)
+	mapping, err = mapper.RESTMapping(mapping.Kind, version)
 	if err != nil {
 		return "", err
 	}
-
-	patchedObj, err := cmdutil.Merge(obj, patch, mapping.Kind)
+	client, err := f.RESTClient(mapping)
 	if err != nil {
 		return "", err
 	}
 
-	data, err := helper.Codec.Encode(patchedObj)
-	if err != nil {
+	helper := resource.NewHelper(client, mapping)
+	_, err = helper.Patch(namespace, name, api.StrategicMergePatchType, []byte(patch))
+	return name, err
+}
+
+// patchAPIVersion returns the apiVersion field of a JSON patch.
+func patchAPIVersion(patch string) (string, error) {
+	var fields struct {
+		APIVersion *string `json:"apiVersion"`
+	}
+	if err := json.Unmarshal([]byte(patch), &fields); err != nil {
 		return "", err
 	}
-
-	_, err = helper.Update(namespace, name, true, data)
-	return name, err
+	if fields.APIVersion == nil {
+		return "", fmt.Errorf("Inline JSON requires an apiVersion field")
+	}
+	return *fields.APIVersion, nil
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package kubectl
 
 import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 )
 
@@ -27,4 +28,5 @@ type RESTClient interface {
 	Post() *client.Request
 	Delete() *client.Request
 	Put() *client.Request
+	Patch(api.PatchType) *client.Request
 }
@@ -42,6 +42,20 @@ func formatLabels(labelMap map[string]string) string {
 	return l
 }
 
+// formatSelector returns a human readable form of a selector made of exact match
+// labels and set-based requirements.
+func formatSelector(labelMap map[string]string, requirements []api.LabelSelectorRequirement) string {
+	selector, err := api.LabelSelectorAsSelector(labelMap, requirements)
+	if err != nil {
+		return "<invalid>"
+	}
+	l := selector.String()
+	if l == "" {
+		l = "<none>"
+	}
+	return l
+}
+
 func listOfImages(spec *api.PodSpec) []string {
 	var images []string
 	for _, container := range spec.Containers {
@@ -134,6 +134,18 @@ func (m *Helper) createResource(c RESTClient, resource, namespace string, data [
 	return c.Post().NamespaceIfScoped(namespace, m.NamespaceScoped).Resource(resource).Body(data).Do().Get()
 }
 
+// Patch applies the patch of the given type to the named resource on the
+// server, which avoids reading and rewriting the whole resource.
+func (m *Helper) Patch(namespace, name string, pt api.PatchType, data []byte) (runtime.Object, error) {
+	return m.RESTClient.Patch(pt).
+		NamespaceIfScoped(namespace, m.NamespaceScoped).
+		Resource(m.Resource).
+		Name(name).
+		Body(data).
+		Do().
+		Get()
+}
+
 func (m *Helper) Update(namespace, name string, overwrite bool, data []byte) (runtime.Object, error) {
 	c := m.RESTClient
 
@@ -17,6 +17,7 @@ limitations under the License.
 package resource
 
 import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/meta"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 )
@@ -28,6 +29,7 @@ type RESTClient interface {
 	Post() *client.Request
 	Delete() *client.Request
 	Put() *client.Request
+	Patch(api.PatchType) *client.Request
 }
 
 // ClientMapper retrieves a client object for a given mapping
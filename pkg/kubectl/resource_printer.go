@@ -34,6 +34,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/conversion"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/docker/docker/pkg/units"
 	"github.com/ghodss/yaml"
 	"github.com/golang/glog"
@@ -230,7 +231,7 @@ func (h *HumanReadablePrinter) validatePrintHandlerFunc(printFunc reflect.Value)
 
 var podColumns = []string{"POD", "IP", "CONTAINER(S)", "IMAGE(S)", "HOST", "LABELS", "STATUS", "CREATED"}
 var replicationControllerColumns = []string{"CONTROLLER", "CONTAINER(S)", "IMAGE(S)", "SELECTOR", "REPLICAS"}
-var serviceColumns = []string{"NAME", "LABELS", "SELECTOR", "IP", "PORT"}
+var serviceColumns = []string{"NAME", "LABELS", "SELECTOR", "IP", "PORT(S)"}
 var endpointColumns = []string{"NAME", "ENDPOINTS"}
 var nodeColumns = []string{"NAME", "LABELS", "STATUS"}
 var statusColumns = []string{"STATUS"}
@@ -277,16 +278,38 @@ func (h *HumanReadablePrinter) printHeader(columnNames []string, w io.Writer) er
 	return nil
 }
 
-func formatEndpoints(endpoints []api.Endpoint) string {
-	if len(endpoints) == 0 {
+// Pass ports=nil for all ports.
+func formatEndpoints(endpoints *api.Endpoints, ports util.StringSet) string {
+	if len(endpoints.Subsets) == 0 {
 		return "<none>"
 	}
 	list := []string{}
-	for i := range endpoints {
-		ep := &endpoints[i]
-		list = append(list, net.JoinHostPort(ep.IP, strconv.Itoa(ep.Port)))
+	max := 3
+	more := false
+	count := 0
+	for i := range endpoints.Subsets {
+		ss := &endpoints.Subsets[i]
+		for i := range ss.Ports {
+			port := &ss.Ports[i]
+			if ports == nil || ports.Has(port.Name) {
+				for i := range ss.Addresses {
+					if len(list) == max {
+						more = true
+					}
+					addr := &ss.Addresses[i]
+					if !more {
+						list = append(list, net.JoinHostPort(addr.IP, strconv.Itoa(port.Port)))
+					}
+					count++
+				}
+			}
+		}
 	}
-	return strings.Join(list, ",")
+	ret := strings.Join(list, ",")
+	if more {
+		return fmt.Sprintf("%s + %d more...", ret, count-max)
+	}
+	return ret
 }
 
 func podHostString(host, ip string) string {
@@ -348,7 +371,7 @@ func printReplicationController(controller *api.ReplicationController, w io.Writ
 		controller.Name,
 		firstContainer.Name,
 		firstContainer.Image,
-		formatLabels(controller.Spec.Selector),
+		formatSelector(controller.Spec.Selector, controller.Spec.SelectorRequirements),
 		controller.Spec.Replicas)
 	if err != nil {
 		return err
@@ -373,9 +396,24 @@ func printReplicationControllerList(list *api.ReplicationControllerList, w io.Wr
 }
 
 func printService(svc *api.Service, w io.Writer) error {
-	_, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\n", svc.Name, formatLabels(svc.Labels),
-		formatLabels(svc.Spec.Selector), svc.Spec.PortalIP, svc.Spec.Port)
-	return err
+	ports := svc.Spec.Ports
+	if len(ports) == 0 {
+		_, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t\n", svc.Name, formatLabels(svc.Labels),
+			formatSelector(svc.Spec.Selector, svc.Spec.SelectorRequirements), svc.Spec.PortalIP)
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d/%s\n", svc.Name, formatLabels(svc.Labels),
+		formatSelector(svc.Spec.Selector, svc.Spec.SelectorRequirements), svc.Spec.PortalIP, ports[0].Port, ports[0].Protocol)
+	if err != nil {
+		return err
+	}
+	// Lay out additional ports.
+	for i := 1; i < len(ports); i++ {
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d/%s\n", "", "", "", "", ports[i].Port, ports[i].Protocol); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func printServiceList(list *api.ServiceList, w io.Writer) error {
@@ -388,7 +426,7 @@ func printServiceList(list *api.ServiceList, w io.Writer) error {
 }
 
 func printEndpoints(endpoint *api.Endpoints, w io.Writer) error {
-	_, err := fmt.Fprintf(w, "%s\t%s\n", endpoint.Name, formatEndpoints(endpoint.Endpoints))
+	_, err := fmt.Fprintf(w, "%s\t%s\n", endpoint.Name, formatEndpoints(endpoint, nil))
 	return err
 }
 
@@ -46,9 +46,11 @@ func TestGenerateService(t *testing.T) {
 						"foo": "bar",
 						"baz": "blah",
 					},
-					Port:       80,
-					Protocol:   "TCP",
-					TargetPort: util.NewIntOrStringFromInt(1234),
+					Ports: []api.ServicePort{{
+						Port:       80,
+						Protocol:   "TCP",
+						TargetPort: util.NewIntOrStringFromInt(1234),
+					}},
 				},
 			},
 		},
@@ -69,9 +71,11 @@ func TestGenerateService(t *testing.T) {
 						"foo": "bar",
 						"baz": "blah",
 					},
-					Port:       80,
-					Protocol:   "UDP",
-					TargetPort: util.NewIntOrStringFromString("foobar"),
+					Ports: []api.ServicePort{{
+						Port:       80,
+						Protocol:   "UDP",
+						TargetPort: util.NewIntOrStringFromString("foobar"),
+					}},
 				},
 			},
 		},
@@ -97,9 +101,11 @@ func TestGenerateService(t *testing.T) {
 						"foo": "bar",
 						"baz": "blah",
 					},
-					Port:       80,
-					Protocol:   "TCP",
-					TargetPort: util.NewIntOrStringFromInt(1234),
+					Ports: []api.ServicePort{{
+						Port:       80,
+						Protocol:   "TCP",
+						TargetPort: util.NewIntOrStringFromInt(1234),
+					}},
 				},
 			},
 		},
@@ -121,10 +127,12 @@ func TestGenerateService(t *testing.T) {
 						"foo": "bar",
 						"baz": "blah",
 					},
-					Port:       80,
-					Protocol:   "UDP",
-					PublicIPs:  []string{"1.2.3.4"},
-					TargetPort: util.NewIntOrStringFromString("foobar"),
+					Ports: []api.ServicePort{{
+						Port:       80,
+						Protocol:   "UDP",
+						TargetPort: util.NewIntOrStringFromString("foobar"),
+					}},
+					PublicIPs: []string{"1.2.3.4"},
 				},
 			},
 		},
@@ -147,10 +155,12 @@ func TestGenerateService(t *testing.T) {
 						"foo": "bar",
 						"baz": "blah",
 					},
-					Port:                       80,
-					Protocol:                   "UDP",
+					Ports: []api.ServicePort{{
+						Port:       80,
+						Protocol:   "UDP",
+						TargetPort: util.NewIntOrStringFromString("foobar"),
+					}},
 					PublicIPs:                  []string{"1.2.3.4"},
-					TargetPort:                 util.NewIntOrStringFromString("foobar"),
 					CreateExternalLoadBalancer: true,
 				},
 			},
@@ -17,6 +17,8 @@ limitations under the License.
 package config
 
 import (
+	"crypto/md5"
+	"encoding/hex"
 	"fmt"
 	"reflect"
 	"sync"
@@ -207,11 +209,11 @@ func (s *podStorage) merge(source string, change interface{}) (adds, updates, de
 
 		filtered := filterInvalidPods(update.Pods, source, s.recorder)
 		for _, ref := range filtered {
+			setConfigAnnotations(ref, source)
 			name := kubecontainer.GetPodFullName(ref)
 			if existing, found := pods[name]; found {
-				if !reflect.DeepEqual(existing.Spec, ref.Spec) {
+				if checkAndUpdatePod(existing, ref) {
 					// this is an update
-					existing.Spec = ref.Spec
 					updates.Pods = append(updates.Pods, *existing)
 					continue
 				}
@@ -219,10 +221,6 @@ func (s *podStorage) merge(source string, change interface{}) (adds, updates, de
 				continue
 			}
 			// this is an add
-			if ref.Annotations == nil {
-				ref.Annotations = make(map[string]string)
-			}
-			ref.Annotations[kubelet.ConfigSourceAnnotationKey] = source
 			pods[name] = ref
 			adds.Pods = append(adds.Pods, *ref)
 		}
@@ -249,22 +247,18 @@ func (s *podStorage) merge(source string, change interface{}) (adds, updates, de
 
 		filtered := filterInvalidPods(update.Pods, source, s.recorder)
 		for _, ref := range filtered {
+			setConfigAnnotations(ref, source)
 			name := kubecontainer.GetPodFullName(ref)
 			if existing, found := oldPods[name]; found {
 				pods[name] = existing
-				if !reflect.DeepEqual(existing.Spec, ref.Spec) {
+				if checkAndUpdatePod(existing, ref) {
 					// this is an update
-					existing.Spec = ref.Spec
 					updates.Pods = append(updates.Pods, *existing)
 					continue
 				}
 				// this is a no-op
 				continue
 			}
-			if ref.Annotations == nil {
-				ref.Annotations = make(map[string]string)
-			}
-			ref.Annotations[kubelet.ConfigSourceAnnotationKey] = source
 			pods[name] = ref
 			adds.Pods = append(adds.Pods, *ref)
 		}
@@ -285,6 +279,58 @@ func (s *podStorage) merge(source string, change interface{}) (adds, updates, de
 	return adds, updates, deletes
 }
 
+// setConfigAnnotations records the source of the pod, and for static pods the
+// hash of their content, in its annotations.
+func setConfigAnnotations(pod *api.Pod, source string) {
+	var hash string
+	if isStaticSource(source) {
+		hash = podHash(pod)
+	}
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[kubelet.ConfigSourceAnnotationKey] = source
+	if len(hash) > 0 {
+		pod.Annotations[kubelet.ConfigHashAnnotationKey] = hash
+	}
+}
+
+// isStaticSource returns true if the pods of source are not known to the apiserver.
+func isStaticSource(source string) bool {
+	return source == kubelet.FileSource || source == kubelet.HTTPSource
+}
+
+// podHash returns the hash of the content of a pod.
+func podHash(pod *api.Pod) string {
+	hasher := md5.New()
+	util.DeepHashObject(hasher, pod)
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+// checkAndUpdatePod copies the spec, metadata and deletion state of ref onto existing and
+// returns true if any of them changed. The deletion state is set when a pod is gracefully
+// deleted in the apiserver and tells the kubelet to terminate the pod. Static pods whose
+// content hash did not change are left alone; a change that leaves the spec alone does not
+// restart any container, since containers are only restarted when their own spec changes.
+func checkAndUpdatePod(existing, ref *api.Pod) bool {
+	if hash, found := ref.Annotations[kubelet.ConfigHashAnnotationKey]; found && hash == existing.Annotations[kubelet.ConfigHashAnnotationKey] {
+		return false
+	}
+	if reflect.DeepEqual(existing.Spec, ref.Spec) &&
+		reflect.DeepEqual(existing.Labels, ref.Labels) &&
+		reflect.DeepEqual(existing.Annotations, ref.Annotations) &&
+		reflect.DeepEqual(existing.DeletionTimestamp, ref.DeletionTimestamp) &&
+		reflect.DeepEqual(existing.DeletionGracePeriodSeconds, ref.DeletionGracePeriodSeconds) {
+		return false
+	}
+	existing.Spec = ref.Spec
+	existing.Labels = ref.Labels
+	existing.Annotations = ref.Annotations
+	existing.DeletionTimestamp = ref.DeletionTimestamp
+	existing.DeletionGracePeriodSeconds = ref.DeletionGracePeriodSeconds
+	return true
+}
+
 func (s *podStorage) markSourceSet(source string) {
 	s.sourcesSeenLock.Lock()
 	defer s.sourcesSeenLock.Unlock()
@@ -24,6 +24,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/record"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/types"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 )
 
 const (
@@ -246,3 +247,65 @@ func TestNewPodAddedUpdatedSet(t *testing.T) {
 		CreatePodUpdate(kubelet.ADD, NoneSource, CreateValidPod("foo4", "new", "test")),
 		CreatePodUpdate(kubelet.UPDATE, NoneSource, pod))
 }
+
+func TestNewPodAddedGracefullyDeleted(t *testing.T) {
+	channel, ch, _ := createPodConfigTester(PodConfigNotificationIncremental)
+
+	// should register an add
+	podUpdate := CreatePodUpdate(kubelet.ADD, NoneSource, CreateValidPod("foo", "new", ""))
+	channel <- podUpdate
+	expectPodUpdate(t, ch, CreatePodUpdate(kubelet.ADD, NoneSource, CreateValidPod("foo", "new", "test")))
+
+	// a graceful deletion in the apiserver should be delivered as an update
+	pod := CreateValidPod("foo", "new", "test")
+	deletionTimestamp := util.Now()
+	gracePeriod := int64(30)
+	pod.DeletionTimestamp = &deletionTimestamp
+	pod.DeletionGracePeriodSeconds = &gracePeriod
+	podUpdate = CreatePodUpdate(kubelet.ADD, NoneSource, pod)
+	channel <- podUpdate
+	expectPodUpdate(t, ch, CreatePodUpdate(kubelet.UPDATE, NoneSource, pod))
+
+	// should ignore the same deletion seen twice
+	channel <- podUpdate
+	expectNoPodUpdate(t, ch)
+}
+
+func TestStaticPodContentHash(t *testing.T) {
+	config := NewPodConfig(PodConfigNotificationIncremental, record.FromSource(api.EventSource{Component: "kubelet"}))
+	channel := config.Channel(kubelet.FileSource)
+	ch := config.Updates()
+
+	channel <- CreatePodUpdate(kubelet.SET, kubelet.FileSource, CreateValidPod("foo", "new", ""))
+	update := <-ch
+	if update.Op != kubelet.ADD || len(update.Pods) != 1 {
+		t.Fatalf("Expected an add of one pod, got %#v", update)
+	}
+	hash := update.Pods[0].Annotations[kubelet.ConfigHashAnnotationKey]
+	if len(hash) == 0 {
+		t.Fatalf("Expected the static pod to have a content hash, got %#v", update.Pods[0].Annotations)
+	}
+
+	// reading the same content again is a no-op
+	channel <- CreatePodUpdate(kubelet.SET, kubelet.FileSource, CreateValidPod("foo", "new", ""))
+	expectNoPodUpdate(t, ch)
+
+	// a change of the labels only is an update that keeps the spec
+	pod := CreateValidPod("foo", "new", "")
+	pod.Labels = map[string]string{"tier": "control-plane"}
+	channel <- CreatePodUpdate(kubelet.SET, kubelet.FileSource, pod)
+	update = <-ch
+	if update.Op != kubelet.UPDATE || len(update.Pods) != 1 {
+		t.Fatalf("Expected an update of one pod, got %#v", update)
+	}
+	updated := update.Pods[0]
+	if updated.Labels["tier"] != "control-plane" {
+		t.Errorf("Expected the labels to be updated, got %v", updated.Labels)
+	}
+	if updated.UID != "foo" || !api.Semantic.DeepEqual(updated.Spec, CreateValidPod("foo", "new", "").Spec) {
+		t.Errorf("Expected the same pod with the same spec, got %#v", updated)
+	}
+	if newHash := updated.Annotations[kubelet.ConfigHashAnnotationKey]; newHash == hash || len(newHash) == 0 {
+		t.Errorf("Expected a new content hash, got %q", newHash)
+	}
+}
@@ -45,12 +45,25 @@ func NewSourceFile(path string, hostname string, period time.Duration, updates c
 		updates:  updates,
 	}
 	glog.V(1).Infof("Watching path %q", path)
-	go util.Forever(config.run, period)
+	go config.run(period)
 }
 
-func (s *sourceFile) run() {
-	if err := s.extractFromPath(); err != nil {
-		glog.Errorf("Unable to read config path %q: %v", s.path, err)
+// run reads the config path every period, and as soon as it changes where
+// changes can be watched.
+func (s *sourceFile) run(period time.Duration) {
+	defer util.HandleCrash()
+	changes, err := watchPath(s.path)
+	if err != nil {
+		glog.Warningf("Unable to watch config path %q, checking it every %v instead: %v", s.path, period, err)
+	}
+	for {
+		if err := s.extractFromPath(); err != nil {
+			glog.Errorf("Unable to read config path %q: %v", s.path, err)
+		}
+		select {
+		case <-changes:
+		case <-time.After(period):
+		}
 	}
 }
 
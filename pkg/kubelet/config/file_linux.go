@@ -0,0 +1,74 @@
+// +build linux
+
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"path/filepath"
+	"strings"
+
+	"code.google.com/p/go.exp/inotify"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/golang/glog"
+)
+
+const watchFlags = inotify.IN_CREATE | inotify.IN_DELETE | inotify.IN_MODIFY | inotify.IN_CLOSE_WRITE |
+	inotify.IN_MOVED_FROM | inotify.IN_MOVED_TO | inotify.IN_ATTRIB
+
+// watchPath returns a channel that receives a value soon after path, or a
+// file in it if it is a directory, changes. The parent directory of path is
+// watched too, so that path being created, removed or replaced (as editors
+// do when saving a file) is noticed.
+func watchPath(path string) (<-chan struct{}, error) {
+	path = filepath.Clean(path)
+	watcher, err := inotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := watcher.AddWatch(filepath.Dir(path), watchFlags); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+	// path may not exist yet, or may be a file, which the parent covers.
+	watcher.AddWatch(path, watchFlags|inotify.IN_ONLYDIR)
+
+	changes := make(chan struct{}, 1)
+	go func() {
+		defer util.HandleCrash()
+		for {
+			select {
+			case event := <-watcher.Event:
+				if event.Name != path && !strings.HasPrefix(event.Name, path+"/") {
+					continue
+				}
+				glog.V(5).Infof("Config path %q changed: %v", path, event)
+				if event.Name == path && event.Mask&(inotify.IN_CREATE|inotify.IN_MOVED_TO) != 0 {
+					// A new directory needs a new watch.
+					watcher.AddWatch(path, watchFlags|inotify.IN_ONLYDIR)
+				}
+				select {
+				case changes <- struct{}{}:
+				default:
+				}
+			case err := <-watcher.Error:
+				glog.Errorf("Error watching config path %q: %v", path, err)
+			}
+		}
+	}()
+	return changes, nil
+}
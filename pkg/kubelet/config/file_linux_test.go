@@ -0,0 +1,70 @@
+// +build linux
+
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet"
+)
+
+func TestWatchDirForChanges(t *testing.T) {
+	dirName, err := ioutil.TempDir("", "watched")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dirName)
+
+	ch := make(chan interface{})
+	// The period is long enough that only a change notification can explain an update.
+	NewSourceFile(dirName, "localhost", time.Hour, ch)
+	select {
+	case got := <-ch:
+		if update := got.(kubelet.PodUpdate); len(update.Pods) != 0 {
+			t.Fatalf("Expected no pods, got %#v", update)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected update, timeout instead")
+	}
+
+	manifest, _ := ExampleManifestAndPod("1")
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dirName, "manifest"), data, 0644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case got := <-ch:
+			if update := got.(kubelet.PodUpdate); len(update.Pods) == 1 {
+				return
+			}
+		case <-deadline:
+			t.Fatalf("Expected the new manifest to be read, timeout instead")
+		}
+	}
+}
@@ -18,12 +18,12 @@ package container
 
 import (
 	"fmt"
+	"io"
 	"reflect"
 	"sync"
 	"time"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
-	"github.com/GoogleCloudPlatform/kubernetes/pkg/volume"
 )
 
 // FakeRuntime is a fake container runtime for testing.
@@ -31,16 +31,20 @@ type FakeRuntime struct {
 	sync.Mutex
 	CalledFunctions   []string
 	Podlist           []*Pod
-	ContainerList     []*Container
 	PodStatus         api.PodStatus
 	StartedPods       []string
 	KilledPods        []string
 	StartedContainers []string
 	KilledContainers  []string
 	VersionInfo       map[string]string
+	PulledImages      []string
+	ExistingImages    []string
 	Err               error
 }
 
+// FakeRuntime should implement Runtime.
+var _ Runtime = &FakeRuntime{}
+
 type FakeRuntimeCache struct {
 	runtime Runtime
 }
@@ -64,13 +68,14 @@ func (f *FakeRuntime) ClearCalls() {
 
 	f.CalledFunctions = []string{}
 	f.Podlist = []*Pod{}
-	f.ContainerList = []*Container{}
 	f.PodStatus = api.PodStatus{}
 	f.StartedPods = []string{}
 	f.KilledPods = []string{}
 	f.StartedContainers = []string{}
 	f.KilledContainers = []string{}
 	f.VersionInfo = map[string]string{}
+	f.PulledImages = []string{}
+	f.ExistingImages = []string{}
 	f.Err = nil
 }
 
@@ -128,11 +133,11 @@ func (f *FakeRuntime) GetPods(all bool) ([]*Pod, error) {
 	return f.Podlist, f.Err
 }
 
-func (f *FakeRuntime) RunPod(pod *api.Pod, volumeMap map[string]volume.VolumePlugin) error {
+func (f *FakeRuntime) SyncPod(pod *api.Pod, _ Pod, _ api.PodStatus, _ PullSecretsGetter) error {
 	f.Lock()
 	defer f.Unlock()
 
-	f.CalledFunctions = append(f.CalledFunctions, "RunPod")
+	f.CalledFunctions = append(f.CalledFunctions, "SyncPod")
 	f.StartedPods = append(f.StartedPods, string(pod.UID))
 	for _, c := range pod.Spec.Containers {
 		f.StartedContainers = append(f.StartedContainers, c.Name)
@@ -140,64 +145,77 @@ func (f *FakeRuntime) RunPod(pod *api.Pod, volumeMap map[string]volume.VolumePlu
 	return f.Err
 }
 
-func (f *FakeRuntime) KillPod(pod *api.Pod) error {
+func (f *FakeRuntime) KillPod(apiPod *api.Pod, pod Pod) error {
 	f.Lock()
 	defer f.Unlock()
 
 	f.CalledFunctions = append(f.CalledFunctions, "KillPod")
-	f.KilledPods = append(f.KilledPods, string(pod.UID))
-	for _, c := range pod.Spec.Containers {
+	f.KilledPods = append(f.KilledPods, string(pod.ID))
+	for _, c := range pod.Containers {
 		f.KilledContainers = append(f.KilledContainers, c.Name)
 	}
 	return f.Err
 }
 
-func (f *FakeRuntime) RunContainerInPod(container api.Container, pod *api.Pod, volumeMap map[string]volume.VolumePlugin) error {
+func (f *FakeRuntime) GetPodStatus(*api.Pod) (*api.PodStatus, error) {
 	f.Lock()
 	defer f.Unlock()
 
-	f.CalledFunctions = append(f.CalledFunctions, "RunContainerInPod")
-	f.StartedContainers = append(f.StartedContainers, container.Name)
+	f.CalledFunctions = append(f.CalledFunctions, "GetPodStatus")
+	status := f.PodStatus
+	return &status, f.Err
+}
 
-	pod.Spec.Containers = append(pod.Spec.Containers, container)
-	for _, c := range pod.Spec.Containers {
-		if c.Name == container.Name { // Container already in the pod.
-			return f.Err
-		}
-	}
-	pod.Spec.Containers = append(pod.Spec.Containers, container)
+func (f *FakeRuntime) PullImage(image string, _ []api.Secret) error {
+	f.Lock()
+	defer f.Unlock()
+
+	f.CalledFunctions = append(f.CalledFunctions, "PullImage")
+	f.PulledImages = append(f.PulledImages, image)
 	return f.Err
 }
 
-func (f *FakeRuntime) KillContainerInPod(container api.Container, pod *api.Pod) error {
+func (f *FakeRuntime) IsImagePresent(image string) (bool, error) {
 	f.Lock()
 	defer f.Unlock()
 
-	f.CalledFunctions = append(f.CalledFunctions, "KillContainerInPod")
-	f.KilledContainers = append(f.KilledContainers, container.Name)
-
-	var containers []api.Container
-	for _, c := range pod.Spec.Containers {
-		if c.Name == container.Name {
-			continue
+	f.CalledFunctions = append(f.CalledFunctions, "IsImagePresent")
+	for _, i := range f.ExistingImages {
+		if i == image {
+			return true, f.Err
 		}
-		containers = append(containers, c)
 	}
+	return false, f.Err
+}
+
+func (f *FakeRuntime) GetContainerLogs(containerID string, opts *api.PodLogOptions, stdout, stderr io.Writer) error {
+	f.Lock()
+	defer f.Unlock()
+
+	f.CalledFunctions = append(f.CalledFunctions, "GetContainerLogs")
 	return f.Err
 }
 
-func (f *FakeRuntime) GetPodStatus(pod *Pod) (api.PodStatus, error) {
+func (f *FakeRuntime) RunInContainer(containerID string, cmd []string) ([]byte, error) {
 	f.Lock()
 	defer f.Unlock()
 
-	f.CalledFunctions = append(f.CalledFunctions, "GetPodStatus")
-	return f.PodStatus, f.Err
+	f.CalledFunctions = append(f.CalledFunctions, "RunInContainer")
+	return []byte{}, f.Err
+}
+
+func (f *FakeRuntime) ExecInContainer(containerID string, cmd []string, stdin io.Reader, stdout, stderr io.WriteCloser, tty bool) error {
+	f.Lock()
+	defer f.Unlock()
+
+	f.CalledFunctions = append(f.CalledFunctions, "ExecInContainer")
+	return f.Err
 }
 
-func (f *FakeRuntime) GetContainers(all bool) ([]*Container, error) {
+func (f *FakeRuntime) PortForward(pod *Pod, port uint16, stream io.ReadWriteCloser) error {
 	f.Lock()
 	defer f.Unlock()
 
-	f.CalledFunctions = append(f.CalledFunctions, "GetContainers")
-	return f.ContainerList, f.Err
+	f.CalledFunctions = append(f.CalledFunctions, "PortForward")
+	return f.Err
 }
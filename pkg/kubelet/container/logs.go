@@ -0,0 +1,69 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package container
+
+import (
+	"errors"
+	"io"
+	"sync"
+)
+
+// ErrLogLimitReached is returned by the writers of LimitLogs once the limit
+// of bytes is reached. Runtimes should stop writing logs and return success.
+var ErrLogLimitReached = errors.New("log limit reached")
+
+// LimitLogs wraps the stdout and stderr of a container's logs so that at
+// most limit bytes are written to them in total. A limit lower than or equal
+// to zero means no limit.
+func LimitLogs(limit int64, stdout, stderr io.Writer) (io.Writer, io.Writer) {
+	if limit <= 0 {
+		return stdout, stderr
+	}
+	remaining := &logLimit{remaining: limit}
+	return &limitWriter{stdout, remaining}, &limitWriter{stderr, remaining}
+}
+
+// logLimit is the number of bytes that can still be written, shared by the
+// stdout and stderr writers.
+type logLimit struct {
+	sync.Mutex
+	remaining int64
+}
+
+type limitWriter struct {
+	out   io.Writer
+	limit *logLimit
+}
+
+func (w *limitWriter) Write(p []byte) (int, error) {
+	w.limit.Lock()
+	defer w.limit.Unlock()
+	if w.limit.remaining <= 0 {
+		return 0, ErrLogLimitReached
+	}
+	truncated := false
+	if int64(len(p)) > w.limit.remaining {
+		p = p[:w.limit.remaining]
+		truncated = true
+	}
+	n, err := w.out.Write(p)
+	w.limit.remaining -= int64(n)
+	if err == nil && truncated {
+		err = ErrLogLimitReached
+	}
+	return n, err
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package container
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestLimitLogs(t *testing.T) {
+	testCases := []struct {
+		limit          int64
+		expectedStdout string
+		expectedStderr string
+		expectedErr    error
+	}{
+		{0, "hello\nworld\n", "oops\n", nil},
+		{17, "hello\nworld\n", "oops\n", nil},
+		{8, "hello\n", "oo", ErrLogLimitReached},
+		{14, "hello\nwor", "oops\n", ErrLogLimitReached},
+	}
+	for i, tc := range testCases {
+		stdoutBuf, stderrBuf := &bytes.Buffer{}, &bytes.Buffer{}
+		stdout, stderr := LimitLogs(tc.limit, stdoutBuf, stderrBuf)
+		var err error
+		for _, w := range []struct {
+			out  io.Writer
+			data string
+		}{{stdout, "hello\n"}, {stderr, "oops\n"}, {stdout, "world\n"}} {
+			if _, err = w.out.Write([]byte(w.data)); err != nil {
+				break
+			}
+		}
+		if err != tc.expectedErr {
+			t.Errorf("%d: expected error %v, got %v", i, tc.expectedErr, err)
+		}
+		if stdoutBuf.String() != tc.expectedStdout {
+			t.Errorf("%d: expected stdout %q, got %q", i, tc.expectedStdout, stdoutBuf.String())
+		}
+		if stderrBuf.String() != tc.expectedStderr {
+			t.Errorf("%d: expected stderr %q, got %q", i, tc.expectedStderr, stderrBuf.String())
+		}
+	}
+}
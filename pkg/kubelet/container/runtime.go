@@ -17,14 +17,25 @@ limitations under the License.
 package container
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"reflect"
 	"strings"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/probe"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/types"
-	"github.com/GoogleCloudPlatform/kubernetes/pkg/volume"
 )
 
+// ErrNoContainersInPod is returned when there are no containers for a given pod.
+var ErrNoContainersInPod = errors.New("no containers exist for this pod")
+
+// PullSecretsGetter returns the image pull secrets of a pod. Runtimes call it
+// only when an image has to be pulled, so syncing a pod whose images are
+// present does not fetch its secrets.
+type PullSecretsGetter func() []api.Secret
+
 // Runtime interface defines the interfaces that should be implemented
 // by a container runtime.
 type Runtime interface {
@@ -34,22 +45,87 @@ type Runtime interface {
 	// specifies whether the runtime returns all containers including those already
 	// exited and dead containers (used for garbage collection).
 	GetPods(all bool) ([]*Pod, error)
-	// RunPod starts all the containers of a pod within a namespace.
-	RunPod(*api.Pod, map[string]volume.Volume) error
-	// KillPod kills all the containers of a pod.
-	KillPod(*api.Pod) error
-	// RunContainerInPod starts a container within the same namespace of a pod.
-	RunContainerInPod(api.Container, *api.Pod, map[string]volume.Volume) error
-	// KillContainerInPod kills a container in the pod.
-	KillContainerInPod(api.Container, *api.Pod) error
+	// SyncPod syncs the running pod into the desired pod. It is responsible
+	// for starting, restarting and killing the containers of the pod, as
+	// well as pulling the images they need with the pod's pull secrets.
+	SyncPod(pod *api.Pod, runningPod Pod, podStatus api.PodStatus, pullSecrets PullSecretsGetter) error
+	// KillPod kills all the containers of a pod. The pod is used to honor its
+	// termination grace period and preStop hooks, and may be nil if it is not known.
+	KillPod(pod *api.Pod, runningPod Pod) error
 	// GetPodStatus retrieves the status of the pod, including the information of
 	// all containers in the pod.
-	GetPodStatus(*Pod) (api.PodStatus, error)
-	// GetContainers returns all containers on the node, including those are
-	// not managed by kubelet. If 'all' is false, then only running containers
-	// are returned.
-	GetContainers(all bool) ([]*Container, error)
-	// TODO(yifan): Pull/Remove images
+	GetPodStatus(*api.Pod) (*api.PodStatus, error)
+	// PullImage pulls an image from the network to local storage, using the
+	// registry credentials of the given dockercfg secrets if needed.
+	PullImage(image string, pullSecrets []api.Secret) error
+	// IsImagePresent checks whether the container image is already in the local storage.
+	IsImagePresent(image string) (bool, error)
+	// GetContainerLogs returns logs of a specific container. By
+	// default, it returns a snapshot of the container log. Set opts.Follow to
+	// true to stream the log. Set opts.Follow to false and specify the number of
+	// lines (e.g. "100" or "all") in opts.Tail to tail the log. See
+	// api.PodLogOptions for the other options.
+	GetContainerLogs(containerID string, opts *api.PodLogOptions, stdout, stderr io.Writer) error
+	// ContainerCommandRunner encapsulates the command runner interfaces for testability.
+	ContainerCommandRunner
+}
+
+// ContainerCommandRunner encapsulates the command runner interfaces for testability.
+type ContainerCommandRunner interface {
+	// RunInContainer runs the command in the container, and returns the
+	// combined stdout and stderr as an array of bytes.
+	RunInContainer(containerID string, cmd []string) ([]byte, error)
+	// ExecInContainer executes a command in a container, connecting the
+	// supplied stdin/stdout/stderr to the command's IO streams.
+	ExecInContainer(containerID string, cmd []string, stdin io.Reader, stdout, stderr io.WriteCloser, tty bool) error
+	// PortForward connects to the port of the pod and copies data between
+	// the port and the stream.
+	PortForward(pod *Pod, port uint16, stream io.ReadWriteCloser) error
+}
+
+// RunContainerOptions specify the options which are necessary for running containers.
+type RunContainerOptions struct {
+	// The environment variables, they are in the form of 'key=value'.
+	Envs []string
+	// The mounts for the containers.
+	Mounts []Mount
+	// If the container has specified the TerminationMessagePath, then
+	// this directory will be used to create and mount the log file to
+	// container.TerminationMessagePath.
+	PodContainerDir string
+	// The list of DNS servers for the container to use.
+	DNS []string
+	// The list of DNS search domains.
+	DNSSearch []string
+}
+
+// Mount is a volume which is mounted into a container.
+type Mount struct {
+	// Name of the volume mount.
+	Name string
+	// Path of the mount within the container.
+	ContainerPath string
+	// Path of the mount on the host.
+	HostPath string
+	// Whether the mount is read-only.
+	ReadOnly bool
+}
+
+// RunContainerOptionsGenerator generates the options that are necessary for
+// the container runtime to run a container. It is implemented by the kubelet,
+// which owns the volumes, the service environment and the DNS settings.
+type RunContainerOptionsGenerator interface {
+	GenerateRunContainerOptions(pod *api.Pod, container *api.Container) (*RunContainerOptions, error)
+}
+
+// HandlerRunner runs a lifecycle handler for a container.
+type HandlerRunner interface {
+	Run(containerID string, pod *api.Pod, container *api.Container, handler *api.Handler) error
+}
+
+// Prober checks the liveness and readiness of a container.
+type Prober interface {
+	Probe(pod *api.Pod, status api.PodStatus, container api.Container, containerID string, createdAt int64) (probe.Result, error)
 }
 
 // Pod is a group of containers, with the status of the pod.
@@ -103,6 +179,24 @@ func (p Pods) FindPodByID(podUID types.UID) Pod {
 	return Pod{}
 }
 
+// FindPod returns a pod in the pod list by the full name of the pod. If the
+// UID is not empty, it must match as well. It will return an empty pod if not
+// found.
+func (p Pods) FindPod(podFullName string, podUID types.UID) Pod {
+	for i := range p {
+		if BuildPodFullName(p[i].Name, p[i].Namespace) == podFullName &&
+			(podUID == "" || p[i].ID == podUID) {
+			return *p[i]
+		}
+	}
+	return Pod{}
+}
+
+// IsEmpty returns true if the pod is empty.
+func (p *Pod) IsEmpty() bool {
+	return reflect.DeepEqual(p, &Pod{})
+}
+
 // FindContainerByName returns a container in the pod with the given name.
 // When there are multiple containers with the same name, the first match will
 // be returned.
@@ -135,3 +229,15 @@ func ParsePodFullName(podFullName string) (string, string, error) {
 	}
 	return parts[0], parts[1], nil
 }
+
+// TrimRuntimePrefix strips the runtime scheme (e.g. "docker://") from a
+// container ID as reported in api.ContainerStatus.
+func TrimRuntimePrefix(fullString string) string {
+	const prefixSeparator = "://"
+
+	idx := strings.Index(fullString, prefixSeparator)
+	if idx < 0 {
+		return fullString
+	}
+	return fullString[idx+len(prefixSeparator):]
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/dockertools"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/errors"
+	"github.com/fsouza/go-dockerclient"
+	"github.com/golang/glog"
+)
+
+// Specified a policy for rotating the logs of containers.
+type ContainerLogPolicy struct {
+	// Size in bytes at which the log of a container is rotated, zero or less
+	// for no rotation.
+	MaxSize int64
+
+	// Number of rotated logs to keep for every container, the oldest ones are
+	// deleted. Zero means the log is truncated.
+	MaxFiles int
+
+	// Root directory of Docker, under which it stores the logs of containers.
+	DockerRoot string
+}
+
+// Manages rotation of the logs of running containers.
+//
+// Implementation is thread-compatible.
+type containerLogManager interface {
+	// Rotate the logs that grew over the size limit.
+	RotateLogs() error
+}
+
+type realContainerLogManager struct {
+	// Docker client to use.
+	dockerClient dockertools.DockerInterface
+
+	// Policy for log rotation.
+	policy ContainerLogPolicy
+}
+
+// New containerLogManager instance with the specified policy.
+func newContainerLogManager(dockerClient dockertools.DockerInterface, policy ContainerLogPolicy) (containerLogManager, error) {
+	if policy.MaxFiles < 0 {
+		return nil, fmt.Errorf("invalid number of rotated container logs: %d", policy.MaxFiles)
+	}
+	if policy.MaxSize > 0 && policy.DockerRoot == "" {
+		return nil, fmt.Errorf("the Docker root directory is required to rotate container logs")
+	}
+
+	return &realContainerLogManager{
+		dockerClient: dockerClient,
+		policy:       policy,
+	}, nil
+}
+
+// Path to the log Docker writes for the container with the specified ID.
+func (self *realContainerLogManager) logPath(id string) string {
+	return path.Join(self.policy.DockerRoot, "containers", id, id+"-json.log")
+}
+
+func (self *realContainerLogManager) RotateLogs() error {
+	if self.policy.MaxSize <= 0 {
+		return nil
+	}
+
+	// Only running containers still write logs.
+	containers, err := self.dockerClient.ListContainers(docker.ListContainersOptions{})
+	if err != nil {
+		return err
+	}
+
+	errs := []error{}
+	for _, container := range containers {
+		// Leave the containers we don't manage alone.
+		if len(container.Names) == 0 {
+			continue
+		}
+		if _, _, err := dockertools.ParseDockerName(container.Names[0]); err != nil {
+			continue
+		}
+
+		logPath := self.logPath(container.ID)
+		info, err := os.Stat(logPath)
+		if err != nil {
+			// Containers using another log driver have no log file.
+			if !os.IsNotExist(err) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		if info.Size() < self.policy.MaxSize {
+			continue
+		}
+		glog.V(2).Infof("Rotating log of container %q of size %d", container.ID, info.Size())
+		if err := rotateLog(logPath, self.policy.MaxFiles); err != nil {
+			errs = append(errs, fmt.Errorf("failed to rotate log of container %q: %v", container.ID, err))
+		}
+	}
+	return errors.NewAggregate(errs)
+}
+
+// rotateLog copies the log at the specified path to "<path>.1", shifting the
+// older copies up to "<path>.<maxFiles>", and truncates it. The log is not
+// moved since Docker keeps it open.
+func rotateLog(logPath string, maxFiles int) error {
+	if maxFiles > 0 {
+		for i := maxFiles - 1; i > 0; i-- {
+			err := os.Rename(fmt.Sprintf("%s.%d", logPath, i), fmt.Sprintf("%s.%d", logPath, i+1))
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		if err := copyFile(logPath, logPath+".1"); err != nil {
+			return err
+		}
+	}
+	return os.Truncate(logPath, 0)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
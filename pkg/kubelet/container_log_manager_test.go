@@ -0,0 +1,123 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/dockertools"
+	"github.com/fsouza/go-dockerclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestContainerLogManager(t *testing.T, maxSize int64, maxFiles int) (containerLogManager, *dockertools.FakeDockerClient, string) {
+	dockerRoot, err := ioutil.TempDir("", "container_log_manager")
+	require.Nil(t, err)
+	fakeDocker := new(dockertools.FakeDockerClient)
+	manager, err := newContainerLogManager(fakeDocker, ContainerLogPolicy{
+		MaxSize:    maxSize,
+		MaxFiles:   maxFiles,
+		DockerRoot: dockerRoot,
+	})
+	require.Nil(t, err)
+	return manager, fakeDocker, dockerRoot
+}
+
+// Writes the log of the container with the specified Docker ID and returns its path.
+func writeContainerLog(t *testing.T, dockerRoot, id, content string) string {
+	dir := path.Join(dockerRoot, "containers", id)
+	require.Nil(t, os.MkdirAll(dir, 0750))
+	logPath := path.Join(dir, id+"-json.log")
+	require.Nil(t, ioutil.WriteFile(logPath, []byte(content), 0640))
+	return logPath
+}
+
+func readContainerLog(t *testing.T, logPath string) string {
+	data, err := ioutil.ReadFile(logPath)
+	require.Nil(t, err)
+	return string(data)
+}
+
+func TestRotateLogsInvalidPolicy(t *testing.T) {
+	_, err := newContainerLogManager(new(dockertools.FakeDockerClient), ContainerLogPolicy{MaxSize: 10, MaxFiles: -1, DockerRoot: "/var/lib/docker"})
+	assert.NotNil(t, err)
+	_, err = newContainerLogManager(new(dockertools.FakeDockerClient), ContainerLogPolicy{MaxSize: 10, MaxFiles: 2})
+	assert.NotNil(t, err)
+}
+
+func TestRotateLogsUnderLimit(t *testing.T) {
+	manager, fakeDocker, dockerRoot := newTestContainerLogManager(t, 10, 2)
+	defer os.RemoveAll(dockerRoot)
+	fakeDocker.ContainerList = []docker.APIContainers{makeAPIContainer("foo", "bar", "1876")}
+	logPath := writeContainerLog(t, dockerRoot, "1876", "short\n")
+
+	assert.Nil(t, manager.RotateLogs())
+	assert.Equal(t, "short\n", readContainerLog(t, logPath))
+	_, err := os.Stat(logPath + ".1")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRotateLogsKeepsMaxFiles(t *testing.T) {
+	manager, fakeDocker, dockerRoot := newTestContainerLogManager(t, 10, 2)
+	defer os.RemoveAll(dockerRoot)
+	fakeDocker.ContainerList = []docker.APIContainers{makeAPIContainer("foo", "bar", "1876")}
+
+	logPath := writeContainerLog(t, dockerRoot, "1876", "first log line\n")
+	assert.Nil(t, manager.RotateLogs())
+	assert.Equal(t, "", readContainerLog(t, logPath))
+	assert.Equal(t, "first log line\n", readContainerLog(t, logPath+".1"))
+
+	writeContainerLog(t, dockerRoot, "1876", "second log line\n")
+	assert.Nil(t, manager.RotateLogs())
+	writeContainerLog(t, dockerRoot, "1876", "third log line\n")
+	assert.Nil(t, manager.RotateLogs())
+	assert.Equal(t, "", readContainerLog(t, logPath))
+	assert.Equal(t, "third log line\n", readContainerLog(t, logPath+".1"))
+	assert.Equal(t, "second log line\n", readContainerLog(t, logPath+".2"))
+	_, err := os.Stat(logPath + ".3")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRotateLogsWithoutFiles(t *testing.T) {
+	manager, fakeDocker, dockerRoot := newTestContainerLogManager(t, 10, 0)
+	defer os.RemoveAll(dockerRoot)
+	fakeDocker.ContainerList = []docker.APIContainers{makeAPIContainer("foo", "bar", "1876")}
+	logPath := writeContainerLog(t, dockerRoot, "1876", "first log line\n")
+
+	assert.Nil(t, manager.RotateLogs())
+	assert.Equal(t, "", readContainerLog(t, logPath))
+	_, err := os.Stat(logPath + ".1")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRotateLogsIgnoresUnmanagedContainers(t *testing.T) {
+	manager, fakeDocker, dockerRoot := newTestContainerLogManager(t, 10, 2)
+	defer os.RemoveAll(dockerRoot)
+	fakeDocker.ContainerList = []docker.APIContainers{
+		{ID: "1876", Names: []string{"/unmanaged"}},
+		// No log file, e.g. another log driver.
+		makeAPIContainer("foo", "bar", "2876"),
+	}
+	logPath := writeContainerLog(t, dockerRoot, "1876", "first log line\n")
+
+	assert.Nil(t, manager.RotateLogs())
+	assert.Equal(t, "first log line\n", readContainerLog(t, logPath))
+}
@@ -29,11 +29,13 @@ import (
 	"os/exec"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/credentialprovider"
 	kubecontainer "github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/container"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/leaky"
+	kubeletTypes "github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/types"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/types"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/docker/docker/pkg/parsers"
@@ -64,9 +66,6 @@ type DockerInterface interface {
 	StartExec(string, docker.StartExecOptions) error
 }
 
-// DockerID is an ID of docker container. It is a type to make it clear when we're working with docker container Ids
-type DockerID string
-
 type KubeletContainerName struct {
 	PodFullName   string
 	PodUID        types.UID
@@ -75,7 +74,7 @@ type KubeletContainerName struct {
 
 // DockerPuller is an abstract interface for testability.  It abstracts image pull operations.
 type DockerPuller interface {
-	Pull(image string) error
+	Pull(image string, secrets []api.Secret) error
 	IsImagePresent(image string) (bool, error)
 }
 
@@ -336,7 +335,7 @@ func parseImageName(image string) (string, string) {
 	return parsers.ParseRepositoryTag(image)
 }
 
-func (p dockerPuller) Pull(image string) error {
+func (p dockerPuller) Pull(image string, secrets []api.Secret) error {
 	repoToPull, tag := parseImageName(image)
 
 	// If no tag was specified, use the default "latest".
@@ -349,12 +348,17 @@ func (p dockerPuller) Pull(image string) error {
 		Tag:        tag,
 	}
 
-	creds, ok := p.keyring.Lookup(repoToPull)
+	keyring, err := credentialprovider.MakeDockerKeyring(secrets, p.keyring)
+	if err != nil {
+		return err
+	}
+
+	creds, ok := keyring.Lookup(repoToPull)
 	if !ok {
 		glog.V(1).Infof("Pulling image %s without credentials", image)
 	}
 
-	err := p.client.PullImage(opts, creds)
+	err = p.client.PullImage(opts, creds)
 	// If there was no error, or we had credentials, just return the error.
 	if err == nil || ok {
 		return err
@@ -370,9 +374,9 @@ func (p dockerPuller) Pull(image string) error {
 	return err
 }
 
-func (p throttledDockerPuller) Pull(image string) error {
+func (p throttledDockerPuller) Pull(image string, secrets []api.Secret) error {
 	if p.limiter.CanAccept() {
-		return p.puller.Pull(image)
+		return p.puller.Pull(image, secrets)
 	}
 	return fmt.Errorf("pull QPS exceeded.")
 }
@@ -395,7 +399,7 @@ func (p throttledDockerPuller) IsImagePresent(name string) (bool, error) {
 }
 
 // DockerContainers is a map of containers
-type DockerContainers map[DockerID]*docker.APIContainers
+type DockerContainers map[kubeletTypes.DockerID]*docker.APIContainers
 
 func (c DockerContainers) FindPodContainer(podFullName string, uid types.UID, containerName string) (*docker.APIContainers, bool, uint64) {
 	for _, dockerContainer := range c {
@@ -417,7 +421,7 @@ func (c DockerContainers) FindPodContainer(podFullName string, uid types.UID, co
 }
 
 // RemoveContainerWithID removes the container with the given containerID.
-func (c DockerContainers) RemoveContainerWithID(containerID DockerID) {
+func (c DockerContainers) RemoveContainerWithID(containerID kubeletTypes.DockerID) {
 	delete(c, containerID)
 }
 
@@ -434,7 +438,7 @@ func (c DockerContainers) FindContainersByPod(podUID types.UID, podFullName stri
 		}
 		if podUID == dockerName.PodUID ||
 			(podUID == "" && podFullName == dockerName.PodFullName) {
-			containers[DockerID(dockerContainer.ID)] = dockerContainer
+			containers[kubeletTypes.DockerID(dockerContainer.ID)] = dockerContainer
 		}
 	}
 	return containers
@@ -461,7 +465,7 @@ func GetKubeletDockerContainers(client DockerInterface, allContainers bool) (Doc
 			glog.V(3).Infof("Docker Container: %s is not managed by kubelet.", container.Names[0])
 			continue
 		}
-		result[DockerID(container.ID)] = container
+		result[kubeletTypes.DockerID(container.ID)] = container
 	}
 	return result, nil
 }
@@ -501,32 +505,101 @@ func GetRecentDockerContainersWithNameAndUUID(client DockerInterface, podFullNam
 
 // GetKubeletDockerContainerLogs returns logs of specific container
 // By default the function will return snapshot of the container log
-// Log streaming is possible if 'follow' param is set to true
-// Log tailing is possible when number of tailed lines are set and only if 'follow' is false
+// Log streaming is possible if 'Follow' option is set to true
+// Log tailing is possible when number of tailed lines are set and only if 'Follow' is false
 // TODO: Make 'RawTerminal' option  flagable.
-func GetKubeletDockerContainerLogs(client DockerInterface, containerID, tail string, follow bool, stdout, stderr io.Writer) (err error) {
+func GetKubeletDockerContainerLogs(client DockerInterface, containerID string, logOpts *api.PodLogOptions, stdout, stderr io.Writer) (err error) {
+	stdout, stderr = kubecontainer.LimitLogs(logOpts.LimitBytes, stdout, stderr)
+	// Docker can't filter logs by time, so ask for timestamps and drop the
+	// older lines ourselves.
+	var stdoutFilter, stderrFilter *sinceTimeFilter
+	if logOpts.SinceTime != nil {
+		stdoutFilter = &sinceTimeFilter{out: stdout, since: logOpts.SinceTime.Time, timestamps: logOpts.ShowTimestamps()}
+		stderrFilter = &sinceTimeFilter{out: stderr, since: logOpts.SinceTime.Time, timestamps: logOpts.ShowTimestamps()}
+		stdout, stderr = stdoutFilter, stderrFilter
+	}
+
 	opts := docker.LogsOptions{
 		Container:    containerID,
 		Stdout:       true,
 		Stderr:       true,
 		OutputStream: stdout,
 		ErrorStream:  stderr,
-		Timestamps:   true,
+		Timestamps:   logOpts.ShowTimestamps() || logOpts.SinceTime != nil,
 		RawTerminal:  false,
-		Follow:       follow,
+		Follow:       logOpts.Follow,
 	}
 
-	if !follow {
-		opts.Tail = tail
+	if !logOpts.Follow {
+		opts.Tail = logOpts.Tail
 	}
 
 	err = client.Logs(opts)
+	if err == nil && stdoutFilter != nil {
+		if err = stdoutFilter.Flush(); err == nil {
+			err = stderrFilter.Flush()
+		}
+	}
+	if err == kubecontainer.ErrLogLimitReached {
+		err = nil
+	}
 	return
 }
 
+// sinceTimeFilter is a writer for docker logs with timestamps which drops the
+// lines logged before a given time, and strips the timestamps unless they were
+// asked for.
+type sinceTimeFilter struct {
+	out        io.Writer
+	since      time.Time
+	timestamps bool
+	// line holds the start of a line until its end is written.
+	line []byte
+}
+
+func (f *sinceTimeFilter) Write(p []byte) (int, error) {
+	f.line = append(f.line, p...)
+	for {
+		i := bytes.IndexByte(f.line, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		if err := f.writeLine(f.line[:i+1]); err != nil {
+			return 0, err
+		}
+		f.line = f.line[i+1:]
+	}
+}
+
+// Flush writes the last line if it didn't end with a newline.
+func (f *sinceTimeFilter) Flush() error {
+	if len(f.line) == 0 {
+		return nil
+	}
+	line := f.line
+	f.line = nil
+	return f.writeLine(line)
+}
+
+func (f *sinceTimeFilter) writeLine(line []byte) error {
+	// Docker prefixes lines with an RFC3339Nano timestamp and a space.
+	if i := bytes.IndexByte(line, ' '); i > 0 {
+		if t, err := time.Parse(time.RFC3339Nano, string(line[:i])); err == nil {
+			if t.Before(f.since) {
+				return nil
+			}
+			if !f.timestamps {
+				line = line[i+1:]
+			}
+		}
+	}
+	_, err := f.out.Write(line)
+	return err
+}
+
 var (
 	// ErrNoContainersInPod is returned when there are no containers for a given pod
-	ErrNoContainersInPod = errors.New("no containers exist for this pod")
+	ErrNoContainersInPod = kubecontainer.ErrNoContainersInPod
 
 	// ErrNoPodInfraContainerInPod is returned when there is no pod infra container for a given pod
 	ErrNoPodInfraContainerInPod = errors.New("No pod infra container exists for this pod")
@@ -583,10 +656,11 @@ func inspectContainer(client DockerInterface, dockerID, containerName, tPath str
 			reason = inspectResult.State.Error
 		}
 		result.status.State.Termination = &api.ContainerStateTerminated{
-			ExitCode:   inspectResult.State.ExitCode,
-			Reason:     reason,
-			StartedAt:  util.NewTime(inspectResult.State.StartedAt),
-			FinishedAt: util.NewTime(inspectResult.State.FinishedAt),
+			ExitCode:    inspectResult.State.ExitCode,
+			Reason:      reason,
+			StartedAt:   util.NewTime(inspectResult.State.StartedAt),
+			FinishedAt:  util.NewTime(inspectResult.State.FinishedAt),
+			ContainerID: DockerPrefix + dockerID,
 		}
 		if tPath != "" {
 			path, found := inspectResult.Volumes[tPath]
@@ -657,6 +731,14 @@ func GetDockerPodStatus(client DockerInterface, manifest api.PodSpec, podFullNam
 		// We assume docker return us a list of containers in time order
 		if containerStatus, found := podStatus.Info[dockerContainerName]; found {
 			containerStatus.RestartCount += 1
+			// The first older instance is the previous one, remember how it
+			// terminated so its logs can be found.
+			if containerStatus.LastTerminationState.Termination == nil {
+				result := inspectContainer(client, value.ID, dockerContainerName, terminationMessagePath)
+				if result.err == nil && result.status.State.Termination != nil {
+					containerStatus.LastTerminationState = result.status.State
+				}
+			}
 			podStatus.Info[dockerContainerName] = containerStatus
 			continue
 		}
@@ -17,10 +17,12 @@ limitations under the License.
 package dockertools
 
 import (
+	"bytes"
 	"fmt"
 	"hash/adler32"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/credentialprovider"
@@ -222,7 +224,7 @@ func TestPull(t *testing.T) {
 			keyring: fakeKeyring,
 		}
 
-		err := dp.Pull(test.imageName)
+		err := dp.Pull(test.imageName, nil)
 		if err != nil {
 			t.Errorf("unexpected non-nil err: %s", err)
 			continue
@@ -250,7 +252,7 @@ func TestDockerKeyringLookupFails(t *testing.T) {
 		keyring: fakeKeyring,
 	}
 
-	err := dp.Pull("host/repository/image:version")
+	err := dp.Pull("host/repository/image:version", nil)
 	if err == nil {
 		t.Errorf("unexpected non-error")
 	}
@@ -637,3 +639,59 @@ func TestFindContainersByPod(t *testing.T) {
 		}
 	}
 }
+
+func TestPullWithSecrets(t *testing.T) {
+	fakeKeyring := &credentialprovider.FakeKeyring{}
+	fakeClient := &FakeDockerClient{
+		Err: fmt.Errorf("test error"),
+	}
+
+	dp := dockerPuller{
+		client:  fakeClient,
+		keyring: fakeKeyring,
+	}
+
+	secrets := []api.Secret{
+		{
+			Type: api.SecretTypeDockercfg,
+			Data: map[string][]byte{
+				api.DockerConfigKey: []byte(`{"host": {"username": "user", "password": "pass", "email": "user@example.com"}}`),
+			},
+		},
+	}
+	err := dp.Pull("host/repository/image:version", secrets)
+	// Credentials were found in the secret, so the error is not decorated.
+	if err == nil || err.Error() != "test error" {
+		t.Errorf("expected the pull error without decoration, saw: %v", err)
+	}
+}
+
+func TestSinceTimeFilter(t *testing.T) {
+	since := time.Date(2015, 5, 1, 10, 0, 0, 0, time.UTC)
+	logs := "2015-05-01T09:59:59.999999999Z too old\n" +
+		"2015-05-01T10:00:00Z just in time\n" +
+		"2015-05-01T10:00:01.5Z la"
+	testCases := []struct {
+		timestamps bool
+		expected   string
+	}{
+		{false, "just in time\nlast\n"},
+		{true, "2015-05-01T10:00:00Z just in time\n2015-05-01T10:00:01.5Z last\n"},
+	}
+	for i, tc := range testCases {
+		var out bytes.Buffer
+		filter := &sinceTimeFilter{out: &out, since: since, timestamps: tc.timestamps}
+		// Lines may be split across writes.
+		for _, data := range []string{logs, "st\n"} {
+			if n, err := filter.Write([]byte(data)); err != nil || n != len(data) {
+				t.Errorf("%d: unexpected write result: %d, %v", i, n, err)
+			}
+		}
+		if err := filter.Flush(); err != nil {
+			t.Errorf("%d: unexpected error: %v", i, err)
+		}
+		if out.String() != tc.expected {
+			t.Errorf("%d: expected %q, got %q", i, tc.expected, out.String())
+		}
+	}
+}
@@ -20,9 +20,8 @@ import (
 	"fmt"
 	"reflect"
 	"sync"
-	"time"
 
-	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/container"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/fsouza/go-dockerclient"
 )
@@ -38,6 +37,7 @@ type FakeDockerClient struct {
 	Err           error
 	called        []string
 	Stopped       []string
+	StopTimeouts  []uint
 	pulled        []string
 	Created       []string
 	Removed       []string
@@ -50,6 +50,7 @@ func (f *FakeDockerClient) ClearCalls() {
 	defer f.Unlock()
 	f.called = []string{}
 	f.Stopped = []string{}
+	f.StopTimeouts = []uint{}
 	f.pulled = []string{}
 	f.Created = []string{}
 	f.Removed = []string{}
@@ -139,6 +140,7 @@ func (f *FakeDockerClient) StopContainer(id string, timeout uint) error {
 	defer f.Unlock()
 	f.called = append(f.called, "stop")
 	f.Stopped = append(f.Stopped, id)
+	f.StopTimeouts = append(f.StopTimeouts, timeout)
 	var newList []docker.APIContainers
 	for _, container := range f.ContainerList {
 		if container.ID != id {
@@ -214,7 +216,7 @@ type FakeDockerPuller struct {
 }
 
 // Pull records the image pull attempt, and optionally injects an error.
-func (f *FakeDockerPuller) Pull(image string) (err error) {
+func (f *FakeDockerPuller) Pull(image string, secrets []api.Secret) (err error) {
 	f.Lock()
 	defer f.Unlock()
 	f.ImagesPulled = append(f.ImagesPulled, image)
@@ -239,21 +241,3 @@ func (f *FakeDockerPuller) IsImagePresent(name string) (bool, error) {
 	}
 	return false, nil
 }
-
-type FakeDockerCache struct {
-	client DockerInterface
-}
-
-func NewFakeDockerCache(client DockerInterface) DockerCache {
-	return &FakeDockerCache{
-		client: client,
-	}
-}
-
-func (f *FakeDockerCache) GetPods() ([]*container.Pod, error) {
-	return GetPods(f.client, false)
-}
-
-func (f *FakeDockerCache) ForceUpdateIfOlder(time.Time) error {
-	return nil
-}
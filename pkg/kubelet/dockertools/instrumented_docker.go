@@ -14,23 +14,23 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package metrics
+package dockertools
 
 import (
 	"time"
 
-	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/dockertools"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/metrics"
 	docker "github.com/fsouza/go-dockerclient"
 )
 
-var _ dockertools.DockerInterface = instrumentedDockerInterface{}
+var _ DockerInterface = instrumentedDockerInterface{}
 
 type instrumentedDockerInterface struct {
-	client dockertools.DockerInterface
+	client DockerInterface
 }
 
 // Creates an instrumented DockerInterface from an existing DockerInterface.
-func NewInstrumentedDockerInterface(dockerClient dockertools.DockerInterface) dockertools.DockerInterface {
+func NewInstrumentedDockerInterface(dockerClient DockerInterface) DockerInterface {
 	return instrumentedDockerInterface{
 		client: dockerClient,
 	}
@@ -39,7 +39,7 @@ func NewInstrumentedDockerInterface(dockerClient dockertools.DockerInterface) do
 func (self instrumentedDockerInterface) ListContainers(options docker.ListContainersOptions) ([]docker.APIContainers, error) {
 	start := time.Now()
 	defer func() {
-		DockerOperationsLatency.WithLabelValues("list_containers").Observe(SinceInMicroseconds(start))
+		metrics.DockerOperationsLatency.WithLabelValues("list_containers").Observe(metrics.SinceInMicroseconds(start))
 	}()
 	return self.client.ListContainers(options)
 }
@@ -47,7 +47,7 @@ func (self instrumentedDockerInterface) ListContainers(options docker.ListContai
 func (self instrumentedDockerInterface) InspectContainer(id string) (*docker.Container, error) {
 	start := time.Now()
 	defer func() {
-		DockerOperationsLatency.WithLabelValues("inspect_container").Observe(SinceInMicroseconds(start))
+		metrics.DockerOperationsLatency.WithLabelValues("inspect_container").Observe(metrics.SinceInMicroseconds(start))
 	}()
 	return self.client.InspectContainer(id)
 }
@@ -55,7 +55,7 @@ func (self instrumentedDockerInterface) InspectContainer(id string) (*docker.Con
 func (self instrumentedDockerInterface) CreateContainer(opts docker.CreateContainerOptions) (*docker.Container, error) {
 	start := time.Now()
 	defer func() {
-		DockerOperationsLatency.WithLabelValues("create_container").Observe(SinceInMicroseconds(start))
+		metrics.DockerOperationsLatency.WithLabelValues("create_container").Observe(metrics.SinceInMicroseconds(start))
 	}()
 	return self.client.CreateContainer(opts)
 }
@@ -63,7 +63,7 @@ func (self instrumentedDockerInterface) CreateContainer(opts docker.CreateContai
 func (self instrumentedDockerInterface) StartContainer(id string, hostConfig *docker.HostConfig) error {
 	start := time.Now()
 	defer func() {
-		DockerOperationsLatency.WithLabelValues("start_container").Observe(SinceInMicroseconds(start))
+		metrics.DockerOperationsLatency.WithLabelValues("start_container").Observe(metrics.SinceInMicroseconds(start))
 	}()
 	return self.client.StartContainer(id, hostConfig)
 }
@@ -71,7 +71,7 @@ func (self instrumentedDockerInterface) StartContainer(id string, hostConfig *do
 func (self instrumentedDockerInterface) StopContainer(id string, timeout uint) error {
 	start := time.Now()
 	defer func() {
-		DockerOperationsLatency.WithLabelValues("stop_container").Observe(SinceInMicroseconds(start))
+		metrics.DockerOperationsLatency.WithLabelValues("stop_container").Observe(metrics.SinceInMicroseconds(start))
 	}()
 	return self.client.StopContainer(id, timeout)
 }
@@ -79,7 +79,7 @@ func (self instrumentedDockerInterface) StopContainer(id string, timeout uint) e
 func (self instrumentedDockerInterface) RemoveContainer(opts docker.RemoveContainerOptions) error {
 	start := time.Now()
 	defer func() {
-		DockerOperationsLatency.WithLabelValues("remove_container").Observe(SinceInMicroseconds(start))
+		metrics.DockerOperationsLatency.WithLabelValues("remove_container").Observe(metrics.SinceInMicroseconds(start))
 	}()
 	return self.client.RemoveContainer(opts)
 }
@@ -87,7 +87,7 @@ func (self instrumentedDockerInterface) RemoveContainer(opts docker.RemoveContai
 func (self instrumentedDockerInterface) InspectImage(image string) (*docker.Image, error) {
 	start := time.Now()
 	defer func() {
-		DockerOperationsLatency.WithLabelValues("inspect_image").Observe(SinceInMicroseconds(start))
+		metrics.DockerOperationsLatency.WithLabelValues("inspect_image").Observe(metrics.SinceInMicroseconds(start))
 	}()
 	return self.client.InspectImage(image)
 }
@@ -95,7 +95,7 @@ func (self instrumentedDockerInterface) InspectImage(image string) (*docker.Imag
 func (self instrumentedDockerInterface) ListImages(opts docker.ListImagesOptions) ([]docker.APIImages, error) {
 	start := time.Now()
 	defer func() {
-		DockerOperationsLatency.WithLabelValues("list_images").Observe(SinceInMicroseconds(start))
+		metrics.DockerOperationsLatency.WithLabelValues("list_images").Observe(metrics.SinceInMicroseconds(start))
 	}()
 	return self.client.ListImages(opts)
 }
@@ -103,7 +103,7 @@ func (self instrumentedDockerInterface) ListImages(opts docker.ListImagesOptions
 func (self instrumentedDockerInterface) PullImage(opts docker.PullImageOptions, auth docker.AuthConfiguration) error {
 	start := time.Now()
 	defer func() {
-		DockerOperationsLatency.WithLabelValues("pull_image").Observe(SinceInMicroseconds(start))
+		metrics.DockerOperationsLatency.WithLabelValues("pull_image").Observe(metrics.SinceInMicroseconds(start))
 	}()
 	return self.client.PullImage(opts, auth)
 }
@@ -111,7 +111,7 @@ func (self instrumentedDockerInterface) PullImage(opts docker.PullImageOptions,
 func (self instrumentedDockerInterface) RemoveImage(image string) error {
 	start := time.Now()
 	defer func() {
-		DockerOperationsLatency.WithLabelValues("remove_image").Observe(SinceInMicroseconds(start))
+		metrics.DockerOperationsLatency.WithLabelValues("remove_image").Observe(metrics.SinceInMicroseconds(start))
 	}()
 	return self.client.RemoveImage(image)
 }
@@ -119,7 +119,7 @@ func (self instrumentedDockerInterface) RemoveImage(image string) error {
 func (self instrumentedDockerInterface) Logs(opts docker.LogsOptions) error {
 	start := time.Now()
 	defer func() {
-		DockerOperationsLatency.WithLabelValues("logs").Observe(SinceInMicroseconds(start))
+		metrics.DockerOperationsLatency.WithLabelValues("logs").Observe(metrics.SinceInMicroseconds(start))
 	}()
 	return self.client.Logs(opts)
 }
@@ -127,7 +127,7 @@ func (self instrumentedDockerInterface) Logs(opts docker.LogsOptions) error {
 func (self instrumentedDockerInterface) Version() (*docker.Env, error) {
 	start := time.Now()
 	defer func() {
-		DockerOperationsLatency.WithLabelValues("version").Observe(SinceInMicroseconds(start))
+		metrics.DockerOperationsLatency.WithLabelValues("version").Observe(metrics.SinceInMicroseconds(start))
 	}()
 	return self.client.Version()
 }
@@ -135,7 +135,7 @@ func (self instrumentedDockerInterface) Version() (*docker.Env, error) {
 func (self instrumentedDockerInterface) CreateExec(opts docker.CreateExecOptions) (*docker.Exec, error) {
 	start := time.Now()
 	defer func() {
-		DockerOperationsLatency.WithLabelValues("create_exec").Observe(SinceInMicroseconds(start))
+		metrics.DockerOperationsLatency.WithLabelValues("create_exec").Observe(metrics.SinceInMicroseconds(start))
 	}()
 	return self.client.CreateExec(opts)
 }
@@ -143,7 +143,7 @@ func (self instrumentedDockerInterface) CreateExec(opts docker.CreateExecOptions
 func (self instrumentedDockerInterface) StartExec(startExec string, opts docker.StartExecOptions) error {
 	start := time.Now()
 	defer func() {
-		DockerOperationsLatency.WithLabelValues("start_exec").Observe(SinceInMicroseconds(start))
+		metrics.DockerOperationsLatency.WithLabelValues("start_exec").Observe(metrics.SinceInMicroseconds(start))
 	}()
 	return self.client.StartExec(startExec, opts)
 }
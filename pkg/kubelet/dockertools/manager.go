@@ -0,0 +1,817 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dockertools
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/capabilities"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/record"
+	kubecontainer "github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/container"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/metrics"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/network"
+	kubeletTypes "github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/types"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/probe"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/types"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	docker "github.com/fsouza/go-dockerclient"
+	"github.com/golang/glog"
+)
+
+const (
+	// Taken from lmctfy https://github.com/google/lmctfy/blob/master/lmctfy/controllers/cpu_controller.cc
+	minShares     = 2
+	sharesPerCPU  = 1024
+	milliCPUToCPU = 1000
+
+	// The CFS period, in microseconds, over which a container's cpu limit is
+	// enforced. 100ms is the kernel default.
+	quotaPeriod = 100000
+	// The kernel rejects CFS quotas below 1ms.
+	minQuotaPeriod = 1000
+
+	// The oom_score_adj of the POD infrastructure container. The default is 0, so
+	// any value below that makes it *less* likely to get OOM killed.
+	podOomScoreAdj = -100
+
+	// minimumGracePeriodInSeconds is the shortest time a container is given to exit
+	// after it is sent SIGTERM and before it is sent SIGKILL.
+	minimumGracePeriodInSeconds = 2
+)
+
+// DockerManager implements the kubecontainer.Runtime interface on top of the
+// Docker daemon.
+type DockerManager struct {
+	client              DockerInterface
+	recorder            record.EventRecorder
+	readinessManager    *kubecontainer.ReadinessManager
+	containerRefManager *kubecontainer.RefManager
+
+	// The image name of the pod infra container.
+	PodInfraContainerImage string
+	// Puller pulls the images of the containers. Defaults to a (possibly
+	// throttled) Docker puller; tests may replace it with a fake.
+	Puller DockerPuller
+	// Runs commands in containers and forwards ports to them.
+	runner ContainerCommandRunner
+	// Network plugin.
+	networkPlugin network.NetworkPlugin
+
+	// Hooks injected by the kubelet.
+	generator     kubecontainer.RunContainerOptionsGenerator
+	prober        kubecontainer.Prober
+	handlerRunner kubecontainer.HandlerRunner
+}
+
+// Ensure that DockerManager abides by the Runtime interface.
+var _ kubecontainer.Runtime = &DockerManager{}
+
+func NewDockerManager(
+	client DockerInterface,
+	recorder record.EventRecorder,
+	readinessManager *kubecontainer.ReadinessManager,
+	containerRefManager *kubecontainer.RefManager,
+	podInfraContainerImage string,
+	qps float32,
+	burst int,
+	networkPlugin network.NetworkPlugin,
+	generator kubecontainer.RunContainerOptionsGenerator,
+	prober kubecontainer.Prober,
+	handlerRunner kubecontainer.HandlerRunner) *DockerManager {
+	return &DockerManager{
+		client:                 client,
+		recorder:               recorder,
+		readinessManager:       readinessManager,
+		containerRefManager:    containerRefManager,
+		PodInfraContainerImage: podInfraContainerImage,
+		Puller:                 NewDockerPuller(client, qps, burst),
+		runner:                 NewDockerContainerCommandRunner(client),
+		networkPlugin:          networkPlugin,
+		generator:              generator,
+		prober:                 prober,
+		handlerRunner:          handlerRunner,
+	}
+}
+
+// Version returns the version information reported by the Docker daemon.
+func (dm *DockerManager) Version() (map[string]string, error) {
+	env, err := dm.client.Version()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get docker version: %v", err)
+	}
+	return env.Map(), nil
+}
+
+// GetDockerServerVersion returns the major and minor version numbers of the
+// Docker API served by the daemon.
+func (dm *DockerManager) GetDockerServerVersion() ([]uint, error) {
+	return dm.runner.GetDockerServerVersion()
+}
+
+// GetPods returns the pods which have containers managed by the kubelet. If
+// all is true, exited and dead containers are included as well.
+func (dm *DockerManager) GetPods(all bool) ([]*kubecontainer.Pod, error) {
+	return GetPods(dm.client, all)
+}
+
+// GetPodStatus returns the docker related status of all containers in the pod.
+func (dm *DockerManager) GetPodStatus(pod *api.Pod) (*api.PodStatus, error) {
+	return GetDockerPodStatus(dm.client, pod.Spec, kubecontainer.GetPodFullName(pod), pod.UID)
+}
+
+func (dm *DockerManager) PullImage(image string, pullSecrets []api.Secret) error {
+	return dm.Puller.Pull(image, pullSecrets)
+}
+
+func (dm *DockerManager) IsImagePresent(image string) (bool, error) {
+	return dm.Puller.IsImagePresent(image)
+}
+
+func (dm *DockerManager) GetContainerLogs(containerID string, opts *api.PodLogOptions, stdout, stderr io.Writer) error {
+	return GetKubeletDockerContainerLogs(dm.client, containerID, opts, stdout, stderr)
+}
+
+func (dm *DockerManager) RunInContainer(containerID string, cmd []string) ([]byte, error) {
+	return dm.runner.RunInContainer(containerID, cmd)
+}
+
+func (dm *DockerManager) ExecInContainer(containerID string, cmd []string, stdin io.Reader, stdout, stderr io.WriteCloser, tty bool) error {
+	return dm.runner.ExecInContainer(containerID, cmd, stdin, stdout, stderr, tty)
+}
+
+// PortForward forwards the port to the network namespace of the pod, which is
+// held by its pod infra container.
+func (dm *DockerManager) PortForward(pod *kubecontainer.Pod, port uint16, stream io.ReadWriteCloser) error {
+	podInfraContainer := pod.FindContainerByName(PodInfraContainerName)
+	if podInfraContainer == nil {
+		return fmt.Errorf("cannot find pod infra container in pod %q", kubecontainer.BuildPodFullName(pod.Name, pod.Namespace))
+	}
+	return dm.runner.PortForward(string(podInfraContainer.ID), port, stream)
+}
+
+func makeBinds(mounts []kubecontainer.Mount) []string {
+	binds := []string{}
+	for _, m := range mounts {
+		b := fmt.Sprintf("%s:%s", m.HostPath, m.ContainerPath)
+		if m.ReadOnly {
+			b += ":ro"
+		}
+		binds = append(binds, b)
+	}
+	return binds
+}
+
+func makePortsAndBindings(container *api.Container) (map[docker.Port]struct{}, map[docker.Port][]docker.PortBinding) {
+	exposedPorts := map[docker.Port]struct{}{}
+	portBindings := map[docker.Port][]docker.PortBinding{}
+	for _, port := range container.Ports {
+		exteriorPort := port.HostPort
+		if exteriorPort == 0 {
+			// No need to do port binding when HostPort is not specified
+			continue
+		}
+		interiorPort := port.ContainerPort
+		// Some of this port stuff is under-documented voodoo.
+		// See http://stackoverflow.com/questions/20428302/binding-a-port-to-a-host-interface-using-the-rest-api
+		var protocol string
+		switch strings.ToUpper(string(port.Protocol)) {
+		case "UDP":
+			protocol = "/udp"
+		case "TCP":
+			protocol = "/tcp"
+		default:
+			glog.Warningf("Unknown protocol %q: defaulting to TCP", port.Protocol)
+			protocol = "/tcp"
+		}
+		dockerPort := docker.Port(strconv.Itoa(interiorPort) + protocol)
+		exposedPorts[dockerPort] = struct{}{}
+		portBindings[dockerPort] = []docker.PortBinding{
+			{
+				HostPort: strconv.Itoa(exteriorPort),
+				HostIP:   port.HostIP,
+			},
+		}
+	}
+	return exposedPorts, portBindings
+}
+
+func milliCPUToShares(milliCPU int64) int64 {
+	if milliCPU == 0 {
+		// zero milliCPU means unset. Use kernel default.
+		return 0
+	}
+	// Conceptually (milliCPU / milliCPUToCPU) * sharesPerCPU, but factored to improve rounding.
+	shares := (milliCPU * sharesPerCPU) / milliCPUToCPU
+	if shares < minShares {
+		return minShares
+	}
+	return shares
+}
+
+// milliCPUToQuota converts a cpu limit in milliCPU to a CFS quota for
+// quotaPeriod. Zero milliCPU means unset and returns a zero quota, which
+// leaves the container uncapped.
+func milliCPUToQuota(milliCPU int64) int64 {
+	if milliCPU == 0 {
+		return 0
+	}
+	// Conceptually (milliCPU / milliCPUToCPU) * quotaPeriod, but factored to improve rounding.
+	quota := (milliCPU * quotaPeriod) / milliCPUToCPU
+	if quota < minQuotaPeriod {
+		return minQuotaPeriod
+	}
+	return quota
+}
+
+func makeCapabilites(capAdd []api.CapabilityType, capDrop []api.CapabilityType) ([]string, []string) {
+	var (
+		addCaps  []string
+		dropCaps []string
+	)
+	for _, cap := range capAdd {
+		addCaps = append(addCaps, string(cap))
+	}
+	for _, cap := range capDrop {
+		dropCaps = append(dropCaps, string(cap))
+	}
+	return addCaps, dropCaps
+}
+
+// Run a single container from a pod. Returns the docker container ID
+func (dm *DockerManager) runContainer(pod *api.Pod, container *api.Container, netMode, ipcMode string) (kubeletTypes.DockerID, error) {
+	ref, err := kubecontainer.GenerateContainerRef(pod, container)
+	if err != nil {
+		glog.Errorf("Couldn't make a ref to pod %v, container %v: '%v'", pod.Name, container.Name, err)
+	}
+
+	opts, err := dm.generator.GenerateRunContainerOptions(pod, container)
+	if err != nil {
+		if ref != nil {
+			dm.recorder.Eventf(ref, "failed", "Failed to create docker container: %v", err)
+		}
+		return "", err
+	}
+	binds := makeBinds(opts.Mounts)
+	exposedPorts, portBindings := makePortsAndBindings(container)
+
+	// TODO(vmarmol): Handle better.
+	// Cap hostname at 63 chars (specification is 64bytes which is 63 chars and the null terminating char).
+	const hostnameMaxLen = 63
+	containerHostname := pod.Name
+	if len(containerHostname) > hostnameMaxLen {
+		containerHostname = containerHostname[:hostnameMaxLen]
+	}
+	dockerOpts := docker.CreateContainerOptions{
+		Name: BuildDockerName(KubeletContainerName{kubecontainer.GetPodFullName(pod), pod.UID, container.Name}, container),
+		Config: &docker.Config{
+			Cmd:          container.Command,
+			Env:          opts.Envs,
+			ExposedPorts: exposedPorts,
+			Hostname:     containerHostname,
+			Image:        container.Image,
+			// The limits cap the memory and cpu time of the container (the
+			// latter with a CFS quota set at start), while its cpu request sets
+			// its share of the cpu time when the node is busy.
+			Memory:     container.Resources.Limits.Memory().Value(),
+			CPUShares:  milliCPUToShares(container.Resources.Requests.Cpu().MilliValue()),
+			WorkingDir: container.WorkingDir,
+		},
+	}
+	dockerContainer, err := dm.client.CreateContainer(dockerOpts)
+	if err != nil {
+		if ref != nil {
+			dm.recorder.Eventf(ref, "failed", "Failed to create docker container with error: %v", err)
+		}
+		return "", err
+	}
+	// Remember this reference so we can report events about this container
+	if ref != nil {
+		dm.containerRefManager.SetRef(dockerContainer.ID, ref)
+		dm.recorder.Eventf(ref, "created", "Created with docker id %v", dockerContainer.ID)
+	}
+
+	if opts.PodContainerDir != "" && len(container.TerminationMessagePath) != 0 {
+		containerLogPath := path.Join(opts.PodContainerDir, dockerContainer.ID)
+		fs, err := os.Create(containerLogPath)
+		if err != nil {
+			// TODO: Clean up the previouly created dir? return the error?
+			glog.Errorf("Error on creating termination-log file %q: %v", containerLogPath, err)
+		} else {
+			fs.Close() // Close immediately; we're just doing a `touch` here
+			b := fmt.Sprintf("%s:%s", containerLogPath, container.TerminationMessagePath)
+			binds = append(binds, b)
+		}
+	}
+	privileged := false
+	if capabilities.Get().AllowPrivileged {
+		privileged = container.Privileged
+	} else if container.Privileged {
+		return "", fmt.Errorf("container requested privileged mode, but it is disallowed globally.")
+	}
+
+	capAdd, capDrop := makeCapabilites(container.Capabilities.Add, container.Capabilities.Drop)
+	hc := &docker.HostConfig{
+		PortBindings: portBindings,
+		Binds:        binds,
+		NetworkMode:  netMode,
+		IpcMode:      ipcMode,
+		Privileged:   privileged,
+		CapAdd:       capAdd,
+		CapDrop:      capDrop,
+	}
+	if quota := milliCPUToQuota(container.Resources.Limits.Cpu().MilliValue()); quota > 0 {
+		hc.CPUQuota = quota
+		hc.CPUPeriod = quotaPeriod
+	}
+	if len(opts.DNS) > 0 {
+		hc.DNS = opts.DNS
+	}
+	if len(opts.DNSSearch) > 0 {
+		hc.DNSSearch = opts.DNSSearch
+	}
+	err = dm.client.StartContainer(dockerContainer.ID, hc)
+	if err != nil {
+		if ref != nil {
+			dm.recorder.Eventf(ref, "failed",
+				"Failed to start with docker id %v with error: %v", dockerContainer.ID, err)
+		}
+		return "", err
+	}
+	if ref != nil {
+		dm.recorder.Eventf(ref, "started", "Started with docker id %v", dockerContainer.ID)
+	}
+
+	if container.Lifecycle != nil && container.Lifecycle.PostStart != nil {
+		handlerErr := dm.handlerRunner.Run(dockerContainer.ID, pod, container, container.Lifecycle.PostStart)
+		if handlerErr != nil {
+			dm.killContainer(types.UID(dockerContainer.ID), nil, nil)
+			return kubeletTypes.DockerID(""), fmt.Errorf("failed to call event handler: %v", handlerErr)
+		}
+	}
+	return kubeletTypes.DockerID(dockerContainer.ID), nil
+}
+
+// KillPod kills all the containers of the pod. The application containers are
+// stopped first, honoring the grace period and preStop hooks of the pod if it is
+// known, and the network plugin is then told to tear down the pod before its infra
+// container is stopped, so that the pod keeps its network while it shuts down.
+// The pod may be nil if its spec is no longer known.
+func (dm *DockerManager) KillPod(pod *api.Pod, runningPod kubecontainer.Pod) error {
+	// Send the kills in parallel since they may take a long time. Len + 1 since there
+	// can be Len errors + the networkPlugin teardown error.
+	errs := make(chan error, len(runningPod.Containers)+1)
+	wg := sync.WaitGroup{}
+	var networkContainer *kubecontainer.Container
+	for _, container := range runningPod.Containers {
+		if container.Name == PodInfraContainerName {
+			networkContainer = container
+			continue
+		}
+		wg.Add(1)
+		go func(container *kubecontainer.Container) {
+			defer util.HandleCrash()
+			defer wg.Done()
+			err := dm.killContainer(container.ID, containerSpecFor(pod, container.Name), pod)
+			if err != nil {
+				glog.Errorf("Failed to delete container: %v; Skipping pod %q", err, runningPod.ID)
+				errs <- err
+			}
+		}(container)
+	}
+	wg.Wait()
+	if networkContainer != nil {
+		if err := dm.networkPlugin.TearDownPod(runningPod.Namespace, runningPod.Name, kubeletTypes.DockerID(networkContainer.ID)); err != nil {
+			glog.Errorf("Network plugin pre-delete method returned an error: %v", err)
+			errs <- err
+		}
+		if err := dm.killContainer(networkContainer.ID, nil, pod); err != nil {
+			glog.Errorf("Failed to delete container: %v; Skipping pod %q", err, runningPod.ID)
+			errs <- err
+		}
+	}
+	close(errs)
+	if len(errs) > 0 {
+		errList := []error{}
+		for err := range errs {
+			errList = append(errList, err)
+		}
+		return fmt.Errorf("failed to delete containers (%v)", errList)
+	}
+	return nil
+}
+
+// containerSpecFor returns the spec of the named container in pod, or nil if the
+// pod is unknown or has no such container.
+func containerSpecFor(pod *api.Pod, name string) *api.Container {
+	if pod == nil {
+		return nil
+	}
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == name {
+			return &pod.Spec.Containers[i]
+		}
+	}
+	return nil
+}
+
+// killContainer stops a docker container and forgets its readiness. If the pod is
+// known, the container is given the pod's grace period to exit: its preStop hook is
+// run first, then it is sent SIGTERM and, if it is still running once the rest of the
+// grace period has elapsed, SIGKILL. The container spec and pod may be nil.
+func (dm *DockerManager) killContainer(containerID types.UID, container *api.Container, pod *api.Pod) error {
+	ID := string(containerID)
+	gracePeriod := int64(minimumGracePeriodInSeconds)
+	if pod != nil {
+		switch {
+		case pod.DeletionGracePeriodSeconds != nil:
+			gracePeriod = *pod.DeletionGracePeriodSeconds
+		case pod.Spec.TerminationGracePeriodSeconds != nil:
+			gracePeriod = *pod.Spec.TerminationGracePeriodSeconds
+		default:
+			gracePeriod = api.DefaultTerminationGracePeriodSeconds
+		}
+	}
+	glog.V(2).Infof("Killing container with id %q and a grace period of %d seconds", ID, gracePeriod)
+	dm.readinessManager.RemoveReadiness(ID)
+
+	start := time.Now()
+	if pod != nil && container != nil && container.Lifecycle != nil && container.Lifecycle.PreStop != nil {
+		glog.V(4).Infof("Running preStop hook for container %q", ID)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			defer util.HandleCrash()
+			if err := dm.handlerRunner.Run(ID, pod, container, container.Lifecycle.PreStop); err != nil {
+				glog.Errorf("preStop hook for container %q failed: %v", ID, err)
+			}
+		}()
+		select {
+		case <-time.After(time.Duration(gracePeriod) * time.Second):
+			glog.Warningf("preStop hook for container %q did not complete in %d seconds", ID, gracePeriod)
+		case <-done:
+			glog.V(4).Infof("preStop hook for container %q completed", ID)
+		}
+		gracePeriod -= int64(time.Since(start).Seconds())
+	}
+	// Always give the container a short window to exit after SIGTERM.
+	if gracePeriod < minimumGracePeriodInSeconds {
+		gracePeriod = minimumGracePeriodInSeconds
+	}
+	// Docker sends SIGTERM and then SIGKILL once the timeout expires.
+	err := dm.client.StopContainer(ID, uint(gracePeriod))
+
+	ref, ok := dm.containerRefManager.GetRef(ID)
+	if !ok {
+		glog.Warningf("No ref for pod '%v'", ID)
+	} else {
+		// TODO: pass reason down here, and state, or move this call up the stack.
+		dm.recorder.Eventf(ref, "killing", "Killing %v", ID)
+	}
+	return err
+}
+
+// createPodInfraContainer starts the pod infra container for a pod. Returns the docker container ID of the newly created container.
+func (dm *DockerManager) createPodInfraContainer(pod *api.Pod, pullSecrets kubecontainer.PullSecretsGetter) (kubeletTypes.DockerID, error) {
+	// Use host networking if specified.
+	netNamespace := ""
+	var ports []api.ContainerPort
+
+	if pod.Spec.HostNetwork {
+		netNamespace = "host"
+	} else {
+		// Docker only exports ports from the pod infra container.  Let's
+		// collect all of the relevant ports and export them.
+		for _, container := range pod.Spec.Containers {
+			ports = append(ports, container.Ports...)
+		}
+	}
+
+	container := &api.Container{
+		Name:  PodInfraContainerName,
+		Image: dm.PodInfraContainerImage,
+		Ports: ports,
+	}
+	ref, err := kubecontainer.GenerateContainerRef(pod, container)
+	if err != nil {
+		glog.Errorf("Couldn't make a ref to pod %v, container %v: '%v'", pod.Name, container.Name, err)
+	}
+	// TODO: make this a TTL based pull (if image older than X policy, pull)
+	ok, err := dm.Puller.IsImagePresent(container.Image)
+	if err != nil {
+		if ref != nil {
+			dm.recorder.Eventf(ref, "failed", "Failed to inspect image %q: %v", container.Image, err)
+		}
+		return "", err
+	}
+	if !ok {
+		if err := dm.pullImage(container.Image, pullSecrets(), ref); err != nil {
+			return "", err
+		}
+	}
+	if ref != nil {
+		dm.recorder.Eventf(ref, "pulled", "Successfully pulled image %q", container.Image)
+	}
+
+	id, err := dm.runContainer(pod, container, netNamespace, "")
+	if err != nil {
+		return "", err
+	}
+
+	// Set OOM score of POD container to lower than those of the other
+	// containers in the pod. This ensures that it is killed only as a last
+	// resort.
+	containerInfo, err := dm.client.InspectContainer(string(id))
+	if err != nil {
+		return "", err
+	}
+
+	// Ensure the PID actually exists, else we'll move ourselves.
+	if containerInfo.State.Pid == 0 {
+		return "", fmt.Errorf("failed to get init PID for Docker pod infra container %q", string(id))
+	}
+	return id, util.ApplyOomScoreAdj(containerInfo.State.Pid, podOomScoreAdj)
+}
+
+func (dm *DockerManager) pullImage(img string, pullSecrets []api.Secret, ref *api.ObjectReference) error {
+	start := time.Now()
+	defer func() {
+		metrics.ImagePullLatency.Observe(metrics.SinceInMicroseconds(start))
+	}()
+
+	if err := dm.Puller.Pull(img, pullSecrets); err != nil {
+		if ref != nil {
+			dm.recorder.Eventf(ref, "failed", "Failed to pull image %q: %v", img, err)
+		}
+		return err
+	}
+	if ref != nil {
+		dm.recorder.Eventf(ref, "pulled", "Successfully pulled image %q", img)
+	}
+	return nil
+}
+
+func (dm *DockerManager) shouldContainerBeRestarted(container *api.Container, pod *api.Pod) bool {
+	podFullName := kubecontainer.GetPodFullName(pod)
+	// Check RestartPolicy for dead container
+	recentContainers, err := GetRecentDockerContainersWithNameAndUUID(dm.client, podFullName, pod.UID, container.Name)
+	if err != nil {
+		glog.Errorf("Error listing recent containers for pod %q: %v", podFullName, err)
+		// TODO(dawnchen): error handling here?
+	}
+	// set dead containers to unready state
+	for _, c := range recentContainers {
+		dm.readinessManager.RemoveReadiness(c.ID)
+	}
+
+	if len(recentContainers) > 0 {
+		if pod.Spec.RestartPolicy == api.RestartPolicyNever {
+			glog.Infof("Already ran container %q of pod %q, do nothing", container.Name, podFullName)
+			return false
+
+		}
+		if pod.Spec.RestartPolicy == api.RestartPolicyOnFailure {
+			// Check the exit code of last run
+			if recentContainers[0].State.ExitCode == 0 {
+				glog.Infof("Already successfully ran container %q of pod %q, do nothing", container.Name, podFullName)
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Attempts to start a container pulling the image before that if necessary. It returns DockerID of a started container
+// if it was successful, and a non-nil error otherwise.
+func (dm *DockerManager) pullImageAndRunContainer(pod *api.Pod, container *api.Container, podInfraContainerID kubeletTypes.DockerID, pullSecrets kubecontainer.PullSecretsGetter) (kubeletTypes.DockerID, error) {
+	podFullName := kubecontainer.GetPodFullName(pod)
+	ref, err := kubecontainer.GenerateContainerRef(pod, container)
+	if err != nil {
+		glog.Errorf("Couldn't make a ref to pod %v, container %v: '%v'", pod.Name, container.Name, err)
+	}
+	if container.ImagePullPolicy != api.PullNever {
+		present, err := dm.Puller.IsImagePresent(container.Image)
+		if err != nil {
+			if ref != nil {
+				dm.recorder.Eventf(ref, "failed", "Failed to inspect image %q: %v", container.Image, err)
+			}
+			glog.Errorf("Failed to inspect image %q: %v; skipping pod %q container %q", container.Image, err, podFullName, container.Name)
+			return "", err
+		}
+		if container.ImagePullPolicy == api.PullAlways ||
+			(container.ImagePullPolicy == api.PullIfNotPresent && (!present)) {
+			if err := dm.pullImage(container.Image, pullSecrets(), ref); err != nil {
+				return "", err
+			}
+		}
+	}
+	// TODO(dawnchen): Check RestartPolicy.DelaySeconds before restart a container
+	namespaceMode := fmt.Sprintf("container:%v", podInfraContainerID)
+	containerID, err := dm.runContainer(pod, container, namespaceMode, namespaceMode)
+	if err != nil {
+		// TODO(bburns) : Perhaps blacklist a container after N failures?
+		glog.Errorf("Error running pod %q container %q: %v", podFullName, container.Name, err)
+		return "", err
+	}
+	return containerID, nil
+}
+
+type empty struct{}
+
+// Structure keeping information on changes that need to happen for a pod. The semantics is as follows:
+// - startInfraContainer is true if new Infra Containers have to be started and old one (if running) killed.
+//   Additionally if it is true then containersToKeep have to be empty
+// - infraContainerId have to be set iff startInfraContainer is false. It stores dockerID of running Infra Container
+// - containersToStart keeps indices of Specs of containers that have to be started.
+// - containersToKeep stores mapping from dockerIDs of running containers to indices of their Specs for containers that
+//   should be kept running. If startInfraContainer is false then it contains an entry for infraContainerId (mapped to -1).
+//   It shouldn't be the case where containersToStart is empty and containersToKeep contains only infraContainerId. In such case
+//   Infra Container should be killed, hence it's removed from this map.
+// - all running containers which are NOT contained in containersToKeep should be killed.
+type podContainerChangesSpec struct {
+	startInfraContainer bool
+	infraContainerId    kubeletTypes.DockerID
+	containersToStart   map[int]empty
+	containersToKeep    map[kubeletTypes.DockerID]int
+}
+
+func (dm *DockerManager) computePodContainerChanges(pod *api.Pod, runningPod kubecontainer.Pod, podStatus api.PodStatus) (podContainerChangesSpec, error) {
+	podFullName := kubecontainer.GetPodFullName(pod)
+	uid := pod.UID
+	glog.V(4).Infof("Syncing Pod %+v, podFullName: %q, uid: %q", pod, podFullName, uid)
+
+	containersToStart := make(map[int]empty)
+	containersToKeep := make(map[kubeletTypes.DockerID]int)
+	createPodInfraContainer := false
+
+	var podInfraContainerID kubeletTypes.DockerID
+	podInfraContainer := runningPod.FindContainerByName(PodInfraContainerName)
+	if podInfraContainer != nil {
+		glog.V(4).Infof("Found infra pod for %q", podFullName)
+		podInfraContainerID = kubeletTypes.DockerID(podInfraContainer.ID)
+		containersToKeep[podInfraContainerID] = -1
+	} else {
+		glog.V(2).Infof("No Infra Container for %q found. All containers will be restarted.", podFullName)
+		createPodInfraContainer = true
+	}
+
+	for index, container := range pod.Spec.Containers {
+		expectedHash := HashContainer(&container)
+
+		c := runningPod.FindContainerByName(container.Name)
+		if c != nil {
+			containerID := kubeletTypes.DockerID(c.ID)
+			hash := c.Hash
+			glog.V(3).Infof("pod %q container %q exists as %v", podFullName, container.Name, containerID)
+
+			if !createPodInfraContainer {
+				// look for changes in the container.
+
+				containerChanged := hash != 0 && hash != expectedHash
+				if !containerChanged {
+					result, err := dm.prober.Probe(pod, podStatus, container, string(c.ID), c.Created)
+					if err != nil {
+						// TODO(vmarmol): examine this logic.
+						glog.V(2).Infof("probe no-error: %q", container.Name)
+						containersToKeep[containerID] = index
+						continue
+					}
+					if result == probe.Success {
+						glog.V(4).Infof("probe success: %q", container.Name)
+						containersToKeep[containerID] = index
+						continue
+					}
+					glog.Infof("pod %q container %q is unhealthy (probe result: %v). Container will be killed and re-created.", podFullName, container.Name, result)
+					containersToStart[index] = empty{}
+				} else {
+					glog.Infof("pod %q container %q hash changed (%d vs %d). Pod will be killed and re-created.", podFullName, container.Name, hash, expectedHash)
+					createPodInfraContainer = true
+					delete(containersToKeep, podInfraContainerID)
+					// If we are to restart Infra Container then we move containersToKeep into containersToStart
+					// if RestartPolicy allows restarting failed containers.
+					if pod.Spec.RestartPolicy != api.RestartPolicyNever {
+						for _, v := range containersToKeep {
+							containersToStart[v] = empty{}
+						}
+					}
+					containersToStart[index] = empty{}
+					containersToKeep = make(map[kubeletTypes.DockerID]int)
+				}
+			} else { // createPodInfraContainer == true and Container exists
+				// If we're creating infra containere everything will be killed anyway
+				// If RestartPolicy is Always or OnFailure we restart containers that were running before we
+				// killed them when restarting Infra Container.
+				if pod.Spec.RestartPolicy != api.RestartPolicyNever {
+					glog.V(1).Infof("Infra Container is being recreated. %q will be restarted.", container.Name)
+					containersToStart[index] = empty{}
+				}
+				continue
+			}
+		} else {
+			if dm.shouldContainerBeRestarted(&container, pod) {
+				// If we are here it means that the container is dead and sould be restarted, or never existed and should
+				// be created. We may be inserting this ID again if the container has changed and it has
+				// RestartPolicy::Always, but it's not a big deal.
+				glog.V(3).Infof("Container %+v is dead, but RestartPolicy says that we should restart it.", container)
+				containersToStart[index] = empty{}
+			}
+		}
+	}
+
+	// After the loop one of the following should be true:
+	// - createPodInfraContainer is true and containersToKeep is empty
+	// - createPodInfraContainer is false and containersToKeep contains at least ID of Infra Container
+
+	// If Infra container is the last running one, we don't want to keep it.
+	if !createPodInfraContainer && len(containersToStart) == 0 && len(containersToKeep) == 1 {
+		containersToKeep = make(map[kubeletTypes.DockerID]int)
+	}
+
+	return podContainerChangesSpec{
+		startInfraContainer: createPodInfraContainer,
+		infraContainerId:    podInfraContainerID,
+		containersToStart:   containersToStart,
+		containersToKeep:    containersToKeep,
+	}, nil
+}
+
+// SyncPod syncs the running pod to match the specified desired pod.
+func (dm *DockerManager) SyncPod(pod *api.Pod, runningPod kubecontainer.Pod, podStatus api.PodStatus, pullSecrets kubecontainer.PullSecretsGetter) error {
+	podFullName := kubecontainer.GetPodFullName(pod)
+	containerChanges, err := dm.computePodContainerChanges(pod, runningPod, podStatus)
+	glog.V(3).Infof("Got container changes for pod %q: %+v", podFullName, containerChanges)
+	if err != nil {
+		return err
+	}
+
+	if containerChanges.startInfraContainer || (len(containerChanges.containersToKeep) == 0 && len(containerChanges.containersToStart) == 0) {
+		if len(containerChanges.containersToKeep) == 0 && len(containerChanges.containersToStart) == 0 {
+			glog.V(4).Infof("Killing Infra Container for %q becase all other containers are dead.", podFullName)
+		} else {
+			glog.V(4).Infof("Killing Infra Container for %q, will start new one", podFullName)
+		}
+
+		// Killing phase: if we want to start new infra container, or nothing is running kill everything (including infra container)
+		if err := dm.KillPod(pod, runningPod); err != nil {
+			return err
+		}
+	} else {
+		// Otherwise kill any containers in this pod which are not specified as ones to keep.
+		for _, container := range runningPod.Containers {
+			_, keep := containerChanges.containersToKeep[kubeletTypes.DockerID(container.ID)]
+			if !keep {
+				glog.V(3).Infof("Killing unwanted container %+v", container)
+				err = dm.killContainer(container.ID, containerSpecFor(pod, container.Name), pod)
+				if err != nil {
+					glog.Errorf("Error killing container: %v", err)
+				}
+			}
+		}
+	}
+
+	// Starting phase: if we should create infra container then we do it first
+	podInfraContainerID := containerChanges.infraContainerId
+	if containerChanges.startInfraContainer && (len(containerChanges.containersToStart) > 0) {
+		glog.Infof("Creating pod infra container for %q", podFullName)
+		podInfraContainerID, err = dm.createPodInfraContainer(pod, pullSecrets)
+
+		// Call the networking plugin
+		if err == nil {
+			err = dm.networkPlugin.SetUpPod(pod.Namespace, pod.Name, podInfraContainerID)
+		}
+		if err != nil {
+			glog.Errorf("Failed to create pod infra container: %v; Skipping pod %q", err, podFullName)
+			return err
+		}
+	}
+
+	// Start everything
+	for idx := range containerChanges.containersToStart {
+		container := &pod.Spec.Containers[idx]
+		glog.V(4).Infof("Creating container %+v", container)
+		dm.pullImageAndRunContainer(pod, container, podInfraContainerID, pullSecrets)
+	}
+
+	return nil
+}
@@ -0,0 +1,316 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dockertools
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/record"
+	kubecontainer "github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/container"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/types"
+	docker "github.com/fsouza/go-dockerclient"
+)
+
+func newTestDockerManager(fakeDocker *FakeDockerClient) *DockerManager {
+	return NewFakeDockerManager(
+		fakeDocker,
+		&record.FakeRecorder{},
+		kubecontainer.NewReadinessManager(),
+		kubecontainer.NewRefManager(),
+		"kubernetes/pause:latest",
+		0, 0, nil, nil, nil, nil)
+}
+
+func TestKillContainerWithError(t *testing.T) {
+	containers := []docker.APIContainers{
+		{
+			ID:    "1234",
+			Names: []string{"/k8s_foo_qux_new_1234_42"},
+		},
+		{
+			ID:    "5678",
+			Names: []string{"/k8s_bar_qux_new_5678_42"},
+		},
+	}
+	fakeDocker := &FakeDockerClient{
+		Err:           fmt.Errorf("sample error"),
+		ContainerList: append([]docker.APIContainers{}, containers...),
+	}
+	manager := newTestDockerManager(fakeDocker)
+	for _, c := range fakeDocker.ContainerList {
+		manager.readinessManager.SetReadiness(c.ID, true)
+	}
+	err := manager.killContainer(types.UID(fakeDocker.ContainerList[0].ID), nil, nil)
+	if err == nil {
+		t.Errorf("expected error, found nil")
+	}
+	verifyCalls(t, fakeDocker, []string{"stop"})
+	killedContainer := containers[0]
+	liveContainer := containers[1]
+	if ready := manager.readinessManager.GetReadiness(killedContainer.ID); ready {
+		t.Errorf("exepcted container entry ID '%v' to not be found.", killedContainer.ID)
+	}
+	if ready := manager.readinessManager.GetReadiness(liveContainer.ID); !ready {
+		t.Errorf("exepcted container entry ID '%v' to be found.", liveContainer.ID)
+	}
+}
+
+func TestKillContainer(t *testing.T) {
+	containers := []docker.APIContainers{
+		{
+			ID:    "1234",
+			Names: []string{"/k8s_foo_qux_new_1234_42"},
+		},
+		{
+			ID:    "5678",
+			Names: []string{"/k8s_bar_qux_new_5678_42"},
+		},
+	}
+	fakeDocker := &FakeDockerClient{
+		ContainerList: append([]docker.APIContainers{}, containers...),
+		Container: &docker.Container{
+			Name: "foobar",
+		},
+	}
+	manager := newTestDockerManager(fakeDocker)
+	for _, c := range fakeDocker.ContainerList {
+		manager.readinessManager.SetReadiness(c.ID, true)
+	}
+
+	err := manager.killContainer(types.UID(fakeDocker.ContainerList[0].ID), nil, nil)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	verifyCalls(t, fakeDocker, []string{"stop"})
+	killedContainer := containers[0]
+	liveContainer := containers[1]
+	if ready := manager.readinessManager.GetReadiness(killedContainer.ID); ready {
+		t.Errorf("exepcted container entry ID '%v' to not be found.", killedContainer.ID)
+	}
+	if ready := manager.readinessManager.GetReadiness(liveContainer.ID); !ready {
+		t.Errorf("exepcted container entry ID '%v' to be found.", liveContainer.ID)
+	}
+}
+
+type fakeHandlerRunner struct {
+	ran []string
+}
+
+func (hr *fakeHandlerRunner) Run(containerID string, pod *api.Pod, container *api.Container, handler *api.Handler) error {
+	hr.ran = append(hr.ran, containerID)
+	return nil
+}
+
+func TestKillContainerHonorsGracePeriod(t *testing.T) {
+	specGrace := int64(20)
+	deletionGrace := int64(5)
+	tests := []struct {
+		pod             *api.Pod
+		expectedTimeout uint
+	}{
+		{nil, minimumGracePeriodInSeconds},
+		{&api.Pod{}, api.DefaultTerminationGracePeriodSeconds},
+		{&api.Pod{Spec: api.PodSpec{TerminationGracePeriodSeconds: &specGrace}}, 20},
+		{
+			&api.Pod{
+				ObjectMeta: api.ObjectMeta{DeletionGracePeriodSeconds: &deletionGrace},
+				Spec:       api.PodSpec{TerminationGracePeriodSeconds: &specGrace},
+			},
+			5,
+		},
+	}
+	for i, test := range tests {
+		fakeDocker := &FakeDockerClient{
+			ContainerList: []docker.APIContainers{{ID: "1234", Names: []string{"/k8s_foo_qux_new_1234_42"}}},
+		}
+		manager := newTestDockerManager(fakeDocker)
+		if err := manager.killContainer("1234", nil, test.pod); err != nil {
+			t.Errorf("%d: unexpected error: %v", i, err)
+		}
+		if !reflect.DeepEqual(fakeDocker.StopTimeouts, []uint{test.expectedTimeout}) {
+			t.Errorf("%d: expected stop timeout %d, got %v", i, test.expectedTimeout, fakeDocker.StopTimeouts)
+		}
+	}
+}
+
+func TestKillContainerRunsPreStopHook(t *testing.T) {
+	grace := int64(30)
+	container := api.Container{
+		Name: "foo",
+		Lifecycle: &api.Lifecycle{
+			PreStop: &api.Handler{Exec: &api.ExecAction{Command: []string{"drain"}}},
+		},
+	}
+	pod := &api.Pod{Spec: api.PodSpec{
+		Containers:                    []api.Container{container},
+		TerminationGracePeriodSeconds: &grace,
+	}}
+	fakeDocker := &FakeDockerClient{
+		ContainerList: []docker.APIContainers{{ID: "1234", Names: []string{"/k8s_foo_qux_new_1234_42"}}},
+	}
+	handlerRunner := &fakeHandlerRunner{}
+	manager := NewFakeDockerManager(
+		fakeDocker,
+		&record.FakeRecorder{},
+		kubecontainer.NewReadinessManager(),
+		kubecontainer.NewRefManager(),
+		"kubernetes/pause:latest",
+		0, 0, nil, nil, nil, handlerRunner)
+
+	if err := manager.killContainer("1234", &pod.Spec.Containers[0], pod); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(handlerRunner.ran, []string{"1234"}) {
+		t.Errorf("expected preStop hook to run for container 1234, got %v", handlerRunner.ran)
+	}
+	verifyCalls(t, fakeDocker, []string{"stop"})
+	if len(fakeDocker.StopTimeouts) != 1 || fakeDocker.StopTimeouts[0] > 30 || fakeDocker.StopTimeouts[0] < 29 {
+		t.Errorf("expected the remaining grace period to be used as the stop timeout, got %v", fakeDocker.StopTimeouts)
+	}
+}
+
+func TestMakePortsAndBindings(t *testing.T) {
+	container := api.Container{
+		Ports: []api.ContainerPort{
+			{
+				ContainerPort: 80,
+				HostPort:      8080,
+				HostIP:        "127.0.0.1",
+			},
+			{
+				ContainerPort: 443,
+				HostPort:      443,
+				Protocol:      "tcp",
+			},
+			{
+				ContainerPort: 444,
+				HostPort:      444,
+				Protocol:      "udp",
+			},
+			{
+				ContainerPort: 445,
+				HostPort:      445,
+				Protocol:      "foobar",
+			},
+		},
+	}
+	exposedPorts, bindings := makePortsAndBindings(&container)
+	if len(container.Ports) != len(exposedPorts) ||
+		len(container.Ports) != len(bindings) {
+		t.Errorf("Unexpected ports and bindings, %#v %#v %#v", container, exposedPorts, bindings)
+	}
+	for key, value := range bindings {
+		switch value[0].HostPort {
+		case "8080":
+			if !reflect.DeepEqual(docker.Port("80/tcp"), key) {
+				t.Errorf("Unexpected docker port: %#v", key)
+			}
+			if value[0].HostIP != "127.0.0.1" {
+				t.Errorf("Unexpected host IP: %s", value[0].HostIP)
+			}
+		case "443":
+			if !reflect.DeepEqual(docker.Port("443/tcp"), key) {
+				t.Errorf("Unexpected docker port: %#v", key)
+			}
+			if value[0].HostIP != "" {
+				t.Errorf("Unexpected host IP: %s", value[0].HostIP)
+			}
+		case "444":
+			if !reflect.DeepEqual(docker.Port("444/udp"), key) {
+				t.Errorf("Unexpected docker port: %#v", key)
+			}
+			if value[0].HostIP != "" {
+				t.Errorf("Unexpected host IP: %s", value[0].HostIP)
+			}
+		case "445":
+			if !reflect.DeepEqual(docker.Port("445/tcp"), key) {
+				t.Errorf("Unexpected docker port: %#v", key)
+			}
+			if value[0].HostIP != "" {
+				t.Errorf("Unexpected host IP: %s", value[0].HostIP)
+			}
+		}
+	}
+}
+
+func TestMakeBinds(t *testing.T) {
+	mounts := []kubecontainer.Mount{
+		{Name: "disk", ContainerPath: "/mnt/path", HostPath: "/mnt/disk"},
+		{Name: "disk", ContainerPath: "/mnt/path3", HostPath: "/mnt/disk", ReadOnly: true},
+		{Name: "disk4", ContainerPath: "/mnt/path4", HostPath: "/mnt/host"},
+		{Name: "disk5", ContainerPath: "/mnt/path5", HostPath: "/var/lib/kubelet/podID/volumes/empty/disk5"},
+	}
+
+	binds := makeBinds(mounts)
+
+	expectedBinds := []string{
+		"/mnt/disk:/mnt/path",
+		"/mnt/disk:/mnt/path3:ro",
+		"/mnt/host:/mnt/path4",
+		"/var/lib/kubelet/podID/volumes/empty/disk5:/mnt/path5",
+	}
+	verifyStringArrayEquals(t, binds, expectedBinds)
+}
+
+func TestMilliCPUToQuota(t *testing.T) {
+	testCases := []struct {
+		milliCPU int64
+		quota    int64
+	}{
+		{milliCPU: 0, quota: 0},
+		{milliCPU: 5, quota: minQuotaPeriod},
+		{milliCPU: 250, quota: 25000},
+		{milliCPU: 1000, quota: quotaPeriod},
+		{milliCPU: 1500, quota: 150000},
+	}
+	for _, tc := range testCases {
+		if quota := milliCPUToQuota(tc.milliCPU); quota != tc.quota {
+			t.Errorf("milliCPU %d: expected quota %d, got %d", tc.milliCPU, tc.quota, quota)
+		}
+	}
+}
+
+type fakeOptionsGenerator struct{}
+
+func (fakeOptionsGenerator) GenerateRunContainerOptions(pod *api.Pod, container *api.Container) (*kubecontainer.RunContainerOptions, error) {
+	return &kubecontainer.RunContainerOptions{}, nil
+}
+
+func TestRunContainerSetsCPUQuotaFromLimit(t *testing.T) {
+	fakeDocker := &FakeDockerClient{}
+	manager := newTestDockerManager(fakeDocker)
+	manager.generator = fakeOptionsGenerator{}
+	pod := &api.Pod{ObjectMeta: api.ObjectMeta{UID: "12345678", Name: "foo", Namespace: "new"}}
+	container := &api.Container{
+		Name: "bar",
+		Resources: api.ResourceRequirements{
+			Limits:   api.ResourceList{api.ResourceCPU: resource.MustParse("500m")},
+			Requests: api.ResourceList{api.ResourceCPU: resource.MustParse("100m")},
+		},
+	}
+	if _, err := manager.runContainer(pod, container, "", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	hc := fakeDocker.Container.HostConfig
+	if hc.CPUQuota != 50000 || hc.CPUPeriod != quotaPeriod {
+		t.Errorf("expected a quota of 50000 over %d, got %d over %d", quotaPeriod, hc.CPUQuota, hc.CPUPeriod)
+	}
+}
@@ -30,45 +30,51 @@ func TestFromServices(t *testing.T) {
 			{
 				ObjectMeta: api.ObjectMeta{Name: "foo-bar"},
 				Spec: api.ServiceSpec{
-					Port:     8080,
 					Selector: map[string]string{"bar": "baz"},
-					Protocol: "TCP",
+					Ports:    []api.ServicePort{{Protocol: "TCP", Port: 8080}},
 					PortalIP: "1.2.3.4",
 				},
 			},
 			{
 				ObjectMeta: api.ObjectMeta{Name: "abc-123"},
 				Spec: api.ServiceSpec{
-					Port:     8081,
 					Selector: map[string]string{"bar": "baz"},
-					Protocol: "UDP",
+					Ports:    []api.ServicePort{{Protocol: "UDP", Port: 8081}},
 					PortalIP: "5.6.7.8",
 				},
 			},
 			{
 				ObjectMeta: api.ObjectMeta{Name: "q-u-u-x"},
 				Spec: api.ServiceSpec{
-					Port:     8082,
 					Selector: map[string]string{"bar": "baz"},
-					Protocol: "TCP",
+					Ports:    []api.ServicePort{{Protocol: "TCP", Port: 8082}},
 					PortalIP: "9.8.7.6",
 				},
 			},
+			{
+				ObjectMeta: api.ObjectMeta{Name: "multi-port-1"},
+				Spec: api.ServiceSpec{
+					Selector: map[string]string{"bar": "baz"},
+					PortalIP: "11.22.33.44",
+					Ports: []api.ServicePort{
+						{Name: "", Port: 8083, Protocol: "TCP"},
+						{Name: "u-d-p-30", Port: 8084, Protocol: "UDP"},
+					},
+				},
+			},
 			{
 				ObjectMeta: api.ObjectMeta{Name: "svrc-portalip-none"},
 				Spec: api.ServiceSpec{
-					Port:     8082,
 					Selector: map[string]string{"bar": "baz"},
-					Protocol: "TCP",
+					Ports:    []api.ServicePort{{Protocol: "TCP", Port: 8082}},
 					PortalIP: "None",
 				},
 			},
 			{
 				ObjectMeta: api.ObjectMeta{Name: "svrc-portalip-empty"},
 				Spec: api.ServiceSpec{
-					Port:     8082,
 					Selector: map[string]string{"bar": "baz"},
-					Protocol: "TCP",
+					Ports:    []api.ServicePort{{Protocol: "TCP", Port: 8082}},
 					PortalIP: "",
 				},
 			},
@@ -97,6 +103,18 @@ func TestFromServices(t *testing.T) {
 		{Name: "Q_U_U_X_PORT_8082_TCP_PROTO", Value: "tcp"},
 		{Name: "Q_U_U_X_PORT_8082_TCP_PORT", Value: "8082"},
 		{Name: "Q_U_U_X_PORT_8082_TCP_ADDR", Value: "9.8.7.6"},
+		{Name: "MULTI_PORT_1_SERVICE_HOST", Value: "11.22.33.44"},
+		{Name: "MULTI_PORT_1_SERVICE_PORT", Value: "8083"},
+		{Name: "MULTI_PORT_1_SERVICE_PORT_U_D_P_30", Value: "8084"},
+		{Name: "MULTI_PORT_1_PORT", Value: "tcp://11.22.33.44:8083"},
+		{Name: "MULTI_PORT_1_PORT_8083_TCP", Value: "tcp://11.22.33.44:8083"},
+		{Name: "MULTI_PORT_1_PORT_8083_TCP_PROTO", Value: "tcp"},
+		{Name: "MULTI_PORT_1_PORT_8083_TCP_PORT", Value: "8083"},
+		{Name: "MULTI_PORT_1_PORT_8083_TCP_ADDR", Value: "11.22.33.44"},
+		{Name: "MULTI_PORT_1_PORT_8084_UDP", Value: "udp://11.22.33.44:8084"},
+		{Name: "MULTI_PORT_1_PORT_8084_UDP_PROTO", Value: "udp"},
+		{Name: "MULTI_PORT_1_PORT_8084_UDP_PORT", Value: "8084"},
+		{Name: "MULTI_PORT_1_PORT_8084_UDP_ADDR", Value: "11.22.33.44"},
 	}
 	if len(vars) != len(expected) {
 		t.Errorf("Expected %d env vars, got: %+v", len(expected), vars)
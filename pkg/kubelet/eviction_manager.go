@@ -0,0 +1,263 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/cadvisor"
+	kubecontainer "github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/container"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/types"
+	"github.com/golang/glog"
+	cadvisorApi "github.com/google/cadvisor/info/v1"
+)
+
+// Specified a policy for evicting pods when the node runs low on resources.
+type EvictionPolicy struct {
+	// Minimum amount of memory in bytes that must stay available on the node.
+	// Below it, the node is under memory pressure. Zero disables memory eviction.
+	MemoryAvailableThreshold int64
+
+	// Minimum percent of the filesystem holding Docker images and containers
+	// that must stay available. Below it, the node is under disk pressure. Zero
+	// disables disk eviction.
+	DiskAvailableThresholdPercent int
+}
+
+// Resource pressure observed on the node, as the node conditions that are
+// under pressure and a human readable message for each.
+type nodePressure map[api.NodeConditionType]string
+
+// Watches the memory and disk usage of the node, and picks the pods to evict
+// when they run low.
+//
+// Implementation is thread-safe.
+type evictionManager interface {
+	// Observes the usage of the node against the eviction policy.
+	Observe() (nodePressure, error)
+
+	// Returns the pressure seen by the last observation.
+	LastPressure() nodePressure
+
+	// Picks the pod to evict among the specified pods to relieve the pressure,
+	// and the reason to record for it. Returns nil if no pod can be evicted.
+	PodToEvict(pods []*api.Pod, pressure nodePressure) (*api.Pod, string, error)
+}
+
+type realEvictionManager struct {
+	// cAdvisor instance.
+	cadvisor cadvisor.Interface
+
+	// Container runtime, to find the containers of pods.
+	runtime kubecontainer.Runtime
+
+	// The eviction policy in use.
+	policy EvictionPolicy
+
+	// Pressure seen by the last observation.
+	pressure     nodePressure
+	pressureLock sync.Mutex
+}
+
+func newEvictionManager(cadvisorInterface cadvisor.Interface, runtime kubecontainer.Runtime, policy EvictionPolicy) (evictionManager, error) {
+	if policy.MemoryAvailableThreshold < 0 {
+		return nil, fmt.Errorf("invalid MemoryAvailableThreshold %d, must not be negative", policy.MemoryAvailableThreshold)
+	}
+	if policy.DiskAvailableThresholdPercent < 0 || policy.DiskAvailableThresholdPercent > 100 {
+		return nil, fmt.Errorf("invalid DiskAvailableThresholdPercent %d, must be in range [0-100]", policy.DiskAvailableThresholdPercent)
+	}
+	return &realEvictionManager{
+		cadvisor: cadvisorInterface,
+		runtime:  runtime,
+		policy:   policy,
+		pressure: nodePressure{},
+	}, nil
+}
+
+func (self *realEvictionManager) Observe() (nodePressure, error) {
+	pressure := nodePressure{}
+
+	if self.policy.MemoryAvailableThreshold > 0 {
+		machineInfo, err := self.cadvisor.MachineInfo()
+		if err != nil {
+			return nil, err
+		}
+		rootInfo, err := self.cadvisor.ContainerInfo("/", &cadvisorApi.ContainerInfoRequest{NumStats: 1})
+		if err != nil {
+			return nil, err
+		}
+		if len(rootInfo.Stats) == 0 {
+			return nil, fmt.Errorf("no stats for the root container")
+		}
+		usage := int64(rootInfo.Stats[len(rootInfo.Stats)-1].Memory.WorkingSet)
+		available := machineInfo.MemoryCapacity - usage
+		if available < self.policy.MemoryAvailableThreshold {
+			pressure[api.NodeMemoryPressure] = fmt.Sprintf("available memory %d bytes is below the threshold of %d bytes", available, self.policy.MemoryAvailableThreshold)
+		}
+	}
+
+	if self.policy.DiskAvailableThresholdPercent > 0 {
+		fsInfo, err := self.cadvisor.DockerImagesFsInfo()
+		if err != nil {
+			return nil, err
+		}
+		if fsInfo.Capacity == 0 {
+			return nil, fmt.Errorf("invalid capacity %d on device %q at mount point %q", fsInfo.Capacity, fsInfo.Device, fsInfo.Mountpoint)
+		}
+		available := 0
+		if fsInfo.Usage < fsInfo.Capacity {
+			available = int((fsInfo.Capacity - fsInfo.Usage) * 100 / fsInfo.Capacity)
+		}
+		if available < self.policy.DiskAvailableThresholdPercent {
+			pressure[api.NodeDiskPressure] = fmt.Sprintf("available disk space %d%% on device %q is below the threshold of %d%%", available, fsInfo.Device, self.policy.DiskAvailableThresholdPercent)
+		}
+	}
+
+	self.pressureLock.Lock()
+	defer self.pressureLock.Unlock()
+	self.pressure = pressure
+	return pressure, nil
+}
+
+func (self *realEvictionManager) LastPressure() nodePressure {
+	self.pressureLock.Lock()
+	defer self.pressureLock.Unlock()
+	return self.pressure
+}
+
+// Information about a pod considered for eviction.
+type evictionCandidate struct {
+	pod *api.Pod
+
+	// Whether the pod requests no resources at all, which makes it the first
+	// to go.
+	bestEffort bool
+
+	// Usage of the resource under pressure beyond the pod's request, in bytes.
+	overRequest int64
+}
+
+// Best effort pods first, then the pods furthest over their request.
+type byEvictionOrder []evictionCandidate
+
+func (a byEvictionOrder) Len() int      { return len(a) }
+func (a byEvictionOrder) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a byEvictionOrder) Less(i, j int) bool {
+	if a[i].bestEffort != a[j].bestEffort {
+		return a[i].bestEffort
+	}
+	return a[i].overRequest > a[j].overRequest
+}
+
+func (self *realEvictionManager) PodToEvict(pods []*api.Pod, pressure nodePressure) (*api.Pod, string, error) {
+	// Memory pressure is the most urgent, as it makes the whole node unresponsive.
+	var condition api.NodeConditionType
+	if _, found := pressure[api.NodeMemoryPressure]; found {
+		condition = api.NodeMemoryPressure
+	} else if _, found := pressure[api.NodeDiskPressure]; found {
+		condition = api.NodeDiskPressure
+	} else {
+		return nil, "", nil
+	}
+
+	runningPods, err := self.runtime.GetPods(false)
+	if err != nil {
+		return nil, "", err
+	}
+	runningPodsByUID := make(map[types.UID]*kubecontainer.Pod)
+	for _, runningPod := range runningPods {
+		runningPodsByUID[runningPod.ID] = runningPod
+	}
+
+	candidates := []evictionCandidate{}
+	for _, pod := range pods {
+		// Static pods run the node itself and can't be rescheduled anywhere else.
+		if isStaticPod(pod) {
+			continue
+		}
+		runningPod, found := runningPodsByUID[pod.UID]
+		if !found {
+			continue
+		}
+		candidates = append(candidates, evictionCandidate{
+			pod:         pod,
+			bestEffort:  isBestEffort(pod),
+			overRequest: self.podUsage(runningPod, condition) - podRequest(pod, condition),
+		})
+	}
+	if len(candidates) == 0 {
+		return nil, "", nil
+	}
+	sort.Sort(byEvictionOrder(candidates))
+
+	return candidates[0].pod, fmt.Sprintf("The node was under %s: %s.", condition, pressure[condition]), nil
+}
+
+// Returns the memory or disk usage in bytes of the running containers of the
+// pod, depending on the condition.
+func (self *realEvictionManager) podUsage(runningPod *kubecontainer.Pod, condition api.NodeConditionType) int64 {
+	usage := int64(0)
+	for _, container := range runningPod.Containers {
+		// TODO: cAdvisor only knows about Docker containers for now.
+		info, err := self.cadvisor.DockerContainer(string(container.ID), &cadvisorApi.ContainerInfoRequest{NumStats: 1})
+		if err != nil {
+			glog.V(2).Infof("Failed to get stats of container %q of pod %q: %v", container.Name, runningPod.Name, err)
+			continue
+		}
+		if len(info.Stats) == 0 {
+			continue
+		}
+		stats := info.Stats[len(info.Stats)-1]
+		switch condition {
+		case api.NodeMemoryPressure:
+			usage += int64(stats.Memory.WorkingSet)
+		case api.NodeDiskPressure:
+			for _, fs := range stats.Filesystem {
+				usage += int64(fs.Usage)
+			}
+		}
+	}
+	return usage
+}
+
+// Returns the memory request in bytes of the pod for memory pressure. Pods
+// can't request disk space, so they are ranked by usage for disk pressure.
+func podRequest(pod *api.Pod, condition api.NodeConditionType) int64 {
+	if condition != api.NodeMemoryPressure {
+		return 0
+	}
+	request := int64(0)
+	for i := range pod.Spec.Containers {
+		request += pod.Spec.Containers[i].Resources.Requests.Memory().Value()
+	}
+	return request
+}
+
+// Returns true if none of the containers of the pod requests any resources.
+func isBestEffort(pod *api.Pod) bool {
+	for i := range pod.Spec.Containers {
+		for _, quantity := range pod.Spec.Containers[i].Resources.Requests {
+			if quantity.MilliValue() != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/cadvisor"
+	kubecontainer "github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/container"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/types"
+	cadvisorApi "github.com/google/cadvisor/info/v1"
+	cadvisorApiV2 "github.com/google/cadvisor/info/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRealEvictionManager(t *testing.T, policy EvictionPolicy) (evictionManager, *kubecontainer.FakeRuntime, *cadvisor.Mock) {
+	fakeRuntime := &kubecontainer.FakeRuntime{}
+	mockCadvisor := new(cadvisor.Mock)
+	manager, err := newEvictionManager(mockCadvisor, fakeRuntime, policy)
+	require.Nil(t, err)
+	return manager, fakeRuntime, mockCadvisor
+}
+
+// Makes a pod with one container per memory request, zero for no request.
+func makeEvictionPod(uid string, memoryRequests ...int64) *api.Pod {
+	pod := &api.Pod{
+		ObjectMeta: api.ObjectMeta{
+			UID:         types.UID(uid),
+			Name:        uid,
+			Namespace:   "new",
+			Annotations: map[string]string{ConfigSourceAnnotationKey: ApiserverSource},
+		},
+	}
+	for i, request := range memoryRequests {
+		container := api.Container{Name: fmt.Sprintf("%s-%d", uid, i)}
+		if request > 0 {
+			container.Resources.Requests = api.ResourceList{api.ResourceMemory: *resource.NewQuantity(request, resource.BinarySI)}
+		}
+		pod.Spec.Containers = append(pod.Spec.Containers, container)
+	}
+	return pod
+}
+
+// Makes the running pod of the pod, and mocks the usage of its containers.
+func makeRunningEvictionPod(mockCadvisor *cadvisor.Mock, pod *api.Pod, memoryUsage, diskUsage uint64) *kubecontainer.Pod {
+	runningPod := &kubecontainer.Pod{ID: pod.UID, Name: pod.Name, Namespace: pod.Namespace}
+	for _, container := range pod.Spec.Containers {
+		id := types.UID("docker-" + container.Name)
+		runningPod.Containers = append(runningPod.Containers, &kubecontainer.Container{ID: id, Name: container.Name})
+		mockCadvisor.On("DockerContainer", string(id), &cadvisorApi.ContainerInfoRequest{NumStats: 1}).Return(cadvisorApi.ContainerInfo{
+			Stats: []*cadvisorApi.ContainerStats{{
+				Memory:     cadvisorApi.MemoryStats{WorkingSet: memoryUsage},
+				Filesystem: []cadvisorApi.FsStats{{Usage: diskUsage}},
+			}},
+		}, nil)
+	}
+	return runningPod
+}
+
+func mockMemoryUsage(mockCadvisor *cadvisor.Mock, capacity int64, usage uint64) {
+	mockCadvisor.On("MachineInfo").Return(&cadvisorApi.MachineInfo{MemoryCapacity: capacity}, nil)
+	mockCadvisor.On("ContainerInfo", "/", &cadvisorApi.ContainerInfoRequest{NumStats: 1}).Return(&cadvisorApi.ContainerInfo{
+		Stats: []*cadvisorApi.ContainerStats{{Memory: cadvisorApi.MemoryStats{WorkingSet: usage}}},
+	}, nil)
+}
+
+func TestNewEvictionManagerInvalidPolicy(t *testing.T) {
+	_, err := newEvictionManager(new(cadvisor.Mock), &kubecontainer.FakeRuntime{}, EvictionPolicy{MemoryAvailableThreshold: -1})
+	assert.NotNil(t, err)
+	_, err = newEvictionManager(new(cadvisor.Mock), &kubecontainer.FakeRuntime{}, EvictionPolicy{DiskAvailableThresholdPercent: 101})
+	assert.NotNil(t, err)
+}
+
+func TestObserveNoPressure(t *testing.T) {
+	manager, _, mockCadvisor := newRealEvictionManager(t, EvictionPolicy{
+		MemoryAvailableThreshold:      100,
+		DiskAvailableThresholdPercent: 10,
+	})
+	mockMemoryUsage(mockCadvisor, 1000, 500)
+	mockCadvisor.On("DockerImagesFsInfo").Return(cadvisorApiV2.FsInfo{Capacity: 100, Usage: 50}, nil)
+
+	pressure, err := manager.Observe()
+	require.Nil(t, err)
+	assert.Len(t, pressure, 0)
+	assert.Len(t, manager.LastPressure(), 0)
+}
+
+func TestObserveMemoryPressure(t *testing.T) {
+	manager, _, mockCadvisor := newRealEvictionManager(t, EvictionPolicy{
+		MemoryAvailableThreshold: 100,
+	})
+	mockMemoryUsage(mockCadvisor, 1000, 950)
+
+	pressure, err := manager.Observe()
+	require.Nil(t, err)
+	assert.Len(t, pressure, 1)
+	assert.Contains(t, pressure[api.NodeMemoryPressure], "available memory 50 bytes")
+	assert.Equal(t, pressure, manager.LastPressure())
+}
+
+func TestObserveDiskPressure(t *testing.T) {
+	manager, _, mockCadvisor := newRealEvictionManager(t, EvictionPolicy{
+		DiskAvailableThresholdPercent: 5,
+	})
+	mockCadvisor.On("DockerImagesFsInfo").Return(cadvisorApiV2.FsInfo{Device: "/dev/sda1", Capacity: 100, Usage: 96}, nil)
+
+	pressure, err := manager.Observe()
+	require.Nil(t, err)
+	assert.Len(t, pressure, 1)
+	assert.Contains(t, pressure[api.NodeDiskPressure], "available disk space 4%")
+}
+
+func TestObserveDiskPressureInvalidCapacity(t *testing.T) {
+	manager, _, mockCadvisor := newRealEvictionManager(t, EvictionPolicy{
+		DiskAvailableThresholdPercent: 5,
+	})
+	mockCadvisor.On("DockerImagesFsInfo").Return(cadvisorApiV2.FsInfo{}, nil)
+
+	_, err := manager.Observe()
+	assert.NotNil(t, err)
+}
+
+func TestPodToEvictWithoutPressure(t *testing.T) {
+	manager, _, _ := newRealEvictionManager(t, EvictionPolicy{})
+
+	pod, _, err := manager.PodToEvict([]*api.Pod{makeEvictionPod("foo")}, nodePressure{})
+	require.Nil(t, err)
+	assert.Nil(t, pod)
+}
+
+func TestPodToEvictBestEffortFirst(t *testing.T) {
+	manager, fakeRuntime, mockCadvisor := newRealEvictionManager(t, EvictionPolicy{})
+	guaranteed := makeEvictionPod("guaranteed", 100)
+	bestEffort := makeEvictionPod("besteffort", 0)
+	fakeRuntime.Podlist = []*kubecontainer.Pod{
+		makeRunningEvictionPod(mockCadvisor, guaranteed, 1000, 0),
+		makeRunningEvictionPod(mockCadvisor, bestEffort, 10, 0),
+	}
+
+	pod, reason, err := manager.PodToEvict([]*api.Pod{guaranteed, bestEffort}, nodePressure{api.NodeMemoryPressure: "low memory"})
+	require.Nil(t, err)
+	assert.Equal(t, bestEffort, pod)
+	assert.Equal(t, "The node was under MemoryPressure: low memory.", reason)
+}
+
+func TestPodToEvictFurthestOverRequest(t *testing.T) {
+	manager, fakeRuntime, mockCadvisor := newRealEvictionManager(t, EvictionPolicy{})
+	// 2*150 - 2*100 = 100 bytes over its request.
+	small := makeEvictionPod("small", 100, 100)
+	// 600 - 550 = 50 bytes over its request.
+	large := makeEvictionPod("large", 550)
+	// Not running, so not using anything.
+	stopped := makeEvictionPod("stopped", 1)
+	fakeRuntime.Podlist = []*kubecontainer.Pod{
+		makeRunningEvictionPod(mockCadvisor, small, 150, 0),
+		makeRunningEvictionPod(mockCadvisor, large, 600, 0),
+	}
+
+	pod, _, err := manager.PodToEvict([]*api.Pod{large, small, stopped}, nodePressure{api.NodeMemoryPressure: "low memory"})
+	require.Nil(t, err)
+	assert.Equal(t, small, pod)
+}
+
+func TestPodToEvictDiskUsage(t *testing.T) {
+	manager, fakeRuntime, mockCadvisor := newRealEvictionManager(t, EvictionPolicy{})
+	small := makeEvictionPod("small", 100)
+	large := makeEvictionPod("large", 100)
+	fakeRuntime.Podlist = []*kubecontainer.Pod{
+		makeRunningEvictionPod(mockCadvisor, small, 500, 10),
+		makeRunningEvictionPod(mockCadvisor, large, 100, 1000),
+	}
+
+	pod, reason, err := manager.PodToEvict([]*api.Pod{small, large}, nodePressure{api.NodeDiskPressure: "low disk"})
+	require.Nil(t, err)
+	assert.Equal(t, large, pod)
+	assert.Equal(t, "The node was under DiskPressure: low disk.", reason)
+}
+
+func TestPodToEvictSkipsStaticPods(t *testing.T) {
+	manager, fakeRuntime, mockCadvisor := newRealEvictionManager(t, EvictionPolicy{})
+	static := makeEvictionPod("static", 0)
+	static.Annotations[ConfigSourceAnnotationKey] = FileSource
+	fakeRuntime.Podlist = []*kubecontainer.Pod{
+		makeRunningEvictionPod(mockCadvisor, static, 1000, 0),
+	}
+
+	pod, _, err := manager.PodToEvict([]*api.Pod{static}, nodePressure{api.NodeMemoryPressure: "low memory"})
+	require.Nil(t, err)
+	assert.Nil(t, pod)
+}
@@ -24,11 +24,22 @@ import (
 	"strconv"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kubecontainer "github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/container"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/types"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/golang/glog"
 )
 
+// kubeletHandlerRunner runs the lifecycle handlers of containers on behalf of
+// the container runtime.
+type kubeletHandlerRunner struct {
+	kubelet *Kubelet
+}
+
+func (hr *kubeletHandlerRunner) Run(containerID string, pod *api.Pod, container *api.Container, handler *api.Handler) error {
+	return hr.kubelet.runHandler(kubecontainer.GetPodFullName(pod), pod.UID, container, handler)
+}
+
 type execActionHandler struct {
 	kubelet *Kubelet
 }
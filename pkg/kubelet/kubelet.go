@@ -26,7 +26,6 @@ import (
 	"os"
 	"path"
 	"sort"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -45,8 +44,8 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/envvars"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/metrics"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/network"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/rkt"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
-	"github.com/GoogleCloudPlatform/kubernetes/pkg/probe"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/scheduler"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/types"
@@ -54,21 +53,11 @@ import (
 	utilErrors "github.com/GoogleCloudPlatform/kubernetes/pkg/util/errors"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/volume"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
-	"github.com/fsouza/go-dockerclient"
 	"github.com/golang/glog"
 	cadvisorApi "github.com/google/cadvisor/info/v1"
 )
 
 const (
-	// Taken from lmctfy https://github.com/google/lmctfy/blob/master/lmctfy/controllers/cpu_controller.cc
-	minShares     = 2
-	sharesPerCPU  = 1024
-	milliCPUToCPU = 1000
-
-	// The oom_score_adj of the POD infrastructure container. The default is 0, so
-	// any value below that makes it *less* likely to get OOM killed.
-	podOomScoreAdj = -100
-
 	// Max amount of time to wait for the Docker daemon to come up.
 	maxWaitForDocker = 5 * time.Minute
 
@@ -128,6 +117,7 @@ func NewMainKubelet(
 	pullQPS float32,
 	pullBurst int,
 	containerGCPolicy ContainerGCPolicy,
+	containerLogPolicy ContainerLogPolicy,
 	sourcesReady SourcesReadyFn,
 	clusterDomain string,
 	clusterDNS net.IP,
@@ -139,31 +129,16 @@ func NewMainKubelet(
 	recorder record.EventRecorder,
 	cadvisorInterface cadvisor.Interface,
 	imageGCPolicy ImageGCPolicy,
-	cloud cloudprovider.Interface) (*Kubelet, error) {
+	evictionPolicy EvictionPolicy,
+	cloud cloudprovider.Interface,
+	containerRuntime string,
+	rktInsecureSkipVerify bool) (*Kubelet, error) {
 	if rootDirectory == "" {
 		return nil, fmt.Errorf("invalid root directory %q", rootDirectory)
 	}
 	if resyncInterval <= 0 {
 		return nil, fmt.Errorf("invalid sync frequency %d", resyncInterval)
 	}
-	dockerClient = metrics.NewInstrumentedDockerInterface(dockerClient)
-
-	// Wait for the Docker daemon to be up (with a timeout).
-	waitStart := time.Now()
-	dockerUp := false
-	for time.Since(waitStart) < maxWaitForDocker {
-		_, err := dockerClient.Version()
-		if err == nil {
-			dockerUp = true
-			break
-		}
-
-		time.Sleep(100 * time.Millisecond)
-	}
-	if !dockerUp {
-		return nil, fmt.Errorf("timed out waiting for Docker to come up")
-	}
-
 	serviceStore := cache.NewStore(cache.MetaNamespaceKeyFunc)
 	if kubeClient != nil {
 		// TODO: cache.NewListWatchFromClient is limited as it takes a client implementation rather
@@ -201,28 +176,16 @@ func NewMainKubelet(
 	}
 	nodeLister := &cache.StoreToNodeLister{nodeStore}
 
-	containerGC, err := newContainerGC(dockerClient, containerGCPolicy)
-	if err != nil {
-		return nil, err
-	}
-	imageManager, err := newImageManager(dockerClient, cadvisorInterface, imageGCPolicy)
-	if err != nil {
-		return nil, fmt.Errorf("failed to initialize image manager: %v", err)
-	}
 	statusManager := newStatusManager(kubeClient)
 
 	klet := &Kubelet{
 		hostname:                       hostname,
-		dockerClient:                   dockerClient,
 		kubeClient:                     kubeClient,
 		rootDirectory:                  rootDirectory,
 		resyncInterval:                 resyncInterval,
-		podInfraContainerImage:         podInfraContainerImage,
-		containerIDToRef:               map[string]*api.ObjectReference{},
-		runner:                         dockertools.NewDockerContainerCommandRunner(dockerClient),
+		containerRefManager:            kubecontainer.NewRefManager(),
+		readinessManager:               kubecontainer.NewReadinessManager(),
 		httpClient:                     &http.Client{},
-		pullQPS:                        pullQPS,
-		pullBurst:                      pullBurst,
 		sourcesReady:                   sourcesReady,
 		clusterDomain:                  clusterDomain,
 		clusterDNS:                     clusterDNS,
@@ -230,31 +193,21 @@ func NewMainKubelet(
 		nodeLister:                     nodeLister,
 		masterServiceNamespace:         masterServiceNamespace,
 		prober:                         newProbeHolder(),
-		readiness:                      newReadinessStates(),
 		streamingConnectionIdleTimeout: streamingConnectionIdleTimeout,
 		recorder:                       recorder,
 		cadvisor:                       cadvisorInterface,
-		containerGC:                    containerGC,
-		imageManager:                   imageManager,
 		statusManager:                  statusManager,
 		cloud:                          cloud,
+		volumeManager:                  newVolumeManager(),
 	}
 
 	klet.podManager = newBasicPodManager(klet.kubeClient)
+	klet.probeWorkers = newProbeWorkers(klet.readinessManager, klet.containerRefManager, recorder, klet.runProbe)
 
-	dockerCache, err := dockertools.NewDockerCache(dockerClient)
-	if err != nil {
-		return nil, err
-	}
-	klet.dockerCache = dockerCache
-	klet.podWorkers = newPodWorkers(dockerCache, klet.syncPod, recorder)
-
-	metrics.Register(dockerCache)
-
-	if err = klet.setupDataDirs(); err != nil {
+	if err := klet.setupDataDirs(); err != nil {
 		return nil, err
 	}
-	if err = klet.volumePluginMgr.InitPlugins(volumePlugins, &volumeHost{klet}); err != nil {
+	if err := klet.volumePluginMgr.InitPlugins(volumePlugins, &volumeHost{klet}); err != nil {
 		return nil, err
 	}
 
@@ -264,9 +217,93 @@ func NewMainKubelet(
 		klet.networkPlugin = plug
 	}
 
+	// Initialize the runtime.
+	switch containerRuntime {
+	case "docker":
+		dockerClient = dockertools.NewInstrumentedDockerInterface(dockerClient)
+		if err := waitForDocker(dockerClient); err != nil {
+			return nil, err
+		}
+		klet.containerRuntime = dockertools.NewDockerManager(
+			dockerClient,
+			recorder,
+			klet.readinessManager,
+			klet.containerRefManager,
+			podInfraContainerImage,
+			pullQPS,
+			pullBurst,
+			klet.networkPlugin,
+			klet,
+			&kubeletProber{klet},
+			&kubeletHandlerRunner{klet})
+
+		// Garbage collection of dead containers and unused images, and
+		// rotation of container logs are only implemented for Docker.
+		containerGC, err := newContainerGC(dockerClient, containerGCPolicy)
+		if err != nil {
+			return nil, err
+		}
+		klet.containerGC = containerGC
+		containerLogManager, err := newContainerLogManager(dockerClient, containerLogPolicy)
+		if err != nil {
+			return nil, err
+		}
+		klet.containerLogManager = containerLogManager
+		imageManager, err := newImageManager(dockerClient, cadvisorInterface, imageGCPolicy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize image manager: %v", err)
+		}
+		klet.imageManager = imageManager
+	case "rkt":
+		conf := &rkt.Config{InsecureSkipVerify: rktInsecureSkipVerify}
+		rktRuntime, err := rkt.New(
+			conf,
+			klet,
+			recorder,
+			klet.containerRefManager,
+			klet.readinessManager,
+			&kubeletProber{klet})
+		if err != nil {
+			return nil, err
+		}
+		klet.containerRuntime = rktRuntime
+	default:
+		return nil, fmt.Errorf("unsupported container runtime %q specified", containerRuntime)
+	}
+	klet.runner = klet.containerRuntime
+
+	evictionManager, err := newEvictionManager(cadvisorInterface, klet.containerRuntime, evictionPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize eviction manager: %v", err)
+	}
+	klet.evictionManager = evictionManager
+
+	runtimeCache, err := kubecontainer.NewRuntimeCache(klet.containerRuntime)
+	if err != nil {
+		return nil, err
+	}
+	klet.runtimeCache = runtimeCache
+	klet.podWorkers = newPodWorkers(runtimeCache, klet.syncPod, recorder)
+
+	metrics.Register(runtimeCache)
+
 	return klet, nil
 }
 
+// waitForDocker waits for the Docker daemon to be up (with a timeout).
+func waitForDocker(dockerClient dockertools.DockerInterface) error {
+	waitStart := time.Now()
+	for time.Since(waitStart) < maxWaitForDocker {
+		_, err := dockerClient.Version()
+		if err == nil {
+			return nil
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+	return fmt.Errorf("timed out waiting for Docker to come up")
+}
+
 type httpGetter interface {
 	Get(url string) (*http.Response, error)
 }
@@ -282,35 +319,26 @@ type nodeLister interface {
 
 // Kubelet is the main kubelet implementation.
 type Kubelet struct {
-	hostname               string
-	dockerClient           dockertools.DockerInterface
-	dockerCache            dockertools.DockerCache
-	kubeClient             client.Interface
-	rootDirectory          string
-	podInfraContainerImage string
-	podWorkers             *podWorkers
-	resyncInterval         time.Duration
-	sourcesReady           SourcesReadyFn
+	hostname       string
+	runtimeCache   kubecontainer.RuntimeCache
+	kubeClient     client.Interface
+	rootDirectory  string
+	podWorkers     *podWorkers
+	resyncInterval time.Duration
+	sourcesReady   SourcesReadyFn
 
 	podManager podManager
 
 	// Needed to report events for containers belonging to deleted/modified pods.
 	// Tracks references for reporting events
-	containerIDToRef map[string]*api.ObjectReference
-	refLock          sync.RWMutex
+	containerRefManager *kubecontainer.RefManager
 
-	// Optional, defaults to simple Docker implementation
-	dockerPuller dockertools.DockerPuller
 	// Optional, defaults to /logs/ from /var/log
 	logServer http.Handler
-	// Optional, defaults to simple Docker implementation
-	runner dockertools.ContainerCommandRunner
+	// Optional, defaults to the container runtime
+	runner kubecontainer.ContainerCommandRunner
 	// Optional, client for http requests, defaults to empty client
 	httpClient httpGetter
-	// Optional, maximum pull QPS from the docker registry, 0.0 means unlimited.
-	pullQPS float32
-	// Optional, maximum burst QPS from the docker registry, must be positive if QPS is > 0.0
-	pullBurst int
 
 	// cAdvisor used for container information.
 	cadvisor cadvisor.Interface
@@ -333,8 +361,10 @@ type Kubelet struct {
 
 	// Probe runner holder
 	prober probeHolder
-	// Container readiness state holder
-	readiness *readinessStates
+	// Runs the liveness and readiness probes of the containers.
+	probeWorkers *probeWorkers
+	// Container readiness state manager.
+	readinessManager *kubecontainer.ReadinessManager
 
 	// How long to keep idle streaming command execution/port forwarding
 	// connections open before terminating them
@@ -346,9 +376,20 @@ type Kubelet struct {
 	// Policy for handling garbage collection of dead containers.
 	containerGC containerGC
 
+	// Manager for rotating the logs of containers.
+	containerLogManager containerLogManager
+
 	// Manager for images.
 	imageManager imageManager
 
+	// Manager for evicting pods when the node runs low on resources.
+	evictionManager evictionManager
+
+	// The last evicted pod, until its containers are gone. SyncPods leaves
+	// them to the eviction loop, which kills them with the pod's grace period.
+	evictedPod     *api.Pod
+	evictedPodLock sync.Mutex
+
 	// Cached MachineInfo returned by cadvisor.
 	machineInfo *cadvisorApi.MachineInfo
 
@@ -357,6 +398,12 @@ type Kubelet struct {
 
 	//Cloud provider interface
 	cloud cloudprovider.Interface
+
+	// Container runtime.
+	containerRuntime kubecontainer.Runtime
+
+	// Manager of the volumes mounted for the pods.
+	volumeManager *volumeManager
 }
 
 // getRootDir returns the full path to the directory under which kubelet can
@@ -514,17 +561,125 @@ func (kl *Kubelet) GetNode() (*api.Node, error) {
 
 // Starts garbage collection theads.
 func (kl *Kubelet) StartGarbageCollection() {
-	go util.Forever(func() {
-		if err := kl.containerGC.GarbageCollect(); err != nil {
-			glog.Errorf("Container garbage collection failed: %v", err)
+	if kl.containerGC != nil {
+		go util.Forever(func() {
+			if err := kl.containerGC.GarbageCollect(); err != nil {
+				glog.Errorf("Container garbage collection failed: %v", err)
+			}
+		}, time.Minute)
+	}
+
+	if kl.imageManager != nil {
+		go util.Forever(func() {
+			if err := kl.imageManager.GarbageCollect(); err != nil {
+				glog.Errorf("Image garbage collection failed: %v", err)
+			}
+		}, 5*time.Minute)
+	}
+}
+
+// Starts the log rotation thread.
+func (kl *Kubelet) StartLogRotation() {
+	if kl.containerLogManager != nil {
+		go util.Forever(func() {
+			if err := kl.containerLogManager.RotateLogs(); err != nil {
+				glog.Errorf("Container log rotation failed: %v", err)
+			}
+		}, time.Minute)
+	}
+}
+
+// Starts the thread evicting pods when the node runs low on resources.
+func (kl *Kubelet) StartEviction() {
+	if kl.evictionManager != nil {
+		go util.Forever(kl.evictPods, 10*time.Second)
+	}
+}
+
+// evictPods evicts a pod if the node is under memory or disk pressure. The
+// evicted pod is failed and its containers are killed with its grace period.
+// No other pod is evicted until they are gone, since their usage still counts
+// towards the pressure until then.
+func (kl *Kubelet) evictPods() {
+	pressure, err := kl.evictionManager.Observe()
+	if err != nil {
+		glog.Errorf("Failed to observe resource pressure: %v", err)
+		return
+	}
+	if len(pressure) == 0 {
+		return
+	}
+
+	if evicted := kl.getEvictedPod(); evicted != nil {
+		runningPods, err := kl.containerRuntime.GetPods(false)
+		if err != nil {
+			glog.Errorf("Failed to list the running pods: %v", err)
+			return
 		}
-	}, time.Minute)
+		if runningPod := kubecontainer.Pods(runningPods).FindPodByID(evicted.UID); len(runningPod.Containers) > 0 {
+			glog.V(2).Infof("Waiting for the containers of evicted pod %q to be killed", kubecontainer.GetPodFullName(evicted))
+			kl.killEvictedPod(evicted, runningPod)
+			return
+		}
+		kl.setEvictedPod(nil)
+	}
 
-	go util.Forever(func() {
-		if err := kl.imageManager.GarbageCollect(); err != nil {
-			glog.Errorf("Image garbage collection failed: %v", err)
+	allPods := kl.GetPods()
+	pods := make([]*api.Pod, 0, len(allPods))
+	for i := range allPods {
+		pod := &allPods[i]
+		status, ok := kl.statusManager.GetPodStatus(kubecontainer.GetPodFullName(pod))
+		if ok && (status.Phase == api.PodFailed || status.Phase == api.PodSucceeded) {
+			continue
 		}
-	}, 5*time.Minute)
+		pods = append(pods, pod)
+	}
+	pod, reason, err := kl.evictionManager.PodToEvict(pods, pressure)
+	if err != nil {
+		glog.Errorf("Failed to pick a pod to evict: %v", err)
+		return
+	}
+	if pod == nil {
+		glog.Warningf("The node is under resource pressure but no pod can be evicted: %v", pressure)
+		return
+	}
+
+	glog.Infof("Evicting pod %q: %s", kubecontainer.GetPodFullName(pod), reason)
+	kl.recorder.Eventf(pod, "evicted", reason)
+	kl.setEvictedPod(pod)
+	kl.statusManager.SetPodStatus(pod, api.PodStatus{
+		Phase:   api.PodFailed,
+		Message: reason})
+
+	runningPods, err := kl.containerRuntime.GetPods(false)
+	if err != nil {
+		glog.Errorf("Failed to list the running pods: %v", err)
+		return
+	}
+	kl.killEvictedPod(pod, kubecontainer.Pods(runningPods).FindPodByID(pod.UID))
+}
+
+// killEvictedPod kills the containers of an evicted pod, honoring its
+// termination grace period and preStop hooks.
+func (kl *Kubelet) killEvictedPod(pod *api.Pod, runningPod kubecontainer.Pod) {
+	if len(runningPod.Containers) == 0 {
+		return
+	}
+	if err := kl.containerRuntime.KillPod(pod, runningPod); err != nil {
+		glog.Errorf("Failed to kill evicted pod %q: %v", kubecontainer.GetPodFullName(pod), err)
+	}
+}
+
+func (kl *Kubelet) getEvictedPod() *api.Pod {
+	kl.evictedPodLock.Lock()
+	defer kl.evictedPodLock.Unlock()
+	return kl.evictedPod
+}
+
+func (kl *Kubelet) setEvictedPod(pod *api.Pod) {
+	kl.evictedPodLock.Lock()
+	defer kl.evictedPodLock.Unlock()
+	kl.evictedPod = pod
 }
 
 // Run starts the kubelet reacting to config updates
@@ -532,9 +687,6 @@ func (kl *Kubelet) Run(updates <-chan PodUpdate) {
 	if kl.logServer == nil {
 		kl.logServer = http.StripPrefix("/logs/", http.FileServer(http.Dir("/var/log/")))
 	}
-	if kl.dockerPuller == nil {
-		kl.dockerPuller = dockertools.NewDockerPuller(kl.dockerClient, kl.pullQPS, kl.pullBurst)
-	}
 	if kl.kubeClient == nil {
 		glog.Warning("No api server defined - no node status update will be sent.")
 	}
@@ -567,81 +719,21 @@ func (kl *Kubelet) syncNodeStatus() {
 	}
 }
 
-func makeBinds(container *api.Container, podVolumes volumeMap) []string {
-	binds := []string{}
+func makeMounts(container *api.Container, podVolumes volumeMap) []kubecontainer.Mount {
+	var mounts []kubecontainer.Mount
 	for _, mount := range container.VolumeMounts {
 		vol, ok := podVolumes[mount.Name]
 		if !ok {
 			continue
 		}
-		b := fmt.Sprintf("%s:%s", vol.GetPath(), mount.MountPath)
-		if mount.ReadOnly {
-			b += ":ro"
-		}
-		binds = append(binds, b)
-	}
-	return binds
-}
-
-func makePortsAndBindings(container *api.Container) (map[docker.Port]struct{}, map[docker.Port][]docker.PortBinding) {
-	exposedPorts := map[docker.Port]struct{}{}
-	portBindings := map[docker.Port][]docker.PortBinding{}
-	for _, port := range container.Ports {
-		exteriorPort := port.HostPort
-		if exteriorPort == 0 {
-			// No need to do port binding when HostPort is not specified
-			continue
-		}
-		interiorPort := port.ContainerPort
-		// Some of this port stuff is under-documented voodoo.
-		// See http://stackoverflow.com/questions/20428302/binding-a-port-to-a-host-interface-using-the-rest-api
-		var protocol string
-		switch strings.ToUpper(string(port.Protocol)) {
-		case "UDP":
-			protocol = "/udp"
-		case "TCP":
-			protocol = "/tcp"
-		default:
-			glog.Warningf("Unknown protocol %q: defaulting to TCP", port.Protocol)
-			protocol = "/tcp"
-		}
-		dockerPort := docker.Port(strconv.Itoa(interiorPort) + protocol)
-		exposedPorts[dockerPort] = struct{}{}
-		portBindings[dockerPort] = []docker.PortBinding{
-			{
-				HostPort: strconv.Itoa(exteriorPort),
-				HostIP:   port.HostIP,
-			},
-		}
-	}
-	return exposedPorts, portBindings
-}
-
-func milliCPUToShares(milliCPU int64) int64 {
-	if milliCPU == 0 {
-		// zero milliCPU means unset. Use kernel default.
-		return 0
-	}
-	// Conceptually (milliCPU / milliCPUToCPU) * sharesPerCPU, but factored to improve rounding.
-	shares := (milliCPU * sharesPerCPU) / milliCPUToCPU
-	if shares < minShares {
-		return minShares
-	}
-	return shares
-}
-
-func makeCapabilites(capAdd []api.CapabilityType, capDrop []api.CapabilityType) ([]string, []string) {
-	var (
-		addCaps  []string
-		dropCaps []string
-	)
-	for _, cap := range capAdd {
-		addCaps = append(addCaps, string(cap))
-	}
-	for _, cap := range capDrop {
-		dropCaps = append(dropCaps, string(cap))
+		mounts = append(mounts, kubecontainer.Mount{
+			Name:          mount.Name,
+			ContainerPath: mount.MountPath,
+			HostPath:      vol.GetPath(),
+			ReadOnly:      mount.ReadOnly,
+		})
 	}
-	return addCaps, dropCaps
+	return mounts
 }
 
 // A basic interface that knows how to execute handlers
@@ -669,173 +761,34 @@ func (kl *Kubelet) runHandler(podFullName string, uid types.UID, container *api.
 	return actionHandler.Run(podFullName, uid, container, handler)
 }
 
-// fieldPath returns a fieldPath locating container within pod.
-// Returns an error if the container isn't part of the pod.
-func fieldPath(pod *api.Pod, container *api.Container) (string, error) {
-	for i := range pod.Spec.Containers {
-		here := &pod.Spec.Containers[i]
-		if here.Name == container.Name {
-			if here.Name == "" {
-				return fmt.Sprintf("spec.containers[%d]", i), nil
-			} else {
-				return fmt.Sprintf("spec.containers{%s}", here.Name), nil
-			}
-		}
-	}
-	return "", fmt.Errorf("container %#v not found in pod %#v", container, pod)
-}
-
-// containerRef returns an *api.ObjectReference which references the given container within the
-// given pod. Returns an error if the reference can't be constructed or the container doesn't
-// actually belong to the pod.
-// TODO: Pods that came to us by static config or over HTTP have no selfLink set, which makes
-// this fail and log an error. Figure out how we want to identify these pods to the rest of the
-// system.
-func containerRef(pod *api.Pod, container *api.Container) (*api.ObjectReference, error) {
-	fieldPath, err := fieldPath(pod, container)
-	if err != nil {
-		// TODO: figure out intelligent way to refer to containers that we implicitly
-		// start (like the pod infra container). This is not a good way, ugh.
-		fieldPath = "implicitly required container " + container.Name
+// GenerateRunContainerOptions generates the RunContainerOptions, which can be used by
+// the container runtime to set parameters for launching a container.
+func (kl *Kubelet) GenerateRunContainerOptions(pod *api.Pod, container *api.Container) (*kubecontainer.RunContainerOptions, error) {
+	var err error
+	opts := &kubecontainer.RunContainerOptions{}
+	if vol, ok := kl.volumeManager.GetVolumes(pod.UID); ok {
+		opts.Mounts = makeMounts(container, vol)
 	}
-	ref, err := api.GetPartialReference(pod, fieldPath)
+	opts.Envs, err = kl.makeEnvironmentVariables(pod, container)
 	if err != nil {
 		return nil, err
 	}
-	return ref, nil
-}
-
-// setRef stores a reference to a pod's container, associating it with the given docker id.
-func (kl *Kubelet) setRef(id string, ref *api.ObjectReference) {
-	kl.refLock.Lock()
-	defer kl.refLock.Unlock()
-	if kl.containerIDToRef == nil {
-		kl.containerIDToRef = map[string]*api.ObjectReference{}
-	}
-	kl.containerIDToRef[id] = ref
-}
-
-// clearRef forgets the given docker id and its associated container reference.
-func (kl *Kubelet) clearRef(id string) {
-	kl.refLock.Lock()
-	defer kl.refLock.Unlock()
-	delete(kl.containerIDToRef, id)
-}
-
-// getRef returns the container reference of the given id, or (nil, false) if none is stored.
-func (kl *Kubelet) getRef(id string) (ref *api.ObjectReference, ok bool) {
-	kl.refLock.RLock()
-	defer kl.refLock.RUnlock()
-	ref, ok = kl.containerIDToRef[id]
-	return ref, ok
-}
-
-// Run a single container from a pod. Returns the docker container ID
-func (kl *Kubelet) runContainer(pod *api.Pod, container *api.Container, podVolumes volumeMap, netMode, ipcMode string) (id dockertools.DockerID, err error) {
-	ref, err := containerRef(pod, container)
-	if err != nil {
-		glog.Errorf("Couldn't make a ref to pod %v, container %v: '%v'", pod.Name, container.Name, err)
-	}
-
-	envVariables, err := kl.makeEnvironmentVariables(pod.Namespace, container)
-	if err != nil {
-		return "", err
-	}
-	binds := makeBinds(container, podVolumes)
-	exposedPorts, portBindings := makePortsAndBindings(container)
-
-	// TODO(vmarmol): Handle better.
-	// Cap hostname at 63 chars (specification is 64bytes which is 63 chars and the null terminating char).
-	const hostnameMaxLen = 63
-	containerHostname := pod.Name
-	if len(containerHostname) > hostnameMaxLen {
-		containerHostname = containerHostname[:hostnameMaxLen]
-	}
-	opts := docker.CreateContainerOptions{
-		Name: dockertools.BuildDockerName(dockertools.KubeletContainerName{kubecontainer.GetPodFullName(pod), pod.UID, container.Name}, container),
-		Config: &docker.Config{
-			Cmd:          container.Command,
-			Env:          envVariables,
-			ExposedPorts: exposedPorts,
-			Hostname:     containerHostname,
-			Image:        container.Image,
-			Memory:       container.Resources.Limits.Memory().Value(),
-			CPUShares:    milliCPUToShares(container.Resources.Limits.Cpu().MilliValue()),
-			WorkingDir:   container.WorkingDir,
-		},
-	}
-	dockerContainer, err := kl.dockerClient.CreateContainer(opts)
-	if err != nil {
-		if ref != nil {
-			kl.recorder.Eventf(ref, "failed", "Failed to create docker container with error: %v", err)
-		}
-		return "", err
-	}
-	// Remember this reference so we can report events about this container
-	if ref != nil {
-		kl.setRef(dockerContainer.ID, ref)
-		kl.recorder.Eventf(ref, "created", "Created with docker id %v", dockerContainer.ID)
-	}
 
 	if len(container.TerminationMessagePath) != 0 {
 		p := kl.getPodContainerDir(pod.UID, container.Name)
 		if err := os.MkdirAll(p, 0750); err != nil {
 			glog.Errorf("Error on creating %q: %v", p, err)
 		} else {
-			containerLogPath := path.Join(p, dockerContainer.ID)
-			fs, err := os.Create(containerLogPath)
-			if err != nil {
-				// TODO: Clean up the previouly created dir? return the error?
-				glog.Errorf("Error on creating termination-log file %q: %v", containerLogPath, err)
-			} else {
-				fs.Close() // Close immediately; we're just doing a `touch` here
-				b := fmt.Sprintf("%s:%s", containerLogPath, container.TerminationMessagePath)
-				binds = append(binds, b)
-			}
+			opts.PodContainerDir = p
 		}
 	}
-	privileged := false
-	if capabilities.Get().AllowPrivileged {
-		privileged = container.Privileged
-	} else if container.Privileged {
-		return "", fmt.Errorf("container requested privileged mode, but it is disallowed globally.")
-	}
-
-	capAdd, capDrop := makeCapabilites(container.Capabilities.Add, container.Capabilities.Drop)
-	hc := &docker.HostConfig{
-		PortBindings: portBindings,
-		Binds:        binds,
-		NetworkMode:  netMode,
-		IpcMode:      ipcMode,
-		Privileged:   privileged,
-		CapAdd:       capAdd,
-		CapDrop:      capDrop,
-	}
 	if pod.Spec.DNSPolicy == api.DNSClusterFirst {
-		if err := kl.applyClusterDNS(hc, pod); err != nil {
-			return "", err
-		}
-	}
-	err = kl.dockerClient.StartContainer(dockerContainer.ID, hc)
-	if err != nil {
-		if ref != nil {
-			kl.recorder.Eventf(ref, "failed",
-				"Failed to start with docker id %v with error: %v", dockerContainer.ID, err)
-		}
-		return "", err
-	}
-	if ref != nil {
-		kl.recorder.Eventf(ref, "started", "Started with docker id %v", dockerContainer.ID)
-	}
-
-	if container.Lifecycle != nil && container.Lifecycle.PostStart != nil {
-		handlerErr := kl.runHandler(kubecontainer.GetPodFullName(pod), pod.UID, container, container.Lifecycle.PostStart)
-		if handlerErr != nil {
-			kl.killContainerByID(dockerContainer.ID)
-			return dockertools.DockerID(""), fmt.Errorf("failed to call event handler: %v", handlerErr)
+		opts.DNS, opts.DNSSearch, err = kl.getClusterDNS(pod)
+		if err != nil {
+			return nil, err
 		}
 	}
-	return dockertools.DockerID(dockerContainer.ID), err
+	return opts, nil
 }
 
 var masterServices = util.NewStringSet("kubernetes", "kubernetes-ro")
@@ -893,8 +846,8 @@ func (kl *Kubelet) getServiceEnvVarMap(ns string) (map[string]string, error) {
 	return m, nil
 }
 
-// Make the service environment variables for a pod in the given namespace.
-func (kl *Kubelet) makeEnvironmentVariables(ns string, container *api.Container) ([]string, error) {
+// Make the environment variables for a container of the given pod.
+func (kl *Kubelet) makeEnvironmentVariables(pod *api.Pod, container *api.Container) ([]string, error) {
 	var result []string
 	// Note:  These are added to the docker.Config, but are not included in the checksum computed
 	// by dockertools.BuildDockerName(...).  That way, we can still determine whether an
@@ -905,11 +858,13 @@ func (kl *Kubelet) makeEnvironmentVariables(ns string, container *api.Container)
 	// To avoid this users can: (1) wait between starting a service and starting; or (2) detect
 	// missing service env var and exit and be restarted; or (3) use DNS instead of env vars
 	// and keep trying to resolve the DNS name of the service (recommended).
+	ns := pod.Namespace
 	serviceEnv, err := kl.getServiceEnvVarMap(ns)
 	if err != nil {
 		return result, err
 	}
 
+	secrets := map[string]*api.Secret{}
 	for _, value := range container.Env {
 		// Accesses apiserver+Pods.
 		// So, the master may set service env vars, or kubelet may.  In case both are doing
@@ -917,7 +872,19 @@ func (kl *Kubelet) makeEnvironmentVariables(ns string, container *api.Container)
 		// env vars.
 		// TODO: remove this net line once all platforms use apiserver+Pods.
 		delete(serviceEnv, value.Name)
-		result = append(result, fmt.Sprintf("%s=%s", value.Name, value.Value))
+		runtimeValue := value.Value
+		if value.ValueFrom != nil {
+			switch {
+			case value.ValueFrom.SecretKeyRef != nil:
+				runtimeValue, err = kl.secretKeyValue(ns, value.ValueFrom.SecretKeyRef, secrets)
+			case value.ValueFrom.FieldRef != nil:
+				runtimeValue, err = kl.podFieldValue(pod, value.ValueFrom.FieldRef)
+			}
+			if err != nil {
+				return result, fmt.Errorf("unable to set env var %q: %v", value.Name, err)
+			}
+		}
+		result = append(result, fmt.Sprintf("%s=%s", value.Name, runtimeValue))
 	}
 
 	// Append remaining service env vars.
@@ -927,27 +894,107 @@ func (kl *Kubelet) makeEnvironmentVariables(ns string, container *api.Container)
 	return result, nil
 }
 
-func (kl *Kubelet) applyClusterDNS(hc *docker.HostConfig, pod *api.Pod) error {
+// secretKeyValue returns the value of the secret key selected by selector in
+// namespace ns. Secrets are fetched from the master once and kept in secrets.
+func (kl *Kubelet) secretKeyValue(ns string, selector *api.SecretKeySelector, secrets map[string]*api.Secret) (string, error) {
+	secret, found := secrets[selector.Name]
+	if !found {
+		if kl.kubeClient == nil {
+			return "", fmt.Errorf("cannot get secret %q without an api server", selector.Name)
+		}
+		var err error
+		secret, err = kl.kubeClient.Secrets(ns).Get(selector.Name)
+		if err != nil {
+			return "", fmt.Errorf("couldn't get secret %s/%s: %v", ns, selector.Name, err)
+		}
+		secrets[selector.Name] = secret
+	}
+	value, found := secret.Data[selector.Key]
+	if !found {
+		return "", fmt.Errorf("couldn't find key %q in secret %s/%s", selector.Key, ns, selector.Name)
+	}
+	return string(value), nil
+}
+
+// podFieldValue returns the value of the pod field selected by selector.
+func (kl *Kubelet) podFieldValue(pod *api.Pod, selector *api.ObjectFieldSelector) (string, error) {
+	switch selector.FieldPath {
+	case "metadata.name":
+		return pod.Name, nil
+	case "metadata.namespace":
+		return pod.Namespace, nil
+	case "status.podIP":
+		// The pod infra container has been started by the time the other
+		// containers are created, so ask the runtime rather than relying on
+		// the status last reported to the master.
+		status, err := kl.containerRuntime.GetPodStatus(pod)
+		if err != nil {
+			return "", fmt.Errorf("couldn't get status of pod %q: %v", kubecontainer.GetPodFullName(pod), err)
+		}
+		if status.PodIP == "" {
+			return "", fmt.Errorf("pod %q has no IP yet", kubecontainer.GetPodFullName(pod))
+		}
+		return status.PodIP, nil
+	}
+	return "", fmt.Errorf("unsupported field path %q", selector.FieldPath)
+}
+
+// getPullSecretsForPod returns the dockercfg secrets referenced by the pod's
+// ImagePullSecrets. Secrets that cannot be retrieved are skipped, so that
+// images which do not need them can still be pulled.
+func (kl *Kubelet) getPullSecretsForPod(pod *api.Pod) []api.Secret {
+	pullSecrets := []api.Secret{}
+	if kl.kubeClient == nil {
+		return pullSecrets
+	}
+	for _, secretRef := range pod.Spec.ImagePullSecrets {
+		secret, err := kl.kubeClient.Secrets(pod.Namespace).Get(secretRef.Name)
+		if err != nil {
+			glog.Warningf("Unable to retrieve pull secret %s/%s for pod %q: %v. The image pull may not succeed.",
+				pod.Namespace, secretRef.Name, kubecontainer.GetPodFullName(pod), err)
+			continue
+		}
+		pullSecrets = append(pullSecrets, *secret)
+	}
+	return pullSecrets
+}
+
+// lazyPullSecretsForPod returns a getter that fetches the pull secrets of the
+// pod on first use, so that they are only retrieved when an image is pulled.
+func (kl *Kubelet) lazyPullSecretsForPod(pod *api.Pod) kubecontainer.PullSecretsGetter {
+	var pullSecrets []api.Secret
+	return func() []api.Secret {
+		if pullSecrets == nil {
+			pullSecrets = kl.getPullSecretsForPod(pod)
+		}
+		return pullSecrets
+	}
+}
+
+// getClusterDNS returns a list of the DNS servers and a list of the DNS search
+// domains of the cluster.
+func (kl *Kubelet) getClusterDNS(pod *api.Pod) ([]string, []string, error) {
 	// Get host DNS settings and append them to cluster DNS settings.
 	f, err := os.Open("/etc/resolv.conf")
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 	defer f.Close()
 
 	hostDNS, hostSearch, err := parseResolvConf(f)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
+	var dns, dnsSearch []string
 	if kl.clusterDNS != nil {
-		hc.DNS = append([]string{kl.clusterDNS.String()}, hostDNS...)
+		dns = append([]string{kl.clusterDNS.String()}, hostDNS...)
 	}
 	if kl.clusterDomain != "" {
 		nsDomain := fmt.Sprintf("%s.%s", pod.Namespace, kl.clusterDomain)
-		hc.DNSSearch = append([]string{nsDomain, kl.clusterDomain}, hostSearch...)
+		dnsSearch = append([]string{nsDomain, kl.clusterDomain}, hostSearch...)
 	}
-	return nil
+	return dns, dnsSearch, nil
 }
 
 // Returns the list of DNS servers and DNS search domains.
@@ -983,26 +1030,6 @@ func parseResolvConf(reader io.Reader) (nameservers []string, searches []string,
 	return nameservers, searches, nil
 }
 
-// Kill a docker container
-func (kl *Kubelet) killContainer(c *kubecontainer.Container) error {
-	return kl.killContainerByID(string(c.ID))
-}
-
-func (kl *Kubelet) killContainerByID(ID string) error {
-	glog.V(2).Infof("Killing container with id %q", ID)
-	kl.readiness.remove(ID)
-	err := kl.dockerClient.StopContainer(ID, 10)
-
-	ref, ok := kl.getRef(ID)
-	if !ok {
-		glog.Warningf("No ref for pod '%v'", ID)
-	} else {
-		// TODO: pass reason down here, and state, or move this call up the stack.
-		kl.recorder.Eventf(ref, "killing", "Killing %v", ID)
-	}
-	return err
-}
-
 const (
 	PodInfraContainerImage = "kubernetes/pause:latest"
 )
@@ -1021,123 +1048,6 @@ func allowHostNetwork(pod *api.Pod) (bool, error) {
 	return false, nil
 }
 
-// createPodInfraContainer starts the pod infra container for a pod. Returns the docker container ID of the newly created container.
-func (kl *Kubelet) createPodInfraContainer(pod *api.Pod) (dockertools.DockerID, error) {
-
-	// Use host networking if specified and allowed.
-	netNamespace := ""
-	var ports []api.ContainerPort
-
-	if pod.Spec.HostNetwork {
-		netNamespace = "host"
-	} else {
-		// Docker only exports ports from the pod infra container.  Let's
-		// collect all of the relevant ports and export them.
-		for _, container := range pod.Spec.Containers {
-			ports = append(ports, container.Ports...)
-		}
-	}
-
-	container := &api.Container{
-		Name:  dockertools.PodInfraContainerName,
-		Image: kl.podInfraContainerImage,
-		Ports: ports,
-	}
-	ref, err := containerRef(pod, container)
-	if err != nil {
-		glog.Errorf("Couldn't make a ref to pod %v, container %v: '%v'", pod.Name, container.Name, err)
-	}
-	// TODO: make this a TTL based pull (if image older than X policy, pull)
-	ok, err := kl.dockerPuller.IsImagePresent(container.Image)
-	if err != nil {
-		if ref != nil {
-			kl.recorder.Eventf(ref, "failed", "Failed to inspect image %q: %v", container.Image, err)
-		}
-		return "", err
-	}
-	if !ok {
-		if err := kl.pullImage(container.Image, ref); err != nil {
-			return "", err
-		}
-	}
-	if ref != nil {
-		kl.recorder.Eventf(ref, "pulled", "Successfully pulled image %q", container.Image)
-	}
-
-	id, err := kl.runContainer(pod, container, nil, netNamespace, "")
-	if err != nil {
-		return "", err
-	}
-
-	// Set OOM score of POD container to lower than those of the other
-	// containers in the pod. This ensures that it is killed only as a last
-	// resort.
-	containerInfo, err := kl.dockerClient.InspectContainer(string(id))
-	if err != nil {
-		return "", err
-	}
-
-	// Ensure the PID actually exists, else we'll move ourselves.
-	if containerInfo.State.Pid == 0 {
-		return "", fmt.Errorf("failed to get init PID for Docker pod infra container %q", string(id))
-	}
-	return id, util.ApplyOomScoreAdj(containerInfo.State.Pid, podOomScoreAdj)
-}
-
-func (kl *Kubelet) pullImage(img string, ref *api.ObjectReference) error {
-	start := time.Now()
-	defer func() {
-		metrics.ImagePullLatency.Observe(metrics.SinceInMicroseconds(start))
-	}()
-
-	if err := kl.dockerPuller.Pull(img); err != nil {
-		if ref != nil {
-			kl.recorder.Eventf(ref, "failed", "Failed to pull image %q: %v", img, err)
-		}
-		return err
-	}
-	if ref != nil {
-		kl.recorder.Eventf(ref, "pulled", "Successfully pulled image %q", img)
-	}
-	return nil
-}
-
-// Kill all containers in a pod.  Returns the number of containers deleted and an error if one occurs.
-func (kl *Kubelet) killContainersInPod(pod *api.Pod, runningPod kubecontainer.Pod) (int, error) {
-	podFullName := kubecontainer.GetPodFullName(pod)
-
-	count := 0
-	errs := make(chan error, len(pod.Spec.Containers))
-	wg := sync.WaitGroup{}
-	for _, container := range pod.Spec.Containers {
-		// TODO: Consider being more aggressive: kill all containers with this pod UID, period.
-		c := runningPod.FindContainerByName(container.Name)
-		if c != nil {
-			count++
-			wg.Add(1)
-			go func() {
-				defer util.HandleCrash()
-				err := kl.killContainer(c)
-				if err != nil {
-					glog.Errorf("Failed to delete container: %v; Skipping pod %q", err, podFullName)
-					errs <- err
-				}
-				wg.Done()
-			}()
-		}
-	}
-	wg.Wait()
-	close(errs)
-	if len(errs) > 0 {
-		errList := []error{}
-		for err := range errs {
-			errList = append(errList, err)
-		}
-		return -1, fmt.Errorf("failed to delete containers (%v)", errList)
-	}
-	return count, nil
-}
-
 type empty struct{}
 
 // makePodDataDirs creates the dirs for the pod datas.
@@ -1155,210 +1065,6 @@ func (kl *Kubelet) makePodDataDirs(pod *api.Pod) error {
 	return nil
 }
 
-func (kl *Kubelet) shouldContainerBeRestarted(container *api.Container, pod *api.Pod) bool {
-	podFullName := kubecontainer.GetPodFullName(pod)
-	// Check RestartPolicy for dead container
-	recentContainers, err := dockertools.GetRecentDockerContainersWithNameAndUUID(kl.dockerClient, podFullName, pod.UID, container.Name)
-	if err != nil {
-		glog.Errorf("Error listing recent containers for pod %q: %v", podFullName, err)
-		// TODO(dawnchen): error handling here?
-	}
-	// set dead containers to unready state
-	for _, c := range recentContainers {
-		kl.readiness.remove(c.ID)
-	}
-
-	if len(recentContainers) > 0 {
-		if pod.Spec.RestartPolicy == api.RestartPolicyNever {
-			glog.Infof("Already ran container %q of pod %q, do nothing", container.Name, podFullName)
-			return false
-
-		}
-		if pod.Spec.RestartPolicy == api.RestartPolicyOnFailure {
-			// Check the exit code of last run
-			if recentContainers[0].State.ExitCode == 0 {
-				glog.Infof("Already successfully ran container %q of pod %q, do nothing", container.Name, podFullName)
-				return false
-			}
-		}
-	}
-	return true
-}
-
-// Finds an infra container for a pod given by podFullName and UID in dockerContainers. If there is an infra container
-// return its ID and true, otherwise it returns empty ID and false.
-func (kl *Kubelet) getPodInfraContainer(podFullName string, uid types.UID,
-	dockerContainers dockertools.DockerContainers) (dockertools.DockerID, bool) {
-	if podInfraDockerContainer, found, _ := dockerContainers.FindPodContainer(podFullName, uid, dockertools.PodInfraContainerName); found {
-		podInfraContainerID := dockertools.DockerID(podInfraDockerContainer.ID)
-		return podInfraContainerID, true
-	}
-	return "", false
-}
-
-// Attempts to start a container pulling the image before that if necessary. It returns DockerID of a started container
-// if it was successful, and a non-nil error otherwise.
-func (kl *Kubelet) pullImageAndRunContainer(pod *api.Pod, container *api.Container, podVolumes *volumeMap,
-	podInfraContainerID dockertools.DockerID) (dockertools.DockerID, error) {
-	podFullName := kubecontainer.GetPodFullName(pod)
-	ref, err := containerRef(pod, container)
-	if err != nil {
-		glog.Errorf("Couldn't make a ref to pod %v, container %v: '%v'", pod.Name, container.Name, err)
-	}
-	if container.ImagePullPolicy != api.PullNever {
-		present, err := kl.dockerPuller.IsImagePresent(container.Image)
-		if err != nil {
-			if ref != nil {
-				kl.recorder.Eventf(ref, "failed", "Failed to inspect image %q: %v", container.Image, err)
-			}
-			glog.Errorf("Failed to inspect image %q: %v; skipping pod %q container %q", container.Image, err, podFullName, container.Name)
-			return "", err
-		}
-		if container.ImagePullPolicy == api.PullAlways ||
-			(container.ImagePullPolicy == api.PullIfNotPresent && (!present)) {
-			if err := kl.pullImage(container.Image, ref); err != nil {
-				return "", err
-			}
-		}
-	}
-	// TODO(dawnchen): Check RestartPolicy.DelaySeconds before restart a container
-	namespaceMode := fmt.Sprintf("container:%v", podInfraContainerID)
-	containerID, err := kl.runContainer(pod, container, *podVolumes, namespaceMode, namespaceMode)
-	if err != nil {
-		// TODO(bburns) : Perhaps blacklist a container after N failures?
-		glog.Errorf("Error running pod %q container %q: %v", podFullName, container.Name, err)
-		return "", err
-	}
-	return containerID, nil
-}
-
-// Structure keeping information on changes that need to happen for a pod. The semantics is as follows:
-// - startInfraContainer is true if new Infra Containers have to be started and old one (if running) killed.
-//   Additionally if it is true then containersToKeep have to be empty
-// - infraContainerId have to be set iff startInfraContainer is false. It stores dockerID of running Infra Container
-// - containersToStart keeps indices of Specs of containers that have to be started.
-// - containersToKeep stores mapping from dockerIDs of running containers to indices of their Specs for containers that
-//   should be kept running. If startInfraContainer is false then it contains an entry for infraContainerId (mapped to -1).
-//   It shouldn't be the case where containersToStart is empty and containersToKeep contains only infraContainerId. In such case
-//   Infra Container should be killed, hence it's removed from this map.
-// - all running containers which are NOT contained in containersToKeep should be killed.
-type podContainerChangesSpec struct {
-	startInfraContainer bool
-	infraContainerId    dockertools.DockerID
-	containersToStart   map[int]empty
-	containersToKeep    map[dockertools.DockerID]int
-}
-
-func (kl *Kubelet) computePodContainerChanges(pod *api.Pod, runningPod kubecontainer.Pod) (podContainerChangesSpec, error) {
-	podFullName := kubecontainer.GetPodFullName(pod)
-	uid := pod.UID
-	glog.V(4).Infof("Syncing Pod %+v, podFullName: %q, uid: %q", pod, podFullName, uid)
-
-	err := kl.makePodDataDirs(pod)
-	if err != nil {
-		return podContainerChangesSpec{}, err
-	}
-
-	containersToStart := make(map[int]empty)
-	containersToKeep := make(map[dockertools.DockerID]int)
-	createPodInfraContainer := false
-	var podStatus api.PodStatus
-
-	var podInfraContainerID dockertools.DockerID
-	podInfraContainer := runningPod.FindContainerByName(dockertools.PodInfraContainerName)
-	if podInfraContainer != nil {
-		glog.V(4).Infof("Found infra pod for %q", podFullName)
-		podInfraContainerID = dockertools.DockerID(podInfraContainer.ID)
-		containersToKeep[podInfraContainerID] = -1
-		podStatus, err = kl.GetPodStatus(podFullName)
-		if err != nil {
-			glog.Errorf("Unable to get pod with name %q and uid %q info with error(%v)", podFullName, uid, err)
-		}
-	} else {
-		glog.V(2).Infof("No Infra Container for %q found. All containers will be restarted.", podFullName)
-		createPodInfraContainer = true
-	}
-
-	for index, container := range pod.Spec.Containers {
-		expectedHash := dockertools.HashContainer(&container)
-
-		c := runningPod.FindContainerByName(container.Name)
-		if c != nil {
-			containerID := dockertools.DockerID(c.ID)
-			hash := c.Hash
-			glog.V(3).Infof("pod %q container %q exists as %v", podFullName, container.Name, containerID)
-
-			if !createPodInfraContainer {
-				// look for changes in the container.
-
-				containerChanged := hash != 0 && hash != expectedHash
-				if !containerChanged {
-					result, err := kl.probeContainer(pod, podStatus, container, string(c.ID), c.Created)
-					if err != nil {
-						// TODO(vmarmol): examine this logic.
-						glog.V(2).Infof("probe no-error: %q", container.Name)
-						containersToKeep[containerID] = index
-						continue
-					}
-					if result == probe.Success {
-						glog.V(4).Infof("probe success: %q", container.Name)
-						containersToKeep[containerID] = index
-						continue
-					}
-					glog.Infof("pod %q container %q is unhealthy (probe result: %v). Container will be killed and re-created.", podFullName, container.Name, result)
-					containersToStart[index] = empty{}
-				} else {
-					glog.Infof("pod %q container %q hash changed (%d vs %d). Pod will be killed and re-created.", podFullName, container.Name, hash, expectedHash)
-					createPodInfraContainer = true
-					delete(containersToKeep, podInfraContainerID)
-					// If we are to restart Infra Container then we move containersToKeep into containersToStart
-					// if RestartPolicy allows restarting failed containers.
-					if pod.Spec.RestartPolicy != api.RestartPolicyNever {
-						for _, v := range containersToKeep {
-							containersToStart[v] = empty{}
-						}
-					}
-					containersToStart[index] = empty{}
-					containersToKeep = make(map[dockertools.DockerID]int)
-				}
-			} else { // createPodInfraContainer == true and Container exists
-				// If we're creating infra containere everything will be killed anyway
-				// If RestartPolicy is Always or OnFailure we restart containers that were running before we
-				// killed them when restarting Infra Container.
-				if pod.Spec.RestartPolicy != api.RestartPolicyNever {
-					glog.V(1).Infof("Infra Container is being recreated. %q will be restarted.", container.Name)
-					containersToStart[index] = empty{}
-				}
-				continue
-			}
-		} else {
-			if kl.shouldContainerBeRestarted(&container, pod) {
-				// If we are here it means that the container is dead and sould be restarted, or never existed and should
-				// be created. We may be inserting this ID again if the container has changed and it has
-				// RestartPolicy::Always, but it's not a big deal.
-				glog.V(3).Infof("Container %+v is dead, but RestartPolicy says that we should restart it.", container)
-				containersToStart[index] = empty{}
-			}
-		}
-	}
-
-	// After the loop one of the following should be true:
-	// - createPodInfraContainer is true and containersToKeep is empty
-	// - createPodInfraContainer is false and containersToKeep contains at least ID of Infra Container
-
-	// If Infra container is the last running one, we don't want to keep it.
-	if !createPodInfraContainer && len(containersToStart) == 0 && len(containersToKeep) == 1 {
-		containersToKeep = make(map[dockertools.DockerID]int)
-	}
-
-	return podContainerChangesSpec{
-		startInfraContainer: createPodInfraContainer,
-		infraContainerId:    podInfraContainerID,
-		containersToStart:   containersToStart,
-		containersToKeep:    containersToKeep,
-	}, nil
-}
-
 func (kl *Kubelet) canRunPod(pod *api.Pod) error {
 	if pod.Spec.HostNetwork {
 		allowed, err := allowHostNetwork(pod)
@@ -1380,6 +1086,21 @@ func (kl *Kubelet) syncPod(pod *api.Pod, mirrorPod *api.Pod, runningPod kubecont
 		return err
 	}
 
+	// A static pod cannot be deleted through the apiserver, so a mirror pod marked for
+	// deletion is removed right away and recreated below. So is a mirror pod of an older
+	// version of the static pod, since a pod spec cannot be updated.
+	if mirrorPod != nil && (mirrorPod.DeletionTimestamp != nil || !isMirrorPodOf(mirrorPod, pod)) {
+		if mirrorPod.DeletionTimestamp != nil {
+			glog.V(3).Infof("Deleting mirror pod %q because it is marked for deletion", podFullName)
+		} else {
+			glog.V(3).Infof("Deleting mirror pod %q because it is outdated", podFullName)
+		}
+		if err := kl.podManager.DeleteMirrorPod(podFullName); err != nil {
+			glog.Errorf("Failed deleting mirror pod %q: %v", podFullName, err)
+		}
+		mirrorPod = nil
+	}
+
 	// Before returning, regenerate status and store it in the cache.
 	defer func() {
 		if isStaticPod(pod) && mirrorPod == nil {
@@ -1399,84 +1120,45 @@ func (kl *Kubelet) syncPod(pod *api.Pod, mirrorPod *api.Pod, runningPod kubecont
 		}
 	}()
 
-	containerChanges, err := kl.computePodContainerChanges(pod, runningPod)
-	glog.V(3).Infof("Got container changes for pod %q: %+v", podFullName, containerChanges)
-	if err != nil {
-		return err
+	// The pod was gracefully deleted in the apiserver. Stop its containers within the
+	// grace period; the status manager confirms the deletion once they have stopped.
+	if pod.DeletionTimestamp != nil {
+		glog.V(3).Infof("Pod %q is terminating, killing its containers", podFullName)
+		return kl.containerRuntime.KillPod(pod, runningPod)
 	}
 
-	if containerChanges.startInfraContainer || (len(containerChanges.containersToKeep) == 0 && len(containerChanges.containersToStart) == 0) {
-		if len(containerChanges.containersToKeep) == 0 && len(containerChanges.containersToStart) == 0 {
-			glog.V(4).Infof("Killing Infra Container for %q becase all other containers are dead.", podFullName)
-		} else {
-			glog.V(4).Infof("Killing Infra Container for %q, will start new one", podFullName)
-		}
-
-		// Killing phase: if we want to start new infra container, or nothing is running kill everything (including infra container)
-		// TODO(yifan): Replace with KillPod().
-		podInfraContainer := runningPod.FindContainerByName(dockertools.PodInfraContainerName)
-		if podInfraContainer != nil {
-			if err := kl.killContainer(podInfraContainer); err != nil {
-				glog.Warningf("Failed to kill pod infra container %q: %v", podInfraContainer.ID, err)
-			}
-		}
-		_, err = kl.killContainersInPod(pod, runningPod)
-		if err != nil {
-			return err
-		}
-	} else {
-		// Otherwise kill any containers in this pod which are not specified as ones to keep.
-		for _, container := range runningPod.Containers {
-			_, keep := containerChanges.containersToKeep[dockertools.DockerID(container.ID)]
-			if !keep {
-				glog.V(3).Infof("Killing unwanted container %+v", container)
-				err = kl.killContainer(container)
-				if err != nil {
-					glog.Errorf("Error killing container: %v", err)
-				}
-			}
-		}
+	// Create the data directories for the pod.
+	if err := kl.makePodDataDirs(pod); err != nil {
+		glog.Errorf("Unable to make pod data directories for pod %q (uid %q): %v", podFullName, uid, err)
+		return err
 	}
 
-	// Starting phase: if we should create infra container then we do it first
-	var ref *api.ObjectReference
-	var podVolumes volumeMap
-	podInfraContainerID := containerChanges.infraContainerId
-	if containerChanges.startInfraContainer && (len(containerChanges.containersToStart) > 0) {
-		ref, err = api.GetReference(pod)
-		if err != nil {
-			glog.Errorf("Couldn't make a ref to pod %q: '%v'", podFullName, err)
+	// Mount volumes.
+	podVolumes, err := kl.mountExternalVolumes(pod)
+	if err != nil {
+		if ref, errGetRef := api.GetReference(pod); errGetRef == nil && ref != nil {
+			kl.recorder.Eventf(ref, "failedMount", "Unable to mount volumes for pod %q: %v", podFullName, err)
 		}
-		glog.Infof("Creating pod infra container for %q", podFullName)
-		podInfraContainerID, err = kl.createPodInfraContainer(pod)
+		glog.Errorf("Unable to mount volumes for pod %q: %v; skipping pod", podFullName, err)
+		return err
+	}
+	kl.volumeManager.SetVolumes(pod.UID, podVolumes)
 
-		// Call the networking plugin
-		if err == nil {
-			err = kl.networkPlugin.SetUpPod(pod.Namespace, pod.Name, podInfraContainerID)
-		}
+	// The pod may not be known to the pod manager (e.g. in runonce mode), so
+	// fall back to generating the status from the pod itself.
+	podStatus, found := kl.statusManager.GetPodStatus(podFullName)
+	if !found {
+		podStatus, err = kl.generatePodStatusByPod(pod)
 		if err != nil {
-			glog.Errorf("Failed to create pod infra container: %v; Skipping pod %q", err, podFullName)
-			return err
+			glog.Errorf("Unable to get status for pod %q (uid %q): %v", podFullName, uid, err)
 		}
 	}
 
-	// Mount volumes
-	podVolumes, err = kl.mountExternalVolumes(pod)
+	err = kl.containerRuntime.SyncPod(pod, runningPod, podStatus, kl.lazyPullSecretsForPod(pod))
 	if err != nil {
-		if ref != nil {
-			kl.recorder.Eventf(ref, "failedMount",
-				"Unable to mount volumes for pod %q: %v", podFullName, err)
-		}
-		glog.Errorf("Unable to mount volumes for pod %q: %v; skipping pod", podFullName, err)
 		return err
 	}
 
-	// Start everything
-	for container := range containerChanges.containersToStart {
-		glog.V(4).Infof("Creating container %+v", pod.Spec.Containers[container])
-		kl.pullImageAndRunContainer(pod, &pod.Spec.Containers[container], &podVolumes, podInfraContainerID)
-	}
-
 	if mirrorPod == nil && isStaticPod(pod) {
 		glog.V(4).Infof("Creating a mirror pod %q", podFullName)
 		// To make sure we will properly update static pod status we need to delete
@@ -1518,6 +1200,7 @@ func (kl *Kubelet) cleanupOrphanedPods(pods []api.Pod) error {
 	for i := range found {
 		if !desired.Has(string(found[i])) {
 			glog.V(3).Infof("Orphaned pod %q found, removing", found[i])
+			kl.volumeManager.DeleteVolumes(found[i])
 			if err := os.RemoveAll(kl.getPodDir(found[i])); err != nil {
 				errlist = append(errlist, err)
 			}
@@ -1528,19 +1211,12 @@ func (kl *Kubelet) cleanupOrphanedPods(pods []api.Pod) error {
 
 // Compares the map of current volumes to the map of desired volumes.
 // If an active volume does not have a respective desired volume, clean it up.
-func (kl *Kubelet) cleanupOrphanedVolumes(pods []api.Pod, running []*docker.Container) error {
+func (kl *Kubelet) cleanupOrphanedVolumes(pods []api.Pod, runningPods []*kubecontainer.Pod) error {
 	desiredVolumes := getDesiredVolumes(pods)
 	currentVolumes := kl.getPodVolumesFromDisk()
 	runningSet := util.StringSet{}
-	for ix := range running {
-		if len(running[ix].Name) == 0 {
-			glog.V(2).Infof("Found running container ix=%d with info: %+v", ix, running[ix])
-		}
-		containerName, _, err := dockertools.ParseDockerName(running[ix].Name)
-		if err != nil {
-			continue
-		}
-		runningSet.Insert(string(containerName.PodUID))
+	for _, pod := range runningPods {
+		runningSet.Insert(string(pod.ID))
 	}
 	for name, vol := range currentVolumes {
 		if _, ok := desiredVolumes[name]; !ok {
@@ -1591,7 +1267,7 @@ func (kl *Kubelet) SyncPods(allPods []api.Pod, podSyncTypes map[types.UID]metric
 	var err error
 	desiredPods := make(map[types.UID]empty)
 
-	runningPods, err := kl.dockerCache.GetPods()
+	runningPods, err := kl.runtimeCache.GetPods()
 	if err != nil {
 		glog.Errorf("Error listing containers: %#v", err)
 		return err
@@ -1620,6 +1296,7 @@ func (kl *Kubelet) SyncPods(allPods []api.Pod, podSyncTypes map[types.UID]metric
 	}
 	// Stop the workers for no-longer existing pods.
 	kl.podWorkers.ForgetNonExistingPodWorkers(desiredPods)
+	kl.probeWorkers.ForgetNonExistingPods(desiredPods)
 
 	if !kl.sourcesReady() {
 		// If the sources aren't ready, skip deletion, as we may accidentally delete pods
@@ -1629,40 +1306,37 @@ func (kl *Kubelet) SyncPods(allPods []api.Pod, podSyncTypes map[types.UID]metric
 	}
 
 	// Kill any containers we don't need.
-	killed := []string{}
+	killed := false
 	for _, pod := range runningPods {
 		if _, found := desiredPods[pod.ID]; found {
 			// syncPod() will handle this one.
 			continue
 		}
+		if evicted := kl.getEvictedPod(); evicted != nil && evicted.UID == pod.ID {
+			// evictPods() kills it with its grace period.
+			continue
+		}
 
 		// Kill all the containers in the unidentified pod.
-		for _, c := range pod.Containers {
-			// call the networking plugin for teardown
-			if c.Name == dockertools.PodInfraContainerName {
-				err := kl.networkPlugin.TearDownPod(pod.Namespace, pod.Name, dockertools.DockerID(c.ID))
-				if err != nil {
-					glog.Errorf("Network plugin pre-delete method returned an error: %v", err)
-				}
-			}
-			glog.V(1).Infof("Killing unwanted container %+v", c)
-			err = kl.killContainer(c)
-			if err != nil {
-				glog.Errorf("Error killing container %+v: %v", c, err)
-			} else {
-				killed = append(killed, string(c.ID))
-			}
+		glog.V(1).Infof("Killing unwanted pod %q", pod.Name)
+		if err := kl.containerRuntime.KillPod(nil, *pod); err != nil {
+			glog.Errorf("Error killing pod %q: %v", pod.Name, err)
 		}
+		killed = true
 	}
 
-	running, err := dockertools.GetRunningContainers(kl.dockerClient, killed)
-	if err != nil {
-		glog.Errorf("Failed to poll container state: %v", err)
-		return err
+	// The runtime cache may not reflect the pods we just killed yet, so
+	// bypass it to find out which of them are still running.
+	if killed {
+		runningPods, err = kl.containerRuntime.GetPods(false)
+		if err != nil {
+			glog.Errorf("Failed to poll container state: %v", err)
+			return err
+		}
 	}
 
 	// Remove any orphaned volumes.
-	err = kl.cleanupOrphanedVolumes(pods, running)
+	err = kl.cleanupOrphanedVolumes(pods, runningPods)
 	if err != nil {
 		return err
 	}
@@ -1807,13 +1481,14 @@ func (kl *Kubelet) syncLoop(updates <-chan PodUpdate, handler SyncHandler) {
 	}
 }
 
-// Returns Docker version for this Kubelet.
+// Returns Docker version for this Kubelet. Kubelets which do not use Docker
+// as their container runtime report no version.
 func (kl *Kubelet) GetDockerVersion() ([]uint, error) {
-	if kl.dockerClient == nil {
-		return nil, fmt.Errorf("no Docker client")
+	dm, ok := kl.containerRuntime.(*dockertools.DockerManager)
+	if !ok {
+		return nil, nil
 	}
-	dockerRunner := dockertools.NewDockerContainerCommandRunner(kl.dockerClient)
-	return dockerRunner.GetDockerServerVersion()
+	return dm.GetDockerServerVersion()
 }
 
 func (kl *Kubelet) validatePodPhase(podStatus *api.PodStatus) error {
@@ -1824,25 +1499,31 @@ func (kl *Kubelet) validatePodPhase(podStatus *api.PodStatus) error {
 	return fmt.Errorf("pod is not in 'Running', 'Succeeded' or 'Failed' state - State: %q", podStatus.Phase)
 }
 
-func (kl *Kubelet) validateContainerStatus(podStatus *api.PodStatus, containerName string) (dockerID string, err error) {
+func (kl *Kubelet) validateContainerStatus(podStatus *api.PodStatus, containerName string, previous bool) (containerID string, err error) {
 	for cName, cStatus := range podStatus.Info {
 		if containerName == cName {
+			if previous {
+				lastState := cStatus.LastTerminationState.Termination
+				if lastState == nil || lastState.ContainerID == "" {
+					return "", fmt.Errorf("previous terminated container %q not found in pod", containerName)
+				}
+				return kubecontainer.TrimRuntimePrefix(lastState.ContainerID), nil
+			}
 			if cStatus.State.Waiting != nil {
 				return "", fmt.Errorf("container %q is in waiting state.", containerName)
 			}
-			return strings.Replace(podStatus.Info[containerName].ContainerID, dockertools.DockerPrefix, "", 1), nil
+			return kubecontainer.TrimRuntimePrefix(podStatus.Info[containerName].ContainerID), nil
 		}
 	}
 	return "", fmt.Errorf("container %q not found in pod", containerName)
 }
 
-// GetKubeletContainerLogs returns logs from the container
-// TODO: this method is returning logs of random container attempts, when it should be returning the most recent attempt
-// or all of them.
-func (kl *Kubelet) GetKubeletContainerLogs(podFullName, containerName, tail string, follow bool, stdout, stderr io.Writer) error {
+// GetKubeletContainerLogs returns logs from the container, or from its
+// previous terminated instance if opts.Previous is set.
+func (kl *Kubelet) GetKubeletContainerLogs(podFullName, containerName string, opts *api.PodLogOptions, stdout, stderr io.Writer) error {
 	podStatus, err := kl.GetPodStatus(podFullName)
 	if err != nil {
-		if err == dockertools.ErrNoContainersInPod {
+		if err == kubecontainer.ErrNoContainersInPod {
 			return fmt.Errorf("pod %q not found\n", podFullName)
 		} else {
 			return fmt.Errorf("failed to get status for pod %q - %v", podFullName, err)
@@ -1852,11 +1533,11 @@ func (kl *Kubelet) GetKubeletContainerLogs(podFullName, containerName, tail stri
 	if err := kl.validatePodPhase(&podStatus); err != nil {
 		return err
 	}
-	dockerContainerID, err := kl.validateContainerStatus(&podStatus, containerName)
+	containerID, err := kl.validateContainerStatus(&podStatus, containerName, opts.Previous)
 	if err != nil {
 		return err
 	}
-	return dockertools.GetKubeletDockerContainerLogs(kl.dockerClient, dockerContainerID, tail, follow, stdout, stderr)
+	return kl.containerRuntime.GetContainerLogs(containerID, opts, stdout, stderr)
 }
 
 // GetHostname Returns the hostname as the kubelet sees it.
@@ -1916,6 +1597,66 @@ func (kl *Kubelet) updateNodeStatus() error {
 	return fmt.Errorf("Update node status exceeds retry count")
 }
 
+// setNodeZoneLabels labels node with the zone and region reported by the cloud
+// provider, if any, so that the node controller can tell zone outages apart
+// from failures of individual nodes.
+func (kl *Kubelet) setNodeZoneLabels(node *api.Node) {
+	if kl.cloud == nil {
+		return
+	}
+	zones, ok := kl.cloud.Zones()
+	if !ok {
+		return
+	}
+	zone, err := zones.GetZone()
+	if err != nil {
+		glog.Errorf("Error getting zone for node %q: %v", node.Name, err)
+		return
+	}
+	if node.Labels == nil {
+		node.Labels = map[string]string{}
+	}
+	if _, found := node.Labels[cloudprovider.LabelZoneFailureDomain]; !found && zone.FailureDomain != "" {
+		node.Labels[cloudprovider.LabelZoneFailureDomain] = zone.FailureDomain
+	}
+	if _, found := node.Labels[cloudprovider.LabelZoneRegion]; !found && zone.Region != "" {
+		node.Labels[cloudprovider.LabelZoneRegion] = zone.Region
+	}
+}
+
+// setNodePressureConditions reports the memory and disk pressure seen by the
+// last eviction observation in the node conditions.
+func (kl *Kubelet) setNodePressureConditions(node *api.Node, currentTime util.Time) {
+	pressure := kl.evictionManager.LastPressure()
+	for _, conditionType := range []api.NodeConditionType{api.NodeMemoryPressure, api.NodeDiskPressure} {
+		newCondition := api.NodeCondition{
+			Type:               conditionType,
+			Status:             api.ConditionFalse,
+			Reason:             "kubelet has enough resources available",
+			LastProbeTime:      currentTime,
+			LastTransitionTime: currentTime,
+		}
+		if message, found := pressure[conditionType]; found {
+			newCondition.Status = api.ConditionTrue
+			newCondition.Reason = "kubelet is evicting pods"
+			newCondition.Message = message
+		}
+		updated := false
+		for i := range node.Status.Conditions {
+			if node.Status.Conditions[i].Type == conditionType {
+				if node.Status.Conditions[i].Status == newCondition.Status {
+					newCondition.LastTransitionTime = node.Status.Conditions[i].LastTransitionTime
+				}
+				node.Status.Conditions[i] = newCondition
+				updated = true
+			}
+		}
+		if !updated {
+			node.Status.Conditions = append(node.Status.Conditions, newCondition)
+		}
+	}
+}
+
 // tryUpdateNodeStatus tries to update node status to master.
 func (kl *Kubelet) tryUpdateNodeStatus() error {
 	node, err := kl.kubeClient.Nodes().Get(kl.hostname)
@@ -1937,6 +1678,7 @@ func (kl *Kubelet) tryUpdateNodeStatus() error {
 		node.Status.NodeInfo.BootID = info.BootID
 		node.Spec.Capacity = CapacityFromMachineInfo(info)
 	}
+	kl.setNodeZoneLabels(node)
 
 	currentTime := util.Now()
 	newCondition := api.NodeCondition{
@@ -1957,6 +1699,9 @@ func (kl *Kubelet) tryUpdateNodeStatus() error {
 		newCondition.LastTransitionTime = currentTime
 		node.Status.Conditions = append(node.Status.Conditions, newCondition)
 	}
+	if kl.evictionManager != nil {
+		kl.setNodePressureConditions(node, currentTime)
+	}
 
 	_, err = kl.kubeClient.Nodes().Update(node)
 	return err
@@ -2049,7 +1794,7 @@ func getPodReadyCondition(spec *api.PodSpec, info api.PodInfo) []api.PodConditio
 	return ready
 }
 
-// GetPodStatus returns information from Docker about the containers in a pod
+// GetPodStatus returns information from the container runtime about the containers in a pod
 func (kl *Kubelet) GetPodStatus(podFullName string) (api.PodStatus, error) {
 	// Check to see if we have a cached version of the status.
 	cachedPodStatus, found := kl.statusManager.GetPodStatus(podFullName)
@@ -2075,18 +1820,18 @@ func (kl *Kubelet) generatePodStatusByPod(pod *api.Pod) (api.PodStatus, error) {
 	glog.V(3).Infof("Generating status for %q", podFullName)
 
 	spec := &pod.Spec
-	podStatus, err := dockertools.GetDockerPodStatus(kl.dockerClient, *spec, podFullName, pod.UID)
+	podStatus, err := kl.containerRuntime.GetPodStatus(pod)
 
 	if err != nil {
 		// Error handling
-		glog.Infof("Query docker container info for pod %q failed with error (%v)", podFullName, err)
+		glog.Infof("Query container info for pod %q failed with error (%v)", podFullName, err)
 		if strings.Contains(err.Error(), "resource temporarily unavailable") {
 			// Leave upstream layer to decide what to do
 			return api.PodStatus{}, err
 		} else {
 			pendingStatus := api.PodStatus{
 				Phase:   api.PodPending,
-				Message: fmt.Sprintf("Query docker container info failed with error (%v)", err),
+				Message: fmt.Sprintf("Query container info failed with error (%v)", err),
 			}
 			return pendingStatus, nil
 		}
@@ -2096,7 +1841,8 @@ func (kl *Kubelet) generatePodStatusByPod(pod *api.Pod) (api.PodStatus, error) {
 	podStatus.Phase = getPhase(spec, podStatus.Info)
 	for _, c := range spec.Containers {
 		containerStatus := podStatus.Info[c.Name]
-		containerStatus.Ready = kl.readiness.IsReady(containerStatus)
+		// A pod being gracefully deleted is taken out of service while it shuts down.
+		containerStatus.Ready = pod.DeletionTimestamp == nil && kl.isContainerReady(containerStatus)
 		podStatus.Info[c.Name] = containerStatus
 	}
 	podStatus.Conditions = append(podStatus.Conditions, getPodReadyCondition(spec, podStatus.Info)...)
@@ -2110,12 +1856,32 @@ func (kl *Kubelet) generatePodStatusByPod(pod *api.Pod) (api.PodStatus, error) {
 	return *podStatus, nil
 }
 
+// isContainerReady returns whether the container is running and has passed its
+// readiness probe.
+func (kl *Kubelet) isContainerReady(c api.ContainerStatus) bool {
+	if c.State.Running == nil {
+		return false
+	}
+	return kl.readinessManager.GetReadiness(kubecontainer.TrimRuntimePrefix(c.ContainerID))
+}
+
 // Returns logs of current machine.
 func (kl *Kubelet) ServeLogs(w http.ResponseWriter, req *http.Request) {
 	// TODO: whitelist logs we are willing to serve
 	kl.logServer.ServeHTTP(w, req)
 }
 
+// findContainer returns the running container with the given name in the pod,
+// or nil if there is no such container.
+func (kl *Kubelet) findContainer(podFullName string, podUID types.UID, containerName string) (*kubecontainer.Container, error) {
+	pods, err := kl.containerRuntime.GetPods(false)
+	if err != nil {
+		return nil, err
+	}
+	pod := kubecontainer.Pods(pods).FindPod(podFullName, podUID)
+	return pod.FindContainerByName(containerName), nil
+}
+
 // Run a command in a container, returns the combined stdout, stderr as an array of bytes
 func (kl *Kubelet) RunInContainer(podFullName string, uid types.UID, container string, cmd []string) ([]byte, error) {
 	uid = kl.podManager.TranslatePodUID(uid)
@@ -2123,15 +1889,14 @@ func (kl *Kubelet) RunInContainer(podFullName string, uid types.UID, container s
 	if kl.runner == nil {
 		return nil, fmt.Errorf("no runner specified.")
 	}
-	dockerContainers, err := dockertools.GetKubeletDockerContainers(kl.dockerClient, false)
+	c, err := kl.findContainer(podFullName, uid, container)
 	if err != nil {
 		return nil, err
 	}
-	dockerContainer, found, _ := dockerContainers.FindPodContainer(podFullName, uid, container)
-	if !found {
+	if c == nil {
 		return nil, fmt.Errorf("container not found (%q)", container)
 	}
-	return kl.runner.RunInContainer(dockerContainer.ID, cmd)
+	return kl.runner.RunInContainer(string(c.ID), cmd)
 }
 
 // ExecInContainer executes a command in a container, connecting the supplied
@@ -2142,15 +1907,14 @@ func (kl *Kubelet) ExecInContainer(podFullName string, uid types.UID, container
 	if kl.runner == nil {
 		return fmt.Errorf("no runner specified.")
 	}
-	dockerContainers, err := dockertools.GetKubeletDockerContainers(kl.dockerClient, false)
+	c, err := kl.findContainer(podFullName, uid, container)
 	if err != nil {
 		return err
 	}
-	dockerContainer, found, _ := dockerContainers.FindPodContainer(podFullName, uid, container)
-	if !found {
+	if c == nil {
 		return fmt.Errorf("container not found (%q)", container)
 	}
-	return kl.runner.ExecInContainer(dockerContainer.ID, cmd, stdin, stdout, stderr, tty)
+	return kl.runner.ExecInContainer(string(c.ID), cmd, stdin, stdout, stderr, tty)
 }
 
 // PortForward connects to the pod's port and copies data between the port
@@ -2161,15 +1925,15 @@ func (kl *Kubelet) PortForward(podFullName string, uid types.UID, port uint16, s
 	if kl.runner == nil {
 		return fmt.Errorf("no runner specified.")
 	}
-	dockerContainers, err := dockertools.GetKubeletDockerContainers(kl.dockerClient, false)
+	pods, err := kl.containerRuntime.GetPods(false)
 	if err != nil {
 		return err
 	}
-	podInfraContainer, found, _ := dockerContainers.FindPodContainer(podFullName, uid, dockertools.PodInfraContainerName)
-	if !found {
-		return fmt.Errorf("Unable to find pod infra container for pod %q, uid %v", podFullName, uid)
+	pod := kubecontainer.Pods(pods).FindPod(podFullName, uid)
+	if pod.IsEmpty() {
+		return fmt.Errorf("pod not found (%q)", podFullName)
 	}
-	return kl.runner.PortForward(podInfraContainer.ID, port, stream)
+	return kl.runner.PortForward(&pod, port, stream)
 }
 
 // BirthCry sends an event that the kubelet has started up.
@@ -2195,19 +1959,21 @@ func (kl *Kubelet) GetContainerInfo(podFullName string, uid types.UID, container
 
 	uid = kl.podManager.TranslatePodUID(uid)
 
-	dockerContainers, err := dockertools.GetKubeletDockerContainers(kl.dockerClient, false)
+	pods, err := kl.containerRuntime.GetPods(false)
 	if err != nil {
 		return nil, err
 	}
-	if len(dockerContainers) == 0 {
+	if len(pods) == 0 {
 		return nil, ErrNoKubeletContainers
 	}
-	dockerContainer, found, _ := dockerContainers.FindPodContainer(podFullName, uid, containerName)
-	if !found {
+	pod := kubecontainer.Pods(pods).FindPod(podFullName, uid)
+	container := pod.FindContainerByName(containerName)
+	if container == nil {
 		return nil, ErrContainerNotFound
 	}
 
-	ci, err := kl.cadvisor.DockerContainer(dockerContainer.ID, req)
+	// TODO: cAdvisor only knows about Docker containers for now.
+	ci, err := kl.cadvisor.DockerContainer(string(container.ID), req)
 	if err != nil {
 		return nil, err
 	}
@@ -68,15 +68,11 @@ type TestKubelet struct {
 
 func newTestKubelet(t *testing.T) *TestKubelet {
 	fakeDocker := &dockertools.FakeDockerClient{RemovedImages: util.StringSet{}}
-	fakeDockerCache := dockertools.NewFakeDockerCache(fakeDocker)
 	fakeRecorder := &record.FakeRecorder{}
 	fakeKubeClient := &client.Fake{}
 
 	kubelet := &Kubelet{}
-	kubelet.dockerClient = fakeDocker
-	kubelet.dockerCache = fakeDockerCache
 	kubelet.kubeClient = fakeKubeClient
-	kubelet.dockerPuller = &dockertools.FakeDockerPuller{}
 	kubelet.hostname = "testnode"
 	kubelet.networkPlugin, _ = network.InitNetworkPlugin([]network.NetworkPlugin{}, "", network.NewFakeHost(nil))
 	if tempDir, err := ioutil.TempDir("/tmp", "kubelet_test."); err != nil {
@@ -88,21 +84,16 @@ func newTestKubelet(t *testing.T) *TestKubelet {
 		t.Fatalf("can't mkdir(%q): %v", kubelet.rootDirectory, err)
 	}
 	waitGroup := new(sync.WaitGroup)
-	kubelet.podWorkers = newPodWorkers(
-		fakeDockerCache,
-		func(pod *api.Pod, mirrorPod *api.Pod, runningPod container.Pod) error {
-			err := kubelet.syncPod(pod, mirrorPod, runningPod)
-			waitGroup.Done()
-			return err
-		},
-		fakeRecorder)
 	kubelet.sourcesReady = func() bool { return true }
 	kubelet.masterServiceNamespace = api.NamespaceDefault
 	kubelet.serviceLister = testServiceLister{}
 	kubelet.nodeLister = testNodeLister{}
-	kubelet.readiness = newReadinessStates()
+	kubelet.readinessManager = container.NewReadinessManager()
+	kubelet.containerRefManager = container.NewRefManager()
+	kubelet.volumeManager = newVolumeManager()
 	kubelet.recorder = fakeRecorder
 	kubelet.statusManager = newStatusManager(fakeKubeClient)
+	kubelet.probeWorkers = newTestProbeWorkers(kubelet)
 	if err := kubelet.setupDataDirs(); err != nil {
 		t.Fatalf("can't initialize kubelet data dirs: %v", err)
 	}
@@ -110,9 +101,37 @@ func newTestKubelet(t *testing.T) *TestKubelet {
 	kubelet.cadvisor = mockCadvisor
 	podManager, fakeMirrorClient := newFakePodManager()
 	kubelet.podManager = podManager
+	kubelet.containerRuntime = newTestDockerManager(kubelet, fakeDocker)
+	kubelet.runner = kubelet.containerRuntime
+	kubelet.runtimeCache = container.NewFakeRuntimeCache(kubelet.containerRuntime)
+	kubelet.podWorkers = newPodWorkers(
+		kubelet.runtimeCache,
+		func(pod *api.Pod, mirrorPod *api.Pod, runningPod container.Pod) error {
+			err := kubelet.syncPod(pod, mirrorPod, runningPod)
+			waitGroup.Done()
+			return err
+		},
+		fakeRecorder)
 	return &TestKubelet{kubelet, fakeDocker, mockCadvisor, fakeKubeClient, waitGroup, fakeMirrorClient}
 }
 
+// newTestDockerManager creates a docker runtime on top of the given client,
+// wired to the hooks of the test kubelet.
+func newTestDockerManager(kubelet *Kubelet, client dockertools.DockerInterface) *dockertools.DockerManager {
+	return dockertools.NewFakeDockerManager(
+		client,
+		kubelet.recorder,
+		kubelet.readinessManager,
+		kubelet.containerRefManager,
+		PodInfraContainerImage,
+		0,
+		0,
+		kubelet.networkPlugin,
+		kubelet,
+		&kubeletProber{kubelet},
+		&kubeletHandlerRunner{kubelet})
+}
+
 func verifyCalls(t *testing.T, fakeDocker *dockertools.FakeDockerClient, calls []string) {
 	err := fakeDocker.AssertCalls(calls)
 	if err != nil {
@@ -350,81 +369,6 @@ func dockerContainersToPod(containers dockertools.DockerContainers) container.Po
 	return pod
 }
 
-func TestKillContainerWithError(t *testing.T) {
-	containers := []docker.APIContainers{
-		{
-			ID:    "1234",
-			Names: []string{"/k8s_foo_qux_new_1234_42"},
-		},
-		{
-			ID:    "5678",
-			Names: []string{"/k8s_bar_qux_new_5678_42"},
-		},
-	}
-	fakeDocker := &dockertools.FakeDockerClient{
-		Err:           fmt.Errorf("sample error"),
-		ContainerList: append([]docker.APIContainers{}, containers...),
-	}
-	testKubelet := newTestKubelet(t)
-	kubelet := testKubelet.kubelet
-	for _, c := range fakeDocker.ContainerList {
-		kubelet.readiness.set(c.ID, true)
-	}
-	kubelet.dockerClient = fakeDocker
-	c := apiContainerToContainer(fakeDocker.ContainerList[0])
-	err := kubelet.killContainer(&c)
-	if err == nil {
-		t.Errorf("expected error, found nil")
-	}
-	verifyCalls(t, fakeDocker, []string{"stop"})
-	killedContainer := containers[0]
-	liveContainer := containers[1]
-	if _, found := kubelet.readiness.states[killedContainer.ID]; found {
-		t.Errorf("exepcted container entry ID '%v' to not be found. states: %+v", killedContainer.ID, kubelet.readiness.states)
-	}
-	if _, found := kubelet.readiness.states[liveContainer.ID]; !found {
-		t.Errorf("exepcted container entry ID '%v' to be found. states: %+v", liveContainer.ID, kubelet.readiness.states)
-	}
-}
-
-func TestKillContainer(t *testing.T) {
-	containers := []docker.APIContainers{
-		{
-			ID:    "1234",
-			Names: []string{"/k8s_foo_qux_new_1234_42"},
-		},
-		{
-			ID:    "5678",
-			Names: []string{"/k8s_bar_qux_new_5678_42"},
-		},
-	}
-	testKubelet := newTestKubelet(t)
-	kubelet := testKubelet.kubelet
-	fakeDocker := testKubelet.fakeDocker
-	fakeDocker.ContainerList = append([]docker.APIContainers{}, containers...)
-	fakeDocker.Container = &docker.Container{
-		Name: "foobar",
-	}
-	for _, c := range fakeDocker.ContainerList {
-		kubelet.readiness.set(c.ID, true)
-	}
-
-	c := apiContainerToContainer(fakeDocker.ContainerList[0])
-	err := kubelet.killContainer(&c)
-	if err != nil {
-		t.Errorf("unexpected error: %v", err)
-	}
-	verifyCalls(t, fakeDocker, []string{"stop"})
-	killedContainer := containers[0]
-	liveContainer := containers[1]
-	if _, found := kubelet.readiness.states[killedContainer.ID]; found {
-		t.Errorf("exepcted container entry ID '%v' to not be found. states: %+v", killedContainer.ID, kubelet.readiness.states)
-	}
-	if _, found := kubelet.readiness.states[liveContainer.ID]; !found {
-		t.Errorf("exepcted container entry ID '%v' to be found. states: %+v", liveContainer.ID, kubelet.readiness.states)
-	}
-}
-
 type channelReader struct {
 	list [][]api.Pod
 	wg   sync.WaitGroup
@@ -530,7 +474,7 @@ func TestSyncPodsWithTerminationLog(t *testing.T) {
 	}
 	waitGroup.Wait()
 	verifyCalls(t, fakeDocker, []string{
-		"list", "list", "list", "create", "start", "inspect_container", "create", "start", "list", "inspect_container", "inspect_container"})
+		"list", "list", "list", "list", "create", "start", "inspect_container", "create", "start", "list", "inspect_container", "inspect_container"})
 
 	fakeDocker.Lock()
 	parts := strings.Split(fakeDocker.Container.HostConfig.Binds[0], ":")
@@ -557,7 +501,7 @@ func TestSyncPodsCreatesNetAndContainer(t *testing.T) {
 	kubelet := testKubelet.kubelet
 	fakeDocker := testKubelet.fakeDocker
 	waitGroup := testKubelet.waitGroup
-	kubelet.podInfraContainerImage = "custom_image_name"
+	kubelet.containerRuntime.(*dockertools.DockerManager).PodInfraContainerImage = "custom_image_name"
 	fakeDocker.ContainerList = []docker.APIContainers{}
 	pods := []api.Pod{
 		{
@@ -582,7 +526,7 @@ func TestSyncPodsCreatesNetAndContainer(t *testing.T) {
 	waitGroup.Wait()
 
 	verifyCalls(t, fakeDocker, []string{
-		"list", "list", "list", "create", "start", "inspect_container", "create", "start", "list", "inspect_container", "inspect_container"})
+		"list", "list", "list", "list", "create", "start", "inspect_container", "create", "start", "list", "inspect_container", "inspect_container"})
 
 	fakeDocker.Lock()
 
@@ -610,9 +554,10 @@ func TestSyncPodsCreatesNetAndContainerPullsImage(t *testing.T) {
 	kubelet := testKubelet.kubelet
 	fakeDocker := testKubelet.fakeDocker
 	waitGroup := testKubelet.waitGroup
-	puller := kubelet.dockerPuller.(*dockertools.FakeDockerPuller)
+	dm := kubelet.containerRuntime.(*dockertools.DockerManager)
+	puller := dm.Puller.(*dockertools.FakeDockerPuller)
 	puller.HasImages = []string{}
-	kubelet.podInfraContainerImage = "custom_image_name"
+	dm.PodInfraContainerImage = "custom_image_name"
 	fakeDocker.ContainerList = []docker.APIContainers{}
 	pods := []api.Pod{
 		{
@@ -637,7 +582,7 @@ func TestSyncPodsCreatesNetAndContainerPullsImage(t *testing.T) {
 	waitGroup.Wait()
 
 	verifyCalls(t, fakeDocker, []string{
-		"list", "list", "list", "create", "start", "inspect_container", "create", "start", "list", "inspect_container", "inspect_container"})
+		"list", "list", "list", "list", "create", "start", "inspect_container", "create", "start", "list", "inspect_container", "inspect_container"})
 
 	fakeDocker.Lock()
 
@@ -864,7 +809,7 @@ func TestSyncPodsDeletesWhenSourcesAreReady(t *testing.T) {
 	if err := kubelet.SyncPods([]api.Pod{}, emptyPodUIDs, map[string]api.Pod{}, time.Now()); err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
-	verifyCalls(t, fakeDocker, []string{"list", "stop", "stop", "inspect_container", "inspect_container"})
+	verifyCalls(t, fakeDocker, []string{"list", "stop", "stop", "list"})
 
 	// A map iteration is used to delete containers, so must not depend on
 	// order here.
@@ -905,7 +850,7 @@ func TestSyncPodsDeletes(t *testing.T) {
 		t.Errorf("unexpected error: %v", err)
 	}
 
-	verifyCalls(t, fakeDocker, []string{"list", "stop", "stop", "inspect_container", "inspect_container"})
+	verifyCalls(t, fakeDocker, []string{"list", "stop", "stop", "list"})
 
 	// A map iteration is used to delete containers, so must not depend on
 	// order here.
@@ -967,6 +912,56 @@ func TestSyncPodDeletesDuplicate(t *testing.T) {
 	}
 }
 
+func TestSyncPodKillsTerminatingPod(t *testing.T) {
+	testKubelet := newTestKubelet(t)
+	kubelet := testKubelet.kubelet
+	fakeDocker := testKubelet.fakeDocker
+	dockerContainers := dockertools.DockerContainers{
+		"1234": &docker.APIContainers{
+			// the k8s prefix is required for the kubelet to manage the container
+			Names: []string{"/k8s_foo_bar_new_12345678_1111"},
+			ID:    "1234",
+		},
+		"9876": &docker.APIContainers{
+			// pod infra container
+			Names: []string{"/k8s_POD_bar_new_12345678_2222"},
+			ID:    "9876",
+		},
+	}
+	deletionTimestamp := util.Now()
+	gracePeriod := int64(10)
+	bound := api.Pod{
+		ObjectMeta: api.ObjectMeta{
+			UID:       "12345678",
+			Name:      "bar",
+			Namespace: "new",
+
+			DeletionTimestamp:          &deletionTimestamp,
+			DeletionGracePeriodSeconds: &gracePeriod,
+		},
+		Spec: api.PodSpec{
+			Containers: []api.Container{
+				{Name: "foo"},
+			},
+		},
+	}
+	pods := []api.Pod{bound}
+	kubelet.podManager.SetPods(pods)
+	err := kubelet.syncPod(&bound, nil, dockerContainersToPod(dockerContainers))
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// The application container is stopped before the pod infra container, and
+	// both are given the grace period of the deletion.
+	if !reflect.DeepEqual(fakeDocker.Stopped, []string{"1234", "9876"}) {
+		t.Errorf("Wrong containers were stopped: %v", fakeDocker.Stopped)
+	}
+	if !reflect.DeepEqual(fakeDocker.StopTimeouts, []uint{10, 10}) {
+		t.Errorf("Wrong stop timeouts: %v", fakeDocker.StopTimeouts)
+	}
+}
+
 func TestSyncPodBadHash(t *testing.T) {
 	testKubelet := newTestKubelet(t)
 	kubelet := testKubelet.kubelet
@@ -1052,6 +1047,9 @@ func TestSyncPodUnhealthy(t *testing.T) {
 	}
 	pods := []api.Pod{bound}
 	kubelet.podManager.SetPods(pods)
+	// The container is only killed once its probe has failed FailureThreshold times.
+	kubelet.probeContainer(&bound, api.PodStatus{}, bound.Spec.Containers[0], "1234", 0)
+	stepProbeWorkers(kubelet, defaultProbeFailureThreshold)
 	err := kubelet.syncPod(&bound, nil, dockerContainersToPod(dockerContainers))
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
@@ -1153,8 +1151,8 @@ func (f *stubVolume) GetPath() string {
 	return f.path
 }
 
-func TestMakeVolumesAndBinds(t *testing.T) {
-	container := api.Container{
+func TestMakeMounts(t *testing.T) {
+	c := api.Container{
 		VolumeMounts: []api.VolumeMount{
 			{
 				MountPath: "/mnt/path",
@@ -1185,120 +1183,16 @@ func TestMakeVolumesAndBinds(t *testing.T) {
 		"disk5": &stubVolume{"/var/lib/kubelet/podID/volumes/empty/disk5"},
 	}
 
-	binds := makeBinds(&container, podVolumes)
-
-	expectedBinds := []string{
-		"/mnt/disk:/mnt/path",
-		"/mnt/disk:/mnt/path3:ro",
-		"/mnt/host:/mnt/path4",
-		"/var/lib/kubelet/podID/volumes/empty/disk5:/mnt/path5",
-	}
-
-	if len(binds) != len(expectedBinds) {
-		t.Errorf("Unexpected binds: Expected %#v got %#v.  Container was: %#v", expectedBinds, binds, container)
-	}
-	verifyStringArrayEquals(t, binds, expectedBinds)
-}
+	mounts := makeMounts(&c, podVolumes)
 
-func TestMakePortsAndBindings(t *testing.T) {
-	container := api.Container{
-		Ports: []api.ContainerPort{
-			{
-				ContainerPort: 80,
-				HostPort:      8080,
-				HostIP:        "127.0.0.1",
-			},
-			{
-				ContainerPort: 443,
-				HostPort:      443,
-				Protocol:      "tcp",
-			},
-			{
-				ContainerPort: 444,
-				HostPort:      444,
-				Protocol:      "udp",
-			},
-			{
-				ContainerPort: 445,
-				HostPort:      445,
-				Protocol:      "foobar",
-			},
-		},
-	}
-	exposedPorts, bindings := makePortsAndBindings(&container)
-	if len(container.Ports) != len(exposedPorts) ||
-		len(container.Ports) != len(bindings) {
-		t.Errorf("Unexpected ports and bindings, %#v %#v %#v", container, exposedPorts, bindings)
-	}
-	for key, value := range bindings {
-		switch value[0].HostPort {
-		case "8080":
-			if !reflect.DeepEqual(docker.Port("80/tcp"), key) {
-				t.Errorf("Unexpected docker port: %#v", key)
-			}
-			if value[0].HostIP != "127.0.0.1" {
-				t.Errorf("Unexpected host IP: %s", value[0].HostIP)
-			}
-		case "443":
-			if !reflect.DeepEqual(docker.Port("443/tcp"), key) {
-				t.Errorf("Unexpected docker port: %#v", key)
-			}
-			if value[0].HostIP != "" {
-				t.Errorf("Unexpected host IP: %s", value[0].HostIP)
-			}
-		case "444":
-			if !reflect.DeepEqual(docker.Port("444/udp"), key) {
-				t.Errorf("Unexpected docker port: %#v", key)
-			}
-			if value[0].HostIP != "" {
-				t.Errorf("Unexpected host IP: %s", value[0].HostIP)
-			}
-		case "445":
-			if !reflect.DeepEqual(docker.Port("445/tcp"), key) {
-				t.Errorf("Unexpected docker port: %#v", key)
-			}
-			if value[0].HostIP != "" {
-				t.Errorf("Unexpected host IP: %s", value[0].HostIP)
-			}
-		}
+	expectedMounts := []container.Mount{
+		{Name: "disk", ContainerPath: "/mnt/path", HostPath: "/mnt/disk", ReadOnly: false},
+		{Name: "disk", ContainerPath: "/mnt/path3", HostPath: "/mnt/disk", ReadOnly: true},
+		{Name: "disk4", ContainerPath: "/mnt/path4", HostPath: "/mnt/host", ReadOnly: false},
+		{Name: "disk5", ContainerPath: "/mnt/path5", HostPath: "/var/lib/kubelet/podID/volumes/empty/disk5", ReadOnly: false},
 	}
-}
-
-func TestFieldPath(t *testing.T) {
-	pod := &api.Pod{Spec: api.PodSpec{Containers: []api.Container{
-		{Name: "foo"},
-		{Name: "bar"},
-		{Name: ""},
-		{Name: "baz"},
-	}}}
-	table := map[string]struct {
-		pod       *api.Pod
-		container *api.Container
-		path      string
-		success   bool
-	}{
-		"basic":            {pod, &api.Container{Name: "foo"}, "spec.containers{foo}", true},
-		"basic2":           {pod, &api.Container{Name: "baz"}, "spec.containers{baz}", true},
-		"emptyName":        {pod, &api.Container{Name: ""}, "spec.containers[2]", true},
-		"basicSamePointer": {pod, &pod.Spec.Containers[0], "spec.containers{foo}", true},
-		"missing":          {pod, &api.Container{Name: "qux"}, "", false},
-	}
-
-	for name, item := range table {
-		res, err := fieldPath(item.pod, item.container)
-		if item.success == false {
-			if err == nil {
-				t.Errorf("%v: unexpected non-error", name)
-			}
-			continue
-		}
-		if err != nil {
-			t.Errorf("%v: unexpected error: %v", name, err)
-			continue
-		}
-		if e, a := item.path, res; e != a {
-			t.Errorf("%v: wanted %v, got %v", name, e, a)
-		}
+	if !reflect.DeepEqual(mounts, expectedMounts) {
+		t.Errorf("Unexpected mounts: Expected %#v got %#v.  Container was: %#v", expectedMounts, mounts, c)
 	}
 }
 
@@ -1354,16 +1248,12 @@ func TestGetRootInfo(t *testing.T) {
 			Name: containerPath,
 		},
 	}
-	fakeDocker := dockertools.FakeDockerClient{}
-
 	mockCadvisor := &cadvisor.Mock{}
 	cadvisorReq := &cadvisorApi.ContainerInfoRequest{}
 	mockCadvisor.On("ContainerInfo", containerPath, cadvisorReq).Return(containerInfo, nil)
 
 	kubelet := Kubelet{
-		dockerClient: &fakeDocker,
-		dockerPuller: &dockertools.FakeDockerPuller{},
-		cadvisor:     mockCadvisor,
+		cadvisor: mockCadvisor,
 	}
 
 	// If the container name is an empty string, then it means the root container.
@@ -1427,7 +1317,7 @@ func TestGetContainerInfoWhenDockerToolsFailed(t *testing.T) {
 	kubelet := testKubelet.kubelet
 	mockCadvisor := testKubelet.fakeCadvisor
 	expectedErr := fmt.Errorf("List containers error")
-	kubelet.dockerClient = &errorTestingDockerClient{listContainersError: expectedErr}
+	kubelet.containerRuntime = newTestDockerManager(kubelet, &errorTestingDockerClient{listContainersError: expectedErr})
 
 	stats, err := kubelet.GetContainerInfo("qux", "", "foo", nil)
 	if err == nil {
@@ -1447,7 +1337,7 @@ func TestGetContainerInfoWithNoContainers(t *testing.T) {
 	kubelet := testKubelet.kubelet
 	mockCadvisor := testKubelet.fakeCadvisor
 
-	kubelet.dockerClient = &errorTestingDockerClient{listContainersError: nil}
+	kubelet.containerRuntime = newTestDockerManager(kubelet, &errorTestingDockerClient{listContainersError: nil})
 	stats, err := kubelet.GetContainerInfo("qux_ns", "", "foo", nil)
 	if err == nil {
 		t.Errorf("Expected error from cadvisor client, got none")
@@ -1473,7 +1363,7 @@ func TestGetContainerInfoWithNoMatchingContainers(t *testing.T) {
 		},
 	}
 
-	kubelet.dockerClient = &errorTestingDockerClient{listContainersError: nil, containerList: containerList}
+	kubelet.containerRuntime = newTestDockerManager(kubelet, &errorTestingDockerClient{listContainersError: nil, containerList: containerList})
 	stats, err := kubelet.GetContainerInfo("qux_ns", "", "foo", nil)
 	if err == nil {
 		t.Errorf("Expected error from cadvisor client, got none")
@@ -1519,8 +1409,12 @@ func (f *fakeContainerCommandRunner) ExecInContainer(id string, cmd []string, in
 	return f.E
 }
 
-func (f *fakeContainerCommandRunner) PortForward(podInfraContainerID string, port uint16, stream io.ReadWriteCloser) error {
-	f.ID = podInfraContainerID
+func (f *fakeContainerCommandRunner) PortForward(pod *kubecontainer.Pod, port uint16, stream io.ReadWriteCloser) error {
+	podInfraContainer := pod.FindContainerByName(dockertools.PodInfraContainerName)
+	if podInfraContainer == nil {
+		return fmt.Errorf("cannot find pod infra container in pod %q", kubecontainer.BuildPodFullName(pod.Name, pod.Namespace))
+	}
+	f.ID = string(podInfraContainer.ID)
 	f.Port = port
 	f.Stream = stream
 	return nil
@@ -1761,9 +1655,10 @@ func TestSyncPodsWithPullPolicy(t *testing.T) {
 	kubelet := testKubelet.kubelet
 	fakeDocker := testKubelet.fakeDocker
 	waitGroup := testKubelet.waitGroup
-	puller := kubelet.dockerPuller.(*dockertools.FakeDockerPuller)
+	dm := kubelet.containerRuntime.(*dockertools.DockerManager)
+	puller := dm.Puller.(*dockertools.FakeDockerPuller)
 	puller.HasImages = []string{"existing_one", "want:latest"}
-	kubelet.podInfraContainerImage = "custom_image_name"
+	dm.PodInfraContainerImage = "custom_image_name"
 	fakeDocker.ContainerList = []docker.APIContainers{}
 	waitGroup.Add(1)
 	err := kubelet.SyncPods([]api.Pod{
@@ -1883,97 +1778,97 @@ func TestMakeEnvironmentVariables(t *testing.T) {
 		{
 			ObjectMeta: api.ObjectMeta{Name: "kubernetes", Namespace: api.NamespaceDefault},
 			Spec: api.ServiceSpec{
-				Port:     8081,
+				Ports:    []api.ServicePort{{Port: 8081}},
 				PortalIP: "1.2.3.1",
 			},
 		},
 		{
 			ObjectMeta: api.ObjectMeta{Name: "kubernetes-ro", Namespace: api.NamespaceDefault},
 			Spec: api.ServiceSpec{
-				Port:     8082,
+				Ports:    []api.ServicePort{{Port: 8082}},
 				PortalIP: "1.2.3.2",
 			},
 		},
 		{
 			ObjectMeta: api.ObjectMeta{Name: "kubernetes-ro", Namespace: api.NamespaceDefault},
 			Spec: api.ServiceSpec{
-				Port:     8082,
+				Ports:    []api.ServicePort{{Port: 8082}},
 				PortalIP: "None",
 			},
 		},
 		{
 			ObjectMeta: api.ObjectMeta{Name: "kubernetes-ro", Namespace: api.NamespaceDefault},
 			Spec: api.ServiceSpec{
-				Port:     8082,
+				Ports:    []api.ServicePort{{Port: 8082}},
 				PortalIP: "",
 			},
 		},
 		{
 			ObjectMeta: api.ObjectMeta{Name: "test", Namespace: "test1"},
 			Spec: api.ServiceSpec{
-				Port:     8083,
+				Ports:    []api.ServicePort{{Port: 8083}},
 				PortalIP: "1.2.3.3",
 			},
 		},
 		{
 			ObjectMeta: api.ObjectMeta{Name: "kubernetes", Namespace: "test2"},
 			Spec: api.ServiceSpec{
-				Port:     8084,
+				Ports:    []api.ServicePort{{Port: 8084}},
 				PortalIP: "1.2.3.4",
 			},
 		},
 		{
 			ObjectMeta: api.ObjectMeta{Name: "test", Namespace: "test2"},
 			Spec: api.ServiceSpec{
-				Port:     8085,
+				Ports:    []api.ServicePort{{Port: 8085}},
 				PortalIP: "1.2.3.5",
 			},
 		},
 		{
 			ObjectMeta: api.ObjectMeta{Name: "test", Namespace: "test2"},
 			Spec: api.ServiceSpec{
-				Port:     8085,
+				Ports:    []api.ServicePort{{Port: 8085}},
 				PortalIP: "None",
 			},
 		},
 		{
 			ObjectMeta: api.ObjectMeta{Name: "test", Namespace: "test2"},
 			Spec: api.ServiceSpec{
-				Port: 8085,
+				Ports: []api.ServicePort{{Port: 8085}},
 			},
 		},
 		{
 			ObjectMeta: api.ObjectMeta{Name: "kubernetes", Namespace: "kubernetes"},
 			Spec: api.ServiceSpec{
-				Port:     8086,
+				Ports:    []api.ServicePort{{Port: 8086}},
 				PortalIP: "1.2.3.6",
 			},
 		},
 		{
 			ObjectMeta: api.ObjectMeta{Name: "kubernetes-ro", Namespace: "kubernetes"},
 			Spec: api.ServiceSpec{
-				Port:     8087,
+				Ports:    []api.ServicePort{{Port: 8087}},
 				PortalIP: "1.2.3.7",
 			},
 		},
 		{
 			ObjectMeta: api.ObjectMeta{Name: "not-special", Namespace: "kubernetes"},
 			Spec: api.ServiceSpec{
-				Port:     8088,
+				Ports:    []api.ServicePort{{Port: 8088}},
 				PortalIP: "1.2.3.8",
 			},
 		},
 		{
 			ObjectMeta: api.ObjectMeta{Name: "not-special", Namespace: "kubernetes"},
 			Spec: api.ServiceSpec{
-				Port:     8088,
+				Ports:    []api.ServicePort{{Port: 8088}},
 				PortalIP: "None",
 			},
 		},
 		{
 			ObjectMeta: api.ObjectMeta{Name: "not-special", Namespace: "kubernetes"},
 			Spec: api.ServiceSpec{
-				Port:     8088,
+				Ports:    []api.ServicePort{{Port: 8088}},
 				PortalIP: "",
 			},
 		},
@@ -2166,7 +2061,8 @@ func TestMakeEnvironmentVariables(t *testing.T) {
 			kl.serviceLister = testServiceLister{services}
 		}
 
-		result, err := kl.makeEnvironmentVariables(tc.ns, tc.container)
+		pod := &api.Pod{ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: tc.ns}}
+		result, err := kl.makeEnvironmentVariables(pod, tc.container)
 		if err != nil {
 			t.Errorf("[%v] Unexpected error: %v", tc.name, err)
 		}
@@ -2182,6 +2078,79 @@ func TestMakeEnvironmentVariables(t *testing.T) {
 	}
 }
 
+func TestMakeEnvironmentVariablesFromSecrets(t *testing.T) {
+	testKubelet := newTestKubelet(t)
+	kl := testKubelet.kubelet
+	kl.serviceLister = nil
+	testKubelet.fakeKubeClient.Secret = api.Secret{
+		ObjectMeta: api.ObjectMeta{Name: "creds", Namespace: "test"},
+		Data:       map[string][]byte{"password": []byte("s3cr3t")},
+	}
+
+	container := &api.Container{
+		Env: []api.EnvVar{
+			{Name: "PLAIN", Value: "value"},
+			{Name: "PASSWORD", ValueFrom: &api.EnvVarSource{SecretKeyRef: &api.SecretKeySelector{Name: "creds", Key: "password"}}},
+			{Name: "PASSWORD_AGAIN", ValueFrom: &api.EnvVarSource{SecretKeyRef: &api.SecretKeySelector{Name: "creds", Key: "password"}}},
+		},
+	}
+	pod := &api.Pod{ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "test"}}
+	result, err := kl.makeEnvironmentVariables(pod, container)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := util.NewStringSet("PLAIN=value", "PASSWORD=s3cr3t", "PASSWORD_AGAIN=s3cr3t")
+	if resultSet := util.NewStringSet(result...); !reflect.DeepEqual(resultSet, expected) {
+		t.Errorf("expected env %v, got %v", expected.List(), resultSet.List())
+	}
+	gets := 0
+	for _, action := range testKubelet.fakeKubeClient.Actions {
+		if action.Action == "get-secret" {
+			gets++
+		}
+	}
+	if gets != 1 {
+		t.Errorf("expected the secret to be fetched once, got %d", gets)
+	}
+
+	container.Env = []api.EnvVar{
+		{Name: "MISSING", ValueFrom: &api.EnvVarSource{SecretKeyRef: &api.SecretKeySelector{Name: "creds", Key: "username"}}},
+	}
+	if _, err := kl.makeEnvironmentVariables(pod, container); err == nil {
+		t.Errorf("expected an error for a missing secret key")
+	}
+}
+
+func TestMakeEnvironmentVariablesFromPodFields(t *testing.T) {
+	testKubelet := newTestKubelet(t)
+	kl := testKubelet.kubelet
+	kl.serviceLister = nil
+	fakeRuntime := &container.FakeRuntime{PodStatus: api.PodStatus{PodIP: "1.2.3.4"}}
+	kl.containerRuntime = fakeRuntime
+
+	pod := &api.Pod{ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "test"}}
+	c := &api.Container{
+		Env: []api.EnvVar{
+			{Name: "POD_NAME", ValueFrom: &api.EnvVarSource{FieldRef: &api.ObjectFieldSelector{FieldPath: "metadata.name"}}},
+			{Name: "POD_NAMESPACE", ValueFrom: &api.EnvVarSource{FieldRef: &api.ObjectFieldSelector{FieldPath: "metadata.namespace"}}},
+			{Name: "POD_IP", ValueFrom: &api.EnvVarSource{FieldRef: &api.ObjectFieldSelector{FieldPath: "status.podIP"}}},
+		},
+	}
+	result, err := kl.makeEnvironmentVariables(pod, c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := util.NewStringSet("POD_NAME=foo", "POD_NAMESPACE=test", "POD_IP=1.2.3.4")
+	if resultSet := util.NewStringSet(result...); !reflect.DeepEqual(resultSet, expected) {
+		t.Errorf("expected env %v, got %v", expected.List(), resultSet.List())
+	}
+
+	fakeRuntime.PodStatus = api.PodStatus{}
+	if _, err := kl.makeEnvironmentVariables(pod, c); err == nil {
+		t.Errorf("expected an error for a pod without an IP")
+	}
+}
+
 func TestPodPhaseWithRestartAlways(t *testing.T) {
 	desiredState := api.PodSpec{
 		Containers: []api.Container{
@@ -2593,6 +2562,67 @@ func TestGetPodReadyCondition(t *testing.T) {
 	}
 }
 
+func TestGeneratePodStatusTerminatingPodIsNotReady(t *testing.T) {
+	testKubelet := newTestKubelet(t)
+	kubelet := testKubelet.kubelet
+	kubelet.nodeLister = testNodeLister{nodes: []api.Node{
+		{
+			ObjectMeta: api.ObjectMeta{Name: "testnode"},
+			Status: api.NodeStatus{
+				Addresses: []api.NodeAddress{{Type: api.NodeLegacyHostIP, Address: "127.0.0.1"}},
+			},
+		},
+	}}
+	kubelet.containerRuntime = &container.FakeRuntime{
+		PodStatus: api.PodStatus{
+			Info: api.PodInfo{
+				"foo": api.ContainerStatus{
+					ContainerID: "docker://1234",
+					State:       api.ContainerState{Running: &api.ContainerStateRunning{}},
+				},
+			},
+		},
+	}
+	kubelet.readinessManager.SetReadiness("1234", true)
+	pod := &api.Pod{
+		ObjectMeta: api.ObjectMeta{
+			UID:       "12345678",
+			Name:      "bar",
+			Namespace: "new",
+		},
+		Spec: api.PodSpec{
+			Containers: []api.Container{
+				{Name: "foo"},
+			},
+		},
+	}
+	unready := []api.PodCondition{{
+		Type:   api.PodReady,
+		Status: api.ConditionFalse,
+	}}
+
+	status, err := kubelet.generatePodStatusByPod(pod)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reflect.DeepEqual(status.Conditions, unready) {
+		t.Fatalf("expected a running pod to be ready, got %+v", status.Conditions)
+	}
+
+	now := util.Now()
+	pod.DeletionTimestamp = &now
+	status, err = kubelet.generatePodStatusByPod(pod)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(status.Conditions, unready) {
+		t.Errorf("expected a terminating pod to be unready, got %+v", status.Conditions)
+	}
+	if status.Info["foo"].Ready {
+		t.Errorf("expected the containers of a terminating pod to be unready")
+	}
+}
+
 func TestExecInContainerNoSuchPod(t *testing.T) {
 	fakeCommandRunner := fakeContainerCommandRunner{}
 	testKubelet := newTestKubelet(t)
@@ -2812,12 +2842,11 @@ func TestPortForward(t *testing.T) {
 	stream := &fakeReadWriteCloser{}
 
 	infraContainerID := "infra"
-	kubelet.podInfraContainerImage = "POD"
 
 	fakeDocker.ContainerList = []docker.APIContainers{
 		{
 			ID:    infraContainerID,
-			Names: []string{"/k8s_" + kubelet.podInfraContainerImage + "_" + podName + "_" + podNamespace + "_12345678_42"},
+			Names: []string{"/k8s_" + dockertools.PodInfraContainerName + "_" + podName + "_" + podNamespace + "_12345678_42"},
 		},
 		{
 			ID:    containerID,
@@ -2982,7 +3011,7 @@ func TestHandleMemExceeded(t *testing.T) {
 	testKubelet.fakeCadvisor.On("MachineInfo").Return(&cadvisorApi.MachineInfo{MemoryCapacity: 100}, nil)
 
 	spec := api.PodSpec{Containers: []api.Container{{Resources: api.ResourceRequirements{
-		Limits: api.ResourceList{
+		Requests: api.ResourceList{
 			"memory": resource.MustParse("90"),
 		},
 	}}}}
@@ -3104,7 +3133,7 @@ func TestValidateContainerStatus(t *testing.T) {
 	for i, tc := range testCases {
 		_, err := kubelet.validateContainerStatus(&api.PodStatus{
 			Info: tc.podInfo,
-		}, containerName)
+		}, containerName, false)
 		if tc.success {
 			if err != nil {
 				t.Errorf("[case %d]: unexpected failure - %v", i, err)
@@ -3115,9 +3144,27 @@ func TestValidateContainerStatus(t *testing.T) {
 	}
 	if _, err := kubelet.validateContainerStatus(&api.PodStatus{
 		Info: testCases[0].podInfo,
-	}, "blah"); err == nil {
+	}, "blah", false); err == nil {
 		t.Errorf("expected error with invalid container name")
 	}
+	if _, err := kubelet.validateContainerStatus(&api.PodStatus{
+		Info: testCases[0].podInfo,
+	}, containerName, true); err == nil {
+		t.Errorf("expected error without a previous terminated container")
+	}
+	containerID, err := kubelet.validateContainerStatus(&api.PodStatus{
+		Info: api.PodInfo{containerName: api.ContainerStatus{
+			State:                api.ContainerState{Running: &api.ContainerStateRunning{}},
+			LastTerminationState: api.ContainerState{Termination: &api.ContainerStateTerminated{ContainerID: "docker://previous"}},
+			ContainerID:          "docker://current",
+		}},
+	}, containerName, true)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if containerID != "previous" {
+		t.Errorf("expected the previous container, got %q", containerID)
+	}
 }
 
 func TestUpdateNewNodeStatus(t *testing.T) {
@@ -3271,6 +3318,187 @@ func TestUpdateExistingNodeStatus(t *testing.T) {
 	}
 }
 
+func TestUpdateNodeStatusWithPressure(t *testing.T) {
+	testKubelet := newTestKubelet(t)
+	kubelet := testKubelet.kubelet
+	kubeClient := testKubelet.fakeKubeClient
+	mockCadvisor := testKubelet.fakeCadvisor
+	oldTransitionTime := util.Date(2012, 1, 1, 0, 0, 0, 0, time.UTC)
+	kubeClient.MinionsList = api.NodeList{Items: []api.Node{
+		{
+			ObjectMeta: api.ObjectMeta{Name: "testnode"},
+			Status: api.NodeStatus{
+				Conditions: []api.NodeCondition{
+					{
+						Type:               api.NodeMemoryPressure,
+						Status:             api.ConditionFalse,
+						LastTransitionTime: oldTransitionTime,
+					},
+					{
+						Type:               api.NodeDiskPressure,
+						Status:             api.ConditionFalse,
+						LastTransitionTime: oldTransitionTime,
+					},
+				},
+			},
+		},
+	}}
+	mockCadvisor.On("MachineInfo").Return(&cadvisorApi.MachineInfo{}, nil)
+	kubelet.evictionManager = &realEvictionManager{pressure: nodePressure{api.NodeMemoryPressure: "low memory"}}
+
+	if err := kubelet.updateNodeStatus(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(kubeClient.Actions) != 2 {
+		t.Fatalf("unexpected actions: %v", kubeClient.Actions)
+	}
+	updatedNode, ok := kubeClient.Actions[1].Value.(*api.Node)
+	if !ok {
+		t.Fatalf("unexpected object type")
+	}
+	conditions := map[api.NodeConditionType]api.NodeCondition{}
+	for _, condition := range updatedNode.Status.Conditions {
+		conditions[condition.Type] = condition
+	}
+	memory := conditions[api.NodeMemoryPressure]
+	if memory.Status != api.ConditionTrue || memory.Message != "low memory" {
+		t.Errorf("unexpected memory pressure condition: %+v", memory)
+	}
+	if memory.LastTransitionTime.Equal(oldTransitionTime.Time) {
+		t.Errorf("expected the memory pressure transition time to be updated")
+	}
+	disk := conditions[api.NodeDiskPressure]
+	if disk.Status != api.ConditionFalse {
+		t.Errorf("unexpected disk pressure condition: %+v", disk)
+	}
+	if !disk.LastTransitionTime.Equal(oldTransitionTime.Time) {
+		t.Errorf("expected the disk pressure transition time to be kept, got %v", disk.LastTransitionTime)
+	}
+}
+
+// fakeEvictionManager reports a given pressure and picks a given pod.
+type fakeEvictionManager struct {
+	pressure nodePressure
+	pod      *api.Pod
+	reason   string
+	// Pods it was asked to pick from.
+	pods []*api.Pod
+}
+
+func (f *fakeEvictionManager) Observe() (nodePressure, error) {
+	return f.pressure, nil
+}
+
+func (f *fakeEvictionManager) LastPressure() nodePressure {
+	return f.pressure
+}
+
+func (f *fakeEvictionManager) PodToEvict(pods []*api.Pod, pressure nodePressure) (*api.Pod, string, error) {
+	f.pods = pods
+	return f.pod, f.reason, nil
+}
+
+func TestEvictPods(t *testing.T) {
+	testKubelet := newTestKubelet(t)
+	kubelet := testKubelet.kubelet
+	fakeDocker := testKubelet.fakeDocker
+	gracePeriod := int64(20)
+	pods := []api.Pod{
+		{
+			ObjectMeta: api.ObjectMeta{UID: "1", Name: "running", Namespace: "new"},
+			Spec:       api.PodSpec{TerminationGracePeriodSeconds: &gracePeriod},
+		},
+		{ObjectMeta: api.ObjectMeta{UID: "2", Name: "failed", Namespace: "new"}},
+	}
+	fakeDocker.ContainerList = []docker.APIContainers{
+		{ID: "1234", Names: []string{"/k8s_bar_running_new_1_42"}},
+	}
+	kubelet.podManager.SetPods(pods)
+	kubelet.statusManager.SetPodStatus(&pods[1], api.PodStatus{Phase: api.PodFailed})
+	fakeEviction := &fakeEvictionManager{
+		pressure: nodePressure{api.NodeMemoryPressure: "low memory"},
+		pod:      &pods[0],
+		reason:   "The node was under MemoryPressure: low memory.",
+	}
+	kubelet.evictionManager = fakeEviction
+
+	kubelet.evictPods()
+
+	if len(fakeEviction.pods) != 1 || fakeEviction.pods[0].Name != "running" {
+		t.Errorf("expected only the running pod to be considered, got %v", fakeEviction.pods)
+	}
+	status, found := kubelet.statusManager.GetPodStatus(kubecontainer.GetPodFullName(&pods[0]))
+	if !found {
+		t.Fatalf("expected the status of the evicted pod to be set")
+	}
+	if status.Phase != api.PodFailed || status.Message != fakeEviction.reason {
+		t.Errorf("unexpected status of the evicted pod: %+v", status)
+	}
+	if !reflect.DeepEqual(fakeDocker.Stopped, []string{"1234"}) || !reflect.DeepEqual(fakeDocker.StopTimeouts, []uint{20}) {
+		t.Errorf("expected the evicted pod to be killed with its grace period, got %v stopped with %v", fakeDocker.Stopped, fakeDocker.StopTimeouts)
+	}
+}
+
+func TestEvictPodsWaitsForEvictedPod(t *testing.T) {
+	testKubelet := newTestKubelet(t)
+	kubelet := testKubelet.kubelet
+	fakeDocker := testKubelet.fakeDocker
+	pods := []api.Pod{
+		{ObjectMeta: api.ObjectMeta{UID: "1", Name: "evicted", Namespace: "new"}},
+		{ObjectMeta: api.ObjectMeta{UID: "2", Name: "running", Namespace: "new"}},
+	}
+	kubelet.podManager.SetPods(pods)
+	kubelet.statusManager.SetPodStatus(&pods[0], api.PodStatus{Phase: api.PodFailed})
+	kubelet.setEvictedPod(&pods[0])
+	fakeEviction := &fakeEvictionManager{
+		pressure: nodePressure{api.NodeMemoryPressure: "low memory"},
+		pod:      &pods[1],
+	}
+	kubelet.evictionManager = fakeEviction
+
+	// The containers of the evicted pod are still there, so they are killed
+	// again and no other pod is evicted.
+	fakeDocker.ContainerList = []docker.APIContainers{
+		{ID: "1234", Names: []string{"/k8s_bar_evicted_new_1_42"}},
+	}
+	kubelet.evictPods()
+	if fakeEviction.pods != nil {
+		t.Errorf("expected no pod to be picked while the evicted pod is running, got asked with %v", fakeEviction.pods)
+	}
+	if !reflect.DeepEqual(fakeDocker.Stopped, []string{"1234"}) {
+		t.Errorf("expected the evicted pod to be killed again, got %v stopped", fakeDocker.Stopped)
+	}
+
+	// Once they are gone, the next pod is evicted.
+	kubelet.evictPods()
+	if len(fakeEviction.pods) != 1 || fakeEviction.pods[0].Name != "running" {
+		t.Errorf("expected the running pod to be considered, got %v", fakeEviction.pods)
+	}
+	if evicted := kubelet.getEvictedPod(); evicted != &pods[1] {
+		t.Errorf("expected the running pod to be evicted, got %v", evicted)
+	}
+}
+
+func TestEvictPodsWithoutPressure(t *testing.T) {
+	testKubelet := newTestKubelet(t)
+	kubelet := testKubelet.kubelet
+	pods := []api.Pod{
+		{ObjectMeta: api.ObjectMeta{UID: "1", Name: "running", Namespace: "new"}},
+	}
+	kubelet.podManager.SetPods(pods)
+	fakeEviction := &fakeEvictionManager{pressure: nodePressure{}, pod: &pods[0]}
+	kubelet.evictionManager = fakeEviction
+
+	kubelet.evictPods()
+
+	if fakeEviction.pods != nil {
+		t.Errorf("expected no pod to be picked, got asked with %v", fakeEviction.pods)
+	}
+	if _, found := kubelet.statusManager.GetPodStatus(kubecontainer.GetPodFullName(&pods[0])); found {
+		t.Errorf("expected no status to be set")
+	}
+}
+
 func TestUpdateNodeStatusError(t *testing.T) {
 	testKubelet := newTestKubelet(t)
 	kubelet := testKubelet.kubelet
@@ -3315,6 +3543,46 @@ func TestCreateMirrorPod(t *testing.T) {
 	}
 }
 
+func TestDeleteOutdatedMirrorPod(t *testing.T) {
+	testKubelet := newTestKubelet(t)
+	kl := testKubelet.kubelet
+	manager := testKubelet.fakeMirrorClient
+	pod := api.Pod{
+		ObjectMeta: api.ObjectMeta{
+			UID:       "12345678",
+			Name:      "bar",
+			Namespace: "foo",
+			Annotations: map[string]string{
+				ConfigSourceAnnotationKey: "file",
+				ConfigHashAnnotationKey:   "new",
+			},
+		},
+	}
+	mirrorPod := api.Pod{
+		ObjectMeta: api.ObjectMeta{
+			UID:       "11111111",
+			Name:      "bar",
+			Namespace: "foo",
+			Annotations: map[string]string{
+				ConfigSourceAnnotationKey: "api",
+				ConfigMirrorAnnotationKey: "mirror",
+				ConfigHashAnnotationKey:   "old",
+			},
+		},
+	}
+	pods := []api.Pod{pod, mirrorPod}
+	kl.podManager.SetPods(pods)
+	err := kl.syncPod(&pod, &mirrorPod, container.Pod{})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	name := kubecontainer.GetPodFullName(&pod)
+	creates, deletes := manager.GetCounts(name)
+	if creates != 1 || deletes != 1 {
+		t.Errorf("expected 1 creation and 1 deletion of %q, got %d, %d", name, creates, deletes)
+	}
+}
+
 func TestDeleteOrphanedMirrorPods(t *testing.T) {
 	testKubelet := newTestKubelet(t)
 	testKubelet.fakeCadvisor.On("MachineInfo").Return(&cadvisorApi.MachineInfo{}, nil)
@@ -3529,3 +3797,68 @@ func TestHostNetworkDisallowed(t *testing.T) {
 		t.Errorf("expected pod infra creation to fail")
 	}
 }
+
+func TestSyncPodsFetchesPullSecretsOnlyWhenPulling(t *testing.T) {
+	testKubelet := newTestKubelet(t)
+	testKubelet.fakeCadvisor.On("MachineInfo").Return(&cadvisorApi.MachineInfo{}, nil)
+	kubelet := testKubelet.kubelet
+	fakeDocker := testKubelet.fakeDocker
+	waitGroup := testKubelet.waitGroup
+	puller := kubelet.containerRuntime.(*dockertools.DockerManager).Puller.(*dockertools.FakeDockerPuller)
+	testKubelet.fakeKubeClient.Secret = api.Secret{
+		ObjectMeta: api.ObjectMeta{Name: "pull", Namespace: "new"},
+		Type:       api.SecretTypeDockercfg,
+	}
+	pods := []api.Pod{
+		{
+			ObjectMeta: api.ObjectMeta{
+				UID:       "12345678",
+				Name:      "foo",
+				Namespace: "new",
+			},
+			Spec: api.PodSpec{
+				Containers: []api.Container{
+					{Name: "bar", Image: "something", ImagePullPolicy: api.PullIfNotPresent},
+				},
+				ImagePullSecrets: []api.LocalObjectReference{{Name: "pull"}},
+			},
+		},
+	}
+	secretGets := func() int {
+		gets := 0
+		for _, action := range testKubelet.fakeKubeClient.Actions {
+			if action.Action == "get-secret" {
+				gets++
+			}
+		}
+		return gets
+	}
+
+	// All images are present, so the secrets are not needed.
+	fakeDocker.ContainerList = []docker.APIContainers{}
+	kubelet.podManager.SetPods(pods)
+	waitGroup.Add(1)
+	if err := kubelet.SyncPods(pods, emptyPodUIDs, map[string]api.Pod{}, time.Now()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	waitGroup.Wait()
+	if gets := secretGets(); gets != 0 {
+		t.Errorf("expected no pull secrets to be fetched, got %d fetches", gets)
+	}
+
+	// Both the infra and the container image are pulled with a single fetch.
+	puller.HasImages = []string{}
+	fakeDocker.ContainerList = []docker.APIContainers{}
+	kubelet.podManager.SetPods(pods)
+	waitGroup.Add(1)
+	if err := kubelet.SyncPods(pods, emptyPodUIDs, map[string]api.Pod{}, time.Now()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	waitGroup.Wait()
+	if len(puller.ImagesPulled) != 2 {
+		t.Errorf("expected the infra and container images to be pulled, got %v", puller.ImagesPulled)
+	}
+	if gets := secretGets(); gets != 1 {
+		t.Errorf("expected the pull secrets to be fetched once, got %d fetches", gets)
+	}
+}
@@ -49,7 +49,13 @@ func (self *basicMirrorClient) CreateMirrorPod(pod api.Pod, hostname string) err
 	}
 	// Indicate that the pod should be scheduled to the current node.
 	pod.Spec.Host = hostname
-	pod.Annotations[ConfigMirrorAnnotationKey] = MirrorType
+	// Copy the annotations, they are shared with the static pod.
+	annotations := make(map[string]string, len(pod.Annotations)+1)
+	for k, v := range pod.Annotations {
+		annotations[k] = v
+	}
+	annotations[ConfigMirrorAnnotationKey] = MirrorType
+	pod.Annotations = annotations
 
 	_, err := self.apiserverClient.Pods(NamespaceDefault).Create(&pod)
 	return err
@@ -66,7 +72,7 @@ func (self *basicMirrorClient) DeleteMirrorPod(podFullName string) error {
 		return err
 	}
 	glog.V(4).Infof("Deleting a mirror pod %q", podFullName)
-	if err := self.apiserverClient.Pods(namespace).Delete(name); err != nil {
+	if err := self.apiserverClient.Pods(namespace).Delete(name, api.NewDeleteOptions(0)); err != nil {
 		glog.Errorf("Failed deleting a mirror pod %q: %v", podFullName, err)
 	}
 	return nil
@@ -94,3 +100,9 @@ func isMirrorPod(pod *api.Pod) bool {
 		return value == MirrorType
 	}
 }
+
+// isMirrorPodOf returns true if mirrorPod reflects the current content of the
+// static pod, i.e. they have the same content hash.
+func isMirrorPodOf(mirrorPod, pod *api.Pod) bool {
+	return mirrorPod.Annotations[ConfigHashAnnotationKey] == pod.Annotations[ConfigHashAnnotationKey]
+}
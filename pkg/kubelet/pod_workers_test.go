@@ -24,7 +24,6 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/record"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/container"
-	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/dockertools"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/types"
 )
 
@@ -38,15 +37,15 @@ func newPod(uid, name string) *api.Pod {
 }
 
 func createPodWorkers() (*podWorkers, map[types.UID][]string) {
-	fakeDocker := &dockertools.FakeDockerClient{}
-	fakeDockerCache := dockertools.NewFakeDockerCache(fakeDocker)
+	fakeRuntime := &container.FakeRuntime{}
+	fakeRuntimeCache := container.NewFakeRuntimeCache(fakeRuntime)
 	recorder := &record.FakeRecorder{}
 
 	lock := sync.Mutex{}
 	processed := make(map[types.UID][]string)
 
 	podWorkers := newPodWorkers(
-		fakeDockerCache,
+		fakeRuntimeCache,
 		func(pod *api.Pod, mirrorPod *api.Pod, runningPod container.Pod) error {
 			func() {
 				lock.Lock()
@@ -19,8 +19,6 @@ package kubelet
 import (
 	"fmt"
 	"strconv"
-	"strings"
-	"sync"
 	"time"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
@@ -35,89 +33,43 @@ import (
 	"github.com/golang/glog"
 )
 
-const maxProbeRetries = 3
+// kubeletProber adapts the probing of the kubelet to kubecontainer.Prober, so
+// that container runtimes can check the health of the containers they manage.
+type kubeletProber struct {
+	kubelet *Kubelet
+}
+
+func (kp *kubeletProber) Probe(pod *api.Pod, status api.PodStatus, container api.Container, containerID string, createdAt int64) (probe.Result, error) {
+	return kp.kubelet.probeContainer(pod, status, container, containerID, createdAt)
+}
 
-// probeContainer probes the liveness/readiness of the given container.
-// If the container's liveness probe is unsuccessful, set readiness to false.
-// If liveness is successful, do a readiness check and set readiness accordingly.
+// probeContainer returns the liveness of the given container, and makes sure its
+// probes are run by the probe workers. Probes are not run inline: the liveness and
+// readiness of the container only change once a probe has failed or succeeded as
+// many consecutive times as its thresholds require.
+// If the container is not live, its readiness is set to false. A container without
+// readiness probe is ready as long as it is live.
 func (kl *Kubelet) probeContainer(pod *api.Pod, status api.PodStatus, container api.Container, containerID string, createdAt int64) (probe.Result, error) {
-	// Probe liveness.
-	live, err := kl.probeContainerLiveness(pod, status, container, createdAt)
-	if err != nil {
-		glog.V(1).Infof("Liveness probe errored: %v", err)
-		kl.readiness.set(containerID, false)
-		return probe.Unknown, err
-	}
+	live := kl.probeWorkers.UpdateContainer(livenessProbe, container.LivenessProbe, pod, status, container, containerID, createdAt)
 	if live != probe.Success {
 		glog.V(1).Infof("Liveness probe unsuccessful: %v", live)
-		kl.readiness.set(containerID, false)
+		kl.readinessManager.SetReadiness(containerID, false)
 		return live, nil
 	}
 
-	// Probe readiness.
-	ready, err := kl.probeContainerReadiness(pod, status, container, createdAt)
-	if err == nil && ready == probe.Success {
-		glog.V(3).Infof("Readiness probe successful: %v", ready)
-		kl.readiness.set(containerID, true)
-		return probe.Success, nil
-	}
-
-	glog.V(1).Infof("Readiness probe failed/errored: %v, %v", ready, err)
-	kl.readiness.set(containerID, false)
-
-	ref, ok := kl.getRef(containerID)
-	if !ok {
-		glog.Warningf("No ref for pod '%v' - '%v'", containerID, container.Name)
+	ready := kl.probeWorkers.UpdateContainer(readinessProbe, container.ReadinessProbe, pod, status, container, containerID, createdAt)
+	if container.ReadinessProbe == nil {
+		kl.readinessManager.SetReadiness(containerID, true)
 	} else {
-		kl.recorder.Eventf(ref, "unhealthy", "Liveness Probe Failed %v - %v", containerID, container.Name)
+		glog.V(4).Infof("Readiness of %q: %v", containerID, ready)
 	}
-	return ready, err
-}
-
-// probeContainerLiveness probes the liveness of a container.
-// If the initalDelay since container creation on liveness probe has not passed the probe will return probe.Success.
-func (kl *Kubelet) probeContainerLiveness(pod *api.Pod, status api.PodStatus, container api.Container, createdAt int64) (probe.Result, error) {
-	p := container.LivenessProbe
-	if p == nil {
-		return probe.Success, nil
-	}
-	if time.Now().Unix()-createdAt < p.InitialDelaySeconds {
-		return probe.Success, nil
-	}
-	return kl.runProbeWithRetries(p, pod, status, container, maxProbeRetries)
-}
-
-// probeContainerLiveness probes the readiness of a container.
-// If the initial delay on the readiness probe has not passed the probe will return probe.Failure.
-func (kl *Kubelet) probeContainerReadiness(pod *api.Pod, status api.PodStatus, container api.Container, createdAt int64) (probe.Result, error) {
-	p := container.ReadinessProbe
-	if p == nil {
-		return probe.Success, nil
-	}
-	if time.Now().Unix()-createdAt < p.InitialDelaySeconds {
-		return probe.Failure, nil
-	}
-	return kl.runProbeWithRetries(p, pod, status, container, maxProbeRetries)
-}
-
-// runProbeWithRetries tries to probe the container in a finite loop, it returns the last result
-// if it never succeeds.
-func (kl *Kubelet) runProbeWithRetries(p *api.Probe, pod *api.Pod, status api.PodStatus, container api.Container, retires int) (probe.Result, error) {
-	var err error
-	var result probe.Result
-	for i := 0; i < retires; i++ {
-		result, err = kl.runProbe(p, pod, status, container)
-		if result == probe.Success {
-			return probe.Success, nil
-		}
-	}
-	return result, err
+	return probe.Success, nil
 }
 
 func (kl *Kubelet) runProbe(p *api.Probe, pod *api.Pod, status api.PodStatus, container api.Container) (probe.Result, error) {
 	timeout := time.Duration(p.TimeoutSeconds) * time.Second
 	if p.Exec != nil {
-		return kl.prober.exec.Probe(kl.newExecInContainer(pod, container))
+		return kl.prober.exec.Probe(kl.newExecInContainer(pod, container, p.Exec.Command))
 	}
 	if p.HTTPGet != nil {
 		port, err := extractPort(p.HTTPGet.Port, container)
@@ -134,7 +86,7 @@ func (kl *Kubelet) runProbe(p *api.Probe, pod *api.Pod, status api.PodStatus, co
 		}
 		return kl.prober.tcp.Probe(status.PodIP, port, timeout)
 	}
-	glog.Warningf("Failed to find probe builder for %s %+v", container.Name, container.LivenessProbe)
+	glog.Warningf("Failed to find probe builder for %s %+v", container.Name, p)
 	return probe.Unknown, nil
 }
 
@@ -188,11 +140,11 @@ type execInContainer struct {
 	run func() ([]byte, error)
 }
 
-func (kl *Kubelet) newExecInContainer(pod *api.Pod, container api.Container) exec.Cmd {
+func (kl *Kubelet) newExecInContainer(pod *api.Pod, container api.Container, cmd []string) exec.Cmd {
 	uid := pod.UID
 	podFullName := kubecontainer.GetPodFullName(pod)
 	return execInContainer{func() ([]byte, error) {
-		return kl.RunInContainer(podFullName, uid, container.Name, container.LivenessProbe.Exec.Command)
+		return kl.RunInContainer(podFullName, uid, container.Name, cmd)
 	}}
 }
 
@@ -204,44 +156,6 @@ func (eic execInContainer) SetDir(dir string) {
 	//unimplemented
 }
 
-// This will eventually maintain info about probe results over time
-// to allow for implementation of health thresholds
-func newReadinessStates() *readinessStates {
-	return &readinessStates{states: make(map[string]bool)}
-}
-
-type readinessStates struct {
-	// guards states
-	sync.RWMutex
-	states map[string]bool
-}
-
-func (r *readinessStates) IsReady(c api.ContainerStatus) bool {
-	if c.State.Running == nil {
-		return false
-	}
-	return r.get(strings.TrimPrefix(c.ContainerID, "docker://"))
-}
-
-func (r *readinessStates) get(key string) bool {
-	r.RLock()
-	defer r.RUnlock()
-	state, found := r.states[key]
-	return state && found
-}
-
-func (r *readinessStates) set(key string, value bool) {
-	r.Lock()
-	defer r.Unlock()
-	r.states[key] = value
-}
-
-func (r *readinessStates) remove(key string) {
-	r.Lock()
-	defer r.Unlock()
-	delete(r.states, key)
-}
-
 func newProbeHolder() probeHolder {
 	return probeHolder{
 		exec: execprobe.New(),
@@ -22,7 +22,9 @@ import (
 	"time"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kubecontainer "github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/container"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/probe"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/types"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/exec"
 
@@ -145,18 +147,41 @@ func (p fakeExecProber) Probe(_ exec.Cmd) (probe.Result, error) {
 }
 
 func makeTestKubelet(result probe.Result, err error) *Kubelet {
-	return &Kubelet{
-		readiness: newReadinessStates(),
+	kl := &Kubelet{
+		readinessManager:    kubecontainer.NewReadinessManager(),
+		containerRefManager: kubecontainer.NewRefManager(),
 		prober: probeHolder{
-			exec: fakeExecProber{
+			exec: &fakeExecProber{
 				result: result,
 				err:    err,
 			},
 		},
 	}
+	kl.probeWorkers = newTestProbeWorkers(kl)
+	return kl
 }
 
-// TestProbeContainer tests the functionality of probeContainer.
+// newTestProbeWorkers returns probe workers which only run when stepped by
+// stepProbeWorkers.
+func newTestProbeWorkers(kl *Kubelet) *probeWorkers {
+	pw := newProbeWorkers(kl.readinessManager, kl.containerRefManager, kl.recorder, kl.runProbe)
+	pw.startWorker = func(w *probeWorker) {}
+	return pw
+}
+
+// stepProbeWorkers runs every probe worker of the kubelet the given number of times.
+func stepProbeWorkers(kl *Kubelet, times int) {
+	kl.probeWorkers.lock.Lock()
+	defer kl.probeWorkers.lock.Unlock()
+	for _, w := range kl.probeWorkers.workers {
+		for i := 0; i < times; i++ {
+			w.doProbe()
+		}
+	}
+}
+
+// TestProbeContainer tests the functionality of probeContainer once the probe
+// workers have run enough times to reach the default thresholds.
 // Test cases are:
 //
 // No probe.
@@ -175,7 +200,8 @@ func TestProbeContainer(t *testing.T) {
 	}
 	tests := []struct {
 		testContainer     api.Container
-		expectError       bool
+		probeResult       probe.Result
+		probeError        bool
 		expectedResult    probe.Result
 		expectedReadiness bool
 	}{
@@ -190,6 +216,7 @@ func TestProbeContainer(t *testing.T) {
 			testContainer: api.Container{
 				LivenessProbe: &api.Probe{InitialDelaySeconds: 100},
 			},
+			probeResult:       probe.Failure,
 			expectedResult:    probe.Success,
 			expectedReadiness: true,
 		},
@@ -197,6 +224,7 @@ func TestProbeContainer(t *testing.T) {
 			testContainer: api.Container{
 				LivenessProbe: &api.Probe{InitialDelaySeconds: -100},
 			},
+			probeResult:       probe.Success,
 			expectedResult:    probe.Unknown,
 			expectedReadiness: false,
 		},
@@ -209,6 +237,7 @@ func TestProbeContainer(t *testing.T) {
 					},
 				},
 			},
+			probeResult:       probe.Failure,
 			expectedResult:    probe.Failure,
 			expectedReadiness: false,
 		},
@@ -221,6 +250,7 @@ func TestProbeContainer(t *testing.T) {
 					},
 				},
 			},
+			probeResult:       probe.Success,
 			expectedResult:    probe.Success,
 			expectedReadiness: true,
 		},
@@ -233,6 +263,7 @@ func TestProbeContainer(t *testing.T) {
 					},
 				},
 			},
+			probeResult:       probe.Unknown,
 			expectedResult:    probe.Unknown,
 			expectedReadiness: false,
 		},
@@ -245,7 +276,8 @@ func TestProbeContainer(t *testing.T) {
 					},
 				},
 			},
-			expectError:       true,
+			probeResult:       probe.Success,
+			probeError:        true,
 			expectedResult:    probe.Unknown,
 			expectedReadiness: false,
 		},
@@ -254,14 +286,16 @@ func TestProbeContainer(t *testing.T) {
 			testContainer: api.Container{
 				ReadinessProbe: &api.Probe{InitialDelaySeconds: 100},
 			},
-			expectedResult:    probe.Failure,
+			probeResult:       probe.Success,
+			expectedResult:    probe.Success,
 			expectedReadiness: false,
 		},
 		{
 			testContainer: api.Container{
 				ReadinessProbe: &api.Probe{InitialDelaySeconds: -100},
 			},
-			expectedResult:    probe.Unknown,
+			probeResult:       probe.Success,
+			expectedResult:    probe.Success,
 			expectedReadiness: false,
 		},
 		{
@@ -273,7 +307,8 @@ func TestProbeContainer(t *testing.T) {
 					},
 				},
 			},
-			expectedResult:    probe.Failure,
+			probeResult:       probe.Failure,
+			expectedResult:    probe.Success,
 			expectedReadiness: false,
 		},
 		{
@@ -285,6 +320,7 @@ func TestProbeContainer(t *testing.T) {
 					},
 				},
 			},
+			probeResult:       probe.Success,
 			expectedResult:    probe.Success,
 			expectedReadiness: true,
 		},
@@ -297,7 +333,8 @@ func TestProbeContainer(t *testing.T) {
 					},
 				},
 			},
-			expectedResult:    probe.Unknown,
+			probeResult:       probe.Unknown,
+			expectedResult:    probe.Success,
 			expectedReadiness: false,
 		},
 		{
@@ -309,8 +346,9 @@ func TestProbeContainer(t *testing.T) {
 					},
 				},
 			},
-			expectError:       true,
-			expectedResult:    probe.Unknown,
+			probeResult:       probe.Success,
+			probeError:        true,
+			expectedResult:    probe.Success,
 			expectedReadiness: false,
 		},
 		// Both LivenessProbe and ReadinessProbe.
@@ -319,7 +357,8 @@ func TestProbeContainer(t *testing.T) {
 				LivenessProbe:  &api.Probe{InitialDelaySeconds: 100},
 				ReadinessProbe: &api.Probe{InitialDelaySeconds: 100},
 			},
-			expectedResult:    probe.Failure,
+			probeResult:       probe.Success,
+			expectedResult:    probe.Success,
 			expectedReadiness: false,
 		},
 		{
@@ -327,7 +366,8 @@ func TestProbeContainer(t *testing.T) {
 				LivenessProbe:  &api.Probe{InitialDelaySeconds: 100},
 				ReadinessProbe: &api.Probe{InitialDelaySeconds: -100},
 			},
-			expectedResult:    probe.Unknown,
+			probeResult:       probe.Success,
+			expectedResult:    probe.Success,
 			expectedReadiness: false,
 		},
 		{
@@ -335,6 +375,7 @@ func TestProbeContainer(t *testing.T) {
 				LivenessProbe:  &api.Probe{InitialDelaySeconds: -100},
 				ReadinessProbe: &api.Probe{InitialDelaySeconds: 100},
 			},
+			probeResult:       probe.Success,
 			expectedResult:    probe.Unknown,
 			expectedReadiness: false,
 		},
@@ -343,6 +384,7 @@ func TestProbeContainer(t *testing.T) {
 				LivenessProbe:  &api.Probe{InitialDelaySeconds: -100},
 				ReadinessProbe: &api.Probe{InitialDelaySeconds: -100},
 			},
+			probeResult:       probe.Success,
 			expectedResult:    probe.Unknown,
 			expectedReadiness: false,
 		},
@@ -356,6 +398,7 @@ func TestProbeContainer(t *testing.T) {
 				},
 				ReadinessProbe: &api.Probe{InitialDelaySeconds: -100},
 			},
+			probeResult:       probe.Unknown,
 			expectedResult:    probe.Unknown,
 			expectedReadiness: false,
 		},
@@ -369,6 +412,7 @@ func TestProbeContainer(t *testing.T) {
 				},
 				ReadinessProbe: &api.Probe{InitialDelaySeconds: -100},
 			},
+			probeResult:       probe.Failure,
 			expectedResult:    probe.Failure,
 			expectedReadiness: false,
 		},
@@ -387,31 +431,92 @@ func TestProbeContainer(t *testing.T) {
 					},
 				},
 			},
+			probeResult:       probe.Success,
 			expectedResult:    probe.Success,
 			expectedReadiness: true,
 		},
 	}
 
-	for _, test := range tests {
+	for i, test := range tests {
 		var kl *Kubelet
 
-		if test.expectError {
-			kl = makeTestKubelet(test.expectedResult, errors.New("error"))
+		if test.probeError {
+			kl = makeTestKubelet(test.probeResult, errors.New("error"))
 		} else {
-			kl = makeTestKubelet(test.expectedResult, nil)
+			kl = makeTestKubelet(test.probeResult, nil)
 		}
+		kl.probeContainer(&api.Pod{}, api.PodStatus{}, test.testContainer, dc.ID, dc.Created)
+		stepProbeWorkers(kl, defaultProbeFailureThreshold)
 		result, err := kl.probeContainer(&api.Pod{}, api.PodStatus{}, test.testContainer, dc.ID, dc.Created)
-		if test.expectError && err == nil {
-			t.Error("Expected error but did no error was returned.")
-		}
-		if !test.expectError && err != nil {
-			t.Errorf("Expected error but got: %v", err)
+		if err != nil {
+			t.Errorf("%d: unexpected error: %v", i, err)
 		}
 		if test.expectedResult != result {
-			t.Errorf("Expected result was %v but probeContainer() returned %v", test.expectedResult, result)
+			t.Errorf("%d: expected result was %v but probeContainer() returned %v", i, test.expectedResult, result)
 		}
-		if test.expectedReadiness != kl.readiness.get(dc.ID) {
-			t.Errorf("Expected readiness was %v but probeContainer() set %v", test.expectedReadiness, kl.readiness.get(dc.ID))
+		if test.expectedReadiness != kl.readinessManager.GetReadiness(dc.ID) {
+			t.Errorf("%d: expected readiness was %v but probeContainer() set %v", i, test.expectedReadiness, kl.readinessManager.GetReadiness(dc.ID))
 		}
 	}
 }
+
+func TestProbeThresholds(t *testing.T) {
+	kl := makeTestKubelet(probe.Success, nil)
+	prober := kl.prober.exec.(*fakeExecProber)
+	handler := api.Handler{Exec: &api.ExecAction{}}
+	container := api.Container{
+		Name:           "foo",
+		LivenessProbe:  &api.Probe{Handler: handler, FailureThreshold: 3},
+		ReadinessProbe: &api.Probe{Handler: handler, SuccessThreshold: 2, FailureThreshold: 2},
+	}
+	pod := &api.Pod{ObjectMeta: api.ObjectMeta{UID: "12345678"}}
+	containerID := "foobar"
+	createdAt := time.Now().Unix()
+
+	steps := []struct {
+		probeResult       probe.Result
+		expectedResult    probe.Result
+		expectedReadiness bool
+	}{
+		// A new container is not ready before SuccessThreshold successes.
+		{probe.Success, probe.Success, false},
+		{probe.Success, probe.Success, true},
+		// One failure is not enough to change anything.
+		{probe.Failure, probe.Success, true},
+		{probe.Success, probe.Success, true},
+		{probe.Failure, probe.Success, true},
+		// FailureThreshold of the readiness probe is reached first.
+		{probe.Failure, probe.Success, false},
+		{probe.Success, probe.Success, false},
+		{probe.Failure, probe.Success, false},
+		{probe.Failure, probe.Success, false},
+		// FailureThreshold of the liveness probe is reached.
+		{probe.Failure, probe.Failure, false},
+	}
+	kl.probeContainer(pod, api.PodStatus{}, container, containerID, createdAt)
+	for i, step := range steps {
+		prober.result = step.probeResult
+		stepProbeWorkers(kl, 1)
+		result, _ := kl.probeContainer(pod, api.PodStatus{}, container, containerID, createdAt)
+		if result != step.expectedResult {
+			t.Errorf("%d: expected result %v, got %v", i, step.expectedResult, result)
+		}
+		if ready := kl.readinessManager.GetReadiness(containerID); ready != step.expectedReadiness {
+			t.Errorf("%d: expected readiness %v, got %v", i, step.expectedReadiness, ready)
+		}
+	}
+
+	// A new container starts over.
+	result, _ := kl.probeContainer(pod, api.PodStatus{}, container, "newcontainer", createdAt)
+	if result != probe.Success {
+		t.Errorf("expected a new container to be live, got %v", result)
+	}
+	if kl.readinessManager.GetReadiness("newcontainer") {
+		t.Errorf("expected a new container not to be ready")
+	}
+
+	kl.probeWorkers.ForgetNonExistingPods(map[types.UID]empty{})
+	if len(kl.probeWorkers.workers) != 0 {
+		t.Errorf("expected workers of deleted pods to be stopped, got %v", kl.probeWorkers.workers)
+	}
+}
@@ -0,0 +1,273 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/record"
+	kubecontainer "github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/container"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/probe"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/types"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
+	"github.com/golang/glog"
+)
+
+// Values used for the unset fields of a probe.
+const (
+	defaultProbePeriodSeconds    = 10
+	defaultProbeSuccessThreshold = 1
+	defaultProbeFailureThreshold = 3
+)
+
+// probeType distinguishes the liveness probe of a container from its readiness probe.
+type probeType int
+
+const (
+	livenessProbe probeType = iota
+	readinessProbe
+)
+
+func (t probeType) String() string {
+	switch t {
+	case livenessProbe:
+		return "Liveness"
+	case readinessProbe:
+		return "Readiness"
+	default:
+		return "Unknown"
+	}
+}
+
+type probeFnType func(p *api.Probe, pod *api.Pod, status api.PodStatus, container api.Container) (probe.Result, error)
+
+type probeKey struct {
+	podUID        types.UID
+	containerName string
+	probeType     probeType
+}
+
+// probeWorkers runs every probe of every container in its own goroutine, so that
+// pod syncs only read the latest results and a slow probe does not delay them.
+type probeWorkers struct {
+	// Protects workers.
+	lock    sync.Mutex
+	workers map[probeKey]*probeWorker
+
+	readinessManager    *kubecontainer.ReadinessManager
+	containerRefManager *kubecontainer.RefManager
+	recorder            record.EventRecorder
+
+	// This function runs a probe once against a container.
+	// NOTE: This function has to be thread-safe - it is called by every worker.
+	probeFn probeFnType
+
+	// This function starts the loop of a new worker.
+	startWorker func(w *probeWorker)
+}
+
+func newProbeWorkers(readinessManager *kubecontainer.ReadinessManager, containerRefManager *kubecontainer.RefManager,
+	recorder record.EventRecorder, probeFn probeFnType) *probeWorkers {
+	return &probeWorkers{
+		workers:             map[probeKey]*probeWorker{},
+		readinessManager:    readinessManager,
+		containerRefManager: containerRefManager,
+		recorder:            recorder,
+		probeFn:             probeFn,
+		startWorker: func(w *probeWorker) {
+			go w.run()
+		},
+	}
+}
+
+// UpdateContainer makes sure that the given probe of a container is run by a worker,
+// and returns its current result. The result of a container without such probe is
+// always probe.Success.
+func (pw *probeWorkers) UpdateContainer(t probeType, p *api.Probe, pod *api.Pod, status api.PodStatus, container api.Container, containerID string, createdAt int64) probe.Result {
+	key := probeKey{pod.UID, container.Name, t}
+
+	pw.lock.Lock()
+	w, exists := pw.workers[key]
+	if p == nil {
+		if exists {
+			close(w.stop)
+			delete(pw.workers, key)
+		}
+		pw.lock.Unlock()
+		return probe.Success
+	}
+	if !exists {
+		w = &probeWorker{
+			workers:   pw,
+			probeType: t,
+			stop:      make(chan struct{}),
+		}
+		pw.workers[key] = w
+	}
+	pw.lock.Unlock()
+
+	result := w.update(p, pod, status, container, containerID, createdAt)
+	if !exists {
+		pw.startWorker(w)
+	}
+	return result
+}
+
+// ForgetNonExistingPods stops the workers of the pods which are not desired anymore.
+func (pw *probeWorkers) ForgetNonExistingPods(desiredPods map[types.UID]empty) {
+	pw.lock.Lock()
+	defer pw.lock.Unlock()
+	for key, w := range pw.workers {
+		if _, exists := desiredPods[key.podUID]; !exists {
+			close(w.stop)
+			delete(pw.workers, key)
+		}
+	}
+}
+
+// probeWorker periodically runs a probe of a container, and changes the result of
+// the probe once the consecutive successes or failures reach the threshold of the probe.
+type probeWorker struct {
+	workers   *probeWorkers
+	probeType probeType
+	// Closed when the worker must exit.
+	stop chan struct{}
+
+	// Protects all fields below.
+	lock sync.Mutex
+	// The probe and the container it runs against, as of the last pod sync.
+	spec        *api.Probe
+	pod         *api.Pod
+	status      api.PodStatus
+	container   api.Container
+	containerID string
+	createdAt   int64
+	// The result of the probe, once thresholds are applied.
+	result probe.Result
+	// The outcome of the last run and the number of consecutive runs with the same outcome.
+	lastResult probe.Result
+	resultRun  int
+}
+
+// update records the latest state of the probed container. The result of the worker
+// is reset whenever the container is replaced.
+func (w *probeWorker) update(p *api.Probe, pod *api.Pod, status api.PodStatus, container api.Container, containerID string, createdAt int64) probe.Result {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.containerID != containerID {
+		w.containerID = containerID
+		w.createdAt = createdAt
+		w.resultRun = 0
+		// A new container is alive but not ready until its probes tell otherwise.
+		if w.probeType == livenessProbe {
+			w.result = probe.Success
+		} else {
+			w.result = probe.Failure
+			w.workers.readinessManager.SetReadiness(containerID, false)
+		}
+	}
+	w.spec = p
+	w.pod = pod
+	w.status = status
+	w.container = container
+	return w.result
+}
+
+func (w *probeWorker) run() {
+	defer util.HandleCrash()
+	for {
+		w.doProbe()
+
+		w.lock.Lock()
+		period := time.Duration(w.spec.PeriodSeconds) * time.Second
+		w.lock.Unlock()
+		if period <= 0 {
+			period = defaultProbePeriodSeconds * time.Second
+		}
+
+		select {
+		case <-w.stop:
+			return
+		case <-time.After(period):
+		}
+	}
+}
+
+// doProbe runs the probe once, unless the initial delay of the probe has not passed yet.
+func (w *probeWorker) doProbe() {
+	w.lock.Lock()
+	spec, pod, status, container, containerID, createdAt := w.spec, w.pod, w.status, w.container, w.containerID, w.createdAt
+	w.lock.Unlock()
+
+	if time.Now().Unix()-createdAt < spec.InitialDelaySeconds {
+		return
+	}
+	result, err := w.workers.probeFn(spec, pod, status, container)
+	if err != nil {
+		glog.V(1).Infof("%v probe for %q errored: %v", w.probeType, containerID, err)
+		result = probe.Unknown
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.containerID != containerID {
+		// The container was replaced while it was probed.
+		return
+	}
+	succeeded := result == probe.Success
+	if w.resultRun > 0 && (w.lastResult == probe.Success) == succeeded {
+		w.resultRun++
+	} else {
+		w.resultRun = 1
+	}
+	w.lastResult = result
+
+	threshold := spec.FailureThreshold
+	if threshold <= 0 {
+		threshold = defaultProbeFailureThreshold
+	}
+	if succeeded {
+		threshold = spec.SuccessThreshold
+		if threshold <= 0 {
+			threshold = defaultProbeSuccessThreshold
+		}
+	}
+	if w.resultRun < threshold {
+		return
+	}
+	changed := (w.result == probe.Success) != succeeded
+	w.result = result
+	if !changed {
+		return
+	}
+
+	glog.V(1).Infof("%v probe for %q changed to %v after %d consecutive runs", w.probeType, containerID, result, w.resultRun)
+	if w.probeType == readinessProbe {
+		w.workers.readinessManager.SetReadiness(containerID, succeeded)
+	}
+	if !succeeded {
+		ref, ok := w.workers.containerRefManager.GetRef(containerID)
+		if !ok {
+			glog.Warningf("No ref for pod '%v' - '%v'", containerID, container.Name)
+		} else {
+			w.workers.recorder.Eventf(ref, "unhealthy", "%v Probe Failed %v - %v", w.probeType, containerID, container.Name)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rkt
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kubecontainer "github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/container"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+)
+
+// rkt pod state.
+// TODO(yifan): Use exported definition in rkt.
+const (
+	Embryo         = "embryo"
+	Preparing      = "preparing"
+	AbortedPrepare = "aborted prepare"
+	Prepared       = "prepared"
+	Running        = "running"
+	Deleting       = "deleting" // This covers pod.isExitedDeleting and pod.isDeleting.
+	Exited         = "exited"   // This covers pod.isExited and pod.isExitedGarbage.
+	Garbage        = "garbage"
+)
+
+const (
+	statusStateKey    = "state"
+	statusPIDKey      = "pid"
+	statusNetworksKey = "networks"
+	statusAppPrefix   = "app-"
+)
+
+// podInfo is the internal type that represents the state of
+// the rkt pod, as reported by 'rkt status'.
+type podInfo struct {
+	// The state of the pod, e.g. running, exited.
+	state string
+	// The pid of the init process in the pod.
+	pid int
+	// The IP of the pod, if any.
+	ip string
+	// A map from app name to exit code.
+	exitCodes map[string]int
+}
+
+// newPodInfo returns an empty podInfo.
+func newPodInfo() *podInfo {
+	return &podInfo{
+		pid:       -1,
+		exitCodes: make(map[string]int),
+	}
+}
+
+// parseStatus parses the output of 'rkt status', which looks like:
+//
+// state=running
+// pid=1234
+// networks=default:ip4=172.16.28.3
+// app-etcd=0
+//
+// Unknown keys are ignored.
+func (p *podInfo) parseStatus(status []string) error {
+	for _, line := range status {
+		tuples := strings.SplitN(line, "=", 2)
+		if len(tuples) != 2 {
+			continue
+		}
+		key, value := strings.TrimSpace(tuples[0]), strings.TrimSpace(tuples[1])
+
+		switch {
+		case key == statusStateKey:
+			p.state = value
+		case key == statusPIDKey:
+			pid, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("cannot parse pid from %q: %v", value, err)
+			}
+			p.pid = pid
+		case key == statusNetworksKey:
+			p.ip = parseNetworks(value)
+		case strings.HasPrefix(key, statusAppPrefix):
+			exitCode, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("cannot parse exit code from %q: %v", value, err)
+			}
+			p.exitCodes[strings.TrimPrefix(key, statusAppPrefix)] = exitCode
+		}
+	}
+	return nil
+}
+
+// parseNetworks returns the first IPv4 address found in the networks value
+// of 'rkt status', e.g. "default:ip4=172.16.28.3,other:ip4=10.0.0.2".
+func parseNetworks(networks string) string {
+	for _, network := range strings.Split(networks, ",") {
+		parts := strings.SplitN(network, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if strings.HasPrefix(parts[1], "ip4=") {
+			return strings.TrimPrefix(parts[1], "ip4=")
+		}
+	}
+	return ""
+}
+
+// getIP returns the IP of the pod.
+func (p *podInfo) getIP() string {
+	return p.ip
+}
+
+// getContainerStatus converts the rkt pod state to the api.ContainerStatus.
+// TODO(yifan): Get more detailed info such as Image, ImageID, etc.
+func (p *podInfo) getContainerStatus(container *kubecontainer.Container) api.ContainerStatus {
+	var status api.ContainerStatus
+	status.Image = container.Image
+	status.ContainerID = buildContainerID(string(container.ID))
+
+	switch p.state {
+	case Running:
+		// TODO(yifan): Get StartedAt.
+		status.State = api.ContainerState{
+			Running: &api.ContainerStateRunning{
+				StartedAt: util.Unix(container.Created, 0),
+			},
+		}
+	case Embryo, Preparing, Prepared:
+		status.State = api.ContainerState{Waiting: &api.ContainerStateWaiting{}}
+	case AbortedPrepare, Deleting, Exited, Garbage:
+		exitCode, ok := p.exitCodes[container.Name]
+		if !ok {
+			exitCode = -1
+		}
+		status.State = api.ContainerState{
+			Termination: &api.ContainerStateTerminated{
+				ExitCode:  exitCode,
+				StartedAt: util.Unix(container.Created, 0),
+			},
+		}
+	default:
+		status.State = api.ContainerState{
+			Waiting: &api.ContainerStateWaiting{
+				Reason: fmt.Sprintf("Unknown rkt pod state %q", p.state),
+			},
+		}
+	}
+	return status
+}
+
+// toPodStatus converts a podInfo type into an api.PodStatus type.
+func (p *podInfo) toPodStatus(pod *kubecontainer.Pod) api.PodStatus {
+	var status api.PodStatus
+	status.PodIP = p.getIP()
+	status.Info = make(api.PodInfo)
+	for _, container := range pod.Containers {
+		status.Info[container.Name] = p.getContainerStatus(container)
+	}
+	return status
+}
+
+// unitOption represents an option in a systemd unit file.
+type unitOption struct {
+	Section string
+	Name    string
+	Value   string
+}
+
+// serializeUnit encodes the unit options into the format of a systemd unit
+// file. Options of the same section are grouped under a single header, in
+// the order the sections first appear.
+func serializeUnit(opts []*unitOption) string {
+	var sections []string
+	bySection := make(map[string][]*unitOption)
+	for _, opt := range opts {
+		if _, ok := bySection[opt.Section]; !ok {
+			sections = append(sections, opt.Section)
+		}
+		bySection[opt.Section] = append(bySection[opt.Section], opt)
+	}
+
+	var b bytes.Buffer
+	for i, section := range sections {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "[%s]\n", section)
+		for _, opt := range bySection[section] {
+			fmt.Fprintf(&b, "%s=%s\n", opt.Name, opt.Value)
+		}
+	}
+	return b.String()
+}
+
+// parseUnit decodes a systemd unit file written by serializeUnit. It does not
+// support line continuations.
+func parseUnit(r io.Reader) ([]*unitOption, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []*unitOption
+	var section string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = line[1 : len(line)-1]
+			continue
+		}
+		if section == "" {
+			return nil, fmt.Errorf("option %q does not belong to any section", line)
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid option %q", line)
+		}
+		opts = append(opts, &unitOption{
+			Section: section,
+			Name:    strings.TrimSpace(parts[0]),
+			Value:   strings.TrimSpace(parts[1]),
+		})
+	}
+	return opts, nil
+}
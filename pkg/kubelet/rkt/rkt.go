@@ -0,0 +1,970 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rkt
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/adler32"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/record"
+	kubecontainer "github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/container"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/probe"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/types"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/coreos/go-systemd/dbus"
+	"github.com/golang/glog"
+)
+
+const (
+	rktBinName = "rkt"
+
+	acVersion             = "0.5.1"
+	systemdServiceDir     = "/run/systemd/system"
+	dockerPrefix          = "docker://"
+	kubernetesUnitPrefix  = "k8s_"
+	unitKubernetesSection = "X-Kubernetes"
+	unitPodName           = "POD"
+	unitRktID             = "RktID"
+)
+
+// runtime implements the Containerruntime for rkt. The implementation
+// uses systemd, so in order to run this runtime, systemd must be installed
+// on the machine. Each pod is run as a transient systemd service unit that
+// invokes 'rkt run-prepared', and the pod spec and the rkt pod UUID are
+// stored in the unit file.
+type runtime struct {
+	systemd *dbus.Conn
+	absPath string
+	config  *Config
+
+	generator           kubecontainer.RunContainerOptionsGenerator
+	recorder            record.EventRecorder
+	prober              kubecontainer.Prober
+	readinessManager    *kubecontainer.ReadinessManager
+	containerRefManager *kubecontainer.RefManager
+}
+
+var _ kubecontainer.Runtime = &runtime{}
+
+// New creates the rkt container runtime which implements the container runtime interface.
+// It will test if the rkt binary is in the $PATH, and whether we can get the
+// version of it. If so, creates the rkt container runtime, otherwise returns an error.
+func New(config *Config,
+	generator kubecontainer.RunContainerOptionsGenerator,
+	recorder record.EventRecorder,
+	containerRefManager *kubecontainer.RefManager,
+	readinessManager *kubecontainer.ReadinessManager,
+	prober kubecontainer.Prober) (kubecontainer.Runtime, error) {
+
+	systemd, err := dbus.New()
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to dbus: %v", err)
+	}
+
+	// Test if rkt binary is in $PATH.
+	// TODO(yifan): Use a kubelet flag to read the path.
+	absPath := config.Path
+	if absPath == "" {
+		absPath, err = exec.LookPath(rktBinName)
+		if err != nil {
+			return nil, fmt.Errorf("cannot find rkt binary: %v", err)
+		}
+	}
+
+	rkt := &runtime{
+		systemd:             systemd,
+		absPath:             absPath,
+		config:              config,
+		generator:           generator,
+		recorder:            recorder,
+		prober:              prober,
+		readinessManager:    readinessManager,
+		containerRefManager: containerRefManager,
+	}
+
+	// Test the rkt version.
+	version, err := rkt.Version()
+	if err != nil {
+		return nil, err
+	}
+	glog.Infof("Using rkt version %v", version)
+	return rkt, nil
+}
+
+func (r *runtime) buildCommand(args ...string) *exec.Cmd {
+	cmd := exec.Command(r.absPath)
+	cmd.Args = append(cmd.Args, r.config.buildGlobalOptions()...)
+	cmd.Args = append(cmd.Args, args...)
+	return cmd
+}
+
+// runCommand invokes rkt binary with arguments and returns the result
+// from stdout in a list of strings.
+func (r *runtime) runCommand(args ...string) ([]string, error) {
+	glog.V(4).Info("rkt: Run command:", args)
+
+	output, err := r.buildCommand(args...).Output()
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(strings.TrimSpace(string(output)), "\n"), nil
+}
+
+// makePodServiceFileName constructs the unit file name for a pod using its UID.
+func makePodServiceFileName(uid types.UID) string {
+	// TODO(yifan): Revisit this later, decide whether we want to use UID.
+	return fmt.Sprintf("%s%s.service", kubernetesUnitPrefix, uid)
+}
+
+// buildContainerID constructs the containers's ID using its rkt pod UUID and
+// the app name, in the format "rkt://<uuid>:<app-name>".
+func buildContainerID(id string) string {
+	return fmt.Sprintf("rkt://%s", id)
+}
+
+// makeContainerID returns the runtime independent part of a container ID.
+func makeContainerID(uuid, appName string) types.UID {
+	return types.UID(fmt.Sprintf("%s:%s", uuid, appName))
+}
+
+// parseContainerID parses the containerID into pod UUID and the app name.
+func parseContainerID(id string) (uuid, appName string, err error) {
+	parts := strings.SplitN(kubecontainer.TrimRuntimePrefix(id), ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid rkt container ID %q", id)
+	}
+	return parts[0], parts[1], nil
+}
+
+// hashContainer returns the hash of the container, which is used to detect
+// changes of the spec.
+func hashContainer(container *api.Container) uint64 {
+	hash := adler32.New()
+	util.DeepHashObject(hash, *container)
+	return uint64(hash.Sum32())
+}
+
+// Version invokes 'rkt version' to get the version information of the rkt
+// runtime on the machine.
+// The return values are a map of component:version.
+//
+// Example:
+// rkt:0.3.2+git
+// appc:0.3.0+git
+func (r *runtime) Version() (map[string]string, error) {
+	output, err := r.runCommand("version")
+	if err != nil {
+		return nil, err
+	}
+	return parseVersion(output)
+}
+
+// parseVersion parses the output of 'rkt version', which looks like:
+// rkt version 0.5.4
+// appc version 0.5.1
+func parseVersion(output []string) (map[string]string, error) {
+	result := make(map[string]string)
+	for _, line := range output {
+		fields := strings.Fields(line)
+		if len(fields) != 3 || fields[1] != "version" {
+			continue
+		}
+		result[fields[0]] = fields[2]
+	}
+	if _, ok := result[rktBinName]; !ok {
+		return nil, fmt.Errorf("rkt: cannot parse version from %q", output)
+	}
+	return result, nil
+}
+
+// The following types mirror the subset of the appc pod manifest schema
+// (https://github.com/appc/spec) that is needed to run a kubernetes pod.
+type podManifest struct {
+	ACKind    string       `json:"acKind"`
+	ACVersion string       `json:"acVersion"`
+	Apps      []runtimeApp `json:"apps"`
+	Volumes   []volume     `json:"volumes,omitempty"`
+}
+
+type runtimeApp struct {
+	Name   string       `json:"name"`
+	Image  runtimeImage `json:"image"`
+	App    *app         `json:"app,omitempty"`
+	Mounts []mount      `json:"mounts,omitempty"`
+}
+
+type runtimeImage struct {
+	Name string `json:"name,omitempty"`
+	ID   string `json:"id"`
+}
+
+type imageManifest struct {
+	Name string `json:"name"`
+	App  *app   `json:"app,omitempty"`
+}
+
+type app struct {
+	Exec             []string      `json:"exec"`
+	User             string        `json:"user"`
+	Group            string        `json:"group"`
+	WorkingDirectory string        `json:"workingDirectory,omitempty"`
+	Environment      []environment `json:"environment,omitempty"`
+	MountPoints      []mountPoint  `json:"mountPoints,omitempty"`
+	Ports            []port        `json:"ports,omitempty"`
+}
+
+type environment struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type mountPoint struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	ReadOnly bool   `json:"readOnly,omitempty"`
+}
+
+type port struct {
+	Name     string `json:"name"`
+	Protocol string `json:"protocol"`
+	Port     int    `json:"port"`
+}
+
+type mount struct {
+	Volume     string `json:"volume"`
+	MountPoint string `json:"mountPoint"`
+}
+
+type volume struct {
+	Name     string `json:"name"`
+	Kind     string `json:"kind"`
+	Source   string `json:"source,omitempty"`
+	ReadOnly *bool  `json:"readOnly,omitempty"`
+}
+
+// getImageManifest invokes 'rkt image cat-manifest' to retrive the image manifest
+// for the image.
+func (r *runtime) getImageManifest(imageID string) (*imageManifest, error) {
+	output, err := r.buildCommand("image", "cat-manifest", imageID).Output()
+	if err != nil {
+		return nil, err
+	}
+	var manifest imageManifest
+	if err := json.Unmarshal(output, &manifest); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// setApp overrides the app's fields if any of them are specified in the
+// container's spec.
+func setApp(a *app, c *api.Container, opts *kubecontainer.RunContainerOptions) error {
+	// Override the exec.
+	// TOOD(yifan): Revisit this for the overriding rule.
+	if len(c.Command) > 0 {
+		a.Exec = c.Command
+	}
+	if len(a.Exec) == 0 {
+		return fmt.Errorf("no command specified for container %q", c.Name)
+	}
+
+	// TODO(yifan): Use non-root user in the future?
+	// Currently it's a bug as reported https://github.com/coreos/rkt/issues/539.
+	// However since we cannot get the user/group information from the container
+	// spec, maybe we will need to resolve this in the future.
+	a.User = "0"
+	a.Group = "0"
+
+	// Override the working directory.
+	if len(c.WorkingDir) > 0 {
+		a.WorkingDirectory = c.WorkingDir
+	}
+
+	// Override the environment.
+	// TODO(yifan): Use RunContainerOptions.
+	for _, env := range opts.Envs {
+		parts := strings.SplitN(env, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		a.Environment = append(a.Environment, environment{Name: parts[0], Value: parts[1]})
+	}
+
+	// Override the mount points.
+	a.MountPoints = nil
+	for _, m := range opts.Mounts {
+		a.MountPoints = append(a.MountPoints, mountPoint{
+			Name:     m.Name,
+			Path:     m.ContainerPath,
+			ReadOnly: m.ReadOnly,
+		})
+	}
+
+	// Override the ports.
+	a.Ports = nil
+	for _, p := range c.Ports {
+		name := p.Name
+		if name == "" {
+			name = fmt.Sprintf("%s-%d", strings.ToLower(string(p.Protocol)), p.ContainerPort)
+		}
+		a.Ports = append(a.Ports, port{
+			Name:     name,
+			Protocol: strings.ToLower(string(p.Protocol)),
+			Port:     p.ContainerPort,
+		})
+	}
+	return nil
+}
+
+// makePodManifest transforms a kubelet pod spec to the rkt pod manifest.
+// TODO(yifan): Use the RunContainerOptionsGenerator for all the fields.
+func (r *runtime) makePodManifest(pod *api.Pod) (*podManifest, error) {
+	manifest := &podManifest{ACKind: "PodManifest", ACVersion: acVersion}
+	volumes := make(map[string]volume)
+
+	for i := range pod.Spec.Containers {
+		c := &pod.Spec.Containers[i]
+		imageID, err := r.getImageID(c.Image)
+		if err != nil {
+			return nil, err
+		}
+		img, err := r.getImageManifest(imageID)
+		if err != nil {
+			return nil, err
+		}
+		if img.App == nil {
+			img.App = &app{}
+		}
+
+		opts, err := r.generator.GenerateRunContainerOptions(pod, c)
+		if err != nil {
+			return nil, err
+		}
+		if err := setApp(img.App, c, opts); err != nil {
+			return nil, err
+		}
+
+		ra := runtimeApp{
+			Name:  c.Name,
+			Image: runtimeImage{Name: img.Name, ID: imageID},
+			App:   img.App,
+		}
+		for _, m := range opts.Mounts {
+			readOnly := m.ReadOnly
+			volumes[m.Name] = volume{
+				Name:     m.Name,
+				Kind:     "host",
+				Source:   m.HostPath,
+				ReadOnly: &readOnly,
+			}
+			ra.Mounts = append(ra.Mounts, mount{Volume: m.Name, MountPoint: m.Name})
+		}
+		manifest.Apps = append(manifest.Apps, ra)
+	}
+
+	for _, v := range volumes {
+		manifest.Volumes = append(manifest.Volumes, v)
+	}
+	return manifest, nil
+}
+
+// apiPodToruntimePod converts an api.Pod to kubelet/container.Pod.
+// we save the this for later reconstruction.
+func apiPodToruntimePod(uuid string, pod *api.Pod, created int64) *kubecontainer.Pod {
+	p := &kubecontainer.Pod{
+		ID:        pod.UID,
+		Name:      pod.Name,
+		Namespace: pod.Namespace,
+	}
+	for i := range pod.Spec.Containers {
+		c := &pod.Spec.Containers[i]
+		p.Containers = append(p.Containers, &kubecontainer.Container{
+			ID:      makeContainerID(uuid, c.Name),
+			Name:    c.Name,
+			Image:   c.Image,
+			Hash:    hashContainer(c),
+			Created: created,
+		})
+	}
+	return p
+}
+
+// preparePod will:
+//
+// 1. Invoke 'rkt prepare' to prepare the pod, and get the rkt pod uuid.
+// 2. Creates the unit file and save it under systemdUnitDir.
+//
+// On success, it will return a string that represents name of the unit file
+// and a boolean that indicates if the unit file needs to be reloaded (whether
+// the file is already existed).
+func (r *runtime) preparePod(pod *api.Pod) (string, bool, error) {
+	// Generate the pod manifest from the pod spec.
+	manifest, err := r.makePodManifest(pod)
+	if err != nil {
+		return "", false, err
+	}
+	manifestFile, err := ioutil.TempFile("", "manifest")
+	if err != nil {
+		return "", false, err
+	}
+	defer func() {
+		manifestFile.Close()
+		if err := os.Remove(manifestFile.Name()); err != nil {
+			glog.Warningf("rkt: Cannot remove temp manifest file %q: %v", manifestFile.Name(), err)
+		}
+	}()
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return "", false, err
+	}
+	// Since File.Write returns error if the written length is less than len(data),
+	// so check error is enough for us.
+	if _, err := manifestFile.Write(data); err != nil {
+		return "", false, err
+	}
+
+	cmds := []string{"prepare", "--quiet", "--pod-manifest", manifestFile.Name()}
+	output, err := r.runCommand(cmds...)
+	if err != nil {
+		return "", false, err
+	}
+	if len(output) != 1 {
+		return "", false, fmt.Errorf("cannot get uuid from 'rkt prepare'")
+	}
+	uuid := output[0]
+	glog.V(4).Infof("'rkt prepare' returns %q.", uuid)
+
+	p, err := json.Marshal(pod)
+	if err != nil {
+		return "", false, err
+	}
+
+	runPrepared := r.buildCommand("run-prepared", uuid).Args
+	units := []*unitOption{
+		{Section: "Unit", Name: "Description", Value: fmt.Sprintf("Kubernetes pod %s", kubecontainer.GetPodFullName(pod))},
+		{Section: "Service", Name: "ExecStart", Value: strings.Join(runPrepared, " ")},
+		{Section: "Service", Name: "KillMode", Value: "mixed"},
+		// This makes the pod spec and the rkt UUID available to GetPods()
+		// and GetPodStatus().
+		{Section: unitKubernetesSection, Name: unitPodName, Value: string(p)},
+		{Section: unitKubernetesSection, Name: unitRktID, Value: uuid},
+	}
+	// systemd sends SIGTERM on stop, and SIGKILL once the timeout expires.
+	gracePeriod := int64(api.DefaultTerminationGracePeriodSeconds)
+	if pod.Spec.TerminationGracePeriodSeconds != nil {
+		gracePeriod = *pod.Spec.TerminationGracePeriodSeconds
+	}
+	units = append(units, &unitOption{Section: "Service", Name: "TimeoutStopSec", Value: strconv.FormatInt(gracePeriod, 10)})
+
+	serviceName := makePodServiceFileName(pod.UID)
+	glog.V(4).Infof("rkt: Creating service file %q for pod %q", serviceName, kubecontainer.GetPodFullName(pod))
+	serviceFile, needReload, err := openServiceFile(serviceName)
+	if err != nil {
+		return "", false, err
+	}
+	defer serviceFile.Close()
+
+	if _, err := io.WriteString(serviceFile, serializeUnit(units)); err != nil {
+		return "", false, err
+	}
+	return serviceName, needReload, nil
+}
+
+// openServiceFile truncates or creates the unit file, and reports whether the
+// file already existed, in which case systemd needs to reload it.
+func openServiceFile(serviceName string) (*os.File, bool, error) {
+	unitPath := path.Join(systemdServiceDir, serviceName)
+	needReload := false
+	if _, err := os.Stat(unitPath); err == nil {
+		needReload = true
+	}
+	file, err := os.Create(unitPath)
+	if err != nil {
+		return nil, false, err
+	}
+	return file, needReload, nil
+}
+
+// RunPod first creates the unit file for a pod, and then calls
+// StartUnit over d-bus.
+func (r *runtime) RunPod(pod *api.Pod) error {
+	glog.V(4).Infof("Rkt starts to run pod: name %q.", kubecontainer.GetPodFullName(pod))
+
+	name, needReload, err := r.preparePod(pod)
+	if err != nil {
+		return err
+	}
+	if needReload {
+		if err := r.systemd.Reload(); err != nil {
+			return err
+		}
+	}
+
+	// TODO(yifan): This is the old version of go-systemd. Should update when libcontainer updates
+	// its version of go-systemd.
+	if _, err := r.systemd.StartUnit(name, "replace"); err != nil {
+		return err
+	}
+
+	runningPod, _, err := r.readServiceFile(name)
+	if err != nil {
+		return err
+	}
+	for i := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[i]
+		ref, err := kubecontainer.GenerateContainerRef(pod, container)
+		if err != nil {
+			glog.Errorf("Couldn't make a ref to pod %q, container %v: '%v'", pod.Name, container.Name, err)
+			continue
+		}
+		c := runningPod.FindContainerByName(container.Name)
+		if c == nil {
+			continue
+		}
+		r.containerRefManager.SetRef(string(c.ID), ref)
+		r.recorder.Eventf(ref, "started", "Started with rkt id %v", c.ID)
+	}
+	return nil
+}
+
+// readServiceFile reads the unit file of a pod, and returns the pod that
+// the unit runs together with its rkt UUID.
+func (r *runtime) readServiceFile(serviceName string) (*kubecontainer.Pod, string, error) {
+	unitPath := path.Join(systemdServiceDir, serviceName)
+	f, err := os.Open(unitPath)
+	if err != nil {
+		return nil, "", err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, "", err
+	}
+	opts, err := parseUnit(f)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var pod api.Pod
+	var uuid string
+	var podFound bool
+	for _, opt := range opts {
+		if opt.Section != unitKubernetesSection {
+			continue
+		}
+		switch opt.Name {
+		case unitPodName:
+			if err := json.Unmarshal([]byte(opt.Value), &pod); err != nil {
+				return nil, "", err
+			}
+			podFound = true
+		case unitRktID:
+			uuid = opt.Value
+		}
+	}
+	if !podFound || uuid == "" {
+		return nil, "", fmt.Errorf("rkt: cannot find the pod or the rkt UUID in %q", unitPath)
+	}
+	return apiPodToruntimePod(uuid, &pod, info.ModTime().Unix()), uuid, nil
+}
+
+// GetPods runs 'systemctl list-unit' and 'rkt list' to get the list of rkt pods.
+// Then it will use the result to contruct list of pods.
+// If all is true, all the pods including the exited ones are returned, otherwise
+// only the active ones are returned.
+func (r *runtime) GetPods(all bool) ([]*kubecontainer.Pod, error) {
+	glog.V(4).Infof("Rkt getting pods")
+
+	units, err := r.systemd.ListUnits()
+	if err != nil {
+		return nil, err
+	}
+
+	var pods []*kubecontainer.Pod
+	for _, u := range units {
+		if !strings.HasPrefix(u.Name, kubernetesUnitPrefix) || !strings.HasSuffix(u.Name, ".service") {
+			continue
+		}
+		if !all && u.ActiveState != "active" {
+			continue
+		}
+		pod, _, err := r.readServiceFile(u.Name)
+		if err != nil {
+			glog.Warningf("rkt: Cannot read the service file %q: %v", u.Name, err)
+			continue
+		}
+		pods = append(pods, pod)
+	}
+	return pods, nil
+}
+
+// KillPod invokes 'systemctl stop' to stop the unit that runs the pod. systemd
+// sends SIGTERM and escalates to SIGKILL once the unit's TimeoutStopSec, set from
+// the pod spec, expires. If the pod is known and its deletion grace period is
+// shorter, the unit is killed once that period has elapsed. preStop hooks are
+// not supported.
+// TODO(yifan): Run 'rkt gc' to remove the stopped pods.
+func (r *runtime) KillPod(pod *api.Pod, runningPod kubecontainer.Pod) error {
+	glog.V(4).Infof("Rkt is killing pod: name %q.", kubecontainer.BuildPodFullName(runningPod.Name, runningPod.Namespace))
+
+	serviceName := makePodServiceFileName(runningPod.ID)
+	for _, c := range runningPod.Containers {
+		id := string(c.ID)
+		r.readinessManager.RemoveReadiness(id)
+		if ref, ok := r.containerRefManager.GetRef(id); ok {
+			r.recorder.Eventf(ref, "killing", "Killing %v", id)
+			r.containerRefManager.ClearRef(id)
+		}
+	}
+
+	stopped := make(chan error, 1)
+	go func() {
+		_, err := r.systemd.StopUnit(serviceName, "replace")
+		stopped <- err
+	}()
+	var timeout <-chan time.Time
+	if pod != nil && pod.DeletionGracePeriodSeconds != nil {
+		timeout = time.After(time.Duration(*pod.DeletionGracePeriodSeconds) * time.Second)
+	}
+	select {
+	case err := <-stopped:
+		if err != nil {
+			return err
+		}
+	case <-timeout:
+		glog.V(2).Infof("Rkt pod %q did not stop within its grace period, killing it", serviceName)
+		r.systemd.KillUnit(serviceName, int32(syscall.SIGKILL))
+		if err := <-stopped; err != nil {
+			return err
+		}
+	}
+	if err := os.Remove(path.Join(systemdServiceDir, serviceName)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return r.systemd.Reload()
+}
+
+// GetPodStatus currently invokes GetPods() to return the status.
+// TODO(yifan): Split the get status logic from GetPods().
+func (r *runtime) GetPodStatus(pod *api.Pod) (*api.PodStatus, error) {
+	runningPod, uuid, err := r.readServiceFile(makePodServiceFileName(pod.UID))
+	if os.IsNotExist(err) {
+		return nil, kubecontainer.ErrNoContainersInPod
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := r.runCommand("status", uuid)
+	if err != nil {
+		return nil, err
+	}
+	info := newPodInfo()
+	if err := info.parseStatus(output); err != nil {
+		return nil, err
+	}
+	status := info.toPodStatus(runningPod)
+	return &status, nil
+}
+
+// getImageID tries to fetch the image and returns the image id, which is
+// the hash printed by 'rkt fetch'.
+func (r *runtime) getImageID(image string) (string, error) {
+	output, err := r.runCommand("fetch", dockerPrefix+image)
+	if err != nil {
+		return "", err
+	}
+	last := output[len(output)-1]
+	if !strings.HasPrefix(last, "sha512-") {
+		return "", fmt.Errorf("rkt: cannot get image id for %q from %q", image, output)
+	}
+	return last, nil
+}
+
+// PullImage invokes 'rkt fetch' to download an aci.
+// TODO(yifan): Now we only support docker images, this should be changed
+// once the format of image is landed, see:
+//
+// https://github.com/GoogleCloudPlatform/kubernetes/issues/7203
+func (r *runtime) PullImage(image string, pullSecrets []api.Secret) error {
+	img := dockerPrefix + image
+	glog.V(4).Infof("Rkt pulling image %s.", image)
+
+	// TODO(yifan): Set the docker credentials in rkt's local config
+	// directory, including those of pullSecrets.
+	if _, err := r.runCommand("fetch", img); err != nil {
+		glog.Errorf("Failed to fetch: %v", err)
+		return err
+	}
+	return nil
+}
+
+// IsImagePresent returns true if the image is available on the machine.
+// TODO(yifan): 'rkt images' is too slow, we can read the aci store
+// directly once it's stable.
+func (r *runtime) IsImagePresent(image string) (bool, error) {
+	output, err := r.runCommand("images", "--no-legend")
+	if err != nil {
+		return false, err
+	}
+	repo := strings.SplitN(image, ":", 2)[0]
+	for _, line := range output {
+		for _, field := range strings.Fields(line) {
+			if field == image || field == repo {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// SyncPod syncs the running pod to match the specified desired pod.
+// Since the apps in a rkt pod cannot be restarted individually, any change
+// to the containers, or a failed liveness probe, restarts the whole pod.
+func (r *runtime) SyncPod(pod *api.Pod, runningPod kubecontainer.Pod, podStatus api.PodStatus, pullSecrets kubecontainer.PullSecretsGetter) error {
+	podFullName := kubecontainer.GetPodFullName(pod)
+	if len(runningPod.Containers) == 0 {
+		if !shouldRestartPod(pod, podStatus) {
+			glog.V(4).Infof("Pod %q has exited, not restarting it per its restart policy", podFullName)
+			return nil
+		}
+		glog.V(4).Infof("Pod %q is not running, will start it", podFullName)
+		// TODO(yifan): Use RunContainerOptionsGenerator to get volumes, etc.
+		return r.RunPod(pod)
+	}
+
+	// Add references to all containers.
+	unidentifiedContainers := make(map[types.UID]*kubecontainer.Container)
+	for _, c := range runningPod.Containers {
+		unidentifiedContainers[c.ID] = c
+	}
+
+	restartPod := false
+	for _, container := range pod.Spec.Containers {
+		expectedHash := hashContainer(&container)
+
+		c := runningPod.FindContainerByName(container.Name)
+		if c == nil {
+			glog.V(3).Infof("Container %q for pod %q not found, restarting the pod", container.Name, podFullName)
+			restartPod = true
+			break
+		}
+
+		containerChanged := c.Hash != 0 && c.Hash != expectedHash
+		if containerChanged {
+			glog.V(3).Infof("Pod %q container %q hash changed (%d vs %d), it will be killed and re-created.", podFullName, container.Name, c.Hash, expectedHash)
+			restartPod = true
+			break
+		}
+
+		result, err := r.prober.Probe(pod, podStatus, container, string(c.ID), c.Created)
+		// TODO(vmarmol): examine this logic.
+		if err == nil && result != probe.Success {
+			glog.V(3).Infof("Pod %q container %q is unhealthy (probe result: %v), it will be killed and re-created.", podFullName, container.Name, result)
+			restartPod = true
+			break
+		}
+		if err != nil {
+			glog.V(2).Infof("Probe container %q failed: %v", container.Name, err)
+		}
+		delete(unidentifiedContainers, c.ID)
+	}
+
+	// If there is any unidentified containers, restart the pod.
+	if len(unidentifiedContainers) > 0 {
+		restartPod = true
+	}
+
+	if restartPod {
+		// TODO(yifan): Handle restart policy.
+		if err := r.KillPod(pod, runningPod); err != nil {
+			return err
+		}
+		if err := r.RunPod(pod); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// shouldRestartPod returns whether a pod that is not running should be
+// (re)started, according to its restart policy and its last status.
+func shouldRestartPod(pod *api.Pod, podStatus api.PodStatus) bool {
+	switch pod.Spec.RestartPolicy {
+	case api.RestartPolicyNever, api.RestartPolicyOnFailure:
+	default:
+		return true
+	}
+
+	ran := false
+	for _, container := range pod.Spec.Containers {
+		status, ok := podStatus.Info[container.Name]
+		if !ok || status.State.Termination == nil {
+			continue
+		}
+		ran = true
+		if pod.Spec.RestartPolicy == api.RestartPolicyOnFailure && status.State.Termination.ExitCode != 0 {
+			return true
+		}
+	}
+	return !ran
+}
+
+// GetContainerLogs uses journalctl to get the logs of the container.
+// By default, it returns a snapshot of the container log. Set |opts.Follow| to true to
+// stream the log. Set |opts.Follow| to false and specify the number of lines (e.g.
+// "100" or "all") in |opts.Tail| to tail the log.
+// TODO(yifan): Currently, it fetches all the containers' log within a pod. We will
+// be able to fetch individual container's log once https://github.com/coreos/rkt/pull/841
+// landed.
+func (r *runtime) GetContainerLogs(containerID string, opts *api.PodLogOptions, stdout, stderr io.Writer) error {
+	uuid, appName, err := parseContainerID(containerID)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command("journalctl", "-M", fmt.Sprintf("rkt-%s", uuid), "-u", fmt.Sprintf("%s.service", appName))
+	if opts.Follow {
+		cmd.Args = append(cmd.Args, "-f")
+	}
+	if opts.Tail == "all" {
+		cmd.Args = append(cmd.Args, "-a")
+	} else if opts.Tail != "" {
+		cmd.Args = append(cmd.Args, "-n", opts.Tail)
+	}
+	if opts.SinceTime != nil {
+		cmd.Args = append(cmd.Args, "--since", opts.SinceTime.Local().Format("2006-01-02 15:04:05"))
+	}
+	if opts.ShowTimestamps() {
+		cmd.Args = append(cmd.Args, "-o", "short-iso")
+	} else {
+		cmd.Args = append(cmd.Args, "-o", "cat")
+	}
+
+	stdout, stderr = kubecontainer.LimitLogs(opts.LimitBytes, stdout, stderr)
+	cmd.Stderr = stderr
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	if _, err := io.Copy(stdout, out); err != nil {
+		// journalctl has no limit of bytes of its own, stop it once the
+		// limit is reached.
+		cmd.Process.Kill()
+		cmd.Wait()
+		if err == kubecontainer.ErrLogLimitReached {
+			return nil
+		}
+		return err
+	}
+	return cmd.Wait()
+}
+
+// RunInContainer runs the command in the app's context using 'rkt enter',
+// and returns the combined output.
+func (r *runtime) RunInContainer(containerID string, cmd []string) ([]byte, error) {
+	glog.V(4).Infof("Rkt running in container.")
+
+	uuid, appName, err := parseContainerID(containerID)
+	if err != nil {
+		return nil, err
+	}
+	args := append([]string{"enter", fmt.Sprintf("--app=%s", appName), uuid}, cmd...)
+	return r.buildCommand(args...).CombinedOutput()
+}
+
+// ExecInContainer runs the command in the app's context using 'rkt enter',
+// wiring up the given streams.
+// TODO(yifan): Support tty.
+func (r *runtime) ExecInContainer(containerID string, cmd []string, stdin io.Reader, stdout, stderr io.WriteCloser, tty bool) error {
+	glog.V(4).Infof("Rkt execing in container.")
+
+	if tty {
+		return fmt.Errorf("rkt: tty is not supported yet")
+	}
+	uuid, appName, err := parseContainerID(containerID)
+	if err != nil {
+		return err
+	}
+	args := append([]string{"enter", fmt.Sprintf("--app=%s", appName), uuid}, cmd...)
+	command := r.buildCommand(args...)
+	command.Stdin = stdin
+	command.Stdout, command.Stderr = stdout, stderr
+	defer func() {
+		if stdout != nil {
+			stdout.Close()
+		}
+		if stderr != nil {
+			stderr.Close()
+		}
+	}()
+	return command.Run()
+}
+
+// PortForward executes socat in the pod's network namespace and copies
+// data between stream (representing the user's local connection on their
+// computer) and the specified port in the container.
+//
+// TODO:
+//   - match cgroups of container
+//   - should we support nsenter + socat on the host? (current impl)
+//   - should we support nsenter + socat in a container, running with elevated privs and --pid=host?
+func (r *runtime) PortForward(pod *kubecontainer.Pod, port uint16, stream io.ReadWriteCloser) error {
+	glog.V(4).Infof("Rkt port forwarding in container.")
+
+	serviceName := makePodServiceFileName(pod.ID)
+	prop, err := r.systemd.GetUnitTypeProperty(serviceName, "Service", "MainPID")
+	if err != nil {
+		return err
+	}
+	pid, ok := prop.Value.Value().(uint32)
+	if !ok || pid == 0 {
+		return fmt.Errorf("rkt: cannot find the main pid of %q", serviceName)
+	}
+
+	// TODO use exec.LookPath for socat / what if the host doesn't have it???
+	args := []string{"-t", fmt.Sprintf("%d", pid), "-n", "socat", "-", fmt.Sprintf("TCP4:localhost:%d", port)}
+	// TODO use exec.LookPath
+	command := exec.Command("nsenter", args...)
+	in, err := command.StdinPipe()
+	if err != nil {
+		return err
+	}
+	out, err := command.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	go io.Copy(in, stream)
+	go io.Copy(stream, out)
+	return command.Run()
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rkt
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	kubecontainer "github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/container"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		output   []string
+		expected map[string]string
+		err      bool
+	}{
+		{
+			output:   []string{"rkt version 0.5.4", "appc version 0.5.1"},
+			expected: map[string]string{"rkt": "0.5.4", "appc": "0.5.1"},
+		},
+		{
+			output:   []string{"rkt version 0.5.4+git"},
+			expected: map[string]string{"rkt": "0.5.4+git"},
+		},
+		{
+			output: []string{"appc version 0.5.1"},
+			err:    true,
+		},
+		{
+			output: []string{""},
+			err:    true,
+		},
+	}
+
+	for i, test := range tests {
+		version, err := parseVersion(test.output)
+		if test.err {
+			if err == nil {
+				t.Errorf("%d: expected error, got %v", i, version)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%d: unexpected error: %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(version, test.expected) {
+			t.Errorf("%d: expected %v, got %v", i, test.expected, version)
+		}
+	}
+}
+
+func TestBuildGlobalOptions(t *testing.T) {
+	tests := []struct {
+		config   *Config
+		expected []string
+	}{
+		{
+			config:   nil,
+			expected: nil,
+		},
+		{
+			// Images are verified unless the kubelet is told otherwise.
+			config:   &Config{},
+			expected: []string{"--debug=false", "--insecure-skip-verify=false"},
+		},
+		{
+			config:   &Config{Debug: true, InsecureSkipVerify: true, LocalConfigDir: "/etc/rkt", Dir: "/var/lib/rkt"},
+			expected: []string{"--debug=true", "--insecure-skip-verify=true", "--local-config=/etc/rkt", "--dir=/var/lib/rkt"},
+		},
+	}
+
+	for i, test := range tests {
+		if options := test.config.buildGlobalOptions(); !reflect.DeepEqual(options, test.expected) {
+			t.Errorf("%d: expected %v, got %v", i, test.expected, options)
+		}
+	}
+}
+
+func TestParseContainerID(t *testing.T) {
+	tests := []struct {
+		id      string
+		uuid    string
+		appName string
+		err     bool
+	}{
+		{id: "rkt://abcd-1234:foo", uuid: "abcd-1234", appName: "foo"},
+		{id: "abcd-1234:foo", uuid: "abcd-1234", appName: "foo"},
+		{id: "rkt://abcd-1234", err: true},
+		{id: "rkt://:foo", err: true},
+		{id: "", err: true},
+	}
+
+	for _, test := range tests {
+		uuid, appName, err := parseContainerID(test.id)
+		if test.err {
+			if err == nil {
+				t.Errorf("%q: expected error", test.id)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.id, err)
+			continue
+		}
+		if uuid != test.uuid || appName != test.appName {
+			t.Errorf("%q: expected (%q, %q), got (%q, %q)", test.id, test.uuid, test.appName, uuid, appName)
+		}
+	}
+
+	id := buildContainerID(string(makeContainerID("abcd-1234", "foo")))
+	if id != "rkt://abcd-1234:foo" {
+		t.Errorf("unexpected container ID %q", id)
+	}
+}
+
+func TestPodInfoToPodStatus(t *testing.T) {
+	pod := &kubecontainer.Pod{
+		Containers: []*kubecontainer.Container{
+			{ID: makeContainerID("abcd-1234", "foo"), Name: "foo", Image: "busybox"},
+			{ID: makeContainerID("abcd-1234", "bar"), Name: "bar", Image: "nginx"},
+		},
+	}
+
+	info := newPodInfo()
+	err := info.parseStatus([]string{
+		"state=running",
+		"pid=42",
+		"networks=default:ip4=172.16.28.3",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	status := info.toPodStatus(pod)
+	if status.PodIP != "172.16.28.3" {
+		t.Errorf("unexpected pod IP %q", status.PodIP)
+	}
+	for _, c := range pod.Containers {
+		containerStatus, ok := status.Info[c.Name]
+		if !ok {
+			t.Errorf("missing status for container %q", c.Name)
+			continue
+		}
+		if containerStatus.State.Running == nil {
+			t.Errorf("expected container %q to be running, got %#v", c.Name, containerStatus.State)
+		}
+		if containerStatus.Image != c.Image {
+			t.Errorf("expected image %q, got %q", c.Image, containerStatus.Image)
+		}
+		if containerStatus.ContainerID != buildContainerID(string(c.ID)) {
+			t.Errorf("unexpected container ID %q", containerStatus.ContainerID)
+		}
+	}
+
+	info = newPodInfo()
+	err = info.parseStatus([]string{"state=exited", "pid=-1", "app-foo=0", "app-bar=3"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	status = info.toPodStatus(pod)
+	if term := status.Info["foo"].State.Termination; term == nil || term.ExitCode != 0 {
+		t.Errorf("unexpected state for foo: %#v", status.Info["foo"].State)
+	}
+	if term := status.Info["bar"].State.Termination; term == nil || term.ExitCode != 3 {
+		t.Errorf("unexpected state for bar: %#v", status.Info["bar"].State)
+	}
+
+	if err := newPodInfo().parseStatus([]string{"pid=abc"}); err == nil {
+		t.Errorf("expected error for invalid pid")
+	}
+}
+
+func TestSerializeAndParseUnit(t *testing.T) {
+	opts := []*unitOption{
+		{Section: "Unit", Name: "Description", Value: "Kubernetes pod foo_default"},
+		{Section: "Service", Name: "ExecStart", Value: "/usr/bin/rkt run-prepared abcd-1234"},
+		{Section: unitKubernetesSection, Name: unitPodName, Value: `{"metadata":{"name":"foo"}}`},
+		{Section: "Service", Name: "KillMode", Value: "mixed"},
+		{Section: unitKubernetesSection, Name: unitRktID, Value: "abcd-1234"},
+	}
+
+	unit := serializeUnit(opts)
+	if strings.Count(unit, "[Service]") != 1 {
+		t.Errorf("expected sections to be grouped, got:\n%s", unit)
+	}
+
+	parsed, err := parseUnit(strings.NewReader(unit))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []*unitOption{opts[0], opts[1], opts[3], opts[2], opts[4]}
+	if !reflect.DeepEqual(parsed, expected) {
+		t.Errorf("expected %v, got %v", expected, parsed)
+	}
+
+	if _, err := parseUnit(strings.NewReader("Name=value\n")); err == nil {
+		t.Errorf("expected error for an option outside of a section")
+	}
+}
+
+func TestShouldRestartPod(t *testing.T) {
+	exited := func(exitCode int) api.ContainerStatus {
+		return api.ContainerStatus{
+			State: api.ContainerState{
+				Termination: &api.ContainerStateTerminated{ExitCode: exitCode},
+			},
+		}
+	}
+	tests := []struct {
+		policy   api.RestartPolicy
+		status   api.PodStatus
+		expected bool
+	}{
+		{api.RestartPolicyAlways, api.PodStatus{Info: api.PodInfo{"foo": exited(0)}}, true},
+		{api.RestartPolicyNever, api.PodStatus{}, true},
+		{api.RestartPolicyNever, api.PodStatus{Info: api.PodInfo{"foo": exited(1)}}, false},
+		{api.RestartPolicyOnFailure, api.PodStatus{Info: api.PodInfo{"foo": exited(0)}}, false},
+		{api.RestartPolicyOnFailure, api.PodStatus{Info: api.PodInfo{"foo": exited(1)}}, true},
+	}
+
+	for i, test := range tests {
+		pod := &api.Pod{
+			Spec: api.PodSpec{
+				RestartPolicy: test.policy,
+				Containers:    []api.Container{{Name: "foo"}},
+			},
+		}
+		if restart := shouldRestartPod(pod, test.status); restart != test.expected {
+			t.Errorf("%d: expected %v, got %v", i, test.expected, restart)
+		}
+	}
+}
@@ -22,7 +22,6 @@ import (
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/container"
-	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/dockertools"
 	"github.com/golang/glog"
 )
 
@@ -53,9 +52,6 @@ func (kl *Kubelet) RunOnce(updates <-chan PodUpdate) ([]RunPodResult, error) {
 
 // runOnce runs a given set of pods and returns their status.
 func (kl *Kubelet) runOnce(pods []api.Pod, retryDelay time.Duration) (results []RunPodResult, err error) {
-	if kl.dockerPuller == nil {
-		kl.dockerPuller = dockertools.NewDockerPuller(kl.dockerClient, kl.pullQPS, kl.pullBurst)
-	}
 	kl.handleNotFittingPods(pods)
 
 	ch := make(chan RunPodResult)
@@ -92,7 +88,7 @@ func (kl *Kubelet) runPod(pod api.Pod, retryDelay time.Duration) error {
 	delay := retryDelay
 	retry := 0
 	for {
-		pods, err := dockertools.GetPods(kl.dockerClient, false)
+		pods, err := kl.containerRuntime.GetPods(false)
 		if err != nil {
 			return fmt.Errorf("failed to get kubelet pods: %v", err)
 		}
@@ -125,18 +121,20 @@ func (kl *Kubelet) runPod(pod api.Pod, retryDelay time.Duration) error {
 // isPodRunning returns true if all containers of a manifest are running.
 func (kl *Kubelet) isPodRunning(pod api.Pod, runningPod container.Pod) (bool, error) {
 	for _, container := range pod.Spec.Containers {
-		c := runningPod.FindContainerByName(container.Name)
-		if c == nil {
+		if c := runningPod.FindContainerByName(container.Name); c == nil {
 			glog.Infof("container %q not found", container.Name)
 			return false, nil
 		}
-		inspectResult, err := kl.dockerClient.InspectContainer(string(c.ID))
-		if err != nil {
-			glog.Infof("failed to inspect container %q: %v", container.Name, err)
-			return false, err
-		}
-		if !inspectResult.State.Running {
-			glog.Infof("container %q not running: %#v", container.Name, inspectResult.State)
+	}
+	status, err := kl.containerRuntime.GetPodStatus(&pod)
+	if err != nil {
+		glog.Infof("failed to get the status of pod %q: %v", container.GetPodFullName(&pod), err)
+		return false, err
+	}
+	for _, container := range pod.Spec.Containers {
+		containerStatus := status.Info[container.Name]
+		if containerStatus.State.Running == nil {
+			glog.Infof("container %q not running: %#v", container.Name, containerStatus.State)
 			return false, nil
 		}
 	}
@@ -25,6 +25,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/record"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/cadvisor"
+	kubecontainer "github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/container"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/dockertools"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/network"
 	docker "github.com/fsouza/go-dockerclient"
@@ -74,12 +75,16 @@ func TestRunOnce(t *testing.T) {
 	cadvisor := &cadvisor.Mock{}
 	cadvisor.On("MachineInfo").Return(&cadvisorApi.MachineInfo{}, nil)
 	kb := &Kubelet{
-		rootDirectory: "/tmp/kubelet",
-		recorder:      &record.FakeRecorder{},
-		cadvisor:      cadvisor,
-		nodeLister:    testNodeLister{},
-		statusManager: newStatusManager(nil),
+		rootDirectory:       "/tmp/kubelet",
+		recorder:            &record.FakeRecorder{},
+		cadvisor:            cadvisor,
+		nodeLister:          testNodeLister{},
+		statusManager:       newStatusManager(nil),
+		containerRefManager: kubecontainer.NewRefManager(),
+		readinessManager:    kubecontainer.NewReadinessManager(),
+		volumeManager:       newVolumeManager(),
 	}
+	kb.probeWorkers = newTestProbeWorkers(kb)
 
 	kb.networkPlugin, _ = network.InitNetworkPlugin([]network.NetworkPlugin{}, "", network.NewFakeHost(nil))
 	if err := kb.setupDataDirs(); err != nil {
@@ -97,13 +102,14 @@ func TestRunOnce(t *testing.T) {
 			Status: "running",
 		},
 	}
-	kb.dockerClient = &testDocker{
+	fakeDocker := &testDocker{
 		listContainersResults: []listContainersResult{
 			{label: "list pod container", containers: []docker.APIContainers{}},
 			{label: "syncPod", containers: []docker.APIContainers{}},
 			{label: "list pod container", containers: []docker.APIContainers{}},
 			{label: "syncPod", containers: podContainers},
 			{label: "list pod container", containers: podContainers},
+			{label: "getPodStatus", containers: podContainers},
 		},
 		inspectContainersResults: []inspectContainersResult{
 			{
@@ -137,7 +143,7 @@ func TestRunOnce(t *testing.T) {
 		},
 		t: t,
 	}
-	kb.dockerPuller = &dockertools.FakeDockerPuller{}
+	kb.containerRuntime = newTestDockerManager(kb, fakeDocker)
 	results, err := kb.runOnce([]api.Pod{
 		{
 			ObjectMeta: api.ObjectMeta{
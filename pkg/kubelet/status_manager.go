@@ -73,7 +73,9 @@ func (s *statusManager) SetPodStatus(pod *api.Pod, status api.PodStatus) {
 	s.podStatusesLock.Lock()
 	defer s.podStatusesLock.Unlock()
 	oldStatus, found := s.podStatuses[podFullName]
-	if !found || !reflect.DeepEqual(oldStatus, status) {
+	// A terminating pod is always synced so that its deletion is confirmed once its
+	// containers have stopped, even if its status did not change.
+	if !found || !reflect.DeepEqual(oldStatus, status) || pod.DeletionTimestamp != nil {
 		s.podStatuses[podFullName] = status
 		s.podStatusChannel <- podStatusSyncRequest{pod, status}
 	} else {
@@ -127,5 +129,25 @@ func (s *statusManager) syncBatch() error {
 	}
 
 	glog.V(3).Infof("Status for pod %q updated successfully", pod.Name)
+
+	// The pod was gracefully deleted and none of its containers are running anymore,
+	// tell the apiserver it can now remove the pod.
+	if pod.DeletionTimestamp != nil && !hasRunningContainers(status) {
+		if err := s.kubeClient.Pods(pod.Namespace).Delete(pod.Name, api.NewDeleteOptions(0)); err != nil {
+			return fmt.Errorf("error confirming deletion of pod %q: %v", pod.Name, err)
+		}
+		glog.V(3).Infof("Pod %q fully terminated and removed from the apiserver", pod.Name)
+		s.DeletePodStatus(podFullName)
+	}
 	return nil
 }
+
+// hasRunningContainers returns true if any container in status is still running.
+func hasRunningContainers(status api.PodStatus) bool {
+	for _, containerStatus := range status.Info {
+		if containerStatus.State.Running != nil {
+			return true
+		}
+	}
+	return false
+}
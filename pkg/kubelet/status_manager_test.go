@@ -23,6 +23,8 @@ import (
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	kubecontainer "github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/container"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 )
 
 var testPod *api.Pod = &api.Pod{
@@ -112,3 +114,49 @@ func TestSyncBatch(t *testing.T) {
 	}
 	verifyActions(t, syncer.kubeClient, []string{"get-pod", "update-status-pod"})
 }
+
+func TestUnchangedStatusOfTerminatingPod(t *testing.T) {
+	syncer := newTestStatusManager()
+	pod := *testPod
+	now := util.Now()
+	pod.DeletionTimestamp = &now
+	podStatus := getRandomPodStatus()
+	syncer.SetPodStatus(&pod, podStatus)
+	syncer.SetPodStatus(&pod, podStatus)
+	verifyUpdates(t, syncer, 2)
+}
+
+func TestSyncBatchConfirmsDeletion(t *testing.T) {
+	syncer := newTestStatusManager()
+	pod := *testPod
+	now := util.Now()
+	pod.DeletionTimestamp = &now
+	syncer.SetPodStatus(&pod, api.PodStatus{
+		Info: api.PodInfo{
+			"bar": api.ContainerStatus{State: api.ContainerState{Termination: &api.ContainerStateTerminated{}}},
+		},
+	})
+	if err := syncer.syncBatch(); err != nil {
+		t.Errorf("unexpected syncing error: %v", err)
+	}
+	verifyActions(t, syncer.kubeClient, []string{"get-pod", "update-status-pod", "delete-pod"})
+	if _, found := syncer.GetPodStatus(kubecontainer.GetPodFullName(&pod)); found {
+		t.Errorf("expected the status of the deleted pod to be forgotten")
+	}
+}
+
+func TestSyncBatchWaitsForRunningContainers(t *testing.T) {
+	syncer := newTestStatusManager()
+	pod := *testPod
+	now := util.Now()
+	pod.DeletionTimestamp = &now
+	syncer.SetPodStatus(&pod, api.PodStatus{
+		Info: api.PodInfo{
+			"bar": api.ContainerStatus{State: api.ContainerState{Running: &api.ContainerStateRunning{}}},
+		},
+	})
+	if err := syncer.syncBatch(); err != nil {
+		t.Errorf("unexpected syncing error: %v", err)
+	}
+	verifyActions(t, syncer.kubeClient, []string{"get-pod", "update-status-pod"})
+}
@@ -25,6 +25,10 @@ import (
 const ConfigSourceAnnotationKey = "kubernetes.io/config.source"
 const ConfigMirrorAnnotationKey = "kubernetes.io/config.mirror"
 
+// ConfigHashAnnotationKey holds the hash of the content of a static pod, as
+// read from its source. It is copied to the mirror pod.
+const ConfigHashAnnotationKey = "kubernetes.io/config.hash"
+
 // PodOperation defines what changes will be made on a pod configuration.
 type PodOperation int
 
@@ -0,0 +1,58 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"sync"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/types"
+)
+
+// volumeManager manages the volumes for the pods running on the kubelet.
+// Currently it only does bookkeeping, so that the container runtime can find
+// the volumes which are mounted for a pod.
+type volumeManager struct {
+	lock       sync.RWMutex
+	volumeMaps map[types.UID]volumeMap
+}
+
+func newVolumeManager() *volumeManager {
+	return &volumeManager{volumeMaps: make(map[types.UID]volumeMap)}
+}
+
+// SetVolumes sets the volume map for a pod.
+func (vm *volumeManager) SetVolumes(podUID types.UID, podVolumes volumeMap) {
+	vm.lock.Lock()
+	defer vm.lock.Unlock()
+	vm.volumeMaps[podUID] = podVolumes
+}
+
+// GetVolumes returns the map of the volumes which are mounted on the host for
+// a pod.
+func (vm *volumeManager) GetVolumes(podUID types.UID) (volumeMap, bool) {
+	vm.lock.RLock()
+	defer vm.lock.RUnlock()
+	vol, ok := vm.volumeMaps[podUID]
+	return vol, ok
+}
+
+// DeleteVolumes forgets the volume map of a pod.
+func (vm *volumeManager) DeleteVolumes(podUID types.UID) {
+	vm.lock.Lock()
+	defer vm.lock.Unlock()
+	delete(vm.volumeMaps, podUID)
+}
@@ -19,6 +19,7 @@ package master
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/http/pprof"
@@ -38,10 +39,16 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/apiserver"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/auth/authenticator"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/auth/authorizer"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/auth/authorizer/rbac"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/auth/handlers"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/cloudprovider"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/master/ports"
+	clusterroleetcd "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/clusterrole/etcd"
+	clusterrolebindingetcd "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/clusterrolebinding/etcd"
 	controlleretcd "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/controller/etcd"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/endpoint"
 	endpointsetcd "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/endpoint/etcd"
@@ -52,14 +59,20 @@ import (
 	nodeetcd "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/minion/etcd"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/namespace"
 	namespaceetcd "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/namespace/etcd"
+	pvetcd "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/persistentvolume/etcd"
+	pvcetcd "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/persistentvolumeclaim/etcd"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/pod"
 	podetcd "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/pod/etcd"
 	resourcequotaetcd "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/resourcequota/etcd"
+	roleetcd "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/role/etcd"
+	rolebindingetcd "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/rolebinding/etcd"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/secret"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/service"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/ui"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 
 	"github.com/emicklei/go-restful"
 	"github.com/emicklei/go-restful/swagger"
@@ -90,9 +103,30 @@ type Config struct {
 	AdmissionControl       admission.Interface
 	MasterServiceNamespace string
 
+	// If true, Authorizer is ignored and requests are authorized against the
+	// roles and bindings stored in the API.
+	EnableRBAC bool
+	// Requests made by this user are always authorized by the RBAC authorizer.
+	RBACSuperUser string
+
 	// Map requests to contexts. Exported so downstream consumers can provider their own mappers
 	RequestContextMapper api.RequestContextMapper
 
+	// If set, a line describing every request to the master is written to
+	// AuditWriter, see apiserver.WithAudit. It must be safe for concurrent use.
+	AuditWriter io.Writer
+	// If true, the audit log includes the bodies of the requests that are not
+	// read-only, and of their responses.
+	AuditLogBodies bool
+
+	// If set, limits the number of requests served at the same time, see
+	// apiserver.MaxInFlightLimit.
+	InFlightLimiter *apiserver.InFlightLimiter
+
+	// If positive, watches and lists at a resource version of pods, nodes and
+	// endpoints are served from caches that remember this many changes.
+	WatchCacheCapacity int
+
 	// If specified, all web services will be registered into this container
 	RestfulContainer *restful.Container
 
@@ -167,8 +201,9 @@ type Master struct {
 }
 
 // NewEtcdHelper returns an EtcdHelper for the provided arguments or an error if the version
-// is incorrect.
-func NewEtcdHelper(client tools.EtcdGetSet, version string) (helper tools.EtcdHelper, err error) {
+// is incorrect. Objects are stored as JSON unless contentType is runtime.ProtobufContentType;
+// stored JSON can be read in both cases.
+func NewEtcdHelper(client tools.EtcdGetSet, version, contentType string) (helper tools.EtcdHelper, err error) {
 	if version == "" {
 		version = latest.Version
 	}
@@ -176,7 +211,18 @@ func NewEtcdHelper(client tools.EtcdGetSet, version string) (helper tools.EtcdHe
 	if err != nil {
 		return helper, err
 	}
-	return tools.NewEtcdHelper(client, versionInterfaces.Codec), nil
+	switch contentType {
+	case "", "application/json":
+		return tools.NewEtcdHelper(client, versionInterfaces.Codec), nil
+	case runtime.ProtobufContentType:
+		codec, err := latest.ProtobufCodecFor(version)
+		if err != nil {
+			return helper, err
+		}
+		return tools.NewEtcdHelper(client, tools.Base64Codec(codec)), nil
+	default:
+		return helper, fmt.Errorf("unsupported storage content type: %s (valid: application/json, %s)", contentType, runtime.ProtobufContentType)
+	}
 }
 
 // setDefaults fills in any fields not set that are required to have valid data.
@@ -348,7 +394,7 @@ func logStackOnRecover(panicReason interface{}, httpWriter http.ResponseWriter)
 
 // init initializes master.
 func (m *Master) init(c *Config) {
-	podStorage, bindingStorage, podStatusStorage := podetcd.NewStorage(c.EtcdHelper)
+	podStorage, bindingStorage, podStatusStorage := podetcd.NewStorage(c.EtcdHelper, c.WatchCacheCapacity)
 	podRegistry := pod.NewRegistry(podStorage)
 
 	eventRegistry := event.NewEtcdRegistry(c.EtcdHelper, uint64(c.EventTTL.Seconds()))
@@ -356,14 +402,36 @@ func (m *Master) init(c *Config) {
 
 	resourceQuotaStorage, resourceQuotaStatusStorage := resourcequotaetcd.NewStorage(c.EtcdHelper)
 	secretRegistry := secret.NewEtcdRegistry(c.EtcdHelper)
+	persistentVolumeStorage, persistentVolumeStatusStorage := pvetcd.NewStorage(c.EtcdHelper)
+	persistentVolumeClaimStorage, persistentVolumeClaimStatusStorage := pvcetcd.NewStorage(c.EtcdHelper)
+
+	var escalation rbac.EscalationChecker
+	if c.EnableRBAC {
+		// The authorizer reads roles and bindings from memory, so that authorizing a
+		// request does not read from etcd.
+		rbacCache := rbac.NewCache(
+			storageListWatch(roleetcd.NewStorage(c.EtcdHelper, nil)),
+			storageListWatch(rolebindingetcd.NewStorage(c.EtcdHelper, nil)),
+			storageListWatch(clusterroleetcd.NewStorage(c.EtcdHelper, nil)),
+			storageListWatch(clusterrolebindingetcd.NewStorage(c.EtcdHelper, nil)),
+		)
+		rbacCache.Run()
+		authz := rbac.New(rbacCache, rbacCache, rbacCache, rbacCache, c.RBACSuperUser)
+		m.authorizer = authz
+		escalation = authz
+	}
+	roleStorage := roleetcd.NewStorage(c.EtcdHelper, escalation)
+	clusterRoleStorage := clusterroleetcd.NewStorage(c.EtcdHelper, escalation)
+	roleBindingStorage := rolebindingetcd.NewStorage(c.EtcdHelper, escalation)
+	clusterRoleBindingStorage := clusterrolebindingetcd.NewStorage(c.EtcdHelper, escalation)
 
 	namespaceStorage, namespaceStatusStorage, namespaceFinalizeStorage := namespaceetcd.NewStorage(c.EtcdHelper)
 	m.namespaceRegistry = namespace.NewRegistry(namespaceStorage)
 
-	endpointsStorage := endpointsetcd.NewStorage(c.EtcdHelper)
+	endpointsStorage := endpointsetcd.NewStorage(c.EtcdHelper, c.WatchCacheCapacity)
 	m.endpointRegistry = endpoint.NewRegistry(endpointsStorage)
 
-	nodeStorage := nodeetcd.NewStorage(c.EtcdHelper, c.KubeletClient)
+	nodeStorage := nodeetcd.NewStorage(c.EtcdHelper, c.KubeletClient, c.WatchCacheCapacity)
 	m.nodeRegistry = minion.NewRegistry(nodeStorage)
 
 	// TODO: split me up into distinct storage registries
@@ -386,13 +454,21 @@ func (m *Master) init(c *Config) {
 		"nodes":                  nodeStorage,
 		"events":                 event.NewStorage(eventRegistry),
 
-		"limitRanges":           limitrange.NewStorage(limitRangeRegistry),
-		"resourceQuotas":        resourceQuotaStorage,
-		"resourceQuotas/status": resourceQuotaStatusStorage,
-		"namespaces":            namespaceStorage,
-		"namespaces/status":     namespaceStatusStorage,
-		"namespaces/finalize":   namespaceFinalizeStorage,
-		"secrets":               secret.NewStorage(secretRegistry),
+		"limitRanges":                   limitrange.NewStorage(limitRangeRegistry),
+		"resourceQuotas":                resourceQuotaStorage,
+		"resourceQuotas/status":         resourceQuotaStatusStorage,
+		"namespaces":                    namespaceStorage,
+		"namespaces/status":             namespaceStatusStorage,
+		"namespaces/finalize":           namespaceFinalizeStorage,
+		"secrets":                       secret.NewStorage(secretRegistry),
+		"persistentVolumes":             persistentVolumeStorage,
+		"persistentVolumes/status":      persistentVolumeStatusStorage,
+		"persistentVolumeClaims":        persistentVolumeClaimStorage,
+		"persistentVolumeClaims/status": persistentVolumeClaimStatusStorage,
+		"roles":                         roleStorage,
+		"roleBindings":                  roleBindingStorage,
+		"clusterRoles":                  clusterRoleStorage,
+		"clusterRoleBindings":           clusterRoleBindingStorage,
 	}
 
 	apiVersions := []string{"v1beta1", "v1beta2"}
@@ -453,6 +529,13 @@ func (m *Master) init(c *Config) {
 	attributeGetter := apiserver.NewRequestAttributeGetter(m.requestContextMapper, latest.RESTMapper, "api")
 	handler = apiserver.WithAuthorizationCheck(handler, attributeGetter, m.authorizer)
 
+	// Limit requests once they are authenticated, so that they are queued by
+	// user.
+	if c.InFlightLimiter != nil {
+		handler = apiserver.MaxInFlightLimit(c.InFlightLimiter, m.requestContextMapper, handler)
+		m.InsecureHandler = apiserver.MaxInFlightLimit(c.InFlightLimiter, m.requestContextMapper, m.InsecureHandler)
+	}
+
 	// Install Authenticator
 	if c.Authenticator != nil {
 		authenticatedHandler, err := handlers.NewRequestAuthenticator(m.requestContextMapper, c.Authenticator, handlers.Unauthorized, handler)
@@ -462,6 +545,15 @@ func (m *Master) init(c *Config) {
 		handler = authenticatedHandler
 	}
 
+	// Audit requests around the authenticator, so that the requests it rejects
+	// are logged too. The user is known once the request is handled, since the
+	// context filter installed below outlives the audit.
+	if c.AuditWriter != nil {
+		resolver := &apiserver.APIRequestInfoResolver{APIPrefixes: util.NewStringSet("api"), RestMapper: latest.RESTMapper}
+		handler = apiserver.WithAudit(handler, m.requestContextMapper, resolver, c.AuditWriter, c.AuditLogBodies)
+		m.InsecureHandler = apiserver.WithAudit(m.InsecureHandler, m.requestContextMapper, resolver, c.AuditWriter, c.AuditLogBodies)
+	}
+
 	// Install root web services
 	m.handlerContainer.Add(m.rootWebService)
 
@@ -489,6 +581,21 @@ func (m *Master) init(c *Config) {
 	m.masterServices.Start()
 }
 
+// storageListWatch lists and watches every object of the given storage.
+func storageListWatch(storage interface {
+	rest.Lister
+	rest.Watcher
+}) *cache.ListWatch {
+	return &cache.ListWatch{
+		ListFunc: func() (runtime.Object, error) {
+			return storage.List(api.NewContext(), labels.Everything(), fields.Everything())
+		},
+		WatchFunc: func(resourceVersion string) (watch.Interface, error) {
+			return storage.Watch(api.NewContext(), labels.Everything(), fields.Everything(), resourceVersion)
+		},
+	}
+}
+
 // InstallSwaggerAPI installs the /swaggerapi/ endpoint to allow schema discovery
 // and traversal.  It is optional to allow consumers of the Kubernetes master to
 // register their own web services into the Kubernetes mux prior to initialization
@@ -604,5 +711,6 @@ func (m *Master) api_v1beta3() *apiserver.APIGroupVersion {
 	version.Storage = storage
 	version.Version = "v1beta3"
 	version.Codec = v1beta3.Codec
+	version.ProtobufCodec = v1beta3.ProtobufCodec
 	return version
 }
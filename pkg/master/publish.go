@@ -113,11 +113,10 @@ func (m *Master) createMasterServiceIfNeeded(serviceName string, serviceIP net.I
 			Labels:    map[string]string{"provider": "kubernetes", "component": "apiserver"},
 		},
 		Spec: api.ServiceSpec{
-			Port: servicePort,
+			Ports: []api.ServicePort{{Port: servicePort, Protocol: api.ProtocolTCP}},
 			// maintained by this code, not by the pod selector
 			Selector:        nil,
 			PortalIP:        serviceIP.String(),
-			Protocol:        api.ProtocolTCP,
 			SessionAffinity: api.AffinityTypeNone,
 		},
 	}
@@ -135,30 +134,34 @@ func (m *Master) createMasterServiceIfNeeded(serviceName string, serviceIP net.I
 func (m *Master) ensureEndpointsContain(serviceName string, ip net.IP, port int) error {
 	ctx := api.NewDefaultContext()
 	e, err := m.endpointRegistry.GetEndpoints(ctx, serviceName)
-	if err != nil || e.Protocol != api.ProtocolTCP {
+	if err != nil || len(e.Subsets) != 1 || len(e.Subsets[0].Ports) != 1 ||
+		e.Subsets[0].Ports[0].Port != port || e.Subsets[0].Ports[0].Protocol != api.ProtocolTCP {
 		e = &api.Endpoints{
 			ObjectMeta: api.ObjectMeta{
 				Name:      serviceName,
 				Namespace: api.NamespaceDefault,
 			},
-			Protocol: api.ProtocolTCP,
+			Subsets: []api.EndpointSubset{{
+				Ports: []api.EndpointPort{{Port: port, Protocol: api.ProtocolTCP}},
+			}},
 		}
 	}
 	found := false
-	for i := range e.Endpoints {
-		ep := &e.Endpoints[i]
-		if ep.IP == ip.String() && ep.Port == port {
+	for i := range e.Subsets[0].Addresses {
+		if e.Subsets[0].Addresses[i].IP == ip.String() {
 			found = true
 			break
 		}
 	}
 	if !found {
-		e.Endpoints = append(e.Endpoints, api.Endpoint{IP: ip.String(), Port: port})
-		if len(e.Endpoints) > m.masterCount {
+		addrs := append([]api.EndpointAddress{}, e.Subsets[0].Addresses...)
+		addrs = append(addrs, api.EndpointAddress{IP: ip.String()})
+		if len(addrs) > m.masterCount {
 			// We append to the end and remove from the beginning, so this should
 			// converge rapidly with all masters performing this operation.
-			e.Endpoints = e.Endpoints[len(e.Endpoints)-m.masterCount:]
+			addrs = addrs[len(addrs)-m.masterCount:]
 		}
+		e.Subsets = []api.EndpointSubset{{Addresses: addrs, Ports: e.Subsets[0].Ports}}
 		return m.endpointRegistry.UpdateEndpoints(ctx, e)
 	}
 	// We didn't make any changes, no need to actually call update.
@@ -34,7 +34,7 @@ func TestEnsureEndpointsContain(t *testing.T) {
 		expectError       bool
 		expectUpdate      bool
 		endpoints         *api.EndpointsList
-		expectedEndpoints []api.Endpoint
+		expectedAddresses []api.EndpointAddress
 		err               error
 		masterCount       int
 	}{
@@ -58,18 +58,14 @@ func TestEnsureEndpointsContain(t *testing.T) {
 						ObjectMeta: api.ObjectMeta{
 							Name: "foo",
 						},
-						Endpoints: []api.Endpoint{
-							{
-								IP:   "1.2.3.4",
-								Port: 8080,
-							},
-						},
-						Protocol: api.ProtocolTCP,
+						Subsets: []api.EndpointSubset{{
+							Addresses: []api.EndpointAddress{{IP: "1.2.3.4"}},
+							Ports:     []api.EndpointPort{{Port: 8080, Protocol: api.ProtocolTCP}},
+						}},
 					},
 				},
 			},
-			masterCount:       1,
-			expectedEndpoints: []api.Endpoint{{"1.2.3.4", 8080, nil}},
+			masterCount: 1,
 		},
 		{
 			serviceName:  "foo",
@@ -84,13 +80,10 @@ func TestEnsureEndpointsContain(t *testing.T) {
 							Name:      "foo",
 							Namespace: api.NamespaceDefault,
 						},
-						Endpoints: []api.Endpoint{
-							{
-								IP:   "4.3.2.1",
-								Port: 8080,
-							},
-						},
-						Protocol: api.ProtocolTCP,
+						Subsets: []api.EndpointSubset{{
+							Addresses: []api.EndpointAddress{{IP: "4.3.2.1"}},
+							Ports:     []api.EndpointPort{{Port: 8080, Protocol: api.ProtocolTCP}},
+						}},
 					},
 				},
 			},
@@ -109,18 +102,15 @@ func TestEnsureEndpointsContain(t *testing.T) {
 							Name:      "foo",
 							Namespace: api.NamespaceDefault,
 						},
-						Endpoints: []api.Endpoint{
-							{
-								IP:   "4.3.2.1",
-								Port: 9090,
-							},
-						},
-						Protocol: api.ProtocolTCP,
+						Subsets: []api.EndpointSubset{{
+							Addresses: []api.EndpointAddress{{IP: "4.3.2.1"}},
+							Ports:     []api.EndpointPort{{Port: 8080, Protocol: api.ProtocolTCP}},
+						}},
 					},
 				},
 			},
 			masterCount:       2,
-			expectedEndpoints: []api.Endpoint{{"4.3.2.1", 9090, nil}, {"1.2.3.4", 8080, nil}},
+			expectedAddresses: []api.EndpointAddress{{IP: "4.3.2.1"}, {IP: "1.2.3.4"}},
 		},
 		{
 			serviceName:  "foo",
@@ -135,22 +125,14 @@ func TestEnsureEndpointsContain(t *testing.T) {
 							Name:      "foo",
 							Namespace: api.NamespaceDefault,
 						},
-						Endpoints: []api.Endpoint{
-							{
-								IP:   "4.3.2.1",
-								Port: 9090,
-							},
-							{
-								IP:   "1.2.3.4",
-								Port: 8000,
-							},
-						},
-						Protocol: api.ProtocolTCP,
+						Subsets: []api.EndpointSubset{{
+							Addresses: []api.EndpointAddress{{IP: "4.3.2.1"}, {IP: "1.2.3.4"}},
+							Ports:     []api.EndpointPort{{Port: 8000, Protocol: api.ProtocolTCP}},
+						}},
 					},
 				},
 			},
-			masterCount:       2,
-			expectedEndpoints: []api.Endpoint{{"1.2.3.4", 8000, nil}, {"1.2.3.4", 8080, nil}},
+			masterCount: 2,
 		},
 	}
 	for _, test := range tests {
@@ -169,16 +151,18 @@ func TestEnsureEndpointsContain(t *testing.T) {
 			t.Errorf("unexpected error: %v", err)
 		}
 		if test.expectUpdate {
-			if test.expectedEndpoints == nil {
-				test.expectedEndpoints = []api.Endpoint{{test.ip, test.port, nil}}
+			if test.expectedAddresses == nil {
+				test.expectedAddresses = []api.EndpointAddress{{IP: test.ip}}
 			}
 			expectedUpdate := api.Endpoints{
 				ObjectMeta: api.ObjectMeta{
 					Name:      test.serviceName,
 					Namespace: "default",
 				},
-				Endpoints: test.expectedEndpoints,
-				Protocol:  "TCP",
+				Subsets: []api.EndpointSubset{{
+					Addresses: test.expectedAddresses,
+					Ports:     []api.EndpointPort{{Port: test.port, Protocol: api.ProtocolTCP}},
+				}},
 			}
 			if len(registry.Updates) != 1 {
 				t.Errorf("unexpected updates: %v", registry.Updates)
@@ -202,17 +186,10 @@ func TestEnsureEndpointsContainConverges(t *testing.T) {
 						Name:      "foo",
 						Namespace: api.NamespaceDefault,
 					},
-					Endpoints: []api.Endpoint{
-						{
-							IP:   "4.3.2.1",
-							Port: 9000,
-						},
-						{
-							IP:   "1.2.3.4",
-							Port: 8000,
-						},
-					},
-					Protocol: api.ProtocolTCP,
+					Subsets: []api.EndpointSubset{{
+						Addresses: []api.EndpointAddress{{IP: "4.3.2.1"}, {IP: "1.2.3.5"}},
+						Ports:     []api.EndpointPort{{Port: 8080, Protocol: api.ProtocolTCP}},
+					}},
 				},
 			},
 		},
@@ -225,7 +202,7 @@ func TestEnsureEndpointsContainConverges(t *testing.T) {
 	wg.Add(2)
 	go func() {
 		for i := 0; i < 10; i++ {
-			if err := master.ensureEndpointsContain("foo", net.ParseIP("4.3.2.1"), 9090); err != nil {
+			if err := master.ensureEndpointsContain("foo", net.ParseIP("4.3.2.1"), 8080); err != nil {
 				t.Errorf("unexpected error: %v", err)
 				t.Fail()
 			}
@@ -249,15 +226,12 @@ func TestEnsureEndpointsContainConverges(t *testing.T) {
 	}
 	// Pick up the last update and validate.
 	endpoints := registry.Updates[len(registry.Updates)-1]
-	if len(endpoints.Endpoints) != 2 {
+	if len(endpoints.Subsets) != 1 || len(endpoints.Subsets[0].Addresses) != 2 {
 		t.Errorf("unexpected update: %v", endpoints)
 	}
-	for _, endpoint := range endpoints.Endpoints {
-		if endpoint.IP == "4.3.2.1" && endpoint.Port != 9090 {
-			t.Errorf("unexpected endpoint state: %v", endpoint)
-		}
-		if endpoint.IP == "1.2.3.4" && endpoint.Port != 8080 {
-			t.Errorf("unexpected endpoint state: %v", endpoint)
+	for _, addr := range endpoints.Subsets[0].Addresses {
+		if addr.IP != "4.3.2.1" && addr.IP != "1.2.3.4" {
+			t.Errorf("unexpected endpoint state: %v", addr)
 		}
 	}
 }
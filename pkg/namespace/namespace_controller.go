@@ -17,34 +17,47 @@ limitations under the License.
 package namespace
 
 import (
-	"sync"
+	"fmt"
+	"reflect"
 	"time"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	apierrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/latest"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/meta"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/errors"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 	"github.com/golang/glog"
 )
 
+// Bounds of the backoff applied to namespaces whose clean-up failed.
+const (
+	minRetryDelay = 5 * time.Millisecond
+	maxRetryDelay = 5 * time.Minute
+)
+
 // NamespaceManager is responsible for performing actions dependent upon a namespace phase
 type NamespaceManager struct {
 	kubeClient client.Interface
+	informer   *cache.Informer
 	store      cache.Store
-	syncTime   <-chan time.Time
+	// queue holds the keys of the namespaces that are being deleted.
+	queue *cache.RateLimitingQueue
 
 	// To allow injection for testing.
 	syncHandler func(namespace api.Namespace) error
 }
 
-// NewNamespaceManager creates a new NamespaceManager
-func NewNamespaceManager(kubeClient client.Interface) *NamespaceManager {
-	store := cache.NewStore(cache.MetaNamespaceKeyFunc)
-	reflector := cache.NewReflector(
+// NewNamespaceManager creates a new NamespaceManager. Namespaces are synced
+// as soon as they are marked for deletion, and again every resyncPeriod.
+func NewNamespaceManager(kubeClient client.Interface, resyncPeriod time.Duration) *NamespaceManager {
+	informer := cache.NewInformer(
 		&cache.ListWatch{
 			ListFunc: func() (runtime.Object, error) {
 				return kubeClient.Namespaces().List(labels.Everything(), fields.Everything())
@@ -54,42 +67,80 @@ func NewNamespaceManager(kubeClient client.Interface) *NamespaceManager {
 			},
 		},
 		&api.Namespace{},
-		store,
-		0,
+		resyncPeriod,
+		nil,
 	)
-	reflector.Run()
 	nm := &NamespaceManager{
 		kubeClient: kubeClient,
-		store:      store,
-	}
+		informer:   informer,
+		store:      informer.GetStore(),
+		queue:      cache.NewRateLimitingQueue(minRetryDelay, maxRetryDelay),
+	}
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    nm.enqueueNamespace,
+		UpdateFunc: func(old, cur interface{}) { nm.enqueueNamespace(cur) },
+	})
 	// set the synchronization handler
 	nm.syncHandler = nm.syncNamespace
 	return nm
 }
 
-// Run begins syncing at the specified period interval
-func (nm *NamespaceManager) Run(period time.Duration) {
-	nm.syncTime = time.Tick(period)
-	go util.Forever(func() { nm.synchronize() }, period)
+// Run starts the informer and, once it has synced, the given number of
+// workers. It returns immediately; everything stops when stopCh is closed.
+func (nm *NamespaceManager) Run(workers int, stopCh <-chan struct{}) {
+	nm.informer.Run(stopCh)
+	go func() {
+		defer util.HandleCrash()
+		if !cache.WaitForSync(stopCh, nm.informer) {
+			return
+		}
+		for i := 0; i < workers; i++ {
+			go util.Until(nm.worker, time.Second, stopCh)
+		}
+		<-stopCh
+		nm.queue.ShutDown()
+	}()
 }
 
-// Iterate over the each namespace that is in terminating phase and perform necessary clean-up
-func (nm *NamespaceManager) synchronize() {
-	namespaceObjs := nm.store.List()
-	wg := sync.WaitGroup{}
-	wg.Add(len(namespaceObjs))
-	for ix := range namespaceObjs {
-		go func(ix int) {
-			defer wg.Done()
-			namespace := namespaceObjs[ix].(*api.Namespace)
-			glog.V(4).Infof("periodic sync of namespace: %v", namespace.Name)
-			err := nm.syncHandler(*namespace)
-			if err != nil {
-				glog.Errorf("Error synchronizing: %v", err)
-			}
-		}(ix)
+// enqueueNamespace queues namespaces that are being deleted; there is nothing
+// to do for the others.
+func (nm *NamespaceManager) enqueueNamespace(obj interface{}) {
+	namespace := obj.(*api.Namespace)
+	if namespace.DeletionTimestamp == nil {
+		return
+	}
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		util.HandleError(fmt.Errorf("couldn't get key for object %+v: %v", obj, err))
+		return
+	}
+	nm.queue.Add(key)
+}
+
+// worker processes namespaces from the queue until it is shut down.
+func (nm *NamespaceManager) worker() {
+	for nm.processNextWorkItem() {
+	}
+}
+
+func (nm *NamespaceManager) processNextWorkItem() bool {
+	key, quit := nm.queue.Get()
+	if quit {
+		return false
+	}
+	defer nm.queue.Done(key)
+	obj, exists, err := nm.store.GetByKey(key)
+	if err == nil && exists {
+		glog.V(4).Infof("Syncing namespace: %v", key)
+		err = nm.syncHandler(*obj.(*api.Namespace))
 	}
-	wg.Wait()
+	if err != nil {
+		glog.Errorf("Error synchronizing namespace %q: %v", key, err)
+		nm.queue.AddRateLimited(key)
+		return true
+	}
+	nm.queue.Forget(key)
+	return true
 }
 
 // finalized returns true if the spec.finalizers is empty list
@@ -119,37 +170,102 @@ func finalize(kubeClient client.Interface, namespace api.Namespace) (*api.Namesp
 	return kubeClient.Namespaces().Finalize(&namespaceFinalize)
 }
 
-// deleteAllContent will delete all content known to the system in a namespace
-func deleteAllContent(kubeClient client.Interface, namespace string) (err error) {
-	err = deleteServices(kubeClient, namespace)
+// deleteAllContent deletes every object of every namespaced resource the
+// apiserver advertises in the given namespace. It returns how many objects of
+// each resource it found; anything found may not be gone yet (deletion can be
+// graceful, or new objects may have been created meanwhile), so the caller has
+// to check again until a pass finds nothing.
+func deleteAllContent(kubeClient client.Interface, namespace string) ([]api.NamespaceRemainingResource, error) {
+	resources, err := kubeClient.ServerResources()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	err = deleteReplicationControllers(kubeClient, namespace)
-	if err != nil {
-		return err
+	remaining := []api.NamespaceRemainingResource{}
+	errs := []error{}
+	for _, resource := range resources.Resources {
+		if !resource.Namespaced || !hasVerbs(resource, "list", "delete") {
+			continue
+		}
+		if err := checkNamespaced(resource.Name); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		count, err := deleteCollection(kubeClient, resource.Name, namespace)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if count > 0 {
+			remaining = append(remaining, api.NamespaceRemainingResource{Resource: resource.Name, Count: count})
+		}
 	}
-	err = deletePods(kubeClient, namespace)
+	return remaining, errors.NewAggregate(errs)
+}
+
+// hasVerbs returns true if the resource supports all the given verbs.
+func hasVerbs(resource api.APIResource, verbs ...string) bool {
+	supported := util.NewStringSet(resource.Verbs...)
+	return supported.HasAll(verbs...)
+}
+
+// checkNamespaced makes sure a discovered resource is known to the
+// RESTMapper as a namespaced kind. A resource the controller cannot map is an
+// error rather than something to skip, or its objects would outlive the
+// namespace.
+func checkNamespaced(resource string) error {
+	version, kind, err := latest.RESTMapper.VersionAndKindForResource(resource)
 	if err != nil {
 		return err
 	}
-	err = deleteSecrets(kubeClient, namespace)
+	mapping, err := latest.RESTMapper.RESTMapping(kind, version)
 	if err != nil {
 		return err
 	}
-	err = deleteLimitRanges(kubeClient, namespace)
-	if err != nil {
-		return err
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return fmt.Errorf("resource %q is advertised as namespaced but %s is not", resource, kind)
 	}
-	err = deleteResourceQuotas(kubeClient, namespace)
+	return nil
+}
+
+// deleteCollection deletes all objects of resource in namespace and returns
+// how many it found.
+func deleteCollection(kubeClient client.Interface, resource, namespace string) (int, error) {
+	list, err := kubeClient.Resources(resource, namespace).List(labels.Everything(), fields.Everything())
 	if err != nil {
-		return err
+		return 0, err
 	}
-	err = deleteEvents(kubeClient, namespace)
+	items, err := runtime.ExtractList(list)
 	if err != nil {
-		return err
+		return 0, err
 	}
-	return nil
+	for _, item := range items {
+		accessor, err := meta.Accessor(item)
+		if err != nil {
+			return len(items), err
+		}
+		err = kubeClient.Resources(resource, namespace).Delete(accessor.Name())
+		if err != nil && !apierrors.IsNotFound(err) {
+			return len(items), err
+		}
+	}
+	return len(items), nil
+}
+
+// updateRemaining records the outcome of a clean-up pass in the namespace
+// status, if it changed.
+func (nm *NamespaceManager) updateRemaining(namespace api.Namespace, remaining []api.NamespaceRemainingResource, message string) error {
+	if len(remaining) == 0 {
+		remaining = nil
+	}
+	if reflect.DeepEqual(namespace.Status.Remaining, remaining) && namespace.Status.Message == message {
+		return nil
+	}
+	newNamespace := api.Namespace{}
+	newNamespace.ObjectMeta = namespace.ObjectMeta
+	newNamespace.Status = namespace.Status
+	newNamespace.Status.Remaining = remaining
+	newNamespace.Status.Message = message
+	_, err := nm.kubeClient.Namespaces().Status(&newNamespace)
+	return err
 }
 
 // syncNamespace makes namespace life-cycle decisions
@@ -178,11 +294,22 @@ func (nm *NamespaceManager) syncNamespace(namespace api.Namespace) (err error) {
 		return err
 	}
 
-	// there may still be content for us to remove
-	err = deleteAllContent(nm.kubeClient, namespace.Name)
+	// there may still be content for us to remove; keep going until a pass
+	// finds nothing, reporting what is left in the status as we go
+	remaining, err := deleteAllContent(nm.kubeClient, namespace.Name)
 	if err != nil {
+		if statusErr := nm.updateRemaining(namespace, remaining, err.Error()); statusErr != nil {
+			glog.Errorf("Unable to update status of namespace %q: %v", namespace.Name, statusErr)
+		}
 		return err
 	}
+	if len(remaining) > 0 {
+		message := fmt.Sprintf("waiting for %d kinds of resources to be deleted", len(remaining))
+		if err := nm.updateRemaining(namespace, remaining, message); err != nil {
+			return err
+		}
+		return fmt.Errorf("namespace %q still has content: %v", namespace.Name, remaining)
+	}
 
 	// we have removed content, so mark it finalized by us
 	result, err := finalize(nm.kubeClient, namespace)
@@ -198,101 +325,3 @@ func (nm *NamespaceManager) syncNamespace(namespace api.Namespace) (err error) {
 
 	return nil
 }
-
-func deleteLimitRanges(kubeClient client.Interface, ns string) error {
-	items, err := kubeClient.LimitRanges(ns).List(labels.Everything())
-	if err != nil {
-		return err
-	}
-	for i := range items.Items {
-		err := kubeClient.LimitRanges(ns).Delete(items.Items[i].Name)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-func deleteResourceQuotas(kubeClient client.Interface, ns string) error {
-	resourceQuotas, err := kubeClient.ResourceQuotas(ns).List(labels.Everything())
-	if err != nil {
-		return err
-	}
-	for i := range resourceQuotas.Items {
-		err := kubeClient.ResourceQuotas(ns).Delete(resourceQuotas.Items[i].Name)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-func deleteServices(kubeClient client.Interface, ns string) error {
-	items, err := kubeClient.Services(ns).List(labels.Everything())
-	if err != nil {
-		return err
-	}
-	for i := range items.Items {
-		err := kubeClient.Services(ns).Delete(items.Items[i].Name)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-func deleteReplicationControllers(kubeClient client.Interface, ns string) error {
-	items, err := kubeClient.ReplicationControllers(ns).List(labels.Everything())
-	if err != nil {
-		return err
-	}
-	for i := range items.Items {
-		err := kubeClient.ReplicationControllers(ns).Delete(items.Items[i].Name)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-func deletePods(kubeClient client.Interface, ns string) error {
-	items, err := kubeClient.Pods(ns).List(labels.Everything())
-	if err != nil {
-		return err
-	}
-	for i := range items.Items {
-		err := kubeClient.Pods(ns).Delete(items.Items[i].Name)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-func deleteEvents(kubeClient client.Interface, ns string) error {
-	items, err := kubeClient.Events(ns).List(labels.Everything(), fields.Everything())
-	if err != nil {
-		return err
-	}
-	for i := range items.Items {
-		err := kubeClient.Events(ns).Delete(items.Items[i].Name)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-func deleteSecrets(kubeClient client.Interface, ns string) error {
-	items, err := kubeClient.Secrets(ns).List(labels.Everything(), fields.Everything())
-	if err != nil {
-		return err
-	}
-	for i := range items.Items {
-		err := kubeClient.Secrets(ns).Delete(items.Items[i].Name)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
@@ -17,11 +17,13 @@ limitations under the License.
 package namespace
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 )
 
@@ -60,8 +62,22 @@ func TestFinalize(t *testing.T) {
 	}
 }
 
+// namespacedResources is what the apiserver advertises for the usual
+// namespaced resources.
+func namespacedResources() []api.APIResource {
+	verbs := []string{"create", "delete", "get", "list", "update", "watch"}
+	resources := []api.APIResource{}
+	for _, name := range []string{"services", "pods", "resourceQuotas", "replicationControllers", "secrets", "limitRanges", "events", "persistentVolumeClaims"} {
+		resources = append(resources, api.APIResource{Name: name, Namespaced: true, Verbs: verbs})
+	}
+	return append(resources,
+		api.APIResource{Name: "namespaces", Namespaced: false, Verbs: verbs},
+		api.APIResource{Name: "bindings", Namespaced: true, Verbs: []string{"create"}},
+	)
+}
+
 func TestSyncNamespaceThatIsTerminating(t *testing.T) {
-	mockClient := &client.Fake{}
+	mockClient := &client.Fake{APIResources: namespacedResources()}
 	nm := NamespaceManager{kubeClient: mockClient, store: cache.NewStore(cache.MetaNamespaceKeyFunc)}
 	now := util.Now()
 	testNamespace := api.Namespace{
@@ -82,13 +98,15 @@ func TestSyncNamespaceThatIsTerminating(t *testing.T) {
 		t.Errorf("Unexpected error when synching namespace %v", err)
 	}
 	expectedActionSet := util.NewStringSet(
+		"get-apiresources",
 		"list-services",
 		"list-pods",
 		"list-resourceQuotas",
-		"list-controllers",
+		"list-replicationControllers",
 		"list-secrets",
 		"list-limitRanges",
 		"list-events",
+		"list-persistentVolumeClaims",
 		"finalize-namespace",
 		"delete-namespace")
 	actionSet := util.NewStringSet()
@@ -98,6 +116,64 @@ func TestSyncNamespaceThatIsTerminating(t *testing.T) {
 	if !actionSet.HasAll(expectedActionSet.List()...) {
 		t.Errorf("Expected actions: %v, but got: %v", expectedActionSet, actionSet)
 	}
+	if actionSet.Has("list-namespaces") || actionSet.Has("list-bindings") {
+		t.Errorf("Unexpected list of a resource that is not namespaced or cannot be listed: %v", actionSet)
+	}
+}
+
+func TestSyncNamespaceWithRemainingContent(t *testing.T) {
+	mockClient := &client.Fake{
+		APIResources: namespacedResources(),
+		ResourceLists: map[string]runtime.Object{
+			"pods": &api.PodList{Items: []api.Pod{
+				{ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "test"}},
+				{ObjectMeta: api.ObjectMeta{Name: "bar", Namespace: "test"}},
+			}},
+		},
+	}
+	nm := NamespaceManager{kubeClient: mockClient, store: cache.NewStore(cache.MetaNamespaceKeyFunc)}
+	now := util.Now()
+	testNamespace := api.Namespace{
+		ObjectMeta: api.ObjectMeta{
+			Name:              "test",
+			ResourceVersion:   "1",
+			DeletionTimestamp: &now,
+		},
+		Spec: api.NamespaceSpec{
+			Finalizers: []api.FinalizerName{"kubernetes"},
+		},
+		Status: api.NamespaceStatus{
+			Phase: api.NamespaceTerminating,
+		},
+	}
+	if err := nm.syncNamespace(testNamespace); err == nil {
+		t.Errorf("Expected an error so that the namespace is retried")
+	}
+	deleted := []string{}
+	var status *api.Namespace
+	for _, action := range mockClient.Actions {
+		switch action.Action {
+		case "delete-pods":
+			deleted = append(deleted, action.Value.(string))
+		case "status-namespace":
+			status = action.Value.(*api.Namespace)
+		case "finalize-namespace", "delete-namespace":
+			t.Errorf("Unexpected action %q while content remains", action.Action)
+		}
+	}
+	if e, a := []string{"foo", "bar"}, deleted; !reflect.DeepEqual(e, a) {
+		t.Errorf("Expected pods %v to be deleted, got %v", e, a)
+	}
+	if status == nil {
+		t.Fatalf("Expected the namespace status to be updated")
+	}
+	expected := []api.NamespaceRemainingResource{{Resource: "pods", Count: 2}}
+	if !reflect.DeepEqual(expected, status.Status.Remaining) {
+		t.Errorf("Expected remaining %v, got %v", expected, status.Status.Remaining)
+	}
+	if len(status.Status.Message) == 0 {
+		t.Errorf("Expected a progress message")
+	}
 }
 
 func TestSyncNamespaceThatIsActive(t *testing.T) {
@@ -127,3 +203,28 @@ func TestSyncNamespaceThatIsActive(t *testing.T) {
 		t.Errorf("Expected no action from controller, but got: %v", actionSet)
 	}
 }
+
+func TestOnlyTerminatingNamespacesAreQueued(t *testing.T) {
+	mockClient := &client.Fake{}
+	nm := NewNamespaceManager(mockClient, 0)
+	now := util.Now()
+	active := &api.Namespace{ObjectMeta: api.ObjectMeta{Name: "active"}}
+	deleted := &api.Namespace{ObjectMeta: api.ObjectMeta{Name: "deleted", DeletionTimestamp: &now}}
+	nm.store.Add(active)
+	nm.store.Add(deleted)
+
+	synced := []string{}
+	nm.syncHandler = func(namespace api.Namespace) error {
+		synced = append(synced, namespace.Name)
+		return nil
+	}
+	nm.enqueueNamespace(active)
+	nm.enqueueNamespace(deleted)
+	if e, a := 1, nm.queue.Len(); e != a {
+		t.Fatalf("Expected %d queued namespaces, got %d", e, a)
+	}
+	nm.processNextWorkItem()
+	if len(synced) != 1 || synced[0] != "deleted" {
+		t.Errorf("Expected only the deleted namespace to be synced, got %v", synced)
+	}
+}
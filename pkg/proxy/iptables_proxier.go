@@ -0,0 +1,538 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"crypto/sha256"
+	"encoding/base32"
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/types"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/iptables"
+	"github.com/golang/glog"
+)
+
+// iptablesServicesChain holds the portal rules of every service.  It is jumped
+// to from both PREROUTING (traffic from containers) and OUTPUT (traffic from
+// the host), and jumps in turn to one chain per service port.
+var iptablesServicesChain iptables.Chain = "KUBE-SERVICES"
+
+// The prefixes of the names of the chains of service ports and of their endpoints.
+const (
+	iptablesServicePortChainPrefix = "KUBE-SVC-"
+	iptablesEndpointChainPrefix    = "KUBE-SEP-"
+)
+
+// iptablesMasqueradeMark marks the connections which must be SNATed on their
+// way out, e.g. when an endpoint reaches itself through its service portal.
+const iptablesMasqueradeMark = "0x4d415351"
+
+type iptablesServiceInfo struct {
+	portalIP            net.IP
+	portalPort          int
+	protocol            api.Protocol
+	publicIPs           []string
+	sessionAffinityType api.AffinityType
+	stickyMaxAgeSeconds int
+}
+
+// IptablesProxier is a proxier which programs iptables to DNAT the traffic sent
+// to a service portal directly to one of the service endpoints.  Unlike Proxier,
+// it never copies traffic through userspace.  Because of the iptables logic, it
+// is assumed that there is only a single proxier active on a machine.
+type IptablesProxier struct {
+	mu                          sync.Mutex // protects the fields below
+	serviceMap                  map[ServicePortName]*iptablesServiceInfo
+	endpointsMap                map[ServicePortName][]string
+	haveReceivedServiceUpdate   bool
+	haveReceivedEndpointsUpdate bool
+	// the rules last written to the chains owned by this proxier, indexed by chain
+	programmedChains map[iptables.Chain][][]string
+	// the rules last written to iptablesServicesChain
+	programmedServices [][]string
+	iptables           iptables.Interface
+}
+
+// NewIptablesProxier returns a new IptablesProxier programming the given iptables.
+// Rules are only written once both services and endpoints have been received.
+func NewIptablesProxier(ipt iptables.Interface) *IptablesProxier {
+	glog.Infof("Initializing iptables")
+	// The userspace mode may have run before, and its portals would take precedence.
+	if err := iptablesCleanupPortals(ipt); err != nil {
+		glog.Errorf("Failed to remove the portals of the userspace proxy mode: %v", err)
+		return nil
+	}
+	if err := iptablesProxyInit(ipt); err != nil {
+		glog.Errorf("Failed to initialize iptables: %v", err)
+		return nil
+	}
+	// Flush the portals left behind by a previous run, they are recreated on the first sync.
+	if err := ipt.FlushChain(iptables.TableNAT, iptablesServicesChain); err != nil {
+		glog.Errorf("Failed to flush iptables: %v", err)
+		return nil
+	}
+	// Nothing jumps to the chains of the previous run anymore.
+	if err := iptablesDeletePortChains(ipt); err != nil {
+		glog.Errorf("Failed to delete stale iptables chains: %v", err)
+		return nil
+	}
+	return &IptablesProxier{
+		serviceMap:       make(map[ServicePortName]*iptablesServiceInfo),
+		endpointsMap:     make(map[ServicePortName][]string),
+		programmedChains: make(map[iptables.Chain][][]string),
+		iptables:         ipt,
+	}
+}
+
+// SyncLoop runs periodic work.  This is expected to run as a goroutine or as the main loop of the app.  It does not return.
+func (proxier *IptablesProxier) SyncLoop() {
+	for {
+		select {
+		case <-time.After(syncInterval):
+			glog.V(2).Infof("Periodic sync")
+			proxier.mu.Lock()
+			proxier.syncProxyRules(true)
+			proxier.mu.Unlock()
+		}
+	}
+}
+
+// OnServiceUpdate records the portals of the given services and reprograms iptables.
+func (proxier *IptablesProxier) OnServiceUpdate(services []api.Service) {
+	glog.V(4).Infof("Received service update notice: %+v", services)
+	serviceMap := make(map[ServicePortName]*iptablesServiceInfo)
+	for i := range services {
+		service := &services[i]
+
+		// if PortalIP is "None" or empty, skip proxying
+		if !api.IsServiceIPSet(service) {
+			glog.V(3).Infof("Skipping service %s due to portal IP = %q", types.NamespacedName{Namespace: service.Namespace, Name: service.Name}, service.Spec.PortalIP)
+			continue
+		}
+
+		for j := range service.Spec.Ports {
+			servicePort := &service.Spec.Ports[j]
+			serviceName := ServicePortName{types.NamespacedName{Namespace: service.Namespace, Name: service.Name}, servicePort.Name}
+			serviceMap[serviceName] = &iptablesServiceInfo{
+				portalIP:            net.ParseIP(service.Spec.PortalIP),
+				portalPort:          servicePort.Port,
+				protocol:            servicePort.Protocol,
+				publicIPs:           service.Spec.PublicIPs,
+				sessionAffinityType: service.Spec.SessionAffinity,
+				// TODO: paramaterize this in the types api file as an attribute of sticky session.   For now it's hardcoded to 3 hours.
+				stickyMaxAgeSeconds: 180 * 60,
+			}
+		}
+	}
+
+	proxier.mu.Lock()
+	defer proxier.mu.Unlock()
+	proxier.serviceMap = serviceMap
+	proxier.haveReceivedServiceUpdate = true
+	proxier.syncProxyRules(false)
+}
+
+// OnEndpointsUpdate records the endpoints of every service port and reprograms iptables.
+func (proxier *IptablesProxier) OnEndpointsUpdate(allEndpoints []api.Endpoints) {
+	glog.V(4).Infof("Received endpoints update notice: %+v", allEndpoints)
+	endpointsMap := make(map[ServicePortName][]string)
+	for i := range allEndpoints {
+		svcEndpoints := &allEndpoints[i]
+
+		// We need to build a map of portname -> all ip:ports for that
+		// portname.  Explode Endpoints.Subsets[*] into this structure.
+		portsToEndpoints := map[string][]hostPortPair{}
+		for j := range svcEndpoints.Subsets {
+			ss := &svcEndpoints.Subsets[j]
+			for k := range ss.Ports {
+				port := &ss.Ports[k]
+				for l := range ss.Addresses {
+					addr := &ss.Addresses[l]
+					portsToEndpoints[port.Name] = append(portsToEndpoints[port.Name], hostPortPair{addr.IP, port.Port})
+				}
+			}
+		}
+
+		for portname := range portsToEndpoints {
+			svcPort := ServicePortName{types.NamespacedName{Namespace: svcEndpoints.Namespace, Name: svcEndpoints.Name}, portname}
+			endpoints := flattenValidEndpoints(portsToEndpoints[portname])
+			// Keep the endpoints in a stable order so that unchanged chains are not rewritten.
+			sort.Strings(endpoints)
+			endpointsMap[svcPort] = endpoints
+		}
+	}
+
+	proxier.mu.Lock()
+	defer proxier.mu.Unlock()
+	proxier.endpointsMap = endpointsMap
+	proxier.haveReceivedEndpointsUpdate = true
+	proxier.syncProxyRules(false)
+}
+
+// syncProxyRules writes the iptables rules for the current services and endpoints.
+// Chains whose rules did not change are left untouched, unless verify is set, in which
+// case they are checked and rewritten if they no longer hold the expected rules.
+// This assumes proxier.mu is locked.
+func (proxier *IptablesProxier) syncProxyRules(verify bool) {
+	// Writing the rules before both are known would drop the traffic of every service.
+	if !proxier.haveReceivedServiceUpdate || !proxier.haveReceivedEndpointsUpdate {
+		glog.V(2).Infof("Not syncing iptables until services and endpoints have been received")
+		return
+	}
+	if err := iptablesProxyInit(proxier.iptables); err != nil {
+		glog.Errorf("Failed to ensure iptables: %v", err)
+		return
+	}
+
+	names := []ServicePortName{}
+	for name := range proxier.serviceMap {
+		names = append(names, name)
+	}
+	sort.Sort(servicePortNames(names))
+
+	chains := map[iptables.Chain][][]string{}
+	servicesRules := [][]string{}
+	for _, name := range names {
+		info := proxier.serviceMap[name]
+		endpoints := proxier.endpointsMap[name]
+		if len(endpoints) == 0 {
+			// Without a portal rule the traffic is simply not DNATed.
+			glog.V(3).Infof("No endpoints for service %q, not opening its portal", name)
+			continue
+		}
+
+		endpointChains := []iptables.Chain{}
+		affinityNames := []string{}
+		for _, endpoint := range endpoints {
+			affinityName := servicePortEndpointAffinityName(name, info.protocol, endpoint)
+			rules := iptablesEndpointRules(endpoint, info, name, affinityName)
+			endpointChain := iptables.Chain(iptablesEndpointChainPrefix + hashChainRules(rules))
+			endpointChains = append(endpointChains, endpointChain)
+			affinityNames = append(affinityNames, affinityName)
+			chains[endpointChain] = rules
+		}
+		rules := iptablesServiceRules(endpointChains, affinityNames, info, name)
+		svcChain := iptables.Chain(iptablesServicePortChainPrefix + hashChainRules(rules))
+		chains[svcChain] = rules
+
+		servicesRules = append(servicesRules, iptablesServicePortalArgs(info.portalIP, info.portalPort, info.protocol, name, svcChain))
+		for _, publicIP := range info.publicIPs {
+			servicesRules = append(servicesRules, iptablesServicePortalArgs(net.ParseIP(publicIP), info.portalPort, info.protocol, name, svcChain))
+		}
+	}
+
+	// Write the per-service chains before the portals which jump to them.  The chains are
+	// named after their rules, so changed rules are written to a new chain while the old
+	// one keeps serving the traffic until nothing jumps to it anymore.
+	for chain, rules := range chains {
+		if _, found := proxier.programmedChains[chain]; found {
+			if !verify || proxier.chainIntact(chain, rules) {
+				continue
+			}
+			glog.Warningf("iptables chain %s was modified, rewriting it", chain)
+		}
+		if err := proxier.writeChain(chain, rules); err != nil {
+			glog.Errorf("Failed to write iptables chain %s: %v", chain, err)
+			delete(proxier.programmedChains, chain)
+			continue
+		}
+		proxier.programmedChains[chain] = rules
+	}
+
+	// The portal rules do not depend on each other, so new ones are added before the
+	// stale ones are removed and no traffic is lost while a service is updated, including
+	// when its portals switch to a new chain.
+	for _, rule := range servicesRules {
+		if _, err := proxier.iptables.EnsureRule(iptables.TableNAT, iptablesServicesChain, rule...); err != nil {
+			glog.Errorf("Failed to install iptables %s rule %v: %v", iptablesServicesChain, rule, err)
+		}
+	}
+	for _, rule := range proxier.programmedServices {
+		if containsRule(servicesRules, rule) {
+			continue
+		}
+		if err := proxier.iptables.DeleteRule(iptables.TableNAT, iptablesServicesChain, rule...); err != nil {
+			glog.Errorf("Failed to delete iptables %s rule %v: %v", iptablesServicesChain, rule, err)
+		}
+	}
+	proxier.programmedServices = servicesRules
+
+	// Nothing but other stale chains jumps to the chains of removed or changed services
+	// and endpoints anymore, so all of them are flushed before any of them is deleted.
+	stale := []iptables.Chain{}
+	for chain := range proxier.programmedChains {
+		if _, found := chains[chain]; found {
+			continue
+		}
+		if err := proxier.iptables.FlushChain(iptables.TableNAT, chain); err != nil {
+			glog.Errorf("Failed to flush iptables chain %s: %v", chain, err)
+		}
+		stale = append(stale, chain)
+	}
+	for _, chain := range stale {
+		if err := proxier.iptables.DeleteChain(iptables.TableNAT, chain); err != nil {
+			glog.Errorf("Failed to delete iptables chain %s: %v", chain, err)
+		}
+		delete(proxier.programmedChains, chain)
+	}
+}
+
+// writeChain replaces the rules of the given chain, creating it if needed.  The chain
+// is briefly empty, so it must not be jumped to yet, unless it is being repaired.
+func (proxier *IptablesProxier) writeChain(chain iptables.Chain, rules [][]string) error {
+	if _, err := proxier.iptables.EnsureChain(iptables.TableNAT, chain); err != nil {
+		return err
+	}
+	if err := proxier.iptables.FlushChain(iptables.TableNAT, chain); err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		if _, err := proxier.iptables.EnsureRule(iptables.TableNAT, chain, rule...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// chainIntact returns true if the given chain exists and holds exactly the given rules,
+// in order.  iptables lists rules in a normalized form, so each rule is checked with
+// iptables itself, and the listing is only compared on the matches and target of each
+// rule, which iptables lists in the order they were given.
+func (proxier *IptablesProxier) chainIntact(chain iptables.Chain, rules [][]string) bool {
+	existed, err := proxier.iptables.EnsureChain(iptables.TableNAT, chain)
+	if err != nil || !existed {
+		return false
+	}
+	listed, err := proxier.iptables.ListRules(iptables.TableNAT, chain)
+	if err != nil || len(listed) != len(rules) {
+		return false
+	}
+	for i, rule := range rules {
+		if ruleShape(listed[i]) != ruleShape(rule) {
+			return false
+		}
+		existed, err := proxier.iptables.EnsureRule(iptables.TableNAT, chain, rule...)
+		if err != nil || !existed {
+			return false
+		}
+	}
+	return true
+}
+
+// ruleShape returns the match modules and the target of a rule.
+func ruleShape(args []string) string {
+	shape := []string{}
+	for i := 0; i+1 < len(args); i++ {
+		if args[i] == "-m" || args[i] == "-j" {
+			shape = append(shape, args[i+1])
+		}
+	}
+	return strings.Join(shape, " ")
+}
+
+// Ensure that the iptables infrastructure used by IptablesProxier is set up.  This can safely be called periodically.
+func iptablesProxyInit(ipt iptables.Interface) error {
+	if _, err := ipt.EnsureChain(iptables.TableNAT, iptablesServicesChain); err != nil {
+		return err
+	}
+	if _, err := ipt.EnsureRule(iptables.TableNAT, iptables.ChainPrerouting, "-j", string(iptablesServicesChain)); err != nil {
+		return err
+	}
+	if _, err := ipt.EnsureRule(iptables.TableNAT, iptables.ChainOutput, "-j", string(iptablesServicesChain)); err != nil {
+		return err
+	}
+	if _, err := ipt.EnsureRule(iptables.TableNAT, iptables.ChainPostrouting, iptablesMasqueradeArgs()...); err != nil {
+		return err
+	}
+	return nil
+}
+
+// iptablesMasqueradeArgs returns the args of the rule SNATing the marked connections.
+func iptablesMasqueradeArgs() []string {
+	return []string{
+		"-m", "comment", "--comment", "kubernetes service traffic requiring SNAT",
+		"-m", "mark", "--mark", iptablesMasqueradeMark,
+		"-j", "MASQUERADE",
+	}
+}
+
+// CleanupIptablesProxyRules removes the rules and chains written by IptablesProxier, which
+// would otherwise keep DNATing the traffic of services once the proxy runs in another mode.
+func CleanupIptablesProxyRules(ipt iptables.Interface) error {
+	chains, err := ipt.ListChains(iptables.TableNAT)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, chain := range chains {
+		if chain == iptablesServicesChain {
+			found = true
+		}
+	}
+	if !found {
+		return nil
+	}
+	for _, chain := range []iptables.Chain{iptables.ChainPrerouting, iptables.ChainOutput} {
+		if err := ipt.DeleteRule(iptables.TableNAT, chain, "-j", string(iptablesServicesChain)); err != nil {
+			return err
+		}
+	}
+	if err := ipt.DeleteRule(iptables.TableNAT, iptables.ChainPostrouting, iptablesMasqueradeArgs()...); err != nil {
+		return err
+	}
+	if err := ipt.FlushChain(iptables.TableNAT, iptablesServicesChain); err != nil {
+		return err
+	}
+	if err := iptablesDeletePortChains(ipt); err != nil {
+		return err
+	}
+	return ipt.DeleteChain(iptables.TableNAT, iptablesServicesChain)
+}
+
+// iptablesDeletePortChains deletes every service port and endpoint chain, whichever run of
+// the proxier wrote them.  Nothing may jump to them anymore but themselves.
+func iptablesDeletePortChains(ipt iptables.Interface) error {
+	chains, err := ipt.ListChains(iptables.TableNAT)
+	if err != nil {
+		return err
+	}
+	portChains := []iptables.Chain{}
+	for _, chain := range chains {
+		if strings.HasPrefix(string(chain), iptablesServicePortChainPrefix) || strings.HasPrefix(string(chain), iptablesEndpointChainPrefix) {
+			portChains = append(portChains, chain)
+		}
+	}
+	// The service port chains jump to the endpoint chains, so all of them are flushed
+	// before any of them is deleted.
+	for _, chain := range portChains {
+		if err := ipt.FlushChain(iptables.TableNAT, chain); err != nil {
+			return err
+		}
+	}
+	for _, chain := range portChains {
+		if err := ipt.DeleteChain(iptables.TableNAT, chain); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Build a slice of iptables args for a portal rule, jumping to the chain of the service port.
+func iptablesServicePortalArgs(destIP net.IP, destPort int, protocol api.Protocol, service ServicePortName, svcChain iptables.Chain) []string {
+	return append(iptablesCommonPortalArgs(destIP, destPort, protocol, service), "-j", string(svcChain))
+}
+
+// Build the rules of the chain of a service port, which picks one of the endpoint chains.
+// Each endpoint is picked with a probability of 1/(number of endpoints left), so that
+// all of them are equally likely.  With ClientIP affinity, a client which recently
+// reached an endpoint, as recorded under the matching affinity name, is sent to it again.
+func iptablesServiceRules(endpointChains []iptables.Chain, affinityNames []string, info *iptablesServiceInfo, service ServicePortName) [][]string {
+	rules := [][]string{}
+	if info.sessionAffinityType == api.AffinityTypeClientIP {
+		for i, endpointChain := range endpointChains {
+			rules = append(rules, []string{
+				"-m", "comment", "--comment", service.String(),
+				"-m", "recent", "--name", affinityNames[i],
+				"--rcheck", "--seconds", strconv.Itoa(info.stickyMaxAgeSeconds), "--reap",
+				"-j", string(endpointChain),
+			})
+		}
+	}
+	n := len(endpointChains)
+	for i, endpointChain := range endpointChains {
+		args := []string{"-m", "comment", "--comment", service.String()}
+		if i < n-1 {
+			args = append(args,
+				"-m", "statistic",
+				"--mode", "random",
+				"--probability", fmt.Sprintf("%0.5f", 1.0/float64(n-i)))
+		}
+		rules = append(rules, append(args, "-j", string(endpointChain)))
+	}
+	return rules
+}
+
+// Build the rules of the chain of an endpoint, which DNATs the traffic to it.  With ClientIP
+// affinity, the client is recorded under the given affinity name.
+func iptablesEndpointRules(endpoint string, info *iptablesServiceInfo, service ServicePortName, affinityName string) [][]string {
+	protocol := strings.ToLower(string(info.protocol))
+	host, _, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		host = endpoint
+	}
+	// Traffic from the endpoint to itself must be SNATed, or the replies would bypass the DNAT.
+	hairpin := []string{
+		"-m", "comment", "--comment", service.String(),
+		"-s", fmt.Sprintf("%s/32", host),
+		"-j", "MARK", "--set-xmark", iptablesMasqueradeMark + "/0xffffffff",
+	}
+	dnat := []string{"-m", "comment", "--comment", service.String()}
+	if info.sessionAffinityType == api.AffinityTypeClientIP {
+		dnat = append(dnat, "-m", "recent", "--name", affinityName, "--set")
+	}
+	dnat = append(dnat,
+		"-p", protocol,
+		"-m", protocol,
+		"-j", "DNAT", "--to-destination", endpoint)
+	return [][]string{hairpin, dnat}
+}
+
+func containsRule(rules [][]string, rule []string) bool {
+	for i := range rules {
+		if reflect.DeepEqual(rules[i], rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// servicePortEndpointAffinityName returns the name under which the clients of an endpoint
+// of a service port are recorded.  Unlike the name of the endpoint chain, it does not
+// change with the rules, so clients keep their endpoint when the service is updated.
+func servicePortEndpointAffinityName(service ServicePortName, protocol api.Protocol, endpoint string) string {
+	return "KUBE-AFF-" + hashChainName(service.String()+string(protocol)+endpoint)
+}
+
+// hashChainRules returns the part of the name of a chain derived from its rules.  Chain
+// names are limited to 28 characters, so the rules are hashed.  The rules of service port
+// and endpoint chains always include the name of the service port.
+func hashChainRules(rules [][]string) string {
+	lines := []string{}
+	for _, rule := range rules {
+		lines = append(lines, strings.Join(rule, " "))
+	}
+	return hashChainName(strings.Join(lines, "\n"))
+}
+
+func hashChainName(s string) string {
+	hash := sha256.Sum256([]byte(s))
+	return base32.StdEncoding.EncodeToString(hash[:])[:16]
+}
+
+type servicePortNames []ServicePortName
+
+func (s servicePortNames) Len() int           { return len(s) }
+func (s servicePortNames) Less(i, j int) bool { return s[i].String() < s[j].String() }
+func (s servicePortNames) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
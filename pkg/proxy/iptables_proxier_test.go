@@ -0,0 +1,366 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxy
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/types"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/iptables"
+)
+
+func makeIptablesTestService(affinity api.AffinityType, publicIPs ...string) api.Service {
+	return api.Service{
+		ObjectMeta: api.ObjectMeta{Namespace: "testnamespace", Name: "echo"},
+		Spec: api.ServiceSpec{
+			PortalIP:        "10.0.0.10",
+			PublicIPs:       publicIPs,
+			SessionAffinity: affinity,
+			Ports:           []api.ServicePort{{Name: "p", Port: 80, Protocol: api.ProtocolTCP}},
+		},
+	}
+}
+
+func makeIptablesTestEndpoints(ips ...string) api.Endpoints {
+	addresses := []api.EndpointAddress{}
+	for _, ip := range ips {
+		addresses = append(addresses, api.EndpointAddress{IP: ip})
+	}
+	return api.Endpoints{
+		ObjectMeta: api.ObjectMeta{Namespace: "testnamespace", Name: "echo"},
+		Subsets: []api.EndpointSubset{{
+			Addresses: addresses,
+			Ports:     []api.EndpointPort{{Name: "p", Port: 8080}},
+		}},
+	}
+}
+
+var iptablesTestServiceName = ServicePortName{types.NamespacedName{Namespace: "testnamespace", Name: "echo"}, "p"}
+
+func newTestIptablesProxier(t *testing.T) (*IptablesProxier, *iptables.FakeIPTables) {
+	fake := iptables.NewFake()
+	proxier := NewIptablesProxier(fake)
+	if proxier == nil {
+		t.Fatalf("failed to create the iptables proxier")
+	}
+	return proxier, fake
+}
+
+// jumpTargets returns the chains the given rules jump to, in order.
+func jumpTargets(rules []string) []iptables.Chain {
+	chains := []iptables.Chain{}
+	for _, rule := range rules {
+		fields := strings.Fields(rule)
+		for i := 0; i+1 < len(fields); i++ {
+			if fields[i] == "-j" && strings.HasPrefix(fields[i+1], "KUBE-") {
+				chains = append(chains, iptables.Chain(fields[i+1]))
+			}
+		}
+	}
+	return chains
+}
+
+// testServiceChain returns the chain the portal of the test service jumps to.
+func testServiceChain(t *testing.T, fake *iptables.FakeIPTables) iptables.Chain {
+	chains := jumpTargets(fake.Rules(iptables.TableNAT, iptablesServicesChain))
+	if len(chains) == 0 {
+		t.Fatalf("expected a portal")
+	}
+	return chains[0]
+}
+
+func TestIptablesProxierInit(t *testing.T) {
+	_, fake := newTestIptablesProxier(t)
+	for _, chain := range []iptables.Chain{iptables.ChainPrerouting, iptables.ChainOutput} {
+		if rules := fake.Rules(iptables.TableNAT, chain); !reflect.DeepEqual(rules, []string{"-j KUBE-SERVICES"}) {
+			t.Errorf("expected %s to jump to the services chain, got %v", chain, rules)
+		}
+	}
+	if rules := fake.Rules(iptables.TableNAT, iptables.ChainPostrouting); len(rules) != 1 || !strings.Contains(rules[0], "MASQUERADE") {
+		t.Errorf("expected a masquerade rule, got %v", rules)
+	}
+}
+
+func TestIptablesProxierWaitsForServicesAndEndpoints(t *testing.T) {
+	proxier, fake := newTestIptablesProxier(t)
+	proxier.OnServiceUpdate([]api.Service{makeIptablesTestService(api.AffinityTypeNone)})
+	if rules := fake.Rules(iptables.TableNAT, iptablesServicesChain); len(rules) != 0 {
+		t.Errorf("expected no portal before endpoints are received, got %v", rules)
+	}
+	proxier.OnEndpointsUpdate([]api.Endpoints{makeIptablesTestEndpoints("1.2.3.4")})
+	if rules := fake.Rules(iptables.TableNAT, iptablesServicesChain); len(rules) != 1 {
+		t.Errorf("expected a portal once endpoints are received, got %v", rules)
+	}
+}
+
+func TestIptablesProxierPortals(t *testing.T) {
+	proxier, fake := newTestIptablesProxier(t)
+	proxier.OnEndpointsUpdate([]api.Endpoints{makeIptablesTestEndpoints("1.2.3.5", "1.2.3.4")})
+	proxier.OnServiceUpdate([]api.Service{makeIptablesTestService(api.AffinityTypeNone, "5.6.7.8")})
+
+	svcChain := testServiceChain(t, fake)
+	expectedPortals := []string{
+		"-m comment --comment testnamespace/echo:p -p tcp -m tcp -d 10.0.0.10/32 --dport 80 -j " + string(svcChain),
+		"-m comment --comment testnamespace/echo:p -p tcp -m tcp -d 5.6.7.8/32 --dport 80 -j " + string(svcChain),
+	}
+	if rules := fake.Rules(iptables.TableNAT, iptablesServicesChain); !reflect.DeepEqual(rules, expectedPortals) {
+		t.Errorf("expected portals %v, got %v", expectedPortals, rules)
+	}
+
+	// endpoints are sorted, and each one is picked with the same probability
+	endpointChains := jumpTargets(fake.Rules(iptables.TableNAT, svcChain))
+	if len(endpointChains) != 2 {
+		t.Fatalf("expected two endpoint chains, got %v", endpointChains)
+	}
+	firstChain, secondChain := endpointChains[0], endpointChains[1]
+	expectedService := []string{
+		"-m comment --comment testnamespace/echo:p -m statistic --mode random --probability 0.50000 -j " + string(firstChain),
+		"-m comment --comment testnamespace/echo:p -j " + string(secondChain),
+	}
+	if rules := fake.Rules(iptables.TableNAT, svcChain); !reflect.DeepEqual(rules, expectedService) {
+		t.Errorf("expected service rules %v, got %v", expectedService, rules)
+	}
+
+	expectedEndpoint := []string{
+		"-m comment --comment testnamespace/echo:p -s 1.2.3.4/32 -j MARK --set-xmark 0x4d415351/0xffffffff",
+		"-m comment --comment testnamespace/echo:p -p tcp -m tcp -j DNAT --to-destination 1.2.3.4:8080",
+	}
+	if rules := fake.Rules(iptables.TableNAT, firstChain); !reflect.DeepEqual(rules, expectedEndpoint) {
+		t.Errorf("expected endpoint rules %v, got %v", expectedEndpoint, rules)
+	}
+}
+
+func TestIptablesProxierClientIPAffinity(t *testing.T) {
+	proxier, fake := newTestIptablesProxier(t)
+	proxier.OnEndpointsUpdate([]api.Endpoints{makeIptablesTestEndpoints("1.2.3.4")})
+	proxier.OnServiceUpdate([]api.Service{makeIptablesTestService(api.AffinityTypeClientIP)})
+
+	svcChain := testServiceChain(t, fake)
+	endpointChains := jumpTargets(fake.Rules(iptables.TableNAT, svcChain))
+	if len(endpointChains) != 2 {
+		t.Fatalf("expected two jumps to the endpoint chain, got %v", endpointChains)
+	}
+	endpointChain := endpointChains[0]
+	affinityName := servicePortEndpointAffinityName(iptablesTestServiceName, api.ProtocolTCP, "1.2.3.4:8080")
+	expectedService := []string{
+		"-m comment --comment testnamespace/echo:p -m recent --name " + affinityName + " --rcheck --seconds 10800 --reap -j " + string(endpointChain),
+		"-m comment --comment testnamespace/echo:p -j " + string(endpointChain),
+	}
+	if rules := fake.Rules(iptables.TableNAT, svcChain); !reflect.DeepEqual(rules, expectedService) {
+		t.Errorf("expected service rules %v, got %v", expectedService, rules)
+	}
+	rules := fake.Rules(iptables.TableNAT, endpointChain)
+	if len(rules) != 2 || !strings.Contains(rules[1], "-m recent --name "+affinityName+" --set") {
+		t.Errorf("expected the endpoint to record the client, got %v", rules)
+	}
+}
+
+func TestIptablesProxierRemovesStaleRules(t *testing.T) {
+	proxier, fake := newTestIptablesProxier(t)
+	proxier.OnEndpointsUpdate([]api.Endpoints{makeIptablesTestEndpoints("1.2.3.4", "1.2.3.5")})
+	proxier.OnServiceUpdate([]api.Service{makeIptablesTestService(api.AffinityTypeNone)})
+
+	oldSvcChain := testServiceChain(t, fake)
+	removedChain := jumpTargets(fake.Rules(iptables.TableNAT, oldSvcChain))[1]
+	proxier.OnEndpointsUpdate([]api.Endpoints{makeIptablesTestEndpoints("1.2.3.4")})
+	if fake.HasChain(iptables.TableNAT, removedChain) {
+		t.Errorf("expected the chain of the removed endpoint to be deleted")
+	}
+	if fake.HasChain(iptables.TableNAT, oldSvcChain) {
+		t.Errorf("expected the replaced service chain to be deleted")
+	}
+	svcChain := testServiceChain(t, fake)
+	if rules := fake.Rules(iptables.TableNAT, svcChain); len(rules) != 1 {
+		t.Errorf("expected a single endpoint, got %v", rules)
+	}
+
+	// a service without endpoints has no portal
+	proxier.OnEndpointsUpdate([]api.Endpoints{})
+	if rules := fake.Rules(iptables.TableNAT, iptablesServicesChain); len(rules) != 0 {
+		t.Errorf("expected no portal, got %v", rules)
+	}
+	if fake.HasChain(iptables.TableNAT, svcChain) {
+		t.Errorf("expected the service chain to be deleted")
+	}
+}
+
+func TestIptablesProxierVerifyRewritesModifiedChains(t *testing.T) {
+	proxier, fake := newTestIptablesProxier(t)
+	proxier.OnEndpointsUpdate([]api.Endpoints{makeIptablesTestEndpoints("1.2.3.4", "1.2.3.5")})
+	proxier.OnServiceUpdate([]api.Service{makeIptablesTestService(api.AffinityTypeNone)})
+
+	svcChain := testServiceChain(t, fake)
+	expected := fake.Rules(iptables.TableNAT, svcChain)
+	if err := fake.FlushChain(iptables.TableNAT, svcChain); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	proxier.mu.Lock()
+	proxier.syncProxyRules(true)
+	proxier.mu.Unlock()
+	if rules := fake.Rules(iptables.TableNAT, svcChain); !reflect.DeepEqual(rules, expected) {
+		t.Errorf("expected rules %v to be restored, got %v", expected, rules)
+	}
+}
+
+func TestIptablesProxierVerifyRewritesChainsWithExtraRules(t *testing.T) {
+	proxier, fake := newTestIptablesProxier(t)
+	proxier.OnEndpointsUpdate([]api.Endpoints{makeIptablesTestEndpoints("1.2.3.4", "1.2.3.5")})
+	proxier.OnServiceUpdate([]api.Service{makeIptablesTestService(api.AffinityTypeNone)})
+
+	svcChain := testServiceChain(t, fake)
+	expected := fake.Rules(iptables.TableNAT, svcChain)
+	fake.Chains[iptables.TableNAT][svcChain] = append([]string{"-j DROP"}, expected...)
+
+	proxier.mu.Lock()
+	proxier.syncProxyRules(true)
+	proxier.mu.Unlock()
+	if rules := fake.Rules(iptables.TableNAT, svcChain); !reflect.DeepEqual(rules, expected) {
+		t.Errorf("expected rules %v to be restored, got %v", expected, rules)
+	}
+}
+
+func TestIptablesProxierVerifyRewritesReorderedChains(t *testing.T) {
+	proxier, fake := newTestIptablesProxier(t)
+	proxier.OnEndpointsUpdate([]api.Endpoints{makeIptablesTestEndpoints("1.2.3.4", "1.2.3.5")})
+	proxier.OnServiceUpdate([]api.Service{makeIptablesTestService(api.AffinityTypeNone)})
+
+	svcChain := testServiceChain(t, fake)
+	expected := fake.Rules(iptables.TableNAT, svcChain)
+	fake.Chains[iptables.TableNAT][svcChain] = []string{expected[1], expected[0]}
+
+	proxier.mu.Lock()
+	proxier.syncProxyRules(true)
+	proxier.mu.Unlock()
+	if rules := fake.Rules(iptables.TableNAT, svcChain); !reflect.DeepEqual(rules, expected) {
+		t.Errorf("expected rules %v to be restored, got %v", expected, rules)
+	}
+}
+
+func TestIptablesProxierDeletesChainsOfPreviousRun(t *testing.T) {
+	fake := iptables.NewFake()
+	stale := []iptables.Chain{"KUBE-SVC-STALE", "KUBE-SEP-STALE"}
+	for _, chain := range stale {
+		fake.EnsureChain(iptables.TableNAT, chain)
+	}
+	fake.EnsureRule(iptables.TableNAT, "KUBE-SVC-STALE", "-j", "KUBE-SEP-STALE")
+	fake.EnsureChain(iptables.TableNAT, "OTHER")
+
+	if NewIptablesProxier(fake) == nil {
+		t.Fatalf("failed to create the iptables proxier")
+	}
+	for _, chain := range stale {
+		if fake.HasChain(iptables.TableNAT, chain) {
+			t.Errorf("expected chain %s of the previous run to be deleted", chain)
+		}
+	}
+	if !fake.HasChain(iptables.TableNAT, "OTHER") {
+		t.Errorf("expected chains not owned by the proxier to be kept")
+	}
+}
+
+func TestIptablesProxierDeletesUserspacePortals(t *testing.T) {
+	fake := iptables.NewFake()
+	if err := iptablesInit(fake); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fake.EnsureRule(iptables.TableNAT, iptablesContainerPortalChain, "-p", "tcp", "-d", "1.2.3.4/32", "--dport", "80", "-j", "REDIRECT", "--to-ports", "34567")
+	fake.EnsureRule(iptables.TableNAT, iptablesHostPortalChain, "-p", "tcp", "-d", "1.2.3.4/32", "--dport", "80", "-j", "DNAT", "--to-destination", "10.0.0.1:34567")
+
+	if NewIptablesProxier(fake) == nil {
+		t.Fatalf("failed to create the iptables proxier")
+	}
+	for _, chain := range []iptables.Chain{iptablesContainerPortalChain, iptablesHostPortalChain} {
+		if fake.HasChain(iptables.TableNAT, chain) {
+			t.Errorf("expected userspace portal chain %s to be deleted", chain)
+		}
+	}
+	expected := []string{"-j " + string(iptablesServicesChain)}
+	for _, chain := range []iptables.Chain{iptables.ChainPrerouting, iptables.ChainOutput} {
+		if rules := fake.Rules(iptables.TableNAT, chain); !reflect.DeepEqual(rules, expected) {
+			t.Errorf("expected %v in %s, got %v", expected, chain, rules)
+		}
+	}
+}
+
+func TestCleanupIptablesProxyRules(t *testing.T) {
+	proxier, fake := newTestIptablesProxier(t)
+	proxier.OnEndpointsUpdate([]api.Endpoints{makeIptablesTestEndpoints("1.2.3.4")})
+	proxier.OnServiceUpdate([]api.Service{makeIptablesTestService(api.AffinityTypeNone)})
+
+	if err := CleanupIptablesProxyRules(fake); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, chain := range []iptables.Chain{iptables.ChainPrerouting, iptables.ChainOutput, iptables.ChainPostrouting} {
+		if rules := fake.Rules(iptables.TableNAT, chain); len(rules) != 0 {
+			t.Errorf("expected no rule left in %s, got %v", chain, rules)
+		}
+	}
+	if chains, _ := fake.ListChains(iptables.TableNAT); len(chains) != 0 {
+		t.Errorf("expected no chain left, got %v", chains)
+	}
+
+	// Nothing to clean up.
+	if err := CleanupIptablesProxyRules(iptables.NewFake()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// flushCheckingIPTables fails the test if a chain which the portals lead to is flushed.
+type flushCheckingIPTables struct {
+	*iptables.FakeIPTables
+	t *testing.T
+}
+
+func (f *flushCheckingIPTables) FlushChain(table iptables.Table, chain iptables.Chain) error {
+	reachable := map[iptables.Chain]bool{}
+	pending := []iptables.Chain{iptablesServicesChain}
+	for len(pending) > 0 {
+		next := pending[0]
+		pending = pending[1:]
+		for _, target := range jumpTargets(f.Rules(table, next)) {
+			if !reachable[target] {
+				reachable[target] = true
+				pending = append(pending, target)
+			}
+		}
+	}
+	if reachable[chain] {
+		f.t.Errorf("chain %s was flushed while traffic could reach it", chain)
+	}
+	return f.FakeIPTables.FlushChain(table, chain)
+}
+
+func TestIptablesProxierNeverFlushesLiveChains(t *testing.T) {
+	fake := &flushCheckingIPTables{iptables.NewFake(), t}
+	proxier := NewIptablesProxier(fake)
+	if proxier == nil {
+		t.Fatalf("failed to create the iptables proxier")
+	}
+	proxier.OnServiceUpdate([]api.Service{makeIptablesTestService(api.AffinityTypeNone)})
+	for _, endpoints := range [][]string{{"1.2.3.4"}, {"1.2.3.4", "1.2.3.5"}, {"1.2.3.5"}, {}} {
+		proxier.OnEndpointsUpdate([]api.Endpoints{makeIptablesTestEndpoints(endpoints...)})
+	}
+	proxier.OnEndpointsUpdate([]api.Endpoints{makeIptablesTestEndpoints("1.2.3.4")})
+	proxier.OnServiceUpdate([]api.Service{makeIptablesTestService(api.AffinityTypeClientIP)})
+	if chains, _ := fake.ListChains(iptables.TableNAT); len(chains) != 3 {
+		t.Errorf("expected the services chain, a service chain and an endpoint chain, got %v", chains)
+	}
+}
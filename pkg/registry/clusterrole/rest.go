@@ -0,0 +1,83 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterrole
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/validation"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/generic"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/fielderrors"
+)
+
+// clusterroleStrategy implements behavior for ClusterRole objects
+type clusterroleStrategy struct {
+	runtime.ObjectTyper
+	api.NameGenerator
+}
+
+// Strategy is the default logic that applies when creating and updating ClusterRole
+// objects via the REST API.
+var Strategy = clusterroleStrategy{api.Scheme, api.SimpleNameGenerator}
+
+// NamespaceScoped is false for clusterroles.
+func (clusterroleStrategy) NamespaceScoped() bool {
+	return false
+}
+
+// ResetBeforeCreate clears fields that are not allowed to be set by end users on creation.
+func (clusterroleStrategy) ResetBeforeCreate(obj runtime.Object) {
+}
+
+// Validate validates a new cluster role.
+func (clusterroleStrategy) Validate(obj runtime.Object) fielderrors.ValidationErrorList {
+	return validation.ValidateClusterRole(obj.(*api.ClusterRole))
+}
+
+// AllowCreateOnUpdate is false for clusterroles.
+func (clusterroleStrategy) AllowCreateOnUpdate() bool {
+	return false
+}
+
+// ValidateUpdate is the default update validation for an end user.
+func (clusterroleStrategy) ValidateUpdate(obj, old runtime.Object) fielderrors.ValidationErrorList {
+	return validation.ValidateClusterRoleUpdate(old.(*api.ClusterRole), obj.(*api.ClusterRole))
+}
+
+// MatchClusterRole returns a generic matcher for a given label and field selector.
+func MatchClusterRole(label labels.Selector, field fields.Selector) generic.Matcher {
+	return generic.MatcherFunc(func(obj runtime.Object) (bool, error) {
+		clusterroleObj, ok := obj.(*api.ClusterRole)
+		if !ok {
+			return false, fmt.Errorf("not a cluster role")
+		}
+		fields := ClusterRoleToSelectableFields(clusterroleObj)
+		return label.Matches(labels.Set(clusterroleObj.Labels)) && field.Matches(fields), nil
+	})
+}
+
+// ClusterRoleToSelectableFields returns a label set that represents the object
+// TODO: fields are not labels, and the validation rules for them do not apply.
+func ClusterRoleToSelectableFields(clusterrole *api.ClusterRole) labels.Set {
+	return labels.Set{
+		"name": clusterrole.Name,
+	}
+}
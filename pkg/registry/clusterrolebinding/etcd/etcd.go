@@ -0,0 +1,90 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/auth/authorizer/rbac"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/clusterrolebinding"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/generic"
+	etcdgeneric "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/generic/etcd"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+)
+
+// rest implements a RESTStorage for clusterrolebindings against etcd
+type REST struct {
+	*etcdgeneric.Etcd
+	escalation rbac.EscalationChecker
+}
+
+// NewStorage returns a RESTStorage object that will work against ClusterRoleBinding objects.
+// If escalation is not nil, users may only bind roles whose rights they already hold.
+func NewStorage(h tools.EtcdHelper, escalation rbac.EscalationChecker) *REST {
+	prefix := "/registry/clusterrolebindings"
+	store := &etcdgeneric.Etcd{
+		NewFunc:     func() runtime.Object { return &api.ClusterRoleBinding{} },
+		NewListFunc: func() runtime.Object { return &api.ClusterRoleBindingList{} },
+		KeyRootFunc: func(ctx api.Context) string {
+			return prefix
+		},
+		KeyFunc: func(ctx api.Context, name string) (string, error) {
+			return prefix + "/" + name, nil
+		},
+		ObjectNameFunc: func(obj runtime.Object) (string, error) {
+			return obj.(*api.ClusterRoleBinding).Name, nil
+		},
+		PredicateFunc: func(label labels.Selector, field fields.Selector) generic.Matcher {
+			return clusterrolebinding.MatchClusterRoleBinding(label, field)
+		},
+		EndpointName: "clusterrolebindings",
+
+		Helper: h,
+	}
+
+	store.CreateStrategy = clusterrolebinding.Strategy
+	store.UpdateStrategy = clusterrolebinding.Strategy
+	store.ReturnDeletedObject = true
+
+	return &REST{store, escalation}
+}
+
+// Create checks that the user may bind the role before creating the binding.
+func (r *REST) Create(ctx api.Context, obj runtime.Object) (runtime.Object, error) {
+	if err := r.confirmNoEscalation(ctx, obj); err != nil {
+		return nil, err
+	}
+	return r.Etcd.Create(ctx, obj)
+}
+
+// Update checks that the user may bind the role before updating the binding.
+func (r *REST) Update(ctx api.Context, obj runtime.Object) (runtime.Object, bool, error) {
+	if err := r.confirmNoEscalation(ctx, obj); err != nil {
+		return nil, false, err
+	}
+	return r.Etcd.Update(ctx, obj)
+}
+
+func (r *REST) confirmNoEscalation(ctx api.Context, obj runtime.Object) error {
+	binding, ok := obj.(*api.ClusterRoleBinding)
+	if r.escalation == nil || !ok {
+		return nil
+	}
+	return r.escalation.ConfirmNoEscalation(ctx, api.NamespaceNone, binding.RoleRef)
+}
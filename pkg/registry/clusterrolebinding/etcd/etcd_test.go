@@ -0,0 +1,134 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/latest"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/rest/resttest"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/clusterrolebinding"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+
+	"github.com/coreos/go-etcd/etcd"
+)
+
+func newStorage(t *testing.T) (*REST, *tools.FakeEtcdClient, tools.EtcdHelper) {
+	fakeEtcdClient := tools.NewFakeEtcdClient(t)
+	fakeEtcdClient.TestIndex = true
+	helper := tools.NewEtcdHelper(fakeEtcdClient, latest.Codec)
+	return NewStorage(helper, nil), fakeEtcdClient, helper
+}
+
+func validNewClusterRoleBinding(name string) *api.ClusterRoleBinding {
+	return &api.ClusterRoleBinding{
+		ObjectMeta: api.ObjectMeta{
+			Name: name,
+		},
+		Subjects: []api.Subject{{Kind: api.GroupKind, Name: "admins"}},
+		RoleRef:  api.RoleRef{Kind: "ClusterRole", Name: "admin"},
+	}
+}
+
+func TestStorage(t *testing.T) {
+	storage, _, _ := newStorage(t)
+	clusterrolebinding.NewRegistry(storage)
+}
+
+func TestCreate(t *testing.T) {
+	storage, fakeEtcdClient, _ := newStorage(t)
+	test := resttest.New(t, storage, fakeEtcdClient.SetError).ClusterScope()
+	obj := validNewClusterRoleBinding("foo")
+	obj.ObjectMeta = api.ObjectMeta{}
+	test.TestCreate(
+		// valid
+		obj,
+		// invalid
+		&api.ClusterRoleBinding{
+			ObjectMeta: api.ObjectMeta{Name: "*BadName!"},
+		},
+	)
+}
+
+func TestEtcdListClusterRoleBindings(t *testing.T) {
+	storage, fakeClient, _ := newStorage(t)
+	ctx := api.NewContext()
+	key := storage.KeyRootFunc(ctx)
+	fakeClient.Data[key] = tools.EtcdResponseWithError{
+		R: &etcd.Response{
+			Node: &etcd.Node{
+				Nodes: []*etcd.Node{
+					{Value: runtime.EncodeOrDie(latest.Codec, validNewClusterRoleBinding("foo"))},
+					{Value: runtime.EncodeOrDie(latest.Codec, validNewClusterRoleBinding("bar"))},
+				},
+			},
+		},
+		E: nil,
+	}
+	obj, err := storage.List(ctx, labels.Everything(), fields.Everything())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	list := obj.(*api.ClusterRoleBindingList)
+	if len(list.Items) != 2 || list.Items[0].Name != "foo" || list.Items[1].Name != "bar" {
+		t.Errorf("Unexpected cluster role binding list: %#v", list)
+	}
+}
+
+type fakeEscalationChecker struct {
+	namespace string
+	ref       api.RoleRef
+	err       error
+}
+
+func (f *fakeEscalationChecker) ConfirmNoEscalation(ctx api.Context, namespace string, ref api.RoleRef) error {
+	f.namespace, f.ref = namespace, ref
+	return f.err
+}
+
+func (f *fakeEscalationChecker) ConfirmNoRuleEscalation(ctx api.Context, namespace, kind, name string, rules []api.PolicyRule) error {
+	return nil
+}
+
+func TestCreateChecksEscalation(t *testing.T) {
+	fakeEtcdClient := tools.NewFakeEtcdClient(t)
+	fakeEtcdClient.TestIndex = true
+	helper := tools.NewEtcdHelper(fakeEtcdClient, latest.Codec)
+	checker := &fakeEscalationChecker{err: errors.New("escalation")}
+	storage := NewStorage(helper, checker)
+
+	obj := validNewClusterRoleBinding("foo")
+	if _, err := storage.Create(api.NewContext(), obj); err != checker.err {
+		t.Errorf("expected the escalation error, got %v", err)
+	}
+	if checker.namespace != "" || checker.ref != obj.RoleRef {
+		t.Errorf("unexpected escalation check of %v in %q", checker.ref, checker.namespace)
+	}
+	if _, _, err := storage.Update(api.NewContext(), obj); err != checker.err {
+		t.Errorf("expected the escalation error, got %v", err)
+	}
+
+	checker.err = nil
+	if _, err := storage.Create(api.NewContext(), obj); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
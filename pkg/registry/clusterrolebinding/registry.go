@@ -0,0 +1,87 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterrolebinding
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/rest"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+)
+
+// Registry is an interface implemented by things that know how to store ClusterRoleBinding objects.
+type Registry interface {
+	// ListClusterRoleBindings obtains a list of ClusterRoleBindings having labels which match selector.
+	ListClusterRoleBindings(ctx api.Context, selector labels.Selector) (*api.ClusterRoleBindingList, error)
+	// Watch for new/changed/deleted ClusterRoleBindings
+	WatchClusterRoleBindings(ctx api.Context, label labels.Selector, field fields.Selector, resourceVersion string) (watch.Interface, error)
+	// Get a specific ClusterRoleBinding
+	GetClusterRoleBinding(ctx api.Context, name string) (*api.ClusterRoleBinding, error)
+	// Create a ClusterRoleBinding based on a specification.
+	CreateClusterRoleBinding(ctx api.Context, clusterrolebinding *api.ClusterRoleBinding) error
+	// Update an existing ClusterRoleBinding
+	UpdateClusterRoleBinding(ctx api.Context, clusterrolebinding *api.ClusterRoleBinding) error
+	// Delete an existing ClusterRoleBinding
+	DeleteClusterRoleBinding(ctx api.Context, name string) error
+}
+
+// storage puts strong typing around storage calls
+type storage struct {
+	rest.StandardStorage
+}
+
+// NewRegistry returns a new Registry interface for the given Storage. Any mismatched
+// types will panic.
+func NewRegistry(s rest.StandardStorage) Registry {
+	return &storage{s}
+}
+
+func (s *storage) ListClusterRoleBindings(ctx api.Context, label labels.Selector) (*api.ClusterRoleBindingList, error) {
+	obj, err := s.List(ctx, label, fields.Everything())
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*api.ClusterRoleBindingList), nil
+}
+
+func (s *storage) WatchClusterRoleBindings(ctx api.Context, label labels.Selector, field fields.Selector, resourceVersion string) (watch.Interface, error) {
+	return s.Watch(ctx, label, field, resourceVersion)
+}
+
+func (s *storage) GetClusterRoleBinding(ctx api.Context, name string) (*api.ClusterRoleBinding, error) {
+	obj, err := s.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*api.ClusterRoleBinding), nil
+}
+
+func (s *storage) CreateClusterRoleBinding(ctx api.Context, clusterrolebinding *api.ClusterRoleBinding) error {
+	_, err := s.Create(ctx, clusterrolebinding)
+	return err
+}
+
+func (s *storage) UpdateClusterRoleBinding(ctx api.Context, clusterrolebinding *api.ClusterRoleBinding) error {
+	_, _, err := s.Update(ctx, clusterrolebinding)
+	return err
+}
+
+func (s *storage) DeleteClusterRoleBinding(ctx api.Context, name string) error {
+	_, err := s.Delete(ctx, name, nil)
+	return err
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterrolebinding
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/validation"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/generic"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/fielderrors"
+)
+
+// clusterrolebindingStrategy implements behavior for ClusterRoleBinding objects
+type clusterrolebindingStrategy struct {
+	runtime.ObjectTyper
+	api.NameGenerator
+}
+
+// Strategy is the default logic that applies when creating and updating ClusterRoleBinding
+// objects via the REST API.
+var Strategy = clusterrolebindingStrategy{api.Scheme, api.SimpleNameGenerator}
+
+// NamespaceScoped is false for clusterrolebindings.
+func (clusterrolebindingStrategy) NamespaceScoped() bool {
+	return false
+}
+
+// ResetBeforeCreate clears fields that are not allowed to be set by end users on creation.
+func (clusterrolebindingStrategy) ResetBeforeCreate(obj runtime.Object) {
+}
+
+// Validate validates a new cluster role binding.
+func (clusterrolebindingStrategy) Validate(obj runtime.Object) fielderrors.ValidationErrorList {
+	return validation.ValidateClusterRoleBinding(obj.(*api.ClusterRoleBinding))
+}
+
+// AllowCreateOnUpdate is false for clusterrolebindings.
+func (clusterrolebindingStrategy) AllowCreateOnUpdate() bool {
+	return false
+}
+
+// ValidateUpdate is the default update validation for an end user.
+func (clusterrolebindingStrategy) ValidateUpdate(obj, old runtime.Object) fielderrors.ValidationErrorList {
+	return validation.ValidateClusterRoleBindingUpdate(old.(*api.ClusterRoleBinding), obj.(*api.ClusterRoleBinding))
+}
+
+// MatchClusterRoleBinding returns a generic matcher for a given label and field selector.
+func MatchClusterRoleBinding(label labels.Selector, field fields.Selector) generic.Matcher {
+	return generic.MatcherFunc(func(obj runtime.Object) (bool, error) {
+		clusterrolebindingObj, ok := obj.(*api.ClusterRoleBinding)
+		if !ok {
+			return false, fmt.Errorf("not a cluster role binding")
+		}
+		fields := ClusterRoleBindingToSelectableFields(clusterrolebindingObj)
+		return label.Matches(labels.Set(clusterrolebindingObj.Labels)) && field.Matches(fields), nil
+	})
+}
+
+// ClusterRoleBindingToSelectableFields returns a label set that represents the object
+// TODO: fields are not labels, and the validation rules for them do not apply.
+func ClusterRoleBindingToSelectableFields(clusterrolebinding *api.ClusterRoleBinding) labels.Set {
+	return labels.Set{
+		"name": clusterrolebinding.Name,
+	}
+}
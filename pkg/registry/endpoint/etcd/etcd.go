@@ -32,31 +32,35 @@ type REST struct {
 	*etcdgeneric.Etcd
 }
 
-// NewStorage returns a RESTStorage object that will work against endpoints.
-func NewStorage(h tools.EtcdHelper) *REST {
+// NewStorage returns a RESTStorage object that will work against endpoints. If
+// cacheCapacity is positive, watches and lists are served from a cache that
+// remembers that many changes.
+func NewStorage(h tools.EtcdHelper, cacheCapacity int) *REST {
 	prefix := "/registry/services/endpoints"
-	return &REST{
-		&etcdgeneric.Etcd{
-			NewFunc:     func() runtime.Object { return &api.Endpoints{} },
-			NewListFunc: func() runtime.Object { return &api.EndpointsList{} },
-			KeyRootFunc: func(ctx api.Context) string {
-				return etcdgeneric.NamespaceKeyRootFunc(ctx, prefix)
-			},
-			KeyFunc: func(ctx api.Context, name string) (string, error) {
-				return etcdgeneric.NamespaceKeyFunc(ctx, prefix, name)
-			},
-			ObjectNameFunc: func(obj runtime.Object) (string, error) {
-				return obj.(*api.Endpoints).Name, nil
-			},
-			PredicateFunc: func(label labels.Selector, field fields.Selector) generic.Matcher {
-				return endpoint.MatchEndpoints(label, field)
-			},
-			EndpointName: "endpoints",
-
-			CreateStrategy: endpoint.Strategy,
-			UpdateStrategy: endpoint.Strategy,
-
-			Helper: h,
+	store := &etcdgeneric.Etcd{
+		NewFunc:     func() runtime.Object { return &api.Endpoints{} },
+		NewListFunc: func() runtime.Object { return &api.EndpointsList{} },
+		KeyRootFunc: func(ctx api.Context) string {
+			return etcdgeneric.NamespaceKeyRootFunc(ctx, prefix)
 		},
+		KeyFunc: func(ctx api.Context, name string) (string, error) {
+			return etcdgeneric.NamespaceKeyFunc(ctx, prefix, name)
+		},
+		ObjectNameFunc: func(obj runtime.Object) (string, error) {
+			return obj.(*api.Endpoints).Name, nil
+		},
+		PredicateFunc: func(label labels.Selector, field fields.Selector) generic.Matcher {
+			return endpoint.MatchEndpoints(label, field)
+		},
+		EndpointName: "endpoints",
+
+		CreateStrategy: endpoint.Strategy,
+		UpdateStrategy: endpoint.Strategy,
+
+		Helper: h,
+	}
+	if cacheCapacity > 0 {
+		store.Cache = etcdgeneric.NewCacher(store, cacheCapacity)
 	}
+	return &REST{store}
 }
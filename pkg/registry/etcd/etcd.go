@@ -111,7 +111,12 @@ func (r *Registry) WatchControllers(ctx api.Context, label labels.Selector, fiel
 		}
 		match := label.Matches(labels.Set(controller.Labels))
 		if match {
-			pods, err := r.pods.ListPods(ctx, labels.Set(controller.Spec.Selector).AsSelector())
+			selector, err := api.LabelSelectorAsSelector(controller.Spec.Selector, controller.Spec.SelectorRequirements)
+			if err != nil {
+				glog.Warningf("Invalid selector for controller %s: %v", controller.Name, err)
+				return false
+			}
+			pods, err := r.pods.ListPods(ctx, selector)
 			if err != nil {
 				glog.Warningf("Error listing pods: %v", err)
 				// No object that's useable so drop it on the floor
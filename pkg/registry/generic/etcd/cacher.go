@@ -0,0 +1,355 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+
+	"github.com/golang/glog"
+)
+
+const (
+	// listWaitTimeout is how long a list waits for the cache to catch up with
+	// the requested resource version before it is served from etcd instead.
+	listWaitTimeout = 3 * time.Second
+	// watcherBufferSize is how many events a watcher may fall behind before
+	// it is stopped.
+	watcherBufferSize = 100
+)
+
+// Cacher serves lists and watches of a resource from memory. It keeps a copy
+// of the resource up to date with a single etcd watch, and a window of the most
+// recent changes, so that watches from a recent resource version do not need
+// an etcd watch of their own.
+type Cacher struct {
+	helper      tools.EtcdHelper
+	keyRoot     string
+	newListFunc func() runtime.Object
+	watchCache  *watchCache
+
+	// lock guards watchers and nextWatcher.
+	lock        sync.Mutex
+	watchers    map[int]*cacheWatcher
+	nextWatcher int
+
+	stop chan struct{}
+}
+
+// NewCacher returns a Cacher for the objects of store, which keeps up to
+// capacity changes in its window. The store must be fully set up.
+func NewCacher(store *Etcd, capacity int) *Cacher {
+	keyFunc := func(obj runtime.Object) (string, error) {
+		meta, err := api.ObjectMetaFor(obj)
+		if err != nil {
+			return "", err
+		}
+		name, err := store.ObjectNameFunc(obj)
+		if err != nil {
+			return "", err
+		}
+		return store.KeyFunc(api.WithNamespace(api.NewContext(), meta.Namespace), name)
+	}
+	c := &Cacher{
+		helper:      store.Helper,
+		keyRoot:     store.KeyRootFunc(api.NewContext()),
+		newListFunc: store.NewListFunc,
+		watchCache:  newWatchCache(capacity, keyFunc, store.Helper.Versioner),
+		watchers:    map[int]*cacheWatcher{},
+		stop:        make(chan struct{}),
+	}
+	c.watchCache.onEvent = c.dispatch
+	c.watchCache.onReplace = c.terminateAllWatchers
+	go util.Until(c.listAndWatch, time.Second, c.stop)
+	return c
+}
+
+// Stop stops the etcd watch that keeps the cache up to date.
+func (c *Cacher) Stop() {
+	close(c.stop)
+}
+
+// listAndWatch fills the cache from an etcd list and keeps it up to date until
+// the watch that follows ends.
+func (c *Cacher) listAndWatch() {
+	list := c.newListFunc()
+	if err := c.helper.ExtractToList(c.keyRoot, list); err != nil {
+		glog.Errorf("Unable to list %s: %v", c.keyRoot, err)
+		return
+	}
+	listMeta, err := api.ListMetaFor(list)
+	if err != nil {
+		glog.Errorf("Unable to understand list of %s: %v", c.keyRoot, err)
+		return
+	}
+	version := uint64(0)
+	if len(listMeta.ResourceVersion) > 0 {
+		if version, err = strconv.ParseUint(listMeta.ResourceVersion, 10, 64); err != nil {
+			glog.Errorf("Unable to parse resource version of %s: %v", c.keyRoot, err)
+			return
+		}
+	}
+	items, err := runtime.ExtractList(list)
+	if err != nil {
+		glog.Errorf("Unable to understand list of %s: %v", c.keyRoot, err)
+		return
+	}
+	if err := c.watchCache.Replace(items, version); err != nil {
+		glog.Errorf("Unable to fill cache of %s: %v", c.keyRoot, err)
+		return
+	}
+
+	w, err := c.helper.WatchList(c.keyRoot, version+1, tools.Everything)
+	if err != nil {
+		glog.Errorf("Unable to watch %s: %v", c.keyRoot, err)
+		return
+	}
+	defer w.Stop()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return
+			}
+			if event.Type == watch.Error {
+				if status, ok := event.Object.(*api.Status); ok {
+					glog.Errorf("Watch of %s ended with error: %s", c.keyRoot, status.Message)
+				}
+				return
+			}
+			if err := c.watchCache.Process(event); err != nil {
+				glog.Errorf("Unable to update cache of %s: %v", c.keyRoot, err)
+				return
+			}
+		}
+	}
+}
+
+// List returns the objects under key once the cache is at least at
+// resourceVersion, and the version they were read at. It returns false if the
+// cache did not catch up in time.
+func (c *Cacher) List(key string, resourceVersion uint64) ([]runtime.Object, uint64, bool) {
+	return c.watchCache.WaitUntilFreshAndList(key+"/", resourceVersion, listWaitTimeout)
+}
+
+// Watch starts a watch of the objects under key that pass filter, from
+// resourceVersion on, as returned by tools.ParseWatchResourceVersion. It
+// returns false if the cache no longer holds all the changes since then.
+func (c *Cacher) Watch(key string, resourceVersion uint64, filter tools.FilterFunc) (watch.Interface, bool) {
+	c.watchCache.RLock()
+	defer c.watchCache.RUnlock()
+	events, ok := c.watchCache.GetAllEventsSinceThreadUnsafe(resourceVersion)
+	if !ok {
+		return nil, false
+	}
+	prefix := key + "/"
+	initEvents := []watchCacheEvent{}
+	for _, event := range events {
+		if strings.HasPrefix(event.Key, prefix) {
+			initEvents = append(initEvents, event)
+		}
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	id := c.nextWatcher
+	c.nextWatcher++
+	w := newCacheWatcher(resourceVersion, prefix, initEvents, filter, func() { c.forgetWatcher(id) })
+	c.watchers[id] = w
+	return w, true
+}
+
+// dispatch passes event to every watcher. A watcher that has fallen too far
+// behind is stopped rather than holding up the others; its client can watch
+// again from the last resource version it saw.
+func (c *Cacher) dispatch(event watchCacheEvent) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for id, w := range c.watchers {
+		if !w.add(event) {
+			glog.V(2).Infof("Stopping watch of %s that fell behind", w.prefix)
+			delete(c.watchers, id)
+			w.stop()
+		}
+	}
+}
+
+// terminateAllWatchers stops every watcher, after the cache was filled from a
+// new list and changes may have been missed.
+func (c *Cacher) terminateAllWatchers() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for id, w := range c.watchers {
+		delete(c.watchers, id)
+		w.stop()
+	}
+}
+
+func (c *Cacher) forgetWatcher(id int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if w, ok := c.watchers[id]; ok {
+		delete(c.watchers, id)
+		w.stop()
+	}
+}
+
+// cacheWatcher implements watch.Interface for a watch served by a Cacher.
+type cacheWatcher struct {
+	// resourceVersion is the first resource version sent: a watch may start
+	// ahead of the cache, whose older changes must not be sent then.
+	resourceVersion uint64
+	prefix          string
+	filter          tools.FilterFunc
+	input           chan watchCacheEvent
+	result          chan watch.Event
+	forget          func()
+
+	// stopped is guarded by the Cacher's lock.
+	stopped bool
+
+	doneLock sync.Mutex
+	done     chan struct{}
+	isDone   bool
+}
+
+func newCacheWatcher(resourceVersion uint64, prefix string, initEvents []watchCacheEvent, filter tools.FilterFunc, forget func()) *cacheWatcher {
+	w := &cacheWatcher{
+		resourceVersion: resourceVersion,
+		prefix:          prefix,
+		filter:          filter,
+		input:           make(chan watchCacheEvent, watcherBufferSize),
+		result:          make(chan watch.Event),
+		forget:          forget,
+		done:            make(chan struct{}),
+	}
+	go w.process(initEvents)
+	return w
+}
+
+// ResultChan implements watch.Interface.
+func (w *cacheWatcher) ResultChan() <-chan watch.Event {
+	return w.result
+}
+
+// Stop implements watch.Interface.
+func (w *cacheWatcher) Stop() {
+	w.forget()
+	w.doneLock.Lock()
+	defer w.doneLock.Unlock()
+	if !w.isDone {
+		w.isDone = true
+		close(w.done)
+	}
+}
+
+// add queues event if it is under the watched key and not older than the
+// watch, and returns false if the watcher has fallen too far behind. Requires
+// the Cacher's lock.
+func (w *cacheWatcher) add(event watchCacheEvent) bool {
+	if event.ResourceVersion < w.resourceVersion || !strings.HasPrefix(event.Key, w.prefix) {
+		return true
+	}
+	select {
+	case w.input <- event:
+		return true
+	default:
+		return false
+	}
+}
+
+// stop ends the watch once the queued events are sent. Requires the Cacher's
+// lock.
+func (w *cacheWatcher) stop() {
+	if !w.stopped {
+		w.stopped = true
+		close(w.input)
+	}
+}
+
+func (w *cacheWatcher) process(initEvents []watchCacheEvent) {
+	defer util.HandleCrash()
+	defer close(w.result)
+	for _, event := range initEvents {
+		if event.ResourceVersion < w.resourceVersion {
+			continue
+		}
+		if !w.send(event) {
+			return
+		}
+	}
+	for event := range w.input {
+		if !w.send(event) {
+			return
+		}
+	}
+}
+
+// send passes event to the client if it concerns objects that pass the
+// filter, and returns false if the client stopped the watch. Like a watch of
+// etcd, a change that makes an object start or stop passing the filter is
+// sent as an add or a delete.
+func (w *cacheWatcher) send(event watchCacheEvent) bool {
+	var curObj, oldObj runtime.Object
+	if event.Type == watch.Deleted {
+		oldObj = event.Object
+	} else {
+		curObj = event.Object
+		oldObj = event.PrevObject
+	}
+	curObjPasses := curObj != nil && w.filter(curObj)
+	oldObjPasses := oldObj != nil && w.filter(oldObj)
+
+	var result watch.Event
+	switch {
+	case curObjPasses && oldObjPasses:
+		result = watch.Event{Type: watch.Modified, Object: shallowCopy(curObj)}
+	case curObjPasses && !oldObjPasses:
+		result = watch.Event{Type: watch.Added, Object: shallowCopy(curObj)}
+	case !curObjPasses && oldObjPasses:
+		result = watch.Event{Type: watch.Deleted, Object: shallowCopy(oldObj)}
+	default:
+		return true
+	}
+	select {
+	case w.result <- result:
+		return true
+	case <-w.done:
+		return false
+	}
+}
+
+// shallowCopy returns a copy of obj that shares its maps, slices and pointers.
+// The objects in the cache are shared by all watchers, and the API server sets
+// the self link of every object it sends.
+func shallowCopy(obj runtime.Object) runtime.Object {
+	v := reflect.ValueOf(obj).Elem()
+	out := reflect.New(v.Type())
+	out.Elem().Set(v)
+	return out.Interface().(runtime.Object)
+}
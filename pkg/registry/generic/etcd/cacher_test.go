@@ -0,0 +1,158 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/testapi"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+
+	"github.com/coreos/go-etcd/etcd"
+)
+
+func TestCacherListAndWatch(t *testing.T) {
+	fakeClient, registry := NewTestGenericEtcdRegistry(t)
+	podFoo := &api.Pod{ObjectMeta: api.ObjectMeta{Name: "foo"}}
+	podBar := &api.Pod{ObjectMeta: api.ObjectMeta{Name: "bar"}}
+	fakeClient.Data["/registry/pods"] = tools.EtcdResponseWithError{
+		R: &etcd.Response{
+			EtcdIndex: 5,
+			Node: &etcd.Node{
+				Nodes: []*etcd.Node{
+					{
+						Key:           "/registry/pods/foo",
+						Value:         runtime.EncodeOrDie(testapi.Codec(), podFoo),
+						ModifiedIndex: 3,
+						CreatedIndex:  3,
+					},
+				},
+			},
+		},
+	}
+
+	registry.Cache = NewCacher(registry, 10)
+	defer registry.Cache.Stop()
+	fakeClient.WaitForWatchCompletion()
+	if e, a := uint64(6), fakeClient.WatchIndex; e != a {
+		t.Errorf("expected the cache to watch from %d, got %d", e, a)
+	}
+
+	w, err := registry.WatchPredicate(api.NewContext(), SetMatcher{util.NewStringSet("bar")}, "5")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer w.Stop()
+	if _, ok := w.(*cacheWatcher); !ok {
+		t.Fatalf("expected the watch to be served from the cache, got %#v", w)
+	}
+
+	fakeClient.WatchResponse <- &etcd.Response{
+		Action: "create",
+		Node: &etcd.Node{
+			Key:           "/registry/pods/bar",
+			Value:         runtime.EncodeOrDie(testapi.Codec(), podBar),
+			ModifiedIndex: 6,
+			CreatedIndex:  6,
+		},
+	}
+	event := <-w.ResultChan()
+	if event.Type != watch.Added || event.Object.(*api.Pod).Name != "bar" || event.Object.(*api.Pod).ResourceVersion != "6" {
+		t.Errorf("unexpected event %#v", event)
+	}
+
+	ctx := api.WithResourceVersion(api.NewContext(), "6")
+	list, err := registry.ListPredicate(ctx, EverythingMatcher{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pods := list.(*api.PodList)
+	if pods.ResourceVersion != "6" || len(pods.Items) != 2 || pods.Items[0].Name != "bar" || pods.Items[1].Name != "foo" {
+		t.Errorf("unexpected list %#v", pods)
+	}
+
+	if _, err := registry.ListPredicate(api.WithResourceVersion(api.NewContext(), "x"), EverythingMatcher{}); err == nil {
+		t.Errorf("expected an invalid resource version to be rejected")
+	}
+}
+
+func TestCacherWatchAheadOfCache(t *testing.T) {
+	fakeClient, registry := NewTestGenericEtcdRegistry(t)
+	fakeClient.Data["/registry/pods"] = tools.EtcdResponseWithError{
+		R: &etcd.Response{EtcdIndex: 5, Node: &etcd.Node{}},
+	}
+	registry.Cache = NewCacher(registry, 10)
+	defer registry.Cache.Stop()
+	fakeClient.WaitForWatchCompletion()
+
+	// The client saw version 7 elsewhere, before the cache did.
+	w, err := registry.WatchPredicate(api.NewContext(), EverythingMatcher{}, "7")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer w.Stop()
+	if _, ok := w.(*cacheWatcher); !ok {
+		t.Fatalf("expected the watch to be served from the cache, got %#v", w)
+	}
+
+	for i, name := range []string{"six", "seven", "eight"} {
+		fakeClient.WatchResponse <- &etcd.Response{
+			Action: "create",
+			Node: &etcd.Node{
+				Key:           "/registry/pods/" + name,
+				Value:         runtime.EncodeOrDie(testapi.Codec(), &api.Pod{ObjectMeta: api.ObjectMeta{Name: name}}),
+				ModifiedIndex: uint64(6 + i),
+				CreatedIndex:  uint64(6 + i),
+			},
+		}
+	}
+	event := <-w.ResultChan()
+	if event.Type != watch.Added || event.Object.(*api.Pod).Name != "eight" {
+		t.Errorf("expected the changes up to the watched version to be skipped, got %#v", event)
+	}
+}
+
+func TestCacheWatcherFilter(t *testing.T) {
+	filter := func(obj runtime.Object) bool {
+		return obj.(*api.Pod).Spec.Host == "machine"
+	}
+	bound := makeTestPod("foo", 2)
+	bound.Spec.Host = "machine"
+	events := []watchCacheEvent{
+		{Type: watch.Added, Key: "/pods/ns/foo", Object: makeTestPod("foo", 1), ResourceVersion: 1},
+		{Type: watch.Modified, Key: "/pods/ns/foo", Object: bound, PrevObject: makeTestPod("foo", 1), ResourceVersion: 2},
+		{Type: watch.Modified, Key: "/pods/ns/foo", Object: makeTestPod("foo", 3), PrevObject: bound, ResourceVersion: 3},
+	}
+	w := newCacheWatcher(0, "/pods/ns/", events, filter, func() {})
+	w.stop()
+
+	got := []watch.EventType{}
+	for event := range w.ResultChan() {
+		got = append(got, event.Type)
+		if event.Object == bound {
+			t.Errorf("expected a copy of the cached object")
+		}
+	}
+	if e, a := []watch.EventType{watch.Added, watch.Deleted}, got; !reflect.DeepEqual(e, a) {
+		t.Errorf("expected events %v, got %v", e, a)
+	}
+}
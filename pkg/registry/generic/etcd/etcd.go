@@ -18,6 +18,8 @@ package etcd
 
 import (
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	kubeerr "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
@@ -28,11 +30,18 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/generic"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/fielderrors"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 
 	"github.com/golang/glog"
 )
 
+// gracefulDeletionMargin is how many seconds past its grace period a gracefully deleted
+// object is kept in etcd, giving whoever is responsible for it the time to confirm the
+// deletion before etcd expires it.
+const gracefulDeletionMargin = 60
+
 // Etcd implements generic.Registry, backing it with etcd storage.
 // It's intended to be embeddable, so that you can implement any
 // non-generic functions if needed.
@@ -99,6 +108,11 @@ type Etcd struct {
 
 	// Used for all etcd access functions
 	Helper tools.EtcdHelper
+
+	// If set, serves watches and lists at a resource version from memory when
+	// it can. Not used if Decorator is set, since decorators change the
+	// objects they are given.
+	Cache *Cacher
 }
 
 // NamespaceKeyRootFunc is the default function for constructing etcd paths to resource directories enforcing namespace rules.
@@ -144,6 +158,15 @@ func (e *Etcd) List(ctx api.Context, label labels.Selector, field fields.Selecto
 // ListPredicate returns a list of all the items matching m.
 func (e *Etcd) ListPredicate(ctx api.Context, m generic.Matcher) (runtime.Object, error) {
 	list := e.NewListFunc()
+	if resourceVersion, ok := api.ResourceVersionFrom(ctx); ok && e.Cache != nil && e.Decorator == nil {
+		version, err := strconv.ParseUint(resourceVersion, 10, 64)
+		if err != nil {
+			return nil, kubeerr.NewInvalid(e.EndpointName, "", fielderrors.ValidationErrorList{fielderrors.NewFieldInvalid("resourceVersion", resourceVersion, err.Error())})
+		}
+		if items, cacheVersion, ok := e.Cache.List(e.KeyRootFunc(ctx), version); ok {
+			return e.filterCached(list, items, cacheVersion, m)
+		}
+	}
 	err := e.Helper.ExtractToList(e.KeyRootFunc(ctx), list)
 	if err != nil {
 		return nil, err
@@ -151,6 +174,29 @@ func (e *Etcd) ListPredicate(ctx api.Context, m generic.Matcher) (runtime.Object
 	return generic.FilterList(list, m, generic.DecoratorFunc(e.Decorator))
 }
 
+// filterCached sets the items from the cache that m matches into list.
+func (e *Etcd) filterCached(list runtime.Object, items []runtime.Object, version uint64, m generic.Matcher) (runtime.Object, error) {
+	filtered := []runtime.Object{}
+	for _, obj := range items {
+		matches, err := m.Matches(obj)
+		if err != nil {
+			return nil, err
+		}
+		if matches {
+			filtered = append(filtered, obj)
+		}
+	}
+	if err := runtime.SetList(list, filtered); err != nil {
+		return nil, err
+	}
+	if e.Helper.Versioner != nil {
+		if err := e.Helper.Versioner.UpdateList(list, version); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
 // CreateWithName inserts a new item with the provided name
 // DEPRECATED: use Create instead
 func (e *Etcd) CreateWithName(ctx api.Context, name string, obj runtime.Object) error {
@@ -296,6 +342,10 @@ func (e *Etcd) Update(ctx api.Context, obj runtime.Object) (runtime.Object, bool
 				return nil, 0, err
 			}
 		}
+		// an update must not drop the TTL of an object pending graceful deletion
+		if ttl == 0 {
+			ttl = gracefulDeletionTTL(existing)
+		}
 		return obj, ttl, nil
 	})
 
@@ -371,8 +421,11 @@ func (e *Etcd) Delete(ctx api.Context, name string, options *api.DeleteOptions)
 		return e.finalizeDelete(obj, false)
 	}
 	if graceful && *options.GracePeriodSeconds != 0 {
+		// the object now carries its deletion timestamp and is removed once whoever is
+		// responsible for it (for pods, the kubelet) confirms it has terminated. The
+		// TTL removes it anyway if that confirmation never comes.
 		out := e.NewFunc()
-		if err := e.Helper.SetObj(key, obj, out, uint64(*options.GracePeriodSeconds)); err != nil {
+		if err := e.Helper.SetObj(key, obj, out, gracefulDeletionTTL(obj)); err != nil {
 			return nil, etcderr.InterpretUpdateError(err, e.EndpointName, name)
 		}
 		return e.finalizeDelete(out, true)
@@ -386,6 +439,20 @@ func (e *Etcd) Delete(ctx api.Context, name string, options *api.DeleteOptions)
 	return e.finalizeDelete(out, true)
 }
 
+// gracefulDeletionTTL returns the TTL that removes obj from etcd gracefulDeletionMargin
+// seconds after its deletion timestamp, or 0 if obj is not pending graceful deletion.
+func gracefulDeletionTTL(obj runtime.Object) uint64 {
+	objectMeta, err := api.ObjectMetaFor(obj)
+	if err != nil || objectMeta.DeletionTimestamp == nil || objectMeta.DeletionGracePeriodSeconds == nil {
+		return 0
+	}
+	remaining := objectMeta.DeletionTimestamp.Sub(util.Now().Time)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return uint64(remaining/time.Second) + gracefulDeletionMargin
+}
+
 func (e *Etcd) finalizeDelete(obj runtime.Object, runHooks bool) (runtime.Object, error) {
 	if runHooks && e.AfterDelete != nil {
 		if err := e.AfterDelete(obj); err != nil {
@@ -416,7 +483,7 @@ func (e *Etcd) WatchPredicate(ctx api.Context, m generic.Matcher, resourceVersio
 	if err != nil {
 		return nil, err
 	}
-	return e.Helper.WatchList(e.KeyRootFunc(ctx), version, func(obj runtime.Object) bool {
+	filter := func(obj runtime.Object) bool {
 		matches, err := m.Matches(obj)
 		if err != nil {
 			glog.Errorf("unable to match watch: %v", err)
@@ -429,5 +496,11 @@ func (e *Etcd) WatchPredicate(ctx api.Context, m generic.Matcher, resourceVersio
 			}
 		}
 		return matches
-	})
+	}
+	if e.Cache != nil && e.Decorator == nil {
+		if w, ok := e.Cache.Watch(e.KeyRootFunc(ctx), version, filter); ok {
+			return w, nil
+		}
+	}
+	return e.Helper.WatchList(e.KeyRootFunc(ctx), version, filter)
 }
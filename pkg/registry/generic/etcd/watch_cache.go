@@ -0,0 +1,237 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+)
+
+// watchCacheEvent is a change to an object in a watchCache.
+type watchCacheEvent struct {
+	Type watch.EventType
+	// Key is the etcd key of the object.
+	Key string
+	// Object is the object after the change, or its last state if it was
+	// deleted, with the resource version of the change.
+	Object runtime.Object
+	// PrevObject is the object before the change, or nil if it was added.
+	PrevObject      runtime.Object
+	ResourceVersion uint64
+}
+
+// watchCache holds the current state of a resource, as seen through a single
+// etcd watch, and a sliding window of the most recent changes to it.
+type watchCache struct {
+	sync.RWMutex
+	// cond is broadcast whenever resourceVersion moves.
+	cond *sync.Cond
+
+	// events is a ring buffer of the most recent changes. The oldest one is
+	// at events[start] and there are count of them.
+	events []watchCacheEvent
+	start  int
+	count  int
+
+	// objects is the current state of the resource, keyed by etcd key.
+	objects map[string]runtime.Object
+	// resourceVersion is the etcd index the cache is up to date with.
+	resourceVersion uint64
+	// windowStart is the resource version the window starts after: events
+	// with a greater resource version are all in the window.
+	windowStart uint64
+	// initialized is set once the cache has been filled by a list.
+	initialized bool
+
+	keyFunc   func(runtime.Object) (string, error)
+	versioner tools.EtcdVersioner
+
+	// onEvent is called with every change, with the cache locked.
+	onEvent func(watchCacheEvent)
+	// onReplace is called when the contents are replaced by a new list, with
+	// the cache locked. Changes between the old and new contents are lost.
+	onReplace func()
+}
+
+func newWatchCache(capacity int, keyFunc func(runtime.Object) (string, error), versioner tools.EtcdVersioner) *watchCache {
+	w := &watchCache{
+		events:    make([]watchCacheEvent, capacity),
+		objects:   map[string]runtime.Object{},
+		keyFunc:   keyFunc,
+		versioner: versioner,
+	}
+	w.cond = sync.NewCond(w.RLocker())
+	return w
+}
+
+// Replace sets the contents of the cache to objs, listed at resourceVersion,
+// and empties the window.
+func (w *watchCache) Replace(objs []runtime.Object, resourceVersion uint64) error {
+	objects := make(map[string]runtime.Object, len(objs))
+	for _, obj := range objs {
+		key, err := w.keyFunc(obj)
+		if err != nil {
+			return err
+		}
+		objects[key] = obj
+	}
+
+	w.Lock()
+	defer w.Unlock()
+	w.objects = objects
+	w.start, w.count = 0, 0
+	w.resourceVersion = resourceVersion
+	w.windowStart = resourceVersion
+	w.initialized = true
+	if w.onReplace != nil {
+		w.onReplace()
+	}
+	w.cond.Broadcast()
+	return nil
+}
+
+// Process applies a change seen by the etcd watch.
+func (w *watchCache) Process(event watch.Event) error {
+	version, err := w.versioner.ObjectResourceVersion(event.Object)
+	if err != nil {
+		return err
+	}
+	key, err := w.keyFunc(event.Object)
+	if err != nil {
+		return err
+	}
+
+	w.Lock()
+	defer w.Unlock()
+	cacheEvent := watchCacheEvent{
+		Type:            event.Type,
+		Key:             key,
+		Object:          event.Object,
+		PrevObject:      w.objects[key],
+		ResourceVersion: version,
+	}
+	switch event.Type {
+	case watch.Added, watch.Modified:
+		w.objects[key] = event.Object
+	case watch.Deleted:
+		delete(w.objects, key)
+	default:
+		return fmt.Errorf("unexpected watch event type %q", event.Type)
+	}
+	w.add(cacheEvent)
+	w.resourceVersion = version
+	if w.onEvent != nil {
+		w.onEvent(cacheEvent)
+	}
+	w.cond.Broadcast()
+	return nil
+}
+
+// add appends event to the window, dropping the oldest event if it is full.
+// Requires that w be locked.
+func (w *watchCache) add(event watchCacheEvent) {
+	capacity := len(w.events)
+	if w.count == capacity {
+		w.windowStart = w.events[w.start].ResourceVersion
+		w.start = (w.start + 1) % capacity
+		w.count--
+	}
+	w.events[(w.start+w.count)%capacity] = event
+	w.count++
+}
+
+// WaitUntilFreshAndList returns the objects whose keys start with prefix,
+// sorted by key, once the cache is at least at resourceVersion, along with the
+// version of the cache. It returns false if that does not happen within timeout.
+func (w *watchCache) WaitUntilFreshAndList(prefix string, resourceVersion uint64, timeout time.Duration) ([]runtime.Object, uint64, bool) {
+	timedOut := false
+	timer := time.AfterFunc(timeout, func() {
+		w.Lock()
+		defer w.Unlock()
+		timedOut = true
+		w.cond.Broadcast()
+	})
+	defer timer.Stop()
+
+	w.RLock()
+	defer w.RUnlock()
+	for !w.initialized || w.resourceVersion < resourceVersion {
+		if timedOut {
+			return nil, 0, false
+		}
+		w.cond.Wait()
+	}
+
+	keys := []string{}
+	for key := range w.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	objs := make([]runtime.Object, 0, len(keys))
+	for _, key := range keys {
+		objs = append(objs, w.objects[key])
+	}
+	return objs, w.resourceVersion, true
+}
+
+// GetAllEventsSinceThreadUnsafe returns the events with a resource version of
+// at least resourceVersion, or, if resourceVersion is 0, the current contents
+// of the cache as added events. It returns false if the cache is not filled yet
+// or if some of those events are no longer in the window. Requires that w be
+// locked.
+func (w *watchCache) GetAllEventsSinceThreadUnsafe(resourceVersion uint64) ([]watchCacheEvent, bool) {
+	if !w.initialized {
+		return nil, false
+	}
+	if resourceVersion == 0 {
+		keys := make([]string, 0, len(w.objects))
+		for key := range w.objects {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		events := make([]watchCacheEvent, 0, len(keys))
+		for _, key := range keys {
+			obj := w.objects[key]
+			version, err := w.versioner.ObjectResourceVersion(obj)
+			if err != nil {
+				return nil, false
+			}
+			events = append(events, watchCacheEvent{Type: watch.Added, Key: key, Object: obj, ResourceVersion: version})
+		}
+		return events, true
+	}
+	if resourceVersion <= w.windowStart {
+		return nil, false
+	}
+	events := []watchCacheEvent{}
+	for i := 0; i < w.count; i++ {
+		event := w.events[(w.start+i)%len(w.events)]
+		if event.ResourceVersion >= resourceVersion {
+			events = append(events, event)
+		}
+	}
+	return events, true
+}
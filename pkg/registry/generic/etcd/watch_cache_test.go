@@ -0,0 +1,116 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+)
+
+func makeTestPod(name string, resourceVersion uint64) *api.Pod {
+	return &api.Pod{
+		ObjectMeta: api.ObjectMeta{
+			Namespace:       "ns",
+			Name:            name,
+			ResourceVersion: strconv.FormatUint(resourceVersion, 10),
+		},
+	}
+}
+
+func newTestWatchCache(capacity int) *watchCache {
+	keyFunc := func(obj runtime.Object) (string, error) {
+		return "/pods/ns/" + obj.(*api.Pod).Name, nil
+	}
+	return newWatchCache(capacity, keyFunc, tools.APIObjectVersioner{})
+}
+
+func eventVersions(events []watchCacheEvent) []uint64 {
+	versions := []uint64{}
+	for _, event := range events {
+		versions = append(versions, event.ResourceVersion)
+	}
+	return versions
+}
+
+func TestWatchCacheWindow(t *testing.T) {
+	w := newTestWatchCache(2)
+	if _, ok := w.GetAllEventsSinceThreadUnsafe(0); ok {
+		t.Errorf("expected no events before the cache is filled")
+	}
+	if err := w.Replace([]runtime.Object{makeTestPod("foo", 3)}, 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, event := range []watch.Event{
+		{Type: watch.Added, Object: makeTestPod("bar", 6)},
+		{Type: watch.Modified, Object: makeTestPod("foo", 7)},
+		{Type: watch.Deleted, Object: makeTestPod("bar", 8)},
+	} {
+		if err := w.Process(event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	events, ok := w.GetAllEventsSinceThreadUnsafe(0)
+	if !ok || len(events) != 1 || events[0].Type != watch.Added || events[0].ResourceVersion != 7 {
+		t.Errorf("expected the current foo as an added event, got %#v", events)
+	}
+	if _, ok := w.GetAllEventsSinceThreadUnsafe(6); ok {
+		t.Errorf("expected the event at 6 to have left the window")
+	}
+	events, ok = w.GetAllEventsSinceThreadUnsafe(7)
+	if e, a := []uint64{7, 8}, eventVersions(events); !ok || !reflect.DeepEqual(e, a) {
+		t.Errorf("expected events %v, got %v", e, a)
+	}
+	if prev := events[0].PrevObject; prev == nil || prev.(*api.Pod).ResourceVersion != "3" {
+		t.Errorf("expected the previous foo, got %#v", prev)
+	}
+	events, ok = w.GetAllEventsSinceThreadUnsafe(9)
+	if !ok || len(events) != 0 {
+		t.Errorf("expected no events, got %#v", events)
+	}
+}
+
+func TestWatchCacheWaitUntilFreshAndList(t *testing.T) {
+	w := newTestWatchCache(10)
+	if err := w.Replace([]runtime.Object{makeTestPod("foo", 3)}, 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, ok := w.WaitUntilFreshAndList("/pods/", 6, 10*time.Millisecond); ok {
+		t.Errorf("expected the list to time out")
+	}
+
+	go func() {
+		w.Process(watch.Event{Type: watch.Added, Object: makeTestPod("bar", 6)})
+	}()
+	objs, version, ok := w.WaitUntilFreshAndList("/pods/", 6, time.Minute)
+	if !ok || version != 6 {
+		t.Fatalf("expected a list at 6, got %d %v", version, ok)
+	}
+	if len(objs) != 2 || objs[0].(*api.Pod).Name != "bar" || objs[1].(*api.Pod).Name != "foo" {
+		t.Errorf("expected bar and foo, got %#v", objs)
+	}
+	if objs, _, _ := w.WaitUntilFreshAndList("/other/", 0, time.Minute); len(objs) != 0 {
+		t.Errorf("expected no objects under another key, got %#v", objs)
+	}
+}
@@ -37,8 +37,10 @@ type REST struct {
 	connection client.ConnectionInfoGetter
 }
 
-// NewStorage returns a RESTStorage object that will work against nodes.
-func NewStorage(h tools.EtcdHelper, connection client.ConnectionInfoGetter) *REST {
+// NewStorage returns a RESTStorage object that will work against nodes. If
+// cacheCapacity is positive, watches and lists are served from a cache that
+// remembers that many changes.
+func NewStorage(h tools.EtcdHelper, connection client.ConnectionInfoGetter, cacheCapacity int) *REST {
 	prefix := "/registry/minions"
 	store := &etcdgeneric.Etcd{
 		NewFunc:     func() runtime.Object { return &api.Node{} },
@@ -62,6 +64,9 @@ func NewStorage(h tools.EtcdHelper, connection client.ConnectionInfoGetter) *RES
 
 		Helper: h,
 	}
+	if cacheCapacity > 0 {
+		store.Cache = etcdgeneric.NewCacher(store, cacheCapacity)
+	}
 
 	return &REST{store, connection}
 }
@@ -50,7 +50,7 @@ func newHelper(t *testing.T) (*tools.FakeEtcdClient, tools.EtcdHelper) {
 
 func newStorage(t *testing.T) (*REST, *tools.FakeEtcdClient) {
 	fakeEtcdClient, h := newHelper(t)
-	storage := NewStorage(h, fakeConnectionInfoGetter{})
+	storage := NewStorage(h, fakeConnectionInfoGetter{}, 0)
 	return storage, fakeEtcdClient
 }
 
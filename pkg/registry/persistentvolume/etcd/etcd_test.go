@@ -0,0 +1,223 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/latest"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/rest/resttest"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
+	"github.com/coreos/go-etcd/etcd"
+)
+
+func newStorage(t *testing.T) (*REST, *StatusREST, *tools.FakeEtcdClient, tools.EtcdHelper) {
+	fakeEtcdClient := tools.NewFakeEtcdClient(t)
+	fakeEtcdClient.TestIndex = true
+	helper := tools.NewEtcdHelper(fakeEtcdClient, latest.Codec)
+	storage, statusStorage := NewStorage(helper)
+	return storage, statusStorage, fakeEtcdClient, helper
+}
+
+func validNewPersistentVolume(name string) *api.PersistentVolume {
+	return &api.PersistentVolume{
+		ObjectMeta: api.ObjectMeta{
+			Name: name,
+		},
+		Spec: api.PersistentVolumeSpec{
+			Capacity: api.ResourceList{
+				api.ResourceStorage: resource.MustParse("10G"),
+			},
+			PersistentVolumeSource: api.PersistentVolumeSource{
+				HostPath: &api.HostPathVolumeSource{Path: "/foo"},
+			},
+		},
+		Status: api.PersistentVolumeStatus{
+			Phase: api.VolumeAvailable,
+		},
+	}
+}
+
+func TestCreate(t *testing.T) {
+	storage, _, fakeEtcdClient, _ := newStorage(t)
+	test := resttest.New(t, storage, fakeEtcdClient.SetError)
+	pv := validNewPersistentVolume("foo")
+	pv.ObjectMeta = api.ObjectMeta{}
+	test.TestCreate(
+		// valid
+		pv,
+		// invalid
+		&api.PersistentVolume{
+			ObjectMeta: api.ObjectMeta{Name: "*BadName!"},
+		},
+	)
+}
+
+func TestCreateSetsFields(t *testing.T) {
+	storage, _, _, helper := newStorage(t)
+	pv := validNewPersistentVolume("foo")
+	pv.Status = api.PersistentVolumeStatus{Phase: api.VolumeBound}
+	_, err := storage.Create(api.NewContext(), pv)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	actual := &api.PersistentVolume{}
+	if err := helper.ExtractObj("/registry/persistentvolumes/foo", actual, false); err != nil {
+		t.Fatalf("unexpected extraction error: %v", err)
+	}
+	if actual.Name != pv.Name {
+		t.Errorf("unexpected persistentvolume: %#v", actual)
+	}
+	if len(actual.UID) == 0 {
+		t.Errorf("expected persistentvolume UID to be set: %#v", actual)
+	}
+	if actual.Status.Phase != api.VolumeAvailable {
+		t.Errorf("expected a new persistentvolume to be available: %#v", actual)
+	}
+}
+
+func TestEtcdListPersistentVolumes(t *testing.T) {
+	storage, _, fakeClient, _ := newStorage(t)
+	ctx := api.NewContext()
+	key := storage.KeyRootFunc(ctx)
+	fakeClient.Data[key] = tools.EtcdResponseWithError{
+		R: &etcd.Response{
+			Node: &etcd.Node{
+				Nodes: []*etcd.Node{
+					{Value: runtime.EncodeOrDie(latest.Codec, validNewPersistentVolume("foo"))},
+					{Value: runtime.EncodeOrDie(latest.Codec, validNewPersistentVolume("bar"))},
+				},
+			},
+		},
+		E: nil,
+	}
+	obj, err := storage.List(ctx, labels.Everything(), fields.Everything())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pvObj := obj.(*api.PersistentVolumeList)
+	if len(pvObj.Items) != 2 || pvObj.Items[0].Name != "foo" || pvObj.Items[1].Name != "bar" {
+		t.Errorf("Unexpected persistentvolume list: %#v", pvObj)
+	}
+}
+
+func TestEtcdGetPersistentVolumes(t *testing.T) {
+	storage, _, fakeClient, _ := newStorage(t)
+	ctx := api.NewContext()
+	persistentVolume := validNewPersistentVolume("foo")
+	key, _ := storage.KeyFunc(ctx, "foo")
+	fakeClient.Set(key, runtime.EncodeOrDie(latest.Codec, persistentVolume), 0)
+
+	response, err := fakeClient.Get(key, false, false)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	var persistentVolumeOut api.PersistentVolume
+	err = latest.Codec.DecodeInto([]byte(response.Node.Value), &persistentVolumeOut)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	obj, err := storage.Get(ctx, "foo")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	got := obj.(*api.PersistentVolume)
+
+	persistentVolume.ObjectMeta.ResourceVersion = got.ObjectMeta.ResourceVersion
+	if e, a := persistentVolume, got; !api.Semantic.DeepEqual(*e, *a) {
+		t.Errorf("Unexpected persistentVolume: %#v, expected %#v", e, a)
+	}
+}
+
+func TestEtcdGetPersistentVolumeNotFound(t *testing.T) {
+	storage, _, fakeClient, _ := newStorage(t)
+	ctx := api.NewContext()
+	key, _ := storage.KeyFunc(ctx, "foo")
+	fakeClient.Data[key] = tools.EtcdResponseWithError{
+		R: &etcd.Response{
+			Node: nil,
+		},
+		E: tools.EtcdErrorNotFound,
+	}
+	_, err := storage.Get(ctx, "foo")
+	if !errors.IsNotFound(err) {
+		t.Errorf("Unexpected error returned: %#v", err)
+	}
+}
+
+func TestEtcdDeletePersistentVolume(t *testing.T) {
+	storage, _, fakeClient, _ := newStorage(t)
+	ctx := api.NewContext()
+	key, _ := storage.KeyFunc(ctx, "foo")
+	fakeClient.Set(key, runtime.EncodeOrDie(latest.Codec, validNewPersistentVolume("foo")), 0)
+
+	_, err := storage.Delete(ctx, "foo", nil)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(fakeClient.DeletedKeys) != 1 {
+		t.Errorf("Expected 1 delete, found %#v", fakeClient.DeletedKeys)
+	}
+	if fakeClient.DeletedKeys[0] != key {
+		t.Errorf("Unexpected key: %s, expected %s", fakeClient.DeletedKeys[0], key)
+	}
+}
+
+func TestEtcdUpdateStatus(t *testing.T) {
+	storage, statusStorage, fakeClient, helper := newStorage(t)
+	ctx := api.NewContext()
+	key, _ := storage.KeyFunc(ctx, "foo")
+	pvStart := validNewPersistentVolume("foo")
+	fakeClient.Set(key, runtime.EncodeOrDie(latest.Codec, pvStart), 1)
+
+	pvIn := &api.PersistentVolume{
+		ObjectMeta: api.ObjectMeta{
+			Name:            "foo",
+			ResourceVersion: "1",
+		},
+		Status: api.PersistentVolumeStatus{
+			Phase: api.VolumeBound,
+		},
+	}
+
+	expected := *pvStart
+	expected.ResourceVersion = "2"
+	expected.Labels = pvIn.Labels
+	expected.Status = pvIn.Status
+
+	_, _, err := statusStorage.Update(ctx, pvIn)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var pvOut api.PersistentVolume
+	if err := helper.ExtractObj(key, &pvOut, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !api.Semantic.DeepEqual(expected, pvOut) {
+		t.Errorf("unexpected object: %s", util.ObjectDiff(expected, pvOut))
+	}
+}
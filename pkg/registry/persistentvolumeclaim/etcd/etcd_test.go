@@ -0,0 +1,242 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/latest"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/rest/resttest"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
+	"github.com/coreos/go-etcd/etcd"
+)
+
+func newStorage(t *testing.T) (*REST, *StatusREST, *tools.FakeEtcdClient, tools.EtcdHelper) {
+	fakeEtcdClient := tools.NewFakeEtcdClient(t)
+	fakeEtcdClient.TestIndex = true
+	helper := tools.NewEtcdHelper(fakeEtcdClient, latest.Codec)
+	storage, statusStorage := NewStorage(helper)
+	return storage, statusStorage, fakeEtcdClient, helper
+}
+
+func validNewPersistentVolumeClaim(name, ns string) *api.PersistentVolumeClaim {
+	return &api.PersistentVolumeClaim{
+		ObjectMeta: api.ObjectMeta{
+			Name:      name,
+			Namespace: ns,
+		},
+		Spec: api.PersistentVolumeClaimSpec{
+			AccessModes: []api.AccessModeType{api.ReadWriteOnce},
+			Resources: api.ResourceRequirements{
+				Requests: api.ResourceList{
+					api.ResourceStorage: resource.MustParse("10G"),
+				},
+			},
+		},
+		Status: api.PersistentVolumeClaimStatus{
+			Phase: api.ClaimPending,
+		},
+	}
+}
+
+func TestCreate(t *testing.T) {
+	storage, _, fakeEtcdClient, _ := newStorage(t)
+	test := resttest.New(t, storage, fakeEtcdClient.SetError)
+	pvc := validNewPersistentVolumeClaim("foo", api.NamespaceDefault)
+	pvc.ObjectMeta = api.ObjectMeta{}
+	test.TestCreate(
+		// valid
+		pvc,
+		// invalid
+		&api.PersistentVolumeClaim{
+			ObjectMeta: api.ObjectMeta{Name: "*BadName!"},
+		},
+	)
+}
+
+func TestCreateSetsFields(t *testing.T) {
+	storage, _, _, helper := newStorage(t)
+	pvc := validNewPersistentVolumeClaim("foo", api.NamespaceDefault)
+	pvc.Status = api.PersistentVolumeClaimStatus{Phase: api.ClaimBound}
+	_, err := storage.Create(api.NewDefaultContext(), pvc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	actual := &api.PersistentVolumeClaim{}
+	if err := helper.ExtractObj("/registry/persistentvolumeclaims/default/foo", actual, false); err != nil {
+		t.Fatalf("unexpected extraction error: %v", err)
+	}
+	if actual.Name != pvc.Name {
+		t.Errorf("unexpected persistentvolumeclaim: %#v", actual)
+	}
+	if len(actual.UID) == 0 {
+		t.Errorf("expected persistentvolumeclaim UID to be set: %#v", actual)
+	}
+	if actual.Status.Phase != api.ClaimPending {
+		t.Errorf("expected a new persistentvolumeclaim to be pending: %#v", actual)
+	}
+}
+
+func TestEtcdCreateFailsWithoutNamespace(t *testing.T) {
+	storage, _, _, _ := newStorage(t)
+	pvc := validNewPersistentVolumeClaim("foo", "")
+	_, err := storage.Create(api.NewContext(), pvc)
+	// Accept "namespace" or "Namespace".
+	if err == nil || !strings.Contains(err.Error(), "amespace") {
+		t.Fatalf("expected error that namespace was missing from context, got: %v", err)
+	}
+}
+
+func TestEtcdListPersistentVolumeClaims(t *testing.T) {
+	storage, _, fakeClient, _ := newStorage(t)
+	ctx := api.NewDefaultContext()
+	key := storage.KeyRootFunc(ctx)
+	fakeClient.Data[key] = tools.EtcdResponseWithError{
+		R: &etcd.Response{
+			Node: &etcd.Node{
+				Nodes: []*etcd.Node{
+					{Value: runtime.EncodeOrDie(latest.Codec, validNewPersistentVolumeClaim("foo", api.NamespaceDefault))},
+					{Value: runtime.EncodeOrDie(latest.Codec, validNewPersistentVolumeClaim("bar", api.NamespaceDefault))},
+				},
+			},
+		},
+		E: nil,
+	}
+	obj, err := storage.List(ctx, labels.Everything(), fields.Everything())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pvcObj := obj.(*api.PersistentVolumeClaimList)
+	if len(pvcObj.Items) != 2 || pvcObj.Items[0].Name != "foo" || pvcObj.Items[1].Name != "bar" {
+		t.Errorf("Unexpected persistentvolumeclaim list: %#v", pvcObj)
+	}
+}
+
+// TestEtcdGetDifferentNamespace ensures same-name claims in different namespaces do not clash
+func TestEtcdGetDifferentNamespace(t *testing.T) {
+	storage, _, fakeClient, _ := newStorage(t)
+
+	ctx1 := api.NewDefaultContext()
+	ctx2 := api.WithNamespace(api.NewContext(), "other")
+
+	key1, _ := storage.KeyFunc(ctx1, "foo")
+	key2, _ := storage.KeyFunc(ctx2, "foo")
+
+	fakeClient.Set(key1, runtime.EncodeOrDie(latest.Codec, validNewPersistentVolumeClaim("foo", api.NamespaceDefault)), 0)
+	fakeClient.Set(key2, runtime.EncodeOrDie(latest.Codec, validNewPersistentVolumeClaim("foo", "other")), 0)
+
+	obj, err := storage.Get(ctx1, "foo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if claim := obj.(*api.PersistentVolumeClaim); claim.Namespace != api.NamespaceDefault {
+		t.Errorf("Unexpected persistentvolumeclaim: %#v", claim)
+	}
+
+	obj, err = storage.Get(ctx2, "foo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if claim := obj.(*api.PersistentVolumeClaim); claim.Namespace != "other" {
+		t.Errorf("Unexpected persistentvolumeclaim: %#v", claim)
+	}
+}
+
+func TestEtcdGetPersistentVolumeClaimNotFound(t *testing.T) {
+	storage, _, fakeClient, _ := newStorage(t)
+	ctx := api.NewDefaultContext()
+	key, _ := storage.KeyFunc(ctx, "foo")
+	fakeClient.Data[key] = tools.EtcdResponseWithError{
+		R: &etcd.Response{
+			Node: nil,
+		},
+		E: tools.EtcdErrorNotFound,
+	}
+	_, err := storage.Get(ctx, "foo")
+	if !errors.IsNotFound(err) {
+		t.Errorf("Unexpected error returned: %#v", err)
+	}
+}
+
+func TestEtcdDeletePersistentVolumeClaim(t *testing.T) {
+	storage, _, fakeClient, _ := newStorage(t)
+	ctx := api.NewDefaultContext()
+	key, _ := storage.KeyFunc(ctx, "foo")
+	fakeClient.Set(key, runtime.EncodeOrDie(latest.Codec, validNewPersistentVolumeClaim("foo", api.NamespaceDefault)), 0)
+
+	_, err := storage.Delete(ctx, "foo", nil)
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(fakeClient.DeletedKeys) != 1 {
+		t.Errorf("Expected 1 delete, found %#v", fakeClient.DeletedKeys)
+	}
+	if fakeClient.DeletedKeys[0] != key {
+		t.Errorf("Unexpected key: %s, expected %s", fakeClient.DeletedKeys[0], key)
+	}
+}
+
+func TestEtcdUpdateStatus(t *testing.T) {
+	storage, statusStorage, fakeClient, helper := newStorage(t)
+	ctx := api.NewDefaultContext()
+	key, _ := storage.KeyFunc(ctx, "foo")
+	pvcStart := validNewPersistentVolumeClaim("foo", api.NamespaceDefault)
+	fakeClient.Set(key, runtime.EncodeOrDie(latest.Codec, pvcStart), 1)
+
+	pvcIn := &api.PersistentVolumeClaim{
+		ObjectMeta: api.ObjectMeta{
+			Name:            "foo",
+			Namespace:       api.NamespaceDefault,
+			ResourceVersion: "1",
+		},
+		Status: api.PersistentVolumeClaimStatus{
+			Phase:       api.ClaimBound,
+			AccessModes: []api.AccessModeType{api.ReadWriteOnce},
+			Capacity: api.ResourceList{
+				api.ResourceStorage: resource.MustParse("20G"),
+			},
+			VolumeRef: &api.ObjectReference{Kind: "PersistentVolume", Name: "bar"},
+		},
+	}
+
+	expected := *pvcStart
+	expected.ResourceVersion = "2"
+	expected.Labels = pvcIn.Labels
+	expected.Status = pvcIn.Status
+
+	_, _, err := statusStorage.Update(ctx, pvcIn)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var pvcOut api.PersistentVolumeClaim
+	if err := helper.ExtractObj(key, &pvcOut, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !api.Semantic.DeepEqual(expected, pvcOut) {
+		t.Errorf("unexpected object: %s", util.ObjectDiff(expected, pvcOut))
+	}
+}
@@ -40,8 +40,10 @@ type REST struct {
 	etcdgeneric.Etcd
 }
 
-// NewStorage returns a RESTStorage object that will work against pods.
-func NewStorage(h tools.EtcdHelper) (*REST, *BindingREST, *StatusREST) {
+// NewStorage returns a RESTStorage object that will work against pods. If
+// cacheCapacity is positive, watches and lists are served from a cache that
+// remembers that many changes.
+func NewStorage(h tools.EtcdHelper, cacheCapacity int) (*REST, *BindingREST, *StatusREST) {
 	prefix := "/registry/pods"
 	store := &etcdgeneric.Etcd{
 		NewFunc:     func() runtime.Object { return &api.Pod{} },
@@ -62,6 +64,9 @@ func NewStorage(h tools.EtcdHelper) (*REST, *BindingREST, *StatusREST) {
 
 		Helper: h,
 	}
+	if cacheCapacity > 0 {
+		store.Cache = etcdgeneric.NewCacher(store, cacheCapacity)
+	}
 	statusStore := *store
 
 	bindings := &podLifecycle{}
@@ -47,7 +47,7 @@ func newHelper(t *testing.T) (*tools.FakeEtcdClient, tools.EtcdHelper) {
 
 func newStorage(t *testing.T) (*REST, *BindingREST, *StatusREST, *tools.FakeEtcdClient, tools.EtcdHelper) {
 	fakeEtcdClient, h := newHelper(t)
-	storage, bindingStorage, statusStorage := NewStorage(h)
+	storage, bindingStorage, statusStorage := NewStorage(h, 0)
 	return storage, bindingStorage, statusStorage, fakeEtcdClient, h
 }
 
@@ -89,7 +89,7 @@ func TestStorage(t *testing.T) {
 
 func TestCreate(t *testing.T) {
 	fakeEtcdClient, helper := newHelper(t)
-	storage, _, _ := NewStorage(helper)
+	storage, _, _ := NewStorage(helper, 0)
 	test := resttest.New(t, storage, fakeEtcdClient.SetError)
 	pod := validNewPod()
 	pod.ObjectMeta = api.ObjectMeta{}
@@ -107,28 +107,41 @@ func TestCreate(t *testing.T) {
 
 func TestDelete(t *testing.T) {
 	fakeEtcdClient, helper := newHelper(t)
-	storage, _, _ := NewStorage(helper)
+	storage, _, _ := NewStorage(helper, 0)
 	test := resttest.New(t, storage, fakeEtcdClient.SetError)
 
-	createFn := func() runtime.Object {
-		pod := validChangedPod()
-		fakeEtcdClient.Data["/registry/pods/default/foo"] = tools.EtcdResponseWithError{
-			R: &etcd.Response{
-				Node: &etcd.Node{
-					Value:         runtime.EncodeOrDie(latest.Codec, pod),
-					ModifiedIndex: 1,
+	key := "/registry/pods/default/foo"
+	createFn := func(host string) func() runtime.Object {
+		return func() runtime.Object {
+			pod := validChangedPod()
+			pod.Status.Host = host
+			fakeEtcdClient.Data[key] = tools.EtcdResponseWithError{
+				R: &etcd.Response{
+					Node: &etcd.Node{
+						Value:         runtime.EncodeOrDie(latest.Codec, pod),
+						ModifiedIndex: 1,
+					},
 				},
-			},
+			}
+			return pod
 		}
-		return pod
 	}
 	gracefulSetFn := func() bool {
-		if fakeEtcdClient.Data["/registry/pods/default/foo"].R.Node == nil {
+		if fakeEtcdClient.Data[key].R.Node == nil {
 			return false
 		}
-		return fakeEtcdClient.Data["/registry/pods/default/foo"].R.Node.TTL == 30
-	}
-	test.TestDelete(createFn, gracefulSetFn)
+		obj, err := latest.Codec.Decode([]byte(fakeEtcdClient.Data[key].R.Node.Value))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		pod := obj.(*api.Pod)
+		return pod.DeletionTimestamp != nil && pod.DeletionGracePeriodSeconds != nil && *pod.DeletionGracePeriodSeconds == 30
+	}
+	test.TestDeleteNonExist(createFn("machine"))
+	// a pod bound to a host waits for its kubelet to terminate it
+	test.TestDeleteGraceful(createFn("machine"), 30, gracefulSetFn)
+	// an unscheduled pod has nothing to wait for
+	test.TestDeleteNoGraceful(createFn(""), gracefulSetFn)
 }
 
 func expectPod(t *testing.T, out runtime.Object) (*api.Pod, bool) {
@@ -143,7 +156,7 @@ func expectPod(t *testing.T, out runtime.Object) (*api.Pod, bool) {
 func TestCreateRegistryError(t *testing.T) {
 	fakeEtcdClient, helper := newHelper(t)
 	fakeEtcdClient.Err = fmt.Errorf("test error")
-	storage, _, _ := NewStorage(helper)
+	storage, _, _ := NewStorage(helper, 0)
 
 	pod := validNewPod()
 	_, err := storage.Create(api.NewDefaultContext(), pod)
@@ -154,7 +167,7 @@ func TestCreateRegistryError(t *testing.T) {
 
 func TestCreateSetsFields(t *testing.T) {
 	fakeEtcdClient, helper := newHelper(t)
-	storage, _, _ := NewStorage(helper)
+	storage, _, _ := NewStorage(helper, 0)
 	pod := validNewPod()
 	_, err := storage.Create(api.NewDefaultContext(), pod)
 	if err != fakeEtcdClient.Err {
@@ -176,7 +189,7 @@ func TestCreateSetsFields(t *testing.T) {
 func TestListError(t *testing.T) {
 	fakeEtcdClient, helper := newHelper(t)
 	fakeEtcdClient.Err = fmt.Errorf("test error")
-	storage, _, _ := NewStorage(helper)
+	storage, _, _ := NewStorage(helper, 0)
 	pods, err := storage.List(api.NewDefaultContext(), labels.Everything(), fields.Everything())
 	if err != fakeEtcdClient.Err {
 		t.Fatalf("Expected %#v, Got %#v", fakeEtcdClient.Err, err)
@@ -194,7 +207,7 @@ func TestListEmptyPodList(t *testing.T) {
 		E: fakeEtcdClient.NewError(tools.EtcdErrorCodeNotFound),
 	}
 
-	storage, _, _ := NewStorage(helper)
+	storage, _, _ := NewStorage(helper, 0)
 	pods, err := storage.List(api.NewContext(), labels.Everything(), fields.Everything())
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -230,7 +243,7 @@ func TestListPodList(t *testing.T) {
 			},
 		},
 	}
-	storage, _, _ := NewStorage(helper)
+	storage, _, _ := NewStorage(helper, 0)
 
 	podsObj, err := storage.List(api.NewDefaultContext(), labels.Everything(), fields.Everything())
 	pods := podsObj.(*api.PodList)
@@ -279,7 +292,7 @@ func TestListPodListSelection(t *testing.T) {
 			},
 		},
 	}
-	storage, _, _ := NewStorage(helper)
+	storage, _, _ := NewStorage(helper, 0)
 
 	ctx := api.NewDefaultContext()
 
@@ -344,7 +357,7 @@ func TestListPodListSelection(t *testing.T) {
 }
 
 func TestPodDecode(t *testing.T) {
-	storage, _, _ := NewStorage(tools.EtcdHelper{})
+	storage, _, _ := NewStorage(tools.EtcdHelper{}, 0)
 	expected := validNewPod()
 	body, err := latest.Codec.Encode(expected)
 	if err != nil {
@@ -374,7 +387,7 @@ func TestGet(t *testing.T) {
 			},
 		},
 	}
-	storage, _, _ := NewStorage(helper)
+	storage, _, _ := NewStorage(helper, 0)
 
 	obj, err := storage.Get(api.WithNamespace(api.NewContext(), "test"), "foo")
 	pod := obj.(*api.Pod)
@@ -391,7 +404,7 @@ func TestGet(t *testing.T) {
 func TestPodStorageValidatesCreate(t *testing.T) {
 	fakeEtcdClient, helper := newHelper(t)
 	fakeEtcdClient.Err = fmt.Errorf("test error")
-	storage, _, _ := NewStorage(helper)
+	storage, _, _ := NewStorage(helper, 0)
 
 	pod := validNewPod()
 	pod.Labels = map[string]string{
@@ -409,7 +422,7 @@ func TestPodStorageValidatesCreate(t *testing.T) {
 // TODO: remove, this is covered by RESTTest.TestCreate
 func TestCreatePod(t *testing.T) {
 	_, helper := newHelper(t)
-	storage, _, _ := NewStorage(helper)
+	storage, _, _ := NewStorage(helper, 0)
 
 	pod := validNewPod()
 	obj, err := storage.Create(api.NewDefaultContext(), pod)
@@ -431,7 +444,7 @@ func TestCreatePod(t *testing.T) {
 // TODO: remove, this is covered by RESTTest.TestCreate
 func TestCreateWithConflictingNamespace(t *testing.T) {
 	_, helper := newHelper(t)
-	storage, _, _ := NewStorage(helper)
+	storage, _, _ := NewStorage(helper, 0)
 
 	pod := validNewPod()
 	pod.Namespace = "not-default"
@@ -460,7 +473,7 @@ func TestUpdateWithConflictingNamespace(t *testing.T) {
 			},
 		},
 	}
-	storage, _, _ := NewStorage(helper)
+	storage, _, _ := NewStorage(helper, 0)
 
 	pod := validChangedPod()
 	pod.Namespace = "not-default"
@@ -577,7 +590,7 @@ func TestResourceLocation(t *testing.T) {
 				},
 			},
 		}
-		storage, _, _ := NewStorage(helper)
+		storage, _, _ := NewStorage(helper, 0)
 
 		redirector := rest.Redirector(storage)
 		location, _, err := redirector.ResourceLocation(api.NewDefaultContext(), tc.query)
@@ -615,7 +628,7 @@ func TestDeletePod(t *testing.T) {
 			},
 		},
 	}
-	storage, _, _ := NewStorage(helper)
+	storage, _, _ := NewStorage(helper, 0)
 
 	_, err := storage.Delete(api.NewDefaultContext(), "foo", nil)
 	if err != nil {
@@ -1225,6 +1238,40 @@ func TestEtcdDeletePodMultipleContainers(t *testing.T) {
 	}
 }
 
+// A gracefully deleted pod whose kubelet never confirms the deletion is expired
+// by etcd shortly after its grace period, even if its status is updated meanwhile.
+func TestEtcdDeletePodGracefulExpiresWithoutKubelet(t *testing.T) {
+	registry, _, status, fakeClient, helper := newStorage(t)
+	ctx := api.NewDefaultContext()
+	fakeClient.TestIndex = true
+
+	key, _ := registry.KeyFunc(ctx, "foo")
+	podStart := validChangedPod()
+	podStart.Status.Host = "machine"
+	fakeClient.Set(key, runtime.EncodeOrDie(latest.Codec, podStart), 0)
+	if _, err := registry.Delete(ctx, "foo", api.NewDeleteOptions(30)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fakeClient.DeletedKeys) != 0 {
+		t.Errorf("Expected the pod to be kept until its grace period ends, found deletes %#v", fakeClient.DeletedKeys)
+	}
+	if ttl := fakeClient.Data[key].R.Node.TTL; ttl <= 30 || ttl > 90 {
+		t.Errorf("Expected a TTL just past the grace period, got %d", ttl)
+	}
+
+	var podIn api.Pod
+	if err := helper.ExtractObj(key, &podIn, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	podIn.Status.Message = "node is unreachable"
+	if _, _, err := status.Update(ctx, &podIn); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ttl := fakeClient.Data[key].R.Node.TTL; ttl <= 0 || ttl > 90 {
+		t.Errorf("Expected the update to keep the TTL, got %d", ttl)
+	}
+}
+
 func TestEtcdEmptyList(t *testing.T) {
 	registry, _, _, fakeClient, _ := newStorage(t)
 	ctx := api.NewDefaultContext()
@@ -75,9 +75,26 @@ func (podStrategy) ValidateUpdate(obj, old runtime.Object) fielderrors.Validatio
 	return validation.ValidatePodUpdate(obj.(*api.Pod), old.(*api.Pod))
 }
 
-// CheckGracefulDelete allows a pod to be gracefully deleted.
+// CheckGracefulDelete allows a pod to be gracefully deleted. It defaults the grace period to the
+// pod's TerminationGracePeriodSeconds, and deletes pods that are not running anywhere immediately.
 func (podStrategy) CheckGracefulDelete(obj runtime.Object, options *api.DeleteOptions) bool {
-	return false
+	if options == nil {
+		return false
+	}
+	pod := obj.(*api.Pod)
+	period := int64(api.DefaultTerminationGracePeriodSeconds)
+	// user has specified a value
+	if options.GracePeriodSeconds != nil {
+		period = *options.GracePeriodSeconds
+	} else if pod.Spec.TerminationGracePeriodSeconds != nil {
+		period = *pod.Spec.TerminationGracePeriodSeconds
+	}
+	// no kubelet owns an unscheduled or terminated pod, so there is nobody to confirm its termination
+	if len(pod.Status.Host) == 0 || pod.Status.Phase == api.PodSucceeded || pod.Status.Phase == api.PodFailed {
+		period = 0
+	}
+	options.GracePeriodSeconds = &period
+	return true
 }
 
 type podStatusStrategy struct {
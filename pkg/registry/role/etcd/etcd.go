@@ -0,0 +1,90 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/auth/authorizer/rbac"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/generic"
+	etcdgeneric "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/generic/etcd"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/role"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+)
+
+// rest implements a RESTStorage for roles against etcd
+type REST struct {
+	*etcdgeneric.Etcd
+	escalation rbac.EscalationChecker
+}
+
+// NewStorage returns a RESTStorage object that will work against Role objects.
+// If escalation is not nil, users may only write rules whose rights they already hold.
+func NewStorage(h tools.EtcdHelper, escalation rbac.EscalationChecker) *REST {
+	prefix := "/registry/roles"
+	store := &etcdgeneric.Etcd{
+		NewFunc:     func() runtime.Object { return &api.Role{} },
+		NewListFunc: func() runtime.Object { return &api.RoleList{} },
+		KeyRootFunc: func(ctx api.Context) string {
+			return etcdgeneric.NamespaceKeyRootFunc(ctx, prefix)
+		},
+		KeyFunc: func(ctx api.Context, name string) (string, error) {
+			return etcdgeneric.NamespaceKeyFunc(ctx, prefix, name)
+		},
+		ObjectNameFunc: func(obj runtime.Object) (string, error) {
+			return obj.(*api.Role).Name, nil
+		},
+		PredicateFunc: func(label labels.Selector, field fields.Selector) generic.Matcher {
+			return role.MatchRole(label, field)
+		},
+		EndpointName: "roles",
+
+		Helper: h,
+	}
+
+	store.CreateStrategy = role.Strategy
+	store.UpdateStrategy = role.Strategy
+	store.ReturnDeletedObject = true
+
+	return &REST{store, escalation}
+}
+
+// Create checks that the user holds the rules of the role before creating it.
+func (r *REST) Create(ctx api.Context, obj runtime.Object) (runtime.Object, error) {
+	if err := r.confirmNoEscalation(ctx, obj); err != nil {
+		return nil, err
+	}
+	return r.Etcd.Create(ctx, obj)
+}
+
+// Update checks that the user holds the rules of the role before updating it.
+func (r *REST) Update(ctx api.Context, obj runtime.Object) (runtime.Object, bool, error) {
+	if err := r.confirmNoEscalation(ctx, obj); err != nil {
+		return nil, false, err
+	}
+	return r.Etcd.Update(ctx, obj)
+}
+
+func (r *REST) confirmNoEscalation(ctx api.Context, obj runtime.Object) error {
+	roleObj, ok := obj.(*api.Role)
+	if r.escalation == nil || !ok {
+		return nil
+	}
+	return r.escalation.ConfirmNoRuleEscalation(ctx, api.NamespaceValue(ctx), "Role", roleObj.Name, roleObj.Rules)
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/latest"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/rest/resttest"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/role"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+
+	"github.com/coreos/go-etcd/etcd"
+)
+
+func newStorage(t *testing.T) (*REST, *tools.FakeEtcdClient, tools.EtcdHelper) {
+	fakeEtcdClient := tools.NewFakeEtcdClient(t)
+	fakeEtcdClient.TestIndex = true
+	helper := tools.NewEtcdHelper(fakeEtcdClient, latest.Codec)
+	return NewStorage(helper, nil), fakeEtcdClient, helper
+}
+
+func validNewRole(name string) *api.Role {
+	return &api.Role{
+		ObjectMeta: api.ObjectMeta{
+			Name:      name,
+			Namespace: api.NamespaceDefault,
+		},
+		Rules: []api.PolicyRule{{Verbs: []string{"update"}, Resources: []string{"pods/status"}}},
+	}
+}
+
+func TestStorage(t *testing.T) {
+	storage, _, _ := newStorage(t)
+	role.NewRegistry(storage)
+}
+
+func TestCreate(t *testing.T) {
+	storage, fakeEtcdClient, _ := newStorage(t)
+	test := resttest.New(t, storage, fakeEtcdClient.SetError)
+	obj := validNewRole("foo")
+	obj.ObjectMeta = api.ObjectMeta{}
+	test.TestCreate(
+		// valid
+		obj,
+		// invalid
+		&api.Role{
+			ObjectMeta: api.ObjectMeta{Name: "*BadName!"},
+		},
+	)
+}
+
+func TestEtcdListRoles(t *testing.T) {
+	storage, fakeClient, _ := newStorage(t)
+	ctx := api.NewDefaultContext()
+	key := storage.KeyRootFunc(ctx)
+	fakeClient.Data[key] = tools.EtcdResponseWithError{
+		R: &etcd.Response{
+			Node: &etcd.Node{
+				Nodes: []*etcd.Node{
+					{Value: runtime.EncodeOrDie(latest.Codec, validNewRole("foo"))},
+					{Value: runtime.EncodeOrDie(latest.Codec, validNewRole("bar"))},
+				},
+			},
+		},
+		E: nil,
+	}
+	obj, err := storage.List(ctx, labels.Everything(), fields.Everything())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	list := obj.(*api.RoleList)
+	if len(list.Items) != 2 || list.Items[0].Name != "foo" || list.Items[1].Name != "bar" {
+		t.Errorf("Unexpected role list: %#v", list)
+	}
+}
+
+type fakeEscalationChecker struct {
+	namespace string
+	kind      string
+	name      string
+	rules     []api.PolicyRule
+	err       error
+}
+
+func (f *fakeEscalationChecker) ConfirmNoEscalation(ctx api.Context, namespace string, ref api.RoleRef) error {
+	return nil
+}
+
+func (f *fakeEscalationChecker) ConfirmNoRuleEscalation(ctx api.Context, namespace, kind, name string, rules []api.PolicyRule) error {
+	f.namespace, f.kind, f.name, f.rules = namespace, kind, name, rules
+	return f.err
+}
+
+func TestCreateChecksEscalation(t *testing.T) {
+	fakeEtcdClient := tools.NewFakeEtcdClient(t)
+	fakeEtcdClient.TestIndex = true
+	helper := tools.NewEtcdHelper(fakeEtcdClient, latest.Codec)
+	checker := &fakeEscalationChecker{err: errors.New("escalation")}
+	storage := NewStorage(helper, checker)
+
+	obj := validNewRole("foo")
+	if _, err := storage.Create(api.NewDefaultContext(), obj); err != checker.err {
+		t.Errorf("expected the escalation error, got %v", err)
+	}
+	if checker.namespace != api.NamespaceDefault || checker.kind != "Role" || checker.name != "foo" || !reflect.DeepEqual(checker.rules, obj.Rules) {
+		t.Errorf("unexpected escalation check of %s %q with %v in %q", checker.kind, checker.name, checker.rules, checker.namespace)
+	}
+	if _, _, err := storage.Update(api.NewDefaultContext(), obj); err != checker.err {
+		t.Errorf("expected the escalation error, got %v", err)
+	}
+
+	checker.err = nil
+	if _, err := storage.Create(api.NewDefaultContext(), obj); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rolebinding
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/rest"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+)
+
+// Registry is an interface implemented by things that know how to store RoleBinding objects.
+type Registry interface {
+	// ListRoleBindings obtains a list of RoleBindings having labels which match selector.
+	ListRoleBindings(ctx api.Context, selector labels.Selector) (*api.RoleBindingList, error)
+	// Watch for new/changed/deleted RoleBindings
+	WatchRoleBindings(ctx api.Context, label labels.Selector, field fields.Selector, resourceVersion string) (watch.Interface, error)
+	// Get a specific RoleBinding
+	GetRoleBinding(ctx api.Context, name string) (*api.RoleBinding, error)
+	// Create a RoleBinding based on a specification.
+	CreateRoleBinding(ctx api.Context, rolebinding *api.RoleBinding) error
+	// Update an existing RoleBinding
+	UpdateRoleBinding(ctx api.Context, rolebinding *api.RoleBinding) error
+	// Delete an existing RoleBinding
+	DeleteRoleBinding(ctx api.Context, name string) error
+}
+
+// storage puts strong typing around storage calls
+type storage struct {
+	rest.StandardStorage
+}
+
+// NewRegistry returns a new Registry interface for the given Storage. Any mismatched
+// types will panic.
+func NewRegistry(s rest.StandardStorage) Registry {
+	return &storage{s}
+}
+
+func (s *storage) ListRoleBindings(ctx api.Context, label labels.Selector) (*api.RoleBindingList, error) {
+	obj, err := s.List(ctx, label, fields.Everything())
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*api.RoleBindingList), nil
+}
+
+func (s *storage) WatchRoleBindings(ctx api.Context, label labels.Selector, field fields.Selector, resourceVersion string) (watch.Interface, error) {
+	return s.Watch(ctx, label, field, resourceVersion)
+}
+
+func (s *storage) GetRoleBinding(ctx api.Context, name string) (*api.RoleBinding, error) {
+	obj, err := s.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*api.RoleBinding), nil
+}
+
+func (s *storage) CreateRoleBinding(ctx api.Context, rolebinding *api.RoleBinding) error {
+	_, err := s.Create(ctx, rolebinding)
+	return err
+}
+
+func (s *storage) UpdateRoleBinding(ctx api.Context, rolebinding *api.RoleBinding) error {
+	_, _, err := s.Update(ctx, rolebinding)
+	return err
+}
+
+func (s *storage) DeleteRoleBinding(ctx api.Context, name string) error {
+	_, err := s.Delete(ctx, name, nil)
+	return err
+}
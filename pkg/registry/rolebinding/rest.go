@@ -0,0 +1,83 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rolebinding
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/validation"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/generic"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/fielderrors"
+)
+
+// rolebindingStrategy implements behavior for RoleBinding objects
+type rolebindingStrategy struct {
+	runtime.ObjectTyper
+	api.NameGenerator
+}
+
+// Strategy is the default logic that applies when creating and updating RoleBinding
+// objects via the REST API.
+var Strategy = rolebindingStrategy{api.Scheme, api.SimpleNameGenerator}
+
+// NamespaceScoped is true for rolebindings.
+func (rolebindingStrategy) NamespaceScoped() bool {
+	return true
+}
+
+// ResetBeforeCreate clears fields that are not allowed to be set by end users on creation.
+func (rolebindingStrategy) ResetBeforeCreate(obj runtime.Object) {
+}
+
+// Validate validates a new role binding.
+func (rolebindingStrategy) Validate(obj runtime.Object) fielderrors.ValidationErrorList {
+	return validation.ValidateRoleBinding(obj.(*api.RoleBinding))
+}
+
+// AllowCreateOnUpdate is false for rolebindings.
+func (rolebindingStrategy) AllowCreateOnUpdate() bool {
+	return false
+}
+
+// ValidateUpdate is the default update validation for an end user.
+func (rolebindingStrategy) ValidateUpdate(obj, old runtime.Object) fielderrors.ValidationErrorList {
+	return validation.ValidateRoleBindingUpdate(old.(*api.RoleBinding), obj.(*api.RoleBinding))
+}
+
+// MatchRoleBinding returns a generic matcher for a given label and field selector.
+func MatchRoleBinding(label labels.Selector, field fields.Selector) generic.Matcher {
+	return generic.MatcherFunc(func(obj runtime.Object) (bool, error) {
+		rolebindingObj, ok := obj.(*api.RoleBinding)
+		if !ok {
+			return false, fmt.Errorf("not a role binding")
+		}
+		fields := RoleBindingToSelectableFields(rolebindingObj)
+		return label.Matches(labels.Set(rolebindingObj.Labels)) && field.Matches(fields), nil
+	})
+}
+
+// RoleBindingToSelectableFields returns a label set that represents the object
+// TODO: fields are not labels, and the validation rules for them do not apply.
+func RoleBindingToSelectableFields(rolebinding *api.RoleBinding) labels.Set {
+	return labels.Set{
+		"name": rolebinding.Name,
+	}
+}
@@ -87,6 +87,7 @@ func (rs *REST) Update(ctx api.Context, obj runtime.Object) (runtime.Object, boo
 	}
 
 	editSecret := oldObj.(*api.Secret)
+	oldSecret := *editSecret
 
 	// set the editable fields on the existing object
 	editSecret.Labels = secret.Labels
@@ -95,7 +96,7 @@ func (rs *REST) Update(ctx api.Context, obj runtime.Object) (runtime.Object, boo
 	editSecret.Data = secret.Data
 	editSecret.Type = secret.Type
 
-	if errs := validation.ValidateSecret(editSecret); len(errs) > 0 {
+	if errs := validation.ValidateSecretUpdate(editSecret, &oldSecret); len(errs) > 0 {
 		return nil, false, errors.NewInvalid("secret", editSecret.Name, errs)
 	}
 
@@ -23,6 +23,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
@@ -232,20 +233,38 @@ func (rs *REST) Update(ctx api.Context, obj runtime.Object) (runtime.Object, boo
 var _ = rest.Redirector(&REST{})
 
 // ResourceLocation returns a URL to which one can send traffic for the specified service.
+// The id may be of the form "name:portname" to select a specific named port.
 func (rs *REST) ResourceLocation(ctx api.Context, id string) (*url.URL, http.RoundTripper, error) {
-	eps, err := rs.endpoints.GetEndpoints(ctx, id)
+	svcName, portName := id, ""
+	if i := strings.Index(id, ":"); i >= 0 {
+		svcName, portName = id[:i], id[i+1:]
+	}
+	eps, err := rs.endpoints.GetEndpoints(ctx, svcName)
 	if err != nil {
 		return nil, nil, err
 	}
-	if len(eps.Endpoints) == 0 {
-		return nil, nil, fmt.Errorf("no endpoints available for %v", id)
+	if len(eps.Subsets) == 0 {
+		return nil, nil, fmt.Errorf("no endpoints available for %v", svcName)
+	}
+	// Pick a random Subset to start searching from.
+	ssSeed := rand.Intn(len(eps.Subsets))
+	// Find a Subset that has the port.
+	for ssi := 0; ssi < len(eps.Subsets); ssi++ {
+		ss := &eps.Subsets[(ssSeed+ssi)%len(eps.Subsets)]
+		for i := range ss.Ports {
+			if ss.Ports[i].Name == portName && len(ss.Addresses) > 0 {
+				// Pick a random address.
+				ip := ss.Addresses[rand.Intn(len(ss.Addresses))].IP
+				port := ss.Ports[i].Port
+				// We leave off the scheme ('http://') because we have no idea what sort of server
+				// is listening at this endpoint.
+				return &url.URL{
+					Host: net.JoinHostPort(ip, strconv.Itoa(port)),
+				}, nil, nil
+			}
+		}
 	}
-	// We leave off the scheme ('http://') because we have no idea what sort of server
-	// is listening at this endpoint.
-	ep := &eps.Endpoints[rand.Intn(len(eps.Endpoints))]
-	return &url.URL{
-		Host: net.JoinHostPort(ep.IP, strconv.Itoa(ep.Port)),
-	}, nil, nil
+	return nil, nil, fmt.Errorf("no endpoints available for %q", id)
 }
 
 func (rs *REST) getLoadbalancerName(ctx api.Context, service *api.Service) string {
@@ -256,7 +275,11 @@ func (rs *REST) createExternalLoadBalancer(ctx api.Context, service *api.Service
 	if rs.cloud == nil {
 		return fmt.Errorf("requested an external service, but no cloud provider supplied.")
 	}
-	if service.Spec.Protocol != api.ProtocolTCP {
+	if len(service.Spec.Ports) != 1 {
+		// TODO: Support multiple ports here too.
+		return fmt.Errorf("external load balancers for services with multiple ports are not currently supported.")
+	}
+	if service.Spec.Ports[0].Protocol != api.ProtocolTCP {
 		// TODO: Support UDP here too.
 		return fmt.Errorf("external load balancers for non TCP services are not currently supported.")
 	}
@@ -281,14 +304,14 @@ func (rs *REST) createExternalLoadBalancer(ctx api.Context, service *api.Service
 	var affinityType api.AffinityType = service.Spec.SessionAffinity
 	if len(service.Spec.PublicIPs) > 0 {
 		for _, publicIP := range service.Spec.PublicIPs {
-			_, err = balancer.CreateTCPLoadBalancer(name, zone.Region, net.ParseIP(publicIP), service.Spec.Port, hostsFromMinionList(hosts), affinityType)
+			_, err = balancer.CreateTCPLoadBalancer(name, zone.Region, net.ParseIP(publicIP), service.Spec.Ports[0].Port, hostsFromMinionList(hosts), affinityType)
 			if err != nil {
 				// TODO: have to roll-back any successful calls.
 				return err
 			}
 		}
 	} else {
-		endpoint, err := balancer.CreateTCPLoadBalancer(name, zone.Region, nil, service.Spec.Port, hostsFromMinionList(hosts), affinityType)
+		endpoint, err := balancer.CreateTCPLoadBalancer(name, zone.Region, nil, service.Spec.Ports[0].Port, hostsFromMinionList(hosts), affinityType)
 		if err != nil {
 			return err
 		}
@@ -328,11 +351,18 @@ func externalLoadBalancerNeedsUpdate(old, new *api.Service) bool {
 		return false
 	}
 	if old.Spec.CreateExternalLoadBalancer != new.Spec.CreateExternalLoadBalancer ||
-		old.Spec.Port != new.Spec.Port ||
-		old.Spec.SessionAffinity != new.Spec.SessionAffinity ||
-		old.Spec.Protocol != new.Spec.Protocol {
+		old.Spec.SessionAffinity != new.Spec.SessionAffinity {
 		return true
 	}
+	if len(old.Spec.Ports) != len(new.Spec.Ports) {
+		return true
+	}
+	for i := range old.Spec.Ports {
+		if old.Spec.Ports[i].Port != new.Spec.Ports[i].Port ||
+			old.Spec.Ports[i].Protocol != new.Spec.Ports[i].Protocol {
+			return true
+		}
+	}
 	if len(old.Spec.PublicIPs) != len(new.Spec.PublicIPs) {
 		return true
 	}
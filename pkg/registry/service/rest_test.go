@@ -59,9 +59,8 @@ func TestServiceRegistryCreate(t *testing.T) {
 	svc := &api.Service{
 		ObjectMeta: api.ObjectMeta{Name: "foo"},
 		Spec: api.ServiceSpec{
-			Port:            6502,
+			Ports:           []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 			Selector:        map[string]string{"bar": "baz"},
-			Protocol:        api.ProtocolTCP,
 			SessionAffinity: api.AffinityTypeNone,
 		},
 	}
@@ -98,9 +97,8 @@ func TestServiceStorageValidatesCreate(t *testing.T) {
 		"empty ID": {
 			ObjectMeta: api.ObjectMeta{Name: ""},
 			Spec: api.ServiceSpec{
-				Port:            6502,
+				Ports:           []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 				Selector:        map[string]string{"bar": "baz"},
-				Protocol:        api.ProtocolTCP,
 				SessionAffinity: api.AffinityTypeNone,
 			},
 		},
@@ -108,7 +106,6 @@ func TestServiceStorageValidatesCreate(t *testing.T) {
 			ObjectMeta: api.ObjectMeta{Name: "foo"},
 			Spec: api.ServiceSpec{
 				Selector:        map[string]string{"bar": "baz"},
-				Protocol:        api.ProtocolTCP,
 				SessionAffinity: api.AffinityTypeNone,
 			},
 		},
@@ -132,16 +129,15 @@ func TestServiceRegistryUpdate(t *testing.T) {
 	registry.CreateService(ctx, &api.Service{
 		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault},
 		Spec: api.ServiceSpec{
-			Port:     6502,
+			Ports:    []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 			Selector: map[string]string{"bar": "baz1"},
 		},
 	})
 	updated_svc, created, err := storage.Update(ctx, &api.Service{
 		ObjectMeta: api.ObjectMeta{Name: "foo"},
 		Spec: api.ServiceSpec{
-			Port:            6502,
+			Ports:           []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 			Selector:        map[string]string{"bar": "baz2"},
-			Protocol:        api.ProtocolTCP,
 			SessionAffinity: api.AffinityTypeNone,
 		},
 	})
@@ -169,7 +165,7 @@ func TestServiceStorageValidatesUpdate(t *testing.T) {
 	registry.CreateService(ctx, &api.Service{
 		ObjectMeta: api.ObjectMeta{Name: "foo"},
 		Spec: api.ServiceSpec{
-			Port:     6502,
+			Ports:    []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 			Selector: map[string]string{"bar": "baz"},
 		},
 	})
@@ -177,18 +173,16 @@ func TestServiceStorageValidatesUpdate(t *testing.T) {
 		"empty ID": {
 			ObjectMeta: api.ObjectMeta{Name: ""},
 			Spec: api.ServiceSpec{
-				Port:            6502,
+				Ports:           []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 				Selector:        map[string]string{"bar": "baz"},
-				Protocol:        api.ProtocolTCP,
 				SessionAffinity: api.AffinityTypeNone,
 			},
 		},
 		"invalid selector": {
 			ObjectMeta: api.ObjectMeta{Name: "foo"},
 			Spec: api.ServiceSpec{
-				Port:            6502,
+				Ports:           []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 				Selector:        map[string]string{"ThisSelectorFailsValidation": "ok"},
-				Protocol:        api.ProtocolTCP,
 				SessionAffinity: api.AffinityTypeNone,
 			},
 		},
@@ -210,10 +204,9 @@ func TestServiceRegistryExternalService(t *testing.T) {
 	svc := &api.Service{
 		ObjectMeta: api.ObjectMeta{Name: "foo"},
 		Spec: api.ServiceSpec{
-			Port:                       6502,
+			Ports:                      []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 			Selector:                   map[string]string{"bar": "baz"},
 			CreateExternalLoadBalancer: true,
-			Protocol:                   api.ProtocolTCP,
 			SessionAffinity:            api.AffinityTypeNone,
 		},
 	}
@@ -239,10 +232,9 @@ func TestServiceRegistryExternalServiceError(t *testing.T) {
 	svc := &api.Service{
 		ObjectMeta: api.ObjectMeta{Name: "foo"},
 		Spec: api.ServiceSpec{
-			Port:                       6502,
+			Ports:                      []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 			Selector:                   map[string]string{"bar": "baz"},
 			CreateExternalLoadBalancer: true,
-			Protocol:                   api.ProtocolTCP,
 			SessionAffinity:            api.AffinityTypeNone,
 		},
 	}
@@ -263,7 +255,6 @@ func TestServiceRegistryDelete(t *testing.T) {
 		ObjectMeta: api.ObjectMeta{Name: "foo"},
 		Spec: api.ServiceSpec{
 			Selector:        map[string]string{"bar": "baz"},
-			Protocol:        api.ProtocolTCP,
 			SessionAffinity: api.AffinityTypeNone,
 		},
 	}
@@ -285,7 +276,6 @@ func TestServiceRegistryDeleteExternal(t *testing.T) {
 		Spec: api.ServiceSpec{
 			Selector:                   map[string]string{"bar": "baz"},
 			CreateExternalLoadBalancer: true,
-			Protocol:                   api.ProtocolTCP,
 			SessionAffinity:            api.AffinityTypeNone,
 		},
 	}
@@ -307,10 +297,9 @@ func TestServiceRegistryUpdateExternalService(t *testing.T) {
 	svc1 := &api.Service{
 		ObjectMeta: api.ObjectMeta{Name: "foo"},
 		Spec: api.ServiceSpec{
-			Port:                       6502,
+			Ports:                      []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 			Selector:                   map[string]string{"bar": "baz"},
 			CreateExternalLoadBalancer: false,
-			Protocol:                   api.ProtocolTCP,
 			SessionAffinity:            api.AffinityTypeNone,
 		},
 	}
@@ -331,7 +320,7 @@ func TestServiceRegistryUpdateExternalService(t *testing.T) {
 	// Change port.
 	svc3 := new(api.Service)
 	*svc3 = *svc2
-	svc3.Spec.Port = 6504
+	svc3.Spec.Ports = []api.ServicePort{{Port: 6504, Protocol: api.ProtocolTCP}}
 	storage.Update(ctx, svc3)
 	if len(fakeCloud.Calls) != 6 || fakeCloud.Calls[0] != "get-zone" || fakeCloud.Calls[1] != "create" ||
 		fakeCloud.Calls[2] != "get-zone" || fakeCloud.Calls[3] != "delete" ||
@@ -367,13 +356,10 @@ func TestServiceRegistryResourceLocation(t *testing.T) {
 					Name:      "foo",
 					Namespace: api.NamespaceDefault,
 				},
-				Endpoints: []api.Endpoint{
-					{
-						IP:   "100.100.100.100",
-						Port: 80,
-					},
-				},
-				Protocol: api.ProtocolTCP,
+				Subsets: []api.EndpointSubset{{
+					Addresses: []api.EndpointAddress{{IP: "1.2.3.4"}},
+					Ports:     []api.EndpointPort{{Name: "", Port: 80}, {Name: "p", Port: 93}},
+				}},
 			},
 		},
 	}
@@ -392,7 +378,19 @@ func TestServiceRegistryResourceLocation(t *testing.T) {
 	if location == nil {
 		t.Errorf("Unexpected nil: %v", location)
 	}
-	if e, a := "//100.100.100.100:80", location.String(); e != a {
+	if e, a := "//1.2.3.4:80", location.String(); e != a {
+		t.Errorf("Expected %v, but got %v", e, a)
+	}
+
+	// Test a name + port.
+	location, _, err = redirector.ResourceLocation(ctx, "foo:p")
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if location == nil {
+		t.Errorf("Unexpected nil: %v", location)
+	}
+	if e, a := "//1.2.3.4:93", location.String(); e != a {
 		t.Errorf("Expected %v, but got %v", e, a)
 	}
 
@@ -445,8 +443,7 @@ func TestServiceRegistryIPAllocation(t *testing.T) {
 		ObjectMeta: api.ObjectMeta{Name: "foo"},
 		Spec: api.ServiceSpec{
 			Selector:        map[string]string{"bar": "baz"},
-			Port:            6502,
-			Protocol:        api.ProtocolTCP,
+			Ports:           []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 			SessionAffinity: api.AffinityTypeNone,
 		},
 	}
@@ -464,8 +461,7 @@ func TestServiceRegistryIPAllocation(t *testing.T) {
 		ObjectMeta: api.ObjectMeta{Name: "bar"},
 		Spec: api.ServiceSpec{
 			Selector:        map[string]string{"bar": "baz"},
-			Port:            6502,
-			Protocol:        api.ProtocolTCP,
+			Ports:           []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 			SessionAffinity: api.AffinityTypeNone,
 		}}
 	ctx = api.NewDefaultContext()
@@ -483,8 +479,7 @@ func TestServiceRegistryIPAllocation(t *testing.T) {
 		Spec: api.ServiceSpec{
 			Selector:        map[string]string{"bar": "baz"},
 			PortalIP:        "1.2.3.93",
-			Port:            6502,
-			Protocol:        api.ProtocolTCP,
+			Ports:           []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 			SessionAffinity: api.AffinityTypeNone,
 		},
 	}
@@ -504,8 +499,7 @@ func TestServiceRegistryIPReallocation(t *testing.T) {
 		ObjectMeta: api.ObjectMeta{Name: "foo"},
 		Spec: api.ServiceSpec{
 			Selector:        map[string]string{"bar": "baz"},
-			Port:            6502,
-			Protocol:        api.ProtocolTCP,
+			Ports:           []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 			SessionAffinity: api.AffinityTypeNone,
 		},
 	}
@@ -525,8 +519,7 @@ func TestServiceRegistryIPReallocation(t *testing.T) {
 		ObjectMeta: api.ObjectMeta{Name: "bar"},
 		Spec: api.ServiceSpec{
 			Selector:        map[string]string{"bar": "baz"},
-			Port:            6502,
-			Protocol:        api.ProtocolTCP,
+			Ports:           []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 			SessionAffinity: api.AffinityTypeNone,
 		},
 	}
@@ -549,16 +542,15 @@ func TestServiceRegistryIPUpdate(t *testing.T) {
 		ObjectMeta: api.ObjectMeta{Name: "foo"},
 		Spec: api.ServiceSpec{
 			Selector:        map[string]string{"bar": "baz"},
-			Port:            6502,
-			Protocol:        api.ProtocolTCP,
+			Ports:           []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 			SessionAffinity: api.AffinityTypeNone,
 		},
 	}
 	ctx := api.NewDefaultContext()
 	created_svc, _ := rest.Create(ctx, svc)
 	created_service := created_svc.(*api.Service)
-	if created_service.Spec.Port != 6502 {
-		t.Errorf("Expected port 6502, but got %v", created_service.Spec.Port)
+	if created_service.Spec.Ports[0].Port != 6502 {
+		t.Errorf("Expected port 6502, but got %v", created_service.Spec.Ports[0].Port)
 	}
 	if created_service.Spec.PortalIP != "1.2.3.1" {
 		t.Errorf("Unexpected PortalIP: %s", created_service.Spec.PortalIP)
@@ -566,16 +558,16 @@ func TestServiceRegistryIPUpdate(t *testing.T) {
 
 	update := new(api.Service)
 	*update = *created_service
-	update.Spec.Port = 6503
+	update.Spec.Ports = []api.ServicePort{{Port: 6503, Protocol: api.ProtocolTCP}}
 
 	updated_svc, _, _ := rest.Update(ctx, update)
 	updated_service := updated_svc.(*api.Service)
-	if updated_service.Spec.Port != 6503 {
-		t.Errorf("Expected port 6503, but got %v", updated_service.Spec.Port)
+	if updated_service.Spec.Ports[0].Port != 6503 {
+		t.Errorf("Expected port 6503, but got %v", updated_service.Spec.Ports[0].Port)
 	}
 
 	*update = *created_service
-	update.Spec.Port = 6503
+	update.Spec.Ports = []api.ServicePort{{Port: 6503, Protocol: api.ProtocolTCP}}
 	update.Spec.PortalIP = "1.2.3.76" // error
 
 	_, _, err := rest.Update(ctx, update)
@@ -592,17 +584,16 @@ func TestServiceRegistryIPExternalLoadBalancer(t *testing.T) {
 		ObjectMeta: api.ObjectMeta{Name: "foo"},
 		Spec: api.ServiceSpec{
 			Selector: map[string]string{"bar": "baz"},
-			Port:     6502,
+			Ports:    []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 			CreateExternalLoadBalancer: true,
-			Protocol:                   api.ProtocolTCP,
 			SessionAffinity:            api.AffinityTypeNone,
 		},
 	}
 	ctx := api.NewDefaultContext()
 	created_svc, _ := rest.Create(ctx, svc)
 	created_service := created_svc.(*api.Service)
-	if created_service.Spec.Port != 6502 {
-		t.Errorf("Expected port 6502, but got %v", created_service.Spec.Port)
+	if created_service.Spec.Ports[0].Port != 6502 {
+		t.Errorf("Expected port 6502, but got %v", created_service.Spec.Ports[0].Port)
 	}
 	if created_service.Spec.PortalIP != "1.2.3.1" {
 		t.Errorf("Unexpected PortalIP: %s", created_service.Spec.PortalIP)
@@ -633,8 +624,7 @@ func TestServiceRegistryIPReloadFromStorage(t *testing.T) {
 		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault},
 		Spec: api.ServiceSpec{
 			Selector:        map[string]string{"bar": "baz"},
-			Port:            6502,
-			Protocol:        api.ProtocolTCP,
+			Ports:           []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 			SessionAffinity: api.AffinityTypeNone,
 		},
 	}
@@ -644,8 +634,7 @@ func TestServiceRegistryIPReloadFromStorage(t *testing.T) {
 		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault},
 		Spec: api.ServiceSpec{
 			Selector:        map[string]string{"bar": "baz"},
-			Port:            6502,
-			Protocol:        api.ProtocolTCP,
+			Ports:           []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 			SessionAffinity: api.AffinityTypeNone,
 		},
 	}
@@ -660,8 +649,7 @@ func TestServiceRegistryIPReloadFromStorage(t *testing.T) {
 		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault},
 		Spec: api.ServiceSpec{
 			Selector:        map[string]string{"bar": "baz"},
-			Port:            6502,
-			Protocol:        api.ProtocolTCP,
+			Ports:           []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 			SessionAffinity: api.AffinityTypeNone,
 		},
 	}
@@ -720,8 +708,7 @@ func TestCreate(t *testing.T) {
 			Spec: api.ServiceSpec{
 				Selector:        map[string]string{"bar": "baz"},
 				PortalIP:        "None",
-				Port:            6502,
-				Protocol:        "TCP",
+				Ports:           []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 				SessionAffinity: "None",
 			},
 		},
@@ -733,8 +720,7 @@ func TestCreate(t *testing.T) {
 		&api.Service{
 			Spec: api.ServiceSpec{
 				Selector:        map[string]string{"bar": "baz"},
-				Port:            6502,
-				Protocol:        "TCP",
+				Ports:           []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 				PortalIP:        "invalid",
 				SessionAffinity: "None",
 			},
@@ -17,64 +17,161 @@ limitations under the License.
 package resourcequota
 
 import (
-	"sync"
+	"fmt"
 	"time"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 	"github.com/golang/glog"
 )
 
+// Bounds of the backoff applied to quotas whose usage failed to sync.
+const (
+	minRetryDelay = 5 * time.Millisecond
+	maxRetryDelay = 5 * time.Minute
+)
+
 // ResourceQuotaManager is responsible for tracking quota usage status in the system
 type ResourceQuotaManager struct {
 	kubeClient client.Interface
-	syncTime   <-chan time.Time
 
 	// To allow injection of syncUsage for testing.
 	syncHandler func(quota api.ResourceQuota) error
+
+	// quotaInformer and podInformer keep quotaStore and podStore up to date
+	// with the apiserver. The pod informer may be shared with other controllers.
+	quotaInformer *cache.Informer
+	quotaStore    cache.Indexer
+	podInformer   *cache.Informer
+	podStore      cache.Indexer
+
+	// queue holds the keys of the quotas whose usage needs to be recomputed.
+	queue *cache.RateLimitingQueue
 }
 
-// NewResourceQuotaManager creates a new ResourceQuotaManager
-func NewResourceQuotaManager(kubeClient client.Interface) *ResourceQuotaManager {
+// NewResourceQuotaManager creates a new ResourceQuotaManager. podInformer may
+// be shared with other controllers. Pod changes recompute the quotas of their
+// namespace right away; every quota is also recomputed every resyncPeriod to
+// account for the objects that aren't watched.
+func NewResourceQuotaManager(kubeClient client.Interface, podInformer *cache.Informer, resyncPeriod time.Duration) *ResourceQuotaManager {
 
 	rm := &ResourceQuotaManager{
 		kubeClient: kubeClient,
+		quotaInformer: cache.NewInformer(
+			&cache.ListWatch{
+				ListFunc: func() (runtime.Object, error) {
+					return kubeClient.ResourceQuotas(api.NamespaceAll).List(labels.Everything())
+				},
+				WatchFunc: func(resourceVersion string) (watch.Interface, error) {
+					return kubeClient.ResourceQuotas(api.NamespaceAll).Watch(labels.Everything(), fields.Everything(), resourceVersion)
+				},
+			},
+			&api.ResourceQuota{},
+			resyncPeriod,
+			nil,
+		),
+		podInformer: podInformer,
+		queue:       cache.NewRateLimitingQueue(minRetryDelay, maxRetryDelay),
 	}
+	rm.quotaStore = rm.quotaInformer.GetStore()
+	rm.podStore = podInformer.GetStore()
+	rm.quotaInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    rm.enqueueQuota,
+		UpdateFunc: func(old, cur interface{}) { rm.enqueueQuota(cur) },
+	})
+	podInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: rm.enqueueNamespaceQuotas,
+		UpdateFunc: func(old, cur interface{}) {
+			if old.(*api.Pod).Status.Phase != cur.(*api.Pod).Status.Phase {
+				rm.enqueueNamespaceQuotas(cur)
+			}
+		},
+		DeleteFunc: rm.enqueueNamespaceQuotas,
+	})
 
 	// set the synchronization handler
 	rm.syncHandler = rm.syncResourceQuota
 	return rm
 }
 
-// Run begins watching and syncing.
-func (rm *ResourceQuotaManager) Run(period time.Duration) {
-	rm.syncTime = time.Tick(period)
-	go util.Forever(func() { rm.synchronize() }, period)
+// Run starts the informers and, once they have synced, the given number of
+// workers. It returns immediately; everything stops when stopCh is closed.
+func (rm *ResourceQuotaManager) Run(workers int, stopCh <-chan struct{}) {
+	rm.quotaInformer.Run(stopCh)
+	rm.podInformer.Run(stopCh)
+	go func() {
+		defer util.HandleCrash()
+		if !cache.WaitForSync(stopCh, rm.quotaInformer, rm.podInformer) {
+			return
+		}
+		for i := 0; i < workers; i++ {
+			go util.Until(rm.worker, time.Second, stopCh)
+		}
+		<-stopCh
+		rm.queue.ShutDown()
+	}()
 }
 
-func (rm *ResourceQuotaManager) synchronize() {
-	var resourceQuotas []api.ResourceQuota
-	list, err := rm.kubeClient.ResourceQuotas(api.NamespaceAll).List(labels.Everything())
+// worker processes quotas from the queue until it is shut down.
+func (rm *ResourceQuotaManager) worker() {
+	for rm.processNextWorkItem() {
+	}
+}
+
+func (rm *ResourceQuotaManager) processNextWorkItem() bool {
+	key, quit := rm.queue.Get()
+	if quit {
+		return false
+	}
+	defer rm.queue.Done(key)
+	if err := rm.syncQuota(key); err != nil {
+		glog.Errorf("Error synchronizing quota %q: %v", key, err)
+		rm.queue.AddRateLimited(key)
+		return true
+	}
+	rm.queue.Forget(key)
+	return true
+}
+
+// syncQuota syncs the quota with the given key from the local cache.
+func (rm *ResourceQuotaManager) syncQuota(key string) error {
+	obj, exists, err := rm.quotaStore.GetByKey(key)
 	if err != nil {
-		glog.Errorf("Synchronization error: %v (%#v)", err, err)
-	}
-	resourceQuotas = list.Items
-	wg := sync.WaitGroup{}
-	wg.Add(len(resourceQuotas))
-	for ix := range resourceQuotas {
-		go func(ix int) {
-			defer wg.Done()
-			glog.V(4).Infof("periodic sync of %v/%v", resourceQuotas[ix].Namespace, resourceQuotas[ix].Name)
-			err := rm.syncHandler(resourceQuotas[ix])
-			if err != nil {
-				glog.Errorf("Error synchronizing: %v", err)
-			}
-		}(ix)
+		return err
+	}
+	if !exists {
+		return nil
+	}
+	glog.V(4).Infof("Syncing quota %v", key)
+	return rm.syncHandler(*obj.(*api.ResourceQuota))
+}
+
+func (rm *ResourceQuotaManager) enqueueQuota(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		util.HandleError(fmt.Errorf("couldn't get key for object %+v: %v", obj, err))
+		return
+	}
+	rm.queue.Add(key)
+}
+
+// enqueueNamespaceQuotas queues every quota in the namespace of obj.
+func (rm *ResourceQuotaManager) enqueueNamespaceQuotas(obj interface{}) {
+	quotas, err := rm.quotaStore.Index("namespace", obj)
+	if err != nil {
+		util.HandleError(fmt.Errorf("unable to list quotas for %+v: %v", obj, err))
+		return
+	}
+	for _, quota := range quotas {
+		rm.enqueueQuota(quota)
 	}
-	wg.Wait()
 }
 
 // FilterQuotaPods eliminates pods that no longer have a cost against the quota
@@ -141,15 +238,18 @@ func (rm *ResourceQuotaManager) syncResourceQuota(quota api.ResourceQuota) (err
 		set[k] = true
 	}
 
-	pods := &api.PodList{}
+	pods := []api.Pod{}
 	if set[api.ResourcePods] || set[api.ResourceMemory] || set[api.ResourceCPU] {
-		pods, err = rm.kubeClient.Pods(usage.Namespace).List(labels.Everything())
+		items, err := rm.podStore.Index("namespace", &quota)
 		if err != nil {
 			return err
 		}
+		for _, item := range items {
+			pods = append(pods, *item.(*api.Pod))
+		}
 	}
 
-	filteredPods := FilterQuotaPods(pods.Items)
+	filteredPods := FilterQuotaPods(pods)
 
 	// iterate over each resource, and update observation
 	for k := range usage.Status.Hard {
@@ -195,6 +295,18 @@ func (rm *ResourceQuotaManager) syncResourceQuota(quota api.ResourceQuota) (err
 				return err
 			}
 			value = resource.NewQuantity(int64(len(items.Items)), resource.DecimalSI)
+		case api.ResourceSecrets:
+			items, err := rm.kubeClient.Secrets(usage.Namespace).List(labels.Everything(), fields.Everything())
+			if err != nil {
+				return err
+			}
+			value = resource.NewQuantity(int64(len(items.Items)), resource.DecimalSI)
+		case api.ResourcePersistentVolumeClaims:
+			items, err := rm.kubeClient.PersistentVolumeClaims(usage.Namespace).List(labels.Everything(), fields.Everything())
+			if err != nil {
+				return err
+			}
+			value = resource.NewQuantity(int64(len(items.Items)), resource.DecimalSI)
 		}
 
 		// ignore fields we do not understand (assume another controller is tracking it)
@@ -214,20 +326,22 @@ func (rm *ResourceQuotaManager) syncResourceQuota(quota api.ResourceQuota) (err
 	return nil
 }
 
-// PodCPU computes total cpu usage of a pod
+// PodCPU computes total cpu usage of a pod, as the sum of the cpu requested
+// by its containers
 func PodCPU(pod *api.Pod) *resource.Quantity {
 	val := int64(0)
 	for j := range pod.Spec.Containers {
-		val = val + pod.Spec.Containers[j].Resources.Limits.Cpu().MilliValue()
+		val = val + pod.Spec.Containers[j].Resources.Requests.Cpu().MilliValue()
 	}
 	return resource.NewMilliQuantity(int64(val), resource.DecimalSI)
 }
 
-// PodMemory computes the memory usage of a pod
+// PodMemory computes the memory usage of a pod, as the sum of the memory
+// requested by its containers
 func PodMemory(pod *api.Pod) *resource.Quantity {
 	val := int64(0)
 	for j := range pod.Spec.Containers {
-		val = val + pod.Spec.Containers[j].Resources.Limits.Memory().Value()
+		val = val + pod.Spec.Containers[j].Resources.Requests.Memory().Value()
 	}
 	return resource.NewQuantity(int64(val), resource.DecimalSI)
 }
@@ -22,17 +22,18 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 )
 
 func getResourceRequirements(cpu, memory string) api.ResourceRequirements {
 	res := api.ResourceRequirements{}
-	res.Limits = api.ResourceList{}
+	res.Requests = api.ResourceList{}
 	if cpu != "" {
-		res.Limits[api.ResourceCPU] = resource.MustParse(cpu)
+		res.Requests[api.ResourceCPU] = resource.MustParse(cpu)
 	}
 	if memory != "" {
-		res.Limits[api.ResourceMemory] = resource.MustParse(memory)
+		res.Requests[api.ResourceMemory] = resource.MustParse(memory)
 	}
 
 	return res
@@ -129,32 +130,46 @@ func TestSyncResourceQuota(t *testing.T) {
 	quota := api.ResourceQuota{
 		Spec: api.ResourceQuotaSpec{
 			Hard: api.ResourceList{
-				api.ResourceCPU:    resource.MustParse("3"),
-				api.ResourceMemory: resource.MustParse("100Gi"),
-				api.ResourcePods:   resource.MustParse("5"),
+				api.ResourceCPU:                    resource.MustParse("3"),
+				api.ResourceMemory:                 resource.MustParse("100Gi"),
+				api.ResourcePods:                   resource.MustParse("5"),
+				api.ResourceSecrets:                resource.MustParse("10"),
+				api.ResourcePersistentVolumeClaims: resource.MustParse("10"),
 			},
 		},
 	}
 	expectedUsage := api.ResourceQuota{
 		Status: api.ResourceQuotaStatus{
 			Hard: api.ResourceList{
-				api.ResourceCPU:    resource.MustParse("3"),
-				api.ResourceMemory: resource.MustParse("100Gi"),
-				api.ResourcePods:   resource.MustParse("5"),
+				api.ResourceCPU:                    resource.MustParse("3"),
+				api.ResourceMemory:                 resource.MustParse("100Gi"),
+				api.ResourcePods:                   resource.MustParse("5"),
+				api.ResourceSecrets:                resource.MustParse("10"),
+				api.ResourcePersistentVolumeClaims: resource.MustParse("10"),
 			},
 			Used: api.ResourceList{
-				api.ResourceCPU:    resource.MustParse("200m"),
-				api.ResourceMemory: resource.MustParse("2147483648"),
-				api.ResourcePods:   resource.MustParse("2"),
+				api.ResourceCPU:                    resource.MustParse("200m"),
+				api.ResourceMemory:                 resource.MustParse("2147483648"),
+				api.ResourcePods:                   resource.MustParse("2"),
+				api.ResourceSecrets:                resource.MustParse("2"),
+				api.ResourcePersistentVolumeClaims: resource.MustParse("1"),
 			},
 		},
 	}
 
 	kubeClient := &client.Fake{
-		PodsList: podList,
+		SecretList: api.SecretList{
+			Items: []api.Secret{{ObjectMeta: api.ObjectMeta{Name: "secret-1"}}, {ObjectMeta: api.ObjectMeta{Name: "secret-2"}}},
+		},
+		PersistentVolumeClaimList: api.PersistentVolumeClaimList{
+			Items: []api.PersistentVolumeClaim{{ObjectMeta: api.ObjectMeta{Name: "claim"}}},
+		},
 	}
 
-	resourceQuotaManager := NewResourceQuotaManager(kubeClient)
+	resourceQuotaManager := NewResourceQuotaManager(kubeClient, cache.NewPodInformer(kubeClient, 0), 0)
+	for i := range podList.Items {
+		resourceQuotaManager.podStore.Add(&podList.Items[i])
+	}
 	err := resourceQuotaManager.syncResourceQuota(quota)
 	if err != nil {
 		t.Errorf("Unexpected error %v", err)
@@ -181,3 +196,18 @@ func TestSyncResourceQuota(t *testing.T) {
 	}
 
 }
+
+func TestPodChangesQueueNamespaceQuotas(t *testing.T) {
+	kubeClient := &client.Fake{}
+	resourceQuotaManager := NewResourceQuotaManager(kubeClient, cache.NewPodInformer(kubeClient, 0), 0)
+	resourceQuotaManager.quotaStore.Add(&api.ResourceQuota{ObjectMeta: api.ObjectMeta{Name: "quota", Namespace: "foo"}})
+	resourceQuotaManager.quotaStore.Add(&api.ResourceQuota{ObjectMeta: api.ObjectMeta{Name: "quota", Namespace: "bar"}})
+
+	resourceQuotaManager.enqueueNamespaceQuotas(&api.Pod{ObjectMeta: api.ObjectMeta{Name: "pod", Namespace: "foo"}})
+	if e, a := 1, resourceQuotaManager.queue.Len(); e != a {
+		t.Fatalf("Expected %d queued quotas, got %d", e, a)
+	}
+	if key, _ := resourceQuotaManager.queue.Get(); key != "foo/quota" {
+		t.Errorf("Expected foo/quota to be queued, got %s", key)
+	}
+}
@@ -17,14 +17,31 @@ limitations under the License.
 package runtime
 
 import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/conversion"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/yaml"
 )
 
+// ProtobufContentType is the media type of objects encoded by a codec from
+// ProtobufCodecFor.
+const ProtobufContentType = "application/x-protobuf"
+
 // CodecFor returns a Codec that invokes Encode with the provided version.
 func CodecFor(scheme *Scheme, version string) Codec {
 	return &codecWrapper{scheme, version}
 }
 
+// ProtobufCodecFor returns a Codec that invokes EncodeToVersionProtobuf with
+// the provided version. It decodes both protobuf and JSON.
+func ProtobufCodecFor(scheme *Scheme, version string) Codec {
+	return &protobufCodecWrapper{scheme, version}
+}
+
+// IsProtobuf returns true if data holds an object encoded by a codec from
+// ProtobufCodecFor.
+func IsProtobuf(data []byte) bool {
+	return conversion.IsProtobuf(data)
+}
+
 // yamlCodec converts YAML passed to the Decoder methods to JSON.
 type yamlCodec struct {
 	// a Codec for JSON
@@ -77,3 +94,15 @@ type codecWrapper struct {
 func (c *codecWrapper) Encode(obj Object) ([]byte, error) {
 	return c.Scheme.EncodeToVersion(obj, c.version)
 }
+
+// protobufCodecWrapper implements encoding to the protobuf wire format of a
+// version of a scheme.
+type protobufCodecWrapper struct {
+	*Scheme
+	version string
+}
+
+// Encode implements Codec
+func (c *protobufCodecWrapper) Encode(obj Object) ([]byte, error) {
+	return c.Scheme.EncodeToVersionProtobuf(obj, c.version)
+}
@@ -388,7 +388,14 @@ func (s *Scheme) EncodeToVersion(obj Object, destVersion string) (data []byte, e
 	return s.raw.EncodeToVersion(obj, destVersion)
 }
 
-// Decode converts a YAML or JSON string back into a pointer to an api object.
+// EncodeToVersionProtobuf is like EncodeToVersion, but writes the object in the
+// protobuf wire format. The fields of the versioned type must carry protobuf
+// field numbers. Decode and DecodeInto recognize the output.
+func (s *Scheme) EncodeToVersionProtobuf(obj Object, destVersion string) (data []byte, err error) {
+	return s.raw.EncodeToVersionProtobuf(obj, destVersion)
+}
+
+// Decode converts a YAML, JSON or protobuf string back into a pointer to an api object.
 // Deduces the type based upon the APIVersion and Kind fields, which are set
 // by Encode. Only versioned objects (APIVersion != "") are accepted. The object
 // will be converted into the in-memory unversioned type before being returned.
@@ -400,7 +407,7 @@ func (s *Scheme) Decode(data []byte) (Object, error) {
 	return obj.(Object), nil
 }
 
-// DecodeInto parses a YAML or JSON string and stores it in obj. Returns an error
+// DecodeInto parses a YAML, JSON or protobuf string and stores it in obj. Returns an error
 // if data.Kind is set and doesn't match the type of obj. Obj should be a
 // pointer to an api type.
 // If obj's APIVersion doesn't match that in data, an attempt will be made to convert
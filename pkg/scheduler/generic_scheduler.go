@@ -25,10 +25,16 @@ import (
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+
+	"github.com/golang/glog"
 )
 
 type FailedPredicateMap map[string]util.StringSet
 
+// extenderPredicateName is recorded in a FailedPredicateMap for the nodes a
+// scheduler extender filtered out.
+const extenderPredicateName = "extender"
+
 type FitError struct {
 	Pod              api.Pod
 	FailedPredicates FailedPredicateMap
@@ -46,6 +52,7 @@ func (f *FitError) Error() string {
 type genericScheduler struct {
 	predicates   map[string]FitPredicate
 	prioritizers []PriorityConfig
+	extenders    []SchedulerExtender
 	pods         PodLister
 	random       *rand.Rand
 	randomLock   sync.Mutex
@@ -60,12 +67,18 @@ func (g *genericScheduler) Schedule(pod api.Pod, minionLister MinionLister) (str
 		return "", fmt.Errorf("no minions available to schedule pods")
 	}
 
-	filteredNodes, failedPredicateMap, err := findNodesThatFit(pod, g.pods, g.predicates, minions)
+	filteredNodes, failedPredicateMap, err := findNodesThatFit(pod, g.pods, g.predicates, minions, g.extenders)
 	if err != nil {
 		return "", err
 	}
+	if len(filteredNodes.Items) == 0 {
+		return "", &FitError{
+			Pod:              pod,
+			FailedPredicates: failedPredicateMap,
+		}
+	}
 
-	priorityList, err := prioritizeNodes(pod, g.pods, g.prioritizers, FakeMinionLister(filteredNodes))
+	priorityList, err := prioritizeNodes(pod, g.pods, g.prioritizers, FakeMinionLister(filteredNodes), g.extenders)
 	if err != nil {
 		return "", err
 	}
@@ -97,7 +110,8 @@ func (g *genericScheduler) selectHost(priorityList HostPriorityList) (string, er
 
 // Filters the minions to find the ones that fit based on the given predicate functions
 // Each minion is passed through the predicate functions to determine if it is a fit
-func findNodesThatFit(pod api.Pod, podLister PodLister, predicates map[string]FitPredicate, nodes api.NodeList) (api.NodeList, FailedPredicateMap, error) {
+// The minions that fit are then passed through each extender, which may filter them further
+func findNodesThatFit(pod api.Pod, podLister PodLister, predicates map[string]FitPredicate, nodes api.NodeList, extenders []SchedulerExtender) (api.NodeList, FailedPredicateMap, error) {
 	filtered := []api.Node{}
 	machineToPods, err := MapPodsToMachines(podLister)
 	failedPredicateMap := FailedPredicateMap{}
@@ -124,7 +138,27 @@ func findNodesThatFit(pod api.Pod, podLister PodLister, predicates map[string]Fi
 			filtered = append(filtered, node)
 		}
 	}
-	return api.NodeList{Items: filtered}, failedPredicateMap, nil
+	filteredNodes := api.NodeList{Items: filtered}
+	for _, extender := range extenders {
+		if len(filteredNodes.Items) == 0 {
+			break
+		}
+		extenderFiltered, err := extender.Filter(pod, filteredNodes)
+		if err != nil {
+			return api.NodeList{}, FailedPredicateMap{}, err
+		}
+		fit := util.StringSet{}
+		for _, node := range extenderFiltered.Items {
+			fit.Insert(node.Name)
+		}
+		for _, node := range filteredNodes.Items {
+			if !fit.Has(node.Name) {
+				failedPredicateMap[node.Name] = util.NewStringSet(extenderPredicateName)
+			}
+		}
+		filteredNodes = extenderFiltered
+	}
+	return filteredNodes, failedPredicateMap, nil
 }
 
 // Prioritizes the minions by running the individual priority functions sequentially.
@@ -133,13 +167,17 @@ func findNodesThatFit(pod api.Pod, podLister PodLister, predicates map[string]Fi
 // Each priority function can also have its own weight
 // The minion scores returned by the priority function are multiplied by the weights to get weighted scores
 // All scores are finally combined (added) to get the total weighted scores of all minions
-func prioritizeNodes(pod api.Pod, podLister PodLister, priorityConfigs []PriorityConfig, minionLister MinionLister) (HostPriorityList, error) {
+// The weighted scores returned by the extenders are added on top of the built-in ones
+func prioritizeNodes(pod api.Pod, podLister PodLister, priorityConfigs []PriorityConfig, minionLister MinionLister, extenders []SchedulerExtender) (HostPriorityList, error) {
 	result := HostPriorityList{}
 
 	// If no priority configs are provided, then the EqualPriority function is applied
 	// This is required to generate the priority list in the required format
 	if len(priorityConfigs) == 0 {
-		return EqualPriority(pod, podLister, minionLister)
+		if len(extenders) == 0 {
+			return EqualPriority(pod, podLister, minionLister)
+		}
+		priorityConfigs = []PriorityConfig{{Function: EqualPriority, Weight: 1}}
 	}
 
 	combinedScores := map[string]int{}
@@ -155,11 +193,31 @@ func prioritizeNodes(pod api.Pod, podLister PodLister, priorityConfigs []Priorit
 			return HostPriorityList{}, err
 		}
 		for _, hostEntry := range prioritizedList {
-			combinedScores[hostEntry.host] += hostEntry.score * weight
+			combinedScores[hostEntry.Host] += hostEntry.Score * weight
+		}
+	}
+	if len(extenders) != 0 {
+		nodes, err := minionLister.List()
+		if err != nil {
+			return HostPriorityList{}, err
+		}
+		for _, extender := range extenders {
+			prioritizedList, weight, err := extender.Prioritize(pod, nodes)
+			if err != nil {
+				// an extender that fails to prioritize is skipped, the others still decide the placement
+				glog.Errorf("Error prioritizing nodes with extender: %v", err)
+				continue
+			}
+			for _, hostEntry := range prioritizedList {
+				if _, found := combinedScores[hostEntry.Host]; !found {
+					continue
+				}
+				combinedScores[hostEntry.Host] += hostEntry.Score * weight
+			}
 		}
 	}
 	for host, score := range combinedScores {
-		result = append(result, HostPriority{host: host, score: score})
+		result = append(result, HostPriority{Host: host, Score: score})
 	}
 	return result, nil
 }
@@ -167,8 +225,8 @@ func prioritizeNodes(pod api.Pod, podLister PodLister, priorityConfigs []Priorit
 func getBestHosts(list HostPriorityList) []string {
 	result := []string{}
 	for _, hostEntry := range list {
-		if hostEntry.score == list[0].score {
-			result = append(result, hostEntry.host)
+		if hostEntry.Score == list[0].Score {
+			result = append(result, hostEntry.Host)
 		} else {
 			break
 		}
@@ -187,17 +245,18 @@ func EqualPriority(pod api.Pod, podLister PodLister, minionLister MinionLister)
 	result := []HostPriority{}
 	for _, minion := range nodes.Items {
 		result = append(result, HostPriority{
-			host:  minion.Name,
-			score: 1,
+			Host:  minion.Name,
+			Score: 1,
 		})
 	}
 	return result, nil
 }
 
-func NewGenericScheduler(predicates map[string]FitPredicate, prioritizers []PriorityConfig, pods PodLister, random *rand.Rand) Scheduler {
+func NewGenericScheduler(predicates map[string]FitPredicate, prioritizers []PriorityConfig, extenders []SchedulerExtender, pods PodLister, random *rand.Rand) Scheduler {
 	return &genericScheduler{
 		predicates:   predicates,
 		prioritizers: prioritizers,
+		extenders:    extenders,
 		pods:         pods,
 		random:       random,
 	}
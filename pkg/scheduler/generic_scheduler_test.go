@@ -53,8 +53,8 @@ func numericPriority(pod api.Pod, podLister PodLister, minionLister MinionLister
 			return nil, err
 		}
 		result = append(result, HostPriority{
-			host:  minion.Name,
-			score: score,
+			Host:  minion.Name,
+			Score: score,
 		})
 	}
 	return result, nil
@@ -70,13 +70,13 @@ func reverseNumericPriority(pod api.Pod, podLister PodLister, minionLister Minio
 	}
 
 	for _, hostPriority := range result {
-		maxScore = math.Max(maxScore, float64(hostPriority.score))
-		minScore = math.Min(minScore, float64(hostPriority.score))
+		maxScore = math.Max(maxScore, float64(hostPriority.Score))
+		minScore = math.Min(minScore, float64(hostPriority.Score))
 	}
 	for _, hostPriority := range result {
 		reverseResult = append(reverseResult, HostPriority{
-			host:  hostPriority.host,
-			score: int(maxScore + minScore - float64(hostPriority.score)),
+			Host:  hostPriority.Host,
+			Score: int(maxScore + minScore - float64(hostPriority.Score)),
 		})
 	}
 
@@ -102,8 +102,8 @@ func TestSelectHost(t *testing.T) {
 	}{
 		{
 			list: []HostPriority{
-				{host: "machine1.1", score: 1},
-				{host: "machine2.1", score: 2},
+				{Host: "machine1.1", Score: 1},
+				{Host: "machine2.1", Score: 2},
 			},
 			possibleHosts: util.NewStringSet("machine2.1"),
 			expectsErr:    false,
@@ -111,10 +111,10 @@ func TestSelectHost(t *testing.T) {
 		// equal scores
 		{
 			list: []HostPriority{
-				{host: "machine1.1", score: 1},
-				{host: "machine1.2", score: 2},
-				{host: "machine1.3", score: 2},
-				{host: "machine2.1", score: 2},
+				{Host: "machine1.1", Score: 1},
+				{Host: "machine1.2", Score: 2},
+				{Host: "machine1.3", Score: 2},
+				{Host: "machine2.1", Score: 2},
 			},
 			possibleHosts: util.NewStringSet("machine1.2", "machine1.3", "machine2.1"),
 			expectsErr:    false,
@@ -122,11 +122,11 @@ func TestSelectHost(t *testing.T) {
 		// out of order scores
 		{
 			list: []HostPriority{
-				{host: "machine1.1", score: 3},
-				{host: "machine1.2", score: 3},
-				{host: "machine2.1", score: 2},
-				{host: "machine3.1", score: 1},
-				{host: "machine1.3", score: 3},
+				{Host: "machine1.1", Score: 3},
+				{Host: "machine1.2", Score: 3},
+				{Host: "machine2.1", Score: 2},
+				{Host: "machine3.1", Score: 1},
+				{Host: "machine1.3", Score: 3},
 			},
 			possibleHosts: util.NewStringSet("machine1.1", "machine1.2", "machine1.3"),
 			expectsErr:    false,
@@ -227,7 +227,7 @@ func TestGenericScheduler(t *testing.T) {
 
 	for _, test := range tests {
 		random := rand.New(rand.NewSource(0))
-		scheduler := NewGenericScheduler(test.predicates, test.prioritizers, FakePodLister([]api.Pod{}), random)
+		scheduler := NewGenericScheduler(test.predicates, test.prioritizers, nil, FakePodLister([]api.Pod{}), random)
 		machine, err := scheduler.Schedule(test.pod, FakeMinionLister(makeNodeList(test.nodes)))
 		if test.expectsErr {
 			if err == nil {
@@ -247,7 +247,7 @@ func TestGenericScheduler(t *testing.T) {
 func TestFindFitAllError(t *testing.T) {
 	nodes := []string{"3", "2", "1"}
 	predicates := map[string]FitPredicate{"true": truePredicate, "false": falsePredicate}
-	_, predicateMap, err := findNodesThatFit(api.Pod{}, FakePodLister([]api.Pod{}), predicates, makeNodeList(nodes))
+	_, predicateMap, err := findNodesThatFit(api.Pod{}, FakePodLister([]api.Pod{}), predicates, makeNodeList(nodes), nil)
 
 	if err != nil {
 		t.Errorf("unexpected error: %v")
@@ -272,7 +272,7 @@ func TestFindFitSomeError(t *testing.T) {
 	nodes := []string{"3", "2", "1"}
 	predicates := map[string]FitPredicate{"true": truePredicate, "match": matchesPredicate}
 	pod := api.Pod{ObjectMeta: api.ObjectMeta{Name: "1"}}
-	_, predicateMap, err := findNodesThatFit(pod, FakePodLister([]api.Pod{}), predicates, makeNodeList(nodes))
+	_, predicateMap, err := findNodesThatFit(pod, FakePodLister([]api.Pod{}), predicates, makeNodeList(nodes), nil)
 
 	if err != nil {
 		t.Errorf("unexpected error: %v")
@@ -295,3 +295,144 @@ func TestFindFitSomeError(t *testing.T) {
 		}
 	}
 }
+
+func TestFindFitExtenderError(t *testing.T) {
+	nodes := []string{"3", "2", "1"}
+	predicates := map[string]FitPredicate{"true": truePredicate}
+	extenders := []SchedulerExtender{&fakeExtender{predicate: matchesPredicate}}
+	pod := api.Pod{ObjectMeta: api.ObjectMeta{Name: "1"}}
+	filtered, predicateMap, err := findNodesThatFit(pod, FakePodLister([]api.Pod{}), predicates, makeNodeList(nodes), extenders)
+
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(filtered.Items) != 1 || filtered.Items[0].Name != pod.Name {
+		t.Errorf("unexpected filtered nodes: %v", filtered.Items)
+	}
+	if len(predicateMap) != (len(nodes) - 1) {
+		t.Errorf("unexpected failed predicate map: %v", predicateMap)
+	}
+	for _, node := range nodes {
+		if node == pod.Name {
+			continue
+		}
+		failures, found := predicateMap[node]
+		if !found {
+			t.Errorf("failed to find node: %s in %v", node, predicateMap)
+		}
+		if len(failures) != 1 || !failures.Has(extenderPredicateName) {
+			t.Errorf("unexpected failures: %v", failures)
+		}
+	}
+}
+
+type fakeExtender struct {
+	predicate FitPredicate
+	priority  PriorityFunction
+	weight    int
+	// prioritizeErr is returned by Prioritize when set
+	prioritizeErr error
+}
+
+func (f *fakeExtender) Filter(pod api.Pod, nodes api.NodeList) (api.NodeList, error) {
+	filtered := []api.Node{}
+	for _, node := range nodes.Items {
+		fits, err := f.predicate(pod, []api.Pod{}, node.Name)
+		if err != nil {
+			return api.NodeList{}, err
+		}
+		if fits {
+			filtered = append(filtered, node)
+		}
+	}
+	return api.NodeList{Items: filtered}, nil
+}
+
+func (f *fakeExtender) Prioritize(pod api.Pod, nodes api.NodeList) (HostPriorityList, int, error) {
+	if f.prioritizeErr != nil {
+		return HostPriorityList{}, 0, f.prioritizeErr
+	}
+	list, err := f.priority(pod, FakePodLister([]api.Pod{}), FakeMinionLister(nodes))
+	return list, f.weight, err
+}
+
+func TestGenericSchedulerWithExtenders(t *testing.T) {
+	tests := []struct {
+		name         string
+		predicates   map[string]FitPredicate
+		prioritizers []PriorityConfig
+		extenders    []SchedulerExtender
+		nodes        []string
+		pod          api.Pod
+		expectedHost string
+		expectsErr   bool
+	}{
+		{
+			name:       "extender filters out every node",
+			predicates: map[string]FitPredicate{"true": truePredicate},
+			extenders: []SchedulerExtender{
+				&fakeExtender{predicate: falsePredicate, priority: EqualPriority, weight: 1},
+			},
+			nodes:      []string{"1", "2"},
+			expectsErr: true,
+		},
+		{
+			name:       "extender filter is applied after the predicates",
+			predicates: map[string]FitPredicate{"true": truePredicate},
+			extenders: []SchedulerExtender{
+				&fakeExtender{predicate: matchesPredicate, priority: EqualPriority, weight: 1},
+			},
+			nodes:        []string{"1", "2", "3"},
+			pod:          api.Pod{ObjectMeta: api.ObjectMeta{Name: "2"}},
+			expectedHost: "2",
+		},
+		{
+			name:       "extender priorities are used when no priority is configured",
+			predicates: map[string]FitPredicate{"true": truePredicate},
+			extenders: []SchedulerExtender{
+				&fakeExtender{predicate: truePredicate, priority: numericPriority, weight: 1},
+			},
+			nodes:        []string{"1", "2", "3"},
+			expectedHost: "3",
+		},
+		{
+			name:         "extender priorities are weighted against the built-in ones",
+			predicates:   map[string]FitPredicate{"true": truePredicate},
+			prioritizers: []PriorityConfig{{Function: numericPriority, Weight: 1}},
+			extenders: []SchedulerExtender{
+				&fakeExtender{predicate: truePredicate, priority: reverseNumericPriority, weight: 2},
+			},
+			nodes:        []string{"1", "2", "3"},
+			expectedHost: "1",
+		},
+		{
+			name:         "a failing extender prioritization is ignored",
+			predicates:   map[string]FitPredicate{"true": truePredicate},
+			prioritizers: []PriorityConfig{{Function: numericPriority, Weight: 1}},
+			extenders: []SchedulerExtender{
+				&fakeExtender{predicate: truePredicate, priority: reverseNumericPriority, weight: 2, prioritizeErr: fmt.Errorf("unavailable")},
+			},
+			nodes:        []string{"1", "2", "3"},
+			expectedHost: "3",
+		},
+	}
+
+	for _, test := range tests {
+		random := rand.New(rand.NewSource(0))
+		scheduler := NewGenericScheduler(test.predicates, test.prioritizers, test.extenders, FakePodLister([]api.Pod{}), random)
+		machine, err := scheduler.Schedule(test.pod, FakeMinionLister(makeNodeList(test.nodes)))
+		if test.expectsErr {
+			if _, ok := err.(*FitError); !ok {
+				t.Errorf("%s: expected a FitError, got %v", test.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if test.expectedHost != machine {
+			t.Errorf("%s: expected %s, saw %s", test.name, test.expectedHost, machine)
+		}
+	}
+}
@@ -91,12 +91,14 @@ type resourceRequest struct {
 	memory   int64
 }
 
+// getResourceRequest returns the resources requested by the containers of a pod.
+// Pods are scheduled on their requests, their containers may use up to their limits.
 func getResourceRequest(pod *api.Pod) resourceRequest {
 	result := resourceRequest{}
 	for ix := range pod.Spec.Containers {
-		limits := pod.Spec.Containers[ix].Resources.Limits
-		result.memory += limits.Memory().Value()
-		result.milliCPU += limits.Cpu().MilliValue()
+		requests := pod.Spec.Containers[ix].Resources.Requests
+		result.memory += requests.Memory().Value()
+		result.milliCPU += requests.Cpu().MilliValue()
 	}
 	return result
 }
@@ -159,10 +161,14 @@ func NewSelectorMatchPredicate(info NodeInfo) FitPredicate {
 }
 
 func PodMatchesNodeLabels(pod *api.Pod, node *api.Node) bool {
-	if len(pod.Spec.NodeSelector) == 0 {
+	if len(pod.Spec.NodeSelector) == 0 && len(pod.Spec.NodeSelectorRequirements) == 0 {
 		return true
 	}
-	selector := labels.SelectorFromSet(pod.Spec.NodeSelector)
+	selector, err := api.LabelSelectorAsSelector(pod.Spec.NodeSelector, pod.Spec.NodeSelectorRequirements)
+	if err != nil {
+		// A pod with an invalid node selector does not fit anywhere.
+		return false
+	}
 	return selector.Matches(labels.Set(node.Labels))
 }
 
@@ -276,7 +282,10 @@ func (s *ServiceAffinity) CheckServiceAffinity(pod api.Pod, existingPods []api.P
 		if err == nil {
 			// just use the first service and get the other pods within the service
 			// TODO: a separate predicate can be created that tries to handle all services for the pod
-			selector := labels.SelectorFromSet(services[0].Spec.Selector)
+			selector, err := api.LabelSelectorAsSelector(services[0].Spec.Selector, services[0].Spec.SelectorRequirements)
+			if err != nil {
+				return false, err
+			}
 			servicePods, err := s.podLister.List(selector)
 			if err != nil {
 				return false, err
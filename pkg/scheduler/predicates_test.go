@@ -57,7 +57,7 @@ func newResourcePod(usage ...resourceRequest) api.Pod {
 	for _, req := range usage {
 		containers = append(containers, api.Container{
 			Resources: api.ResourceRequirements{
-				Limits: api.ResourceList{
+				Requests: api.ResourceList{
 					"cpu":    *resource.NewMilliQuantity(req.milliCPU, resource.DecimalSI),
 					"memory": *resource.NewQuantity(req.memory, resource.BinarySI),
 				},
@@ -71,6 +71,17 @@ func newResourcePod(usage ...resourceRequest) api.Pod {
 	}
 }
 
+// withResourceLimits sets the limits of every container of the pod.
+func withResourceLimits(pod api.Pod, milliCPU int64, memory int64) api.Pod {
+	for ix := range pod.Spec.Containers {
+		pod.Spec.Containers[ix].Resources.Limits = api.ResourceList{
+			"cpu":    *resource.NewMilliQuantity(milliCPU, resource.DecimalSI),
+			"memory": *resource.NewQuantity(memory, resource.BinarySI),
+		}
+	}
+	return pod
+}
+
 func TestPodFitsResources(t *testing.T) {
 	tests := []struct {
 		pod          api.Pod
@@ -118,6 +129,14 @@ func TestPodFitsResources(t *testing.T) {
 			fits: true,
 			test: "equal edge case",
 		},
+		{
+			pod: withResourceLimits(newResourcePod(resourceRequest{milliCPU: 1, memory: 1}), 10, 20),
+			existingPods: []api.Pod{
+				withResourceLimits(newResourcePod(resourceRequest{milliCPU: 5, memory: 5}), 10, 20),
+			},
+			fits: true,
+			test: "limits over capacity are ignored",
+		},
 	}
 	for _, test := range tests {
 		node := api.Node{Spec: api.NodeSpec{Capacity: makeResources(10, 20).Capacity}}
@@ -388,6 +407,71 @@ func TestPodFitsSelector(t *testing.T) {
 			fits: false,
 			test: "node labels are subset",
 		},
+		{
+			pod: api.Pod{
+				Spec: api.PodSpec{
+					NodeSelectorRequirements: []api.LabelSelectorRequirement{
+						{Key: "tier", Operator: api.LabelSelectorOpIn, Values: []string{"web", "api"}},
+					},
+				},
+			},
+			labels: map[string]string{
+				"tier": "api",
+			},
+			fits: true,
+			test: "node label in requirement values",
+		},
+		{
+			pod: api.Pod{
+				Spec: api.PodSpec{
+					NodeSelectorRequirements: []api.LabelSelectorRequirement{
+						{Key: "tier", Operator: api.LabelSelectorOpIn, Values: []string{"web", "api"}},
+					},
+				},
+			},
+			labels: map[string]string{
+				"tier": "db",
+			},
+			fits: false,
+			test: "node label not in requirement values",
+		},
+		{
+			pod: api.Pod{
+				Spec: api.PodSpec{
+					NodeSelector: map[string]string{
+						"foo": "bar",
+					},
+					NodeSelectorRequirements: []api.LabelSelectorRequirement{
+						{Key: "ssd", Operator: api.LabelSelectorOpExists},
+						{Key: "zone", Operator: api.LabelSelectorOpNotIn, Values: []string{"us-east"}},
+					},
+				},
+			},
+			labels: map[string]string{
+				"foo":  "bar",
+				"ssd":  "true",
+				"zone": "us-west",
+			},
+			fits: true,
+			test: "node labels match exact and set-based requirements",
+		},
+		{
+			pod: api.Pod{
+				Spec: api.PodSpec{
+					NodeSelector: map[string]string{
+						"foo": "bar",
+					},
+					NodeSelectorRequirements: []api.LabelSelectorRequirement{
+						{Key: "ssd", Operator: api.LabelSelectorOpExists},
+					},
+				},
+			},
+			labels: map[string]string{
+				"foo": "bar",
+			},
+			fits: false,
+			test: "node labels missing existing key",
+		},
 	}
 	for _, test := range tests {
 		node := api.Node{ObjectMeta: api.ObjectMeta{Labels: test.labels}}
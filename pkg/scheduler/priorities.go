@@ -42,15 +42,15 @@ func calculateOccupancy(pod api.Pod, node api.Node, pods []api.Pod) HostPriority
 	totalMemory := int64(0)
 	for _, existingPod := range pods {
 		for _, container := range existingPod.Spec.Containers {
-			totalMilliCPU += container.Resources.Limits.Cpu().MilliValue()
-			totalMemory += container.Resources.Limits.Memory().Value()
+			totalMilliCPU += container.Resources.Requests.Cpu().MilliValue()
+			totalMemory += container.Resources.Requests.Memory().Value()
 		}
 	}
 	// Add the resources requested by the current pod being scheduled.
 	// This also helps differentiate between differently sized, but empty, minions.
 	for _, container := range pod.Spec.Containers {
-		totalMilliCPU += container.Resources.Limits.Cpu().MilliValue()
-		totalMemory += container.Resources.Limits.Memory().Value()
+		totalMilliCPU += container.Resources.Requests.Cpu().MilliValue()
+		totalMemory += container.Resources.Requests.Memory().Value()
 	}
 
 	capacityMilliCPU := node.Spec.Capacity.Cpu().MilliValue()
@@ -67,8 +67,8 @@ func calculateOccupancy(pod api.Pod, node api.Node, pods []api.Pod) HostPriority
 	)
 
 	return HostPriority{
-		host:  node.Name,
-		score: int((cpuScore + memoryScore) / 2),
+		Host:  node.Name,
+		Score: int((cpuScore + memoryScore) / 2),
 	}
 }
 
@@ -128,7 +128,7 @@ func (n *NodeLabelPrioritizer) CalculateNodeLabelPriority(pod api.Pod, podLister
 		} else {
 			score = 0
 		}
-		result = append(result, HostPriority{host: minionName, score: score})
+		result = append(result, HostPriority{Host: minionName, Score: score})
 	}
 	return result, nil
 }
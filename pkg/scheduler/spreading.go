@@ -42,7 +42,10 @@ func (s *ServiceSpread) CalculateSpreadPriority(pod api.Pod, podLister PodLister
 	if err == nil {
 		// just use the first service and get the other pods within the service
 		// TODO: a separate predicate can be created that tries to handle all services for the pod
-		selector := labels.SelectorFromSet(services[0].Spec.Selector)
+		selector, err := api.LabelSelectorAsSelector(services[0].Spec.Selector, services[0].Spec.SelectorRequirements)
+		if err != nil {
+			return nil, err
+		}
 		pods, err := podLister.List(selector)
 		if err != nil {
 			return nil, err
@@ -80,7 +83,7 @@ func (s *ServiceSpread) CalculateSpreadPriority(pod api.Pod, podLister PodLister
 		if maxCount > 0 {
 			fScore = 10 * (float32(maxCount-counts[minion.Name]) / float32(maxCount))
 		}
-		result = append(result, HostPriority{host: minion.Name, score: int(fScore)})
+		result = append(result, HostPriority{Host: minion.Name, Score: int(fScore)})
 	}
 	return result, nil
 }
@@ -108,7 +111,10 @@ func (s *ServiceAntiAffinity) CalculateAntiAffinityPriority(pod api.Pod, podList
 	if err == nil {
 		// just use the first service and get the other pods within the service
 		// TODO: a separate predicate can be created that tries to handle all services for the pod
-		selector := labels.SelectorFromSet(services[0].Spec.Selector)
+		selector, err := api.LabelSelectorAsSelector(services[0].Spec.Selector, services[0].Spec.SelectorRequirements)
+		if err != nil {
+			return nil, err
+		}
 		pods, err := podLister.List(selector)
 		if err != nil {
 			return nil, err
@@ -157,11 +163,11 @@ func (s *ServiceAntiAffinity) CalculateAntiAffinityPriority(pod api.Pod, podList
 		if numServicePods > 0 {
 			fScore = 10 * (float32(numServicePods-podCounts[labeledMinions[minion]]) / float32(numServicePods))
 		}
-		result = append(result, HostPriority{host: minion, score: int(fScore)})
+		result = append(result, HostPriority{Host: minion, Score: int(fScore)})
 	}
 	// add the open minions with a score of 0
 	for _, minion := range otherMinions {
-		result = append(result, HostPriority{host: minion, score: 0})
+		result = append(result, HostPriority{Host: minion, Score: 0})
 	}
 
 	return result, nil
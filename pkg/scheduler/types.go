@@ -25,8 +25,8 @@ type FitPredicate func(pod api.Pod, existingPods []api.Pod, node string) (bool,
 
 // HostPriority represents the priority of scheduling to a particular host, lower priority is better.
 type HostPriority struct {
-	host  string
-	score int
+	Host  string
+	Score int
 }
 
 type HostPriorityList []HostPriority
@@ -36,10 +36,10 @@ func (h HostPriorityList) Len() int {
 }
 
 func (h HostPriorityList) Less(i, j int) bool {
-	if h[i].score == h[j].score {
-		return h[i].host < h[j].host
+	if h[i].Score == h[j].Score {
+		return h[i].Host < h[j].Host
 	}
-	return h[i].score < h[j].score
+	return h[i].Score < h[j].Score
 }
 
 func (h HostPriorityList) Swap(i, j int) {
@@ -52,3 +52,14 @@ type PriorityConfig struct {
 	Function PriorityFunction
 	Weight   int
 }
+
+// SchedulerExtender is an interface for external processes to influence the scheduling
+// decisions made by the generic scheduler.
+type SchedulerExtender interface {
+	// Filter returns the subset of the given nodes the pod fits on, as decided by the extender.
+	Filter(pod api.Pod, nodes api.NodeList) (filteredNodes api.NodeList, err error)
+
+	// Prioritize returns the scores the extender gives to the given nodes, together with the
+	// weight by which those scores are multiplied before being added to the built-in ones.
+	Prioritize(pod api.Pod, nodes api.NodeList) (hostPriorities HostPriorityList, weight int, err error)
+}
@@ -18,84 +18,249 @@ package service
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/endpoints"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/v1beta1"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/v1beta2"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 
 	"github.com/golang/glog"
 )
 
+// DefaultSyncPeriod is the time between full resyncs of every service from the local cache.
+const DefaultSyncPeriod = 30 * time.Second
+
+// Bounds of the backoff applied to services whose endpoints failed to sync.
+const (
+	minRetryDelay = 5 * time.Millisecond
+	maxRetryDelay = 5 * time.Minute
+)
+
 // EndpointController manages selector-based service endpoints.
 type EndpointController struct {
-	client *client.Client
+	client client.Interface
+
+	// serviceInformer and podInformer keep serviceStore and podStore up to
+	// date with the apiserver. The pod informer may be shared with other
+	// controllers.
+	serviceInformer *cache.Informer
+	serviceStore    cache.Indexer
+	podInformer     *cache.Informer
+	podStore        cache.Indexer
+
+	// queue holds the keys of the services whose endpoints need to be synced.
+	queue *cache.RateLimitingQueue
 }
 
-// NewEndpointController returns a new *EndpointController.
-func NewEndpointController(client *client.Client) *EndpointController {
-	return &EndpointController{
+// NewEndpointController returns a new *EndpointController. podInformer may be
+// shared with other controllers; every service is resynced from the local
+// cache every resyncPeriod.
+func NewEndpointController(client client.Interface, podInformer *cache.Informer, resyncPeriod time.Duration) *EndpointController {
+	e := &EndpointController{
 		client: client,
+		serviceInformer: cache.NewInformer(
+			&cache.ListWatch{
+				ListFunc: func() (runtime.Object, error) {
+					return client.Services(api.NamespaceAll).List(labels.Everything())
+				},
+				WatchFunc: func(resourceVersion string) (watch.Interface, error) {
+					return client.Services(api.NamespaceAll).Watch(labels.Everything(), fields.Everything(), resourceVersion)
+				},
+			},
+			&api.Service{},
+			resyncPeriod,
+			nil,
+		),
+		podInformer: podInformer,
+		queue:       cache.NewRateLimitingQueue(minRetryDelay, maxRetryDelay),
 	}
+	e.serviceStore = e.serviceInformer.GetStore()
+	e.podStore = podInformer.GetStore()
+	e.serviceInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    e.enqueueService,
+		UpdateFunc: func(old, cur interface{}) { e.enqueueService(cur) },
+		DeleteFunc: e.enqueueService,
+	})
+	podInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    e.addPod,
+		UpdateFunc: e.updatePod,
+		DeleteFunc: e.addPod,
+	})
+	return e
+}
+
+// Run starts the informers and, once they have synced, the given number of
+// workers. It returns immediately; everything stops when stopCh is closed.
+func (e *EndpointController) Run(workers int, stopCh <-chan struct{}) {
+	e.serviceInformer.Run(stopCh)
+	e.podInformer.Run(stopCh)
+	go func() {
+		defer util.HandleCrash()
+		if !cache.WaitForSync(stopCh, e.serviceInformer, e.podInformer) {
+			return
+		}
+		for i := 0; i < workers; i++ {
+			go util.Until(e.worker, time.Second, stopCh)
+		}
+		<-stopCh
+		e.queue.ShutDown()
+	}()
 }
 
-// SyncServiceEndpoints syncs endpoints for services with selectors.
-func (e *EndpointController) SyncServiceEndpoints() error {
-	services, err := e.client.Services(api.NamespaceAll).List(labels.Everything())
+// worker processes services from the queue until it is shut down.
+func (e *EndpointController) worker() {
+	for e.processNextWorkItem() {
+	}
+}
+
+func (e *EndpointController) processNextWorkItem() bool {
+	key, quit := e.queue.Get()
+	if quit {
+		return false
+	}
+	defer e.queue.Done(key)
+	if err := e.syncService(key); err != nil {
+		util.HandleError(fmt.Errorf("error syncing endpoints for service %q: %v", key, err))
+		e.queue.AddRateLimited(key)
+		return true
+	}
+	e.queue.Forget(key)
+	return true
+}
+
+func (e *EndpointController) enqueueService(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		util.HandleError(fmt.Errorf("couldn't get key for object %+v: %v", obj, err))
+		return
+	}
+	e.queue.Add(key)
+}
+
+// getPodServices returns the keys of the services in the pod's namespace
+// whose selector matches the pod.
+func (e *EndpointController) getPodServices(pod *api.Pod) []string {
+	items, err := e.serviceStore.Index("namespace", pod)
+	if err != nil {
+		util.HandleError(fmt.Errorf("unable to list services for pod %s/%s: %v", pod.Namespace, pod.Name, err))
+		return nil
+	}
+	keys := []string{}
+	for _, item := range items {
+		service := item.(*api.Service)
+		if service.Spec.Selector == nil && len(service.Spec.SelectorRequirements) == 0 {
+			continue
+		}
+		selector, err := api.LabelSelectorAsSelector(service.Spec.Selector, service.Spec.SelectorRequirements)
+		if err != nil || !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		if key, err := cache.MetaNamespaceKeyFunc(service); err == nil {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// addPod queues the services of a pod that was added or deleted.
+func (e *EndpointController) addPod(obj interface{}) {
+	for _, key := range e.getPodServices(obj.(*api.Pod)) {
+		e.queue.Add(key)
+	}
+}
+
+func (e *EndpointController) updatePod(old, cur interface{}) {
+	oldPod, curPod := old.(*api.Pod), cur.(*api.Pod)
+	keys := util.NewStringSet(e.getPodServices(curPod)...)
+	if !api.Semantic.DeepEqual(oldPod.Labels, curPod.Labels) {
+		keys.Insert(e.getPodServices(oldPod)...)
+	}
+	for _, key := range keys.List() {
+		e.queue.Add(key)
+	}
+}
+
+// syncService syncs the endpoints of the service with the given key with the
+// pods in the local cache.
+func (e *EndpointController) syncService(key string) error {
+	obj, exists, err := e.serviceStore.GetByKey(key)
 	if err != nil {
-		glog.Errorf("Failed to list services: %v", err)
 		return err
 	}
-	var resultErr error
-	for _, service := range services.Items {
-		if service.Spec.Selector == nil {
-			// services without a selector receive no endpoints from this controller;
-			// these services will receive the endpoints that are created out-of-band via the REST API.
+	if !exists {
+		// The service registry removes the endpoints of deleted services.
+		glog.V(5).Infof("Service %s has been deleted", key)
+		return nil
+	}
+	service := obj.(*api.Service)
+	if service.Spec.Selector == nil && len(service.Spec.SelectorRequirements) == 0 {
+		// services without a selector receive no endpoints from this controller;
+		// these services will receive the endpoints that are created out-of-band via the REST API.
+		return nil
+	}
+
+	glog.V(5).Infof("About to update endpoints for service %s/%s", service.Namespace, service.Name)
+	selector, err := api.LabelSelectorAsSelector(service.Spec.Selector, service.Spec.SelectorRequirements)
+	if err != nil {
+		// Retrying won't fix an invalid selector.
+		glog.Errorf("Invalid selector for service %s/%s: %v", service.Namespace, service.Name, err)
+		return nil
+	}
+	items, err := e.podStore.Index("namespace", service)
+	if err != nil {
+		return err
+	}
+	subsets := []api.EndpointSubset{}
+	for _, item := range items {
+		pod := item.(*api.Pod)
+		if !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		if pod.DeletionTimestamp != nil {
+			glog.V(5).Infof("Pod is being deleted: %v/%v", pod.Namespace, pod.Name)
+			continue
+		}
+		if len(pod.Status.PodIP) == 0 {
+			glog.Errorf("Failed to find an IP for pod %s/%s", pod.Namespace, pod.Name)
 			continue
 		}
 
-		glog.V(5).Infof("About to update endpoints for service %s/%s", service.Namespace, service.Name)
-		pods, err := e.client.Pods(service.Namespace).List(labels.Set(service.Spec.Selector).AsSelector())
-		if err != nil {
-			glog.Errorf("Error syncing service: %s/%s, skipping", service.Namespace, service.Name)
-			resultErr = err
+		inService := false
+		for _, c := range pod.Status.Conditions {
+			if c.Type == api.PodReady && c.Status == api.ConditionTrue {
+				inService = true
+				break
+			}
+		}
+		if !inService {
+			glog.V(5).Infof("Pod is out of service: %v/%v", pod.Namespace, pod.Name)
 			continue
 		}
-		endpoints := []api.Endpoint{}
 
-		for _, pod := range pods.Items {
+		for i := range service.Spec.Ports {
+			servicePort := &service.Spec.Ports[i]
 			// TODO: Once v1beta1 and v1beta2 are EOL'ed, this can
-			// assume that service.Spec.TargetPort is populated.
+			// assume that servicePort.TargetPort is populated.
 			_ = v1beta1.Dependency
 			_ = v1beta2.Dependency
-			port, err := findPort(&pod, &service)
+			portNum, err := findPort(pod, servicePort)
 			if err != nil {
-				glog.Errorf("Failed to find port for service %s/%s: %v", service.Namespace, service.Name, err)
-				continue
-			}
-			if len(pod.Status.PodIP) == 0 {
-				glog.Errorf("Failed to find an IP for pod %s/%s", pod.Namespace, pod.Name)
-				continue
-			}
-
-			inService := false
-			for _, c := range pod.Status.Conditions {
-				if c.Type == api.PodReady && c.Status == api.ConditionTrue {
-					inService = true
-					break
-				}
-			}
-			if !inService {
-				glog.V(5).Infof("Pod is out of service: %v/%v", pod.Namespace, pod.Name)
+				glog.Errorf("Failed to find port %q for service %s/%s: %v", servicePort.Name, service.Namespace, service.Name, err)
 				continue
 			}
 
-			endpoints = append(endpoints, api.Endpoint{
-				IP:   pod.Status.PodIP,
-				Port: port,
+			epp := api.EndpointPort{Name: servicePort.Name, Port: portNum, Protocol: servicePort.Protocol}
+			epa := api.EndpointAddress{
+				IP: pod.Status.PodIP,
 				TargetRef: &api.ObjectReference{
 					Kind:            "Pod",
 					Namespace:       pod.ObjectMeta.Namespace,
@@ -103,79 +268,40 @@ func (e *EndpointController) SyncServiceEndpoints() error {
 					UID:             pod.ObjectMeta.UID,
 					ResourceVersion: pod.ObjectMeta.ResourceVersion,
 				},
-			})
-		}
-		currentEndpoints, err := e.client.Endpoints(service.Namespace).Get(service.Name)
-		if err != nil {
-			if errors.IsNotFound(err) {
-				currentEndpoints = &api.Endpoints{
-					ObjectMeta: api.ObjectMeta{
-						Name: service.Name,
-					},
-					Protocol: service.Spec.Protocol,
-				}
-			} else {
-				glog.Errorf("Error getting endpoints: %v", err)
-				continue
-			}
-		}
-		newEndpoints := &api.Endpoints{}
-		*newEndpoints = *currentEndpoints
-		newEndpoints.Endpoints = endpoints
-
-		if len(currentEndpoints.ResourceVersion) == 0 {
-			// No previous endpoints, create them
-			_, err = e.client.Endpoints(service.Namespace).Create(newEndpoints)
-		} else {
-			// Pre-existing
-			if currentEndpoints.Protocol == service.Spec.Protocol && endpointsListEqual(currentEndpoints, endpoints) {
-				glog.V(5).Infof("protocol and endpoints are equal for %s/%s, skipping update", service.Namespace, service.Name)
-				continue
 			}
-			_, err = e.client.Endpoints(service.Namespace).Update(newEndpoints)
-		}
-		if err != nil {
-			glog.Errorf("Error updating endpoints: %v", err)
-			continue
+			subsets = append(subsets, api.EndpointSubset{Addresses: []api.EndpointAddress{epa}, Ports: []api.EndpointPort{epp}})
 		}
 	}
-	return resultErr
-}
-
-func endpointEqual(this, that *api.Endpoint) bool {
-	if this.IP != that.IP || this.Port != that.Port {
-		return false
-	}
-
-	if this.TargetRef == nil || that.TargetRef == nil {
-		return this.TargetRef == that.TargetRef
-	}
-
-	return *this.TargetRef == *that.TargetRef
-}
+	subsets = endpoints.RepackSubsets(subsets)
 
-func containsEndpoint(haystack *api.Endpoints, needle *api.Endpoint) bool {
-	if haystack == nil || needle == nil {
-		return false
-	}
-	for ix := range haystack.Endpoints {
-		if endpointEqual(&haystack.Endpoints[ix], needle) {
-			return true
+	currentEndpoints, err := e.client.Endpoints(service.Namespace).Get(service.Name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			currentEndpoints = &api.Endpoints{
+				ObjectMeta: api.ObjectMeta{
+					Name: service.Name,
+				},
+			}
+		} else {
+			return err
 		}
 	}
-	return false
-}
+	newEndpoints := &api.Endpoints{}
+	*newEndpoints = *currentEndpoints
+	newEndpoints.Subsets = subsets
 
-func endpointsListEqual(eps *api.Endpoints, endpoints []api.Endpoint) bool {
-	if len(eps.Endpoints) != len(endpoints) {
-		return false
-	}
-	for i := range endpoints {
-		if !containsEndpoint(eps, &endpoints[i]) {
-			return false
+	if len(currentEndpoints.ResourceVersion) == 0 {
+		// No previous endpoints, create them
+		_, err = e.client.Endpoints(service.Namespace).Create(newEndpoints)
+	} else {
+		// Pre-existing
+		if api.Semantic.DeepEqual(endpoints.RepackSubsets(currentEndpoints.Subsets), subsets) {
+			glog.V(5).Infof("endpoints are equal for %s/%s, skipping update", service.Namespace, service.Name)
+			return nil
 		}
+		_, err = e.client.Endpoints(service.Namespace).Update(newEndpoints)
 	}
-	return true
+	return err
 }
 
 func findDefaultPort(pod *api.Pod, servicePort int) (int, bool) {
@@ -194,13 +320,13 @@ func findDefaultPort(pod *api.Pod, servicePort int) (int, bool) {
 	return 0, false
 }
 
-// findPort locates the container port for the given manifest and portName.
-func findPort(pod *api.Pod, service *api.Service) (int, error) {
-	portName := service.Spec.TargetPort
+// findPort locates the container port for the given pod and service port.
+func findPort(pod *api.Pod, servicePort *api.ServicePort) (int, error) {
+	portName := servicePort.TargetPort
 	switch portName.Kind {
 	case util.IntstrString:
 		if len(portName.StrVal) == 0 {
-			if port, found := findDefaultPort(pod, service.Spec.Port); found {
+			if port, found := findDefaultPort(pod, servicePort.Port); found {
 				return port, nil
 			}
 			break
@@ -215,7 +341,7 @@ func findPort(pod *api.Pod, service *api.Service) (int, error) {
 		}
 	case util.IntstrInt:
 		if portName.IntVal == 0 {
-			if port, found := findDefaultPort(pod, service.Spec.Port); found {
+			if port, found := findDefaultPort(pod, servicePort.Port); found {
 				return port, nil
 			}
 			break
@@ -26,6 +26,7 @@ import (
 	_ "github.com/GoogleCloudPlatform/kubernetes/pkg/api/latest"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/testapi"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 )
@@ -35,7 +36,7 @@ func newPodList(count int) *api.PodList {
 	for i := 0; i < count; i++ {
 		pods = append(pods, api.Pod{
 			TypeMeta:   api.TypeMeta{APIVersion: testapi.Version()},
-			ObjectMeta: api.ObjectMeta{Name: fmt.Sprintf("pod%d", i)},
+			ObjectMeta: api.ObjectMeta{Name: fmt.Sprintf("pod%d", i), Labels: map[string]string{"foo": "bar"}},
 			Spec: api.PodSpec{
 				Containers: []api.Container{
 					{
@@ -213,7 +214,7 @@ func TestFindPort(t *testing.T) {
 		},
 	}
 	for _, test := range tests {
-		port, err := findPort(&test.pod, &api.Service{Spec: api.ServiceSpec{Port: servicePort, TargetPort: test.portName}})
+		port, err := findPort(&test.pod, &api.ServicePort{Protocol: "TCP", Port: servicePort, TargetPort: test.portName})
 		if port != test.wport {
 			t.Errorf("Expected port %d, Got %d", test.wport, port)
 		}
@@ -231,22 +232,12 @@ type serverResponse struct {
 	obj        interface{}
 }
 
-func makeTestServer(t *testing.T, namespace string, podResponse, serviceResponse, endpointsResponse serverResponse) (*httptest.Server, *util.FakeHandler) {
-	fakePodHandler := util.FakeHandler{
-		StatusCode:   podResponse.statusCode,
-		ResponseBody: runtime.EncodeOrDie(testapi.Codec(), podResponse.obj.(runtime.Object)),
-	}
-	fakeServiceHandler := util.FakeHandler{
-		StatusCode:   serviceResponse.statusCode,
-		ResponseBody: runtime.EncodeOrDie(testapi.Codec(), serviceResponse.obj.(runtime.Object)),
-	}
+func makeTestServer(t *testing.T, namespace string, endpointsResponse serverResponse) (*httptest.Server, *util.FakeHandler) {
 	fakeEndpointsHandler := util.FakeHandler{
 		StatusCode:   endpointsResponse.statusCode,
 		ResponseBody: runtime.EncodeOrDie(testapi.Codec(), endpointsResponse.obj.(runtime.Object)),
 	}
 	mux := http.NewServeMux()
-	mux.Handle(testapi.ResourcePath("pods", namespace, ""), &fakePodHandler)
-	mux.Handle(testapi.ResourcePath("services", "", ""), &fakeServiceHandler)
 	mux.Handle(testapi.ResourcePath("endpoints", namespace, ""), &fakeEndpointsHandler)
 	mux.Handle(testapi.ResourcePath("endpoints/", namespace, ""), &fakeEndpointsHandler)
 	mux.HandleFunc("/", func(res http.ResponseWriter, req *http.Request) {
@@ -256,29 +247,44 @@ func makeTestServer(t *testing.T, namespace string, podResponse, serviceResponse
 	return httptest.NewServer(mux), &fakeEndpointsHandler
 }
 
-func TestSyncEndpointsEmpty(t *testing.T) {
-	testServer, _ := makeTestServer(t, api.NamespaceDefault,
-		serverResponse{http.StatusOK, newPodList(0)},
-		serverResponse{http.StatusOK, &api.ServiceList{}},
-		serverResponse{http.StatusOK, &api.Endpoints{}})
-	defer testServer.Close()
-	client := client.NewOrDie(&client.Config{Host: testServer.URL, Version: testapi.Version()})
-	endpoints := NewEndpointController(client)
-	if err := endpoints.SyncServiceEndpoints(); err != nil {
-		t.Errorf("unexpected error: %v", err)
+// newTestController returns an EndpointController whose informers are never
+// run; its caches hold the given pods, placed in namespace, and services.
+func newTestController(client *client.Client, namespace string, pods *api.PodList, services *api.ServiceList) *EndpointController {
+	e := NewEndpointController(client, cache.NewPodInformer(client, 0), 0)
+	for i := range pods.Items {
+		pod := pods.Items[i]
+		pod.Namespace = namespace
+		e.podStore.Add(&pod)
+	}
+	for i := range services.Items {
+		e.serviceStore.Add(&services.Items[i])
 	}
+	return e
 }
 
-func TestSyncEndpointsError(t *testing.T) {
+// syncAll syncs every service in the controller's cache.
+func syncAll(e *EndpointController) error {
+	var resultErr error
+	for _, obj := range e.serviceStore.List() {
+		key, err := cache.MetaNamespaceKeyFunc(obj)
+		if err != nil {
+			return err
+		}
+		if err := e.syncService(key); err != nil {
+			resultErr = err
+		}
+	}
+	return resultErr
+}
+
+func TestSyncEndpointsEmpty(t *testing.T) {
 	testServer, _ := makeTestServer(t, api.NamespaceDefault,
-		serverResponse{http.StatusOK, newPodList(0)},
-		serverResponse{http.StatusInternalServerError, &api.ServiceList{}},
 		serverResponse{http.StatusOK, &api.Endpoints{}})
 	defer testServer.Close()
 	client := client.NewOrDie(&client.Config{Host: testServer.URL, Version: testapi.Version()})
-	endpoints := NewEndpointController(client)
-	if err := endpoints.SyncServiceEndpoints(); err == nil {
-		t.Errorf("unexpected non-error")
+	endpoints := newTestController(client, api.NamespaceDefault, newPodList(0), &api.ServiceList{})
+	if err := syncAll(endpoints); err != nil {
+		t.Errorf("unexpected error: %v", err)
 	}
 }
 
@@ -292,20 +298,20 @@ func TestSyncEndpointsItemsPreserveNoSelector(t *testing.T) {
 		},
 	}
 	testServer, endpointsHandler := makeTestServer(t, api.NamespaceDefault,
-		serverResponse{http.StatusOK, newPodList(0)},
-		serverResponse{http.StatusOK, &serviceList},
 		serverResponse{http.StatusOK, &api.Endpoints{
 			ObjectMeta: api.ObjectMeta{
 				Name:            "foo",
 				ResourceVersion: "1",
 			},
-			Protocol:  api.ProtocolTCP,
-			Endpoints: []api.Endpoint{{IP: "6.7.8.9", Port: 1000}},
+			Subsets: []api.EndpointSubset{{
+				Addresses: []api.EndpointAddress{{IP: "6.7.8.9"}},
+				Ports:     []api.EndpointPort{{Port: 1000, Protocol: "TCP"}},
+			}},
 		}})
 	defer testServer.Close()
 	client := client.NewOrDie(&client.Config{Host: testServer.URL, Version: testapi.Version()})
-	endpoints := NewEndpointController(client)
-	if err := endpoints.SyncServiceEndpoints(); err != nil {
+	endpoints := newTestController(client, api.NamespaceDefault, newPodList(0), &serviceList)
+	if err := syncAll(endpoints); err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
 	endpointsHandler.ValidateRequestCount(t, 0)
@@ -318,26 +324,26 @@ func TestSyncEndpointsProtocolTCP(t *testing.T) {
 				ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "other"},
 				Spec: api.ServiceSpec{
 					Selector: map[string]string{},
-					Protocol: api.ProtocolTCP,
+					Ports:    []api.ServicePort{{Port: 80}},
 				},
 			},
 		},
 	}
 	testServer, endpointsHandler := makeTestServer(t, "other",
-		serverResponse{http.StatusOK, newPodList(0)},
-		serverResponse{http.StatusOK, &serviceList},
 		serverResponse{http.StatusOK, &api.Endpoints{
 			ObjectMeta: api.ObjectMeta{
 				Name:            "foo",
 				ResourceVersion: "1",
 			},
-			Protocol:  api.ProtocolTCP,
-			Endpoints: []api.Endpoint{{IP: "6.7.8.9", Port: 1000}},
+			Subsets: []api.EndpointSubset{{
+				Addresses: []api.EndpointAddress{{IP: "6.7.8.9"}},
+				Ports:     []api.EndpointPort{{Port: 1000, Protocol: "TCP"}},
+			}},
 		}})
 	defer testServer.Close()
 	client := client.NewOrDie(&client.Config{Host: testServer.URL, Version: testapi.Version()})
-	endpoints := NewEndpointController(client)
-	if err := endpoints.SyncServiceEndpoints(); err != nil {
+	endpoints := newTestController(client, "other", newPodList(0), &serviceList)
+	if err := syncAll(endpoints); err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
 	endpointsHandler.ValidateRequestCount(t, 0)
@@ -350,26 +356,26 @@ func TestSyncEndpointsProtocolUDP(t *testing.T) {
 				ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "other"},
 				Spec: api.ServiceSpec{
 					Selector: map[string]string{},
-					Protocol: api.ProtocolUDP,
+					Ports:    []api.ServicePort{{Port: 80, Protocol: "UDP"}},
 				},
 			},
 		},
 	}
 	testServer, endpointsHandler := makeTestServer(t, "other",
-		serverResponse{http.StatusOK, newPodList(0)},
-		serverResponse{http.StatusOK, &serviceList},
 		serverResponse{http.StatusOK, &api.Endpoints{
 			ObjectMeta: api.ObjectMeta{
 				Name:            "foo",
 				ResourceVersion: "1",
 			},
-			Protocol:  api.ProtocolUDP,
-			Endpoints: []api.Endpoint{{IP: "6.7.8.9", Port: 1000}},
+			Subsets: []api.EndpointSubset{{
+				Addresses: []api.EndpointAddress{{IP: "6.7.8.9"}},
+				Ports:     []api.EndpointPort{{Port: 1000, Protocol: "UDP"}},
+			}},
 		}})
 	defer testServer.Close()
 	client := client.NewOrDie(&client.Config{Host: testServer.URL, Version: testapi.Version()})
-	endpoints := NewEndpointController(client)
-	if err := endpoints.SyncServiceEndpoints(); err != nil {
+	endpoints := newTestController(client, "other", newPodList(0), &serviceList)
+	if err := syncAll(endpoints); err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
 	endpointsHandler.ValidateRequestCount(t, 0)
@@ -382,25 +388,23 @@ func TestSyncEndpointsItemsEmptySelectorSelectsAll(t *testing.T) {
 				ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "other"},
 				Spec: api.ServiceSpec{
 					Selector: map[string]string{},
+					Ports:    []api.ServicePort{{Port: 80, Protocol: "TCP", TargetPort: util.NewIntOrStringFromInt(8080)}},
 				},
 			},
 		},
 	}
 	testServer, endpointsHandler := makeTestServer(t, "other",
-		serverResponse{http.StatusOK, newPodList(1)},
-		serverResponse{http.StatusOK, &serviceList},
 		serverResponse{http.StatusOK, &api.Endpoints{
 			ObjectMeta: api.ObjectMeta{
 				Name:            "foo",
 				ResourceVersion: "1",
 			},
-			Protocol:  api.ProtocolTCP,
-			Endpoints: []api.Endpoint{},
+			Subsets: []api.EndpointSubset{},
 		}})
 	defer testServer.Close()
 	client := client.NewOrDie(&client.Config{Host: testServer.URL, Version: testapi.Version()})
-	endpoints := NewEndpointController(client)
-	if err := endpoints.SyncServiceEndpoints(); err != nil {
+	endpoints := newTestController(client, "other", newPodList(1), &serviceList)
+	if err := syncAll(endpoints); err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
 	data := runtime.EncodeOrDie(testapi.Codec(), &api.Endpoints{
@@ -408,14 +412,9 @@ func TestSyncEndpointsItemsEmptySelectorSelectsAll(t *testing.T) {
 			Name:            "foo",
 			ResourceVersion: "1",
 		},
-		Protocol: api.ProtocolTCP,
-		Endpoints: []api.Endpoint{{
-			IP:   "1.2.3.4",
-			Port: 8080,
-			TargetRef: &api.ObjectReference{
-				Kind: "Pod",
-				Name: "pod0",
-			},
+		Subsets: []api.EndpointSubset{{
+			Addresses: []api.EndpointAddress{{IP: "1.2.3.4", TargetRef: &api.ObjectReference{Kind: "Pod", Namespace: "other", Name: "pod0"}}},
+			Ports:     []api.EndpointPort{{Port: 8080, Protocol: "TCP"}},
 		}},
 	})
 	endpointsHandler.ValidateRequest(t, testapi.ResourcePathWithQueryParams("endpoints", "other", "foo"), "PUT", &data)
@@ -430,25 +429,26 @@ func TestSyncEndpointsItemsPreexisting(t *testing.T) {
 					Selector: map[string]string{
 						"foo": "bar",
 					},
+					Ports: []api.ServicePort{{Port: 80, Protocol: "TCP", TargetPort: util.NewIntOrStringFromInt(8080)}},
 				},
 			},
 		},
 	}
 	testServer, endpointsHandler := makeTestServer(t, "bar",
-		serverResponse{http.StatusOK, newPodList(1)},
-		serverResponse{http.StatusOK, &serviceList},
 		serverResponse{http.StatusOK, &api.Endpoints{
 			ObjectMeta: api.ObjectMeta{
 				Name:            "foo",
 				ResourceVersion: "1",
 			},
-			Protocol:  api.ProtocolTCP,
-			Endpoints: []api.Endpoint{{IP: "6.7.8.9", Port: 1000}},
+			Subsets: []api.EndpointSubset{{
+				Addresses: []api.EndpointAddress{{IP: "6.7.8.9"}},
+				Ports:     []api.EndpointPort{{Port: 1000, Protocol: "TCP"}},
+			}},
 		}})
 	defer testServer.Close()
 	client := client.NewOrDie(&client.Config{Host: testServer.URL, Version: testapi.Version()})
-	endpoints := NewEndpointController(client)
-	if err := endpoints.SyncServiceEndpoints(); err != nil {
+	endpoints := newTestController(client, "bar", newPodList(1), &serviceList)
+	if err := syncAll(endpoints); err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
 	data := runtime.EncodeOrDie(testapi.Codec(), &api.Endpoints{
@@ -456,14 +456,9 @@ func TestSyncEndpointsItemsPreexisting(t *testing.T) {
 			Name:            "foo",
 			ResourceVersion: "1",
 		},
-		Protocol: api.ProtocolTCP,
-		Endpoints: []api.Endpoint{{
-			IP:   "1.2.3.4",
-			Port: 8080,
-			TargetRef: &api.ObjectReference{
-				Kind: "Pod",
-				Name: "pod0",
-			},
+		Subsets: []api.EndpointSubset{{
+			Addresses: []api.EndpointAddress{{IP: "1.2.3.4", TargetRef: &api.ObjectReference{Kind: "Pod", Namespace: "bar", Name: "pod0"}}},
+			Ports:     []api.EndpointPort{{Port: 8080, Protocol: "TCP"}},
 		}},
 	})
 	endpointsHandler.ValidateRequest(t, testapi.ResourcePathWithQueryParams("endpoints", "bar", "foo"), "PUT", &data)
@@ -478,31 +473,25 @@ func TestSyncEndpointsItemsPreexistingIdentical(t *testing.T) {
 					Selector: map[string]string{
 						"foo": "bar",
 					},
+					Ports: []api.ServicePort{{Port: 80, Protocol: "TCP", TargetPort: util.NewIntOrStringFromInt(8080)}},
 				},
 			},
 		},
 	}
 	testServer, endpointsHandler := makeTestServer(t, api.NamespaceDefault,
-		serverResponse{http.StatusOK, newPodList(1)},
-		serverResponse{http.StatusOK, &serviceList},
 		serverResponse{http.StatusOK, &api.Endpoints{
 			ObjectMeta: api.ObjectMeta{
 				ResourceVersion: "1",
 			},
-			Protocol: api.ProtocolTCP,
-			Endpoints: []api.Endpoint{{
-				IP:   "1.2.3.4",
-				Port: 8080,
-				TargetRef: &api.ObjectReference{
-					Kind: "Pod",
-					Name: "pod0",
-				},
+			Subsets: []api.EndpointSubset{{
+				Addresses: []api.EndpointAddress{{IP: "1.2.3.4", TargetRef: &api.ObjectReference{Kind: "Pod", Namespace: api.NamespaceDefault, Name: "pod0"}}},
+				Ports:     []api.EndpointPort{{Port: 8080, Protocol: "TCP"}},
 			}},
 		}})
 	defer testServer.Close()
 	client := client.NewOrDie(&client.Config{Host: testServer.URL, Version: testapi.Version()})
-	endpoints := NewEndpointController(client)
-	if err := endpoints.SyncServiceEndpoints(); err != nil {
+	endpoints := newTestController(client, api.NamespaceDefault, newPodList(1), &serviceList)
+	if err := syncAll(endpoints); err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
 	endpointsHandler.ValidateRequest(t, testapi.ResourcePathWithQueryParams("endpoints", api.NamespaceDefault, "foo"), "GET", nil)
@@ -517,32 +506,26 @@ func TestSyncEndpointsItems(t *testing.T) {
 					Selector: map[string]string{
 						"foo": "bar",
 					},
+					Ports: []api.ServicePort{{Port: 80, Protocol: "TCP", TargetPort: util.NewIntOrStringFromInt(8080)}},
 				},
 			},
 		},
 	}
 	testServer, endpointsHandler := makeTestServer(t, "other",
-		serverResponse{http.StatusOK, newPodList(1)},
-		serverResponse{http.StatusOK, &serviceList},
 		serverResponse{http.StatusOK, &api.Endpoints{}})
 	defer testServer.Close()
 	client := client.NewOrDie(&client.Config{Host: testServer.URL, Version: testapi.Version()})
-	endpoints := NewEndpointController(client)
-	if err := endpoints.SyncServiceEndpoints(); err != nil {
+	endpoints := newTestController(client, "other", newPodList(1), &serviceList)
+	if err := syncAll(endpoints); err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
 	data := runtime.EncodeOrDie(testapi.Codec(), &api.Endpoints{
 		ObjectMeta: api.ObjectMeta{
 			ResourceVersion: "",
 		},
-		Protocol: api.ProtocolTCP,
-		Endpoints: []api.Endpoint{{
-			IP:   "1.2.3.4",
-			Port: 8080,
-			TargetRef: &api.ObjectReference{
-				Kind: "Pod",
-				Name: "pod0",
-			},
+		Subsets: []api.EndpointSubset{{
+			Addresses: []api.EndpointAddress{{IP: "1.2.3.4", TargetRef: &api.ObjectReference{Kind: "Pod", Namespace: "other", Name: "pod0"}}},
+			Ports:     []api.EndpointPort{{Port: 8080, Protocol: "TCP"}},
 		}},
 	})
 	// endpointsHandler should get 2 requests - one for "GET" and the next for "POST".
@@ -550,7 +533,86 @@ func TestSyncEndpointsItems(t *testing.T) {
 	endpointsHandler.ValidateRequest(t, testapi.ResourcePathWithQueryParams("endpoints", "other", ""), "POST", &data)
 }
 
-func TestSyncEndpointsPodError(t *testing.T) {
+func TestSyncEndpointsSkipsTerminatingPods(t *testing.T) {
+	serviceList := api.ServiceList{
+		Items: []api.Service{
+			{
+				ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "other"},
+				Spec: api.ServiceSpec{
+					Selector: map[string]string{
+						"foo": "bar",
+					},
+					Ports: []api.ServicePort{{Port: 80, Protocol: "TCP", TargetPort: util.NewIntOrStringFromInt(8080)}},
+				},
+			},
+		},
+	}
+	testServer, endpointsHandler := makeTestServer(t, "other",
+		serverResponse{http.StatusOK, &api.Endpoints{}})
+	defer testServer.Close()
+	client := client.NewOrDie(&client.Config{Host: testServer.URL, Version: testapi.Version()})
+	pods := newPodList(2)
+	now := util.Now()
+	pods.Items[1].DeletionTimestamp = &now
+	pods.Items[1].Status.PodIP = "1.2.3.5"
+	endpoints := newTestController(client, "other", pods, &serviceList)
+	if err := syncAll(endpoints); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	data := runtime.EncodeOrDie(testapi.Codec(), &api.Endpoints{
+		ObjectMeta: api.ObjectMeta{
+			ResourceVersion: "",
+		},
+		Subsets: []api.EndpointSubset{{
+			Addresses: []api.EndpointAddress{{IP: "1.2.3.4", TargetRef: &api.ObjectReference{Kind: "Pod", Namespace: "other", Name: "pod0"}}},
+			Ports:     []api.EndpointPort{{Port: 8080, Protocol: "TCP"}},
+		}},
+	})
+	endpointsHandler.ValidateRequest(t, testapi.ResourcePathWithQueryParams("endpoints", "other", ""), "POST", &data)
+}
+
+func TestSyncEndpointsItemsMultiplePorts(t *testing.T) {
+	serviceList := api.ServiceList{
+		Items: []api.Service{
+			{
+				ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "other"},
+				Spec: api.ServiceSpec{
+					Selector: map[string]string{
+						"foo": "bar",
+					},
+					Ports: []api.ServicePort{
+						{Name: "port0", Port: 80, Protocol: "TCP", TargetPort: util.NewIntOrStringFromInt(8080)},
+						{Name: "port1", Port: 88, Protocol: "TCP", TargetPort: util.NewIntOrStringFromInt(8088)},
+					},
+				},
+			},
+		},
+	}
+	testServer, endpointsHandler := makeTestServer(t, "other",
+		serverResponse{http.StatusOK, &api.Endpoints{}})
+	defer testServer.Close()
+	client := client.NewOrDie(&client.Config{Host: testServer.URL, Version: testapi.Version()})
+	endpoints := newTestController(client, "other", newPodList(1), &serviceList)
+	if err := syncAll(endpoints); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	data := runtime.EncodeOrDie(testapi.Codec(), &api.Endpoints{
+		ObjectMeta: api.ObjectMeta{
+			ResourceVersion: "",
+		},
+		Subsets: []api.EndpointSubset{{
+			Addresses: []api.EndpointAddress{{IP: "1.2.3.4", TargetRef: &api.ObjectReference{Kind: "Pod", Namespace: "other", Name: "pod0"}}},
+			Ports: []api.EndpointPort{
+				{Name: "port0", Port: 8080, Protocol: "TCP"},
+				{Name: "port1", Port: 8088, Protocol: "TCP"},
+			},
+		}},
+	})
+	endpointsHandler.ValidateRequestCount(t, 2)
+	endpointsHandler.ValidateRequest(t, testapi.ResourcePathWithQueryParams("endpoints", "other", ""), "POST", &data)
+}
+
+func TestSyncEndpointsError(t *testing.T) {
 	serviceList := api.ServiceList{
 		Items: []api.Service{
 			{
@@ -559,18 +621,104 @@ func TestSyncEndpointsPodError(t *testing.T) {
 					Selector: map[string]string{
 						"foo": "bar",
 					},
+					Ports: []api.ServicePort{{Port: 80, Protocol: "TCP", TargetPort: util.NewIntOrStringFromInt(8080)}},
 				},
 			},
 		},
 	}
 	testServer, _ := makeTestServer(t, api.NamespaceDefault,
-		serverResponse{http.StatusInternalServerError, &api.PodList{}},
-		serverResponse{http.StatusOK, &serviceList},
+		serverResponse{http.StatusInternalServerError, &api.Endpoints{}})
+	defer testServer.Close()
+	client := client.NewOrDie(&client.Config{Host: testServer.URL, Version: testapi.Version()})
+	endpoints := newTestController(client, api.NamespaceDefault, newPodList(1), &serviceList)
+	if err := syncAll(endpoints); err == nil {
+		t.Errorf("unexpected non-error")
+	}
+}
+
+func TestSyncEndpointsSelectsOnlyMatchingPods(t *testing.T) {
+	serviceList := api.ServiceList{
+		Items: []api.Service{
+			{
+				ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "other"},
+				Spec: api.ServiceSpec{
+					Selector: map[string]string{
+						"foo": "baz",
+					},
+					Ports: []api.ServicePort{{Port: 80, Protocol: "TCP", TargetPort: util.NewIntOrStringFromInt(8080)}},
+				},
+			},
+		},
+	}
+	testServer, endpointsHandler := makeTestServer(t, "other",
 		serverResponse{http.StatusOK, &api.Endpoints{}})
 	defer testServer.Close()
 	client := client.NewOrDie(&client.Config{Host: testServer.URL, Version: testapi.Version()})
-	endpoints := NewEndpointController(client)
-	if err := endpoints.SyncServiceEndpoints(); err == nil {
-		t.Error("Unexpected non-error")
+	endpoints := newTestController(client, "other", newPodList(1), &serviceList)
+	// A matching pod in another namespace must not be selected either.
+	otherPod := newPodList(1).Items[0]
+	otherPod.Namespace = "elsewhere"
+	otherPod.Labels = map[string]string{"foo": "baz"}
+	endpoints.podStore.Add(&otherPod)
+	if err := syncAll(endpoints); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	data := runtime.EncodeOrDie(testapi.Codec(), &api.Endpoints{
+		ObjectMeta: api.ObjectMeta{
+			ResourceVersion: "",
+		},
+		Subsets: []api.EndpointSubset{},
+	})
+	endpointsHandler.ValidateRequest(t, testapi.ResourcePathWithQueryParams("endpoints", "other", ""), "POST", &data)
+}
+
+func TestSyncEndpointsDeletedService(t *testing.T) {
+	testServer, endpointsHandler := makeTestServer(t, api.NamespaceDefault,
+		serverResponse{http.StatusOK, &api.Endpoints{}})
+	defer testServer.Close()
+	client := client.NewOrDie(&client.Config{Host: testServer.URL, Version: testapi.Version()})
+	endpoints := newTestController(client, api.NamespaceDefault, newPodList(1), &api.ServiceList{})
+	if err := endpoints.syncService("default/foo"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	endpointsHandler.ValidateRequestCount(t, 0)
+}
+
+func TestPodChangesQueueServices(t *testing.T) {
+	serviceList := api.ServiceList{
+		Items: []api.Service{
+			{
+				ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "other"},
+				Spec:       api.ServiceSpec{Selector: map[string]string{"foo": "bar"}},
+			},
+			{
+				ObjectMeta: api.ObjectMeta{Name: "baz", Namespace: "other"},
+				Spec:       api.ServiceSpec{Selector: map[string]string{"foo": "baz"}},
+			},
+			{
+				ObjectMeta: api.ObjectMeta{Name: "noselector", Namespace: "other"},
+			},
+		},
+	}
+	client := client.NewOrDie(&client.Config{Host: "localhost", Version: testapi.Version()})
+	endpoints := newTestController(client, "other", &api.PodList{}, &serviceList)
+
+	pod := newPodList(1).Items[0]
+	pod.Namespace = "other"
+	endpoints.addPod(&pod)
+	if e, a := 1, endpoints.queue.Len(); e != a {
+		t.Fatalf("expected %d queued services, got %d", e, a)
+	}
+	key, _ := endpoints.queue.Get()
+	if key != "other/foo" {
+		t.Errorf("expected other/foo to be queued, got %s", key)
+	}
+	endpoints.queue.Done(key)
+
+	relabeled := pod
+	relabeled.Labels = map[string]string{"foo": "baz"}
+	endpoints.updatePod(&pod, &relabeled)
+	if e, a := 2, endpoints.queue.Len(); e != a {
+		t.Errorf("expected %d queued services, got %d", e, a)
 	}
 }
@@ -0,0 +1,67 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tools
+
+import (
+	"encoding/base64"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+)
+
+// base64Codec stores the binary output of a protobuf codec in etcd, whose
+// values are strings.
+type base64Codec struct {
+	runtime.Codec
+}
+
+// Base64Codec returns a codec for an EtcdHelper that encodes objects with
+// codec, which writes the protobuf wire format, and stores them in base64.
+// Values that do not hold base64 of protobuf, like the JSON written before the
+// storage format was changed, are passed to codec as they are, so that both
+// formats can be read while the objects in etcd are rewritten.
+func Base64Codec(codec runtime.Codec) runtime.Codec {
+	return base64Codec{codec}
+}
+
+func (c base64Codec) Encode(obj runtime.Object) ([]byte, error) {
+	data, err := c.Codec.Encode(obj)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, base64.StdEncoding.EncodedLen(len(data)))
+	base64.StdEncoding.Encode(out, data)
+	return out, nil
+}
+
+func (c base64Codec) Decode(data []byte) (runtime.Object, error) {
+	return c.Codec.Decode(decodeBase64Protobuf(data))
+}
+
+func (c base64Codec) DecodeInto(data []byte, obj runtime.Object) error {
+	return c.Codec.DecodeInto(decodeBase64Protobuf(data), obj)
+}
+
+// decodeBase64Protobuf returns the protobuf data holds in base64, or data
+// itself if it holds something else.
+func decodeBase64Protobuf(data []byte) []byte {
+	out := make([]byte, base64.StdEncoding.DecodedLen(len(data)))
+	n, err := base64.StdEncoding.Decode(out, data)
+	if err != nil || !runtime.IsProtobuf(out[:n]) {
+		return data
+	}
+	return out[:n]
+}
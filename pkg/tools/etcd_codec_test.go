@@ -0,0 +1,84 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tools
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/v1beta3"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+)
+
+func TestBase64CodecSetAndExtract(t *testing.T) {
+	obj := &api.Pod{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Labels: map[string]string{"a": "b"}},
+		Spec: api.PodSpec{
+			Containers: []api.Container{{
+				Name:                   "c",
+				Image:                  "busybox",
+				ImagePullPolicy:        api.PullIfNotPresent,
+				TerminationMessagePath: api.TerminationMessagePathDefault,
+			}},
+			RestartPolicy: api.RestartPolicyAlways,
+			DNSPolicy:     api.DNSClusterFirst,
+		},
+	}
+	fakeClient := NewFakeEtcdClient(t)
+	helper := NewEtcdHelper(fakeClient, Base64Codec(v1beta3.ProtobufCodec))
+	if err := helper.SetObj("/some/key", obj, nil, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	value := fakeClient.Data["/some/key"].R.Node.Value
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		t.Fatalf("expected base64, got %q: %v", value, err)
+	}
+	if !runtime.IsProtobuf(data) {
+		t.Errorf("expected protobuf, got %q", string(data))
+	}
+
+	got := &api.Pod{}
+	if err := helper.ExtractObj("/some/key", got, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got.ResourceVersion = ""
+	if !api.Semantic.DeepEqual(obj, got) {
+		t.Errorf("expected %#v, got %#v", obj, got)
+	}
+}
+
+func TestBase64CodecReadsJSON(t *testing.T) {
+	obj := &api.Pod{ObjectMeta: api.ObjectMeta{Name: "foo"}}
+	data, err := v1beta3.Codec.Encode(obj)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fakeClient := NewFakeEtcdClient(t)
+	fakeClient.Set("/some/key", string(data), 0)
+	helper := NewEtcdHelper(fakeClient, Base64Codec(v1beta3.ProtobufCodec))
+
+	got := &api.Pod{}
+	if err := helper.ExtractObj("/some/key", got, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Name != "foo" {
+		t.Errorf("unexpected object: %#v", got)
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iptables
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// FakeIPTables implements iptables.Interface in memory for tests. Rules are
+// recorded as their space-joined arguments, in the order they were appended.
+type FakeIPTables struct {
+	mu     sync.Mutex
+	Ipv6   bool
+	Chains map[Table]map[Chain][]string
+}
+
+// NewFake returns an empty FakeIPTables.
+func NewFake() *FakeIPTables {
+	return &FakeIPTables{Chains: map[Table]map[Chain][]string{}}
+}
+
+// EnsureChain is part of Interface.
+func (f *FakeIPTables) EnsureChain(table Table, chain Chain) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	chains := f.table(table)
+	if _, found := chains[chain]; found {
+		return true, nil
+	}
+	chains[chain] = []string{}
+	return false, nil
+}
+
+// FlushChain is part of Interface.
+func (f *FakeIPTables) FlushChain(table Table, chain Chain) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	chains := f.table(table)
+	if _, found := chains[chain]; !found {
+		return fmt.Errorf("chain %q does not exist in table %q", chain, table)
+	}
+	chains[chain] = []string{}
+	return nil
+}
+
+// DeleteChain is part of Interface.
+func (f *FakeIPTables) DeleteChain(table Table, chain Chain) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	chains := f.table(table)
+	if _, found := chains[chain]; !found {
+		return fmt.Errorf("chain %q does not exist in table %q", chain, table)
+	}
+	delete(chains, chain)
+	return nil
+}
+
+// EnsureRule is part of Interface. Rules may be appended to the built-in chains
+// without creating them first.
+func (f *FakeIPTables) EnsureRule(table Table, chain Chain, args ...string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	chains := f.table(table)
+	rule := strings.Join(args, " ")
+	for _, existing := range chains[chain] {
+		if existing == rule {
+			return true, nil
+		}
+	}
+	chains[chain] = append(chains[chain], rule)
+	return false, nil
+}
+
+// DeleteRule is part of Interface.
+func (f *FakeIPTables) DeleteRule(table Table, chain Chain, args ...string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	chains := f.table(table)
+	rule := strings.Join(args, " ")
+	for i, existing := range chains[chain] {
+		if existing == rule {
+			chains[chain] = append(chains[chain][:i], chains[chain][i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// ListRules is part of Interface.
+func (f *FakeIPTables) ListRules(table Table, chain Chain) ([][]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rules, found := f.table(table)[chain]
+	if !found {
+		return nil, fmt.Errorf("chain %q does not exist in table %q", chain, table)
+	}
+	listed := [][]string{}
+	for _, rule := range rules {
+		listed = append(listed, strings.Fields(rule))
+	}
+	return listed, nil
+}
+
+// ListChains is part of Interface.
+func (f *FakeIPTables) ListChains(table Table) ([]Chain, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	chains := []Chain{}
+	for chain := range f.table(table) {
+		switch chain {
+		case ChainPostrouting, ChainPrerouting, ChainOutput:
+			continue
+		}
+		chains = append(chains, chain)
+	}
+	return chains, nil
+}
+
+// IsIpv6 is part of Interface.
+func (f *FakeIPTables) IsIpv6() bool {
+	return f.Ipv6
+}
+
+// Rules returns a copy of the rules of the given chain.
+func (f *FakeIPTables) Rules(table Table, chain Chain) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rules := f.table(table)[chain]
+	return append([]string{}, rules...)
+}
+
+// HasChain returns true if the given chain exists.
+func (f *FakeIPTables) HasChain(table Table, chain Chain) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, found := f.table(table)[chain]
+	return found
+}
+
+// This assumes f.mu is locked.
+func (f *FakeIPTables) table(table Table) map[Chain][]string {
+	chains, found := f.Chains[table]
+	if !found {
+		chains = map[Chain][]string{}
+		f.Chains[table] = chains
+	}
+	return chains
+}
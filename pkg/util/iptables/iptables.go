@@ -40,6 +40,11 @@ type Interface interface {
 	EnsureRule(table Table, chain Chain, args ...string) (bool, error)
 	// DeleteRule checks if the specified rule is present and, if so, deletes it.
 	DeleteRule(table Table, chain Chain, args ...string) error
+	// ListRules returns the arguments of the rules of the specified chain, in order.  iptables
+	// lists rules in a normalized form, which may differ from the arguments they were added with.
+	ListRules(table Table, chain Chain) ([][]string, error)
+	// ListChains returns the user-defined chains of the specified table.
+	ListChains(table Table) ([]Chain, error)
 	// IsIpv6 returns true if this is managing ipv6 tables
 	IsIpv6() bool
 }
@@ -167,6 +172,55 @@ func (runner *runner) DeleteRule(table Table, chain Chain, args ...string) error
 	return nil
 }
 
+// ListRules is part of Interface.
+func (runner *runner) ListRules(table Table, chain Chain) ([][]string, error) {
+	fullArgs := makeFullArgs(table, chain)
+
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+
+	out, err := runner.run(opListRules, fullArgs)
+	if err != nil {
+		return nil, fmt.Errorf("error listing chain %q: %v: %s", chain, err, out)
+	}
+	rules := [][]string{}
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		// Skip the "-N <chain name>" line, and strip "-A <chain name>" from the rules.
+		if len(fields) < 2 || fields[0] != string(opAppendRule) || fields[1] != string(chain) {
+			continue
+		}
+		fields = fields[2:]
+		for i := range fields {
+			unquote(&fields[i])
+		}
+		rules = append(rules, fields)
+	}
+	return rules, nil
+}
+
+// ListChains is part of Interface.
+func (runner *runner) ListChains(table Table) ([]Chain, error) {
+	fullArgs := []string{"-t", string(table)}
+
+	runner.mu.Lock()
+	defer runner.mu.Unlock()
+
+	out, err := runner.run(opListRules, fullArgs)
+	if err != nil {
+		return nil, fmt.Errorf("error listing table %q: %v: %s", table, err, out)
+	}
+	chains := []Chain{}
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		// Built-in chains are listed with "-P <chain name> <policy>".
+		if len(fields) == 2 && fields[0] == string(opCreateChain) {
+			chains = append(chains, Chain(fields[1]))
+		}
+	}
+	return chains, nil
+}
+
 func (runner *runner) IsIpv6() bool {
 	return runner.protocol == ProtocolIpv6
 }
@@ -278,6 +332,7 @@ const (
 	opAppendRule  operation = "-A"
 	opCheckRule   operation = "-C"
 	opDeleteRule  operation = "-D"
+	opListRules   operation = "-S"
 )
 
 func makeFullArgs(table Table, chain Chain, args ...string) []string {
@@ -124,6 +124,42 @@ func TestFlushChain(t *testing.T) {
 	}
 }
 
+func TestListChains(t *testing.T) {
+	fcmd := exec.FakeCmd{
+		CombinedOutputScript: []exec.FakeCombinedOutputAction{
+			// Success.
+			func() ([]byte, error) {
+				return []byte("-P PREROUTING ACCEPT\n-N KUBE-SERVICES\n-N FOOBAR\n-A PREROUTING -j KUBE-SERVICES\n"), nil
+			},
+			// Failure.
+			func() ([]byte, error) { return nil, &exec.FakeExitError{1} },
+		},
+	}
+	fexec := exec.FakeExec{
+		CommandScript: []exec.FakeCommandAction{
+			func(cmd string, args ...string) exec.Cmd { return exec.InitFakeCmd(&fcmd, cmd, args...) },
+			func(cmd string, args ...string) exec.Cmd { return exec.InitFakeCmd(&fcmd, cmd, args...) },
+		},
+	}
+	runner := New(&fexec, ProtocolIpv4)
+	// Success.
+	chains, err := runner.ListChains(TableNAT)
+	if err != nil {
+		t.Errorf("expected success, got %v", err)
+	}
+	if len(chains) != 2 || chains[0] != "KUBE-SERVICES" || chains[1] != "FOOBAR" {
+		t.Errorf("expected the user-defined chains, got %v", chains)
+	}
+	if !util.NewStringSet(fcmd.CombinedOutputLog[0]...).HasAll("iptables", "-t", "nat", "-S") {
+		t.Errorf("wrong CombinedOutput() log, got %s", fcmd.CombinedOutputLog[0])
+	}
+	// Failure.
+	_, err = runner.ListChains(TableNAT)
+	if err == nil {
+		t.Errorf("expected failure")
+	}
+}
+
 func TestDeleteChain(t *testing.T) {
 	fcmd := exec.FakeCmd{
 		CombinedOutputScript: []exec.FakeCombinedOutputAction{
@@ -0,0 +1,804 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package protobuf encodes Go structs in the Protocol Buffers wire format
+// without generated code. Every struct is a message, and each of its exported
+// fields must carry a protobuf tag holding its field number, like
+// `protobuf:"3"`, unless the field is left out of JSON with `json:"-"`. The
+// encoding of each type is worked out once, the first time it is seen.
+//
+// Booleans and integers are varints, with negative integers in two's
+// complement like the int64 type of Protocol Buffers. Floats are fixed32 or
+// fixed64 values. Strings, byte slices and nested structs are length
+// delimited. Other slices are repeated fields, and maps are repeated entries
+// holding the key as field 1 and the value as field 2, sorted by key so that
+// equal maps encode to equal bytes. Types implementing Marshaler and
+// Unmarshaler, like times and quantities, are encoded as the bytes they
+// return. Other types that marshal themselves to JSON are encoded as bytes
+// holding their JSON, so that they keep the format they have in the API.
+//
+// Fields holding their zero value are left out, except behind a pointer, so
+// that a pointer to a zero value survives a round trip. Empty slices and maps
+// are told apart from nil ones when JSON writes them, that is when their json
+// tag has no omitempty option: their field numbers are listed in the reserved
+// field emptyFieldsNumber of the message. Unknown fields are skipped when
+// decoding.
+package protobuf
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Marshaler is implemented by types that encode themselves as a length
+// delimited field. Returning no bytes leaves the field out, unless the value
+// is behind a pointer.
+type Marshaler interface {
+	MarshalProtobuf() ([]byte, error)
+}
+
+// Unmarshaler is implemented by types that decode the bytes written by their
+// Marshaler.
+type Unmarshaler interface {
+	UnmarshalProtobuf([]byte) error
+}
+
+// Wire types of Protocol Buffers.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// emptyFieldsNumber is the largest field number of Protocol Buffers. It is
+// reserved in every message for the packed numbers of its empty slices and
+// maps.
+const emptyFieldsNumber = 1<<29 - 1
+
+var (
+	marshalerType       = reflect.TypeOf((*Marshaler)(nil)).Elem()
+	unmarshalerType     = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+	jsonMarshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// Marshal returns the wire encoding of the struct v, or of the struct v points to.
+func Marshal(v interface{}) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("can not marshal a nil %v", rv.Type())
+		}
+		rv = rv.Elem()
+	}
+	c, err := messageCoderFor(rv.Type())
+	if err != nil {
+		return nil, err
+	}
+	e := &encoder{}
+	if err := e.message(c.message, rv); err != nil {
+		return nil, err
+	}
+	return e.buf, nil
+}
+
+// Unmarshal decodes the wire encoding in data into the struct v points to.
+func Unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("can not unmarshal into %T: a non-nil pointer is required", v)
+	}
+	rv = rv.Elem()
+	c, err := messageCoderFor(rv.Type())
+	if err != nil {
+		return err
+	}
+	return decodeMessage(c.message, data, rv)
+}
+
+// coder encodes and decodes the values of one type.
+type coder struct {
+	// encode appends v as the field with the given number. Zero values are
+	// left out unless always is set.
+	encode func(e *encoder, number uint64, v reflect.Value, always bool) error
+	// decode decodes one occurrence of a field into v, which must be
+	// settable. Repeated fields and map entries are appended.
+	decode func(v reflect.Value, wireType int, x uint64, b []byte) error
+	// message is set for structs that are encoded as messages.
+	message *message
+	// collection is set for slices and maps that are encoded item by item.
+	collection bool
+}
+
+// field is an exported struct field that is encoded.
+type field struct {
+	index  int
+	number uint64
+	name   string
+	coder  *coder
+	// keepEmpty is set for slices and maps that JSON writes when they are
+	// empty, so that an empty one must not come back as nil.
+	keepEmpty bool
+}
+
+// message describes how a struct type is encoded.
+type message struct {
+	fields   []field
+	byNumber map[uint64]int
+}
+
+var coders = struct {
+	sync.RWMutex
+	m map[reflect.Type]*coder
+}{m: map[reflect.Type]*coder{}}
+
+// coderFor returns the coder of t, which is built the first time t is seen.
+func coderFor(t reflect.Type) (*coder, error) {
+	coders.RLock()
+	c, ok := coders.m[t]
+	coders.RUnlock()
+	if ok {
+		return c, nil
+	}
+
+	coders.Lock()
+	defer coders.Unlock()
+	// The coders of t and of the types it holds are only kept once they are
+	// all built, so that a type with an invalid field is not half known.
+	built := map[reflect.Type]*coder{}
+	c, err := buildCoder(t, built)
+	if err != nil {
+		return nil, err
+	}
+	for t, c := range built {
+		coders.m[t] = c
+	}
+	return c, nil
+}
+
+// messageCoderFor returns the coder of t, which must be encoded as a message.
+func messageCoderFor(t reflect.Type) (*coder, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("can not encode %v: only structs are messages", t)
+	}
+	c, err := coderFor(t)
+	if err != nil {
+		return nil, err
+	}
+	if c.message == nil {
+		return nil, fmt.Errorf("can not encode %v as a message: it encodes itself", t)
+	}
+	return c, nil
+}
+
+// buildCoder returns the coder of t, adding it and the coders it needs to
+// built unless they are already known. The caller must hold the coders lock.
+func buildCoder(t reflect.Type, built map[reflect.Type]*coder) (*coder, error) {
+	if c, ok := coders.m[t]; ok {
+		return c, nil
+	}
+	if c, ok := built[t]; ok {
+		return c, nil
+	}
+	// The coder is added before it is complete so that recursive types
+	// find it.
+	c := &coder{}
+	built[t] = c
+
+	switch {
+	case t.Kind() == reflect.Ptr:
+		elem, err := buildCoder(t.Elem(), built)
+		if err != nil {
+			return nil, err
+		}
+		c.encode = func(e *encoder, number uint64, v reflect.Value, always bool) error {
+			if v.IsNil() {
+				return nil
+			}
+			return elem.encode(e, number, v.Elem(), true)
+		}
+		c.decode = func(v reflect.Value, wireType int, x uint64, b []byte) error {
+			if v.IsNil() {
+				v.Set(reflect.New(t.Elem()))
+			}
+			return elem.decode(v.Elem(), wireType, x, b)
+		}
+	case reflect.PtrTo(t).Implements(marshalerType) && reflect.PtrTo(t).Implements(unmarshalerType):
+		c.encode = encodeMarshaler
+		c.decode = func(v reflect.Value, wireType int, x uint64, b []byte) error {
+			if err := expect(t, wireType, wireBytes); err != nil {
+				return err
+			}
+			return v.Addr().Interface().(Unmarshaler).UnmarshalProtobuf(b)
+		}
+	case t.Implements(jsonMarshalerType) && reflect.PtrTo(t).Implements(jsonUnmarshalerType):
+		c.encode = encodeJSON
+		c.decode = func(v reflect.Value, wireType int, x uint64, b []byte) error {
+			if err := expect(t, wireType, wireBytes); err != nil {
+				return err
+			}
+			return v.Addr().Interface().(json.Unmarshaler).UnmarshalJSON(b)
+		}
+	default:
+		if err := buildKindCoder(c, t, built); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// buildKindCoder fills c with the encoding of the kind of t.
+func buildKindCoder(c *coder, t reflect.Type, built map[reflect.Type]*coder) error {
+	switch t.Kind() {
+	case reflect.Bool:
+		c.encode = encodeBool
+		c.decode = func(v reflect.Value, wireType int, x uint64, b []byte) error {
+			if err := expect(t, wireType, wireVarint); err != nil {
+				return err
+			}
+			v.SetBool(x != 0)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		c.encode = encodeInt
+		c.decode = func(v reflect.Value, wireType int, x uint64, b []byte) error {
+			if err := expect(t, wireType, wireVarint); err != nil {
+				return err
+			}
+			v.SetInt(int64(x))
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		c.encode = encodeUint
+		c.decode = func(v reflect.Value, wireType int, x uint64, b []byte) error {
+			if err := expect(t, wireType, wireVarint); err != nil {
+				return err
+			}
+			v.SetUint(x)
+			return nil
+		}
+	case reflect.Float32:
+		c.encode = encodeFloat32
+		c.decode = func(v reflect.Value, wireType int, x uint64, b []byte) error {
+			if err := expect(t, wireType, wireFixed32); err != nil {
+				return err
+			}
+			v.SetFloat(float64(math.Float32frombits(uint32(x))))
+			return nil
+		}
+	case reflect.Float64:
+		c.encode = encodeFloat64
+		c.decode = func(v reflect.Value, wireType int, x uint64, b []byte) error {
+			if err := expect(t, wireType, wireFixed64); err != nil {
+				return err
+			}
+			v.SetFloat(math.Float64frombits(x))
+			return nil
+		}
+	case reflect.String:
+		c.encode = encodeString
+		c.decode = func(v reflect.Value, wireType int, x uint64, b []byte) error {
+			if err := expect(t, wireType, wireBytes); err != nil {
+				return err
+			}
+			v.SetString(string(b))
+			return nil
+		}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			c.encode = encodeBytes
+			c.decode = func(v reflect.Value, wireType int, x uint64, b []byte) error {
+				if err := expect(t, wireType, wireBytes); err != nil {
+					return err
+				}
+				v.SetBytes(append([]byte{}, b...))
+				return nil
+			}
+			return nil
+		}
+		c.collection = true
+		return buildSliceCoder(c, t, built)
+	case reflect.Map:
+		c.collection = true
+		return buildMapCoder(c, t, built)
+	case reflect.Struct:
+		return buildMessageCoder(c, t, built)
+	default:
+		return fmt.Errorf("can not encode %v", t)
+	}
+	return nil
+}
+
+func buildSliceCoder(c *coder, t reflect.Type, built map[reflect.Type]*coder) error {
+	elem, err := buildCoder(t.Elem(), built)
+	if err != nil {
+		return err
+	}
+	isPtr := t.Elem().Kind() == reflect.Ptr
+	c.encode = func(e *encoder, number uint64, v reflect.Value, always bool) error {
+		for i := 0; i < v.Len(); i++ {
+			item := v.Index(i)
+			if isPtr && item.IsNil() {
+				return fmt.Errorf("can not encode a nil item of %v", t)
+			}
+			if err := elem.encode(e, number, item, true); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	c.decode = func(v reflect.Value, wireType int, x uint64, b []byte) error {
+		n := v.Len()
+		if n == v.Cap() {
+			grown := reflect.MakeSlice(t, n, 2*n+1)
+			reflect.Copy(grown, v)
+			v.Set(grown)
+		}
+		v.SetLen(n + 1)
+		item := v.Index(n)
+		item.Set(reflect.Zero(t.Elem()))
+		return elem.decode(item, wireType, x, b)
+	}
+	return nil
+}
+
+func buildMapCoder(c *coder, t reflect.Type, built map[reflect.Type]*coder) error {
+	key, err := buildCoder(t.Key(), built)
+	if err != nil {
+		return err
+	}
+	value, err := buildCoder(t.Elem(), built)
+	if err != nil {
+		return err
+	}
+	isPtr := t.Elem().Kind() == reflect.Ptr
+	c.encode = func(e *encoder, number uint64, v reflect.Value, always bool) error {
+		keys := v.MapKeys()
+		sort.Sort(byKey(keys))
+		for _, k := range keys {
+			item := v.MapIndex(k)
+			if isPtr && item.IsNil() {
+				return fmt.Errorf("can not encode a nil value of %v", t)
+			}
+			err := e.nested(number, true, func() error {
+				if err := key.encode(e, 1, k, true); err != nil {
+					return err
+				}
+				return value.encode(e, 2, item, true)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	c.decode = func(v reflect.Value, wireType int, x uint64, b []byte) error {
+		if err := expect(t, wireType, wireBytes); err != nil {
+			return err
+		}
+		k := reflect.New(t.Key()).Elem()
+		item := reflect.New(t.Elem()).Elem()
+		err := parse(b, func(number uint64, wireType int, x uint64, b []byte) error {
+			switch number {
+			case 1:
+				return key.decode(k, wireType, x, b)
+			case 2:
+				return value.decode(item, wireType, x, b)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(t))
+		}
+		v.SetMapIndex(k, item)
+		return nil
+	}
+	return nil
+}
+
+func buildMessageCoder(c *coder, t reflect.Type, built map[reflect.Type]*coder) error {
+	m := &message{byNumber: map[uint64]int{}}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		jsonTag := f.Tag.Get("json")
+		tag := f.Tag.Get("protobuf")
+		if tag == "" {
+			if jsonTag == "-" {
+				continue
+			}
+			return fmt.Errorf("field %s of %v has no protobuf tag", f.Name, t)
+		}
+		number, err := strconv.ParseUint(tag, 10, 29)
+		if err != nil || number == 0 || number == emptyFieldsNumber {
+			return fmt.Errorf("field %s of %v has an invalid protobuf tag %q", f.Name, t, tag)
+		}
+		if _, ok := m.byNumber[number]; ok {
+			return fmt.Errorf("field %s of %v reuses field number %d", f.Name, t, number)
+		}
+		fc, err := buildCoder(f.Type, built)
+		if err != nil {
+			return err
+		}
+		keepEmpty := fc.collection && !strings.Contains(jsonTag, ",omitempty")
+		m.byNumber[number] = len(m.fields)
+		m.fields = append(m.fields, field{index: i, number: number, name: f.Name, coder: fc, keepEmpty: keepEmpty})
+	}
+
+	c.message = m
+	c.encode = func(e *encoder, number uint64, v reflect.Value, always bool) error {
+		return e.nested(number, always, func() error { return e.message(m, v) })
+	}
+	c.decode = func(v reflect.Value, wireType int, x uint64, b []byte) error {
+		if err := expect(t, wireType, wireBytes); err != nil {
+			return err
+		}
+		return decodeMessage(m, b, v)
+	}
+	return nil
+}
+
+// expect returns an error if a field of the wire type got can not be decoded
+// into t, which is encoded with the wire type want.
+func expect(t reflect.Type, got, want int) error {
+	if got != want {
+		return fmt.Errorf("wire type %d can not be decoded into %v", got, t)
+	}
+	return nil
+}
+
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) varint(x uint64) {
+	for x >= 0x80 {
+		e.buf = append(e.buf, byte(x)|0x80)
+		x >>= 7
+	}
+	e.buf = append(e.buf, byte(x))
+}
+
+func (e *encoder) key(number uint64, wireType int) {
+	e.varint(number<<3 | uint64(wireType))
+}
+
+func (e *encoder) bytes(number uint64, b []byte) {
+	e.key(number, wireBytes)
+	e.varint(uint64(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+func (e *encoder) string(number uint64, s string) {
+	e.key(number, wireBytes)
+	e.varint(uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+// nested encodes the field with the given number by calling fn to append its
+// content, and then moves the content to make room for its length. The field
+// is left out if fn appends nothing and always is not set.
+func (e *encoder) nested(number uint64, always bool, fn func() error) error {
+	start := len(e.buf)
+	e.key(number, wireBytes)
+	content := len(e.buf)
+	if err := fn(); err != nil {
+		return err
+	}
+	length := len(e.buf) - content
+	if length == 0 && !always {
+		e.buf = e.buf[:start]
+		return nil
+	}
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], uint64(length))
+	e.buf = append(e.buf, b[:n]...)
+	copy(e.buf[content+n:], e.buf[content:content+length])
+	copy(e.buf[content:], b[:n])
+	return nil
+}
+
+func (e *encoder) message(m *message, v reflect.Value) error {
+	var empty []uint64
+	for i := range m.fields {
+		f := &m.fields[i]
+		fv := v.Field(f.index)
+		if f.keepEmpty && fv.Len() == 0 {
+			if !fv.IsNil() {
+				empty = append(empty, f.number)
+			}
+			continue
+		}
+		if err := f.coder.encode(e, f.number, fv, false); err != nil {
+			return err
+		}
+	}
+	if len(empty) == 0 {
+		return nil
+	}
+	return e.nested(emptyFieldsNumber, true, func() error {
+		for _, number := range empty {
+			e.varint(number)
+		}
+		return nil
+	})
+}
+
+func encodeBool(e *encoder, number uint64, v reflect.Value, always bool) error {
+	if !always && !v.Bool() {
+		return nil
+	}
+	e.key(number, wireVarint)
+	if v.Bool() {
+		e.varint(1)
+	} else {
+		e.varint(0)
+	}
+	return nil
+}
+
+func encodeInt(e *encoder, number uint64, v reflect.Value, always bool) error {
+	if !always && v.Int() == 0 {
+		return nil
+	}
+	e.key(number, wireVarint)
+	e.varint(uint64(v.Int()))
+	return nil
+}
+
+func encodeUint(e *encoder, number uint64, v reflect.Value, always bool) error {
+	if !always && v.Uint() == 0 {
+		return nil
+	}
+	e.key(number, wireVarint)
+	e.varint(v.Uint())
+	return nil
+}
+
+func encodeFloat32(e *encoder, number uint64, v reflect.Value, always bool) error {
+	if !always && v.Float() == 0 {
+		return nil
+	}
+	e.key(number, wireFixed32)
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], math.Float32bits(float32(v.Float())))
+	e.buf = append(e.buf, b[:]...)
+	return nil
+}
+
+func encodeFloat64(e *encoder, number uint64, v reflect.Value, always bool) error {
+	if !always && v.Float() == 0 {
+		return nil
+	}
+	e.key(number, wireFixed64)
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], math.Float64bits(v.Float()))
+	e.buf = append(e.buf, b[:]...)
+	return nil
+}
+
+func encodeString(e *encoder, number uint64, v reflect.Value, always bool) error {
+	if !always && v.Len() == 0 {
+		return nil
+	}
+	e.string(number, v.String())
+	return nil
+}
+
+func encodeBytes(e *encoder, number uint64, v reflect.Value, always bool) error {
+	if !always && v.Len() == 0 {
+		return nil
+	}
+	e.bytes(number, v.Bytes())
+	return nil
+}
+
+// addressed returns a pointer to v, which has the methods of both v and its
+// pointer and is stored in an interface without a copy when v is addressable.
+func addressed(v reflect.Value) interface{} {
+	if v.CanAddr() {
+		return v.Addr().Interface()
+	}
+	p := reflect.New(v.Type())
+	p.Elem().Set(v)
+	return p.Interface()
+}
+
+func encodeMarshaler(e *encoder, number uint64, v reflect.Value, always bool) error {
+	data, err := addressed(v).(Marshaler).MarshalProtobuf()
+	if err != nil {
+		return err
+	}
+	if !always && len(data) == 0 {
+		return nil
+	}
+	e.bytes(number, data)
+	return nil
+}
+
+func encodeJSON(e *encoder, number uint64, v reflect.Value, always bool) error {
+	if !always && isZero(v) {
+		return nil
+	}
+	data, err := addressed(v).(json.Marshaler).MarshalJSON()
+	if err != nil {
+		return err
+	}
+	e.bytes(number, data)
+	return nil
+}
+
+// isZero returns true if v holds the zero value of its type.
+func isZero(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Complex64, reflect.Complex128:
+		return v.Complex() == 0
+	case reflect.String:
+		return v.Len() == 0
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if !isZero(v.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !isZero(v.Field(i)) {
+				return false
+			}
+		}
+		return true
+	default:
+		return v.IsNil()
+	}
+}
+
+// byKey sorts map keys of a basic kind.
+type byKey []reflect.Value
+
+func (k byKey) Len() int      { return len(k) }
+func (k byKey) Swap(i, j int) { k[i], k[j] = k[j], k[i] }
+func (k byKey) Less(i, j int) bool {
+	switch k[i].Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return k[i].Int() < k[j].Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return k[i].Uint() < k[j].Uint()
+	case reflect.Float32, reflect.Float64:
+		return k[i].Float() < k[j].Float()
+	case reflect.Bool:
+		return !k[i].Bool() && k[j].Bool()
+	default:
+		return k[i].String() < k[j].String()
+	}
+}
+
+// parse calls fn with every field encoded in data. Varint and fixed values
+// are passed in x, length delimited ones in b.
+func parse(data []byte, fn func(number uint64, wireType int, x uint64, b []byte) error) error {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return fmt.Errorf("invalid field key")
+		}
+		data = data[n:]
+		number, wireType := key>>3, int(key&7)
+
+		var x uint64
+		var b []byte
+		switch wireType {
+		case wireVarint:
+			x, n = binary.Uvarint(data)
+			if n <= 0 {
+				return fmt.Errorf("invalid varint in field %d", number)
+			}
+			data = data[n:]
+		case wireFixed64:
+			if len(data) < 8 {
+				return fmt.Errorf("truncated fixed64 in field %d", number)
+			}
+			x = binary.LittleEndian.Uint64(data)
+			data = data[8:]
+		case wireFixed32:
+			if len(data) < 4 {
+				return fmt.Errorf("truncated fixed32 in field %d", number)
+			}
+			x = uint64(binary.LittleEndian.Uint32(data))
+			data = data[4:]
+		case wireBytes:
+			length, n := binary.Uvarint(data)
+			if n <= 0 || length > uint64(len(data)-n) {
+				return fmt.Errorf("truncated bytes in field %d", number)
+			}
+			b = data[n : n+int(length)]
+			data = data[n+int(length):]
+		default:
+			return fmt.Errorf("unsupported wire type %d in field %d", wireType, number)
+		}
+		if err := fn(number, wireType, x, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decodeMessage(m *message, data []byte, v reflect.Value) error {
+	return parse(data, func(number uint64, wireType int, x uint64, b []byte) error {
+		if number == emptyFieldsNumber {
+			if err := expect(v.Type(), wireType, wireBytes); err != nil {
+				return err
+			}
+			return decodeEmptyFields(m, b, v)
+		}
+		i, ok := m.byNumber[number]
+		if !ok {
+			return nil
+		}
+		f := &m.fields[i]
+		if err := f.coder.decode(v.Field(f.index), wireType, x, b); err != nil {
+			return fmt.Errorf("field %s of %v: %v", f.name, v.Type(), err)
+		}
+		return nil
+	})
+}
+
+// decodeEmptyFields makes the nil slices and maps of v listed in b, which
+// holds the packed numbers of the empty fields of m, empty.
+func decodeEmptyFields(m *message, b []byte, v reflect.Value) error {
+	for len(b) > 0 {
+		number, n := binary.Uvarint(b)
+		if n <= 0 {
+			return fmt.Errorf("invalid empty field numbers in %v", v.Type())
+		}
+		b = b[n:]
+		i, ok := m.byNumber[number]
+		if !ok || !m.fields[i].keepEmpty {
+			continue
+		}
+		fv := v.Field(m.fields[i].index)
+		if !fv.IsNil() {
+			continue
+		}
+		if fv.Kind() == reflect.Map {
+			fv.Set(reflect.MakeMap(fv.Type()))
+		} else {
+			fv.Set(reflect.MakeSlice(fv.Type(), 0, 0))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protobuf
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+)
+
+type inner struct {
+	Name  string `protobuf:"1"`
+	Count int    `protobuf:"2"`
+}
+
+type outer struct {
+	Bool     bool              `protobuf:"1"`
+	Int      int64             `protobuf:"2"`
+	Negative int32             `protobuf:"3"`
+	Uint     uint64            `protobuf:"4"`
+	Float    float64           `protobuf:"5"`
+	Single   float32           `protobuf:"6"`
+	String   string            `protobuf:"7"`
+	Bytes    []byte            `protobuf:"8"`
+	Strings  []string          `protobuf:"9"`
+	Inner    inner             `protobuf:"10"`
+	Inners   []inner           `protobuf:"11"`
+	Pointer  *inner            `protobuf:"12"`
+	Zero     *int              `protobuf:"13"`
+	Labels   map[string]string `protobuf:"14"`
+	Counts   map[string]inner  `protobuf:"15"`
+	Time     util.Time         `protobuf:"16"`
+	Port     util.IntOrString  `protobuf:"17"`
+	Ignored  string            `json:"-"`
+	internal string
+}
+
+func TestRoundTrip(t *testing.T) {
+	zero := 0
+	in := outer{
+		Bool:     true,
+		Int:      1 << 40,
+		Negative: -5,
+		Uint:     7,
+		Float:    1.5,
+		Single:   -2.25,
+		String:   "hello",
+		Bytes:    []byte{0, 1, 2},
+		Strings:  []string{"a", "", "c"},
+		Inner:    inner{Name: "x", Count: 3},
+		Inners:   []inner{{Name: "y"}, {}, {Count: -1}},
+		Pointer:  &inner{},
+		Zero:     &zero,
+		Labels:   map[string]string{"b": "2", "a": "1", "c": ""},
+		Counts:   map[string]inner{"one": {Count: 1}},
+		Time:     util.Date(2015, time.March, 1, 10, 20, 30, 0, time.Local),
+		Port:     util.NewIntOrStringFromString("http"),
+	}
+	data, err := Marshal(&in)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var out outer
+	if err := Unmarshal(data, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("expected\n%#v\ngot\n%#v", in, out)
+	}
+}
+
+type collections struct {
+	Items   []string          `json:"items" protobuf:"1"`
+	Labels  map[string]string `json:"labels" protobuf:"2"`
+	Omitted []string          `json:"omitted,omitempty" protobuf:"3"`
+	Nil     []string          `json:"nil" protobuf:"4"`
+	Inner   *collections      `json:"inner,omitempty" protobuf:"5"`
+}
+
+func TestRoundTripKeepsEmptyCollections(t *testing.T) {
+	in := collections{
+		Items:   []string{},
+		Labels:  map[string]string{},
+		Omitted: []string{},
+		Inner:   &collections{Items: []string{}},
+	}
+	data, err := Marshal(&in)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var out collections
+	if err := Unmarshal(data, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := collections{
+		Items:  []string{},
+		Labels: map[string]string{},
+		Inner:  &collections{Items: []string{}},
+	}
+	if !reflect.DeepEqual(expected, out) {
+		t.Errorf("expected\n%#v\ngot\n%#v", expected, out)
+	}
+}
+
+// seconds encodes itself as the varint of its value.
+type seconds struct {
+	value uint64
+}
+
+func (s seconds) MarshalProtobuf() ([]byte, error) {
+	if s.value == 0 {
+		return nil, nil
+	}
+	return []byte{byte(s.value)}, nil
+}
+
+func (s *seconds) UnmarshalProtobuf(b []byte) error {
+	if len(b) != 1 {
+		return fmt.Errorf("unexpected bytes %v", b)
+	}
+	s.value = uint64(b[0])
+	return nil
+}
+
+func TestMarshaler(t *testing.T) {
+	type custom struct {
+		Value   seconds            `protobuf:"1"`
+		Zero    seconds            `protobuf:"2"`
+		Pointer *seconds           `protobuf:"3"`
+		Values  map[string]seconds `protobuf:"4"`
+	}
+	in := custom{
+		Value:   seconds{5},
+		Pointer: &seconds{},
+		Values:  map[string]seconds{"a": {7}},
+	}
+	data, err := Marshal(&in)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []byte{
+		1<<3 | wireBytes, 1, 5,
+		3<<3 | wireBytes, 0,
+		4<<3 | wireBytes, 6, 1<<3 | wireBytes, 1, 'a', 2<<3 | wireBytes, 1, 7,
+	}
+	if !bytes.Equal(data, expected) {
+		t.Errorf("expected %v, got %v", expected, data)
+	}
+	var out custom
+	if err := Unmarshal(data, &out); err == nil {
+		t.Errorf("expected an error for the empty pointer")
+	}
+}
+
+func TestMarshalOmitsZeroValues(t *testing.T) {
+	data, err := Marshal(outer{Ignored: "a", internal: "b"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(data) != 0 {
+		t.Errorf("expected no bytes, got %v", data)
+	}
+}
+
+func TestMarshalWireFormat(t *testing.T) {
+	data, err := Marshal(outer{
+		Negative: -1,
+		String:   "ab",
+		Inner:    inner{Count: 150},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []byte{
+		3<<3 | wireVarint, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01,
+		7<<3 | wireBytes, 2, 'a', 'b',
+		10<<3 | wireBytes, 3, 2<<3 | wireVarint, 0x96, 0x01,
+	}
+	if !bytes.Equal(data, expected) {
+		t.Errorf("expected %v, got %v", expected, data)
+	}
+}
+
+func TestMarshalSortsMapKeys(t *testing.T) {
+	labels := map[string]string{}
+	for _, k := range []string{"d", "b", "a", "e", "c"} {
+		labels[k] = k
+	}
+	first, err := Marshal(outer{Labels: labels})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		data, err := Marshal(outer{Labels: labels})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(first, data) {
+			t.Fatalf("expected equal maps to encode the same, got %v and %v", first, data)
+		}
+	}
+}
+
+func TestUnmarshalSkipsUnknownFields(t *testing.T) {
+	data := []byte{
+		15<<3 | wireVarint, 1,
+		14<<3 | wireBytes, 1, 'z',
+		13<<3 | wireFixed64, 0, 0, 0, 0, 0, 0, 0, 0,
+		12<<3 | wireFixed32, 0, 0, 0, 0,
+		1<<3 | wireBytes, 1, 'x',
+	}
+	var out inner
+	if err := Unmarshal(data, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Name != "x" {
+		t.Errorf("unexpected result: %#v", out)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	type untagged struct {
+		Name string
+	}
+	type duplicate struct {
+		A string `protobuf:"1"`
+		B string `protobuf:"1"`
+	}
+	if _, err := Marshal(untagged{}); err == nil {
+		t.Errorf("expected an error for an untagged field")
+	}
+	if _, err := Marshal(duplicate{}); err == nil {
+		t.Errorf("expected an error for a reused field number")
+	}
+	type reserved struct {
+		A string `protobuf:"536870911"`
+	}
+	if _, err := Marshal(reserved{}); err == nil {
+		t.Errorf("expected an error for the reserved field number")
+	}
+	if _, err := Marshal("a"); err == nil {
+		t.Errorf("expected an error for a non struct")
+	}
+	if err := Unmarshal(nil, inner{}); err == nil {
+		t.Errorf("expected an error for a non pointer")
+	}
+	var out inner
+	for _, data := range [][]byte{
+		{1<<3 | wireBytes, 5, 'a'},
+		{1<<3 | wireVarint, 1},
+		{2<<3 | wireVarint},
+		{2<<3 | 3},
+	} {
+		if err := Unmarshal(data, &out); err == nil {
+			t.Errorf("expected an error for %v", data)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package strategicpatch merges JSON patches into JSON documents using the
+// patch strategies declared in the struct tags of the type the documents
+// decode into.
+//
+// A patch is merged like an RFC 7386 merge patch, except for lists tagged with
+// `patchStrategy:"merge"`. Their items are matched with the items of the
+// original list by the field named in `patchMergeKey`; matching items are
+// merged recursively and the other ones are appended. A "$patch" key changes
+// how the object holding it is applied. In an object, "$patch": "replace"
+// replaces the original object instead of merging into it and "$patch":
+// "delete" clears it. In a list item, "$patch": "delete" removes the original
+// item with the same merge key, and an item holding only "$patch": "replace"
+// replaces the whole original list with the other items of the patch.
+package strategicpatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	directiveMarker  = "$patch"
+	deleteDirective  = "delete"
+	replaceDirective = "replace"
+
+	mergeStrategy = "merge"
+)
+
+// StrategicMergePatchData applies the JSON patch to the JSON original document
+// and returns the patched document. dataStruct is an instance of the type the
+// documents decode into (e.g. v1beta3.Pod), whose struct tags select how lists
+// are merged.
+func StrategicMergePatchData(original, patch []byte, dataStruct interface{}) ([]byte, error) {
+	t, err := structType(dataStruct)
+	if err != nil {
+		return nil, err
+	}
+	originalMap := map[string]interface{}{}
+	if err := json.Unmarshal(original, &originalMap); err != nil {
+		return nil, err
+	}
+	patchMap := map[string]interface{}{}
+	if err := json.Unmarshal(patch, &patchMap); err != nil {
+		return nil, err
+	}
+	result, err := mergeMap(originalMap, patchMap, t)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(result)
+}
+
+func structType(dataStruct interface{}) (reflect.Type, error) {
+	t := indirect(reflect.TypeOf(dataStruct))
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("strategic merge patch needs a struct to read patch strategies from, got %T", dataStruct)
+	}
+	return t, nil
+}
+
+// mergeMap merges patch into original, which may be nil, and returns the
+// result. t is the type the objects decode into, or nil if it is unknown.
+func mergeMap(original, patch map[string]interface{}, t reflect.Type) (map[string]interface{}, error) {
+	if directive, ok := patch[directiveMarker]; ok {
+		switch directive {
+		case replaceDirective:
+			original = nil
+		case deleteDirective:
+			return map[string]interface{}{}, nil
+		default:
+			return nil, fmt.Errorf("unknown patch directive %v", directive)
+		}
+	}
+	if original == nil {
+		original = map[string]interface{}{}
+	}
+	for key, patchValue := range patch {
+		if key == directiveMarker {
+			continue
+		}
+		if patchValue == nil {
+			delete(original, key)
+			continue
+		}
+		fieldType, patchStrategy, mergeKey := lookupField(t, key)
+		merged, err := mergeValue(original[key], patchValue, fieldType, patchStrategy, mergeKey)
+		if err != nil {
+			return nil, err
+		}
+		original[key] = merged
+	}
+	return original, nil
+}
+
+func mergeValue(original, patch interface{}, t reflect.Type, patchStrategy, mergeKey string) (interface{}, error) {
+	switch typedPatch := patch.(type) {
+	case map[string]interface{}:
+		typedOriginal, _ := original.(map[string]interface{})
+		return mergeMap(typedOriginal, typedPatch, t)
+	case []interface{}:
+		if patchStrategy != mergeStrategy {
+			return typedPatch, nil
+		}
+		var elemType reflect.Type
+		if t = indirect(t); t != nil && t.Kind() == reflect.Slice {
+			elemType = t.Elem()
+		}
+		typedOriginal, _ := original.([]interface{})
+		return mergeList(typedOriginal, typedPatch, elemType, mergeKey)
+	}
+	return patch, nil
+}
+
+// mergeList merges the items of patch into original by the value of their
+// mergeKey field. Lists of values other than objects are merged as sets.
+func mergeList(original, patch []interface{}, t reflect.Type, mergeKey string) ([]interface{}, error) {
+	merged := make([]interface{}, 0, len(original)+len(patch))
+	items := make([]interface{}, 0, len(patch))
+	replace := false
+	for _, item := range patch {
+		if typedItem, ok := item.(map[string]interface{}); ok && len(typedItem) == 1 {
+			if directive, ok := typedItem[directiveMarker]; ok && directive == replaceDirective {
+				replace = true
+				continue
+			}
+		}
+		items = append(items, item)
+	}
+	if !replace {
+		merged = append(merged, original...)
+	}
+
+	for _, item := range items {
+		patchItem, ok := item.(map[string]interface{})
+		if !ok {
+			if findItem(merged, item) < 0 {
+				merged = append(merged, item)
+			}
+			continue
+		}
+		if len(mergeKey) == 0 {
+			return nil, fmt.Errorf("list items of %v can not be merged without a patch merge key", t)
+		}
+		keyValue, ok := patchItem[mergeKey]
+		if !ok {
+			return nil, fmt.Errorf("list item %v does not have the merge key %q", patchItem, mergeKey)
+		}
+		i := findItemByKey(merged, mergeKey, keyValue)
+		if patchItem[directiveMarker] == deleteDirective {
+			if i >= 0 {
+				merged = append(merged[:i], merged[i+1:]...)
+			}
+			continue
+		}
+		var originalItem map[string]interface{}
+		if i >= 0 {
+			originalItem, _ = merged[i].(map[string]interface{})
+		}
+		mergedItem, err := mergeMap(originalItem, patchItem, t)
+		if err != nil {
+			return nil, err
+		}
+		if i >= 0 {
+			merged[i] = mergedItem
+		} else {
+			merged = append(merged, mergedItem)
+		}
+	}
+	return merged, nil
+}
+
+func findItem(list []interface{}, value interface{}) int {
+	for i, item := range list {
+		if reflect.DeepEqual(item, value) {
+			return i
+		}
+	}
+	return -1
+}
+
+func findItemByKey(list []interface{}, key string, value interface{}) int {
+	for i, item := range list {
+		if typedItem, ok := item.(map[string]interface{}); ok && reflect.DeepEqual(typedItem[key], value) {
+			return i
+		}
+	}
+	return -1
+}
+
+// lookupField returns the type and the patch tags of the field serialized as
+// name in t, following inlined structs. The type is nil if it is unknown.
+func lookupField(t reflect.Type, name string) (reflect.Type, string, string) {
+	t = indirect(t)
+	if t == nil {
+		return nil, "", ""
+	}
+	switch t.Kind() {
+	case reflect.Map:
+		return t.Elem(), "", ""
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			jsonName := strings.Split(field.Tag.Get("json"), ",")[0]
+			if jsonName == "-" {
+				continue
+			}
+			if len(jsonName) == 0 && field.Anonymous {
+				if fieldType, patchStrategy, mergeKey := lookupField(field.Type, name); fieldType != nil {
+					return fieldType, patchStrategy, mergeKey
+				}
+				continue
+			}
+			if len(jsonName) == 0 {
+				jsonName = field.Name
+			}
+			if jsonName == name {
+				return field.Type, field.Tag.Get("patchStrategy"), field.Tag.Get("patchMergeKey")
+			}
+		}
+	}
+	return nil, "", ""
+}
+
+func indirect(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package strategicpatch
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type testObjectMeta struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+type testPort struct {
+	ContainerPort int    `json:"containerPort"`
+	Protocol      string `json:"protocol,omitempty"`
+}
+
+type testContainer struct {
+	Name  string     `json:"name"`
+	Image string     `json:"image,omitempty"`
+	Ports []testPort `json:"ports,omitempty" patchStrategy:"merge" patchMergeKey:"containerPort"`
+	Args  []string   `json:"args,omitempty"`
+}
+
+type testSpec struct {
+	Containers []testContainer `json:"containers" patchStrategy:"merge" patchMergeKey:"name"`
+	DNS        []string        `json:"dns,omitempty" patchStrategy:"merge"`
+}
+
+type testObject struct {
+	testObjectMeta `json:",inline"`
+	Spec           *testSpec `json:"spec,omitempty"`
+}
+
+func TestStrategicMergePatchData(t *testing.T) {
+	testCases := []struct {
+		name     string
+		original string
+		patch    string
+		expected string
+	}{
+		{
+			name:     "merge maps",
+			original: `{"name": "foo", "labels": {"a": "1", "b": "2"}}`,
+			patch:    `{"labels": {"b": "3", "c": "4"}}`,
+			expected: `{"name": "foo", "labels": {"a": "1", "b": "3", "c": "4"}}`,
+		},
+		{
+			name:     "delete with null",
+			original: `{"name": "foo", "labels": {"a": "1", "b": "2"}}`,
+			patch:    `{"labels": {"a": null}}`,
+			expected: `{"name": "foo", "labels": {"b": "2"}}`,
+		},
+		{
+			name:     "merge list item by key",
+			original: `{"spec": {"containers": [{"name": "a", "image": "a:1"}, {"name": "b", "image": "b:1"}]}}`,
+			patch:    `{"spec": {"containers": [{"name": "b", "image": "b:2"}]}}`,
+			expected: `{"spec": {"containers": [{"name": "a", "image": "a:1"}, {"name": "b", "image": "b:2"}]}}`,
+		},
+		{
+			name:     "append list item",
+			original: `{"spec": {"containers": [{"name": "a", "image": "a:1"}]}}`,
+			patch:    `{"spec": {"containers": [{"name": "b", "image": "b:1"}]}}`,
+			expected: `{"spec": {"containers": [{"name": "a", "image": "a:1"}, {"name": "b", "image": "b:1"}]}}`,
+		},
+		{
+			name:     "merge nested list by key",
+			original: `{"spec": {"containers": [{"name": "a", "ports": [{"containerPort": 80}, {"containerPort": 53, "protocol": "TCP"}]}]}}`,
+			patch:    `{"spec": {"containers": [{"name": "a", "ports": [{"containerPort": 53, "protocol": "UDP"}]}]}}`,
+			expected: `{"spec": {"containers": [{"name": "a", "ports": [{"containerPort": 80}, {"containerPort": 53, "protocol": "UDP"}]}]}}`,
+		},
+		{
+			name:     "replace untagged list",
+			original: `{"spec": {"containers": [{"name": "a", "args": ["x", "y"]}]}}`,
+			patch:    `{"spec": {"containers": [{"name": "a", "args": ["z"]}]}}`,
+			expected: `{"spec": {"containers": [{"name": "a", "args": ["z"]}]}}`,
+		},
+		{
+			name:     "merge list of values as a set",
+			original: `{"spec": {"dns": ["a", "b"]}}`,
+			patch:    `{"spec": {"dns": ["b", "c"]}}`,
+			expected: `{"spec": {"dns": ["a", "b", "c"]}}`,
+		},
+		{
+			name:     "delete list item",
+			original: `{"spec": {"containers": [{"name": "a"}, {"name": "b"}]}}`,
+			patch:    `{"spec": {"containers": [{"name": "a", "$patch": "delete"}]}}`,
+			expected: `{"spec": {"containers": [{"name": "b"}]}}`,
+		},
+		{
+			name:     "replace list",
+			original: `{"spec": {"containers": [{"name": "a"}, {"name": "b"}]}}`,
+			patch:    `{"spec": {"containers": [{"$patch": "replace"}, {"name": "c"}]}}`,
+			expected: `{"spec": {"containers": [{"name": "c"}]}}`,
+		},
+		{
+			name:     "replace list item",
+			original: `{"spec": {"containers": [{"name": "a", "image": "a:1", "args": ["x"]}]}}`,
+			patch:    `{"spec": {"containers": [{"name": "a", "image": "a:2", "$patch": "replace"}]}}`,
+			expected: `{"spec": {"containers": [{"name": "a", "image": "a:2"}]}}`,
+		},
+		{
+			name:     "replace map",
+			original: `{"name": "foo", "labels": {"a": "1", "b": "2"}}`,
+			patch:    `{"labels": {"c": "3", "$patch": "replace"}}`,
+			expected: `{"name": "foo", "labels": {"c": "3"}}`,
+		},
+		{
+			name:     "delete map",
+			original: `{"name": "foo", "labels": {"a": "1", "b": "2"}}`,
+			patch:    `{"labels": {"$patch": "delete"}}`,
+			expected: `{"name": "foo", "labels": {}}`,
+		},
+		{
+			name:     "strip directives from new fields",
+			original: `{"name": "foo"}`,
+			patch:    `{"spec": {"containers": [{"name": "a", "$patch": "replace"}, {"name": "b", "$patch": "delete"}]}}`,
+			expected: `{"name": "foo", "spec": {"containers": [{"name": "a"}]}}`,
+		},
+	}
+
+	for _, test := range testCases {
+		result, err := StrategicMergePatchData([]byte(test.original), []byte(test.patch), testObject{})
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		var actual, expected interface{}
+		if err := json.Unmarshal(result, &actual); err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if err := json.Unmarshal([]byte(test.expected), &expected); err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%s: expected %s, got %s", test.name, test.expected, string(result))
+		}
+	}
+}
+
+func TestStrategicMergePatchDataErrors(t *testing.T) {
+	testCases := []struct {
+		name       string
+		original   string
+		patch      string
+		dataStruct interface{}
+	}{
+		{
+			name:       "invalid patch",
+			original:   `{"name": "foo"}`,
+			patch:      `{"name":`,
+			dataStruct: testObject{},
+		},
+		{
+			name:       "unknown directive",
+			original:   `{"name": "foo"}`,
+			patch:      `{"labels": {"$patch": "merge"}}`,
+			dataStruct: testObject{},
+		},
+		{
+			name:       "missing merge key",
+			original:   `{"spec": {"containers": [{"name": "a"}]}}`,
+			patch:      `{"spec": {"containers": [{"image": "a:2"}]}}`,
+			dataStruct: testObject{},
+		},
+		{
+			name:       "not a struct",
+			original:   `{"name": "foo"}`,
+			patch:      `{"name": "bar"}`,
+			dataStruct: "foo",
+		},
+	}
+
+	for _, test := range testCases {
+		if _, err := StrategicMergePatchData([]byte(test.original), []byte(test.patch), test.dataStruct); err == nil {
+			t.Errorf("%s: expected an error", test.name)
+		}
+	}
+}
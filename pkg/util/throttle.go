@@ -102,3 +102,17 @@ func (t *tickRateLimiter) increment() {
 	default:
 	}
 }
+
+type fakeRateLimiter struct{}
+
+// NewFakeRateLimiter returns a RateLimiter that accepts everything, to be used
+// where rate limiting is disabled.
+func NewFakeRateLimiter() RateLimiter {
+	return &fakeRateLimiter{}
+}
+
+func (f *fakeRateLimiter) CanAccept() bool {
+	return true
+}
+
+func (f *fakeRateLimiter) Stop() {}
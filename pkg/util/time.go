@@ -17,7 +17,9 @@ limitations under the License.
 package util
 
 import (
+	"encoding/binary"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/gofuzz"
@@ -100,6 +102,31 @@ func (t Time) MarshalJSON() ([]byte, error) {
 	return json.Marshal(t.UTC().Format(time.RFC3339))
 }
 
+// MarshalProtobuf implements the protobuf.Marshaler interface. Like the JSON
+// of the time, it keeps the seconds since the Unix epoch, which are written as
+// a varint. A zero time is written as nothing.
+func (t Time) MarshalProtobuf() ([]byte, error) {
+	if t.IsZero() {
+		return nil, nil
+	}
+	b := make([]byte, binary.MaxVarintLen64)
+	return b[:binary.PutUvarint(b, uint64(t.Unix()))], nil
+}
+
+// UnmarshalProtobuf implements the protobuf.Unmarshaler interface.
+func (t *Time) UnmarshalProtobuf(b []byte) error {
+	if len(b) == 0 {
+		t.Time = time.Time{}
+		return nil
+	}
+	sec, n := binary.Uvarint(b)
+	if n != len(b) {
+		return fmt.Errorf("invalid time %v", b)
+	}
+	t.Time = time.Unix(int64(sec), 0)
+	return nil
+}
+
 // Fuzz satisfies fuzz.Interface.
 func (t *Time) Fuzz(c fuzz.Continue) {
 	if t == nil {
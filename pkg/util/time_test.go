@@ -145,3 +145,28 @@ func TestTimeMarshalJSONUnmarshalYAML(t *testing.T) {
 		}
 	}
 }
+
+func TestTimeProtobuf(t *testing.T) {
+	cases := []struct {
+		input  Time
+		result Time
+	}{
+		{Time{}, Time{}},
+		{Date(1998, time.May, 5, 5, 5, 5, 50, time.UTC), Time{Date(1998, time.May, 5, 5, 5, 5, 0, time.UTC).Local()}},
+		{Date(1969, time.May, 5, 5, 5, 5, 0, time.UTC), Time{Date(1969, time.May, 5, 5, 5, 5, 0, time.UTC).Local()}},
+	}
+
+	for _, c := range cases {
+		b, err := c.input.MarshalProtobuf()
+		if err != nil {
+			t.Errorf("Failed to marshal input: '%v': %v", c.input, err)
+		}
+		var result Time
+		if err := result.UnmarshalProtobuf(b); err != nil {
+			t.Errorf("Failed to unmarshal '%v': %v", b, err)
+		}
+		if result != c.result {
+			t.Errorf("Failed to marshal input '%v': expected %+v, got %+v", c.input, c.result, result)
+		}
+	}
+}
@@ -92,6 +92,9 @@ func Forever(f func(), period time.Duration) {
 	Until(f, period, nil)
 }
 
+// NeverStop may be passed to Until to make it never stop.
+var NeverStop <-chan struct{} = make(chan struct{})
+
 // Until loops until stop channel is closed, running f every period.
 // Catches any panics, and keeps going. f may not be invoked if
 // stop channel is already closed.
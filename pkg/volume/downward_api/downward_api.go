@@ -0,0 +1,196 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package downward_api
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/types"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/volume"
+	"github.com/golang/glog"
+)
+
+// ProbeVolumePlugin is the entry point for plugin detection in a package.
+func ProbeVolumePlugins() []volume.VolumePlugin {
+	return []volume.VolumePlugin{&downwardAPIPlugin{}}
+}
+
+const (
+	downwardAPIPluginName = "kubernetes.io/downward-api"
+)
+
+// downwardAPIPlugin implements the VolumePlugin interface.
+type downwardAPIPlugin struct {
+	host volume.VolumeHost
+}
+
+func (plugin *downwardAPIPlugin) Init(host volume.VolumeHost) {
+	plugin.host = host
+}
+
+func (plugin *downwardAPIPlugin) Name() string {
+	return downwardAPIPluginName
+}
+
+func (plugin *downwardAPIPlugin) CanSupport(spec *api.Volume) bool {
+	return spec.DownwardAPI != nil
+}
+
+func (plugin *downwardAPIPlugin) NewBuilder(spec *api.Volume, pod *api.Pod) (volume.Builder, error) {
+	return &downwardAPIVolume{spec.Name, *pod, plugin, spec.DownwardAPI.Items}, nil
+}
+
+func (plugin *downwardAPIPlugin) NewCleaner(volName string, podUID types.UID) (volume.Cleaner, error) {
+	return &downwardAPIVolume{volName, api.Pod{ObjectMeta: api.ObjectMeta{UID: podUID}}, plugin, nil}, nil
+}
+
+// downwardAPIVolume writes the selected fields of its pod into files on the
+// host. The files are rewritten whenever the volume is set up again with a
+// pod whose fields have changed, so that label and annotation updates reach
+// running containers.
+type downwardAPIVolume struct {
+	volName string
+	pod     api.Pod
+	plugin  *downwardAPIPlugin
+	items   []api.DownwardAPIVolumeFile
+}
+
+func (dv *downwardAPIVolume) SetUp() error {
+	return dv.SetUpAt(dv.GetPath())
+}
+
+// This is the spec for the volume that this plugin wraps.
+var wrappedVolumeSpec = &api.Volume{
+	Name:         "not-used",
+	VolumeSource: api.VolumeSource{EmptyDir: &api.EmptyDirVolumeSource{Medium: api.StorageTypeMemory}},
+}
+
+func (dv *downwardAPIVolume) SetUpAt(dir string) error {
+	glog.V(3).Infof("Setting up volume %v for pod %v at %v", dv.volName, dv.pod.UID, dir)
+
+	// Wrap EmptyDir, let it do the setup.
+	wrapped, err := dv.plugin.host.NewWrapperBuilder(wrappedVolumeSpec, &dv.pod)
+	if err != nil {
+		return err
+	}
+	if err := wrapped.SetUpAt(dir); err != nil {
+		return err
+	}
+
+	for _, item := range dv.items {
+		data, err := fieldData(&dv.pod, item.FieldRef.FieldPath)
+		if err != nil {
+			return err
+		}
+		hostFilePath := path.Join(dir, item.Path)
+		if err := writeFileIfChanged(hostFilePath, data); err != nil {
+			glog.Errorf("Error writing downward API data to host path: %v, %v", hostFilePath, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fieldData returns the content of the file for the given field of pod.
+func fieldData(pod *api.Pod, fieldPath string) ([]byte, error) {
+	switch fieldPath {
+	case "metadata.name":
+		return []byte(pod.Name), nil
+	case "metadata.namespace":
+		return []byte(pod.Namespace), nil
+	case "metadata.labels":
+		return formatMap(pod.Labels), nil
+	case "metadata.annotations":
+		return formatMap(pod.Annotations), nil
+	}
+	return nil, fmt.Errorf("unsupported field path %q", fieldPath)
+}
+
+// formatMap renders m as one key="value" line per entry, sorted by key, with
+// the value quoted using Go escaping rules.
+func formatMap(m map[string]string) []byte {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	for _, k := range keys {
+		fmt.Fprintf(&buf, "%s=%s\n", k, strconv.Quote(m[k]))
+	}
+	return buf.Bytes()
+}
+
+// writeFileIfChanged writes data to filePath unless the file already holds
+// exactly that data. The new content is written to a temporary file first and
+// renamed into place, so readers never observe a partially written file.
+func writeFileIfChanged(filePath string, data []byte) error {
+	if existing, err := ioutil.ReadFile(filePath); err == nil && bytes.Equal(existing, data) {
+		return nil
+	}
+	dir := path.Dir(filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(dir, "."+path.Base(filePath)+".")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), filePath)
+}
+
+func (dv *downwardAPIVolume) GetPath() string {
+	return dv.plugin.host.GetPodVolumeDir(dv.pod.UID, util.EscapeQualifiedNameForDisk(downwardAPIPluginName), dv.volName)
+}
+
+func (dv *downwardAPIVolume) TearDown() error {
+	return dv.TearDownAt(dv.GetPath())
+}
+
+func (dv *downwardAPIVolume) TearDownAt(dir string) error {
+	glog.V(3).Infof("Tearing down volume %v for pod %v at %v", dv.volName, dv.pod.UID, dir)
+
+	// Wrap EmptyDir, let it do the teardown.
+	wrapped, err := dv.plugin.host.NewWrapperCleaner(wrappedVolumeSpec, dv.pod.UID)
+	if err != nil {
+		return err
+	}
+	return wrapped.TearDownAt(dir)
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package downward_api
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/types"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/mount"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/volume"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/volume/empty_dir"
+)
+
+func newTestHost(t *testing.T) volume.VolumeHost {
+	tempDir, err := ioutil.TempDir("/tmp", "downward_api_volume_test.")
+	if err != nil {
+		t.Fatalf("can't make a temp rootdir: %v", err)
+	}
+
+	return volume.NewFakeVolumeHost(tempDir, nil, empty_dir.ProbeVolumePluginsWithMounter(&mount.FakeMounter{}))
+}
+
+func TestCanSupport(t *testing.T) {
+	pluginMgr := volume.VolumePluginMgr{}
+	pluginMgr.InitPlugins(ProbeVolumePlugins(), newTestHost(t))
+
+	plugin, err := pluginMgr.FindPluginByName(downwardAPIPluginName)
+	if err != nil {
+		t.Errorf("Can't find the plugin by name")
+	}
+	if plugin.Name() != downwardAPIPluginName {
+		t.Errorf("Wrong name: %s", plugin.Name())
+	}
+	if !plugin.CanSupport(&api.Volume{VolumeSource: api.VolumeSource{DownwardAPI: &api.DownwardAPIVolumeSource{}}}) {
+		t.Errorf("Expected true")
+	}
+	if plugin.CanSupport(&api.Volume{VolumeSource: api.VolumeSource{EmptyDir: &api.EmptyDirVolumeSource{}}}) {
+		t.Errorf("Expected false")
+	}
+}
+
+func readFile(t *testing.T, filePath string) string {
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Couldn't read downward API data from %v: %v", filePath, err)
+	}
+	return string(data)
+}
+
+func TestPlugin(t *testing.T) {
+	volumeSpec := &api.Volume{
+		Name: "podinfo",
+		VolumeSource: api.VolumeSource{
+			DownwardAPI: &api.DownwardAPIVolumeSource{
+				Items: []api.DownwardAPIVolumeFile{
+					{Path: "name", FieldRef: api.ObjectFieldSelector{FieldPath: "metadata.name"}},
+					{Path: "namespace", FieldRef: api.ObjectFieldSelector{FieldPath: "metadata.namespace"}},
+					{Path: "labels", FieldRef: api.ObjectFieldSelector{FieldPath: "metadata.labels"}},
+					{Path: "meta/annotations", FieldRef: api.ObjectFieldSelector{FieldPath: "metadata.annotations"}},
+				},
+			},
+		},
+	}
+	pod := &api.Pod{
+		ObjectMeta: api.ObjectMeta{
+			Name:        "foo",
+			Namespace:   "test",
+			UID:         types.UID("test_pod_uid"),
+			Labels:      map[string]string{"b": "2", "a": "1"},
+			Annotations: map[string]string{"note": "say \"hi\"\n"},
+		},
+	}
+
+	pluginMgr := volume.VolumePluginMgr{}
+	pluginMgr.InitPlugins(ProbeVolumePlugins(), newTestHost(t))
+
+	plugin, err := pluginMgr.FindPluginByName(downwardAPIPluginName)
+	if err != nil {
+		t.Fatalf("Can't find the plugin by name")
+	}
+
+	builder, err := plugin.NewBuilder(volumeSpec, pod)
+	if err != nil {
+		t.Fatalf("Failed to make a new Builder: %v", err)
+	}
+	volumePath := builder.GetPath()
+	if !strings.HasSuffix(volumePath, "pods/test_pod_uid/volumes/kubernetes.io~downward-api/podinfo") {
+		t.Errorf("Got unexpected path: %s", volumePath)
+	}
+
+	if err := builder.SetUp(); err != nil {
+		t.Fatalf("Failed to setup volume: %v", err)
+	}
+	expected := map[string]string{
+		"name":             "foo",
+		"namespace":        "test",
+		"labels":           "a=\"1\"\nb=\"2\"\n",
+		"meta/annotations": "note=\"say \\\"hi\\\"\\n\"\n",
+	}
+	for file, content := range expected {
+		if actual := readFile(t, path.Join(volumePath, file)); actual != content {
+			t.Errorf("%s: expected %q, got %q", file, content, actual)
+		}
+	}
+
+	// Setting up the volume again with an updated pod refreshes the files
+	// whose content changed and leaves the others alone.
+	nameInfo, err := os.Stat(path.Join(volumePath, "name"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	updated := *pod
+	updated.Labels = map[string]string{"a": "3"}
+	builder, err = plugin.NewBuilder(volumeSpec, &updated)
+	if err != nil {
+		t.Fatalf("Failed to make a new Builder: %v", err)
+	}
+	if err := builder.SetUp(); err != nil {
+		t.Fatalf("Failed to setup volume: %v", err)
+	}
+	if actual := readFile(t, path.Join(volumePath, "labels")); actual != "a=\"3\"\n" {
+		t.Errorf("Expected labels to be refreshed, got %q", actual)
+	}
+	newNameInfo, err := os.Stat(path.Join(volumePath, "name"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !os.SameFile(nameInfo, newNameInfo) {
+		t.Errorf("Expected unchanged file to be left in place")
+	}
+}
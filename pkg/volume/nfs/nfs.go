@@ -65,18 +65,18 @@ func (plugin *nfsPlugin) GetAccessModes() []api.AccessModeType {
 	}
 }
 
-func (plugin *nfsPlugin) NewBuilder(spec *api.Volume, podRef *api.ObjectReference) (volume.Builder, error) {
-	return plugin.newBuilderInternal(spec, podRef, plugin.mounter)
+func (plugin *nfsPlugin) NewBuilder(spec *api.Volume, pod *api.Pod) (volume.Builder, error) {
+	return plugin.newBuilderInternal(spec, pod, plugin.mounter)
 }
 
-func (plugin *nfsPlugin) newBuilderInternal(spec *api.Volume, podRef *api.ObjectReference, mounter nfsMountInterface) (volume.Builder, error) {
+func (plugin *nfsPlugin) newBuilderInternal(spec *api.Volume, pod *api.Pod, mounter nfsMountInterface) (volume.Builder, error) {
 	return &nfs{
 		volName:    spec.Name,
 		server:     spec.VolumeSource.NFS.Server,
 		exportPath: spec.VolumeSource.NFS.Path,
 		readOnly:   spec.VolumeSource.NFS.ReadOnly,
 		mounter:    mounter,
-		podRef:     podRef,
+		pod:        pod,
 		plugin:     plugin,
 	}, nil
 }
@@ -92,7 +92,7 @@ func (plugin *nfsPlugin) newCleanerInternal(volName string, podUID types.UID, mo
 		exportPath: "",
 		readOnly:   false,
 		mounter:    mounter,
-		podRef:     &api.ObjectReference{UID: podUID},
+		pod:        &api.Pod{ObjectMeta: api.ObjectMeta{UID: podUID}},
 		plugin:     plugin,
 	}, nil
 }
@@ -100,7 +100,7 @@ func (plugin *nfsPlugin) newCleanerInternal(volName string, podUID types.UID, mo
 // NFS volumes represent a bare host file or directory mount of an NFS export.
 type nfs struct {
 	volName    string
-	podRef     *api.ObjectReference
+	pod        *api.Pod
 	server     string
 	exportPath string
 	readOnly   bool
@@ -155,7 +155,7 @@ func (nfsVolume *nfs) SetUpAt(dir string) error {
 
 func (nfsVolume *nfs) GetPath() string {
 	name := nfsPluginName
-	return nfsVolume.plugin.host.GetPodVolumeDir(nfsVolume.podRef.UID, util.EscapeQualifiedNameForDisk(name), nfsVolume.volName)
+	return nfsVolume.plugin.host.GetPodVolumeDir(nfsVolume.pod.UID, util.EscapeQualifiedNameForDisk(name), nfsVolume.volName)
 }
 
 func (nfsVolume *nfs) TearDown() error {
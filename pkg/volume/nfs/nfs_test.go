@@ -108,7 +108,7 @@ func TestPlugin(t *testing.T) {
 		VolumeSource: api.VolumeSource{NFS: &api.NFSVolumeSource{"localhost", "/tmp", false}},
 	}
 	fake := &fakeNFSMounter{}
-	builder, err := plug.(*nfsPlugin).newBuilderInternal(spec, &api.ObjectReference{UID: types.UID("poduid")}, fake)
+	builder, err := plug.(*nfsPlugin).newBuilderInternal(spec, &api.Pod{ObjectMeta: api.ObjectMeta{UID: types.UID("poduid")}}, fake)
 	volumePath := builder.GetPath()
 	if err != nil {
 		t.Errorf("Failed to make a new Builder: %v", err)
@@ -55,12 +55,12 @@ func (f *fakeVolumeHost) GetKubeClient() client.Interface {
 	return f.kubeClient
 }
 
-func (f *fakeVolumeHost) NewWrapperBuilder(spec *api.Volume, podRef *api.ObjectReference) (Builder, error) {
+func (f *fakeVolumeHost) NewWrapperBuilder(spec *api.Volume, pod *api.Pod) (Builder, error) {
 	plug, err := f.pluginMgr.FindPluginBySpec(spec)
 	if err != nil {
 		return nil, err
 	}
-	return plug.NewBuilder(spec, podRef)
+	return plug.NewBuilder(spec, pod)
 }
 
 func (f *fakeVolumeHost) NewWrapperCleaner(spec *api.Volume, podUID types.UID) (Cleaner, error) {
@@ -95,8 +95,8 @@ func (plugin *FakeVolumePlugin) CanSupport(spec *api.Volume) bool {
 	return true
 }
 
-func (plugin *FakeVolumePlugin) NewBuilder(spec *api.Volume, podRef *api.ObjectReference) (Builder, error) {
-	return &FakeVolume{podRef.UID, spec.Name, plugin}, nil
+func (plugin *FakeVolumePlugin) NewBuilder(spec *api.Volume, pod *api.Pod) (Builder, error) {
+	return &FakeVolume{pod.UID, spec.Name, plugin}, nil
 }
 
 func (plugin *FakeVolumePlugin) NewCleaner(volName string, podUID types.UID) (Cleaner, error) {
@@ -0,0 +1,301 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumeclaimbinder
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/volume"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+
+	"github.com/golang/glog"
+)
+
+// PersistentVolumeClaimBinder is a controller that binds pending PersistentVolumeClaims
+// to the smallest available PersistentVolume satisfying their access modes and capacity.
+type PersistentVolumeClaimBinder struct {
+	kubeClient  client.Interface
+	volumeStore cache.Store
+	claimStore  cache.Store
+	plugins     *volume.VolumePluginMgr
+}
+
+// NewPersistentVolumeClaimBinder creates a new PersistentVolumeClaimBinder. The plugins
+// are used to determine the access modes supported by each volume.
+func NewPersistentVolumeClaimBinder(kubeClient client.Interface, plugins []volume.VolumePlugin) (*PersistentVolumeClaimBinder, error) {
+	pluginMgr := &volume.VolumePluginMgr{}
+	if err := pluginMgr.InitPlugins(plugins, nil); err != nil {
+		return nil, err
+	}
+
+	volumeStore := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	cache.NewReflector(
+		&cache.ListWatch{
+			ListFunc: func() (runtime.Object, error) {
+				return kubeClient.PersistentVolumes().List(labels.Everything(), fields.Everything())
+			},
+			WatchFunc: func(resourceVersion string) (watch.Interface, error) {
+				return kubeClient.PersistentVolumes().Watch(labels.Everything(), fields.Everything(), resourceVersion)
+			},
+		},
+		&api.PersistentVolume{},
+		volumeStore,
+		0,
+	).Run()
+
+	claimStore := cache.NewStore(cache.MetaNamespaceKeyFunc)
+	cache.NewReflector(
+		&cache.ListWatch{
+			ListFunc: func() (runtime.Object, error) {
+				return kubeClient.PersistentVolumeClaims(api.NamespaceAll).List(labels.Everything(), fields.Everything())
+			},
+			WatchFunc: func(resourceVersion string) (watch.Interface, error) {
+				return kubeClient.PersistentVolumeClaims(api.NamespaceAll).Watch(labels.Everything(), fields.Everything(), resourceVersion)
+			},
+		},
+		&api.PersistentVolumeClaim{},
+		claimStore,
+		0,
+	).Run()
+
+	return &PersistentVolumeClaimBinder{
+		kubeClient:  kubeClient,
+		volumeStore: volumeStore,
+		claimStore:  claimStore,
+		plugins:     pluginMgr,
+	}, nil
+}
+
+// Run begins syncing volumes and claims at the specified period interval
+func (binder *PersistentVolumeClaimBinder) Run(period time.Duration) {
+	go util.Forever(func() { binder.synchronize() }, period)
+}
+
+// synchronize reconciles the phase of every volume and then attempts to bind every pending claim.
+func (binder *PersistentVolumeClaimBinder) synchronize() {
+	for _, obj := range binder.volumeStore.List() {
+		volume := obj.(*api.PersistentVolume)
+		if err := binder.syncVolume(volume); err != nil {
+			glog.Errorf("Error synchronizing persistent volume %s: %v", volume.Name, err)
+		}
+	}
+	for _, obj := range binder.claimStore.List() {
+		claim := obj.(*api.PersistentVolumeClaim)
+		if err := binder.syncClaim(claim); err != nil {
+			glog.Errorf("Error synchronizing persistent volume claim %s/%s: %v", claim.Namespace, claim.Name, err)
+		}
+	}
+}
+
+// syncVolume updates the phase of a volume to reflect the state of the claim it is bound to, if any.
+func (binder *PersistentVolumeClaimBinder) syncVolume(volume *api.PersistentVolume) error {
+	phase := api.VolumeAvailable
+	if volume.Spec.ClaimRef != nil {
+		phase = api.VolumeReleased
+		claim, exists := binder.getClaim(volume.Spec.ClaimRef.Namespace, volume.Spec.ClaimRef.Name)
+		if exists && claim.UID == volume.Spec.ClaimRef.UID {
+			phase = api.VolumeBound
+		}
+	}
+	// released volumes must be recycled before becoming available again: once an administrator
+	// has reclaimed the storage and cleared the claim reference, the volume is available
+	if volume.Status.Phase == phase || (volume.Status.Phase == api.VolumeReleased && volume.Spec.ClaimRef != nil) {
+		return nil
+	}
+
+	glog.V(4).Infof("Persistent volume %s is now %s", volume.Name, phase)
+	obj, err := api.Scheme.Copy(volume)
+	if err != nil {
+		return err
+	}
+	volume = obj.(*api.PersistentVolume)
+	volume.Status.Phase = phase
+	updated, err := binder.kubeClient.PersistentVolumes().UpdateStatus(volume)
+	if err != nil {
+		return err
+	}
+	return binder.volumeStore.Update(updated)
+}
+
+// syncClaim binds a pending claim to the best matching available volume.
+func (binder *PersistentVolumeClaimBinder) syncClaim(claim *api.PersistentVolumeClaim) error {
+	if claim.Status.VolumeRef != nil {
+		return nil
+	}
+
+	// a previous sync may have bound the volume without recording it on the claim
+	volume := binder.findVolumeForClaim(claim)
+	if volume == nil {
+		volume = binder.findBestMatchForClaim(claim)
+		if volume == nil {
+			glog.V(5).Infof("No persistent volume available for claim %s/%s", claim.Namespace, claim.Name)
+			return nil
+		}
+		volume.Spec.ClaimRef = &api.ObjectReference{
+			Kind:      "PersistentVolumeClaim",
+			Namespace: claim.Namespace,
+			Name:      claim.Name,
+			UID:       claim.UID,
+		}
+		updated, err := binder.kubeClient.PersistentVolumes().Update(volume)
+		if err != nil {
+			return fmt.Errorf("error binding volume %s: %v", volume.Name, err)
+		}
+		if err := binder.volumeStore.Update(updated); err != nil {
+			return err
+		}
+		volume = updated
+	}
+
+	accessModes, err := binder.getAccessModes(volume)
+	if err != nil {
+		return err
+	}
+	obj, err := api.Scheme.Copy(claim)
+	if err != nil {
+		return err
+	}
+	claim = obj.(*api.PersistentVolumeClaim)
+	claim.Status = api.PersistentVolumeClaimStatus{
+		Phase:       api.ClaimBound,
+		AccessModes: accessModes,
+		Capacity:    volume.Spec.Capacity,
+		VolumeRef: &api.ObjectReference{
+			Kind: "PersistentVolume",
+			Name: volume.Name,
+			UID:  volume.UID,
+		},
+	}
+	glog.V(4).Infof("Binding persistent volume claim %s/%s to volume %s", claim.Namespace, claim.Name, volume.Name)
+	updated, err := binder.kubeClient.PersistentVolumeClaims(claim.Namespace).UpdateStatus(claim)
+	if err != nil {
+		return fmt.Errorf("error updating claim %s/%s: %v", claim.Namespace, claim.Name, err)
+	}
+	return binder.claimStore.Update(updated)
+}
+
+// getClaim returns the claim with the given namespace and name from the local cache.
+func (binder *PersistentVolumeClaimBinder) getClaim(namespace, name string) (*api.PersistentVolumeClaim, bool) {
+	obj, exists, err := binder.claimStore.Get(&api.PersistentVolumeClaim{ObjectMeta: api.ObjectMeta{Namespace: namespace, Name: name}})
+	if err != nil || !exists {
+		return nil, false
+	}
+	return obj.(*api.PersistentVolumeClaim), true
+}
+
+// findVolumeForClaim returns the volume already bound to the claim, if any.
+func (binder *PersistentVolumeClaimBinder) findVolumeForClaim(claim *api.PersistentVolumeClaim) *api.PersistentVolume {
+	for _, obj := range binder.volumeStore.List() {
+		volume := obj.(*api.PersistentVolume)
+		ref := volume.Spec.ClaimRef
+		if ref != nil && ref.Namespace == claim.Namespace && ref.Name == claim.Name && ref.UID == claim.UID {
+			return volume
+		}
+	}
+	return nil
+}
+
+// findBestMatchForClaim returns the smallest unbound volume which supports all the access modes
+// requested by the claim and whose capacity is at least the requested storage.
+func (binder *PersistentVolumeClaimBinder) findBestMatchForClaim(claim *api.PersistentVolumeClaim) *api.PersistentVolume {
+	requested := claim.Spec.Resources.Requests[api.ResourceStorage]
+	requestedBytes := requested.Value()
+
+	var best *api.PersistentVolume
+	for _, obj := range binder.volumeStore.List() {
+		volume := obj.(*api.PersistentVolume)
+		if volume.Spec.ClaimRef != nil {
+			continue
+		}
+		capacity, ok := volume.Spec.Capacity[api.ResourceStorage]
+		if !ok || capacity.Value() < requestedBytes {
+			continue
+		}
+		accessModes, err := binder.getAccessModes(volume)
+		if err != nil {
+			glog.V(4).Infof("Skipping persistent volume %s: %v", volume.Name, err)
+			continue
+		}
+		if !containsAllAccessModes(accessModes, claim.Spec.AccessModes) {
+			continue
+		}
+		if best != nil {
+			bestCapacity := best.Spec.Capacity[api.ResourceStorage]
+			if capacity.Value() >= bestCapacity.Value() {
+				continue
+			}
+		}
+		best = volume
+	}
+	if best == nil {
+		return nil
+	}
+	// the store owns the cached object, hand out a copy that can be mutated
+	copied, err := api.Scheme.Copy(best)
+	if err != nil {
+		glog.Errorf("Unable to copy persistent volume %s: %v", best.Name, err)
+		return nil
+	}
+	return copied.(*api.PersistentVolume)
+}
+
+// getAccessModes returns the access modes supported by the volume plugin backing the volume.
+func (binder *PersistentVolumeClaimBinder) getAccessModes(volume *api.PersistentVolume) ([]api.AccessModeType, error) {
+	plugin, err := binder.plugins.FindPersistentPluginBySpec(volumeSpecFor(volume))
+	if err != nil {
+		return nil, err
+	}
+	return plugin.GetAccessModes(), nil
+}
+
+// volumeSpecFor returns the volume a pod would mount for the source of the persistent volume,
+// so that every source of persistent volume is matched against the volume plugins. Sources
+// added to PersistentVolumeSource must be copied here too.
+func volumeSpecFor(volume *api.PersistentVolume) *api.Volume {
+	return &api.Volume{
+		Name: volume.Name,
+		VolumeSource: api.VolumeSource{
+			GCEPersistentDisk: volume.Spec.GCEPersistentDisk,
+			HostPath:          volume.Spec.HostPath,
+		},
+	}
+}
+
+// containsAllAccessModes returns true if every requested mode is in the supported modes.
+func containsAllAccessModes(supported, requested []api.AccessModeType) bool {
+	for _, mode := range requested {
+		found := false
+		for _, m := range supported {
+			if m == mode {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
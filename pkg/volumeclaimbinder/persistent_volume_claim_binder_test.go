@@ -0,0 +1,294 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumeclaimbinder
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/types"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/volume"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/volume/gce_pd"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/volume/host_path"
+)
+
+func newTestBinder(t *testing.T, mockClient client.Interface) *PersistentVolumeClaimBinder {
+	plugins := &volume.VolumePluginMgr{}
+	allPlugins := append(host_path.ProbeVolumePlugins(), gce_pd.ProbeVolumePlugins()...)
+	if err := plugins.InitPlugins(allPlugins, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return &PersistentVolumeClaimBinder{
+		kubeClient:  mockClient,
+		volumeStore: cache.NewStore(cache.MetaNamespaceKeyFunc),
+		claimStore:  cache.NewStore(cache.MetaNamespaceKeyFunc),
+		plugins:     plugins,
+	}
+}
+
+func hostPathVolume(name, size string) *api.PersistentVolume {
+	return &api.PersistentVolume{
+		ObjectMeta: api.ObjectMeta{Name: name, UID: types.UID("uid-" + name)},
+		Spec: api.PersistentVolumeSpec{
+			Capacity: api.ResourceList{
+				api.ResourceStorage: resource.MustParse(size),
+			},
+			PersistentVolumeSource: api.PersistentVolumeSource{
+				HostPath: &api.HostPathVolumeSource{Path: "/tmp/" + name},
+			},
+		},
+		Status: api.PersistentVolumeStatus{Phase: api.VolumeAvailable},
+	}
+}
+
+func gcePDVolume(name, size string) *api.PersistentVolume {
+	return &api.PersistentVolume{
+		ObjectMeta: api.ObjectMeta{Name: name, UID: types.UID("uid-" + name)},
+		Spec: api.PersistentVolumeSpec{
+			Capacity: api.ResourceList{
+				api.ResourceStorage: resource.MustParse(size),
+			},
+			PersistentVolumeSource: api.PersistentVolumeSource{
+				GCEPersistentDisk: &api.GCEPersistentDiskVolumeSource{PDName: name, FSType: "ext4"},
+			},
+		},
+		Status: api.PersistentVolumeStatus{Phase: api.VolumeAvailable},
+	}
+}
+
+func claim(name, size string, modes ...api.AccessModeType) *api.PersistentVolumeClaim {
+	return &api.PersistentVolumeClaim{
+		ObjectMeta: api.ObjectMeta{Name: name, Namespace: api.NamespaceDefault, UID: types.UID("uid-" + name)},
+		Spec: api.PersistentVolumeClaimSpec{
+			AccessModes: modes,
+			Resources: api.ResourceRequirements{
+				Requests: api.ResourceList{
+					api.ResourceStorage: resource.MustParse(size),
+				},
+			},
+		},
+		Status: api.PersistentVolumeClaimStatus{Phase: api.ClaimPending},
+	}
+}
+
+func TestFindBestMatchForClaim(t *testing.T) {
+	binder := newTestBinder(t, &client.Fake{})
+	binder.volumeStore.Add(hostPathVolume("small", "1G"))
+	binder.volumeStore.Add(hostPathVolume("medium", "5G"))
+	binder.volumeStore.Add(hostPathVolume("large", "10G"))
+	binder.volumeStore.Add(gcePDVolume("gce-large", "10G"))
+	bound := hostPathVolume("bound", "3G")
+	bound.Spec.ClaimRef = &api.ObjectReference{Namespace: api.NamespaceDefault, Name: "other"}
+	binder.volumeStore.Add(bound)
+
+	tests := map[string]struct {
+		claim    *api.PersistentVolumeClaim
+		expected string
+	}{
+		"smallest that fits": {
+			claim:    claim("a", "2G", api.ReadWriteOnce),
+			expected: "medium",
+		},
+		"exact fit": {
+			claim:    claim("b", "1G", api.ReadWriteOnce),
+			expected: "small",
+		},
+		"access modes": {
+			claim:    claim("c", "2G", api.ReadOnlyMany),
+			expected: "gce-large",
+		},
+		"too large": {
+			claim:    claim("d", "20G", api.ReadWriteOnce),
+			expected: "",
+		},
+		"unsupported access modes": {
+			claim:    claim("e", "1G", api.ReadWriteMany),
+			expected: "",
+		},
+	}
+	for name, test := range tests {
+		volume := binder.findBestMatchForClaim(test.claim)
+		if test.expected == "" {
+			if volume != nil {
+				t.Errorf("%s: expected no match, got %s", name, volume.Name)
+			}
+			continue
+		}
+		if volume == nil {
+			t.Errorf("%s: expected %s, got no match", name, test.expected)
+			continue
+		}
+		if volume.Name != test.expected {
+			t.Errorf("%s: expected %s, got %s", name, test.expected, volume.Name)
+		}
+	}
+}
+
+func TestSyncClaimBindsVolume(t *testing.T) {
+	mockClient := &client.Fake{}
+	binder := newTestBinder(t, mockClient)
+	binder.volumeStore.Add(hostPathVolume("small", "1G"))
+	binder.volumeStore.Add(hostPathVolume("large", "10G"))
+	pvc := claim("foo", "5G", api.ReadWriteOnce)
+	binder.claimStore.Add(pvc)
+
+	if err := binder.syncClaim(pvc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(mockClient.Actions) != 2 {
+		t.Fatalf("Expected 2 mock client actions, got %#v", mockClient.Actions)
+	}
+	if e, a := "update-persistentVolume", mockClient.Actions[0].Action; e != a {
+		t.Errorf("Expected %s, got %s", e, a)
+	}
+	if e, a := "update-status-persistentVolumeClaim", mockClient.Actions[1].Action; e != a {
+		t.Errorf("Expected %s, got %s", e, a)
+	}
+
+	obj, _, _ := binder.volumeStore.Get(&api.PersistentVolume{ObjectMeta: api.ObjectMeta{Name: "large"}})
+	volume := obj.(*api.PersistentVolume)
+	if volume.Spec.ClaimRef == nil || volume.Spec.ClaimRef.Name != "foo" || volume.Spec.ClaimRef.UID != pvc.UID {
+		t.Errorf("Expected volume to reference the claim, got %#v", volume.Spec.ClaimRef)
+	}
+
+	obj, _, _ = binder.claimStore.Get(pvc)
+	bound := obj.(*api.PersistentVolumeClaim)
+	if bound.Status.Phase != api.ClaimBound {
+		t.Errorf("Expected claim to be bound, got %s", bound.Status.Phase)
+	}
+	if bound.Status.VolumeRef == nil || bound.Status.VolumeRef.Name != "large" {
+		t.Errorf("Expected claim to reference volume large, got %#v", bound.Status.VolumeRef)
+	}
+	if len(bound.Status.AccessModes) != 1 || bound.Status.AccessModes[0] != api.ReadWriteOnce {
+		t.Errorf("Unexpected access modes: %v", bound.Status.AccessModes)
+	}
+	capacity := bound.Status.Capacity[api.ResourceStorage]
+	if capacity.String() != "10G" {
+		t.Errorf("Expected capacity 10G, got %s", capacity.String())
+	}
+
+	// a second claim cannot reuse the bound volume
+	other := claim("bar", "5G", api.ReadWriteOnce)
+	mockClient.Actions = nil
+	if err := binder.syncClaim(other); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mockClient.Actions) != 0 {
+		t.Errorf("Expected no mock client actions, got %#v", mockClient.Actions)
+	}
+}
+
+func TestSyncClaimCompletesPartialBind(t *testing.T) {
+	mockClient := &client.Fake{}
+	binder := newTestBinder(t, mockClient)
+	pvc := claim("foo", "5G", api.ReadWriteOnce)
+	volume := hostPathVolume("large", "10G")
+	volume.Spec.ClaimRef = &api.ObjectReference{Namespace: pvc.Namespace, Name: pvc.Name, UID: pvc.UID}
+	binder.volumeStore.Add(volume)
+	binder.claimStore.Add(pvc)
+
+	if err := binder.syncClaim(pvc); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mockClient.Actions) != 1 || mockClient.Actions[0].Action != "update-status-persistentVolumeClaim" {
+		t.Fatalf("Expected only a claim status update, got %#v", mockClient.Actions)
+	}
+}
+
+func TestSyncVolumePhase(t *testing.T) {
+	pvc := claim("foo", "5G", api.ReadWriteOnce)
+	tests := map[string]struct {
+		claimRef      *api.ObjectReference
+		phase         api.PersistentVolumePhase
+		expectedPhase api.PersistentVolumePhase
+	}{
+		"unbound": {
+			phase:         api.VolumeAvailable,
+			expectedPhase: api.VolumeAvailable,
+		},
+		"bound": {
+			claimRef:      &api.ObjectReference{Namespace: pvc.Namespace, Name: pvc.Name, UID: pvc.UID},
+			phase:         api.VolumeAvailable,
+			expectedPhase: api.VolumeBound,
+		},
+		"claim deleted": {
+			claimRef:      &api.ObjectReference{Namespace: pvc.Namespace, Name: "missing", UID: "missing"},
+			phase:         api.VolumeBound,
+			expectedPhase: api.VolumeReleased,
+		},
+		"claim recreated": {
+			claimRef:      &api.ObjectReference{Namespace: pvc.Namespace, Name: pvc.Name, UID: "old-uid"},
+			phase:         api.VolumeBound,
+			expectedPhase: api.VolumeReleased,
+		},
+		"released stays released": {
+			claimRef:      &api.ObjectReference{Namespace: pvc.Namespace, Name: "missing", UID: "missing"},
+			phase:         api.VolumeReleased,
+			expectedPhase: api.VolumeReleased,
+		},
+		"released and reclaimed": {
+			phase:         api.VolumeReleased,
+			expectedPhase: api.VolumeAvailable,
+		},
+	}
+	for name, test := range tests {
+		mockClient := &client.Fake{}
+		binder := newTestBinder(t, mockClient)
+		binder.claimStore.Add(pvc)
+		volume := hostPathVolume("vol", "10G")
+		volume.Spec.ClaimRef = test.claimRef
+		volume.Status.Phase = test.phase
+		binder.volumeStore.Add(volume)
+
+		if err := binder.syncVolume(volume); err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		obj, _, _ := binder.volumeStore.Get(volume)
+		if e, a := test.expectedPhase, obj.(*api.PersistentVolume).Status.Phase; e != a {
+			t.Errorf("%s: expected phase %s, got %s", name, e, a)
+		}
+		if test.phase == test.expectedPhase && len(mockClient.Actions) != 0 {
+			t.Errorf("%s: expected no mock client actions, got %#v", name, mockClient.Actions)
+		}
+	}
+}
+
+// Every source of persistent volume must be backed by one of the plugins probed by
+// ProbePersistentVolumePlugins in the controller manager.
+func TestGetAccessModesForEverySource(t *testing.T) {
+	binder := newTestBinder(t, &client.Fake{})
+	sourceType := reflect.TypeOf(api.PersistentVolumeSource{})
+	for i := 0; i < sourceType.NumField(); i++ {
+		field := sourceType.Field(i)
+		volume := &api.PersistentVolume{ObjectMeta: api.ObjectMeta{Name: "vol"}}
+		reflect.ValueOf(&volume.Spec.PersistentVolumeSource).Elem().Field(i).Set(reflect.New(field.Type.Elem()))
+		accessModes, err := binder.getAccessModes(volume)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", field.Name, err)
+			continue
+		}
+		if len(accessModes) == 0 {
+			t.Errorf("%s: expected access modes", field.Name)
+		}
+	}
+}
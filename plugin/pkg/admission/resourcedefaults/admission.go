@@ -64,6 +64,15 @@ func (resourceDefaults) Admit(a admission.Attributes) (err error) {
 		if pod.Spec.Containers[index].Resources.Limits.Cpu().Value() == 0 {
 			pod.Spec.Containers[index].Resources.Limits[api.ResourceCPU] = resource.MustParse(defaultCPU)
 		}
+		// Containers which do not request resources request their limits, as API defaulting does.
+		if pod.Spec.Containers[index].Resources.Requests == nil {
+			pod.Spec.Containers[index].Resources.Requests = api.ResourceList{}
+		}
+		for _, name := range []api.ResourceName{api.ResourceCPU, api.ResourceMemory} {
+			if _, found := pod.Spec.Containers[index].Resources.Requests[name]; !found {
+				pod.Spec.Containers[index].Resources.Requests[name] = pod.Spec.Containers[index].Resources.Limits[name]
+			}
+		}
 	}
 	return nil
 }
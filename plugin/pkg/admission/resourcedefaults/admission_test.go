@@ -50,6 +50,10 @@ func TestAdmission(t *testing.T) {
 		if cpu != "1" {
 			t.Errorf("Unexpected cpu value %s", cpu)
 		}
+		requests := pod.Spec.Containers[i].Resources.Requests
+		if requests.Memory().String() != "512Mi" || requests.Cpu().String() != "1" {
+			t.Errorf("Unexpected requests %v", requests)
+		}
 	}
 }
 
@@ -19,6 +19,8 @@ package resourcequota
 import (
 	"fmt"
 	"io"
+	"strconv"
+	"sync"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/admission"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
@@ -40,9 +42,21 @@ func init() {
 	})
 }
 
+// maxStatusUpdateAttempts bounds how many times a quota status update is
+// retried when it conflicts with a concurrent update.
+const maxStatusUpdateAttempts = 3
+
 type quota struct {
 	client  client.Interface
 	indexer cache.Indexer
+
+	// lock guards updated.
+	lock sync.Mutex
+	// updated holds the quotas whose status this plugin wrote, keyed by
+	// namespace/name, until the watch delivers them or a newer version.
+	// Without it, admissions that follow one another closely would be
+	// checked against stale usage and fail with conflicts.
+	updated map[string]*api.ResourceQuota
 }
 
 func NewResourceQuota(client client.Interface) admission.Interface {
@@ -56,7 +70,7 @@ func NewResourceQuota(client client.Interface) admission.Interface {
 	}
 	indexer, reflector := cache.NewNamespaceKeyedIndexerAndReflector(lw, &api.ResourceQuota{}, 0)
 	reflector.Run()
-	return &quota{client: client, indexer: indexer}
+	return &quota{client: client, indexer: indexer, updated: map[string]*api.ResourceQuota{}}
 }
 
 var resourceToResourceName = map[string]api.ResourceName{
@@ -64,6 +78,56 @@ var resourceToResourceName = map[string]api.ResourceName{
 	"services":               api.ResourceServices,
 	"replicationControllers": api.ResourceReplicationControllers,
 	"resourceQuotas":         api.ResourceQuotas,
+	"secrets":                api.ResourceSecrets,
+	"persistentVolumeClaims": api.ResourcePersistentVolumeClaims,
+}
+
+// resourceVersionNewer returns true if resource version a is known to be newer than b.
+func resourceVersionNewer(a, b string) bool {
+	aVersion, err := strconv.ParseUint(a, 10, 64)
+	if err != nil {
+		return false
+	}
+	bVersion, err := strconv.ParseUint(b, 10, 64)
+	if err != nil {
+		return false
+	}
+	return aVersion > bVersion
+}
+
+// latest returns the newest version of cached that this plugin knows about:
+// either cached itself or the result of the last status update made by this
+// plugin, if the watch has not caught up with it yet.
+func (q *quota) latest(cached *api.ResourceQuota) *api.ResourceQuota {
+	key, err := cache.MetaNamespaceKeyFunc(cached)
+	if err != nil {
+		return cached
+	}
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	updated, found := q.updated[key]
+	if !found {
+		return cached
+	}
+	if resourceVersionNewer(updated.ResourceVersion, cached.ResourceVersion) {
+		return updated
+	}
+	delete(q.updated, key)
+	return cached
+}
+
+// recordUpdate remembers the result of a status update made by this plugin.
+func (q *quota) recordUpdate(updated *api.ResourceQuota) {
+	key, err := cache.MetaNamespaceKeyFunc(updated)
+	if err != nil {
+		return
+	}
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if previous, found := q.updated[key]; found && resourceVersionNewer(previous.ResourceVersion, updated.ResourceVersion) {
+		return
+	}
+	q.updated[key] = updated
 }
 
 func (q *quota) Admit(a admission.Attributes) (err error) {
@@ -93,8 +157,19 @@ func (q *quota) Admit(a admission.Attributes) (err error) {
 	}
 
 	for i := range items {
-		quota := items[i].(*api.ResourceQuota)
+		if err := q.admitToQuota(a, name, q.latest(items[i].(*api.ResourceQuota))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
+// admitToQuota charges the operation described by a against quota. The new
+// usage is written with the resource version of the quota it was computed
+// from, so two concurrent admissions cannot both consume the last unit of a
+// resource: the loser gets a conflict, re-reads the quota and tries again.
+func (q *quota) admitToQuota(a admission.Attributes, name string, quota *api.ResourceQuota) error {
+	for attempt := 1; ; attempt++ {
 		// we cannot modify the value directly in the cache, so we copy
 		status := &api.ResourceQuotaStatus{
 			Hard: api.ResourceList{},
@@ -111,25 +186,34 @@ func (q *quota) Admit(a admission.Attributes) (err error) {
 		if err != nil {
 			return err
 		}
+		if !dirty {
+			return nil
+		}
 
-		if dirty {
-			// construct a usage record
-			usage := api.ResourceQuota{
-				ObjectMeta: api.ObjectMeta{
-					Name:            quota.Name,
-					Namespace:       quota.Namespace,
-					ResourceVersion: quota.ResourceVersion,
-					Labels:          quota.Labels,
-					Annotations:     quota.Annotations},
-			}
-			usage.Status = *status
-			_, err = q.client.ResourceQuotas(usage.Namespace).UpdateStatus(&usage)
-			if err != nil {
-				return apierrors.NewForbidden(a.GetResource(), name, fmt.Errorf("Unable to %s %s at this time because there was an error enforcing quota", a.GetOperation(), a.GetResource()))
-			}
+		// construct a usage record
+		usage := api.ResourceQuota{
+			ObjectMeta: api.ObjectMeta{
+				Name:            quota.Name,
+				Namespace:       quota.Namespace,
+				ResourceVersion: quota.ResourceVersion,
+				Labels:          quota.Labels,
+				Annotations:     quota.Annotations},
+		}
+		usage.Status = *status
+		updated, err := q.client.ResourceQuotas(usage.Namespace).UpdateStatus(&usage)
+		if err == nil {
+			q.recordUpdate(updated)
+			return nil
+		}
+		if !apierrors.IsConflict(err) || attempt >= maxStatusUpdateAttempts {
+			return apierrors.NewForbidden(a.GetResource(), name, fmt.Errorf("Unable to %s %s at this time because there was an error enforcing quota", a.GetOperation(), a.GetResource()))
+		}
+		// someone else changed the quota, start over from its current state
+		quota, err = q.client.ResourceQuotas(usage.Namespace).Get(usage.Name)
+		if err != nil {
+			return apierrors.NewForbidden(a.GetResource(), name, fmt.Errorf("Unable to %s %s at this time because there was an error enforcing quota", a.GetOperation(), a.GetResource()))
 		}
 	}
-	return nil
 }
 
 // IncrementUsage updates the supplied ResourceQuotaStatus object based on the incoming operation
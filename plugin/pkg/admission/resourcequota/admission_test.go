@@ -17,22 +17,25 @@ limitations under the License.
 package resourcequota
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/admission"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	apierrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
 )
 
 func getResourceRequirements(cpu, memory string) api.ResourceRequirements {
 	res := api.ResourceRequirements{}
-	res.Limits = api.ResourceList{}
+	res.Requests = api.ResourceList{}
 	if cpu != "" {
-		res.Limits[api.ResourceCPU] = resource.MustParse(cpu)
+		res.Requests[api.ResourceCPU] = resource.MustParse(cpu)
 	}
 	if memory != "" {
-		res.Limits[api.ResourceMemory] = resource.MustParse(memory)
+		res.Requests[api.ResourceMemory] = resource.MustParse(memory)
 	}
 
 	return res
@@ -375,3 +378,98 @@ func TestExceedUsageReplicationControllers(t *testing.T) {
 		t.Errorf("Expected error for exceeding hard limits")
 	}
 }
+
+func TestIncrementUsageSecretsAndClaims(t *testing.T) {
+	namespace := "default"
+	for resourceName, r := range map[string]api.ResourceName{
+		"secrets":                api.ResourceSecrets,
+		"persistentVolumeClaims": api.ResourcePersistentVolumeClaims,
+	} {
+		status := &api.ResourceQuotaStatus{
+			Hard: api.ResourceList{r: resource.MustParse("2")},
+			Used: api.ResourceList{r: resource.MustParse("1")},
+		}
+		dirty, err := IncrementUsage(admission.NewAttributesRecord(&api.Secret{}, namespace, resourceName, "CREATE"), status, &client.Fake{})
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", resourceName, err)
+		}
+		if !dirty {
+			t.Errorf("%s: expected the status to get incremented", resourceName)
+		}
+		if quantity := status.Used[r]; quantity.Value() != 2 {
+			t.Errorf("%s: expected new item count to be 2, but was %s", resourceName, quantity.String())
+		}
+		if _, err := IncrementUsage(admission.NewAttributesRecord(&api.Secret{}, namespace, resourceName, "CREATE"), status, &client.Fake{}); err == nil {
+			t.Errorf("%s: expected error because this would exceed the quota", resourceName)
+		}
+	}
+}
+
+// conflictingQuotas is a ResourceQuotaInterface whose stored quota can be
+// changed behind the admission plugin's back. UpdateStatus enforces the
+// resource version like the apiserver does.
+type conflictingQuotas struct {
+	client.FakeResourceQuotas
+	stored  *api.ResourceQuota
+	updates int
+}
+
+func (c *conflictingQuotas) Get(name string) (*api.ResourceQuota, error) {
+	copied := *c.stored
+	return &copied, nil
+}
+
+func (c *conflictingQuotas) UpdateStatus(quota *api.ResourceQuota) (*api.ResourceQuota, error) {
+	if quota.ResourceVersion != c.stored.ResourceVersion {
+		return nil, apierrors.NewConflict("resourceQuotas", quota.Name, fmt.Errorf("resource version mismatch"))
+	}
+	c.updates++
+	updated := *quota
+	updated.ResourceVersion = fmt.Sprintf("%d", 100+c.updates)
+	c.stored = &updated
+	return &updated, nil
+}
+
+type conflictingClient struct {
+	*client.Fake
+	quotas *conflictingQuotas
+}
+
+func (c *conflictingClient) ResourceQuotas(namespace string) client.ResourceQuotaInterface {
+	return c.quotas
+}
+
+func newPodQuota(resourceVersion string, used string) *api.ResourceQuota {
+	return &api.ResourceQuota{
+		ObjectMeta: api.ObjectMeta{Name: "quota", Namespace: "test", ResourceVersion: resourceVersion},
+		Status: api.ResourceQuotaStatus{
+			Hard: api.ResourceList{api.ResourcePods: resource.MustParse("3")},
+			Used: api.ResourceList{api.ResourcePods: resource.MustParse(used)},
+		},
+	}
+}
+
+func TestAdmissionRetriesOnConflict(t *testing.T) {
+	// The cached quota is out of date: another pod was admitted since.
+	quotas := &conflictingQuotas{stored: newPodQuota("2", "2")}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{"namespace": cache.MetaNamespaceIndexFunc})
+	indexer.Add(newPodQuota("1", "1"))
+	handler := &quota{client: &conflictingClient{&client.Fake{}, quotas}, indexer: indexer, updated: map[string]*api.ResourceQuota{}}
+
+	pod := &api.Pod{ObjectMeta: api.ObjectMeta{Name: "pod", Namespace: "test"}}
+	if err := handler.Admit(admission.NewAttributesRecord(pod, "test", "pods", "CREATE")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if used := quotas.stored.Status.Used[api.ResourcePods]; used.Value() != 3 {
+		t.Errorf("expected usage to be computed from the live quota, got %s", used.String())
+	}
+
+	// The watch has not caught up, but the plugin must not admit against the
+	// stale usage it still holds in its cache.
+	if err := handler.Admit(admission.NewAttributesRecord(pod, "test", "pods", "CREATE")); err == nil {
+		t.Errorf("expected the quota to be exhausted")
+	}
+	if quotas.updates != 1 {
+		t.Errorf("expected 1 status update, got %d", quotas.updates)
+	}
+}
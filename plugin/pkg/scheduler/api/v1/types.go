@@ -17,6 +17,8 @@ limitations under the License.
 package v1
 
 import (
+	"time"
+
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/v1beta3"
 )
 
@@ -26,6 +28,8 @@ type Policy struct {
 	Predicates []PredicatePolicy `json:"predicates"`
 	// Holds the information to configure the priority functions
 	Priorities []PriorityPolicy `json:"priorities"`
+	// Holds the information to communicate with the extender(s)
+	ExtenderConfigs []ExtenderConfig `json:"extenders"`
 }
 
 type PredicatePolicy struct {
@@ -101,3 +105,47 @@ type LabelPreference struct {
 	// If false, higher priority is given to minions that do not have the label
 	Presence bool `json:"presence"`
 }
+
+// Holds the parameters used to communicate with the extender. If a verb is unspecified/empty,
+// it is assumed that the extender chose not to provide that extension.
+type ExtenderConfig struct {
+	// URLPrefix at which the extender is available
+	URLPrefix string `json:"urlPrefix"`
+	// Verb for the filter call, empty if not supported. This verb is appended to the URLPrefix when issuing the filter call to extender.
+	FilterVerb string `json:"filterVerb,omitempty"`
+	// Verb for the prioritize call, empty if not supported. This verb is appended to the URLPrefix when issuing the prioritize call to extender.
+	PrioritizeVerb string `json:"prioritizeVerb,omitempty"`
+	// The numeric multiplier for the minion scores that the prioritize call generates
+	Weight int `json:"weight,omitempty"`
+	// HTTPTimeout specifies the timeout duration for a call to the extender. Filter timeout fails the scheduling of the pod.
+	// Prioritize timeout is ignored, k8s/other extenders priorities are used to select the minion.
+	HTTPTimeout time.Duration `json:"httpTimeout,omitempty"`
+}
+
+// ExtenderArgs represents the arguments needed by the extender to filter/prioritize
+// minions for a pod.
+type ExtenderArgs struct {
+	// Pod being scheduled
+	Pod v1beta3.Pod `json:"pod"`
+	// List of candidate minions where the pod can be scheduled
+	Nodes v1beta3.NodeList `json:"nodes"`
+}
+
+// ExtenderFilterResult represents the results of a filter call to an extender
+type ExtenderFilterResult struct {
+	// Filtered set of minions where the pod can be scheduled
+	Nodes v1beta3.NodeList `json:"nodes,omitempty"`
+	// Error message indicating failure
+	Error string `json:"error,omitempty"`
+}
+
+// HostPriority represents the priority of scheduling to a particular minion, as returned by
+// the prioritize call to an extender. A higher score is better.
+type HostPriority struct {
+	// Name of the minion
+	Host string `json:"host"`
+	// Score associated with the minion
+	Score int `json:"score"`
+}
+
+type HostPriorityList []HostPriority
@@ -0,0 +1,139 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	algorithm "github.com/GoogleCloudPlatform/kubernetes/pkg/scheduler"
+	schedulerapi "github.com/GoogleCloudPlatform/kubernetes/plugin/pkg/scheduler/api"
+	schedulerapiv1 "github.com/GoogleCloudPlatform/kubernetes/plugin/pkg/scheduler/api/v1"
+)
+
+// DefaultExtenderTimeout is the timeout of a call to an extender when none is configured.
+const DefaultExtenderTimeout = 5 * time.Second
+
+// HTTPExtender implements the algorithm.SchedulerExtender interface by POSTing the pod
+// and the candidate minions, in the v1beta3 format, to an external process.
+type HTTPExtender struct {
+	extenderURL    string
+	filterVerb     string
+	prioritizeVerb string
+	weight         int
+	client         *http.Client
+}
+
+// NewHTTPExtender creates an HTTPExtender from its configuration.
+func NewHTTPExtender(config *schedulerapi.ExtenderConfig) (algorithm.SchedulerExtender, error) {
+	if len(config.URLPrefix) == 0 {
+		return nil, fmt.Errorf("an extender must specify a urlPrefix")
+	}
+	if config.Weight < 0 {
+		return nil, fmt.Errorf("the weight of extender %s must be non-negative", config.URLPrefix)
+	}
+	timeout := config.HTTPTimeout
+	if timeout == 0 {
+		timeout = DefaultExtenderTimeout
+	}
+	return &HTTPExtender{
+		extenderURL:    strings.TrimRight(config.URLPrefix, "/"),
+		filterVerb:     config.FilterVerb,
+		prioritizeVerb: config.PrioritizeVerb,
+		weight:         config.Weight,
+		client:         &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Filter asks the extender which of the given minions the pod fits on. The minions are
+// returned unchanged when the extender does not support filtering.
+func (h *HTTPExtender) Filter(pod api.Pod, nodes api.NodeList) (api.NodeList, error) {
+	if len(h.filterVerb) == 0 {
+		return nodes, nil
+	}
+
+	var result schedulerapiv1.ExtenderFilterResult
+	if err := h.send(h.filterVerb, pod, nodes, &result); err != nil {
+		return api.NodeList{}, err
+	}
+	if len(result.Error) != 0 {
+		return api.NodeList{}, fmt.Errorf("extender %s failed to filter minions: %s", h.extenderURL, result.Error)
+	}
+
+	filtered := api.NodeList{}
+	if err := api.Scheme.Convert(&result.Nodes, &filtered); err != nil {
+		return api.NodeList{}, err
+	}
+	return filtered, nil
+}
+
+// Prioritize asks the extender to score the given minions, and returns the scores with the
+// weight configured for the extender. Extenders that do not support prioritizing have no say.
+func (h *HTTPExtender) Prioritize(pod api.Pod, nodes api.NodeList) (algorithm.HostPriorityList, int, error) {
+	if len(h.prioritizeVerb) == 0 {
+		return algorithm.HostPriorityList{}, 0, nil
+	}
+
+	var result schedulerapiv1.HostPriorityList
+	if err := h.send(h.prioritizeVerb, pod, nodes, &result); err != nil {
+		return algorithm.HostPriorityList{}, 0, err
+	}
+
+	priorities := algorithm.HostPriorityList{}
+	for _, hostPriority := range result {
+		priorities = append(priorities, algorithm.HostPriority{Host: hostPriority.Host, Score: hostPriority.Score})
+	}
+	return priorities, h.weight, nil
+}
+
+// send POSTs the pod and minions to the given verb of the extender and decodes the
+// response into result.
+func (h *HTTPExtender) send(verb string, pod api.Pod, nodes api.NodeList, result interface{}) error {
+	args := schedulerapiv1.ExtenderArgs{}
+	if err := api.Scheme.Convert(&pod, &args.Pod); err != nil {
+		return err
+	}
+	if err := api.Scheme.Convert(&nodes, &args.Nodes); err != nil {
+		return err
+	}
+	body, err := json.Marshal(&args)
+	if err != nil {
+		return err
+	}
+
+	url := h.extenderURL + "/" + verb
+	resp, err := h.client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("extender %s returned %d: %s", url, resp.StatusCode, string(data))
+	}
+	return json.Unmarshal(data, result)
+}
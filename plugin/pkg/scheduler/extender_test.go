@@ -0,0 +1,156 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api"
+	algorithm "github.com/GoogleCloudPlatform/kubernetes/pkg/scheduler"
+	schedulerapi "github.com/GoogleCloudPlatform/kubernetes/plugin/pkg/scheduler/api"
+	schedulerapiv1 "github.com/GoogleCloudPlatform/kubernetes/plugin/pkg/scheduler/api/v1"
+)
+
+// newExtenderServer serves an extender that keeps the minions whose name matches the
+// pod's, and scores each minion with the length of its name.
+func newExtenderServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var args schedulerapiv1.ExtenderArgs
+		if err := json.NewDecoder(req.Body).Decode(&args); err != nil {
+			t.Errorf("unexpected error decoding extender args: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var result interface{}
+		switch req.URL.Path {
+		case "/scheduler/filter":
+			filtered := schedulerapiv1.ExtenderFilterResult{}
+			for _, node := range args.Nodes.Items {
+				if node.Name == args.Pod.Name {
+					filtered.Nodes.Items = append(filtered.Nodes.Items, node)
+				}
+			}
+			result = filtered
+		case "/scheduler/prioritize":
+			priorities := schedulerapiv1.HostPriorityList{}
+			for _, node := range args.Nodes.Items {
+				priorities = append(priorities, schedulerapiv1.HostPriority{Host: node.Name, Score: len(node.Name)})
+			}
+			result = priorities
+		case "/scheduler/broken":
+			result = schedulerapiv1.ExtenderFilterResult{Error: "broken"}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			t.Errorf("unexpected error encoding extender result: %v", err)
+		}
+	}))
+}
+
+func makeMinions(names ...string) api.NodeList {
+	nodes := api.NodeList{}
+	for _, name := range names {
+		nodes.Items = append(nodes.Items, api.Node{ObjectMeta: api.ObjectMeta{Name: name}})
+	}
+	return nodes
+}
+
+func TestHTTPExtenderFilter(t *testing.T) {
+	server := newExtenderServer(t)
+	defer server.Close()
+
+	tests := []struct {
+		verb       string
+		expected   []string
+		expectsErr bool
+	}{
+		{verb: "", expected: []string{"foo", "bar"}},
+		{verb: "filter", expected: []string{"foo"}},
+		{verb: "broken", expectsErr: true},
+		{verb: "missing", expectsErr: true},
+	}
+	for _, test := range tests {
+		extender, err := NewHTTPExtender(&schedulerapi.ExtenderConfig{URLPrefix: server.URL + "/scheduler/", FilterVerb: test.verb})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		pod := api.Pod{ObjectMeta: api.ObjectMeta{Name: "foo"}}
+		nodes, err := extender.Filter(pod, makeMinions("foo", "bar"))
+		if test.expectsErr {
+			if err == nil {
+				t.Errorf("%q: unexpected non-error", test.verb)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.verb, err)
+			continue
+		}
+		names := []string{}
+		for _, node := range nodes.Items {
+			names = append(names, node.Name)
+		}
+		if !reflect.DeepEqual(names, test.expected) {
+			t.Errorf("%q: expected %v, got %v", test.verb, test.expected, names)
+		}
+	}
+}
+
+func TestHTTPExtenderPrioritize(t *testing.T) {
+	server := newExtenderServer(t)
+	defer server.Close()
+
+	extender, err := NewHTTPExtender(&schedulerapi.ExtenderConfig{URLPrefix: server.URL + "/scheduler", PrioritizeVerb: "prioritize", Weight: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	priorities, weight, err := extender.Prioritize(api.Pod{}, makeMinions("a", "bbb"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := algorithm.HostPriorityList{{Host: "a", Score: 1}, {Host: "bbb", Score: 3}}
+	if !reflect.DeepEqual(priorities, expected) {
+		t.Errorf("expected %v, got %v", expected, priorities)
+	}
+	if weight != 3 {
+		t.Errorf("expected weight 3, got %d", weight)
+	}
+
+	// an extender that does not prioritize has no weight
+	extender, err = NewHTTPExtender(&schedulerapi.ExtenderConfig{URLPrefix: server.URL, Weight: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, weight, err := extender.Prioritize(api.Pod{}, makeMinions("a")); err != nil || weight != 0 {
+		t.Errorf("expected no priorities, got weight %d and error %v", weight, err)
+	}
+}
+
+func TestNewHTTPExtenderValidatesConfig(t *testing.T) {
+	if _, err := NewHTTPExtender(&schedulerapi.ExtenderConfig{}); err == nil {
+		t.Errorf("expected an error for a missing urlPrefix")
+	}
+	if _, err := NewHTTPExtender(&schedulerapi.ExtenderConfig{URLPrefix: "http://localhost", Weight: -1}); err == nil {
+		t.Errorf("expected an error for a negative weight")
+	}
+}
@@ -81,7 +81,7 @@ func (f *ConfigFactory) CreateFromProvider(providerName string) (*scheduler.Conf
 		return nil, err
 	}
 
-	return f.CreateFromKeys(provider.FitPredicateKeys, provider.PriorityFunctionKeys)
+	return f.CreateFromKeys(provider.FitPredicateKeys, provider.PriorityFunctionKeys, []algorithm.SchedulerExtender{})
 }
 
 // Creates a scheduler from the configuration file
@@ -100,11 +100,22 @@ func (f *ConfigFactory) CreateFromConfig(policy schedulerapi.Policy) (*scheduler
 		priorityKeys.Insert(RegisterCustomPriorityFunction(priority))
 	}
 
-	return f.CreateFromKeys(predicateKeys, priorityKeys)
+	extenders := []algorithm.SchedulerExtender{}
+	for i := range policy.ExtenderConfigs {
+		glog.V(2).Infof("Creating extender with config %+v", policy.ExtenderConfigs[i])
+		extender, err := scheduler.NewHTTPExtender(&policy.ExtenderConfigs[i])
+		if err != nil {
+			return nil, err
+		}
+		extenders = append(extenders, extender)
+	}
+
+	return f.CreateFromKeys(predicateKeys, priorityKeys, extenders)
 }
 
-// Creates a scheduler from a set of registered fit predicate keys and priority keys.
-func (f *ConfigFactory) CreateFromKeys(predicateKeys, priorityKeys util.StringSet) (*scheduler.Config, error) {
+// Creates a scheduler from a set of registered fit predicate keys and priority keys,
+// consulting the given extenders after the built-in predicates and priorities.
+func (f *ConfigFactory) CreateFromKeys(predicateKeys, priorityKeys util.StringSet, extenders []algorithm.SchedulerExtender) (*scheduler.Config, error) {
 	glog.V(2).Infof("creating scheduler with fit predicates '%v' and priority functions '%v", predicateKeys, priorityKeys)
 	pluginArgs := PluginFactoryArgs{
 		PodLister:     f.PodLister,
@@ -146,7 +157,7 @@ func (f *ConfigFactory) CreateFromKeys(predicateKeys, priorityKeys util.StringSe
 
 	r := rand.New(rand.NewSource(time.Now().UnixNano()))
 
-	algo := algorithm.NewGenericScheduler(predicateFuncs, priorityConfigs, f.PodLister, r)
+	algo := algorithm.NewGenericScheduler(predicateFuncs, priorityConfigs, extenders, f.PodLister, r)
 
 	podBackoff := podBackoff{
 		perPodBackoff: map[string]*backoffEntry{},
@@ -220,6 +231,14 @@ func (factory *ConfigFactory) pollMinions() (cache.Enumerator, error) {
 				continue
 			}
 		}
+		// Kubelet evicts pods from nodes running low on memory or disk, don't
+		// send it new ones.
+		if condition, ok := conditionMap[api.NodeMemoryPressure]; ok && condition.Status == api.ConditionTrue {
+			continue
+		}
+		if condition, ok := conditionMap[api.NodeDiskPressure]; ok && condition.Status == api.ConditionTrue {
+			continue
+		}
 		if condition, ok := conditionMap[api.NodeReady]; ok {
 			if condition.Status == api.ConditionTrue {
 				nodes.Items = append(nodes.Items, node)
@@ -82,13 +82,20 @@ func TestCreateFromConfig(t *testing.T) {
 		"priorities" : [
 			{"name" : "RackSpread", "weight" : 3, "argument" : {"serviceAntiAffinity" : {"label" : "rack"}}},
 			{"name" : "PriorityOne", "weight" : 2},
-			{"name" : "PriorityTwo", "weight" : 1}		]
+			{"name" : "PriorityTwo", "weight" : 1}		],
+		"extenders" : [
+			{"urlPrefix" : "http://127.0.0.1:12346/scheduler", "filterVerb" : "filter", "prioritizeVerb" : "prioritize", "weight" : 5}
+		]
 	}`)
 	err := latestschedulerapi.Codec.DecodeInto(configData, &policy)
 	if err != nil {
 		t.Errorf("Invalid configuration: %v", err)
 	}
 
+	if len(policy.ExtenderConfigs) != 1 || policy.ExtenderConfigs[0].FilterVerb != "filter" || policy.ExtenderConfigs[0].Weight != 5 {
+		t.Errorf("Unexpected extender configuration: %#v", policy.ExtenderConfigs)
+	}
+
 	factory.CreateFromConfig(policy)
 }
 
@@ -301,6 +308,39 @@ func TestPollMinions(t *testing.T) {
 			},
 			expectedCount: 1,
 		},
+		{
+			minions: []api.Node{
+				{
+					ObjectMeta: api.ObjectMeta{Name: "foo"},
+					Status: api.NodeStatus{
+						Conditions: []api.NodeCondition{
+							{Type: api.NodeReady, Status: api.ConditionTrue},
+							{Type: api.NodeMemoryPressure, Status: api.ConditionFalse},
+							{Type: api.NodeDiskPressure, Status: api.ConditionFalse},
+						},
+					},
+				},
+				{
+					ObjectMeta: api.ObjectMeta{Name: "bar"},
+					Status: api.NodeStatus{
+						Conditions: []api.NodeCondition{
+							{Type: api.NodeReady, Status: api.ConditionTrue},
+							{Type: api.NodeMemoryPressure, Status: api.ConditionTrue},
+						},
+					},
+				},
+				{
+					ObjectMeta: api.ObjectMeta{Name: "baz"},
+					Status: api.NodeStatus{
+						Conditions: []api.NodeCondition{
+							{Type: api.NodeReady, Status: api.ConditionTrue},
+							{Type: api.NodeDiskPressure, Status: api.ConditionTrue},
+						},
+					},
+				},
+			},
+			expectedCount: 1,
+		},
 	}
 
 	for _, item := range table {
@@ -77,8 +77,11 @@ var _ = Describe("Networking", func() {
 				},
 			},
 			Spec: api.ServiceSpec{
-				Port:       8080,
-				TargetPort: util.NewIntOrStringFromInt(8080),
+				Ports: []api.ServicePort{{
+					Protocol:   "TCP",
+					Port:       8080,
+					TargetPort: util.NewIntOrStringFromInt(8080),
+				}},
 				Selector: map[string]string{
 					"name": name,
 				},
@@ -132,7 +135,7 @@ var _ = Describe("Networking", func() {
 			defer GinkgoRecover()
 			By("Cleaning up the webserver pods")
 			for _, podName := range podNames {
-				if err = c.Pods(ns).Delete(podName); err != nil {
+				if err = c.Pods(ns).Delete(podName, nil); err != nil {
 					Logf("Failed to delete pod %s: %v", podName, err)
 				}
 			}
@@ -114,7 +114,7 @@ var _ = Describe("Services", func() {
 		defer func() {
 			By("deleting the pod")
 			defer GinkgoRecover()
-			podClient.Delete(pod.Name)
+			podClient.Delete(pod.Name, nil)
 		}()
 		if _, err := podClient.Create(pod); err != nil {
 			Failf("Failed to create %s pod: %v", pod.Name, err)
@@ -200,9 +200,12 @@ var _ = Describe("Services", func() {
 				Name: serviceName,
 			},
 			Spec: api.ServiceSpec{
-				Port:       80,
-				Selector:   labels,
-				TargetPort: util.NewIntOrStringFromInt(80),
+				Selector: labels,
+				Ports: []api.ServicePort{{
+					Protocol:   "TCP",
+					Port:       80,
+					TargetPort: util.NewIntOrStringFromInt(80),
+				}},
 			},
 		}
 		_, err := c.Services(ns).Create(service)
@@ -214,7 +217,7 @@ var _ = Describe("Services", func() {
 		var names []string
 		defer func() {
 			for _, name := range names {
-				err := c.Pods(ns).Delete(name)
+				err := c.Pods(ns).Delete(name, nil)
 				Expect(err).NotTo(HaveOccurred())
 			}
 		}()
@@ -231,13 +234,13 @@ var _ = Describe("Services", func() {
 
 		validateEndpointsOrFail(c, ns, serviceName, expectedPort, names)
 
-		err = c.Pods(ns).Delete(name1)
+		err = c.Pods(ns).Delete(name1, nil)
 		Expect(err).NotTo(HaveOccurred())
 		names = []string{name2}
 
 		validateEndpointsOrFail(c, ns, serviceName, expectedPort, names)
 
-		err = c.Pods(ns).Delete(name2)
+		err = c.Pods(ns).Delete(name2, nil)
 		Expect(err).NotTo(HaveOccurred())
 		names = []string{}
 
@@ -260,9 +263,12 @@ var _ = Describe("Services", func() {
 				Name: serviceName,
 			},
 			Spec: api.ServiceSpec{
-				Port:                       80,
-				Selector:                   labels,
-				TargetPort:                 util.NewIntOrStringFromInt(80),
+				Selector: labels,
+				Ports: []api.ServicePort{{
+					Protocol:   "TCP",
+					Port:       80,
+					TargetPort: util.NewIntOrStringFromInt(80),
+				}},
 				CreateExternalLoadBalancer: true,
 			},
 		}
@@ -283,7 +289,7 @@ var _ = Describe("Services", func() {
 			Failf("got unexpected number (%d) of public IPs for externally load balanced service: %v", result.Spec.PublicIPs, result)
 		}
 		ip := result.Spec.PublicIPs[0]
-		port := result.Spec.Port
+		port := result.Spec.Ports[0].Port
 
 		pod := &api.Pod{
 			TypeMeta: api.TypeMeta{
@@ -309,7 +315,7 @@ var _ = Describe("Services", func() {
 		defer func() {
 			By("deleting pod " + pod.Name)
 			defer GinkgoRecover()
-			podClient.Delete(pod.Name)
+			podClient.Delete(pod.Name, nil)
 		}()
 		if _, err := podClient.Create(pod); err != nil {
 			Failf("Failed to create pod %s: %v", pod.Name, err)
@@ -347,9 +353,12 @@ var _ = Describe("Services", func() {
 		service := &api.Service{
 			ObjectMeta: api.ObjectMeta{},
 			Spec: api.ServiceSpec{
-				Port:                       80,
-				Selector:                   labels,
-				TargetPort:                 util.NewIntOrStringFromInt(80),
+				Selector: labels,
+				Ports: []api.ServicePort{{
+					Protocol:   "TCP",
+					Port:       80,
+					TargetPort: util.NewIntOrStringFromInt(80),
+				}},
 				CreateExternalLoadBalancer: true,
 			},
 		}
@@ -396,11 +405,15 @@ func validateUniqueOrFail(s []string) {
 
 func validateIPsOrFail(c *client.Client, ns string, expectedPort int, expectedEndpoints []string, endpoints *api.Endpoints) {
 	ips := util.StringSet{}
-	for _, ep := range endpoints.Endpoints {
-		if ep.Port != expectedPort {
-			Failf("invalid port, expected %d, got %d", expectedPort, ep.Port)
+	for _, ss := range endpoints.Subsets {
+		for _, port := range ss.Ports {
+			if port.Port != expectedPort {
+				Failf("invalid port, expected %d, got %d", expectedPort, port.Port)
+			}
+		}
+		for _, addr := range ss.Addresses {
+			ips.Insert(addr.IP)
 		}
-		ips.Insert(ep.IP)
 	}
 
 	for _, name := range expectedEndpoints {
@@ -417,15 +430,23 @@ func validateIPsOrFail(c *client.Client, ns string, expectedPort int, expectedEn
 
 }
 
+func countEndpoints(eps *api.Endpoints) int {
+	count := 0
+	for i := range eps.Subsets {
+		count += len(eps.Subsets[i].Addresses) * len(eps.Subsets[i].Ports)
+	}
+	return count
+}
+
 func validateEndpointsOrFail(c *client.Client, ns, serviceName string, expectedPort int, expectedEndpoints []string) {
 	for {
 		endpoints, err := c.Endpoints(ns).Get(serviceName)
 		if err == nil {
-			if len(endpoints.Endpoints) == len(expectedEndpoints) {
+			if countEndpoints(endpoints) == len(expectedEndpoints) {
 				validateIPsOrFail(c, ns, expectedPort, expectedEndpoints, endpoints)
 				return
 			} else {
-				By(fmt.Sprintf("Unexpected number of endpoints: found %v, expected %v (ignoring for 1 second)", endpoints.Endpoints, expectedEndpoints))
+				By(fmt.Sprintf("Unexpected number of endpoints: found %v, expected %v (ignoring for 1 second)", endpoints.Subsets, expectedEndpoints))
 			}
 		} else {
 			By(fmt.Sprintf("Failed to get endpoints: %v (ignoring for 1 second)", err))
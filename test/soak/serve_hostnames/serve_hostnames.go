@@ -106,8 +106,11 @@ func main() {
 				},
 			},
 			Spec: api.ServiceSpec{
-				Port:       9376,
-				TargetPort: util.NewIntOrStringFromInt(9376),
+				Ports: []api.ServicePort{{
+					Protocol:   "TCP",
+					Port:       9376,
+					TargetPort: util.NewIntOrStringFromInt(9376),
+				}},
 				Selector: map[string]string{
 					"name": "serve-hostname",
 				},
@@ -181,7 +184,7 @@ func main() {
 		// Make several attempts to delete the pods.
 		for _, podName := range podNames {
 			for start := time.Now(); time.Since(start) < deleteTimeout; time.Sleep(1 * time.Second) {
-				if err := c.Pods(ns).Delete(podName); err == nil {
+				if err := c.Pods(ns).Delete(podName, nil); err == nil {
 					break
 				}
 				glog.Warningf("After %v failed to delete pod %s: %v", time.Since(start), podName, err)